@@ -0,0 +1,53 @@
+// Package decorators provides reusable handler.Decorator implementations for
+// cross-cutting concerns that would otherwise be duplicated across handlers.
+//
+// Decorators wrap a typed handler.HandlerFunc and are attached with
+// handler.WithDecorators:
+//
+//	http.HandleFunc("/payments", handler.Wrap(createPayment,
+//		handler.WithBinders(binder.JSON()),
+//		handler.WithDecorators(
+//			decorators.Idempotency[handler.Context, CreatePaymentRequest](store),
+//		),
+//	))
+//
+// # Idempotency
+//
+// Idempotency makes unsafe requests (POST, PUT, PATCH, DELETE) safe to retry.
+// When a request carries an Idempotency-Key header, the first response is
+// recorded in an IdempotencyStore and replayed verbatim for later requests
+// with the same key, method and path until the TTL expires. A duplicate that
+// arrives while the first request is still running receives 409 Conflict.
+//
+// Keys are shared by all callers unless WithIdempotencySubject scopes them,
+// e.g. by user or tenant ID; set it whenever responses are caller-specific.
+// A hash of the request body is stored with the response, and reusing a key
+// with a different body receives 422 Unprocessable Entity.
+//
+// Responses with a 5xx status, render errors, and nil responses are not
+// recorded, so clients can retry after transient failures.
+//
+// The IdempotencyStore interface is small enough to back with Redis
+// (SET NX + GET) or a database table; MemoryIdempotencyStore is provided
+// for tests and single-instance deployments.
+//
+// # Metrics
+//
+// Metrics reports method, route, status and duration of every request to a
+// MetricsRecorder, a single-method interface that maps directly onto a
+// Prometheus histogram or a statsd timer. The status is captured from what the
+// response writes, or derived from the error when rendering fails (the code of
+// a handler.HTTPError, otherwise 500). WithServerTiming adds a Server-Timing
+// header so handler latency shows up in browser devtools:
+//
+//	recorder := decorators.MetricsRecorderFunc(func(ctx context.Context, m decorators.RequestMetrics) {
+//		requestDuration.WithLabelValues(m.Method, m.Route, strconv.Itoa(m.Status)).
+//			Observe(m.Duration.Seconds())
+//	})
+//
+//	handler.WithDecorators(
+//		decorators.Metrics[handler.Context, CreatePaymentRequest](recorder,
+//			decorators.WithServerTiming(),
+//		),
+//	)
+package decorators
@@ -0,0 +1,261 @@
+package decorators
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"maps"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/dmitrymomot/saaskit/handler"
+)
+
+const (
+	// IdempotencyKeyHeader is the request header carrying the client-generated key.
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader is set to "true" on responses replayed from the store.
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+)
+
+type idempotencyConfig struct {
+	ttl     time.Duration
+	lockTTL time.Duration
+	header  string
+	subject func(ctx context.Context) string
+}
+
+// IdempotencyOption configures the Idempotency decorator.
+type IdempotencyOption func(*idempotencyConfig)
+
+// WithIdempotencyTTL sets how long recorded responses are replayed (default 24h).
+func WithIdempotencyTTL(ttl time.Duration) IdempotencyOption {
+	return func(c *idempotencyConfig) {
+		if ttl > 0 {
+			c.ttl = ttl
+		}
+	}
+}
+
+// WithIdempotencyLockTTL sets how long an in-progress reservation is held (default 1m).
+// It bounds how long duplicates are rejected if the first request never completes.
+func WithIdempotencyLockTTL(ttl time.Duration) IdempotencyOption {
+	return func(c *idempotencyConfig) {
+		if ttl > 0 {
+			c.lockTTL = ttl
+		}
+	}
+}
+
+// WithIdempotencyHeader overrides the request header name (default "Idempotency-Key").
+func WithIdempotencyHeader(name string) IdempotencyOption {
+	return func(c *idempotencyConfig) {
+		if name != "" {
+			c.header = name
+		}
+	}
+}
+
+// WithIdempotencySubject scopes keys to the caller, e.g. the user or tenant ID
+// from the request context. Without it, clients sending the same key share the
+// recorded response, so set it for any endpoint whose response is caller-specific.
+// Requests with an empty subject share one anonymous scope.
+func WithIdempotencySubject(fn func(ctx context.Context) string) IdempotencyOption {
+	return func(c *idempotencyConfig) {
+		if fn != nil {
+			c.subject = fn
+		}
+	}
+}
+
+// Idempotency replays the first response for requests sharing an idempotency key.
+// Only unsafe methods (POST, PUT, PATCH, DELETE) carrying the key header are affected;
+// everything else passes straight through. Keys are scoped by method, path and the
+// subject set with WithIdempotencySubject. Reusing a key with a different request body
+// fails with handler.ErrUnprocessableEntity. Concurrent duplicates fail with
+// handler.ErrConflict until the first request completes.
+func Idempotency[C handler.Context, R any](store IdempotencyStore, opts ...IdempotencyOption) handler.Decorator[C, R] {
+	if store == nil {
+		panic("decorators: idempotency store is required")
+	}
+
+	cfg := &idempotencyConfig{
+		ttl:     24 * time.Hour,
+		lockTTL: time.Minute,
+		header:  IdempotencyKeyHeader,
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	return func(next handler.HandlerFunc[C, R]) handler.HandlerFunc[C, R] {
+		return func(ctx C, req R) handler.Response {
+			r := ctx.Request()
+			key := r.Header.Get(cfg.header)
+			if key == "" || isSafeMethod(r.Method) {
+				return next(ctx, req)
+			}
+
+			var subject string
+			if cfg.subject != nil {
+				subject = cfg.subject(ctx)
+			}
+			// Quoting keeps subject and key unambiguous when either contains spaces
+			storeKey := r.Method + " " + r.URL.Path + " " + strconv.Quote(subject) + " " + key
+
+			requestHash, err := hashRequestBody(r)
+			if err != nil {
+				return errorResponse{handler.ErrBadRequest}
+			}
+
+			stored, err := store.Begin(ctx, storeKey, cfg.lockTTL)
+			switch {
+			case errors.Is(err, ErrIdempotencyInProgress):
+				return errorResponse{handler.ErrConflict}
+			case err != nil:
+				return errorResponse{err}
+			case stored != nil && stored.RequestHash != "" && stored.RequestHash != requestHash:
+				return errorResponse{handler.ErrUnprocessableEntity}
+			case stored != nil:
+				return replayResponse{stored}
+			}
+
+			resp := next(ctx, req)
+			if resp == nil {
+				_ = store.Release(ctx, storeKey)
+				return nil
+			}
+
+			return &recordingResponse{
+				inner:       resp,
+				store:       store,
+				key:         storeKey,
+				requestHash: requestHash,
+				ttl:         cfg.ttl,
+			}
+		}
+	}
+}
+
+// hashRequestBody returns the hex SHA-256 of the body and restores it for the handler.
+func hashRequestBody(r *http.Request) (string, error) {
+	var body []byte
+	if r.Body != nil && r.Body != http.NoBody {
+		var err error
+		body, err = io.ReadAll(r.Body)
+		if err != nil {
+			return "", err
+		}
+		_ = r.Body.Close()
+		r.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func isSafeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return true
+	}
+	return false
+}
+
+// errorResponse defers the error to the handler's ErrorHandler.
+type errorResponse struct {
+	err error
+}
+
+func (e errorResponse) Render(http.ResponseWriter, *http.Request) error {
+	return e.err
+}
+
+// replayResponse writes a previously recorded response.
+type replayResponse struct {
+	stored *StoredResponse
+}
+
+func (rr replayResponse) Render(w http.ResponseWriter, r *http.Request) error {
+	maps.Copy(w.Header(), rr.stored.Header)
+	w.Header().Set(IdempotentReplayedHeader, "true")
+	w.WriteHeader(rr.stored.StatusCode)
+	_, err := w.Write(rr.stored.Body)
+	return err
+}
+
+// recordingResponse renders the inner response into a buffer, stores it and
+// then copies it to the client.
+type recordingResponse struct {
+	inner       handler.Response
+	store       IdempotencyStore
+	key         string
+	requestHash string
+	ttl         time.Duration
+}
+
+func (rr *recordingResponse) Render(w http.ResponseWriter, r *http.Request) error {
+	rec := &responseRecorder{header: make(http.Header)}
+	ctx := r.Context()
+
+	if err := rr.inner.Render(rec, r); err != nil {
+		_ = rr.store.Release(ctx, rr.key)
+		return err
+	}
+
+	status := rec.statusCode()
+	if status >= http.StatusInternalServerError {
+		// Transient failures must stay retryable
+		_ = rr.store.Release(ctx, rr.key)
+	} else {
+		stored := StoredResponse{
+			StatusCode:  status,
+			Header:      rec.header.Clone(),
+			Body:        bytes.Clone(rec.body.Bytes()),
+			RequestHash: rr.requestHash,
+		}
+		if err := rr.store.Complete(ctx, rr.key, stored, rr.ttl); err != nil {
+			_ = rr.store.Release(ctx, rr.key)
+		}
+	}
+
+	maps.Copy(w.Header(), rec.header)
+	w.WriteHeader(status)
+	_, err := w.Write(rec.body.Bytes())
+	return err
+}
+
+// responseRecorder captures status, headers and body written by a Response.
+type responseRecorder struct {
+	header http.Header
+	body   bytes.Buffer
+	status int
+}
+
+func (r *responseRecorder) Header() http.Header {
+	return r.header
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	return r.body.Write(b)
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+}
+
+func (r *responseRecorder) statusCode() int {
+	if r.status == 0 {
+		return http.StatusOK
+	}
+	return r.status
+}
@@ -0,0 +1,92 @@
+package decorators
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrIdempotencyInProgress indicates a request with the same key is still being processed.
+	ErrIdempotencyInProgress = errors.New("request with this idempotency key is in progress")
+)
+
+// StoredResponse is a recorded response replayed for duplicate requests.
+type StoredResponse struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       []byte      `json:"body,omitempty"`
+	// RequestHash is the hex SHA-256 of the original request body. A duplicate
+	// with a different body is rejected instead of replayed.
+	RequestHash string `json:"request_hash,omitempty"`
+}
+
+// IdempotencyStore persists idempotency key reservations and recorded responses.
+// Implementations must make Begin atomic across instances.
+type IdempotencyStore interface {
+	// Begin reserves the key for ttl.
+	// It returns (nil, nil) when the caller acquired the key and should process the request,
+	// the stored response when the key has completed, or ErrIdempotencyInProgress
+	// when another request holds the reservation.
+	Begin(ctx context.Context, key string, ttl time.Duration) (*StoredResponse, error)
+
+	// Complete stores the response for the reserved key, keeping it for ttl.
+	Complete(ctx context.Context, key string, resp StoredResponse, ttl time.Duration) error
+
+	// Release drops the reservation so the request can be retried.
+	Release(ctx context.Context, key string) error
+}
+
+type idempotencyRecord struct {
+	response  *StoredResponse
+	expiresAt time.Time
+}
+
+// MemoryIdempotencyStore is an in-memory IdempotencyStore.
+// Expired records are removed lazily on access.
+type MemoryIdempotencyStore struct {
+	mu      sync.Mutex
+	records map[string]idempotencyRecord
+}
+
+// NewMemoryIdempotencyStore creates an empty in-memory idempotency store.
+func NewMemoryIdempotencyStore() *MemoryIdempotencyStore {
+	return &MemoryIdempotencyStore{records: make(map[string]idempotencyRecord)}
+}
+
+// Begin reserves the key or returns the stored response.
+func (s *MemoryIdempotencyStore) Begin(_ context.Context, key string, ttl time.Duration) (*StoredResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if rec, ok := s.records[key]; ok && now.Before(rec.expiresAt) {
+		if rec.response == nil {
+			return nil, ErrIdempotencyInProgress
+		}
+		return rec.response, nil
+	}
+
+	s.records[key] = idempotencyRecord{expiresAt: now.Add(ttl)}
+	return nil, nil
+}
+
+// Complete stores the response for the key.
+func (s *MemoryIdempotencyStore) Complete(_ context.Context, key string, resp StoredResponse, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.records[key] = idempotencyRecord{response: &resp, expiresAt: time.Now().Add(ttl)}
+	return nil
+}
+
+// Release removes the reservation for the key.
+func (s *MemoryIdempotencyStore) Release(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.records, key)
+	return nil
+}
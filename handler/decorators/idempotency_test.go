@@ -0,0 +1,271 @@
+package decorators_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dmitrymomot/saaskit/handler"
+	"github.com/dmitrymomot/saaskit/handler/decorators"
+)
+
+type stubResponse struct {
+	status int
+	body   string
+	err    error
+}
+
+func (s stubResponse) Render(w http.ResponseWriter, r *http.Request) error {
+	if s.err != nil {
+		return s.err
+	}
+	w.Header().Set("X-Test", "yes")
+	w.WriteHeader(s.status)
+	_, err := w.Write([]byte(s.body))
+	return err
+}
+
+func newIdempotentHandler(store decorators.IdempotencyStore, calls *atomic.Int32, resp func() handler.Response) http.HandlerFunc {
+	h := func(ctx handler.Context, req struct{}) handler.Response {
+		calls.Add(1)
+		return resp()
+	}
+	return handler.Wrap(h, handler.WithDecorators(
+		decorators.Idempotency[handler.Context, struct{}](store),
+	))
+}
+
+func doRequest(h http.HandlerFunc, method, key string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, "/payments", nil)
+	if key != "" {
+		req.Header.Set(decorators.IdempotencyKeyHeader, key)
+	}
+	rec := httptest.NewRecorder()
+	h(rec, req)
+	return rec
+}
+
+func TestIdempotency(t *testing.T) {
+	t.Parallel()
+
+	created := func() handler.Response { return stubResponse{status: http.StatusCreated, body: "created"} }
+
+	t.Run("replays first response for same key", func(t *testing.T) {
+		t.Parallel()
+		var calls atomic.Int32
+		h := newIdempotentHandler(decorators.NewMemoryIdempotencyStore(), &calls, created)
+
+		first := doRequest(h, http.MethodPost, "k1")
+		second := doRequest(h, http.MethodPost, "k1")
+
+		assert.Equal(t, int32(1), calls.Load())
+		assert.Equal(t, http.StatusCreated, first.Code)
+		assert.Equal(t, http.StatusCreated, second.Code)
+		assert.Equal(t, "created", second.Body.String())
+		assert.Equal(t, "yes", second.Header().Get("X-Test"))
+		assert.Empty(t, first.Header().Get(decorators.IdempotentReplayedHeader))
+		assert.Equal(t, "true", second.Header().Get(decorators.IdempotentReplayedHeader))
+	})
+
+	t.Run("different keys are processed independently", func(t *testing.T) {
+		t.Parallel()
+		var calls atomic.Int32
+		h := newIdempotentHandler(decorators.NewMemoryIdempotencyStore(), &calls, created)
+
+		doRequest(h, http.MethodPost, "k1")
+		doRequest(h, http.MethodPost, "k2")
+
+		assert.Equal(t, int32(2), calls.Load())
+	})
+
+	t.Run("requests without key are not deduplicated", func(t *testing.T) {
+		t.Parallel()
+		var calls atomic.Int32
+		h := newIdempotentHandler(decorators.NewMemoryIdempotencyStore(), &calls, created)
+
+		doRequest(h, http.MethodPost, "")
+		doRequest(h, http.MethodPost, "")
+
+		assert.Equal(t, int32(2), calls.Load())
+	})
+
+	t.Run("safe methods are ignored", func(t *testing.T) {
+		t.Parallel()
+		var calls atomic.Int32
+		h := newIdempotentHandler(decorators.NewMemoryIdempotencyStore(), &calls, created)
+
+		doRequest(h, http.MethodGet, "k1")
+		doRequest(h, http.MethodGet, "k1")
+
+		assert.Equal(t, int32(2), calls.Load())
+	})
+
+	t.Run("server errors are not recorded", func(t *testing.T) {
+		t.Parallel()
+		var calls atomic.Int32
+		h := newIdempotentHandler(decorators.NewMemoryIdempotencyStore(), &calls, func() handler.Response {
+			return stubResponse{status: http.StatusServiceUnavailable, body: "try later"}
+		})
+
+		doRequest(h, http.MethodPost, "k1")
+		rec := doRequest(h, http.MethodPost, "k1")
+
+		assert.Equal(t, int32(2), calls.Load())
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	})
+
+	t.Run("render errors release the key", func(t *testing.T) {
+		t.Parallel()
+		var calls atomic.Int32
+		h := newIdempotentHandler(decorators.NewMemoryIdempotencyStore(), &calls, func() handler.Response {
+			return stubResponse{err: errors.New("render failed")}
+		})
+
+		first := doRequest(h, http.MethodPost, "k1")
+		doRequest(h, http.MethodPost, "k1")
+
+		assert.Equal(t, int32(2), calls.Load())
+		assert.Equal(t, http.StatusInternalServerError, first.Code)
+	})
+
+	t.Run("concurrent duplicate gets conflict", func(t *testing.T) {
+		t.Parallel()
+		store := decorators.NewMemoryIdempotencyStore()
+		started := make(chan struct{})
+		release := make(chan struct{})
+		var calls atomic.Int32
+		h := newIdempotentHandler(store, &calls, func() handler.Response {
+			close(started)
+			<-release
+			return stubResponse{status: http.StatusCreated, body: "created"}
+		})
+
+		done := make(chan *httptest.ResponseRecorder)
+		go func() { done <- doRequest(h, http.MethodPost, "k1") }()
+
+		<-started
+		dup := doRequest(h, http.MethodPost, "k1")
+		assert.Equal(t, http.StatusConflict, dup.Code)
+
+		close(release)
+		first := <-done
+		assert.Equal(t, http.StatusCreated, first.Code)
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("keys are scoped by subject", func(t *testing.T) {
+		t.Parallel()
+		var calls atomic.Int32
+		h := handler.Wrap(func(ctx handler.Context, req struct{}) handler.Response {
+			calls.Add(1)
+			return stubResponse{status: http.StatusCreated, body: "for " + testSubject(ctx)}
+		}, handler.WithDecorators(
+			decorators.Idempotency[handler.Context, struct{}](decorators.NewMemoryIdempotencyStore(),
+				decorators.WithIdempotencySubject(testSubject),
+			),
+		))
+
+		alice := doSubjectRequest(h, "alice", "k1", "")
+		bob := doSubjectRequest(h, "bob", "k1", "")
+		aliceAgain := doSubjectRequest(h, "alice", "k1", "")
+
+		assert.Equal(t, int32(2), calls.Load())
+		assert.Equal(t, "for alice", alice.Body.String())
+		assert.Equal(t, "for bob", bob.Body.String())
+		assert.Empty(t, bob.Header().Get(decorators.IdempotentReplayedHeader))
+		assert.Equal(t, "for alice", aliceAgain.Body.String())
+		assert.Equal(t, "true", aliceAgain.Header().Get(decorators.IdempotentReplayedHeader))
+	})
+
+	t.Run("reused key with different body is rejected", func(t *testing.T) {
+		t.Parallel()
+		var calls atomic.Int32
+		h := newIdempotentHandler(decorators.NewMemoryIdempotencyStore(), &calls, created)
+
+		first := doSubjectRequest(h, "", "k1", `{"amount":100}`)
+		same := doSubjectRequest(h, "", "k1", `{"amount":100}`)
+		changed := doSubjectRequest(h, "", "k1", `{"amount":999}`)
+
+		assert.Equal(t, int32(1), calls.Load())
+		assert.Equal(t, http.StatusCreated, first.Code)
+		assert.Equal(t, "true", same.Header().Get(decorators.IdempotentReplayedHeader))
+		assert.Equal(t, http.StatusUnprocessableEntity, changed.Code)
+	})
+
+	t.Run("handler still reads the body", func(t *testing.T) {
+		t.Parallel()
+		var body string
+		h := handler.Wrap(func(ctx handler.Context, req struct{}) handler.Response {
+			b, _ := io.ReadAll(ctx.Request().Body)
+			body = string(b)
+			return stubResponse{status: http.StatusCreated}
+		}, handler.WithDecorators(
+			decorators.Idempotency[handler.Context, struct{}](decorators.NewMemoryIdempotencyStore()),
+		))
+
+		doSubjectRequest(h, "", "k1", `{"amount":100}`)
+		assert.Equal(t, `{"amount":100}`, body)
+	})
+}
+
+type testSubjectKey struct{}
+
+func testSubject(ctx context.Context) string {
+	subject, _ := ctx.Value(testSubjectKey{}).(string)
+	return subject
+}
+
+func doSubjectRequest(h http.HandlerFunc, subject, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/payments", strings.NewReader(body))
+	req = req.WithContext(context.WithValue(req.Context(), testSubjectKey{}, subject))
+	req.Header.Set(decorators.IdempotencyKeyHeader, key)
+	rec := httptest.NewRecorder()
+	h(rec, req)
+	return rec
+}
+
+func TestMemoryIdempotencyStore(t *testing.T) {
+	t.Parallel()
+
+	t.Run("expired reservation can be reacquired", func(t *testing.T) {
+		t.Parallel()
+		store := decorators.NewMemoryIdempotencyStore()
+		ctx := context.Background()
+
+		resp, err := store.Begin(ctx, "k", 10*time.Millisecond)
+		require.NoError(t, err)
+		assert.Nil(t, resp)
+
+		_, err = store.Begin(ctx, "k", 10*time.Millisecond)
+		require.ErrorIs(t, err, decorators.ErrIdempotencyInProgress)
+
+		time.Sleep(20 * time.Millisecond)
+		resp, err = store.Begin(ctx, "k", time.Minute)
+		require.NoError(t, err)
+		assert.Nil(t, resp)
+	})
+
+	t.Run("completed key returns stored response", func(t *testing.T) {
+		t.Parallel()
+		store := decorators.NewMemoryIdempotencyStore()
+		ctx := context.Background()
+
+		_, err := store.Begin(ctx, "k", time.Minute)
+		require.NoError(t, err)
+		require.NoError(t, store.Complete(ctx, "k", decorators.StoredResponse{StatusCode: http.StatusOK, Body: []byte("ok")}, time.Minute))
+
+		resp, err := store.Begin(ctx, "k", time.Minute)
+		require.NoError(t, err)
+		require.NotNil(t, resp)
+		assert.Equal(t, []byte("ok"), resp.Body)
+	})
+}
@@ -0,0 +1,208 @@
+package decorators
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/dmitrymomot/saaskit/handler"
+)
+
+// ServerTimingHeader is the response header carrying handler timing for browser devtools.
+const ServerTimingHeader = "Server-Timing"
+
+// UnmatchedRoute is the default route label of requests without a ServeMux pattern.
+const UnmatchedRoute = "unmatched"
+
+// RequestMetrics describes a single handled request.
+type RequestMetrics struct {
+	Method   string
+	Route    string
+	Status   int
+	Duration time.Duration // Handler execution plus response rendering
+}
+
+// MetricsRecorder receives per-request metrics. Implementations typically
+// observe a histogram labeled by method, route and status (Prometheus) or
+// emit a timer (statsd). RecordRequest is called synchronously after the
+// response is rendered, so it should not block.
+type MetricsRecorder interface {
+	RecordRequest(ctx context.Context, m RequestMetrics)
+}
+
+// MetricsRecorderFunc adapts a function to the MetricsRecorder interface.
+type MetricsRecorderFunc func(ctx context.Context, m RequestMetrics)
+
+// RecordRequest calls f(ctx, m).
+func (f MetricsRecorderFunc) RecordRequest(ctx context.Context, m RequestMetrics) {
+	f(ctx, m)
+}
+
+type metricsConfig struct {
+	route        func(r *http.Request) string
+	serverTiming bool
+}
+
+// MetricsOption configures the Metrics decorator.
+type MetricsOption func(*metricsConfig)
+
+// WithRouteName labels all requests through this handler with a fixed route name.
+func WithRouteName(name string) MetricsOption {
+	return func(c *metricsConfig) {
+		if name != "" {
+			c.route = func(*http.Request) string { return name }
+		}
+	}
+}
+
+// WithRouteFunc sets how the route label is derived from the request.
+func WithRouteFunc(fn func(r *http.Request) string) MetricsOption {
+	return func(c *metricsConfig) {
+		if fn != nil {
+			c.route = fn
+		}
+	}
+}
+
+// WithServerTiming adds a "Server-Timing: handler;dur=<ms>" header to responses.
+func WithServerTiming() MetricsOption {
+	return func(c *metricsConfig) {
+		c.serverTiming = true
+	}
+}
+
+// Metrics records duration, status code and route of every request.
+//
+// By default the route is the http.ServeMux pattern that matched the request
+// (e.g. "GET /users/{id}"), or UnmatchedRoute when there is none. Raw URL
+// paths are never used, to keep label cardinality bounded; use WithRouteName
+// or WithRouteFunc with routers that don't set Request.Pattern.
+//
+// The status is taken from what the response writes, including the implicit
+// 200 of a body written without WriteHeader. When rendering fails, or the
+// handler returns nil, the status the error handler will produce is recorded:
+// the code of a handler.HTTPError, otherwise 500.
+func Metrics[C handler.Context, R any](recorder MetricsRecorder, opts ...MetricsOption) handler.Decorator[C, R] {
+	if recorder == nil {
+		panic("decorators: metrics recorder is required")
+	}
+
+	cfg := &metricsConfig{route: defaultRoute}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	return func(next handler.HandlerFunc[C, R]) handler.HandlerFunc[C, R] {
+		return func(ctx C, req R) handler.Response {
+			start := time.Now()
+			resp := next(ctx, req)
+
+			if cfg.serverTiming {
+				ctx.ResponseWriter().Header().Set(ServerTimingHeader, serverTiming(time.Since(start)))
+			}
+
+			r := ctx.Request()
+			m := RequestMetrics{
+				Method: r.Method,
+				Route:  cfg.route(r),
+			}
+
+			if resp == nil {
+				m.Status = statusFromError(handler.ErrNilResponse)
+				m.Duration = time.Since(start)
+				recorder.RecordRequest(ctx, m)
+				return nil
+			}
+
+			return &metricsResponse{
+				inner:    resp,
+				recorder: recorder,
+				metrics:  m,
+				start:    start,
+			}
+		}
+	}
+}
+
+// metricsResponse renders the inner response through a status-capturing writer.
+type metricsResponse struct {
+	inner    handler.Response
+	recorder MetricsRecorder
+	metrics  RequestMetrics
+	start    time.Time
+}
+
+func (mr *metricsResponse) Render(w http.ResponseWriter, r *http.Request) error {
+	sw := &statusWriter{ResponseWriter: w}
+	err := mr.inner.Render(sw, r)
+
+	switch {
+	case sw.status != 0:
+		// Headers are already sent, so the error handler can't change the status
+		mr.metrics.Status = sw.status
+	case err != nil:
+		mr.metrics.Status = statusFromError(err)
+	default:
+		mr.metrics.Status = http.StatusOK
+	}
+	mr.metrics.Duration = time.Since(mr.start)
+	mr.recorder.RecordRequest(r.Context(), mr.metrics)
+
+	return err
+}
+
+// statusWriter records the first status code written to the response.
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *statusWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush keeps streaming responses (SSE) working through the wrapper.
+func (w *statusWriter) Flush() {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController.
+func (w *statusWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func defaultRoute(r *http.Request) string {
+	if r.Pattern != "" {
+		return r.Pattern
+	}
+	return UnmatchedRoute
+}
+
+func statusFromError(err error) int {
+	var httpErr handler.HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.Code
+	}
+	return http.StatusInternalServerError
+}
+
+func serverTiming(d time.Duration) string {
+	return fmt.Sprintf("handler;dur=%.2f", float64(d)/float64(time.Millisecond))
+}
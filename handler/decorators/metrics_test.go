@@ -0,0 +1,155 @@
+package decorators_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dmitrymomot/saaskit/handler"
+	"github.com/dmitrymomot/saaskit/handler/decorators"
+)
+
+type memoryRecorder struct {
+	mu      sync.Mutex
+	metrics []decorators.RequestMetrics
+}
+
+func (m *memoryRecorder) RecordRequest(_ context.Context, rm decorators.RequestMetrics) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.metrics = append(m.metrics, rm)
+}
+
+func (m *memoryRecorder) last(t *testing.T) decorators.RequestMetrics {
+	t.Helper()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	require.Len(t, m.metrics, 1)
+	return m.metrics[0]
+}
+
+type bodyOnlyResponse struct{}
+
+func (bodyOnlyResponse) Render(w http.ResponseWriter, r *http.Request) error {
+	_, err := w.Write([]byte("ok"))
+	return err
+}
+
+func newMetricsHandler(rec decorators.MetricsRecorder, resp handler.Response, opts ...decorators.MetricsOption) http.HandlerFunc {
+	h := func(ctx handler.Context, req struct{}) handler.Response {
+		return resp
+	}
+	return handler.Wrap(h, handler.WithDecorators(
+		decorators.Metrics[handler.Context, struct{}](rec, opts...),
+	))
+}
+
+func TestMetrics(t *testing.T) {
+	t.Parallel()
+
+	t.Run("records status and route", func(t *testing.T) {
+		t.Parallel()
+		rec := &memoryRecorder{}
+		h := newMetricsHandler(rec, stubResponse{status: http.StatusCreated, body: "created"})
+
+		mux := http.NewServeMux()
+		mux.HandleFunc("POST /users/{id}", h)
+
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/users/42", nil))
+
+		m := rec.last(t)
+		assert.Equal(t, http.MethodPost, m.Method)
+		assert.Equal(t, "POST /users/{id}", m.Route)
+		assert.Equal(t, http.StatusCreated, m.Status)
+		assert.Positive(t, m.Duration)
+		assert.Empty(t, w.Header().Get(decorators.ServerTimingHeader))
+	})
+
+	t.Run("implicit 200 when body written directly", func(t *testing.T) {
+		t.Parallel()
+		rec := &memoryRecorder{}
+		h := newMetricsHandler(rec, bodyOnlyResponse{})
+
+		h(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/health", nil))
+
+		m := rec.last(t)
+		assert.Equal(t, http.StatusOK, m.Status)
+		assert.Equal(t, decorators.UnmatchedRoute, m.Route)
+	})
+
+	t.Run("status from render error", func(t *testing.T) {
+		t.Parallel()
+		rec := &memoryRecorder{}
+		h := newMetricsHandler(rec, stubResponse{err: handler.ErrNotFound})
+
+		w := httptest.NewRecorder()
+		h(w, httptest.NewRequest(http.MethodGet, "/missing", nil))
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Equal(t, http.StatusNotFound, rec.last(t).Status)
+	})
+
+	t.Run("nil response recorded as 500", func(t *testing.T) {
+		t.Parallel()
+		rec := &memoryRecorder{}
+		h := newMetricsHandler(rec, nil)
+
+		w := httptest.NewRecorder()
+		h(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Equal(t, http.StatusInternalServerError, rec.last(t).Status)
+	})
+
+	t.Run("server timing header and static route name", func(t *testing.T) {
+		t.Parallel()
+		var got decorators.RequestMetrics
+		rec := decorators.MetricsRecorderFunc(func(_ context.Context, m decorators.RequestMetrics) {
+			got = m
+		})
+		h := newMetricsHandler(rec, stubResponse{status: http.StatusOK},
+			decorators.WithServerTiming(),
+			decorators.WithRouteName("list_users"),
+		)
+
+		w := httptest.NewRecorder()
+		h(w, httptest.NewRequest(http.MethodGet, "/users?page=2", nil))
+
+		assert.Regexp(t, `^handler;dur=\d+\.\d{2}$`, w.Header().Get(decorators.ServerTimingHeader))
+		assert.Equal(t, "list_users", got.Route)
+	})
+
+	t.Run("preserves flusher for streaming", func(t *testing.T) {
+		t.Parallel()
+		rec := &memoryRecorder{}
+		var flushed bool
+		stream := renderFunc(func(w http.ResponseWriter, r *http.Request) error {
+			require.NoError(t, http.NewResponseController(w).Flush())
+			flushed = true
+			return nil
+		})
+		h := newMetricsHandler(rec, stream)
+
+		h(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/events", nil))
+
+		assert.True(t, flushed)
+		assert.Equal(t, http.StatusOK, rec.last(t).Status)
+	})
+
+	t.Run("panics without recorder", func(t *testing.T) {
+		t.Parallel()
+		assert.Panics(t, func() { decorators.Metrics[handler.Context, struct{}](nil) })
+	})
+}
+
+type renderFunc func(w http.ResponseWriter, r *http.Request) error
+
+func (f renderFunc) Render(w http.ResponseWriter, r *http.Request) error {
+	return f(w, r)
+}
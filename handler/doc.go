@@ -64,6 +64,27 @@
 //		return stream.SendComponent(component, opts...)
 //	})
 //
+// WebSocket connections for bidirectional real-time features:
+//
+//	handler.WebSocket(func(conn WSConn) error {
+//		var msg Message
+//		if err := conn.ReadJSON(&msg); err != nil {
+//			return err
+//		}
+//		return conn.WriteJSON(reply)
+//	})
+//
+// Decorators run before the upgrade, so authentication rejects the request with a
+// regular error response. Handshake failures also go through the error handler;
+// errors returned after the upgrade close the connection with status 1011. The server
+// pings idle connections and closes those that stop responding; see
+// WithWebSocketPingInterval and WithWebSocketReadTimeout.
+//
+// File downloads (seekable readers and FileBytes support Range requests):
+//
+//	handler.File(f, handler.WithAttachment("users.csv"))
+//	handler.FileBytes(pdf, handler.WithInline("invoice.pdf"))
+//
 // # DataStar Integration
 //
 // DataStar requests (identified by Accept: text/event-stream) automatically receive
@@ -90,6 +111,11 @@
 //	err.Add("email", "Email format is invalid")
 //	return handler.JSONError(err)  // 422 with field errors
 //
+// Field details are keyed by RFC 6901 JSON Pointer: "address.zip" becomes
+// "/address/zip" and "items[2].name" becomes "/items/2/name". WithGroupedValidationErrors
+// adds a per-field "fields" list for form libraries, and WithFlatValidationErrors keeps
+// the original field names as keys.
+//
 // # Context Enhancement
 //
 // The Context interface extends standard context.Context with HTTP-specific methods:
@@ -8,4 +8,14 @@ var (
 	ErrNilResponse = errors.New("handler returned nil response")
 	// ErrSSENotInitialized indicates SSE was accessed before being set up for the request
 	ErrSSENotInitialized = errors.New("SSE not initialized for this request")
+	// ErrNilFileReader indicates File was called with a nil reader
+	ErrNilFileReader = errors.New("file response has nil reader")
+	// ErrHijackNotSupported indicates the response writer can't be taken over for a WebSocket upgrade
+	ErrHijackNotSupported = errors.New("response writer does not support hijacking")
+	// ErrWebSocketClosed indicates the WebSocket connection was closed by either side
+	ErrWebSocketClosed = errors.New("websocket connection closed")
+	// ErrWebSocketProtocol indicates the client sent a malformed WebSocket frame
+	ErrWebSocketProtocol = errors.New("websocket protocol error")
+	// ErrWebSocketMessageTooBig indicates an incoming message exceeded the read limit
+	ErrWebSocketMessageTooBig = errors.New("websocket message too big")
 )
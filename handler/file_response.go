@@ -0,0 +1,156 @@
+package handler
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// fileResponse streams a file body with download headers
+type fileResponse struct {
+	reader        io.Reader
+	contentType   string
+	filename      string
+	disposition   string
+	contentLength int64
+	modTime       time.Time
+}
+
+// FileOption configures a file response
+type FileOption func(*fileResponse)
+
+// WithFileContentType sets the Content-Type header.
+// Defaults to the type guessed from the filename extension, or application/octet-stream.
+func WithFileContentType(contentType string) FileOption {
+	return func(f *fileResponse) {
+		f.contentType = contentType
+	}
+}
+
+// WithAttachment makes browsers download the file under the given name
+func WithAttachment(filename string) FileOption {
+	return func(f *fileResponse) {
+		f.filename = filename
+		f.disposition = "attachment"
+	}
+}
+
+// WithInline makes browsers display the file (e.g. a PDF preview) when they can
+func WithInline(filename string) FileOption {
+	return func(f *fileResponse) {
+		f.filename = filename
+		f.disposition = "inline"
+	}
+}
+
+// WithFileContentLength sets Content-Length for readers that can't seek.
+// Seekable readers get the length computed automatically.
+func WithFileContentLength(n int64) FileOption {
+	return func(f *fileResponse) {
+		f.contentLength = n
+	}
+}
+
+// WithFileModTime sets Last-Modified, enabling If-Modified-Since and If-Range
+// handling for seekable readers
+func WithFileModTime(t time.Time) FileOption {
+	return func(f *fileResponse) {
+		f.modTime = t
+	}
+}
+
+// Render writes the file. Seekable readers are served with http.ServeContent,
+// which handles Range and conditional requests; other readers are streamed as is.
+// Readers implementing io.Closer are closed after rendering.
+func (f fileResponse) Render(w http.ResponseWriter, r *http.Request) error {
+	if f.reader == nil {
+		return ErrNilFileReader
+	}
+	if c, ok := f.reader.(io.Closer); ok {
+		defer c.Close()
+	}
+
+	header := w.Header()
+	header.Set("Content-Type", f.resolveContentType())
+	if f.disposition != "" {
+		header.Set("Content-Disposition", contentDisposition(f.disposition, f.filename))
+	}
+
+	if rs, ok := f.reader.(io.ReadSeeker); ok {
+		http.ServeContent(w, r, f.filename, f.modTime, rs)
+		return nil
+	}
+
+	header.Set("Accept-Ranges", "none")
+	if f.contentLength > 0 {
+		header.Set("Content-Length", strconv.FormatInt(f.contentLength, 10))
+	}
+	if !f.modTime.IsZero() {
+		header.Set("Last-Modified", f.modTime.UTC().Format(http.TimeFormat))
+	}
+	w.WriteHeader(http.StatusOK)
+
+	if r.Method == http.MethodHead {
+		return nil
+	}
+
+	_, err := io.Copy(w, f.reader)
+	return err
+}
+
+func (f fileResponse) resolveContentType() string {
+	if f.contentType != "" {
+		return f.contentType
+	}
+	if ct := mime.TypeByExtension(filepath.Ext(f.filename)); ct != "" {
+		return ct
+	}
+	return "application/octet-stream"
+}
+
+// contentDisposition formats the header value, encoding non-ASCII filenames per RFC 2231
+func contentDisposition(disposition, filename string) string {
+	if filename == "" {
+		return disposition
+	}
+	if v := mime.FormatMediaType(disposition, map[string]string{"filename": filename}); v != "" {
+		return v
+	}
+	return disposition
+}
+
+// File creates a response that streams reader to the client.
+// Pass an io.ReadSeeker (e.g. *os.File) to support Range requests for large files.
+//
+// Example:
+//
+//	handler := saaskit.HandlerFunc[saaskit.Context, ExportRequest](
+//		func(ctx saaskit.Context, req ExportRequest) saaskit.Response {
+//			f, err := os.Open(exportPath)
+//			if err != nil {
+//				return saaskit.JSONError(err)
+//			}
+//			return saaskit.File(f, saaskit.WithAttachment("users.csv"))
+//		},
+//	)
+func File(reader io.Reader, opts ...FileOption) Response {
+	f := &fileResponse{reader: reader}
+	for _, opt := range opts {
+		opt(f)
+	}
+	return *f
+}
+
+// FileBytes creates a file response from in-memory content, such as a generated PDF.
+// Range requests are supported.
+//
+// Example:
+//
+//	return saaskit.FileBytes(pdf, saaskit.WithInline("invoice.pdf"))
+func FileBytes(data []byte, opts ...FileOption) Response {
+	return File(bytes.NewReader(data), opts...)
+}
@@ -0,0 +1,145 @@
+package handler_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	saaskit "github.com/dmitrymomot/saaskit/handler"
+)
+
+// streamOnly hides Seek so the response can't serve ranges
+type streamOnly struct {
+	io.Reader
+	closed bool
+}
+
+func (s *streamOnly) Close() error {
+	s.closed = true
+	return nil
+}
+
+func TestFileBytes(t *testing.T) {
+	t.Parallel()
+
+	data := []byte("id,name\n1,alice\n2,bob\n")
+
+	t.Run("attachment with content type from extension", func(t *testing.T) {
+		t.Parallel()
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/export", nil)
+
+		err := saaskit.FileBytes(data, saaskit.WithAttachment("users.csv")).Render(w, r)
+
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+		assert.Equal(t, `attachment; filename=users.csv`, w.Header().Get("Content-Disposition"))
+		assert.Equal(t, "22", w.Header().Get("Content-Length"))
+		assert.Equal(t, string(data), w.Body.String())
+	})
+
+	t.Run("inline with explicit content type", func(t *testing.T) {
+		t.Parallel()
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/invoice", nil)
+
+		err := saaskit.FileBytes([]byte("%PDF-1.7"),
+			saaskit.WithInline("invoice.pdf"),
+			saaskit.WithFileContentType("application/pdf"),
+		).Render(w, r)
+
+		require.NoError(t, err)
+		assert.Equal(t, "application/pdf", w.Header().Get("Content-Type"))
+		assert.Equal(t, `inline; filename=invoice.pdf`, w.Header().Get("Content-Disposition"))
+	})
+
+	t.Run("encodes non-ascii filenames", func(t *testing.T) {
+		t.Parallel()
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/export", nil)
+
+		err := saaskit.FileBytes(data, saaskit.WithAttachment("отчёт.csv")).Render(w, r)
+
+		require.NoError(t, err)
+		assert.Equal(t, `attachment; filename*=utf-8''%D0%BE%D1%82%D1%87%D1%91%D1%82.csv`, w.Header().Get("Content-Disposition"))
+	})
+
+	t.Run("serves range requests", func(t *testing.T) {
+		t.Parallel()
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/export", nil)
+		r.Header.Set("Range", "bytes=8-14")
+
+		err := saaskit.FileBytes(data, saaskit.WithAttachment("users.csv")).Render(w, r)
+
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusPartialContent, w.Code)
+		assert.Equal(t, "bytes 8-14/22", w.Header().Get("Content-Range"))
+		assert.Equal(t, "1,alice", w.Body.String())
+	})
+
+	t.Run("honors If-Modified-Since", func(t *testing.T) {
+		t.Parallel()
+		modTime := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/export", nil)
+		r.Header.Set("If-Modified-Since", modTime.Add(time.Hour).Format(http.TimeFormat))
+
+		err := saaskit.FileBytes(data, saaskit.WithFileModTime(modTime)).Render(w, r)
+
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusNotModified, w.Code)
+	})
+}
+
+func TestFile(t *testing.T) {
+	t.Parallel()
+
+	t.Run("streams non-seekable readers and closes them", func(t *testing.T) {
+		t.Parallel()
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/export", nil)
+		r.Header.Set("Range", "bytes=0-3")
+		body := &streamOnly{Reader: strings.NewReader("streamed content")}
+
+		err := saaskit.File(body,
+			saaskit.WithAttachment("report.bin"),
+			saaskit.WithFileContentLength(16),
+		).Render(w, r)
+
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "application/octet-stream", w.Header().Get("Content-Type"))
+		assert.Equal(t, "16", w.Header().Get("Content-Length"))
+		assert.Equal(t, "none", w.Header().Get("Accept-Ranges"))
+		assert.Equal(t, "streamed content", w.Body.String())
+		assert.True(t, body.closed)
+	})
+
+	t.Run("head request skips body", func(t *testing.T) {
+		t.Parallel()
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodHead, "/export", nil)
+
+		err := saaskit.File(&streamOnly{Reader: strings.NewReader("data")}).Render(w, r)
+
+		require.NoError(t, err)
+		assert.Empty(t, w.Body.String())
+	})
+
+	t.Run("nil reader returns error", func(t *testing.T) {
+		t.Parallel()
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/export", nil)
+
+		err := saaskit.File(nil).Render(w, r)
+		assert.ErrorIs(t, err, saaskit.ErrNilFileReader)
+	})
+}
@@ -2,8 +2,8 @@ package handler
 
 import (
 	"encoding/json"
-	"maps"
 	"net/http"
+	"slices"
 )
 
 // JSONResponse is the standard JSON response structure
@@ -18,12 +18,17 @@ type ErrorDetail struct {
 	Code    string              `json:"code,omitempty"`
 	Message string              `json:"message,omitempty"`
 	Details map[string][]string `json:"details,omitempty"`
+	Fields  []FieldError        `json:"fields,omitempty"`
 }
 
 // jsonResponse implements Response for JSON rendering
 type jsonResponse struct {
 	status int
 	body   JSONResponse
+
+	validation      ValidationError
+	flatValidation  bool
+	groupValidation bool
 }
 
 func (j jsonResponse) Render(w http.ResponseWriter, r *http.Request) error {
@@ -49,6 +54,23 @@ func WithJSONMeta(meta map[string]any) JSONOption {
 	}
 }
 
+// WithFlatValidationErrors keys validation error details by field name as added,
+// e.g. "address.zip", instead of by JSON Pointer. Use it for clients that rely on
+// the original format.
+func WithFlatValidationErrors() JSONOption {
+	return func(r *jsonResponse) {
+		r.flatValidation = true
+	}
+}
+
+// WithGroupedValidationErrors adds the "fields" list to validation error responses,
+// with one entry per field holding its pointer, field name and messages.
+func WithGroupedValidationErrors() JSONOption {
+	return func(r *jsonResponse) {
+		r.groupValidation = true
+	}
+}
+
 // JSON creates a JSON response with options
 func JSON(v any, opts ...JSONOption) Response {
 	r := &jsonResponse{
@@ -65,6 +87,7 @@ func JSON(v any, opts ...JSONOption) Response {
 		r.status = http.StatusInternalServerError
 	case error:
 		r.body.Error = errorToDetail(val, &r.status)
+		r.validation, _ = val.(ValidationError)
 	default:
 		r.body.Data = v
 	}
@@ -74,6 +97,8 @@ func JSON(v any, opts ...JSONOption) Response {
 		opt(r)
 	}
 
+	r.addValidationDetails()
+
 	return r
 }
 
@@ -90,6 +115,7 @@ func JSONError(err any, opts ...JSONOption) Response {
 		r.body.Error = e
 	case error:
 		r.body.Error = errorToDetail(e, &r.status)
+		r.validation, _ = e.(ValidationError)
 	}
 
 	// Apply options (can override status or add meta)
@@ -97,6 +123,8 @@ func JSONError(err any, opts ...JSONOption) Response {
 		opt(r)
 	}
 
+	r.addValidationDetails()
+
 	return r
 }
 
@@ -111,21 +139,15 @@ func errorToDetail(err error, status *int) *ErrorDetail {
 	}
 
 	// Check for ValidationError
-	if valErr, ok := err.(ValidationError); ok {
+	if _, ok := err.(ValidationError); ok {
 		*status = http.StatusUnprocessableEntity
 		code = "validation_error"
 
-		detail := &ErrorDetail{
+		// Field details depend on the response options, see addValidationDetails
+		return &ErrorDetail{
 			Code:    code,
 			Message: message,
 		}
-
-		if len(valErr) > 0 {
-			detail.Details = make(map[string][]string)
-			maps.Copy(detail.Details, valErr)
-		}
-
-		return detail
 	}
 
 	// Check for HTTPError
@@ -140,3 +162,24 @@ func errorToDetail(err error, status *int) *ErrorDetail {
 		Message: message,
 	}
 }
+
+// addValidationDetails fills the field details of a validation error response.
+// Details are keyed by RFC 6901 JSON Pointer ("/address/zip") unless WithFlatValidationErrors is set.
+func (r *jsonResponse) addValidationDetails() {
+	if len(r.validation) == 0 || r.body.Error == nil {
+		return
+	}
+
+	if r.flatValidation {
+		r.body.Error.Details = make(map[string][]string, len(r.validation))
+		for field, messages := range r.validation {
+			r.body.Error.Details[field] = slices.Clone(messages)
+		}
+	} else {
+		r.body.Error.Details = r.validation.Pointers()
+	}
+
+	if r.groupValidation {
+		r.body.Error.Fields = r.validation.Fields()
+	}
+}
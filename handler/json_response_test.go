@@ -297,9 +297,47 @@ func TestJSONError(t *testing.T) {
 		assert.Contains(t, got.Error.Message, "email: invalid format")
 		assert.Contains(t, got.Error.Message, "age: must be positive")
 		assert.Equal(t, map[string][]string{
-			"email": {"invalid format", "already exists"},
-			"age":   {"must be positive"},
+			"/email": {"invalid format", "already exists"},
+			"/age":   {"must be positive"},
 		}, got.Error.Details)
+		assert.Empty(t, got.Error.Fields)
+	})
+
+	t.Run("validation error with nested fields", func(t *testing.T) {
+		t.Parallel()
+
+		valErr := handler.NewValidationError()
+		valErr.Add("address.zip", "is required")
+		valErr.Add("items[2].name", "is too long")
+
+		render := func(opts ...handler.JSONOption) handler.JSONResponse {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			require.NoError(t, handler.JSONError(valErr, opts...).Render(w, r))
+			assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+
+			var got handler.JSONResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+			return got
+		}
+
+		got := render()
+		assert.Equal(t, map[string][]string{
+			"/address/zip":  {"is required"},
+			"/items/2/name": {"is too long"},
+		}, got.Error.Details)
+
+		got = render(handler.WithFlatValidationErrors())
+		assert.Equal(t, map[string][]string{
+			"address.zip":   {"is required"},
+			"items[2].name": {"is too long"},
+		}, got.Error.Details)
+
+		got = render(handler.WithGroupedValidationErrors())
+		assert.Equal(t, []handler.FieldError{
+			{Pointer: "/address/zip", Field: "address.zip", Messages: []string{"is required"}},
+			{Pointer: "/items/2/name", Field: "items[2].name", Messages: []string{"is too long"}},
+		}, got.Error.Fields)
 	})
 
 	t.Run("empty validation error", func(t *testing.T) {
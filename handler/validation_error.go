@@ -2,7 +2,9 @@ package handler
 
 import (
 	"fmt"
+	"maps"
 	"net/url"
+	"slices"
 	"strings"
 )
 
@@ -50,3 +52,78 @@ func (e ValidationError) Has(field string) bool {
 func (e ValidationError) IsEmpty() bool {
 	return len(e) == 0
 }
+
+// FieldError groups the messages of a single field for form libraries.
+type FieldError struct {
+	Pointer  string   `json:"pointer"`  // RFC 6901 JSON Pointer, e.g. "/items/2/name"
+	Field    string   `json:"field"`    // Field name as added, e.g. "items[2].name"
+	Messages []string `json:"messages"` // All messages for the field
+}
+
+// Pointers returns the errors keyed by RFC 6901 JSON Pointer instead of field name.
+// Fields that resolve to the same pointer, such as "items[2].name" and "items.2.name",
+// are merged.
+func (e ValidationError) Pointers() map[string][]string {
+	pointers := make(map[string][]string, len(e))
+	for _, field := range e.sortedFields() {
+		pointer := FieldPointer(field)
+		pointers[pointer] = append(pointers[pointer], e[field]...)
+	}
+	return pointers
+}
+
+// Fields returns the errors grouped by field, sorted by pointer.
+func (e ValidationError) Fields() []FieldError {
+	fields := make([]FieldError, 0, len(e))
+	for _, field := range e.sortedFields() {
+		fields = append(fields, FieldError{
+			Pointer:  FieldPointer(field),
+			Field:    field,
+			Messages: slices.Clone(e[field]),
+		})
+	}
+	slices.SortStableFunc(fields, func(a, b FieldError) int {
+		return strings.Compare(a.Pointer, b.Pointer)
+	})
+	return fields
+}
+
+func (e ValidationError) sortedFields() []string {
+	return slices.Sorted(maps.Keys(e))
+}
+
+// FieldPointer converts a field path to an RFC 6901 JSON Pointer.
+// Dots separate nested fields and brackets hold array indices:
+// "address.zip" becomes "/address/zip" and "items[2].name" becomes "/items/2/name".
+// "~" and "/" inside segments are escaped; paths that already start with "/"
+// are returned unchanged, and an empty path is the root pointer "".
+func FieldPointer(field string) string {
+	if field == "" || strings.HasPrefix(field, "/") {
+		return field
+	}
+
+	var b strings.Builder
+	b.Grow(len(field) + 1)
+	segment := func(s string) {
+		b.WriteByte('/')
+		b.WriteString(pointerEscaper.Replace(s))
+	}
+
+	for part := range strings.SplitSeq(field, ".") {
+		// Split "items[2][0]" into "items", "2", "0"
+		name, rest, hasIndex := strings.Cut(part, "[")
+		if name != "" || !hasIndex {
+			segment(name)
+		}
+		for hasIndex {
+			var index string
+			index, rest, _ = strings.Cut(rest, "]")
+			segment(index)
+			_, rest, hasIndex = strings.Cut(rest, "[")
+		}
+	}
+
+	return b.String()
+}
+
+var pointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
@@ -57,3 +57,43 @@ func TestValidationError(t *testing.T) {
 		assert.Equal(t, "already exists", err["email"][1])
 	})
 }
+
+func TestFieldPointer(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		"":                "",
+		"email":           "/email",
+		"address.zip":     "/address/zip",
+		"items[2].name":   "/items/2/name",
+		"items.2.name":    "/items/2/name",
+		"matrix[1][0]":    "/matrix/1/0",
+		"[0].id":          "/0/id",
+		"tags[3]":         "/tags/3",
+		"a/b.c~d":         "/a~1b/c~0d",
+		"/already/a/path": "/already/a/path",
+	}
+
+	for field, want := range tests {
+		assert.Equal(t, want, handler.FieldPointer(field), field)
+	}
+}
+
+func TestValidationError_Pointers(t *testing.T) {
+	t.Parallel()
+
+	err := handler.NewValidationError()
+	err.Add("items[0].name", "is required")
+	err.Add("items.0.name", "is too short")
+	err.Add("email", "is invalid")
+
+	assert.Equal(t, map[string][]string{
+		"/items/0/name": {"is too short", "is required"},
+		"/email":        {"is invalid"},
+	}, err.Pointers())
+
+	fields := err.Fields()
+	assert.Len(t, fields, 3)
+	assert.Equal(t, "/email", fields[0].Pointer)
+	assert.Equal(t, "/items/0/name", fields[1].Pointer)
+}
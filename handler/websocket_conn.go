@@ -0,0 +1,344 @@
+package handler
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
+)
+
+// WSConn is a WebSocket connection passed to a WebSocketHandler.
+//
+// WriteJSON and Close are safe for concurrent use. ReadJSON must be called from
+// a single goroutine, and the handler should keep reading for as long as the
+// connection is open: control frames such as pings and close requests are
+// processed while reading, and the read timeout applies only while reading.
+type WSConn interface {
+	// Context returns a context that is cancelled when the connection is closed
+	// by either side or the request context is done.
+	Context() context.Context
+
+	// ReadJSON reads the next text or binary message and decodes it into v.
+	// Returns ErrWebSocketClosed once the connection is closed.
+	ReadJSON(v any) error
+
+	// WriteJSON encodes v and sends it as a text message.
+	WriteJSON(v any) error
+
+	// Close sends a normal closure frame and closes the underlying connection.
+	Close() error
+}
+
+// WebSocket opcodes (RFC 6455, section 5.2)
+const (
+	opContinuation byte = 0x0
+	opText         byte = 0x1
+	opBinary       byte = 0x2
+	opClose        byte = 0x8
+	opPing         byte = 0x9
+	opPong         byte = 0xA
+)
+
+// WebSocket close status codes (RFC 6455, section 7.4.1)
+const (
+	closeNormal         uint16 = 1000
+	closeProtocolError  uint16 = 1002
+	closeInvalidPayload uint16 = 1007
+	closeTooBig         uint16 = 1009
+	closeInternalError  uint16 = 1011
+)
+
+// maxControlPayload is the largest payload allowed in a control frame.
+const maxControlPayload = 125
+
+// closeWriteTimeout bounds how long closing waits for a write in progress,
+// so a stalled peer can't block Close.
+const closeWriteTimeout = time.Second
+
+// wsConn implements WSConn on top of a hijacked HTTP connection.
+type wsConn struct {
+	ctx         context.Context
+	cancel      context.CancelFunc
+	conn        net.Conn
+	br          *bufio.Reader
+	bw          *bufio.Writer
+	readLimit   int64
+	readTimeout time.Duration
+
+	writeMu     sync.Mutex
+	writeFailed bool // A write failed midway, so the stream can't carry a close frame; guarded by writeMu
+	closeOnce   sync.Once
+	closed      atomic.Bool
+}
+
+// Context returns the connection context.
+func (c *wsConn) Context() context.Context {
+	return c.ctx
+}
+
+// ReadJSON reads a message and decodes it into v.
+func (c *wsConn) ReadJSON(v any) error {
+	msg, err := c.readMessage()
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(msg, v)
+}
+
+// WriteJSON encodes v and sends it as a text message.
+func (c *wsConn) WriteJSON(v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return c.writeFrame(opText, data)
+}
+
+// Close sends a normal closure frame and closes the connection.
+func (c *wsConn) Close() error {
+	return c.closeWith(closeNormal, "")
+}
+
+// closeWith sends a close frame with the given status, once, and closes the connection.
+// It doesn't wait for the peer to acknowledge the close.
+func (c *wsConn) closeWith(code uint16, reason string) error {
+	var err error
+	c.closeOnce.Do(func() {
+		// Let a write in progress finish before the close frame; one blocked on a
+		// stalled peer fails at the deadline and the frame is skipped
+		_ = c.conn.SetWriteDeadline(time.Now().Add(closeWriteTimeout))
+		c.writeMu.Lock()
+		if !c.writeFailed {
+			payload := binary.BigEndian.AppendUint16(nil, code)
+			payload = append(payload, reason...)
+			_ = c.writeFrameLocked(opClose, payload)
+		}
+		c.closed.Store(true)
+		c.writeMu.Unlock()
+		c.cancel()
+		err = c.conn.Close()
+	})
+	return err
+}
+
+// readMessage reads frames until a complete data message is assembled,
+// answering pings and close requests along the way.
+func (c *wsConn) readMessage() ([]byte, error) {
+	var (
+		msg     []byte
+		started bool
+		text    bool
+	)
+
+	for {
+		fin, op, payload, err := c.readFrame()
+		if err != nil {
+			return nil, err
+		}
+
+		switch op {
+		case opPing:
+			if err := c.writeFrame(opPong, payload); err != nil {
+				return nil, err
+			}
+			continue
+		case opPong:
+			continue
+		case opClose:
+			// Echo the peer's status code to complete the close handshake
+			code := closeNormal
+			switch {
+			case len(payload) == 1:
+				return nil, c.protocolError()
+			case len(payload) >= 2:
+				if !utf8.Valid(payload[2:]) {
+					return nil, c.invalidPayload()
+				}
+				code = binary.BigEndian.Uint16(payload)
+			}
+			_ = c.closeWith(code, "")
+			return nil, ErrWebSocketClosed
+		case opText, opBinary:
+			if started {
+				return nil, c.protocolError()
+			}
+			started = true
+			text = op == opText
+			msg = payload
+		case opContinuation:
+			if !started {
+				return nil, c.protocolError()
+			}
+			msg = append(msg, payload...)
+		default:
+			return nil, c.protocolError()
+		}
+
+		if int64(len(msg)) > c.readLimit {
+			_ = c.closeWith(closeTooBig, "")
+			return nil, ErrWebSocketMessageTooBig
+		}
+
+		if fin {
+			// Validated per message, a character may be split across fragments
+			if text && !utf8.Valid(msg) {
+				return nil, c.invalidPayload()
+			}
+			return msg, nil
+		}
+	}
+}
+
+// readFrame reads a single client frame and unmasks its payload.
+// With a read timeout, the connection is closed when no frame arrives in time.
+func (c *wsConn) readFrame() (fin bool, op byte, payload []byte, err error) {
+	if c.readTimeout > 0 {
+		_ = c.conn.SetReadDeadline(time.Now().Add(c.readTimeout))
+	}
+
+	var header [2]byte
+	if _, err := io.ReadFull(c.br, header[:]); err != nil {
+		return false, 0, nil, c.readError(err)
+	}
+
+	fin = header[0]&0x80 != 0
+	op = header[0] & 0x0F
+	masked := header[1]&0x80 != 0
+
+	// No extensions are negotiated, so reserved bits must be unset,
+	// and clients must mask every frame
+	if header[0]&0x70 != 0 || !masked {
+		return false, 0, nil, c.protocolError()
+	}
+
+	length := uint64(header[1] & 0x7F)
+	switch length {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(c.br, ext[:]); err != nil {
+			return false, 0, nil, c.readError(err)
+		}
+		length = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(c.br, ext[:]); err != nil {
+			return false, 0, nil, c.readError(err)
+		}
+		length = binary.BigEndian.Uint64(ext[:])
+	}
+
+	if op >= opClose && (!fin || length > maxControlPayload) {
+		return false, 0, nil, c.protocolError()
+	}
+
+	if length > uint64(c.readLimit) {
+		_ = c.closeWith(closeTooBig, "")
+		return false, 0, nil, ErrWebSocketMessageTooBig
+	}
+
+	var mask [4]byte
+	if _, err := io.ReadFull(c.br, mask[:]); err != nil {
+		return false, 0, nil, c.readError(err)
+	}
+
+	payload = make([]byte, length)
+	if _, err := io.ReadFull(c.br, payload); err != nil {
+		return false, 0, nil, c.readError(err)
+	}
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+
+	return fin, op, payload, nil
+}
+
+// writeFrame sends a single unmasked frame with the FIN bit set.
+func (c *wsConn) writeFrame(op byte, payload []byte) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	if c.closed.Load() {
+		return ErrWebSocketClosed
+	}
+	return c.writeFrameLocked(op, payload)
+}
+
+// writeFrameLocked writes a frame; the caller must hold writeMu.
+func (c *wsConn) writeFrameLocked(op byte, payload []byte) error {
+	if err := c.writeFrameBytes(op, payload); err != nil {
+		c.writeFailed = true
+		return errors.Join(ErrWebSocketClosed, err)
+	}
+	return nil
+}
+
+// writeFrameBytes encodes a frame into the buffered writer and flushes it.
+func (c *wsConn) writeFrameBytes(op byte, payload []byte) error {
+	header := make([]byte, 0, 10)
+	header = append(header, 0x80|op)
+	switch n := len(payload); {
+	case n <= maxControlPayload:
+		header = append(header, byte(n))
+	case n <= 0xFFFF:
+		header = append(header, 126)
+		header = binary.BigEndian.AppendUint16(header, uint16(n))
+	default:
+		header = append(header, 127)
+		header = binary.BigEndian.AppendUint64(header, uint64(n))
+	}
+
+	if _, err := c.bw.Write(header); err != nil {
+		return err
+	}
+	if _, err := c.bw.Write(payload); err != nil {
+		return err
+	}
+	return c.bw.Flush()
+}
+
+// pingLoop sends a ping every interval until the connection is closed,
+// so idle peers answer with pongs that keep the read deadline moving.
+func (c *wsConn) pingLoop(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.ctx.Done():
+			return
+		case <-ticker.C:
+			if err := c.writeFrame(opPing, nil); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// protocolError closes the connection after a malformed frame.
+func (c *wsConn) protocolError() error {
+	_ = c.closeWith(closeProtocolError, "")
+	return ErrWebSocketProtocol
+}
+
+// invalidPayload closes the connection after a text message or close reason that isn't valid UTF-8.
+func (c *wsConn) invalidPayload() error {
+	_ = c.closeWith(closeInvalidPayload, "")
+	return ErrWebSocketProtocol
+}
+
+// readError closes the connection after a failed read; the stream can't be resumed.
+func (c *wsConn) readError(err error) error {
+	c.closeOnce.Do(func() {
+		c.closed.Store(true)
+		c.cancel()
+		_ = c.conn.Close()
+	})
+	return errors.Join(ErrWebSocketClosed, err)
+}
@@ -0,0 +1,248 @@
+package handler
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DefaultWebSocketReadLimit is the maximum size of an incoming WebSocket message.
+const DefaultWebSocketReadLimit = 1 << 20 // 1MB
+
+// DefaultWebSocketPingInterval is how often the server pings an open WebSocket connection.
+const DefaultWebSocketPingInterval = 30 * time.Second
+
+// DefaultWebSocketReadTimeout is how long a read waits for the next frame before the
+// connection is closed. Pongs count, so it must exceed the ping interval.
+const DefaultWebSocketReadTimeout = 60 * time.Second
+
+// websocketGUID is the RFC 6455 magic value used to compute Sec-WebSocket-Accept.
+const websocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// WebSocketHandler is a function that handles a WebSocket connection.
+// It runs for the lifetime of the connection; the connection is closed when
+// the handler returns. Returning an error closes it with status 1011 (internal error).
+//
+// Example:
+//
+//	handler.WebSocket(func(conn handler.WSConn) error {
+//		for {
+//			var msg ChatMessage
+//			if err := conn.ReadJSON(&msg); err != nil {
+//				if errors.Is(err, handler.ErrWebSocketClosed) {
+//					return nil
+//				}
+//				return err
+//			}
+//			if err := conn.WriteJSON(reply(msg)); err != nil {
+//				return err
+//			}
+//		}
+//	})
+type WebSocketHandler func(conn WSConn) error
+
+// websocketResponse implements Response for WebSocket connections.
+type websocketResponse struct {
+	handler      WebSocketHandler
+	readLimit    int64
+	readTimeout  time.Duration
+	pingInterval time.Duration
+	checkOrigin  func(r *http.Request) bool
+}
+
+// WebSocketOption configures a WebSocket response
+type WebSocketOption func(*websocketResponse)
+
+// WithWebSocketReadLimit sets the maximum size of an incoming message in bytes.
+// Larger messages close the connection with status 1009 (message too big).
+// Defaults to DefaultWebSocketReadLimit.
+func WithWebSocketReadLimit(n int64) WebSocketOption {
+	return func(ws *websocketResponse) {
+		if n > 0 {
+			ws.readLimit = n
+		}
+	}
+}
+
+// WithWebSocketReadTimeout sets how long ReadJSON waits for the next frame from the
+// client, including pongs and other control frames. When it passes, the connection
+// is closed and ReadJSON returns ErrWebSocketClosed. Zero disables the timeout.
+// Defaults to DefaultWebSocketReadTimeout.
+func WithWebSocketReadTimeout(d time.Duration) WebSocketOption {
+	return func(ws *websocketResponse) {
+		if d >= 0 {
+			ws.readTimeout = d
+		}
+	}
+}
+
+// WithWebSocketPingInterval sets how often the server sends pings, so idle clients
+// answer with pongs before the read timeout passes. Keep it well below the read
+// timeout. Zero disables pings. Defaults to DefaultWebSocketPingInterval.
+func WithWebSocketPingInterval(d time.Duration) WebSocketOption {
+	return func(ws *websocketResponse) {
+		if d >= 0 {
+			ws.pingInterval = d
+		}
+	}
+}
+
+// WithWebSocketOriginCheck replaces the default same-origin check.
+// Browsers don't apply CORS to WebSockets, so accepting any origin lets other sites
+// open authenticated connections with the user's cookies.
+func WithWebSocketOriginCheck(fn func(r *http.Request) bool) WebSocketOption {
+	return func(ws *websocketResponse) {
+		if fn != nil {
+			ws.checkOrigin = fn
+		}
+	}
+}
+
+// Render validates the handshake, upgrades the connection and runs the handler.
+// Handshake failures are returned before the upgrade, so they reach the error handler
+// like any other response error. After the upgrade the response writer is hijacked,
+// so handler errors are reported to the client as a close frame instead.
+func (ws websocketResponse) Render(w http.ResponseWriter, r *http.Request) error {
+	if r.Method != http.MethodGet ||
+		!headerContainsToken(r.Header, "Connection", "upgrade") ||
+		!headerContainsToken(r.Header, "Upgrade", "websocket") {
+		return ErrUpgradeRequired
+	}
+
+	if r.Header.Get("Sec-WebSocket-Version") != "13" {
+		w.Header().Set("Sec-WebSocket-Version", "13")
+		return ErrUpgradeRequired
+	}
+
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if decoded, err := base64.StdEncoding.DecodeString(key); err != nil || len(decoded) != 16 {
+		return NewHTTPError(http.StatusBadRequest, "invalid_websocket_key")
+	}
+
+	if !ws.checkOrigin(r) {
+		return ErrForbidden
+	}
+
+	netConn, brw, err := http.NewResponseController(w).Hijack()
+	if err != nil {
+		return errors.Join(ErrHijackNotSupported, err)
+	}
+
+	ctx, cancel := context.WithCancel(r.Context())
+	conn := &wsConn{
+		ctx:         ctx,
+		cancel:      cancel,
+		conn:        netConn,
+		br:          brw.Reader,
+		bw:          brw.Writer,
+		readLimit:   ws.readLimit,
+		readTimeout: ws.readTimeout,
+	}
+
+	handshake := "HTTP/1.1 101 Switching Protocols\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + websocketAccept(key) + "\r\n\r\n"
+	if _, err := conn.bw.WriteString(handshake); err != nil {
+		cancel()
+		_ = netConn.Close()
+		return nil
+	}
+	if err := conn.bw.Flush(); err != nil {
+		cancel()
+		_ = netConn.Close()
+		return nil
+	}
+
+	// Tie the connection to the request context, e.g. on server shutdown
+	go func() {
+		<-ctx.Done()
+		_ = conn.Close()
+	}()
+
+	if ws.pingInterval > 0 {
+		go conn.pingLoop(ws.pingInterval)
+	}
+
+	if err := ws.handler(conn); err != nil && !errors.Is(err, ErrWebSocketClosed) {
+		_ = conn.closeWith(closeInternalError, "")
+		return nil
+	}
+
+	_ = conn.Close()
+	return nil
+}
+
+// WebSocket creates a response that upgrades the request to a WebSocket
+// connection and runs the given handler with it.
+//
+// Decorators run before the response is rendered, so authentication and
+// authorization decorators reject the request before the upgrade happens.
+// By default only same-origin requests (or requests without an Origin header)
+// are accepted; see WithWebSocketOriginCheck.
+//
+// Example usage in a handler:
+//
+//	handler.HandlerFunc[handler.Context, RoomRequest](
+//		func(ctx handler.Context, req RoomRequest) handler.Response {
+//			return handler.WebSocket(func(conn handler.WSConn) error {
+//				events := hub.Subscribe(conn.Context(), req.RoomID)
+//				for event := range events {
+//					if err := conn.WriteJSON(event); err != nil {
+//						return err
+//					}
+//				}
+//				return nil
+//			})
+//		},
+//	)
+func WebSocket(handler WebSocketHandler, opts ...WebSocketOption) Response {
+	ws := websocketResponse{
+		handler:      handler,
+		readLimit:    DefaultWebSocketReadLimit,
+		readTimeout:  DefaultWebSocketReadTimeout,
+		pingInterval: DefaultWebSocketPingInterval,
+		checkOrigin:  sameOrigin,
+	}
+	for _, opt := range opts {
+		opt(&ws)
+	}
+	return ws
+}
+
+// sameOrigin accepts requests without an Origin header (non-browser clients)
+// and requests whose Origin host matches the Host header.
+func sameOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(u.Host, r.Host)
+}
+
+// websocketAccept computes the Sec-WebSocket-Accept value for a client key.
+func websocketAccept(key string) string {
+	h := sha1.Sum([]byte(key + websocketGUID))
+	return base64.StdEncoding.EncodeToString(h[:])
+}
+
+// headerContainsToken reports whether a comma-separated header contains the token.
+func headerContainsToken(h http.Header, name, token string) bool {
+	for _, value := range h.Values(name) {
+		for part := range strings.SplitSeq(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(part), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,530 @@
+package handler_test
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dmitrymomot/saaskit/handler"
+)
+
+type wsMessage struct {
+	Text string `json:"text"`
+}
+
+// wsClient is a minimal RFC 6455 client for exercising the server side.
+type wsClient struct {
+	conn net.Conn
+	br   *bufio.Reader
+}
+
+// dialWS performs the opening handshake and returns the response and, on a 101, a client.
+func dialWS(t *testing.T, serverURL string, header http.Header) (*http.Response, *wsClient) {
+	t.Helper()
+
+	conn, err := net.Dial("tcp", strings.TrimPrefix(serverURL, "http://"))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	require.NoError(t, conn.SetDeadline(time.Now().Add(5*time.Second)))
+
+	key := make([]byte, 16)
+	_, _ = rand.Read(key)
+
+	req, err := http.NewRequest(http.MethodGet, serverURL+"/ws", nil)
+	require.NoError(t, err)
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	req.Header.Set("Sec-WebSocket-Key", base64.StdEncoding.EncodeToString(key))
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	require.NoError(t, req.Write(conn))
+
+	br := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(br, req)
+	require.NoError(t, err)
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		return resp, nil
+	}
+	return resp, &wsClient{conn: conn, br: br}
+}
+
+func (c *wsClient) writeFrame(t *testing.T, op byte, payload []byte) {
+	t.Helper()
+	c.writeFragment(t, op, true, payload)
+}
+
+func (c *wsClient) writeFragment(t *testing.T, op byte, fin bool, payload []byte) {
+	t.Helper()
+
+	frame := []byte{op}
+	if fin {
+		frame[0] |= 0x80
+	}
+	switch n := len(payload); {
+	case n <= 125:
+		frame = append(frame, 0x80|byte(n))
+	case n <= 0xFFFF:
+		frame = append(frame, 0x80|126)
+		frame = binary.BigEndian.AppendUint16(frame, uint16(n))
+	default:
+		frame = append(frame, 0x80|127)
+		frame = binary.BigEndian.AppendUint64(frame, uint64(n))
+	}
+
+	mask := [4]byte{1, 2, 3, 4}
+	frame = append(frame, mask[:]...)
+	for i, b := range payload {
+		frame = append(frame, b^mask[i%4])
+	}
+
+	_, err := c.conn.Write(frame)
+	require.NoError(t, err)
+}
+
+func (c *wsClient) writeJSON(t *testing.T, v any) {
+	t.Helper()
+	data, err := json.Marshal(v)
+	require.NoError(t, err)
+	c.writeFrame(t, 0x1, data)
+}
+
+func (c *wsClient) readFrame(t *testing.T) (byte, []byte) {
+	t.Helper()
+
+	var header [2]byte
+	_, err := io.ReadFull(c.br, header[:])
+	require.NoError(t, err)
+	assert.Zero(t, header[1]&0x80, "server frames must not be masked")
+
+	length := uint64(header[1] & 0x7F)
+	switch length {
+	case 126:
+		var ext [2]byte
+		_, err = io.ReadFull(c.br, ext[:])
+		require.NoError(t, err)
+		length = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		_, err = io.ReadFull(c.br, ext[:])
+		require.NoError(t, err)
+		length = binary.BigEndian.Uint64(ext[:])
+	}
+
+	payload := make([]byte, length)
+	_, err = io.ReadFull(c.br, payload)
+	require.NoError(t, err)
+	return header[0] & 0x0F, payload
+}
+
+func (c *wsClient) readClose(t *testing.T) uint16 {
+	t.Helper()
+	op, payload := c.readFrame(t)
+	require.Equal(t, byte(0x8), op)
+	require.GreaterOrEqual(t, len(payload), 2)
+	return binary.BigEndian.Uint16(payload)
+}
+
+func echoHandler(conn handler.WSConn) error {
+	for {
+		var msg wsMessage
+		if err := conn.ReadJSON(&msg); err != nil {
+			if errors.Is(err, handler.ErrWebSocketClosed) {
+				return nil
+			}
+			return err
+		}
+		if err := conn.WriteJSON(wsMessage{Text: "echo: " + msg.Text}); err != nil {
+			return err
+		}
+	}
+}
+
+func TestWebSocket(t *testing.T) {
+	t.Parallel()
+
+	t.Run("echoes JSON messages", func(t *testing.T) {
+		t.Parallel()
+
+		srv := httptest.NewServer(handler.Wrap(func(ctx handler.Context, req struct{}) handler.Response {
+			return handler.WebSocket(echoHandler)
+		}))
+		defer srv.Close()
+
+		resp, client := dialWS(t, srv.URL, nil)
+		require.NotNil(t, client)
+		assert.Equal(t, "websocket", strings.ToLower(resp.Header.Get("Upgrade")))
+		assert.NotEmpty(t, resp.Header.Get("Sec-WebSocket-Accept"))
+
+		client.writeJSON(t, wsMessage{Text: "hello"})
+		op, payload := client.readFrame(t)
+		assert.Equal(t, byte(0x1), op)
+		assert.JSONEq(t, `{"text":"echo: hello"}`, string(payload))
+
+		// Pings are answered while the handler reads
+		client.writeFrame(t, 0x9, []byte("ping"))
+		op, payload = client.readFrame(t)
+		assert.Equal(t, byte(0xA), op)
+		assert.Equal(t, "ping", string(payload))
+
+		// Large messages use the extended length
+		long := strings.Repeat("x", 70000)
+		client.writeJSON(t, wsMessage{Text: long})
+		_, payload = client.readFrame(t)
+		var got wsMessage
+		require.NoError(t, json.Unmarshal(payload, &got))
+		assert.Equal(t, "echo: "+long, got.Text)
+
+		client.writeFrame(t, 0x8, binary.BigEndian.AppendUint16(nil, 1000))
+		assert.Equal(t, uint16(1000), client.readClose(t))
+	})
+
+	t.Run("reassembles fragmented messages", func(t *testing.T) {
+		t.Parallel()
+
+		srv := httptest.NewServer(handler.Wrap(func(ctx handler.Context, req struct{}) handler.Response {
+			return handler.WebSocket(echoHandler)
+		}))
+		defer srv.Close()
+
+		_, client := dialWS(t, srv.URL, nil)
+		require.NotNil(t, client)
+
+		// First fragment without FIN, then a continuation with FIN
+		data := []byte(`{"text":"split"}`)
+		frame := []byte{0x01, 0x80 | 8, 0, 0, 0, 0}
+		frame = append(frame, data[:8]...)
+		_, err := client.conn.Write(frame)
+		require.NoError(t, err)
+		client.writeFrame(t, 0x0, data[8:])
+
+		_, payload := client.readFrame(t)
+		assert.JSONEq(t, `{"text":"echo: split"}`, string(payload))
+	})
+
+	t.Run("handler error closes with internal error", func(t *testing.T) {
+		t.Parallel()
+
+		srv := httptest.NewServer(handler.Wrap(func(ctx handler.Context, req struct{}) handler.Response {
+			return handler.WebSocket(func(conn handler.WSConn) error {
+				return errors.New("boom")
+			})
+		}))
+		defer srv.Close()
+
+		_, client := dialWS(t, srv.URL, nil)
+		require.NotNil(t, client)
+		assert.Equal(t, uint16(1011), client.readClose(t))
+	})
+
+	t.Run("context is cancelled when client closes", func(t *testing.T) {
+		t.Parallel()
+
+		done := make(chan struct{})
+		srv := httptest.NewServer(handler.Wrap(func(ctx handler.Context, req struct{}) handler.Response {
+			return handler.WebSocket(func(conn handler.WSConn) error {
+				defer close(done)
+				go func() {
+					var msg wsMessage
+					_ = conn.ReadJSON(&msg)
+				}()
+				<-conn.Context().Done()
+				return nil
+			})
+		}))
+		defer srv.Close()
+
+		_, client := dialWS(t, srv.URL, nil)
+		require.NotNil(t, client)
+		require.NoError(t, client.conn.Close())
+
+		select {
+		case <-done:
+		case <-time.After(2 * time.Second):
+			t.Fatal("handler did not observe connection close")
+		}
+	})
+
+	t.Run("read limit", func(t *testing.T) {
+		t.Parallel()
+
+		srv := httptest.NewServer(handler.Wrap(func(ctx handler.Context, req struct{}) handler.Response {
+			return handler.WebSocket(echoHandler, handler.WithWebSocketReadLimit(16))
+		}))
+		defer srv.Close()
+
+		_, client := dialWS(t, srv.URL, nil)
+		require.NotNil(t, client)
+
+		client.writeJSON(t, wsMessage{Text: "this message is too long"})
+		assert.Equal(t, uint16(1009), client.readClose(t))
+	})
+
+	t.Run("rejects unmasked frames", func(t *testing.T) {
+		t.Parallel()
+
+		srv := httptest.NewServer(handler.Wrap(func(ctx handler.Context, req struct{}) handler.Response {
+			return handler.WebSocket(echoHandler)
+		}))
+		defer srv.Close()
+
+		_, client := dialWS(t, srv.URL, nil)
+		require.NotNil(t, client)
+
+		_, err := client.conn.Write([]byte{0x81, 0x02, '{', '}'})
+		require.NoError(t, err)
+		assert.Equal(t, uint16(1002), client.readClose(t))
+	})
+}
+
+func TestWebSocket_Frames(t *testing.T) {
+	t.Parallel()
+
+	newServer := func(t *testing.T, h handler.WebSocketHandler, opts ...handler.WebSocketOption) *wsClient {
+		t.Helper()
+		srv := httptest.NewServer(handler.Wrap(func(ctx handler.Context, req struct{}) handler.Response {
+			return handler.WebSocket(h, opts...)
+		}))
+		t.Cleanup(srv.Close)
+
+		_, client := dialWS(t, srv.URL, nil)
+		require.NotNil(t, client)
+		return client
+	}
+
+	t.Run("rejects invalid UTF-8 in text messages", func(t *testing.T) {
+		t.Parallel()
+		client := newServer(t, echoHandler)
+
+		client.writeFrame(t, 0x1, []byte{'"', 0xff, '"'})
+		assert.Equal(t, uint16(1007), client.readClose(t))
+	})
+
+	t.Run("validates UTF-8 per message, not per fragment", func(t *testing.T) {
+		t.Parallel()
+		client := newServer(t, echoHandler)
+
+		// "é" is split across the two fragments
+		data := []byte(`{"text":"café"}`)
+		split := strings.Index(string(data), "é") + 1
+		client.writeFragment(t, 0x1, false, data[:split])
+		client.writeFragment(t, 0x0, true, data[split:])
+
+		op, payload := client.readFrame(t)
+		assert.Equal(t, byte(0x1), op)
+		assert.JSONEq(t, `{"text":"echo: café"}`, string(payload))
+	})
+
+	t.Run("rejects invalid UTF-8 in close reason", func(t *testing.T) {
+		t.Parallel()
+		client := newServer(t, echoHandler)
+
+		client.writeFrame(t, 0x8, []byte{0x03, 0xe8, 0xff})
+		assert.Equal(t, uint16(1007), client.readClose(t))
+	})
+
+	t.Run("sends pings", func(t *testing.T) {
+		t.Parallel()
+		client := newServer(t, echoHandler, handler.WithWebSocketPingInterval(20*time.Millisecond))
+
+		op, payload := client.readFrame(t)
+		assert.Equal(t, byte(0x9), op)
+		assert.Empty(t, payload)
+	})
+
+	t.Run("read timeout closes idle connections", func(t *testing.T) {
+		t.Parallel()
+		closed := make(chan error, 1)
+		client := newServer(t, func(conn handler.WSConn) error {
+			var msg wsMessage
+			closed <- conn.ReadJSON(&msg)
+			return nil
+		}, handler.WithWebSocketPingInterval(0), handler.WithWebSocketReadTimeout(50*time.Millisecond))
+
+		select {
+		case err := <-closed:
+			assert.ErrorIs(t, err, handler.ErrWebSocketClosed)
+		case <-time.After(2 * time.Second):
+			t.Fatal("read did not time out")
+		}
+		_, err := client.br.ReadByte()
+		assert.Error(t, err, "connection is closed")
+	})
+
+	t.Run("pongs keep the connection open", func(t *testing.T) {
+		t.Parallel()
+		client := newServer(t, echoHandler,
+			handler.WithWebSocketPingInterval(20*time.Millisecond),
+			handler.WithWebSocketReadTimeout(100*time.Millisecond),
+		)
+
+		for range 10 {
+			op, payload := client.readFrame(t)
+			require.Equal(t, byte(0x9), op)
+			client.writeFrame(t, 0xA, payload)
+		}
+
+		client.writeJSON(t, wsMessage{Text: "still here"})
+		for {
+			op, payload := client.readFrame(t)
+			if op == 0x9 {
+				continue
+			}
+			assert.JSONEq(t, `{"text":"echo: still here"}`, string(payload))
+			break
+		}
+	})
+
+	t.Run("close frame follows a write in progress", func(t *testing.T) {
+		t.Parallel()
+		large := strings.Repeat("a", 16<<20)
+		client := newServer(t, func(conn handler.WSConn) error {
+			go func() { _ = conn.WriteJSON(wsMessage{Text: large}) }()
+			time.Sleep(50 * time.Millisecond) // The client isn't reading, so the write blocks
+			return nil
+		})
+
+		time.Sleep(100 * time.Millisecond)
+		for {
+			op, payload := client.readFrame(t)
+			if op != 0x8 {
+				continue
+			}
+			require.GreaterOrEqual(t, len(payload), 2)
+			assert.Equal(t, uint16(1000), binary.BigEndian.Uint16(payload))
+			break
+		}
+	})
+}
+
+func TestWebSocket_Handshake(t *testing.T) {
+	t.Parallel()
+
+	newServer := func(t *testing.T, opts ...handler.WebSocketOption) *httptest.Server {
+		t.Helper()
+		srv := httptest.NewServer(handler.Wrap(func(ctx handler.Context, req struct{}) handler.Response {
+			return handler.WebSocket(echoHandler, opts...)
+		}))
+		t.Cleanup(srv.Close)
+		return srv
+	}
+
+	t.Run("plain request requires upgrade", func(t *testing.T) {
+		t.Parallel()
+
+		srv := newServer(t)
+		resp, err := http.Get(srv.URL)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusUpgradeRequired, resp.StatusCode)
+	})
+
+	t.Run("unsupported version", func(t *testing.T) {
+		t.Parallel()
+
+		srv := newServer(t)
+		resp, client := dialWS(t, srv.URL, http.Header{"Sec-Websocket-Version": {"8"}})
+		assert.Nil(t, client)
+		assert.Equal(t, http.StatusUpgradeRequired, resp.StatusCode)
+		assert.Equal(t, "13", resp.Header.Get("Sec-WebSocket-Version"))
+	})
+
+	t.Run("invalid key", func(t *testing.T) {
+		t.Parallel()
+
+		srv := newServer(t)
+		resp, client := dialWS(t, srv.URL, http.Header{"Sec-Websocket-Key": {"short"}})
+		assert.Nil(t, client)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("cross-origin rejected by default", func(t *testing.T) {
+		t.Parallel()
+
+		srv := newServer(t)
+		resp, client := dialWS(t, srv.URL, http.Header{"Origin": {"https://evil.example"}})
+		assert.Nil(t, client)
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+
+	t.Run("same origin accepted", func(t *testing.T) {
+		t.Parallel()
+
+		srv := newServer(t)
+		_, client := dialWS(t, srv.URL, http.Header{"Origin": {srv.URL}})
+		assert.NotNil(t, client)
+	})
+
+	t.Run("custom origin check", func(t *testing.T) {
+		t.Parallel()
+
+		srv := newServer(t, handler.WithWebSocketOriginCheck(func(r *http.Request) bool {
+			return r.Header.Get("Origin") == "https://app.example"
+		}))
+		_, client := dialWS(t, srv.URL, http.Header{"Origin": {"https://app.example"}})
+		assert.NotNil(t, client)
+	})
+
+	t.Run("decorators run before upgrade", func(t *testing.T) {
+		t.Parallel()
+
+		var upgraded atomic.Bool
+		requireAuth := func(next handler.HandlerFunc[handler.Context, struct{}]) handler.HandlerFunc[handler.Context, struct{}] {
+			return func(ctx handler.Context, req struct{}) handler.Response {
+				if ctx.Request().Header.Get("Authorization") == "" {
+					return handler.JSONError(handler.ErrUnauthorized)
+				}
+				return next(ctx, req)
+			}
+		}
+
+		srv := httptest.NewServer(handler.Wrap(
+			func(ctx handler.Context, req struct{}) handler.Response {
+				return handler.WebSocket(func(conn handler.WSConn) error {
+					upgraded.Store(true)
+					return nil
+				})
+			},
+			handler.WithDecorators(requireAuth),
+		))
+		defer srv.Close()
+
+		resp, client := dialWS(t, srv.URL, nil)
+		assert.Nil(t, client)
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+		assert.False(t, upgraded.Load())
+
+		_, client = dialWS(t, srv.URL, http.Header{"Authorization": {"Bearer token"}})
+		require.NotNil(t, client)
+		assert.Equal(t, uint16(1000), client.readClose(t))
+		assert.True(t, upgraded.Load())
+	})
+
+	t.Run("response writer without hijacking", func(t *testing.T) {
+		t.Parallel()
+
+		req := httptest.NewRequest(http.MethodGet, "/ws", nil)
+		req.Header.Set("Connection", "Upgrade")
+		req.Header.Set("Upgrade", "websocket")
+		req.Header.Set("Sec-WebSocket-Version", "13")
+		req.Header.Set("Sec-WebSocket-Key", base64.StdEncoding.EncodeToString(make([]byte, 16)))
+
+		err := handler.WebSocket(echoHandler).Render(httptest.NewRecorder(), req)
+		assert.ErrorIs(t, err, handler.ErrHijackNotSupported)
+	})
+}
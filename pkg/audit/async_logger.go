@@ -3,9 +3,10 @@ package audit
 import "context"
 
 // NewAsyncLogger creates a logger optimized for high-throughput scenarios.
-// Returns both the logger and a cleanup function that should be called during shutdown.
+// Returns both the logger and a cleanup function that should be called during shutdown;
+// its FlushResult tells how many buffered events were dropped.
 // BufferSize determines memory usage vs throughput tradeoff (typical: 1000-10000).
-func NewAsyncLogger(bw batchWriter, bufferSize int, opts ...Option) (*Logger, func(context.Context) error) {
+func NewAsyncLogger(bw batchWriter, bufferSize int, opts ...Option) (*Logger, func(context.Context) FlushResult) {
 	asyncWriter, closeFunc := NewAsyncWriter(bw, AsyncOptions{
 		BufferSize: bufferSize,
 	})
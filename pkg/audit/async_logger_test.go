@@ -26,7 +26,7 @@ func TestNewAsyncLogger(t *testing.T) {
 		assert.NotNil(t, closeFunc)
 
 		// Clean up
-		err := closeFunc(context.Background())
+		err := closeFunc(context.Background()).Err()
 		assert.NoError(t, err)
 	})
 
@@ -50,7 +50,7 @@ func TestNewAsyncLogger(t *testing.T) {
 		assert.Equal(t, "test-tenant", event.TenantID)
 
 		// Clean up
-		err := closeFunc(context.Background())
+		err := closeFunc(context.Background()).Err()
 		assert.NoError(t, err)
 	})
 
@@ -64,7 +64,7 @@ func TestNewAsyncLogger(t *testing.T) {
 		assert.NotNil(t, logger)
 
 		// Clean up
-		err := closeFunc(context.Background())
+		err := closeFunc(context.Background()).Err()
 		assert.NoError(t, err)
 	})
 }
@@ -83,7 +83,7 @@ func TestAsyncLogger_Integration(t *testing.T) {
 
 		logger, closeFunc := NewAsyncLogger(mockBW, 100)
 		defer func() {
-			err := closeFunc(context.Background())
+			err := closeFunc(context.Background()).Err()
 			assert.NoError(t, err)
 		}()
 
@@ -109,7 +109,7 @@ func TestAsyncLogger_Integration(t *testing.T) {
 
 		logger, closeFunc := NewAsyncLogger(mockBW, 100)
 		defer func() {
-			err := closeFunc(context.Background())
+			err := closeFunc(context.Background()).Err()
 			assert.NoError(t, err)
 		}()
 
@@ -138,7 +138,7 @@ func TestAsyncLogger_Integration(t *testing.T) {
 			BatchTimeout: 100 * time.Millisecond,
 		})
 		defer func() {
-			err := closeFunc(context.Background())
+			err := closeFunc(context.Background()).Err()
 			assert.NoError(t, err)
 		}()
 
@@ -170,7 +170,7 @@ func TestAsyncLogger_Integration(t *testing.T) {
 			}),
 		)
 		defer func() {
-			err := closeFunc(context.Background())
+			err := closeFunc(context.Background()).Err()
 			assert.NoError(t, err)
 		}()
 
@@ -261,7 +261,7 @@ func TestAsyncLogger_Integration(t *testing.T) {
 		assert.NoError(t, err3)
 
 		// Close should flush all pending events
-		err := closeFunc(context.Background())
+		err := closeFunc(context.Background()).Err()
 		assert.NoError(t, err)
 
 		// Verify all events were processed
@@ -290,7 +290,7 @@ func TestAsyncLogger_Integration(t *testing.T) {
 
 		logger, closeFunc := NewAsyncLogger(mockBW, 100)
 		defer func() {
-			err := closeFunc(context.Background())
+			err := closeFunc(context.Background()).Err()
 			assert.NoError(t, err)
 		}()
 
@@ -335,7 +335,7 @@ func TestAsyncLogger_Integration(t *testing.T) {
 			}),
 		)
 		defer func() {
-			err := closeFunc(context.Background())
+			err := closeFunc(context.Background()).Err()
 			assert.NoError(t, err)
 		}()
 
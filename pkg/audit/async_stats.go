@@ -0,0 +1,51 @@
+package audit
+
+import "context"
+
+// AsyncStats is a snapshot of an AsyncWriter's buffer pressure, for metrics and alerting.
+type AsyncStats struct {
+	BufferLen         int   // Events currently queued
+	BufferCap         int   // Queue capacity, AsyncOptions.BufferSize
+	DroppedCount      int64 // Queued events whose batch write failed, including at shutdown
+	SyncFallbackCount int64 // Events written synchronously because the buffer was full
+	BatchesWritten    int64 // Batches successfully written by the background worker
+}
+
+// BufferFullHandler is called when the buffer is full and the event is about to be
+// written synchronously. It runs on the caller's goroutine, so keep it fast.
+type BufferFullHandler func(ctx context.Context, event Event)
+
+// Stats returns the current buffer and write counters. All values are read
+// atomically and without locks, so it's cheap to poll from a metrics goroutine.
+// Counters are read independently and may be momentarily inconsistent with each other.
+func (aw *AsyncWriter) Stats() AsyncStats {
+	return AsyncStats{
+		BufferLen:         len(aw.eventChan),
+		BufferCap:         cap(aw.eventChan),
+		DroppedCount:      aw.dropped.Load(),
+		SyncFallbackCount: aw.syncFallbacks.Load(),
+		BatchesWritten:    aw.batchesWritten.Load(),
+	}
+}
+
+// Stats returns the buffer pressure stats of a logger created by NewAsyncLogger.
+// Returns false for loggers whose writer isn't asynchronous.
+func (l *Logger) Stats() (AsyncStats, bool) {
+	aw, ok := l.writer.(*AsyncWriter)
+	if !ok {
+		return AsyncStats{}, false
+	}
+	return aw.Stats(), true
+}
+
+// WithOnBufferFull sets a handler called whenever the buffer of a logger created by
+// NewAsyncLogger is full and an event falls back to a synchronous write, e.g. to raise
+// an alert before latency suffers. Has no effect on other loggers.
+// Use AsyncOptions.OnBufferFull when creating an AsyncWriter directly.
+func WithOnBufferFull(handler BufferFullHandler) Option {
+	return func(l *Logger) {
+		if aw, ok := l.writer.(*AsyncWriter); ok {
+			aw.options.OnBufferFull = handler
+		}
+	}
+}
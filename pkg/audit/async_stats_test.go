@@ -0,0 +1,150 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// blockingBatchWriter blocks its first StoreBatch call until release is closed,
+// keeping the async worker busy so the buffer can fill up.
+type blockingBatchWriter struct {
+	calls   atomic.Int32
+	entered chan struct{}
+	release chan struct{}
+	err     error
+}
+
+func newBlockingBatchWriter() *blockingBatchWriter {
+	return &blockingBatchWriter{entered: make(chan struct{}), release: make(chan struct{})}
+}
+
+func (w *blockingBatchWriter) StoreBatch(ctx context.Context, _ []Event) error {
+	if w.calls.Add(1) == 1 {
+		close(w.entered)
+		select {
+		case <-w.release:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return w.err
+}
+
+func TestAsyncWriter_Stats(t *testing.T) {
+	t.Parallel()
+
+	t.Run("counts sync fallbacks and calls the handler", func(t *testing.T) {
+		t.Parallel()
+		bw := newBlockingBatchWriter()
+
+		var fullEvents []string
+		var mu sync.Mutex
+		aw, shutdown := NewAsyncWriter(bw, AsyncOptions{
+			BufferSize: 1,
+			BatchSize:  1,
+			OnBufferFull: func(_ context.Context, event Event) {
+				mu.Lock()
+				defer mu.Unlock()
+				fullEvents = append(fullEvents, event.ID)
+			},
+		})
+
+		ctx := context.Background()
+		var wg sync.WaitGroup
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, aw.Store(ctx, Event{ID: "1", Action: "a"}))
+		}()
+		<-bw.entered // Worker is blocked writing event 1
+
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, aw.Store(ctx, Event{ID: "2", Action: "a"}))
+		}()
+		require.Eventually(t, func() bool { return aw.Stats().BufferLen == 1 }, time.Second, time.Millisecond)
+
+		// Buffer is full, so this one is written synchronously
+		require.NoError(t, aw.Store(ctx, Event{ID: "3", Action: "a"}))
+
+		stats := aw.Stats()
+		assert.Equal(t, 1, stats.BufferCap)
+		assert.Equal(t, int64(1), stats.SyncFallbackCount)
+		mu.Lock()
+		assert.Equal(t, []string{"3"}, fullEvents)
+		mu.Unlock()
+
+		close(bw.release)
+		wg.Wait()
+
+		stats = aw.Stats()
+		assert.Equal(t, 0, stats.BufferLen)
+		assert.Equal(t, int64(2), stats.BatchesWritten)
+		assert.Equal(t, int64(0), stats.DroppedCount)
+
+		assert.Zero(t, shutdown(ctx).Dropped)
+	})
+
+	t.Run("counts dropped events", func(t *testing.T) {
+		t.Parallel()
+		bw := newBlockingBatchWriter()
+		bw.err = errors.New("storage down")
+		close(bw.release)
+
+		aw, shutdown := NewAsyncWriter(bw, AsyncOptions{BatchSize: 1})
+		ctx := context.Background()
+
+		require.Error(t, aw.Store(ctx, Event{ID: "1", Action: "a"}))
+		require.Error(t, aw.Store(ctx, Event{ID: "2", Action: "a"}))
+
+		stats := aw.Stats()
+		assert.Equal(t, int64(2), stats.DroppedCount)
+		assert.Equal(t, int64(0), stats.BatchesWritten)
+
+		shutdown(ctx)
+	})
+}
+
+func TestLogger_Stats(t *testing.T) {
+	t.Parallel()
+
+	t.Run("async logger", func(t *testing.T) {
+		t.Parallel()
+		bw := newBlockingBatchWriter()
+		close(bw.release)
+
+		var called atomic.Bool
+		logger, closeFunc := NewAsyncLogger(bw, 10, WithOnBufferFull(func(context.Context, Event) {
+			called.Store(true)
+		}))
+		defer closeFunc(context.Background())
+
+		require.NoError(t, logger.Log(context.Background(), "user.login"))
+
+		stats, ok := logger.Stats()
+		require.True(t, ok)
+		assert.Equal(t, 10, stats.BufferCap)
+		assert.Equal(t, int64(1), stats.BatchesWritten)
+		assert.False(t, called.Load())
+
+		aw, ok := logger.writer.(*AsyncWriter)
+		require.True(t, ok)
+		assert.NotNil(t, aw.options.OnBufferFull)
+	})
+
+	t.Run("sync logger", func(t *testing.T) {
+		t.Parallel()
+		logger := NewLogger(&MockWriter{}, WithOnBufferFull(func(context.Context, Event) {}))
+
+		stats, ok := logger.Stats()
+		assert.False(t, ok)
+		assert.Zero(t, stats)
+	})
+}
@@ -2,7 +2,10 @@ package audit
 
 import (
 	"context"
+	"errors"
+	"runtime"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -32,6 +35,9 @@ type AsyncOptions struct {
 	BatchSize      int           // Target events per batch - optimize based on storage bulk insert performance
 	BatchTimeout   time.Duration // Max time to wait for partial batches - controls worst-case latency
 	StorageTimeout time.Duration // Per-batch storage timeout - prevents hanging on slow/failed storage
+
+	// OnBufferFull is called before an event falls back to a synchronous write, optional
+	OnBufferFull BufferFullHandler
 }
 
 type AsyncWriter struct {
@@ -40,6 +46,37 @@ type AsyncWriter struct {
 	done        chan struct{}
 	wg          sync.WaitGroup
 	options     AsyncOptions
+
+	// Store counts itself in inflight before checking closed, and Shutdown waits for
+	// inflight to reach zero after setting closed, so no event is queued after the
+	// worker starts draining. Both are atomics to keep Store lock-free.
+	closed   atomic.Bool
+	inflight atomic.Int64
+
+	// Shutdown state: shutdownCtx is published to the worker by closing done
+	shutdownCtx context.Context
+	result      chan FlushResult
+	flushed     atomic.Int64
+	held        atomic.Int64 // Events taken off the queue by the worker and not yet known to be written
+
+	// Counters reported by Stats
+	dropped        atomic.Int64
+	syncFallbacks  atomic.Int64
+	batchesWritten atomic.Int64
+}
+
+// FlushResult reports how many buffered events were written during shutdown.
+// Dropped events were still buffered or being written when the shutdown context
+// expired, or failed their final write; alert on them, as audit events may be lost.
+type FlushResult struct {
+	Flushed int
+	Dropped int
+	err     error
+}
+
+// Err returns the context or storage error that caused events to be dropped.
+func (r FlushResult) Err() error {
+	return r.err
 }
 
 type eventBatch struct {
@@ -55,10 +92,12 @@ type batchWriter interface {
 	StoreBatch(ctx context.Context, events []Event) error
 }
 
+var _ batchWriter = (*AsyncWriter)(nil)
+
 // NewAsyncWriter creates an async writer that batches events for improved throughput.
 // Uses a background goroutine to collect events into batches, reducing storage I/O.
 // Only accepts BatchWriter since single-event writers would defeat the batching purpose.
-func NewAsyncWriter(bw batchWriter, opts AsyncOptions) (*AsyncWriter, func(context.Context) error) {
+func NewAsyncWriter(bw batchWriter, opts AsyncOptions) (*AsyncWriter, func(context.Context) FlushResult) {
 	if bw == nil {
 		panic("audit: batch writer cannot be nil")
 	}
@@ -82,39 +121,57 @@ func NewAsyncWriter(bw batchWriter, opts AsyncOptions) (*AsyncWriter, func(conte
 		eventChan:   make(chan eventBatch, opts.BufferSize),
 		done:        make(chan struct{}),
 		options:     opts,
+		result:      make(chan FlushResult, 1),
 	}
 
 	aw.wg.Add(1)
 	go aw.worker()
 
-	closeFunc := func(ctx context.Context) error {
-		return aw.Close(ctx)
-	}
-
-	return aw, closeFunc
+	return aw, aw.Shutdown
 }
 
 // Store implements Writer interface
 func (aw *AsyncWriter) Store(ctx context.Context, event Event) error {
+	return aw.StoreBatch(ctx, []Event{event})
+}
+
+// StoreBatch queues events as one unit, so Logger.LogBatch costs a single queue
+// slot and its events are always written together in one storage batch, which
+// may hold events of other callers too. It waits for that write like Store does.
+func (aw *AsyncWriter) StoreBatch(ctx context.Context, events []Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+
 	result := make(chan error, 1)
 
-	select {
-	case aw.eventChan <- eventBatch{ctx: ctx, events: []Event{event}, result: result}:
-		// Event queued successfully, wait for batch processing result
-		select {
-		case err := <-result:
-			return err
-		case <-ctx.Done():
-			return ctx.Err()
-		}
-	case <-ctx.Done():
-		return ctx.Err()
-	case <-aw.done:
+	aw.inflight.Add(1)
+	if aw.closed.Load() {
+		aw.inflight.Add(-1)
 		return ErrStorageNotAvailable
+	}
+	select {
+	case aw.eventChan <- eventBatch{ctx: ctx, events: events, result: result}:
+		aw.inflight.Add(-1)
 	default:
+		aw.inflight.Add(-1)
 		// Buffer full - bypass async processing to prevent event loss
 		// This maintains audit completeness at the cost of synchronous I/O
-		return aw.batchWriter.StoreBatch(ctx, []Event{event})
+		aw.syncFallbacks.Add(1)
+		if aw.options.OnBufferFull != nil {
+			for _, event := range events {
+				aw.options.OnBufferFull(ctx, event)
+			}
+		}
+		return aw.batchWriter.StoreBatch(ctx, events)
+	}
+
+	// Events queued successfully, wait for batch processing result
+	select {
+	case err := <-result:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
 	}
 }
 
@@ -125,7 +182,8 @@ func (aw *AsyncWriter) worker() {
 	batchTimer := time.NewTicker(aw.options.BatchTimeout)
 	defer batchTimer.Stop()
 
-	pendingResults := make([]chan error, 0, aw.options.BatchSize)
+	// Queued batches whose events are in batchEvents, kept for their result channels
+	pending := make([]eventBatch, 0, aw.options.BatchSize)
 
 	// flushBatch writes accumulated events to storage and notifies all waiting callers.
 	// Uses background context to prevent client timeouts from cascading to storage operations.
@@ -139,34 +197,31 @@ func (aw *AsyncWriter) worker() {
 		defer cancel()
 
 		err := aw.batchWriter.StoreBatch(ctx, batchEvents)
+		aw.recordBatch(len(batchEvents), err)
+		aw.held.Add(-int64(len(batchEvents)))
 
 		// Notify all requests in this batch of the storage result
-		for _, resultChan := range pendingResults {
-			select {
-			case resultChan <- err:
-			default:
-				// Channel closed due to client timeout - safe to ignore
-			}
-		}
+		notifyResults(pending, err)
 
 		// Reset batch collectors for next iteration
 		// clear() zeroes the slice elements to help garbage collection reclaim memory
 		// from any references held by the slice elements (e.g., event.Metadata maps).
 		// This is particularly important for long-running processes to prevent memory leaks.
 		clear(batchEvents)
-		clear(pendingResults)
+		clear(pending)
 		// Truncate slices to zero length while preserving their underlying capacity.
 		// This allows reuse of the already-allocated arrays for the next batch,
 		// avoiding repeated allocations and improving performance.
 		batchEvents = batchEvents[:0]
-		pendingResults = pendingResults[:0]
+		pending = pending[:0]
 	}
 
 	for {
 		select {
 		case batch := <-aw.eventChan:
+			aw.held.Add(int64(len(batch.events)))
 			batchEvents = append(batchEvents, batch.events...)
-			pendingResults = append(pendingResults, batch.result)
+			pending = append(pending, batch)
 
 			// Flush when batch reaches target size for optimal database performance
 			if len(batchEvents) >= aw.options.BatchSize {
@@ -179,37 +234,132 @@ func (aw *AsyncWriter) worker() {
 
 		case <-aw.done:
 			// Graceful shutdown: drain remaining events to prevent data loss
-			close(aw.eventChan)
-			for batch := range aw.eventChan {
-				batchEvents = append(batchEvents, batch.events...)
-				pendingResults = append(pendingResults, batch.result)
-			}
-			flushBatch()
+			aw.result <- aw.drain(aw.shutdownCtx, pending)
 			return
 		}
 	}
 }
 
+// drain writes everything still buffered within the shutdown context's deadline.
+// The channel is not closed, so a late Store can't panic on send.
+// Queued batches are never split, so each caller gets the result of the one write
+// holding all of its events; a batch larger than BatchSize is written on its own.
+func (aw *AsyncWriter) drain(ctx context.Context, pending []eventBatch) FlushResult {
+	for collecting := true; collecting; {
+		select {
+		case batch := <-aw.eventChan:
+			aw.held.Add(int64(len(batch.events)))
+			pending = append(pending, batch)
+		default:
+			collecting = false
+		}
+	}
+
+	var res FlushResult
+	for len(pending) > 0 && ctx.Err() == nil {
+		// Not enough time left for several round trips: make one final attempt with everything
+		final := false
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < aw.options.StorageTimeout {
+			final = true
+		}
+
+		var events []Event
+		n := 0
+		for n < len(pending) && (final || n == 0 || len(events)+len(pending[n].events) <= aw.options.BatchSize) {
+			events = append(events, pending[n].events...)
+			n++
+		}
+
+		storeCtx, cancel := context.WithTimeout(ctx, aw.options.StorageTimeout)
+		err := aw.batchWriter.StoreBatch(storeCtx, events)
+		cancel()
+		aw.recordBatch(len(events), err)
+
+		notifyResults(pending[:n], err)
+		if err != nil {
+			res.Dropped += len(events)
+			res.err = errors.Join(res.err, err)
+		} else {
+			res.Flushed += len(events)
+			aw.flushed.Add(int64(len(events)))
+			aw.held.Add(-int64(len(events)))
+		}
+
+		pending = pending[n:]
+	}
+
+	if len(pending) > 0 {
+		notifyResults(pending, ctx.Err())
+		left := countEvents(pending)
+		aw.dropped.Add(int64(left))
+		res.Dropped += left
+		res.err = errors.Join(res.err, ctx.Err())
+	}
+
+	return res
+}
+
+// recordBatch updates the Stats counters after a batch write of n events.
+func (aw *AsyncWriter) recordBatch(n int, err error) {
+	if err != nil {
+		aw.dropped.Add(int64(n))
+		return
+	}
+	aw.batchesWritten.Add(1)
+}
+
+// notifyResults sends err to the callers waiting on batches.
+// Callers that gave up are skipped; their channels are buffered, so sends never block.
+func notifyResults(batches []eventBatch, err error) {
+	for _, batch := range batches {
+		select {
+		case batch.result <- err:
+		default:
+		}
+	}
+}
+
+func countEvents(batches []eventBatch) int {
+	n := 0
+	for _, batch := range batches {
+		n += len(batch.events)
+	}
+	return n
+}
+
 // Close gracefully shuts down the async writer, ensuring no events are lost.
 // The context controls shutdown timeout - if exceeded, some events may remain unflushed.
 // Always call this during application shutdown to prevent audit event loss.
+// Use Shutdown to find out how many events were dropped.
 func (aw *AsyncWriter) Close(ctx context.Context) error {
-	// Signal shutdown
-	close(aw.done)
+	return aw.Shutdown(ctx).Err()
+}
 
-	// Race between graceful shutdown completion and context timeout
-	doneChan := make(chan struct{})
-	go func() {
-		aw.wg.Wait()
-		close(doneChan)
-	}()
+// Shutdown stops the writer and flushes buffered events in batches bounded by ctx.
+// When the remaining time gets shorter than StorageTimeout, everything left is
+// written in one final synchronous batch. Events that still couldn't be written
+// are reported as Dropped. Must be called only once.
+func (aw *AsyncWriter) Shutdown(ctx context.Context) FlushResult {
+	// Once closed is set and in-flight calls are done no Store can queue,
+	// so drain sees every accepted event. Queueing never blocks, so this is short.
+	aw.closed.Store(true)
+	for aw.inflight.Load() > 0 {
+		runtime.Gosched()
+	}
+	aw.shutdownCtx = ctx
+	close(aw.done)
 
 	select {
-	case <-doneChan:
-		// All events successfully flushed
-		return nil
+	case res := <-aw.result:
+		aw.wg.Wait()
+		return res
 	case <-ctx.Done():
-		// Shutdown timeout exceeded - some events may be lost
-		return ctx.Err()
+		// A storage call ignores the deadline; report what's known so far.
+		// Events of a write still running are counted in held, as they may be lost.
+		return FlushResult{
+			Flushed: int(aw.flushed.Load()),
+			Dropped: int(aw.held.Load()) + len(aw.eventChan),
+			err:     ctx.Err(),
+		}
 	}
 }
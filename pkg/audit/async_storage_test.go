@@ -3,6 +3,7 @@ package audit
 import (
 	"context"
 	"errors"
+	"slices"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -10,6 +11,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 // MockBatchWriter implements the batchWriter interface for testing
@@ -57,7 +59,7 @@ func TestNewAsyncWriter(t *testing.T) {
 		assert.Equal(t, 5*time.Second, writer.options.StorageTimeout)
 
 		// Clean up
-		err := closeFunc(context.Background())
+		err := closeFunc(context.Background()).Err()
 		assert.NoError(t, err)
 	})
 
@@ -79,7 +81,7 @@ func TestNewAsyncWriter(t *testing.T) {
 		assert.Equal(t, 2*time.Second, writer.options.StorageTimeout)
 
 		// Clean up
-		err := closeFunc(context.Background())
+		err := closeFunc(context.Background()).Err()
 		assert.NoError(t, err)
 	})
 
@@ -102,7 +104,7 @@ func TestNewAsyncWriter(t *testing.T) {
 		// We can't directly test internal state without exposing it
 
 		// Clean up
-		err := closeFunc(context.Background())
+		err := closeFunc(context.Background()).Err()
 		assert.NoError(t, err)
 	})
 }
@@ -121,7 +123,7 @@ func TestAsyncWriter_Store(t *testing.T) {
 			BufferSize: 10,
 		})
 		defer func() {
-			err := closeFunc(context.Background())
+			err := closeFunc(context.Background()).Err()
 			assert.NoError(t, err)
 		}()
 
@@ -153,7 +155,7 @@ func TestAsyncWriter_Store(t *testing.T) {
 
 		writer, closeFunc := NewAsyncWriter(mockBW, opts)
 		defer func() {
-			err := closeFunc(context.Background())
+			err := closeFunc(context.Background()).Err()
 			assert.NoError(t, err)
 		}()
 
@@ -188,7 +190,7 @@ func TestAsyncWriter_Store(t *testing.T) {
 
 		writer, closeFunc := NewAsyncWriter(mockBW, opts)
 		defer func() {
-			err := closeFunc(context.Background())
+			err := closeFunc(context.Background()).Err()
 			assert.NoError(t, err)
 		}()
 
@@ -227,7 +229,7 @@ func TestAsyncWriter_Store(t *testing.T) {
 
 		writer, closeFunc := NewAsyncWriter(mockBW, opts)
 		defer func() {
-			err := closeFunc(context.Background())
+			err := closeFunc(context.Background()).Err()
 			assert.NoError(t, err)
 		}()
 
@@ -257,7 +259,7 @@ func TestAsyncWriter_Store(t *testing.T) {
 			BufferSize: 10,
 		})
 		defer func() {
-			err := closeFunc(context.Background())
+			err := closeFunc(context.Background()).Err()
 			assert.NoError(t, err)
 		}()
 
@@ -281,7 +283,7 @@ func TestAsyncWriter_Store(t *testing.T) {
 			BufferSize: 10,
 		})
 		defer func() {
-			err := closeFunc(context.Background())
+			err := closeFunc(context.Background()).Err()
 			assert.NoError(t, err)
 		}()
 
@@ -301,7 +303,7 @@ func TestAsyncWriter_Store(t *testing.T) {
 		})
 
 		// Close the writer
-		err := closeFunc(context.Background())
+		err := closeFunc(context.Background()).Err()
 		assert.NoError(t, err)
 
 		// Try to store after closing - this may panic or return ErrStorageNotAvailable
@@ -324,6 +326,109 @@ func TestAsyncWriter_Store(t *testing.T) {
 	})
 }
 
+func TestAsyncWriter_StoreBatch(t *testing.T) {
+	t.Parallel()
+
+	// countBulk records how many "bulk" events each StoreBatch call received;
+	// the worker reuses its buffer, so events are counted during the call
+	countBulk := func(mockBW *MockBatchWriter) func() []int {
+		var (
+			mu     sync.Mutex
+			counts []int
+		)
+		mockBW.On("StoreBatch", mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+			n := 0
+			for _, event := range args.Get(1).([]Event) {
+				if event.Action == "bulk" {
+					n++
+				}
+			}
+			mu.Lock()
+			counts = append(counts, n)
+			mu.Unlock()
+		})
+		return func() []int {
+			mu.Lock()
+			defer mu.Unlock()
+			return slices.Clone(counts)
+		}
+	}
+
+	t.Run("LogBatch is written in a single storage batch", func(t *testing.T) {
+		t.Parallel()
+		mockBW := &MockBatchWriter{}
+		bulkCounts := countBulk(mockBW)
+
+		writer, cleanup := NewAsyncWriter(mockBW, AsyncOptions{
+			BatchSize:    2,
+			BatchTimeout: time.Hour,
+		})
+		defer cleanup(context.Background())
+
+		logger := NewLogger(writer)
+		err := logger.LogBatch(context.Background(), []BatchEntry{
+			{Action: "bulk"}, {Action: "bulk"}, {Action: "bulk"},
+		})
+		require.NoError(t, err)
+
+		mockBW.AssertNumberOfCalls(t, "StoreBatch", 1)
+		assert.Equal(t, []int{3}, bulkCounts())
+	})
+
+	t.Run("queued batches are not split on shutdown", func(t *testing.T) {
+		t.Parallel()
+		mockBW := &MockBatchWriter{storeDelay: 100 * time.Millisecond}
+		bulkCounts := countBulk(mockBW)
+
+		writer, cleanup := NewAsyncWriter(mockBW, AsyncOptions{
+			BatchSize:      2,
+			BatchTimeout:   time.Hour,
+			StorageTimeout: time.Second,
+		})
+
+		// Two single events keep the worker busy while the rest queues up
+		var wg sync.WaitGroup
+		errs := make(chan error, 4)
+		for _, events := range [][]Event{
+			{{Action: "single"}},
+			{{Action: "single"}},
+			{{Action: "bulk"}, {Action: "bulk"}, {Action: "bulk"}},
+			{{Action: "single"}},
+		} {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				errs <- writer.StoreBatch(context.Background(), events)
+			}()
+			time.Sleep(10 * time.Millisecond)
+		}
+
+		res := cleanup(context.Background())
+		wg.Wait()
+		close(errs)
+
+		require.NoError(t, res.Err())
+		for err := range errs {
+			assert.NoError(t, err)
+		}
+		assert.Contains(t, bulkCounts(), 3)
+		for _, n := range bulkCounts() {
+			assert.Contains(t, []int{0, 3}, n, "a queued batch must be written in one call")
+		}
+	})
+
+	t.Run("empty batch is a no-op", func(t *testing.T) {
+		t.Parallel()
+		mockBW := &MockBatchWriter{}
+
+		writer, cleanup := NewAsyncWriter(mockBW, AsyncOptions{})
+		defer cleanup(context.Background())
+
+		require.NoError(t, writer.StoreBatch(context.Background(), nil))
+		mockBW.AssertNotCalled(t, "StoreBatch", mock.Anything, mock.Anything)
+	})
+}
+
 func TestAsyncWriter_Close(t *testing.T) {
 	t.Parallel()
 
@@ -333,7 +438,7 @@ func TestAsyncWriter_Close(t *testing.T) {
 
 		_, closeFunc := NewAsyncWriter(mockBW, AsyncOptions{})
 
-		err := closeFunc(context.Background())
+		err := closeFunc(context.Background()).Err()
 		assert.NoError(t, err)
 	})
 
@@ -362,7 +467,7 @@ func TestAsyncWriter_Close(t *testing.T) {
 		}
 
 		// Close should flush the pending events
-		err := closeFunc(context.Background())
+		err := closeFunc(context.Background()).Err()
 		assert.NoError(t, err)
 
 		// Verify all events were processed
@@ -403,7 +508,7 @@ func TestAsyncWriter_Close(t *testing.T) {
 		defer cancel()
 
 		start := time.Now()
-		err = closeFunc(ctx)
+		err = closeFunc(ctx).Err()
 		elapsed := time.Since(start)
 
 		// Should succeed because worker should be done
@@ -424,14 +529,153 @@ func TestAsyncWriter_Close(t *testing.T) {
 		_, closeFunc := NewAsyncWriter(mockBW, AsyncOptions{})
 
 		// First close should succeed
-		err := closeFunc(context.Background())
+		err := closeFunc(context.Background()).Err()
 		assert.NoError(t, err)
 
 		// Second close should panic due to closing closed channel
 		// This documents current behavior - in production, close should only be called once
 		assert.Panics(t, func() {
-			closeFunc(context.Background())
+			closeFunc(context.Background()).Err()
+		})
+	})
+}
+
+func TestAsyncWriter_Shutdown(t *testing.T) {
+	t.Parallel()
+
+	// queueEvents stores events from background goroutines and waits until the worker picked them up
+	queueEvents := func(writer *AsyncWriter, n int) *sync.WaitGroup {
+		var wg sync.WaitGroup
+		for range n {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_ = writer.Store(context.Background(), Event{Action: "shutdown.test"})
+			}()
+		}
+		time.Sleep(50 * time.Millisecond)
+		return &wg
+	}
+
+	t.Run("flushes buffered events in batches", func(t *testing.T) {
+		t.Parallel()
+		mockBW := &MockBatchWriter{}
+		mockBW.On("StoreBatch", mock.Anything, mock.Anything).Return(nil)
+
+		writer, cleanup := NewAsyncWriter(mockBW, AsyncOptions{
+			BatchSize:    2,
+			BatchTimeout: time.Hour,
+		})
+		stores := queueEvents(writer, 5)
+
+		// Batch size is reached while queuing, so only the tail is left for shutdown
+		res := cleanup(context.Background())
+		stores.Wait()
+
+		assert.NoError(t, res.Err())
+		assert.Equal(t, 1, res.Flushed)
+		assert.Zero(t, res.Dropped)
+	})
+
+	t.Run("reports dropped events on storage error", func(t *testing.T) {
+		t.Parallel()
+		storageErr := errors.New("database down")
+		mockBW := &MockBatchWriter{}
+		mockBW.On("StoreBatch", mock.Anything, mock.Anything).Return(storageErr)
+
+		writer, cleanup := NewAsyncWriter(mockBW, AsyncOptions{
+			BatchSize:    100,
+			BatchTimeout: time.Hour,
+		})
+		stores := queueEvents(writer, 5)
+
+		res := cleanup(context.Background())
+		stores.Wait()
+
+		assert.ErrorIs(t, res.Err(), storageErr)
+		assert.Zero(t, res.Flushed)
+		assert.Equal(t, 5, res.Dropped)
+	})
+
+	t.Run("writes everything in one batch when deadline is near", func(t *testing.T) {
+		t.Parallel()
+		mockBW := &MockBatchWriter{}
+		mockBW.On("StoreBatch", mock.Anything, mock.Anything).Return(nil)
+
+		writer, cleanup := NewAsyncWriter(mockBW, AsyncOptions{
+			BatchSize:      100,
+			BatchTimeout:   time.Hour,
+			StorageTimeout: time.Second,
+		})
+		stores := queueEvents(writer, 5)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+		defer cancel()
+
+		res := cleanup(ctx)
+		stores.Wait()
+
+		assert.NoError(t, res.Err())
+		assert.Equal(t, 5, res.Flushed)
+		mockBW.AssertNumberOfCalls(t, "StoreBatch", 1)
+		assert.Len(t, mockBW.Calls[0].Arguments.Get(1), 5)
+	})
+
+	t.Run("reports dropped events when deadline expires", func(t *testing.T) {
+		t.Parallel()
+		mockBW := &MockBatchWriter{storeDelay: time.Second}
+		mockBW.On("StoreBatch", mock.Anything, mock.Anything).Return(nil)
+
+		writer, cleanup := NewAsyncWriter(mockBW, AsyncOptions{
+			BatchSize:      100,
+			BatchTimeout:   time.Hour,
+			StorageTimeout: time.Second,
+		})
+		stores := queueEvents(writer, 5)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+		defer cancel()
+
+		res := cleanup(ctx)
+		stores.Wait()
+
+		assert.ErrorIs(t, res.Err(), context.DeadlineExceeded)
+		assert.Zero(t, res.Flushed)
+		assert.Equal(t, 5, res.Dropped)
+	})
+
+	t.Run("counts events of a running write as dropped when deadline expires", func(t *testing.T) {
+		t.Parallel()
+		mockBW := &MockBatchWriter{storeDelay: time.Second}
+		mockBW.On("StoreBatch", mock.Anything, mock.Anything).Return(nil)
+
+		writer, cleanup := NewAsyncWriter(mockBW, AsyncOptions{
+			BatchSize:    2,
+			BatchTimeout: time.Hour,
 		})
+		// The first two events are in a regular flush when shutdown starts, the third is queued
+		stores := queueEvents(writer, 3)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+		defer cancel()
+
+		res := cleanup(ctx)
+		stores.Wait()
+
+		assert.ErrorIs(t, res.Err(), context.DeadlineExceeded)
+		assert.Zero(t, res.Flushed)
+		assert.Equal(t, 3, res.Dropped)
+	})
+
+	t.Run("rejects events after shutdown", func(t *testing.T) {
+		t.Parallel()
+		mockBW := &MockBatchWriter{}
+
+		writer, cleanup := NewAsyncWriter(mockBW, AsyncOptions{})
+		require.NoError(t, cleanup(context.Background()).Err())
+
+		err := writer.Store(context.Background(), Event{Action: "late.event"})
+		assert.ErrorIs(t, err, ErrStorageNotAvailable)
 	})
 }
 
@@ -450,7 +694,7 @@ func TestAsyncWriter_ConcurrentOperations(t *testing.T) {
 			BatchTimeout: 50 * time.Millisecond,
 		})
 		defer func() {
-			err := closeFunc(context.Background())
+			err := closeFunc(context.Background()).Err()
 			assert.NoError(t, err)
 		}()
 
@@ -492,6 +736,43 @@ func TestAsyncWriter_ConcurrentOperations(t *testing.T) {
 		assert.True(t, mockBW.GetCallCount() > 0)
 	})
 
+	t.Run("events accepted during shutdown are flushed", func(t *testing.T) {
+		t.Parallel()
+		var written atomic.Int64
+		mockBW := &MockBatchWriter{}
+		mockBW.On("StoreBatch", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			written.Add(int64(len(args.Get(1).([]Event))))
+		}).Return(nil)
+
+		writer, shutdown := NewAsyncWriter(mockBW, AsyncOptions{BufferSize: 1000, BatchTimeout: time.Hour})
+
+		var accepted atomic.Int64
+		var wg sync.WaitGroup
+		for range 50 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for range 20 {
+					// A stranded event would wait for its result until this deadline
+					ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+					err := writer.Store(ctx, Event{Action: "race.test"})
+					cancel()
+					if err == nil {
+						accepted.Add(1)
+						continue
+					}
+					assert.ErrorIs(t, err, ErrStorageNotAvailable)
+				}
+			}()
+		}
+
+		res := shutdown(context.Background())
+		wg.Wait()
+
+		require.NoError(t, res.Err())
+		assert.Equal(t, accepted.Load(), written.Load())
+	})
+
 	t.Run("handles Store during Close", func(t *testing.T) {
 		t.Parallel()
 		mockBW := &MockBatchWriter{}
@@ -539,7 +820,7 @@ func TestAsyncWriter_ConcurrentOperations(t *testing.T) {
 		// Close after a short delay
 		time.Sleep(10 * time.Millisecond)
 		close(done) // Signal goroutine to stop
-		err := closeFunc(context.Background())
+		err := closeFunc(context.Background()).Err()
 		assert.NoError(t, err)
 
 		wg.Wait()
@@ -561,7 +842,7 @@ func TestAsyncWriter_ErrorHandling(t *testing.T) {
 			BatchTimeout: 1 * time.Second,
 		})
 		defer func() {
-			err := closeFunc(context.Background())
+			err := closeFunc(context.Background()).Err()
 			assert.NoError(t, err)
 		}()
 
@@ -653,7 +934,7 @@ func TestAsyncWriter_Integration(t *testing.T) {
 
 		writer, closeFunc := NewAsyncWriter(mockBW, opts)
 		defer func() {
-			err := closeFunc(context.Background())
+			err := closeFunc(context.Background()).Err()
 			assert.NoError(t, err)
 		}()
 
@@ -12,6 +12,8 @@
 //   - AsyncLogger: High-throughput asynchronous logger with batching
 //   - Writer interfaces: Pluggable storage backends (writer, batchWriter)
 //   - MetadataFilter: Configurable PII and sensitive data filtering system
+//   - FileWriter: Rotating JSONL file storage for deployments without a database
+//   - Reader: Query interface for reading events back with EventFilter
 //   - AsyncOptions: Configuration for batching and buffering behavior
 //   - Result constants: Standard result values (ResultSuccess, ResultFailure, ResultError)
 //
@@ -65,6 +67,16 @@
 //		audit.WithMetadata("failure_reason", "invalid_password"),
 //	)
 //
+// LogBatch records many events sharing a context, e.g. from a bulk import, running
+// context extractors once and using StoreBatch when the writer implements it.
+// AsyncWriter does, and keeps the batch together in a single storage write.
+// The returned error joins a *BatchEntryError for each entry that wasn't stored:
+//
+//	err := logger.LogBatch(ctx, []audit.BatchEntry{
+//		{Action: "user.import", Options: []audit.EventOption{audit.WithResource("user", "1")}},
+//		{Action: "user.import", Err: importErr},
+//	})
+//
 // # High-Throughput Scenarios
 //
 // For applications with high audit event volumes, use AsyncLogger with batch processing:
@@ -103,7 +115,15 @@
 //	// Create async logger with cleanup function
 //	batchWriter := &BatchDatabaseWriter{db: db}
 //	logger, cleanup := audit.NewAsyncLogger(batchWriter, 1000, /* options */)
-//	defer cleanup(context.Background()) // Always call during shutdown
+//
+//	// Always call during shutdown; buffered events are flushed within the deadline
+//	defer func() {
+//		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+//		defer cancel()
+//		if res := cleanup(ctx); res.Dropped > 0 {
+//			slog.Error("audit events lost on shutdown", "dropped", res.Dropped, "error", res.Err())
+//		}
+//	}()
 //
 //	// Usage remains the same as synchronous logger
 //	err := logger.Log(ctx, "data.export",
@@ -111,6 +131,19 @@
 //		audit.WithMetadata("export_format", "csv"),
 //	)
 //
+// When the buffer is full, events fall back to synchronous writes. Logger.Stats
+// reports buffer length and capacity along with dropped, sync fallback and
+// written batch counters; it reads atomics only, so it's cheap to poll for
+// metrics. WithOnBufferFull sets a handler called on each fallback, e.g. to
+// raise an alert:
+//
+//	logger, cleanup := audit.NewAsyncLogger(batchWriter, 1000,
+//		audit.WithOnBufferFull(func(ctx context.Context, event audit.Event) {
+//			slog.WarnContext(ctx, "audit buffer full", "action", event.Action)
+//		}),
+//	)
+//	stats, _ := logger.Stats()
+//
 // # Context Integration
 //
 // The package integrates seamlessly with Go's context.Context to automatically
@@ -234,6 +267,10 @@
 // Default PII fields include passwords, tokens, SSNs, credit cards, and other
 // sensitive data commonly found in application logs.
 //
+// Rules match keys at any depth: nested map[string]any and []any values are
+// filtered recursively, so {"user": {"ssn": "..."}} has its SSN masked. Containers
+// nested deeper than WithMaxDepth (default 10) are replaced with MaxDepthExceeded.
+//
 // # Error Handling
 //
 // The package provides structured error handling for different failure scenarios:
@@ -283,6 +320,46 @@
 //		StorageTimeout: 2 * time.Second,   // Quick storage operations
 //	})
 //
+// # File Storage
+//
+// FileWriter appends each event as one JSON line to a local file, for small
+// deployments without a database. It implements both writer interfaces, rotates
+// the file when it would exceed MaxSize (keeping MaxBackups old files as path.1,
+// path.2, ...) and fsyncs every SyncInterval. An incomplete last line left by a
+// crash is truncated on reopen:
+//
+//	w, err := audit.NewFileWriter("/var/log/app/audit.jsonl",
+//		audit.WithMaxSize(50<<20),
+//		audit.WithMaxBackups(10),
+//		audit.WithSyncInterval(time.Second),
+//	)
+//	if err != nil {
+//		return err
+//	}
+//	defer w.Close()
+//
+//	logger, cleanup := audit.NewAsyncLogger(w, 1000)
+//
+// # Querying Events
+//
+// A Reader reads events back for compliance exports. NewReader wraps a query
+// function that applies the EventFilter in storage; it validates the filter and
+// returns events ordered by CreatedAt, then ID, so pagination with Limit and
+// Offset is stable. WithReader enables Logger.Query, also on loggers created
+// by NewAsyncLogger:
+//
+//	reader := audit.NewReader(queryAuditEvents) // ORDER BY created_at, id
+//	logger := audit.NewLogger(writer, audit.WithReader(reader))
+//
+//	events, err := logger.Query(ctx, audit.EventFilter{
+//		TenantID:     tenantID,
+//		ActionPrefix: "user.",
+//		From:         start,
+//		To:           end,
+//		Limit:        100,
+//		Offset:       200,
+//	})
+//
 // # Compliance and Security
 //
 // The audit system is designed with compliance and security requirements in mind:
@@ -8,4 +8,7 @@ var (
 	ErrEventValidation     = errors.New("audit: event validation failed")
 	ErrStorageTimeout      = errors.New("audit: storage operation timed out")
 	ErrBufferFull          = errors.New("audit: async buffer is full")
+	ErrInvalidFilter       = errors.New("audit: invalid event filter")
+	ErrQueryFailed         = errors.New("audit: failed to query events")
+	ErrReaderNotConfigured = errors.New("audit: reader is not configured")
 )
@@ -0,0 +1,333 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Default configuration values for FileWriter
+const (
+	// DefaultFileMaxSize is the size in bytes after which the file is rotated
+	DefaultFileMaxSize = 100 * 1024 * 1024
+
+	// DefaultFileMaxBackups is the number of rotated files kept
+	DefaultFileMaxBackups = 5
+
+	// DefaultFileSyncInterval is how often written events are flushed to disk
+	DefaultFileSyncInterval = time.Second
+)
+
+// FileWriter appends audit events to a JSONL file, one JSON-encoded Event per line.
+// The file is rotated when it would exceed MaxSize: path is renamed to path.1, older
+// backups shift to path.2 and so on, and backups beyond MaxBackups are removed.
+// It implements both the writer and batchWriter interfaces, so it works with
+// NewLogger and NewAsyncLogger. Safe for concurrent use.
+//
+// Events are written with one write call per Store or StoreBatch and fsynced every
+// SyncInterval, so a crash can lose at most the last interval of events. A line left
+// incomplete by a crash is truncated when the file is reopened.
+type FileWriter struct {
+	path         string
+	maxSize      int64
+	maxBackups   int
+	syncInterval time.Duration
+
+	mu      sync.Mutex
+	file    *os.File
+	size    int64
+	dirty   bool
+	syncErr error
+	closed  bool
+
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+// FileWriterOption configures a FileWriter.
+type FileWriterOption func(*FileWriter)
+
+// WithMaxSize sets the file size in bytes that triggers rotation.
+// Default is DefaultFileMaxSize. A batch larger than the limit is still written to a fresh file.
+func WithMaxSize(size int64) FileWriterOption {
+	return func(w *FileWriter) {
+		if size > 0 {
+			w.maxSize = size
+		}
+	}
+}
+
+// WithMaxBackups sets how many rotated files are kept. Default is DefaultFileMaxBackups;
+// 0 removes the file on rotation.
+func WithMaxBackups(n int) FileWriterOption {
+	return func(w *FileWriter) {
+		if n >= 0 {
+			w.maxBackups = n
+		}
+	}
+}
+
+// WithSyncInterval sets how often written events are fsynced. Default is
+// DefaultFileSyncInterval; 0 fsyncs on every write, trading throughput for durability.
+func WithSyncInterval(d time.Duration) FileWriterOption {
+	return func(w *FileWriter) {
+		if d >= 0 {
+			w.syncInterval = d
+		}
+	}
+}
+
+// NewFileWriter opens or creates the JSONL file at path, creating missing directories.
+// Files are created with 0600 permissions, as audit events may contain personal data.
+// Call Close during shutdown to flush the last events to disk.
+func NewFileWriter(path string, opts ...FileWriterOption) (*FileWriter, error) {
+	w := &FileWriter{
+		path:         path,
+		maxSize:      DefaultFileMaxSize,
+		maxBackups:   DefaultFileMaxBackups,
+		syncInterval: DefaultFileSyncInterval,
+		done:         make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(w)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, errors.Join(ErrStorageNotAvailable, err)
+	}
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+
+	if w.syncInterval > 0 {
+		w.wg.Add(1)
+		go w.syncLoop()
+	}
+
+	return w, nil
+}
+
+// Store appends a single event.
+func (w *FileWriter) Store(ctx context.Context, event Event) error {
+	return w.StoreBatch(ctx, []Event{event})
+}
+
+// StoreBatch appends the events with a single write, so a batch is never split
+// between files. Returns ErrStorageNotAvailable after Close or on I/O errors.
+func (w *FileWriter) StoreBatch(ctx context.Context, events []Event) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if len(events) == 0 {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for i := range events {
+		// Encode appends the newline that terminates each line
+		if err := enc.Encode(&events[i]); err != nil {
+			return errors.Join(ErrInvalidEvent, err)
+		}
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return ErrStorageNotAvailable
+	}
+	if err := w.syncErr; err != nil {
+		w.syncErr = nil
+		return errors.Join(ErrStorageNotAvailable, err)
+	}
+
+	if w.size > 0 && w.size+int64(buf.Len()) > w.maxSize {
+		if err := w.rotate(); err != nil {
+			return err
+		}
+	}
+
+	n, err := w.file.Write(buf.Bytes())
+	w.size += int64(n)
+	w.dirty = true
+	if err != nil {
+		return errors.Join(ErrStorageNotAvailable, err)
+	}
+
+	if w.syncInterval == 0 {
+		if err := w.sync(); err != nil {
+			return errors.Join(ErrStorageNotAvailable, err)
+		}
+	}
+	return nil
+}
+
+// Sync flushes written events to disk.
+func (w *FileWriter) Sync() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return ErrStorageNotAvailable
+	}
+	if err := w.sync(); err != nil {
+		return errors.Join(ErrStorageNotAvailable, err)
+	}
+	return nil
+}
+
+// Close flushes written events to disk and closes the file.
+// It is safe to call Close multiple times.
+func (w *FileWriter) Close() error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return nil
+	}
+	w.closed = true
+	close(w.done)
+
+	err := errors.Join(w.syncErr, w.sync(), w.file.Close())
+	w.mu.Unlock()
+
+	w.wg.Wait()
+	if err != nil {
+		return errors.Join(ErrStorageNotAvailable, err)
+	}
+	return nil
+}
+
+func (w *FileWriter) syncLoop() {
+	defer w.wg.Done()
+
+	ticker := time.NewTicker(w.syncInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			w.mu.Lock()
+			if !w.closed {
+				// Reported by the next Store, since there's no caller to return it to
+				if err := w.sync(); err != nil && w.syncErr == nil {
+					w.syncErr = err
+				}
+			}
+			w.mu.Unlock()
+		case <-w.done:
+			return
+		}
+	}
+}
+
+// sync fsyncs the file if anything was written since the last sync. Requires w.mu.
+func (w *FileWriter) sync() error {
+	if !w.dirty {
+		return nil
+	}
+	if err := w.file.Sync(); err != nil {
+		return err
+	}
+	w.dirty = false
+	return nil
+}
+
+// open opens the file for appending, truncating an incomplete last line. Requires w.mu or exclusive access.
+func (w *FileWriter) open() error {
+	f, err := os.OpenFile(w.path, os.O_RDWR|os.O_CREATE, 0o600)
+	if err != nil {
+		return errors.Join(ErrStorageNotAvailable, err)
+	}
+
+	size, err := completeLinesSize(f)
+	if err == nil {
+		err = f.Truncate(size)
+	}
+	if err == nil {
+		_, err = f.Seek(size, io.SeekStart)
+	}
+	if err != nil {
+		_ = f.Close()
+		return errors.Join(ErrStorageNotAvailable, err)
+	}
+
+	w.file = f
+	w.size = size
+	return nil
+}
+
+// rotate closes the current file, shifts backups and opens a new file. Requires w.mu.
+func (w *FileWriter) rotate() error {
+	if err := errors.Join(w.sync(), w.file.Close()); err != nil {
+		return errors.Join(ErrStorageNotAvailable, err)
+	}
+
+	if err := w.shiftBackups(); err != nil {
+		// Keep writing to the current file rather than losing events
+		if openErr := w.open(); openErr != nil {
+			return errors.Join(err, openErr)
+		}
+		return err
+	}
+
+	return w.open()
+}
+
+// shiftBackups renames path.N-1 to path.N down to path to path.1, dropping the oldest.
+func (w *FileWriter) shiftBackups() error {
+	if w.maxBackups == 0 {
+		if err := os.Remove(w.path); err != nil {
+			return errors.Join(ErrStorageNotAvailable, err)
+		}
+		return nil
+	}
+
+	if err := os.Remove(w.backupPath(w.maxBackups)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return errors.Join(ErrStorageNotAvailable, err)
+	}
+	for i := w.maxBackups - 1; i >= 1; i-- {
+		if err := os.Rename(w.backupPath(i), w.backupPath(i+1)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return errors.Join(ErrStorageNotAvailable, err)
+		}
+	}
+	if err := os.Rename(w.path, w.backupPath(1)); err != nil {
+		return errors.Join(ErrStorageNotAvailable, err)
+	}
+	return nil
+}
+
+func (w *FileWriter) backupPath(n int) string {
+	return fmt.Sprintf("%s.%d", w.path, n)
+}
+
+// completeLinesSize returns the size of f up to and including its last newline,
+// scanning backwards from the end in chunks.
+func completeLinesSize(f *os.File) (int64, error) {
+	info, err := f.Stat()
+	if err != nil {
+		return 0, err
+	}
+
+	const chunkSize = 4096
+	buf := make([]byte, chunkSize)
+	for end := info.Size(); end > 0; {
+		start := max(end-chunkSize, 0)
+		chunk := buf[:end-start]
+		if _, err := f.ReadAt(chunk, start); err != nil {
+			return 0, err
+		}
+		if i := bytes.LastIndexByte(chunk, '\n'); i >= 0 {
+			return start + int64(i) + 1, nil
+		}
+		end = start
+	}
+	return 0, nil
+}
@@ -0,0 +1,220 @@
+package audit
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// readEvents decodes the JSONL file at path, failing on any malformed line.
+func readEvents(t *testing.T, path string) []Event {
+	t.Helper()
+
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	var events []Event
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var event Event
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &event), "line %q", scanner.Text())
+		events = append(events, event)
+	}
+	require.NoError(t, scanner.Err())
+	return events
+}
+
+func TestFileWriter_Store(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "logs", "audit.jsonl")
+	w, err := NewFileWriter(path)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	createdAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	require.NoError(t, w.Store(ctx, Event{ID: "1", Action: "user.login", Result: ResultSuccess, CreatedAt: createdAt}))
+	require.NoError(t, w.StoreBatch(ctx, []Event{{ID: "2"}, {ID: "3"}}))
+	require.NoError(t, w.Close())
+
+	events := readEvents(t, path)
+	require.Len(t, events, 3)
+	assert.Equal(t, "user.login", events[0].Action)
+	assert.True(t, createdAt.Equal(events[0].CreatedAt))
+	assert.Equal(t, "3", events[2].ID)
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+}
+
+func TestFileWriter_AppendsOnReopen(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	ctx := context.Background()
+
+	for _, id := range []string{"1", "2"} {
+		w, err := NewFileWriter(path)
+		require.NoError(t, err)
+		require.NoError(t, w.Store(ctx, Event{ID: id}))
+		require.NoError(t, w.Close())
+	}
+
+	events := readEvents(t, path)
+	require.Len(t, events, 2)
+	assert.Equal(t, "1", events[0].ID)
+	assert.Equal(t, "2", events[1].ID)
+}
+
+func TestFileWriter_TruncatesPartialLine(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	// Simulates a crash in the middle of writing the second line
+	require.NoError(t, os.WriteFile(path, []byte(`{"id":"1"}`+"\n"+`{"id":"2","act`), 0o600))
+
+	w, err := NewFileWriter(path)
+	require.NoError(t, err)
+	require.NoError(t, w.Store(context.Background(), Event{ID: "3"}))
+	require.NoError(t, w.Close())
+
+	events := readEvents(t, path)
+	require.Len(t, events, 2)
+	assert.Equal(t, "1", events[0].ID)
+	assert.Equal(t, "3", events[1].ID)
+}
+
+func TestFileWriter_Rotation(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	line, err := json.Marshal(Event{ID: "0"})
+	require.NoError(t, err)
+
+	// Room for two events per file
+	w, err := NewFileWriter(path, WithMaxSize(int64(2*(len(line)+1))), WithMaxBackups(2))
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	for i := range 8 {
+		require.NoError(t, w.Store(ctx, Event{ID: fmt.Sprint(i)}))
+	}
+	require.NoError(t, w.Close())
+
+	assert.Equal(t, []string{"6", "7"}, eventIDs(readEvents(t, path)))
+	assert.Equal(t, []string{"4", "5"}, eventIDs(readEvents(t, path+".1")))
+	assert.Equal(t, []string{"2", "3"}, eventIDs(readEvents(t, path+".2")))
+	assert.NoFileExists(t, path+".3")
+}
+
+func TestFileWriter_RotationWithoutBackups(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	w, err := NewFileWriter(path, WithMaxSize(1), WithMaxBackups(0))
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	require.NoError(t, w.Store(ctx, Event{ID: "1"}))
+	// Larger than the limit, but still written whole to the fresh file
+	require.NoError(t, w.StoreBatch(ctx, []Event{{ID: "2"}, {ID: "3"}}))
+	require.NoError(t, w.Close())
+
+	assert.Equal(t, []string{"2", "3"}, eventIDs(readEvents(t, path)))
+	assert.NoFileExists(t, path+".1")
+}
+
+func TestFileWriter_ConcurrentStore(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	w, err := NewFileWriter(path, WithMaxSize(4096), WithMaxBackups(100), WithSyncInterval(time.Millisecond))
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	var wg sync.WaitGroup
+	for g := range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 50 {
+				assert.NoError(t, w.Store(ctx, Event{ID: fmt.Sprintf("%d-%d", g, i), Action: "user.login"}))
+			}
+		}()
+	}
+	wg.Wait()
+	require.NoError(t, w.Close())
+
+	files, err := filepath.Glob(path + "*")
+	require.NoError(t, err)
+
+	seen := make(map[string]bool)
+	for _, file := range files {
+		for _, event := range readEvents(t, file) {
+			seen[event.ID] = true
+		}
+	}
+	assert.Len(t, seen, 500)
+}
+
+func TestFileWriter_SyncEveryWrite(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	w, err := NewFileWriter(path, WithSyncInterval(0))
+	require.NoError(t, err)
+	defer w.Close()
+
+	require.NoError(t, w.Store(context.Background(), Event{ID: "1"}))
+	assert.Len(t, readEvents(t, path), 1)
+}
+
+func TestFileWriter_Closed(t *testing.T) {
+	t.Parallel()
+
+	w, err := NewFileWriter(filepath.Join(t.TempDir(), "audit.jsonl"))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	require.NoError(t, w.Close())
+
+	assert.ErrorIs(t, w.Store(context.Background(), Event{ID: "1"}), ErrStorageNotAvailable)
+	assert.ErrorIs(t, w.Sync(), ErrStorageNotAvailable)
+}
+
+func TestFileWriter_WithAsyncLogger(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	w, err := NewFileWriter(path)
+	require.NoError(t, err)
+
+	logger, closeFunc := NewAsyncLogger(w, 100)
+	ctx := context.Background()
+	require.NoError(t, logger.Log(ctx, "user.login"))
+	require.NoError(t, logger.Log(ctx, "user.logout"))
+	closeFunc(ctx)
+	require.NoError(t, w.Close())
+
+	events := readEvents(t, path)
+	require.Len(t, events, 2)
+	assert.Equal(t, "user.login", events[0].Action)
+}
+
+func eventIDs(events []Event) []string {
+	ids := make([]string, len(events))
+	for i, event := range events {
+		ids[i] = event.ID
+	}
+	return ids
+}
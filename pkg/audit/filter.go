@@ -21,11 +21,19 @@ type FilterRule struct {
 	Action FilterAction
 }
 
+// DefaultFilterMaxDepth is the default nesting depth up to which metadata is filtered
+const DefaultFilterMaxDepth = 10
+
+// MaxDepthExceeded replaces nested maps and slices deeper than the filter's MaxDepth,
+// so values that weren't inspected never leak into the audit log
+const MaxDepthExceeded = "[max depth exceeded]"
+
 // MetadataFilter provides configurable filtering for sensitive data in audit events
 type MetadataFilter struct {
 	customFilters map[string]FilterRule
 	allowedFields map[string]bool
 	filterPII     bool
+	maxDepth      int
 }
 
 // Default PII fields that should be filtered automatically
@@ -67,6 +75,7 @@ func NewMetadataFilter(opts ...FilterOption) *MetadataFilter {
 		customFilters: make(map[string]FilterRule),
 		allowedFields: make(map[string]bool),
 		filterPII:     true,
+		maxDepth:      DefaultFilterMaxDepth,
 	}
 
 	for _, opt := range opts {
@@ -90,6 +99,17 @@ func WithAllowedField(field string) FilterOption {
 	}
 }
 
+// WithMaxDepth limits how deep nested maps and slices are filtered, with the top-level
+// metadata at depth 1. Deeper containers are replaced with MaxDepthExceeded.
+// Default is DefaultFilterMaxDepth; values below 1 are ignored.
+func WithMaxDepth(depth int) FilterOption {
+	return func(f *MetadataFilter) {
+		if depth > 0 {
+			f.maxDepth = depth
+		}
+	}
+}
+
 // WithoutPIIDefaults disables default PII field filtering
 func WithoutPIIDefaults() FilterOption {
 	return func(f *MetadataFilter) {
@@ -97,13 +117,20 @@ func WithoutPIIDefaults() FilterOption {
 	}
 }
 
-// Filter applies filtering rules to the provided metadata map
+// Filter applies filtering rules to the provided metadata map.
+// Nested map[string]any and []any values are filtered recursively, so rules
+// match keys at any depth. The input map and its nested values are not modified.
 func (f *MetadataFilter) Filter(metadata map[string]any) map[string]any {
 	if metadata == nil {
 		return nil
 	}
 
-	filtered := make(map[string]any)
+	return f.filterMap(metadata, 1)
+}
+
+// filterMap filters a map found at the given depth, with the top-level metadata at depth 1
+func (f *MetadataFilter) filterMap(metadata map[string]any, depth int) map[string]any {
+	filtered := make(map[string]any, len(metadata))
 
 	for key, value := range metadata {
 		lowerKey := strings.ToLower(key)
@@ -114,45 +141,68 @@ func (f *MetadataFilter) Filter(metadata map[string]any) map[string]any {
 			continue
 		}
 
-		// Check custom filters first
-		if rule, ok := f.customFilters[lowerKey]; ok {
-			if result := f.applyRule(rule, value); result != nil {
+		if rule := f.ruleFor(lowerKey); rule != nil {
+			if result := f.applyRule(*rule, value); result != nil {
 				filtered[key] = result
 			}
 			continue
 		}
 
-		// Check wildcard patterns in custom filters
-		if rule := f.matchWildcard(lowerKey, f.customFilters); rule != nil {
-			if result := f.applyRule(*rule, value); result != nil {
-				filtered[key] = result
-			}
-			continue
+		// No filter matched, include the field with its nested values filtered
+		filtered[key] = f.filterValue(value, depth+1)
+	}
+
+	return filtered
+}
+
+// filterValue recurses into nested maps and slices found at the given depth
+func (f *MetadataFilter) filterValue(value any, depth int) any {
+	switch v := value.(type) {
+	case map[string]any:
+		if depth > f.maxDepth {
+			return MaxDepthExceeded
+		}
+		return f.filterMap(v, depth)
+	case []any:
+		if depth > f.maxDepth {
+			return MaxDepthExceeded
+		}
+		// Slices don't add a level of keys, but still count towards the depth limit
+		filtered := make([]any, len(v))
+		for i, item := range v {
+			filtered[i] = f.filterValue(item, depth+1)
 		}
+		return filtered
+	default:
+		return value
+	}
+}
 
-		// Check default PII filters if enabled
-		if f.filterPII {
-			if rule, ok := defaultPIIFields[lowerKey]; ok {
-				if result := f.applyRule(rule, value); result != nil {
-					filtered[key] = result
-				}
-				continue
-			}
+// ruleFor returns the rule matching the lowercased key, or nil if the field isn't filtered
+func (f *MetadataFilter) ruleFor(lowerKey string) *FilterRule {
+	// Check custom filters first
+	if rule, ok := f.customFilters[lowerKey]; ok {
+		return &rule
+	}
 
-			// Check wildcard patterns in default PII filters
-			if rule := f.matchWildcard(lowerKey, defaultPIIFields); rule != nil {
-				if result := f.applyRule(*rule, value); result != nil {
-					filtered[key] = result
-				}
-				continue
-			}
+	// Check wildcard patterns in custom filters
+	if rule := f.matchWildcard(lowerKey, f.customFilters); rule != nil {
+		return rule
+	}
+
+	// Check default PII filters if enabled
+	if f.filterPII {
+		if rule, ok := defaultPIIFields[lowerKey]; ok {
+			return &rule
 		}
 
-		// No filter matched, include the field as-is
-		filtered[key] = value
+		// Check wildcard patterns in default PII filters
+		if rule := f.matchWildcard(lowerKey, defaultPIIFields); rule != nil {
+			return rule
+		}
 	}
 
-	return filtered
+	return nil
 }
 
 // matchWildcard checks if the key matches any wildcard patterns in the rules
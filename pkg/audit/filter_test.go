@@ -204,3 +204,104 @@ func TestMetadataFilter_ComplexScenario(t *testing.T) {
 	assert.Nil(t, result["password"])
 	assert.Equal(t, "stays", result["normal_data"])
 }
+
+func TestMetadataFilter_NestedValues(t *testing.T) {
+	f := NewMetadataFilter(WithCustomField("internal_note", FilterActionRemove))
+
+	metadata := map[string]any{
+		"user": map[string]any{
+			"name":     "John",
+			"ssn":      "123-45-6789",
+			"password": "secret",
+			"contact": map[string]any{
+				"Email": "user@example.com",
+			},
+		},
+		"items": []any{
+			map[string]any{"sku": "A1", "internal_note": "vip"},
+			"plain",
+			[]any{map[string]any{"token": "abc"}},
+		},
+	}
+
+	result := f.Filter(metadata)
+
+	user, ok := result["user"].(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, "John", user["name"])
+	assert.Equal(t, "12*******89", user["ssn"])
+	assert.NotContains(t, user, "password")
+
+	contact, ok := user["contact"].(map[string]any)
+	require.True(t, ok)
+	assert.NotEqual(t, "user@example.com", contact["Email"])
+
+	items, ok := result["items"].([]any)
+	require.True(t, ok)
+	require.Len(t, items, 3)
+	assert.Equal(t, map[string]any{"sku": "A1"}, items[0])
+	assert.Equal(t, "plain", items[1])
+	assert.Equal(t, []any{map[string]any{}}, items[2])
+
+	// The input is not modified
+	assert.Equal(t, "secret", metadata["user"].(map[string]any)["password"])
+}
+
+func TestMetadataFilter_NestedMatchedKeyAppliesToWholeValue(t *testing.T) {
+	f := NewMetadataFilter(WithAllowedField("raw"))
+
+	result := f.Filter(map[string]any{
+		"secret": map[string]any{"anything": "here"},
+		"raw":    map[string]any{"password": "kept"},
+	})
+
+	assert.NotContains(t, result, "secret")
+	assert.Equal(t, map[string]any{"password": "kept"}, result["raw"]) // Explicitly allowed values aren't inspected
+}
+
+func TestMetadataFilter_MaxDepth(t *testing.T) {
+	// Builds {"level": {"level": ... {"password": "secret"}}} with n maps above the leaf map
+	nested := func(n int) map[string]any {
+		m := map[string]any{"password": "secret"}
+		for range n {
+			m = map[string]any{"level": m}
+		}
+		return m
+	}
+
+	t.Run("default depth", func(t *testing.T) {
+		result := NewMetadataFilter().Filter(nested(DefaultFilterMaxDepth - 1))
+
+		m := result
+		for range DefaultFilterMaxDepth - 1 {
+			m = m["level"].(map[string]any)
+		}
+		assert.Empty(t, m)
+	})
+
+	t.Run("deeper values are replaced", func(t *testing.T) {
+		result := NewMetadataFilter(WithMaxDepth(2)).Filter(nested(2))
+
+		level1, ok := result["level"].(map[string]any)
+		require.True(t, ok)
+		assert.Equal(t, MaxDepthExceeded, level1["level"])
+	})
+
+	t.Run("slices count towards depth", func(t *testing.T) {
+		result := NewMetadataFilter(WithMaxDepth(2)).Filter(map[string]any{
+			"list": []any{map[string]any{"password": "secret"}},
+		})
+
+		assert.Equal(t, []any{MaxDepthExceeded}, result["list"])
+	})
+
+	t.Run("flat metadata with depth 1", func(t *testing.T) {
+		result := NewMetadataFilter(WithMaxDepth(1)).Filter(map[string]any{
+			"password": "secret",
+			"name":     "John",
+			"nested":   map[string]any{"a": 1},
+		})
+
+		assert.Equal(t, map[string]any{"name": "John", "nested": MaxDepthExceeded}, result)
+	})
+}
@@ -16,6 +16,7 @@ type Logger struct {
 	ipExtractor        contextExtractor
 	userAgentExtractor contextExtractor
 	metadataFilter     *MetadataFilter
+	reader             Reader
 }
 
 // contextExtractor extracts string values from request context for audit events.
@@ -45,22 +46,8 @@ func NewLogger(w writer, opts ...Option) *Logger {
 
 // Log records a successful action
 func (l *Logger) Log(ctx context.Context, action string, opts ...EventOption) error {
-	event := l.eventFromContext(ctx)
-	event.ID = uuid.New().String()
-	event.CreatedAt = time.Now()
-	event.Action = action
-	event.Result = ResultSuccess
-
-	for _, opt := range opts {
-		opt(&event)
-	}
-
-	// Apply metadata filtering if configured
-	if l.metadataFilter != nil && event.Metadata != nil {
-		event.Metadata = l.metadataFilter.Filter(event.Metadata)
-	}
-
-	if err := event.Validate(); err != nil {
+	event, err := l.newEvent(l.eventFromContext(ctx), action, nil, opts)
+	if err != nil {
 		return err
 	}
 
@@ -69,12 +56,26 @@ func (l *Logger) Log(ctx context.Context, action string, opts ...EventOption) er
 
 // LogError records a failed action
 func (l *Logger) LogError(ctx context.Context, action string, err error, opts ...EventOption) error {
-	event := l.eventFromContext(ctx)
+	event, validationErr := l.newEvent(l.eventFromContext(ctx), action, err, opts)
+	if validationErr != nil {
+		return validationErr
+	}
+
+	return l.writer.Store(ctx, event)
+}
+
+// newEvent builds a validated event from the context fields in base, shared by the
+// single-event and batch paths. A non-nil actionErr records the action as failed.
+func (l *Logger) newEvent(base Event, action string, actionErr error, opts []EventOption) (Event, error) {
+	event := base
 	event.ID = uuid.New().String()
-	event.Action = action
-	event.Result = ResultError
-	event.Error = err.Error()
 	event.CreatedAt = time.Now()
+	event.Action = action
+	event.Result = ResultSuccess
+	if actionErr != nil {
+		event.Result = ResultError
+		event.Error = actionErr.Error()
+	}
 
 	for _, opt := range opts {
 		opt(&event)
@@ -86,10 +87,20 @@ func (l *Logger) LogError(ctx context.Context, action string, err error, opts ..
 	}
 
 	if err := event.Validate(); err != nil {
-		return err
+		return Event{}, err
 	}
 
-	return l.writer.Store(ctx, event)
+	return event, nil
+}
+
+// Query reads events back through the Reader set with WithReader,
+// so loggers created by NewAsyncLogger can read what they have flushed.
+// Returns ErrReaderNotConfigured when no reader is set.
+func (l *Logger) Query(ctx context.Context, filter EventFilter) ([]Event, error) {
+	if l.reader == nil {
+		return nil, ErrReaderNotConfigured
+	}
+	return l.reader.Query(ctx, filter)
 }
 
 // eventFromContext populates an Event with contextual information using configured extractors.
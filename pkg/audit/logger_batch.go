@@ -0,0 +1,97 @@
+package audit
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// BatchEntry describes one event for Logger.LogBatch.
+type BatchEntry struct {
+	Action  string
+	Err     error // Records the action as failed, like LogError; nil for success
+	Options []EventOption
+}
+
+// BatchEntryError identifies an entry of LogBatch that wasn't stored.
+type BatchEntryError struct {
+	Index  int    // Position of the entry in the batch
+	Action string // Action of the entry
+	Err    error
+}
+
+func (e *BatchEntryError) Error() string {
+	return fmt.Sprintf("audit: batch entry %d (%s): %v", e.Index, e.Action, e.Err)
+}
+
+func (e *BatchEntryError) Unwrap() error {
+	return e.Err
+}
+
+// LogBatch records several events sharing ctx, e.g. from a bulk import job.
+// Context extractors run once for the whole batch, while options, metadata
+// filtering and validation apply to each entry as in Log.
+//
+// Valid entries are written with a single StoreBatch call when the writer supports
+// it (AsyncWriter queues them as one unit), otherwise one Store call each. Invalid entries are skipped without blocking the
+// rest. The returned error joins a *BatchEntryError for every entry that wasn't
+// stored; as StoreBatch is atomic, its failure is reported for all valid entries.
+func (l *Logger) LogBatch(ctx context.Context, entries []BatchEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	base := l.eventFromContext(ctx)
+
+	var failed []*BatchEntryError
+	events := make([]Event, 0, len(entries))
+	indexes := make([]int, 0, len(entries))
+	for i, entry := range entries {
+		event, err := l.newEvent(base, entry.Action, entry.Err, entry.Options)
+		if err != nil {
+			failed = append(failed, &BatchEntryError{Index: i, Action: entry.Action, Err: err})
+			continue
+		}
+		events = append(events, event)
+		indexes = append(indexes, i)
+	}
+
+	switch w := l.writer.(type) {
+	case batchWriter:
+		if len(events) > 0 {
+			if err := w.StoreBatch(ctx, events); err != nil {
+				for _, i := range indexes {
+					failed = append(failed, &BatchEntryError{Index: i, Action: entries[i].Action, Err: err})
+				}
+			}
+		}
+	default:
+		for j, event := range events {
+			if err := w.Store(ctx, event); err != nil {
+				i := indexes[j]
+				failed = append(failed, &BatchEntryError{Index: i, Action: entries[i].Action, Err: err})
+			}
+		}
+	}
+
+	return joinBatchErrors(failed)
+}
+
+// joinBatchErrors joins the entry errors ordered by index, or returns nil if there are none.
+func joinBatchErrors(failed []*BatchEntryError) error {
+	if len(failed) == 0 {
+		return nil
+	}
+
+	slices.SortFunc(failed, func(a, b *BatchEntryError) int {
+		return cmp.Compare(a.Index, b.Index)
+	})
+
+	errs := make([]error, len(failed))
+	for i, err := range failed {
+		errs[i] = err
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,130 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogger_LogBatch(t *testing.T) {
+	t.Parallel()
+
+	t.Run("uses StoreBatch and runs extractors once", func(t *testing.T) {
+		t.Parallel()
+		store := &memoryStore{}
+
+		var extractorCalls atomic.Int32
+		logger := NewLogger(&batchOnlyWriter{store}, WithTenantIDExtractor(func(context.Context) (string, bool) {
+			extractorCalls.Add(1)
+			return "tenant-1", true
+		}), WithMetadataFilter(NewMetadataFilter()))
+
+		actionErr := errors.New("card declined")
+		err := logger.LogBatch(context.Background(), []BatchEntry{
+			{Action: "import.user", Options: []EventOption{WithMetadata("password", "secret")}},
+			{Action: "import.payment", Err: actionErr, Options: []EventOption{WithResource("payment", "p1")}},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, int32(1), extractorCalls.Load())
+
+		require.Len(t, store.events, 2)
+		assert.Equal(t, "tenant-1", store.events[0].TenantID)
+		assert.Equal(t, ResultSuccess, store.events[0].Result)
+		assert.NotContains(t, store.events[0].Metadata, "password")
+
+		assert.Equal(t, "tenant-1", store.events[1].TenantID)
+		assert.Equal(t, ResultError, store.events[1].Result)
+		assert.Equal(t, "card declined", store.events[1].Error)
+		assert.Equal(t, "p1", store.events[1].ResourceID)
+		assert.NotEqual(t, store.events[0].ID, store.events[1].ID)
+	})
+
+	t.Run("falls back to Store per entry", func(t *testing.T) {
+		t.Parallel()
+		mockWriter := &MockWriter{}
+		storeErr := errors.New("storage down")
+		mockWriter.On("Store", mock.Anything, mock.MatchedBy(func(e Event) bool { return e.Action == "a" })).Return(nil).Once()
+		mockWriter.On("Store", mock.Anything, mock.MatchedBy(func(e Event) bool { return e.Action == "b" })).Return(storeErr).Once()
+		mockWriter.On("Store", mock.Anything, mock.MatchedBy(func(e Event) bool { return e.Action == "c" })).Return(nil).Once()
+
+		logger := NewLogger(mockWriter)
+		err := logger.LogBatch(context.Background(), []BatchEntry{{Action: "a"}, {Action: "b"}, {Action: "c"}})
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, storeErr)
+		var entryErr *BatchEntryError
+		require.ErrorAs(t, err, &entryErr)
+		assert.Equal(t, 1, entryErr.Index)
+		assert.Equal(t, "b", entryErr.Action)
+		mockWriter.AssertExpectations(t)
+	})
+
+	t.Run("skips invalid entries", func(t *testing.T) {
+		t.Parallel()
+		store := &memoryStore{}
+		logger := NewLogger(&batchOnlyWriter{store})
+
+		err := logger.LogBatch(context.Background(), []BatchEntry{{Action: "a"}, {Action: ""}, {Action: "c"}})
+
+		assert.ErrorIs(t, err, ErrEventValidation)
+		assert.Equal(t, []int{1}, batchErrorIndexes(err))
+		assert.Len(t, store.events, 2)
+	})
+
+	t.Run("batch failure reports every stored entry in order", func(t *testing.T) {
+		t.Parallel()
+		storeErr := errors.New("storage down")
+		logger := NewLogger(&batchOnlyWriter{failingBatchStore{storeErr}})
+
+		err := logger.LogBatch(context.Background(), []BatchEntry{{Action: "a"}, {Action: ""}, {Action: "c"}})
+
+		assert.ErrorIs(t, err, storeErr)
+		assert.ErrorIs(t, err, ErrEventValidation)
+		assert.Equal(t, []int{0, 1, 2}, batchErrorIndexes(err))
+	})
+
+	t.Run("empty batch", func(t *testing.T) {
+		t.Parallel()
+		logger := NewLogger(&MockWriter{})
+		assert.NoError(t, logger.LogBatch(context.Background(), nil))
+	})
+}
+
+// batchOnlyWriter satisfies writer by delegating to a batch store, like storage
+// backends implementing both interfaces.
+type batchOnlyWriter struct {
+	batchWriter
+}
+
+func (w *batchOnlyWriter) Store(ctx context.Context, event Event) error {
+	return w.StoreBatch(ctx, []Event{event})
+}
+
+type failingBatchStore struct {
+	err error
+}
+
+func (s failingBatchStore) StoreBatch(context.Context, []Event) error {
+	return s.err
+}
+
+func batchErrorIndexes(err error) []int {
+	joined, ok := err.(interface{ Unwrap() []error })
+	if !ok {
+		return nil
+	}
+
+	var indexes []int
+	for _, e := range joined.Unwrap() {
+		var entryErr *BatchEntryError
+		if errors.As(e, &entryErr) {
+			indexes = append(indexes, entryErr.Index)
+		}
+	}
+	return indexes
+}
@@ -274,16 +274,16 @@ func TestLogger_WithMetadataFilter(t *testing.T) {
 
 		ctx := context.Background()
 		mockWriter.On("Store", mock.Anything, mock.MatchedBy(func(event Event) bool {
-			// Nested structures should be preserved as-is since they're not PII fields
 			userData, hasUserData := event.Metadata["user_data"]
 			config, hasConfig := event.Metadata["config"]
 
-			// The nested structure should remain unchanged since "user_data" is not a PII field
+			// "user_data" is not a PII field, so it's kept but its nested PII fields are filtered
 			userDataMap, isMap := userData.(map[string]any)
+			_, hasPassword := userDataMap["password"]
 
 			return hasUserData && isMap &&
 				userDataMap["name"] == "John Doe" &&
-				userDataMap["password"] == "secret" &&
+				!hasPassword &&
 				hasConfig && config == "safe-config"
 		})).Return(nil).Once()
 
@@ -365,7 +365,7 @@ func TestAsyncLogger_MetadataFiltering(t *testing.T) {
 	}
 
 	// Close the logger and wait for it to finish
-	err = closeFunc(ctx)
+	err = closeFunc(ctx).Err()
 	assert.NoError(t, err)
 
 	mockBW.AssertExpectations(t)
@@ -51,3 +51,11 @@ func WithMetadataFilter(filter *MetadataFilter) Option {
 		l.metadataFilter = filter
 	}
 }
+
+// WithReader enables Logger.Query, reading events back from the storage the writer fills.
+// Use NewReader to wrap a query function.
+func WithReader(r Reader) Option {
+	return func(l *Logger) {
+		l.reader = r
+	}
+}
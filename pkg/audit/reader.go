@@ -0,0 +1,130 @@
+package audit
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+)
+
+// EventFilter selects audit events for Reader.Query.
+// Zero-value fields don't filter, so an empty EventFilter matches every event.
+type EventFilter struct {
+	TenantID     string
+	UserID       string
+	ActionPrefix string    // Matches actions starting with the prefix, e.g. "user." matches "user.login"
+	From         time.Time // Inclusive lower bound on CreatedAt
+	To           time.Time // Exclusive upper bound on CreatedAt
+	Result       Result
+	Limit        int // Maximum number of events to return, 0 means no limit
+	Offset       int // Number of matching events to skip, for pagination
+}
+
+// Validate checks that pagination values aren't negative and the time range isn't inverted.
+func (f EventFilter) Validate() error {
+	if f.Limit < 0 || f.Offset < 0 {
+		return errors.Join(ErrInvalidFilter, errors.New("limit and offset must not be negative"))
+	}
+	if !f.From.IsZero() && !f.To.IsZero() && !f.From.Before(f.To) {
+		return errors.Join(ErrInvalidFilter, errors.New("from must be before to"))
+	}
+	return nil
+}
+
+// Matches reports whether event satisfies the filter criteria. Limit and Offset are ignored.
+func (f EventFilter) Matches(event Event) bool {
+	switch {
+	case f.TenantID != "" && event.TenantID != f.TenantID:
+		return false
+	case f.UserID != "" && event.UserID != f.UserID:
+		return false
+	case f.ActionPrefix != "" && !strings.HasPrefix(event.Action, f.ActionPrefix):
+		return false
+	case !f.From.IsZero() && event.CreatedAt.Before(f.From):
+		return false
+	case !f.To.IsZero() && !event.CreatedAt.Before(f.To):
+		return false
+	case f.Result != "" && event.Result != f.Result:
+		return false
+	}
+	return true
+}
+
+// Apply returns the events matching the filter in export order, paginated by
+// Offset and Limit. Useful for query functions backed by in-memory storage.
+// The input slice is not modified.
+func (f EventFilter) Apply(events []Event) []Event {
+	matched := make([]Event, 0)
+	for _, event := range events {
+		if f.Matches(event) {
+			matched = append(matched, event)
+		}
+	}
+	SortEvents(matched)
+
+	if f.Offset >= len(matched) {
+		return matched[:0]
+	}
+	matched = matched[f.Offset:]
+	if f.Limit > 0 && f.Limit < len(matched) {
+		matched = matched[:f.Limit]
+	}
+	return matched
+}
+
+// SortEvents sorts events in export order: by CreatedAt ascending, then by ID,
+// so events created at the same instant keep a stable order across pages.
+func SortEvents(events []Event) {
+	slices.SortStableFunc(events, func(a, b Event) int {
+		if c := a.CreatedAt.Compare(b.CreatedAt); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.ID, b.ID)
+	})
+}
+
+// Reader reads audit events back, e.g. for compliance exports.
+// Implementations must return events in export order (see SortEvents)
+// so that paginating with Limit and Offset neither skips nor repeats events.
+type Reader interface {
+	Query(ctx context.Context, filter EventFilter) ([]Event, error)
+}
+
+// QueryFunc loads the events matching filter from storage, applying
+// all criteria and pagination, e.g. with a SQL query ending in
+// ORDER BY created_at, id LIMIT $n OFFSET $m.
+type QueryFunc func(ctx context.Context, filter EventFilter) ([]Event, error)
+
+// FuncReader is the default Reader, wrapping a user-supplied QueryFunc.
+// It validates filters before querying and guarantees export order of the results.
+type FuncReader struct {
+	query QueryFunc
+}
+
+// NewReader creates a Reader backed by the query function.
+func NewReader(query QueryFunc) *FuncReader {
+	if query == nil {
+		panic("audit: query function cannot be nil")
+	}
+	return &FuncReader{query: query}
+}
+
+// Query returns the events matching filter in export order.
+// Returns ErrInvalidFilter for invalid filters and ErrQueryFailed
+// joined with the cause when the query function fails.
+func (r *FuncReader) Query(ctx context.Context, filter EventFilter) ([]Event, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+
+	events, err := r.query(ctx, filter)
+	if err != nil {
+		return nil, errors.Join(ErrQueryFailed, err)
+	}
+
+	// Query functions may not break CreatedAt ties deterministically
+	SortEvents(events)
+	return events, nil
+}
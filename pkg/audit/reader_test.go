@@ -0,0 +1,214 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryStore is a batch writer whose events can be queried back.
+type memoryStore struct {
+	mu     sync.Mutex
+	events []Event
+}
+
+func (s *memoryStore) StoreBatch(_ context.Context, events []Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = append(s.events, events...)
+	return nil
+}
+
+func (s *memoryStore) Query(_ context.Context, filter EventFilter) ([]Event, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return filter.Apply(s.events), nil
+}
+
+func TestEventFilter_Matches(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	event := Event{
+		TenantID:  "tenant-1",
+		UserID:    "user-1",
+		Action:    "user.login",
+		Result:    ResultSuccess,
+		CreatedAt: now,
+	}
+
+	tests := []struct {
+		name   string
+		filter EventFilter
+		want   bool
+	}{
+		{name: "empty filter", filter: EventFilter{}, want: true},
+		{name: "tenant", filter: EventFilter{TenantID: "tenant-1"}, want: true},
+		{name: "other tenant", filter: EventFilter{TenantID: "tenant-2"}, want: false},
+		{name: "user", filter: EventFilter{UserID: "user-2"}, want: false},
+		{name: "action prefix", filter: EventFilter{ActionPrefix: "user."}, want: true},
+		{name: "other action prefix", filter: EventFilter{ActionPrefix: "billing."}, want: false},
+		{name: "from is inclusive", filter: EventFilter{From: now}, want: true},
+		{name: "to is exclusive", filter: EventFilter{To: now}, want: false},
+		{name: "within range", filter: EventFilter{From: now.Add(-time.Minute), To: now.Add(time.Minute)}, want: true},
+		{name: "result", filter: EventFilter{Result: ResultError}, want: false},
+		{name: "all criteria", filter: EventFilter{TenantID: "tenant-1", UserID: "user-1", ActionPrefix: "user", Result: ResultSuccess}, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, tt.filter.Matches(event))
+		})
+	}
+}
+
+func TestEventFilter_Validate(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	assert.NoError(t, EventFilter{Limit: 10, Offset: 20, From: now, To: now.Add(time.Hour)}.Validate())
+	assert.ErrorIs(t, EventFilter{Limit: -1}.Validate(), ErrInvalidFilter)
+	assert.ErrorIs(t, EventFilter{Offset: -1}.Validate(), ErrInvalidFilter)
+	assert.ErrorIs(t, EventFilter{From: now, To: now}.Validate(), ErrInvalidFilter)
+}
+
+func TestEventFilter_Apply(t *testing.T) {
+	t.Parallel()
+
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	// Out of order, with CreatedAt ties broken by ID
+	events := []Event{
+		{ID: "4", Action: "user.logout", CreatedAt: base.Add(2 * time.Second)},
+		{ID: "2", Action: "user.login", CreatedAt: base.Add(time.Second)},
+		{ID: "3", Action: "billing.charge", CreatedAt: base.Add(time.Second)},
+		{ID: "1", Action: "user.login", CreatedAt: base},
+		{ID: "5", Action: "user.login", CreatedAt: base.Add(2 * time.Second)},
+	}
+
+	ids := func(events []Event) []string {
+		result := make([]string, len(events))
+		for i, e := range events {
+			result[i] = e.ID
+		}
+		return result
+	}
+
+	assert.Equal(t, []string{"1", "2", "3", "4", "5"}, ids(EventFilter{}.Apply(events)))
+	assert.Equal(t, []string{"1", "2", "4", "5"}, ids(EventFilter{ActionPrefix: "user."}.Apply(events)))
+	assert.Equal(t, "4", events[0].ID, "input must not be reordered")
+
+	t.Run("pages cover all events exactly once", func(t *testing.T) {
+		t.Parallel()
+		var all []string
+		for offset := 0; ; offset += 2 {
+			page := EventFilter{Limit: 2, Offset: offset}.Apply(events)
+			if len(page) == 0 {
+				break
+			}
+			all = append(all, ids(page)...)
+		}
+		assert.Equal(t, []string{"1", "2", "3", "4", "5"}, all)
+	})
+
+	assert.Empty(t, EventFilter{Offset: 10}.Apply(events))
+}
+
+func TestFuncReader_Query(t *testing.T) {
+	t.Parallel()
+
+	t.Run("sorts results and passes the filter", func(t *testing.T) {
+		t.Parallel()
+		base := time.Now()
+		var got EventFilter
+		reader := NewReader(func(_ context.Context, filter EventFilter) ([]Event, error) {
+			got = filter
+			return []Event{
+				{ID: "b", CreatedAt: base},
+				{ID: "c", CreatedAt: base.Add(-time.Second)},
+				{ID: "a", CreatedAt: base},
+			}, nil
+		})
+
+		filter := EventFilter{TenantID: "tenant-1", Limit: 3}
+		events, err := reader.Query(context.Background(), filter)
+		require.NoError(t, err)
+		assert.Equal(t, filter, got)
+		require.Len(t, events, 3)
+		assert.Equal(t, []string{"c", "a", "b"}, []string{events[0].ID, events[1].ID, events[2].ID})
+	})
+
+	t.Run("rejects invalid filters without querying", func(t *testing.T) {
+		t.Parallel()
+		reader := NewReader(func(context.Context, EventFilter) ([]Event, error) {
+			t.Fatal("query must not be called")
+			return nil, nil
+		})
+
+		_, err := reader.Query(context.Background(), EventFilter{Limit: -1})
+		assert.ErrorIs(t, err, ErrInvalidFilter)
+	})
+
+	t.Run("wraps query errors", func(t *testing.T) {
+		t.Parallel()
+		dbErr := errors.New("connection refused")
+		reader := NewReader(func(context.Context, EventFilter) ([]Event, error) {
+			return nil, dbErr
+		})
+
+		_, err := reader.Query(context.Background(), EventFilter{})
+		assert.ErrorIs(t, err, ErrQueryFailed)
+		assert.ErrorIs(t, err, dbErr)
+	})
+
+	t.Run("panics with nil query", func(t *testing.T) {
+		t.Parallel()
+		assert.Panics(t, func() { NewReader(nil) })
+	})
+}
+
+func TestLogger_Query(t *testing.T) {
+	t.Parallel()
+
+	t.Run("without reader", func(t *testing.T) {
+		t.Parallel()
+		logger := NewLogger(&MockWriter{})
+
+		_, err := logger.Query(context.Background(), EventFilter{})
+		assert.ErrorIs(t, err, ErrReaderNotConfigured)
+	})
+
+	t.Run("async logger reads flushed events", func(t *testing.T) {
+		t.Parallel()
+		store := &memoryStore{}
+		logger, cleanup := NewAsyncLogger(store, 100,
+			WithReader(NewReader(store.Query)),
+			WithTenantIDExtractor(func(context.Context) (string, bool) { return "tenant-1", true }),
+		)
+		defer cleanup(context.Background())
+
+		ctx := context.Background()
+		for i := range 5 {
+			require.NoError(t, logger.Log(ctx, fmt.Sprintf("user.action%d", i)))
+		}
+		require.NoError(t, logger.LogError(ctx, "billing.charge", errors.New("declined")))
+
+		events, err := logger.Query(ctx, EventFilter{TenantID: "tenant-1", ActionPrefix: "user.", Limit: 3, Offset: 1})
+		require.NoError(t, err)
+		require.Len(t, events, 3)
+		for _, e := range events {
+			assert.Equal(t, "tenant-1", e.TenantID)
+		}
+
+		failed, err := logger.Query(ctx, EventFilter{Result: ResultError})
+		require.NoError(t, err)
+		require.Len(t, failed, 1)
+		assert.Equal(t, "billing.charge", failed[0].Action)
+	})
+}
@@ -0,0 +1,182 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/dmitrymomot/saaskit/pkg/token"
+)
+
+// staticBreachChecker reports the listed passwords as breached.
+type staticBreachChecker struct {
+	breached map[string]bool
+	err      error
+}
+
+func (c staticBreachChecker) CheckPassword(_ context.Context, password string) (bool, error) {
+	return c.breached[password], c.err
+}
+
+func sha1Hex(password string) string {
+	sum := sha1.Sum([]byte(password))
+	return strings.ToUpper(hex.EncodeToString(sum[:]))
+}
+
+func TestHaveIBeenPwnedChecker(t *testing.T) {
+	t.Parallel()
+
+	const breachedPassword = "P@ssw0rd123"
+	breachedHash := sha1Hex(breachedPassword)
+
+	newServer := func(t *testing.T, body string) *httptest.Server {
+		t.Helper()
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Only the 5 character prefix may leave the process
+			assert.Len(t, strings.TrimPrefix(r.URL.Path, "/range/"), 5)
+			assert.Equal(t, "true", r.Header.Get("Add-Padding"))
+			assert.NotEmpty(t, r.Header.Get("User-Agent"))
+			fmt.Fprint(w, body)
+		}))
+	}
+
+	t.Run("detects breached password", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/range/"+breachedHash[:5], r.URL.Path)
+			fmt.Fprintf(w, "0018A45C4D1DEF81644B54AB7F969B88D65:1\r\n%s:3861493\r\n", strings.ToLower(breachedHash[5:]))
+		}))
+		defer server.Close()
+
+		checker := NewHaveIBeenPwnedChecker(WithPwnedPasswordsURL(server.URL + "/range/"))
+		breached, err := checker.CheckPassword(context.Background(), breachedPassword)
+		require.NoError(t, err)
+		assert.True(t, breached)
+	})
+
+	t.Run("unknown password", func(t *testing.T) {
+		t.Parallel()
+
+		server := newServer(t, "0018A45C4D1DEF81644B54AB7F969B88D65:1\r\n00D4F6E8FA6EECAD2A3AA415EEC418D38EC:2\r\n")
+		defer server.Close()
+
+		checker := NewHaveIBeenPwnedChecker(WithPwnedPasswordsURL(server.URL + "/range/"))
+		breached, err := checker.CheckPassword(context.Background(), "correct horse battery staple 42")
+		require.NoError(t, err)
+		assert.False(t, breached)
+	})
+
+	t.Run("ignores padding entries", func(t *testing.T) {
+		t.Parallel()
+
+		server := newServer(t, breachedHash[5:]+":0\r\n")
+		defer server.Close()
+
+		checker := NewHaveIBeenPwnedChecker(WithPwnedPasswordsURL(server.URL + "/range/"))
+		breached, err := checker.CheckPassword(context.Background(), breachedPassword)
+		require.NoError(t, err)
+		assert.False(t, breached)
+	})
+
+	t.Run("api error", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusTooManyRequests)
+		}))
+		defer server.Close()
+
+		checker := NewHaveIBeenPwnedChecker(
+			WithPwnedPasswordsURL(server.URL+"/range/"),
+			WithPwnedPasswordsHTTPClient(server.Client()),
+			WithPwnedPasswordsUserAgent("test-agent"),
+		)
+		_, err := checker.CheckPassword(context.Background(), breachedPassword)
+		assert.Error(t, err)
+	})
+}
+
+func TestBreachChecker_Services(t *testing.T) {
+	t.Parallel()
+
+	const (
+		tokenSecret      = "test-secret-32-chars-long-12345"
+		breachedPassword = "Breached1Password!"
+		currentPassword  = "CurrentPassword1!"
+	)
+	checker := staticBreachChecker{breached: map[string]bool{breachedPassword: true}}
+
+	t.Run("register rejects breached password", func(t *testing.T) {
+		t.Parallel()
+
+		storage := &MockPasswordStorage{}
+		storage.On("GetUserByEmail", mock.Anything, "user@example.com").Return(nil, ErrUserNotFound)
+		svc := NewPasswordService(storage, tokenSecret, WithBreachChecker(checker))
+
+		_, err := svc.Register(context.Background(), "user@example.com", breachedPassword)
+		assert.ErrorIs(t, err, ErrPasswordBreached)
+		storage.AssertNotCalled(t, "CreateUser", mock.Anything, mock.Anything)
+	})
+
+	t.Run("register fails when checker fails", func(t *testing.T) {
+		t.Parallel()
+
+		storage := &MockPasswordStorage{}
+		storage.On("GetUserByEmail", mock.Anything, "user@example.com").Return(nil, ErrUserNotFound)
+		checkErr := errors.New("api unavailable")
+		svc := NewPasswordService(storage, tokenSecret, WithBreachChecker(staticBreachChecker{err: checkErr}))
+
+		_, err := svc.Register(context.Background(), "user@example.com", "Fresh1Password!")
+		assert.ErrorIs(t, err, checkErr)
+		storage.AssertNotCalled(t, "CreateUser", mock.Anything, mock.Anything)
+	})
+
+	t.Run("reset rejects breached password", func(t *testing.T) {
+		t.Parallel()
+
+		storage := &MockPasswordStorage{}
+		svc := NewPasswordService(storage, tokenSecret, WithBreachChecker(checker))
+
+		resetToken, err := token.GenerateToken(PasswordResetTokenPayload{
+			ID:       uuid.NewString(),
+			Email:    "user@example.com",
+			Subject:  SubjectPasswordReset,
+			ExpireAt: time.Now().Add(time.Hour).Unix(),
+		}, tokenSecret)
+		require.NoError(t, err)
+
+		_, err = svc.ResetPassword(context.Background(), resetToken, breachedPassword)
+		assert.ErrorIs(t, err, ErrPasswordBreached)
+		storage.AssertNotCalled(t, "StorePasswordHash", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("change password rejects breached password", func(t *testing.T) {
+		t.Parallel()
+
+		storage := &MockUserStorage{}
+		userID := uuid.New()
+		hash, err := bcrypt.GenerateFromPassword([]byte(currentPassword), bcrypt.MinCost)
+		require.NoError(t, err)
+		storage.On("GetUserByID", mock.Anything, userID).Return(&User{ID: userID}, nil)
+		storage.On("GetPasswordHash", mock.Anything, userID).Return(hash, nil)
+
+		svc := NewUserService(storage, tokenSecret, WithUserBreachChecker(checker))
+		err = svc.ChangePassword(context.Background(), userID, currentPassword, breachedPassword)
+		assert.ErrorIs(t, err, ErrPasswordBreached)
+		storage.AssertNotCalled(t, "UpdatePasswordHash", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
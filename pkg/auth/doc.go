@@ -7,11 +7,12 @@
 //
 // # Supported Authentication Methods
 //
-// The package supports four primary authentication methods:
+// The package supports five primary authentication methods:
 //
 //   - Password-based authentication with bcrypt hashing and configurable strength requirements
 //   - Magic link authentication via email for passwordless login
-//   - OAuth integration with GitHub and Google providers (extensible to other providers)
+//   - Passkeys (WebAuthn) for phishing-resistant passwordless login
+//   - OAuth integration with GitHub, Google, Apple and Microsoft providers (extensible to other providers)
 //   - User management operations including password changes and email updates
 //
 // # Architecture Overview
@@ -55,6 +56,41 @@
 //		// Handle authentication errors (invalid credentials, etc.)
 //	}
 //
+// Login attempts can be throttled with any LoginRateLimiter. Denied attempts return
+// ErrTooManyAttempts without touching storage or running bcrypt. Keys default to the
+// normalized email; WithLoginRateLimitKey selects LoginKeyByIP, LoginKeyByEmailAndIP
+// or a custom LoginKeyFunc:
+//
+//	passwordAuth := auth.NewPasswordService(storage, tokenSecret,
+//		auth.WithLoginRateLimiter(limiter),
+//		auth.WithLoginRateLimitKey(auth.LoginKeyByEmailAndIP),
+//	)
+//
+// WithBreachChecker rejects passwords known from data breaches with ErrPasswordBreached
+// in Register and ResetPassword; WithUserBreachChecker does the same for ChangePassword.
+// HaveIBeenPwnedChecker queries the Pwned Passwords range API with k-anonymity, sending
+// only the first 5 hex characters of the password's SHA-1 hash:
+//
+//	passwordAuth := auth.NewPasswordService(storage, tokenSecret,
+//		auth.WithBreachChecker(auth.NewHaveIBeenPwnedChecker()),
+//	)
+//
+// WithMaxFailedAttempts(n) locks an account after n consecutive failed logins, returning
+// ErrAccountLocked until WithLockoutDuration (15 minutes by default) has passed since the
+// last failure. The storage must also implement LoginAttemptStore. Each attempt is counted
+// with an atomic RecordFailedLogin before its result is revealed, so concurrent guesses
+// can't exceed the limit. The password is still compared while locked, so timing doesn't
+// reveal the lockout.
+//
+// Passwords are hashed with bcrypt unless WithHasher selects another Hasher, such as
+// Argon2idHasher. The hash format is detected on verification, so existing bcrypt and
+// argon2id hashes keep working, and Authenticate transparently rehashes them with the
+// configured hasher through StorePasswordHash on the next successful login:
+//
+//	passwordAuth := auth.NewPasswordService(storage, tokenSecret,
+//		auth.WithHasher(auth.NewArgon2idHasher()),
+//	)
+//
 // # Magic Link Authentication
 //
 // Magic link authentication enables passwordless login through secure email tokens:
@@ -84,7 +120,7 @@
 //
 // # OAuth Authentication
 //
-// OAuth authentication supports GitHub and Google providers with an extensible adapter pattern:
+// OAuth authentication supports GitHub, Google, Apple and Microsoft providers with an extensible adapter pattern:
 //
 //	// Configure GitHub OAuth
 //	githubConfig := auth.GitHubOAuthConfig{
@@ -126,6 +162,50 @@
 //		// Handle linking errors (already linked, etc.)
 //	}
 //
+// Provider tokens are discarded after login unless WithProviderTokens is set.
+// It persists them through a ProviderTokenStorage, encrypted with pkg/secrets,
+// and enables RefreshProviderToken (OAuthProviderTokenRefresher) for later provider API calls:
+//
+//	oauthService := auth.NewOAuthService(storage, githubAdapter,
+//		auth.WithProviderTokens(tokenStorage, keyProvider),
+//	)
+//	refresher := oauthService.(auth.OAuthProviderTokenRefresher)
+//	accessToken, err := refresher.RefreshProviderToken(ctx, userID, auth.OAuthProviderGithub)
+//
+// NewAppleAdapter signs client secrets with the configured .p8 key. Apple posts the
+// callback in form_post mode and shares the user's name only on first sign in;
+// ParseAppleCallback reads the form and its Context carries the name to the adapter:
+//
+//	cb, err := auth.ParseAppleCallback(r)
+//	user, err := appleService.Auth(cb.Context(r.Context()), cb.Code, cb.State, nil)
+//
+// Apple private relay emails are verified when Apple says so. NewMicrosoftAdapter uses
+// the tenant-scoped Entra ID endpoints and marks work account emails verified only
+// when the optional xms_edov claim says so.
+//
+// # Passkey Authentication
+//
+// PasskeyService implements WebAuthn registration and login. Begin methods return
+// the options to pass to navigator.credentials.create() or get() as JSON, and
+// Finish methods verify the browser's PublicKeyCredential.toJSON() output:
+//
+//	passkeyAuth := auth.NewPasskeyService(storage, auth.RelyingParty{
+//		ID:      "example.com",
+//		Name:    "Example",
+//		Origins: []string{"https://app.example.com"},
+//	})
+//
+//	opts, err := passkeyAuth.BeginRegistration(ctx, userID) // signed-in user
+//	passkey, err := passkeyAuth.FinishRegistration(ctx, userID, registrationResponse)
+//
+//	opts, err = passkeyAuth.BeginLogin(ctx, "") // any discoverable passkey
+//	user, err := passkeyAuth.FinishLogin(ctx, assertionResponse)
+//
+// Challenges are single-use and consumed before verification. Signatures are
+// checked against the stored public key (ES256, EdDSA or RS256), and a sign
+// counter that doesn't increase is rejected with ErrPasskeyCloned, as it means
+// the authenticator's key may have been copied.
+//
 // # User Management
 //
 // User management provides secure operations for account maintenance:
@@ -139,8 +219,8 @@
 //			return nil
 //		}),
 //		auth.WithAfterUpdate(func(ctx context.Context, user *auth.User) error {
-//			// Invalidate sessions, send notification, etc.
-//			return nil
+//			// Sign the user out everywhere (session.Manager), send notification, etc.
+//			return sessionManager.RevokeByUserID(ctx, user.ID)
 //		}),
 //	)
 //
@@ -162,6 +242,50 @@
 //		// Handle confirmation errors
 //	}
 //
+// WithPasswordHistory(n) makes ChangePassword reject the last n passwords, the
+// current one included, with ErrPasswordReused; WithResetPasswordHistory(n) does
+// the same for ResetPassword. The storage must also implement
+// PasswordHistoryStore, which receives the replaced hash after every change.
+// Each candidate is compared with bcrypt against every stored hash, so large
+// histories make password changes noticeably slower.
+//
+// # Token Issuance
+//
+// A TokenIssuer turns an authenticated user into an access+refresh token pair
+// using pkg/jwt. Refresh tokens are tracked in a RefreshStore and rotated on
+// every exchange, so a replayed refresh token fails with ErrTokenAlreadyUsed:
+//
+//	jwtSvc, _ := jwt.NewFromString(cfg.JWTSecret)
+//	issuer := auth.NewTokenIssuer(jwtSvc, refreshStore,
+//		auth.WithAccessTokenTTL(15*time.Minute),
+//		auth.WithRefreshTokenTTL(30*24*time.Hour),
+//	)
+//
+//	passwordAuth := auth.NewPasswordService(storage, tokenSecret,
+//		auth.WithPasswordTokenIssuer(issuer),
+//	)
+//
+//	tokenAuth := passwordAuth.(auth.PasswordTokenAuthenticator)
+//	user, tokens, err := tokenAuth.AuthenticateWithTokens(ctx, email, password)
+//	// Later: rotate the pair
+//	tokens, err = issuer.Refresh(ctx, tokens.RefreshToken)
+//
+// Magic link and OAuth services implement MagicLinkTokenAuthenticator and
+// OAuthTokenAuthenticator, configured with WithMagicLinkTokenIssuer and WithOAuthTokenIssuer.
+//
+// # Step-Up Authentication
+//
+// RequireRecentAuth returns ErrReauthRequired unless the user authenticated within
+// maxAge. The authentication time comes from the context, set by middleware with
+// WithAuthTime from the auth_at token claim (TokenClaims.AuthTime) or the session.
+// Issued tokens set auth_at at login and keep it across refreshes, so only
+// Reauthenticate or ReauthenticateWithTokens (PasswordReauthenticator) make it
+// recent again. Both go through the login rate limiter and account lockout:
+//
+//	if err := auth.RequireRecentAuth(ctx, userID.String(), 5*time.Minute); err != nil {
+//		return err // client prompts for the password, then calls the reauth endpoint
+//	}
+//
 // # Error Handling
 //
 // The package defines specific error types for different failure scenarios, enabling precise
@@ -172,6 +296,10 @@
 //		switch {
 //		case errors.Is(err, auth.ErrInvalidCredentials):
 //			// Show generic "invalid email or password" message
+//		case errors.Is(err, auth.ErrAccountLocked):
+//			// Too many failed logins, ask the user to retry later or reset the password
+//		case errors.Is(err, auth.ErrTooManyAttempts):
+//			// Login throttled, ask the user to retry later
 //		case errors.Is(err, auth.ErrUserNotFound):
 //			// User doesn't exist, might suggest registration
 //		case errors.Is(err, auth.ErrTokenExpired):
@@ -185,11 +313,14 @@
 //
 // The package implements several security best practices:
 //
-//   - Password hashing with bcrypt and configurable cost factors
+//   - Password hashing with bcrypt or argon2id and transparent rehash-on-login upgrades
 //   - CSRF protection for OAuth flows using cryptographically secure state tokens
-//   - JWT tokens with expiration and replay protection for magic links
+//   - Signed, strictly single-use magic link tokens with expiration
 //   - Email normalization to prevent duplicate accounts
 //   - Timing attack prevention in authentication flows
+//   - Pluggable login rate limiting against brute-force attacks
+//   - Breached password rejection without disclosing the password hash
+//   - Temporary account lockout after repeated failed logins
 //   - Secure token generation using crypto/rand
 //   - Input validation and sanitization
 //
@@ -215,6 +346,11 @@
 //
 //	// Implement remaining PasswordStorage methods...
 //
+// MagicLinkStorage.ConsumeToken must be atomic: concurrent verifications of the
+// same link must succeed exactly once, the rest returning ErrTokenAlreadyUsed.
+// Verification fails closed on any other storage error. MemoryTokenStore is an
+// in-memory reference implementation; in Redis use SET NX with the given TTL.
+//
 // # Provider Extension
 //
 // New OAuth providers can be added by implementing the ProviderAdapter interface:
@@ -236,15 +372,21 @@
 //		return auth.ProviderProfile{}, nil
 //	}
 //
+// Adapters that set ProviderProfile.Token and implement TokenRefresher support
+// RefreshProviderToken.
+//
 // # Constants
 //
 // The package exports constants for authentication methods and token subjects:
 //
 //	// Authentication method identifiers
-//	auth.MethodPassword    // "password"
-//	auth.MethodMagicLink   // "magic_link"
-//	auth.MethodOAuthGoogle // "oauth_google"
-//	auth.MethodOAuthGithub // "oauth_github"
+//	auth.MethodPassword       // "password"
+//	auth.MethodMagicLink      // "magic_link"
+//	auth.MethodOAuthGoogle    // "oauth_google"
+//	auth.MethodOAuthGithub    // "oauth_github"
+//	auth.MethodOAuthApple     // "oauth_apple"
+//	auth.MethodOAuthMicrosoft // "oauth_microsoft"
+//	auth.MethodPasskey        // "passkey"
 //
 //	// JWT token subjects
 //	auth.SubjectPasswordReset // "password_reset"
@@ -253,8 +395,10 @@
 //	auth.SubjectMagicLink     // "magic_link"
 //
 //	// OAuth provider identifiers
-//	auth.OAuthProviderGoogle // "google"
-//	auth.OAuthProviderGithub // "github"
+//	auth.OAuthProviderGoogle    // "google"
+//	auth.OAuthProviderGithub    // "github"
+//	auth.OAuthProviderApple     // "apple"
+//	auth.OAuthProviderMicrosoft // "microsoft"
 //
 // # Thread Safety
 //
@@ -271,5 +415,6 @@
 //   - github.com/dmitrymomot/saaskit/pkg/validator for input validation
 //   - github.com/dmitrymomot/saaskit/pkg/sanitizer for data normalization
 //   - github.com/dmitrymomot/saaskit/pkg/token for JWT token handling
+//   - github.com/dmitrymomot/saaskit/pkg/jwt for access and refresh tokens
 //   - github.com/dmitrymomot/saaskit/pkg/logger for structured logging
 package auth
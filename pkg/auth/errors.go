@@ -8,6 +8,9 @@ var (
 	ErrEmailAlreadyExists = errors.New("email already exists")
 	ErrInvalidCredentials = errors.New("invalid credentials")
 	ErrUnauthorized       = errors.New("unauthorized")
+	ErrReauthRequired     = errors.New("recent authentication required")
+	ErrTooManyAttempts    = errors.New("too many login attempts")
+	ErrAccountLocked      = errors.New("account temporarily locked")
 )
 
 // Token-related errors
@@ -15,6 +18,8 @@ var (
 	ErrTokenExpired  = errors.New("token expired")
 	ErrTokenInvalid  = errors.New("invalid token")
 	ErrTokenNotFound = errors.New("token not found")
+
+	ErrTokenIssuerNotConfigured = errors.New("token issuer not configured")
 )
 
 // Password-specific errors
@@ -22,6 +27,9 @@ var (
 	ErrWeakPassword     = errors.New("password does not meet security requirements")
 	ErrPasswordMismatch = errors.New("passwords do not match")
 	ErrPasswordRequired = errors.New("password is required")
+	ErrPasswordReused   = errors.New("password was used recently")
+	ErrPasswordBreached = errors.New("password found in a data breach")
+	ErrUnsupportedHash  = errors.New("unsupported password hash format")
 )
 
 // OAuth-specific errors
@@ -34,6 +42,13 @@ var (
 	ErrUnverifiedEmail    = errors.New("email not verified by provider")
 	ErrNoPrimaryEmail     = errors.New("no primary email from provider")
 	ErrProviderEmailInUse = errors.New("email from provider already registered")
+
+	ErrProviderTokensNotConfigured = errors.New("provider token storage not configured")
+	ErrProviderTokenNotFound       = errors.New("provider token not found")
+	ErrProviderMismatch            = errors.New("provider does not match OAuth service")
+	ErrNoRefreshToken              = errors.New("no refresh token from provider")
+	ErrTokenRefreshNotSupported    = errors.New("provider does not support token refresh")
+	ErrProviderTokenRefreshFailed  = errors.New("failed to refresh provider token")
 )
 
 // Magic link errors
@@ -43,6 +58,16 @@ var (
 	ErrTokenAlreadyUsed = errors.New("token already used")
 )
 
+// Passkey errors
+var (
+	ErrPasskeyNotFound             = errors.New("passkey not found")
+	ErrPasskeyAlreadyRegistered    = errors.New("passkey already registered")
+	ErrPasskeyChallengeNotFound    = errors.New("passkey challenge not found or expired")
+	ErrInvalidPasskeyResponse      = errors.New("invalid passkey response")
+	ErrUnsupportedPasskeyAlgorithm = errors.New("unsupported passkey algorithm")
+	ErrPasskeyCloned               = errors.New("passkey sign counter regressed, authenticator may be cloned")
+)
+
 // User management errors
 var (
 	ErrEmailUnchanged   = errors.New("email unchanged")
@@ -0,0 +1,288 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Hasher hashes passwords for storage and verifies them.
+// Compare returns ErrInvalidCredentials if the password doesn't match and
+// ErrUnsupportedHash if the hash wasn't produced by this hasher's scheme.
+type Hasher interface {
+	Hash(password string) (string, error)
+	Compare(hash, password string) error
+}
+
+// RehashChecker is implemented by hashers that can tell when a hash in their own
+// scheme was made with outdated parameters, such as a lower bcrypt cost.
+type RehashChecker interface {
+	NeedsRehash(hash string) bool
+}
+
+// WithHasher configures the password hasher. Defaults to bcrypt with the WithBcryptCost cost.
+// Hashes from the built-in bcrypt and argon2id schemes keep verifying after a switch,
+// and Authenticate rehashes them with the new hasher on the next successful login.
+func WithHasher(hasher Hasher) PasswordOption {
+	return func(s *passwordService) {
+		s.hasher = hasher
+	}
+}
+
+// WithUserHasher configures the hasher used by ChangePassword. Use the same hasher as
+// the password service; hashes from built-in schemes keep verifying after a switch.
+func WithUserHasher(hasher Hasher) UserOption {
+	return func(s *userService) {
+		s.hasher = hasher
+	}
+}
+
+// verifyPassword compares password against hash. Hashes the configured hasher doesn't
+// support are verified with the built-in scheme they were made with. needsRehash reports
+// whether a matching hash should be replaced with one from hasher.
+func verifyPassword(hasher Hasher, hash, password string) (needsRehash bool, err error) {
+	err = hasher.Compare(hash, password)
+	if errors.Is(err, ErrUnsupportedHash) {
+		legacy := builtinHasherFor(hash)
+		if legacy == nil {
+			return false, err
+		}
+		if err := legacy.Compare(hash, password); err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if checker, ok := hasher.(RehashChecker); ok {
+		return checker.NeedsRehash(hash), nil
+	}
+	return false, nil
+}
+
+// builtinHasherFor detects the scheme of hash. Comparison only needs the parameters
+// encoded in the hash, so default-configured hashers are enough.
+func builtinHasherFor(hash string) Hasher {
+	switch {
+	case isBcryptHash(hash):
+		return NewBcryptHasher(bcrypt.DefaultCost)
+	case strings.HasPrefix(hash, argon2idPrefix):
+		return NewArgon2idHasher()
+	}
+	return nil
+}
+
+// BcryptHasher hashes passwords with bcrypt. Passwords longer than 72 bytes are rejected.
+type BcryptHasher struct {
+	cost int
+}
+
+// NewBcryptHasher creates a bcrypt hasher. Costs outside bcrypt's range fall back to bcrypt.DefaultCost.
+func NewBcryptHasher(cost int) *BcryptHasher {
+	if cost < bcrypt.MinCost || cost > bcrypt.MaxCost {
+		cost = bcrypt.DefaultCost
+	}
+	return &BcryptHasher{cost: cost}
+}
+
+// Hash returns the bcrypt hash of password.
+func (h *BcryptHasher) Hash(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), h.cost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// Compare checks password against a bcrypt hash.
+func (h *BcryptHasher) Compare(hash, password string) error {
+	if !isBcryptHash(hash) {
+		return ErrUnsupportedHash
+	}
+	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+	if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+		return ErrInvalidCredentials
+	}
+	if err != nil {
+		return errors.Join(ErrUnsupportedHash, err)
+	}
+	return nil
+}
+
+// NeedsRehash reports whether hash isn't bcrypt or uses a different cost.
+func (h *BcryptHasher) NeedsRehash(hash string) bool {
+	cost, err := bcrypt.Cost([]byte(hash))
+	return err != nil || cost != h.cost
+}
+
+func isBcryptHash(hash string) bool {
+	return strings.HasPrefix(hash, "$2a$") || strings.HasPrefix(hash, "$2b$") || strings.HasPrefix(hash, "$2y$")
+}
+
+// Default argon2id parameters, following RFC 9106 with a lower parallelism for shared servers.
+const (
+	DefaultArgon2Memory      = 64 * 1024 // KiB
+	DefaultArgon2Iterations  = 3
+	DefaultArgon2Parallelism = 2
+)
+
+const (
+	argon2idPrefix    = "$argon2id$"
+	argon2SaltLength  = 16
+	argon2KeyLength   = 32
+	argon2MaxMemory   = 4 * 1024 * 1024 // KiB; bounds the cost of verifying a tampered hash
+	argon2MaxKeyBytes = 1024
+)
+
+// Argon2idHasher hashes passwords with argon2id and encodes them in the PHC string format:
+// $argon2id$v=19$m=65536,t=3,p=2$<salt>$<key>.
+type Argon2idHasher struct {
+	memory      uint32
+	iterations  uint32
+	parallelism uint8
+}
+
+// Argon2idOption configures an Argon2idHasher.
+type Argon2idOption func(*Argon2idHasher)
+
+// WithArgon2Memory sets the memory cost in KiB.
+func WithArgon2Memory(kib uint32) Argon2idOption {
+	return func(h *Argon2idHasher) {
+		if kib > 0 {
+			h.memory = kib
+		}
+	}
+}
+
+// WithArgon2Iterations sets the number of passes over the memory.
+func WithArgon2Iterations(n uint32) Argon2idOption {
+	return func(h *Argon2idHasher) {
+		if n > 0 {
+			h.iterations = n
+		}
+	}
+}
+
+// WithArgon2Parallelism sets the number of lanes (and goroutines) used per hash.
+func WithArgon2Parallelism(p uint8) Argon2idOption {
+	return func(h *Argon2idHasher) {
+		if p > 0 {
+			h.parallelism = p
+		}
+	}
+}
+
+// NewArgon2idHasher creates an argon2id hasher with the Default* parameters unless overridden.
+func NewArgon2idHasher(opts ...Argon2idOption) *Argon2idHasher {
+	h := &Argon2idHasher{
+		memory:      DefaultArgon2Memory,
+		iterations:  DefaultArgon2Iterations,
+		parallelism: DefaultArgon2Parallelism,
+	}
+
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	return h
+}
+
+// Hash returns the PHC-encoded argon2id hash of password with a random salt.
+func (h *Argon2idHasher) Hash(password string) (string, error) {
+	salt := make([]byte, argon2SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("failed to generate salt: %w", err)
+	}
+
+	key := argon2.IDKey([]byte(password), salt, h.iterations, h.memory, h.parallelism, argon2KeyLength)
+	return fmt.Sprintf("%sv=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2idPrefix, argon2.Version, h.memory, h.iterations, h.parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+// Compare checks password against a PHC-encoded argon2id hash using the parameters stored in it.
+func (h *Argon2idHasher) Compare(hash, password string) error {
+	p, err := parseArgon2idHash(hash)
+	if err != nil {
+		return err
+	}
+
+	key := argon2.IDKey([]byte(password), p.salt, p.iterations, p.memory, p.parallelism, uint32(len(p.key)))
+	if subtle.ConstantTimeCompare(key, p.key) != 1 {
+		return ErrInvalidCredentials
+	}
+	return nil
+}
+
+// NeedsRehash reports whether hash isn't argon2id or uses different parameters.
+func (h *Argon2idHasher) NeedsRehash(hash string) bool {
+	p, err := parseArgon2idHash(hash)
+	return err != nil ||
+		p.memory != h.memory ||
+		p.iterations != h.iterations ||
+		p.parallelism != h.parallelism ||
+		len(p.key) != argon2KeyLength
+}
+
+type argon2idParams struct {
+	memory      uint32
+	iterations  uint32
+	parallelism uint8
+	salt        []byte
+	key         []byte
+}
+
+func parseArgon2idHash(hash string) (argon2idParams, error) {
+	if !strings.HasPrefix(hash, argon2idPrefix) {
+		return argon2idParams{}, ErrUnsupportedHash
+	}
+
+	malformed := fmt.Errorf("%w: malformed argon2id hash", ErrUnsupportedHash)
+
+	// "", "argon2id", "v=19", "m=...,t=...,p=...", salt, key
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 {
+		return argon2idParams{}, malformed
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return argon2idParams{}, malformed
+	}
+
+	var p argon2idParams
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &p.memory, &p.iterations, &p.parallelism); err != nil {
+		return argon2idParams{}, malformed
+	}
+	if p.memory == 0 || p.memory > argon2MaxMemory || p.iterations == 0 || p.parallelism == 0 {
+		return argon2idParams{}, malformed
+	}
+
+	var err error
+	if p.salt, err = base64.RawStdEncoding.DecodeString(parts[4]); err != nil {
+		return argon2idParams{}, malformed
+	}
+	if p.key, err = base64.RawStdEncoding.DecodeString(parts[5]); err != nil || len(p.key) == 0 || len(p.key) > argon2MaxKeyBytes {
+		return argon2idParams{}, malformed
+	}
+
+	return p, nil
+}
+
+// Compile-time interface assertion
+var (
+	_ Hasher        = (*BcryptHasher)(nil)
+	_ RehashChecker = (*BcryptHasher)(nil)
+	_ Hasher        = (*Argon2idHasher)(nil)
+	_ RehashChecker = (*Argon2idHasher)(nil)
+)
@@ -0,0 +1,225 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// newTestArgon2idHasher keeps argon2id cheap enough for unit tests.
+func newTestArgon2idHasher(opts ...Argon2idOption) *Argon2idHasher {
+	opts = append([]Argon2idOption{WithArgon2Memory(64), WithArgon2Iterations(1), WithArgon2Parallelism(1)}, opts...)
+	return NewArgon2idHasher(opts...)
+}
+
+func TestBcryptHasher(t *testing.T) {
+	t.Parallel()
+
+	h := NewBcryptHasher(bcrypt.MinCost)
+	hash, err := h.Hash("secret-password")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(hash, "$2a$"))
+
+	assert.NoError(t, h.Compare(hash, "secret-password"))
+	assert.ErrorIs(t, h.Compare(hash, "wrong-password"), ErrInvalidCredentials)
+	assert.ErrorIs(t, h.Compare("$argon2id$v=19$m=64,t=1,p=1$c2FsdA$a2V5", "secret-password"), ErrUnsupportedHash)
+
+	assert.False(t, h.NeedsRehash(hash))
+	assert.True(t, NewBcryptHasher(bcrypt.MinCost+1).NeedsRehash(hash))
+	assert.Equal(t, bcrypt.DefaultCost, NewBcryptHasher(0).cost)
+
+	_, err = h.Hash(strings.Repeat("a", 73))
+	assert.Error(t, err, "bcrypt rejects passwords over 72 bytes")
+}
+
+func TestArgon2idHasher(t *testing.T) {
+	t.Parallel()
+
+	h := newTestArgon2idHasher()
+	hash, err := h.Hash(strings.Repeat("long password ", 10))
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(hash, "$argon2id$v=19$m=64,t=1,p=1$"), hash)
+
+	assert.NoError(t, h.Compare(hash, strings.Repeat("long password ", 10)))
+	assert.ErrorIs(t, h.Compare(hash, strings.Repeat("long password ", 9)), ErrInvalidCredentials)
+
+	again, err := h.Hash(strings.Repeat("long password ", 10))
+	require.NoError(t, err)
+	assert.NotEqual(t, hash, again, "salt is random")
+
+	t.Run("compares with parameters from the hash", func(t *testing.T) {
+		t.Parallel()
+
+		other := newTestArgon2idHasher(WithArgon2Iterations(2))
+		assert.NoError(t, other.Compare(hash, strings.Repeat("long password ", 10)))
+		assert.True(t, other.NeedsRehash(hash))
+		assert.False(t, h.NeedsRehash(hash))
+	})
+
+	t.Run("rejects other and malformed hashes", func(t *testing.T) {
+		t.Parallel()
+
+		bcryptHash, err := bcrypt.GenerateFromPassword([]byte("pw"), bcrypt.MinCost)
+		require.NoError(t, err)
+
+		for _, bad := range []string{
+			string(bcryptHash),
+			"$argon2id$v=19$m=64,t=1,p=1$c2FsdA",
+			"$argon2id$v=16$m=64,t=1,p=1$c2FsdA$a2V5",
+			"$argon2id$v=19$m=0,t=1,p=1$c2FsdA$a2V5",
+			"$argon2id$v=19$m=64,t=1,p=1$!!!$a2V5",
+			"$argon2id$v=19$m=64,t=1,p=1$c2FsdA$",
+			"$argon2id$v=19$m=999999999,t=1,p=1$c2FsdA$a2V5",
+		} {
+			assert.ErrorIs(t, h.Compare(bad, "pw"), ErrUnsupportedHash, bad)
+			assert.True(t, h.NeedsRehash(bad), bad)
+		}
+	})
+}
+
+func TestVerifyPassword(t *testing.T) {
+	t.Parallel()
+
+	bcryptHash, err := NewBcryptHasher(bcrypt.MinCost).Hash("password")
+	require.NoError(t, err)
+	argonHash, err := newTestArgon2idHasher().Hash("password")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		hasher      Hasher
+		hash        string
+		password    string
+		needsRehash bool
+		err         error
+	}{
+		{"current scheme", newTestArgon2idHasher(), argonHash, "password", false, nil},
+		{"legacy bcrypt", newTestArgon2idHasher(), bcryptHash, "password", true, nil},
+		{"legacy argon2id", NewBcryptHasher(bcrypt.MinCost), argonHash, "password", true, nil},
+		{"outdated bcrypt cost", NewBcryptHasher(bcrypt.MinCost + 1), bcryptHash, "password", true, nil},
+		{"legacy mismatch", newTestArgon2idHasher(), bcryptHash, "wrong", false, ErrInvalidCredentials},
+		{"unknown scheme", newTestArgon2idHasher(), "$pbkdf2$whatever", "password", false, ErrUnsupportedHash},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			needsRehash, err := verifyPassword(tt.hasher, tt.hash, tt.password)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.needsRehash, needsRehash)
+		})
+	}
+}
+
+func TestPasswordService_RehashOnLogin(t *testing.T) {
+	t.Parallel()
+
+	const (
+		tokenSecret = "test-secret-32-chars-long-12345"
+		email       = "user@example.com"
+		password    = "correct-password"
+	)
+
+	legacyHash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	require.NoError(t, err)
+
+	t.Run("upgrades legacy hash", func(t *testing.T) {
+		t.Parallel()
+
+		hasher := newTestArgon2idHasher()
+		storage := &MockPasswordStorage{}
+		user := &User{ID: uuid.New(), Email: email}
+		storage.On("GetUserByEmail", mock.Anything, email).Return(user, nil)
+		storage.On("GetPasswordHash", mock.Anything, user.ID).Return(legacyHash, nil)
+
+		var upgraded string
+		storage.On("StorePasswordHash", mock.Anything, user.ID, mock.Anything).
+			Run(func(args mock.Arguments) { upgraded = string(args.Get(2).([]byte)) }).
+			Return(nil).Once()
+
+		svc := NewPasswordService(storage, tokenSecret, WithHasher(hasher))
+		_, err := svc.Authenticate(context.Background(), email, password)
+		require.NoError(t, err)
+
+		assert.True(t, strings.HasPrefix(upgraded, "$argon2id$"))
+		assert.NoError(t, hasher.Compare(upgraded, password))
+		storage.AssertExpectations(t)
+	})
+
+	t.Run("wrong password doesn't upgrade", func(t *testing.T) {
+		t.Parallel()
+
+		storage := &MockPasswordStorage{}
+		user := &User{ID: uuid.New(), Email: email}
+		storage.On("GetUserByEmail", mock.Anything, email).Return(user, nil)
+		storage.On("GetPasswordHash", mock.Anything, user.ID).Return(legacyHash, nil)
+
+		svc := NewPasswordService(storage, tokenSecret, WithHasher(newTestArgon2idHasher()))
+		_, err := svc.Authenticate(context.Background(), email, "wrong-password")
+		assert.ErrorIs(t, err, ErrInvalidCredentials)
+		storage.AssertNotCalled(t, "StorePasswordHash", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("upgrade failure doesn't fail the login", func(t *testing.T) {
+		t.Parallel()
+
+		storage := &MockPasswordStorage{}
+		user := &User{ID: uuid.New(), Email: email}
+		storage.On("GetUserByEmail", mock.Anything, email).Return(user, nil)
+		storage.On("GetPasswordHash", mock.Anything, user.ID).Return(legacyHash, nil)
+		storage.On("StorePasswordHash", mock.Anything, user.ID, mock.Anything).Return(errors.New("db down"))
+
+		svc := NewPasswordService(storage, tokenSecret, WithHasher(newTestArgon2idHasher()))
+		got, err := svc.Authenticate(context.Background(), email, password)
+		require.NoError(t, err)
+		assert.Equal(t, user.ID, got.ID)
+	})
+
+	t.Run("register uses configured hasher", func(t *testing.T) {
+		t.Parallel()
+
+		storage := &MockPasswordStorage{}
+		storage.On("GetUserByEmail", mock.Anything, email).Return(nil, ErrUserNotFound)
+		storage.On("CreateUser", mock.Anything, mock.Anything).Return(nil)
+		storage.On("StorePasswordHash", mock.Anything, mock.Anything, mock.MatchedBy(func(hash []byte) bool {
+			return strings.HasPrefix(string(hash), "$argon2id$")
+		})).Return(nil)
+
+		svc := NewPasswordService(storage, tokenSecret, WithHasher(newTestArgon2idHasher()))
+		_, err := svc.Register(context.Background(), email, "Str0ng-Password!")
+		require.NoError(t, err)
+		storage.AssertExpectations(t)
+	})
+}
+
+func TestUserService_ChangePasswordWithHasher(t *testing.T) {
+	t.Parallel()
+
+	const currentPassword = "CurrentPassword1!"
+	legacyHash, err := bcrypt.GenerateFromPassword([]byte(currentPassword), bcrypt.MinCost)
+	require.NoError(t, err)
+
+	storage := &MockUserStorage{}
+	userID := uuid.New()
+	storage.On("GetUserByID", mock.Anything, userID).Return(&User{ID: userID}, nil)
+	storage.On("GetPasswordHash", mock.Anything, userID).Return(legacyHash, nil)
+	storage.On("UpdatePasswordHash", mock.Anything, userID, mock.MatchedBy(func(hash []byte) bool {
+		return strings.HasPrefix(string(hash), "$argon2id$")
+	})).Return(nil)
+
+	svc := NewUserService(storage, "test-secret", WithUserHasher(newTestArgon2idHasher()))
+	require.NoError(t, svc.ChangePassword(context.Background(), userID, currentPassword, "FreshPassword33!"))
+	storage.AssertExpectations(t)
+}
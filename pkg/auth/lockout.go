@@ -0,0 +1,80 @@
+package auth
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/dmitrymomot/saaskit/pkg/logger"
+)
+
+// LoginAttemptStore persists consecutive failed logins so lockouts survive restarts.
+// PasswordStorage implementations opt in by implementing it when WithMaxFailedAttempts is used.
+type LoginAttemptStore interface {
+	// GetFailedLogins returns the number of consecutive failed logins and the time of the latest one.
+	// Users without failures return 0 and a zero time.
+	GetFailedLogins(ctx context.Context, userID uuid.UUID) (count int, lastFailedAt time.Time, err error)
+	// RecordFailedLogin increments the counter, stores at as the latest failure and returns the new count.
+	// The increment and read must be atomic (e.g. INCR in Redis, UPDATE ... RETURNING in SQL):
+	// every attempt is recorded before its password check is revealed, and the returned count
+	// decides whether concurrent attempts are answered.
+	RecordFailedLogin(ctx context.Context, userID uuid.UUID, at time.Time) (int, error)
+	// ClearFailedLogins resets the counter after a successful login or an expired lockout.
+	ClearFailedLogins(ctx context.Context, userID uuid.UUID) error
+}
+
+// DefaultLockoutDuration is how long an account stays locked when WithLockoutDuration isn't set.
+const DefaultLockoutDuration = 15 * time.Minute
+
+// WithMaxFailedAttempts locks an account after n consecutive failed logins, each within
+// the lockout duration of the previous one. Locked accounts fail Authenticate with
+// ErrAccountLocked until the lockout duration has passed since the last failure.
+// The storage passed to NewPasswordService must implement LoginAttemptStore.
+func WithMaxFailedAttempts(n int) PasswordOption {
+	return func(s *passwordService) {
+		s.maxFailedAttempts = n
+	}
+}
+
+// WithLockoutDuration configures how long an account stays locked. Defaults to DefaultLockoutDuration.
+func WithLockoutDuration(d time.Duration) PasswordOption {
+	return func(s *passwordService) {
+		if d > 0 {
+			s.lockoutDuration = d
+		}
+	}
+}
+
+// failedLogins returns the user's current failure count, treating failures older than
+// the lockout duration as expired. Only called when lockout is enabled.
+func (s *passwordService) failedLogins(ctx context.Context, userID uuid.UUID, now time.Time) (int, error) {
+	count, lastFailedAt, err := s.storage.(LoginAttemptStore).GetFailedLogins(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+	if count > 0 && now.Sub(lastFailedAt) >= s.lockoutDuration {
+		if err := s.storage.(LoginAttemptStore).ClearFailedLogins(ctx, userID); err != nil {
+			return 0, err
+		}
+		return 0, nil
+	}
+	return count, nil
+}
+
+// recordFailedLogin counts a login attempt and returns the new count. Successful attempts
+// are counted too and cleared afterwards, so concurrent guesses can't outrun the lockout.
+func (s *passwordService) recordFailedLogin(ctx context.Context, userID uuid.UUID, now time.Time) (int, error) {
+	return s.storage.(LoginAttemptStore).RecordFailedLogin(ctx, userID, now)
+}
+
+// clearFailedLogins resets the counter after a successful login.
+func (s *passwordService) clearFailedLogins(ctx context.Context, userID uuid.UUID) {
+	if err := s.storage.(LoginAttemptStore).ClearFailedLogins(ctx, userID); err != nil {
+		s.logger.Error("failed to clear failed logins",
+			logger.UserID(userID.String()),
+			logger.Error(err),
+			logger.Component("password"),
+		)
+	}
+}
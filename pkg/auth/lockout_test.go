@@ -0,0 +1,172 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// lockoutStorage is a PasswordStorage that keeps failed logins in memory.
+type lockoutStorage struct {
+	MockPasswordStorage
+
+	mu           sync.Mutex
+	count        int
+	lastFailedAt time.Time
+	getErr       error
+	cleared      int
+}
+
+func (s *lockoutStorage) GetFailedLogins(_ context.Context, _ uuid.UUID) (int, time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.count, s.lastFailedAt, s.getErr
+}
+
+func (s *lockoutStorage) RecordFailedLogin(_ context.Context, _ uuid.UUID, at time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.count++
+	s.lastFailedAt = at
+	return s.count, nil
+}
+
+func (s *lockoutStorage) ClearFailedLogins(_ context.Context, _ uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.count, s.lastFailedAt = 0, time.Time{}
+	s.cleared++
+	return nil
+}
+
+func TestPasswordService_Lockout(t *testing.T) {
+	t.Parallel()
+
+	const (
+		tokenSecret = "test-secret-32-chars-long-12345"
+		email       = "user@example.com"
+		password    = "correct-password"
+	)
+
+	setup := func(t *testing.T, opts ...PasswordOption) (*lockoutStorage, PasswordAuthenticator) {
+		t.Helper()
+		hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+		require.NoError(t, err)
+
+		storage := &lockoutStorage{}
+		user := &User{ID: uuid.New(), Email: email}
+		storage.On("GetUserByEmail", mock.Anything, email).Return(user, nil)
+		storage.On("GetPasswordHash", mock.Anything, user.ID).Return(hash, nil)
+
+		opts = append([]PasswordOption{WithMaxFailedAttempts(3), WithLockoutDuration(time.Hour), WithBcryptCost(bcrypt.MinCost)}, opts...)
+		return storage, NewPasswordService(storage, tokenSecret, opts...)
+	}
+
+	t.Run("locks after max failed attempts", func(t *testing.T) {
+		t.Parallel()
+
+		storage, svc := setup(t)
+		ctx := context.Background()
+
+		for range 3 {
+			_, err := svc.Authenticate(ctx, email, "wrong-password")
+			assert.ErrorIs(t, err, ErrInvalidCredentials)
+		}
+
+		// Even the right password is refused while locked
+		_, err := svc.Authenticate(ctx, email, password)
+		assert.ErrorIs(t, err, ErrAccountLocked)
+
+		// Attempts during the lockout don't extend it
+		_, err = svc.Authenticate(ctx, email, "wrong-password")
+		assert.ErrorIs(t, err, ErrAccountLocked)
+		assert.Equal(t, 3, storage.count)
+	})
+
+	t.Run("lockout expires after the duration", func(t *testing.T) {
+		t.Parallel()
+
+		storage, svc := setup(t)
+		storage.count = 5
+		storage.lastFailedAt = time.Now().Add(-2 * time.Hour)
+
+		_, err := svc.Authenticate(context.Background(), email, "wrong-password")
+		assert.ErrorIs(t, err, ErrInvalidCredentials)
+		assert.Equal(t, 1, storage.count, "expired failures are reset before counting")
+	})
+
+	t.Run("successful login clears the counter", func(t *testing.T) {
+		t.Parallel()
+
+		storage, svc := setup(t)
+		ctx := context.Background()
+
+		_, err := svc.Authenticate(ctx, email, "wrong-password")
+		require.ErrorIs(t, err, ErrInvalidCredentials)
+
+		user, err := svc.Authenticate(ctx, email, password)
+		require.NoError(t, err)
+		assert.Equal(t, email, user.Email)
+		assert.Equal(t, 0, storage.count)
+		assert.Equal(t, 1, storage.cleared)
+	})
+
+	t.Run("concurrent guesses can't exceed the limit", func(t *testing.T) {
+		t.Parallel()
+
+		storage, svc := setup(t)
+
+		var wg sync.WaitGroup
+		results := make(chan error, 10)
+		for range 10 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := svc.Authenticate(context.Background(), email, "wrong-password")
+				results <- err
+			}()
+		}
+		wg.Wait()
+		close(results)
+
+		var answered int
+		for err := range results {
+			if errors.Is(err, ErrInvalidCredentials) {
+				answered++
+			}
+		}
+		assert.Equal(t, 3, answered, "only max attempts get an answer")
+		assert.GreaterOrEqual(t, storage.count, 3)
+	})
+
+	t.Run("storage errors fail the login", func(t *testing.T) {
+		t.Parallel()
+
+		storage, svc := setup(t)
+		storage.getErr = errors.New("db down")
+
+		_, err := svc.Authenticate(context.Background(), email, password)
+		assert.ErrorIs(t, err, storage.getErr)
+	})
+
+	t.Run("disabled by default", func(t *testing.T) {
+		t.Parallel()
+
+		storage := &MockPasswordStorage{}
+		assert.NotPanics(t, func() { NewPasswordService(storage, tokenSecret, WithLockoutDuration(time.Minute)) })
+	})
+
+	t.Run("requires LoginAttemptStore", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Panics(t, func() { NewPasswordService(&MockPasswordStorage{}, tokenSecret, WithMaxFailedAttempts(5)) })
+	})
+}
@@ -0,0 +1,158 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/dmitrymomot/saaskit/pkg/clientip"
+)
+
+// countingLimiter allows up to limit attempts per key and records the keys it saw.
+type countingLimiter struct {
+	mu    sync.Mutex
+	limit int
+	seen  map[string]int
+	err   error
+}
+
+func newCountingLimiter(limit int) *countingLimiter {
+	return &countingLimiter{limit: limit, seen: make(map[string]int)}
+}
+
+func (l *countingLimiter) Allow(_ context.Context, key string) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.err != nil {
+		return false, l.err
+	}
+	l.seen[key]++
+	return l.seen[key] <= l.limit, nil
+}
+
+func TestPasswordService_LoginRateLimit(t *testing.T) {
+	t.Parallel()
+
+	const tokenSecret = "test-secret-32-chars-long-12345"
+	email := "user@example.com"
+
+	t.Run("denied attempts skip storage and bcrypt", func(t *testing.T) {
+		t.Parallel()
+
+		storage := &MockPasswordStorage{}
+		user := &User{ID: uuid.New(), Email: email}
+		hash, err := bcrypt.GenerateFromPassword([]byte("correct-password"), bcrypt.MinCost)
+		require.NoError(t, err)
+
+		// Only the first, allowed attempt may reach storage
+		storage.On("GetUserByEmail", mock.Anything, email).Return(user, nil).Once()
+		storage.On("GetPasswordHash", mock.Anything, user.ID).Return(hash, nil).Once()
+
+		limiter := newCountingLimiter(1)
+		svc := NewPasswordService(storage, tokenSecret, WithLoginRateLimiter(limiter), WithBcryptCost(bcrypt.MinCost))
+
+		_, err = svc.Authenticate(context.Background(), email, "correct-password")
+		require.NoError(t, err)
+
+		_, err = svc.Authenticate(context.Background(), " USER@example.com ", "correct-password")
+		assert.ErrorIs(t, err, ErrTooManyAttempts)
+		assert.Equal(t, 2, limiter.seen["login:email:"+email])
+
+		storage.AssertExpectations(t)
+	})
+
+	t.Run("limiter runs before the beforeLogin hook", func(t *testing.T) {
+		t.Parallel()
+
+		storage := &MockPasswordStorage{}
+		hookCalled := false
+		svc := NewPasswordService(storage, tokenSecret,
+			WithLoginRateLimiter(newCountingLimiter(0)),
+			WithBeforeLogin(func(context.Context, string) error {
+				hookCalled = true
+				return nil
+			}),
+		)
+
+		_, err := svc.Authenticate(context.Background(), email, "password")
+		assert.ErrorIs(t, err, ErrTooManyAttempts)
+		assert.False(t, hookCalled)
+	})
+
+	t.Run("limiter errors block the login", func(t *testing.T) {
+		t.Parallel()
+
+		storage := &MockPasswordStorage{}
+		limiter := newCountingLimiter(10)
+		limiter.err = errors.New("redis down")
+		svc := NewPasswordService(storage, tokenSecret, WithLoginRateLimiter(limiter))
+
+		_, err := svc.Authenticate(context.Background(), email, "password")
+		require.Error(t, err)
+		assert.ErrorIs(t, err, limiter.err)
+		assert.NotErrorIs(t, err, ErrTooManyAttempts)
+		storage.AssertExpectations(t)
+	})
+
+	t.Run("custom key function", func(t *testing.T) {
+		t.Parallel()
+
+		storage := &MockPasswordStorage{}
+		storage.On("GetUserByEmail", mock.Anything, email).Return(nil, ErrUserNotFound)
+
+		limiter := newCountingLimiter(10)
+		svc := NewPasswordService(storage, tokenSecret,
+			WithLoginRateLimiter(limiter),
+			WithLoginRateLimitKey(LoginKeyByEmailAndIP),
+		)
+
+		ctx := clientip.SetIPToContext(context.Background(), "203.0.113.7")
+		_, err := svc.Authenticate(ctx, email, "password")
+		assert.ErrorIs(t, err, ErrInvalidCredentials)
+		assert.Equal(t, map[string]int{"login:email_ip:" + email + "|203.0.113.7": 1}, limiter.seen)
+	})
+
+	t.Run("empty key skips the limiter", func(t *testing.T) {
+		t.Parallel()
+
+		storage := &MockPasswordStorage{}
+		storage.On("GetUserByEmail", mock.Anything, email).Return(nil, ErrUserNotFound)
+
+		limiter := newCountingLimiter(0)
+		svc := NewPasswordService(storage, tokenSecret,
+			WithLoginRateLimiter(limiter),
+			WithLoginRateLimitKey(LoginKeyByIP),
+		)
+
+		_, err := svc.Authenticate(context.Background(), email, "password")
+		assert.ErrorIs(t, err, ErrInvalidCredentials)
+		assert.Empty(t, limiter.seen)
+	})
+
+	t.Run("func adapter", func(t *testing.T) {
+		t.Parallel()
+
+		var gotKey string
+		limiter := LoginRateLimiterFunc(func(_ context.Context, key string) (bool, error) {
+			gotKey = key
+			return false, nil
+		})
+		svc := NewPasswordService(&MockPasswordStorage{}, tokenSecret,
+			WithLoginRateLimiter(limiter),
+			WithLoginRateLimitKey(LoginKeyByIP),
+		)
+
+		ctx := clientip.SetIPToContext(context.Background(), "198.51.100.1")
+		_, err := svc.Authenticate(ctx, email, "password")
+		assert.ErrorIs(t, err, ErrTooManyAttempts)
+		assert.Equal(t, "login:ip:198.51.100.1", gotKey)
+	})
+}
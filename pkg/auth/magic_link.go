@@ -38,6 +38,16 @@ type MagicLinkAuthenticator interface {
 	VerifyMagicLink(ctx context.Context, magicLinkToken string) (*User, error)
 }
 
+// MagicLinkTokenAuthenticator is implemented by the service returned from NewMagicLinkService
+// for token-based clients. It's separate from MagicLinkAuthenticator so existing
+// implementations of that interface keep compiling:
+//
+//	tokenAuth := magicLinkAuth.(auth.MagicLinkTokenAuthenticator)
+type MagicLinkTokenAuthenticator interface {
+	// VerifyMagicLinkWithTokens verifies the link and issues a token pair via the configured TokenIssuer.
+	VerifyMagicLinkWithTokens(ctx context.Context, magicLinkToken string) (*User, *TokenPair, error)
+}
+
 // MagicLinkStorage defines the storage interface required by magic link services.
 type MagicLinkStorage interface {
 	GetUserByEmail(ctx context.Context, email string) (*User, error)
@@ -46,6 +56,11 @@ type MagicLinkStorage interface {
 	// ConsumeToken atomically checks if token was used and marks it as consumed.
 	// Returns ErrTokenAlreadyUsed if token was already consumed.
 	// Token ID should be stored with TTL matching token expiration.
+	//
+	// The check and the mark must be a single atomic operation (e.g. SET NX in Redis,
+	// INSERT with a unique constraint in SQL) so that concurrent verifications of the
+	// same link succeed exactly once. Any other error fails verification.
+	// See MemoryTokenStore for a reference implementation.
 	ConsumeToken(ctx context.Context, tokenID string, ttl time.Duration) error
 }
 
@@ -54,6 +69,7 @@ type magicLinkService struct {
 	tokenSecret  string
 	logger       *slog.Logger
 	magicLinkTTL time.Duration // TTL for magic link tokens
+	tokenIssuer  *TokenIssuer
 
 	// Hooks for extending magic link behavior
 	afterGenerate func(ctx context.Context, user *User, token string) error
@@ -78,6 +94,13 @@ func WithMagicLinkTTL(ttl time.Duration) MagicLinkOption {
 	}
 }
 
+// WithMagicLinkTokenIssuer configures the token issuer used by VerifyMagicLinkWithTokens.
+func WithMagicLinkTokenIssuer(issuer *TokenIssuer) MagicLinkOption {
+	return func(s *magicLinkService) {
+		s.tokenIssuer = issuer
+	}
+}
+
 // WithAfterGenerate configures a hook that runs after magic link generation (async).
 func WithAfterGenerate(fn func(context.Context, *User, string) error) MagicLinkOption {
 	return func(s *magicLinkService) {
@@ -205,20 +228,18 @@ func (s *magicLinkService) VerifyMagicLink(ctx context.Context, magicLinkToken s
 		return nil, ErrTokenExpired
 	}
 
-	// Replay protection: atomically check and consume token
-	if payload.TokenID != "" {
-		ttl := time.Until(time.Unix(payload.ExpireAt, 0))
-		if err := s.storage.ConsumeToken(ctx, payload.TokenID, ttl); err != nil {
-			if errors.Is(err, ErrTokenAlreadyUsed) {
-				return nil, ErrTokenAlreadyUsed
-			}
-			// Log error but continue - don't break auth if replay protection fails
-			s.logger.Error("failed to consume token for replay protection",
-				slog.String("token_id", payload.TokenID),
-				logger.Error(err),
-				logger.Component("magic_link"),
-			)
+	// Tokens without an ID can't be tracked, so they would be replayable
+	if payload.TokenID == "" {
+		return nil, ErrTokenInvalid
+	}
+
+	// Single-use enforcement: fail closed so a storage outage can't enable replays
+	ttl := time.Until(time.Unix(payload.ExpireAt, 0))
+	if err := s.storage.ConsumeToken(ctx, payload.TokenID, ttl); err != nil {
+		if errors.Is(err, ErrTokenAlreadyUsed) {
+			return nil, ErrTokenAlreadyUsed
 		}
+		return nil, fmt.Errorf("failed to consume magic link token: %w", err)
 	}
 
 	user, err := s.storage.GetUserByEmail(ctx, payload.Email)
@@ -255,5 +276,26 @@ func (s *magicLinkService) VerifyMagicLink(ctx context.Context, magicLinkToken s
 	return user, nil
 }
 
+// VerifyMagicLinkWithTokens verifies the magic link and issues an access+refresh token pair.
+// Returns ErrTokenIssuerNotConfigured if the service was built without WithMagicLinkTokenIssuer.
+func (s *magicLinkService) VerifyMagicLinkWithTokens(ctx context.Context, magicLinkToken string) (*User, *TokenPair, error) {
+	if s.tokenIssuer == nil {
+		return nil, nil, ErrTokenIssuerNotConfigured
+	}
+
+	user, err := s.VerifyMagicLink(ctx, magicLinkToken)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tokens, err := s.tokenIssuer.Issue(ctx, user)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return user, tokens, nil
+}
+
 // Compile-time interface assertion
 var _ MagicLinkAuthenticator = (*magicLinkService)(nil)
+var _ MagicLinkTokenAuthenticator = (*magicLinkService)(nil)
@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"log/slog"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -698,26 +700,93 @@ func TestMagicLinkSecurityFeatures(t *testing.T) {
 		storage.AssertExpectations(t)
 	})
 
-	t.Run("continues auth if replay protection fails", func(t *testing.T) {
+	t.Run("fails closed if replay protection fails", func(t *testing.T) {
 		t.Parallel()
 
 		storage := &MockMagicLinkStorage{}
 		svc := NewMagicLinkService(storage, tokenSecret)
 
-		email := "test@example.com"
-		user := &User{ID: uuid.New(), Email: email, IsVerified: true}
-		validToken := createValidMagicLinkToken(t, tokenSecret, email, 15*time.Minute)
+		validToken := createValidMagicLinkToken(t, tokenSecret, "test@example.com", 15*time.Minute)
 
-		storage.On("GetUserByEmail", mock.Anything, email).Return(user, nil)
-		storage.On("ConsumeToken", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("time.Duration")).Return(errors.New("storage error"))
+		storageErr := errors.New("storage error")
+		storage.On("ConsumeToken", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("time.Duration")).Return(storageErr)
 
 		ctx := context.Background()
-		resultUser, err := svc.VerifyMagicLink(ctx, validToken)
+		_, err := svc.VerifyMagicLink(ctx, validToken)
 
-		require.NoError(t, err) // Should still succeed despite storage error
-		assert.Equal(t, user.ID, resultUser.ID)
+		require.ErrorIs(t, err, storageErr)
+		storage.AssertNotCalled(t, "GetUserByEmail", mock.Anything, mock.Anything)
 		storage.AssertExpectations(t)
 	})
+
+	t.Run("rejects token without id", func(t *testing.T) {
+		t.Parallel()
+
+		storage := &MockMagicLinkStorage{}
+		svc := NewMagicLinkService(storage, tokenSecret)
+
+		tokenStr, err := token.GenerateToken(MagicLinkTokenPayload{
+			Email:    "test@example.com",
+			Subject:  SubjectMagicLink,
+			ExpireAt: time.Now().Add(15 * time.Minute).Unix(),
+		}, tokenSecret)
+		require.NoError(t, err)
+
+		_, err = svc.VerifyMagicLink(context.Background(), tokenStr)
+
+		assert.Equal(t, ErrTokenInvalid, err)
+		storage.AssertNotCalled(t, "ConsumeToken", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("concurrent verification succeeds once", func(t *testing.T) {
+		t.Parallel()
+
+		storage := &memoryMagicLinkStorage{
+			MockMagicLinkStorage: &MockMagicLinkStorage{},
+			MemoryTokenStore:     NewMemoryTokenStore(),
+		}
+		svc := NewMagicLinkService(storage, tokenSecret)
+
+		email := "test@example.com"
+		user := &User{ID: uuid.New(), Email: email, IsVerified: true}
+		storage.MockMagicLinkStorage.On("GetUserByEmail", mock.Anything, email).Return(user, nil)
+
+		validToken := createValidMagicLinkToken(t, tokenSecret, email, 15*time.Minute)
+
+		const attempts = 20
+		var (
+			wg        sync.WaitGroup
+			successes atomic.Int32
+			reused    atomic.Int32
+		)
+		for range attempts {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := svc.VerifyMagicLink(context.Background(), validToken)
+				switch {
+				case err == nil:
+					successes.Add(1)
+				case errors.Is(err, ErrTokenAlreadyUsed):
+					reused.Add(1)
+				}
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, int32(1), successes.Load())
+		assert.Equal(t, int32(attempts-1), reused.Load())
+	})
+}
+
+// memoryMagicLinkStorage uses the mock for users and MemoryTokenStore for consumption.
+type memoryMagicLinkStorage struct {
+	*MockMagicLinkStorage
+	*MemoryTokenStore
+}
+
+func (s *memoryMagicLinkStorage) ConsumeToken(ctx context.Context, tokenID string, ttl time.Duration) error {
+	return s.MemoryTokenStore.ConsumeToken(ctx, tokenID, ttl)
 }
 
 // Test that the service correctly implements the interface
@@ -730,3 +799,27 @@ func TestMagicLinkServiceInterface(t *testing.T) {
 	require.NotNil(t, svc)
 	// If this compiles, the interface is correctly implemented
 }
+
+func TestMemoryTokenStore(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	t.Run("second consume fails", func(t *testing.T) {
+		t.Parallel()
+		store := NewMemoryTokenStore()
+
+		require.NoError(t, store.ConsumeToken(ctx, "t1", time.Minute))
+		assert.ErrorIs(t, store.ConsumeToken(ctx, "t1", time.Minute), ErrTokenAlreadyUsed)
+		assert.NoError(t, store.ConsumeToken(ctx, "t2", time.Minute))
+	})
+
+	t.Run("expired entries are forgotten", func(t *testing.T) {
+		t.Parallel()
+		store := NewMemoryTokenStore()
+
+		require.NoError(t, store.ConsumeToken(ctx, "t1", time.Millisecond))
+		time.Sleep(5 * time.Millisecond)
+		assert.NoError(t, store.ConsumeToken(ctx, "t1", time.Minute))
+	})
+}
@@ -19,6 +19,27 @@ type OAuthAuthenticator interface {
 	Unlink(ctx context.Context, userID uuid.UUID) error
 }
 
+// OAuthProviderTokenRefresher is implemented by the service returned from NewOAuthService.
+// It's separate from OAuthAuthenticator so existing implementations of that interface
+// keep compiling:
+//
+//	refresher := oauthAuth.(auth.OAuthProviderTokenRefresher)
+type OAuthProviderTokenRefresher interface {
+	// RefreshProviderToken mints a new provider access token from the stored refresh token.
+	// Requires WithProviderTokens.
+	RefreshProviderToken(ctx context.Context, userID uuid.UUID, provider string) (string, error)
+}
+
+// OAuthTokenAuthenticator is implemented by the service returned from NewOAuthService
+// for token-based clients. It's separate from OAuthAuthenticator so existing
+// implementations of that interface keep compiling:
+//
+//	tokenAuth := oauthAuth.(auth.OAuthTokenAuthenticator)
+type OAuthTokenAuthenticator interface {
+	// AuthWithTokens handles OAuth callback and issues a token pair via the configured TokenIssuer
+	AuthWithTokens(ctx context.Context, code, state string, linkToUserID *uuid.UUID) (*User, *TokenPair, error)
+}
+
 // OAuthStorage defines the storage interface required by OAuth services.
 type OAuthStorage interface {
 	// User operations
@@ -1,11 +1,21 @@
 package auth
 
-import "context"
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"golang.org/x/oauth2"
+)
 
 // OAuth provider identifiers used across the auth system.
 const (
-	OAuthProviderGoogle = "google"
-	OAuthProviderGithub = "github"
+	OAuthProviderGoogle    = "google"
+	OAuthProviderGithub    = "github"
+	OAuthProviderApple     = "apple"
+	OAuthProviderMicrosoft = "microsoft"
 )
 
 // ProviderAdapter abstracts provider-specific OAuth behavior behind a minimal,
@@ -47,4 +57,67 @@ type ProviderProfile struct {
 
 	// EmailVerified indicates whether the provider asserts the email is verified.
 	EmailVerified bool
+
+	// Name is the user's display name, or empty if the provider didn't share it.
+	Name string
+
+	// AvatarURL is the URL of the user's profile picture, or empty if unavailable.
+	AvatarURL string
+
+	// Token holds the provider tokens from the code exchange, or nil if the adapter
+	// doesn't return them. It's persisted only when WithProviderTokens is configured.
+	Token *ProviderToken
+}
+
+// providerToken converts an exchanged oauth2 token for persistence.
+func providerToken(tok *oauth2.Token) *ProviderToken {
+	return &ProviderToken{
+		AccessToken:  tok.AccessToken,
+		RefreshToken: tok.RefreshToken,
+		Expiry:       tok.Expiry,
+	}
+}
+
+// refreshOAuth2Token exchanges a refresh token at the config's token endpoint.
+func refreshOAuth2Token(ctx context.Context, conf *oauth2.Config, refreshToken string) (ProviderToken, error) {
+	tok, err := conf.TokenSource(ctx, &oauth2.Token{RefreshToken: refreshToken}).Token()
+	if err != nil {
+		return ProviderToken{}, err
+	}
+	// The token source reuses the old refresh token when none is returned;
+	// report it as not rotated
+	if tok.RefreshToken == refreshToken {
+		tok.RefreshToken = ""
+	}
+	return *providerToken(tok), nil
+}
+
+// decodeIDTokenClaims decodes the payload of an OpenID Connect ID token into v.
+// The signature is not checked: adapters only call it for tokens received directly
+// from the provider's token endpoint over TLS (OpenID Connect Core §3.1.3.7).
+func decodeIDTokenClaims(idToken string, v any) error {
+	parts := strings.Split(idToken, ".")
+	if len(parts) != 3 {
+		return errors.New("malformed id token")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return errors.New("malformed id token payload")
+	}
+	return json.Unmarshal(payload, v)
+}
+
+// claimBool decodes boolean claims that some providers (e.g., Apple) send as "true"/"false" strings.
+type claimBool bool
+
+func (b *claimBool) UnmarshalJSON(data []byte) error {
+	switch strings.Trim(string(data), `"`) {
+	case "true":
+		*b = true
+	case "false", "null", "":
+		*b = false
+	default:
+		return errors.New("invalid boolean claim")
+	}
+	return nil
 }
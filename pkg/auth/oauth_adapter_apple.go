@@ -0,0 +1,300 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+const (
+	appleIssuer = "https://appleid.apple.com"
+
+	// appleClientSecretTTL is the lifetime of minted client secrets. Apple accepts up to
+	// six months; a short lifetime limits the damage of a leaked secret.
+	appleClientSecretTTL = time.Hour
+)
+
+// appleEndpoint is the Sign in with Apple OAuth endpoint. Apple expects the
+// client secret in the request body.
+var appleEndpoint = oauth2.Endpoint{
+	AuthURL:   appleIssuer + "/auth/authorize",
+	TokenURL:  appleIssuer + "/auth/token",
+	AuthStyle: oauth2.AuthStyleInParams,
+}
+
+// AppleOAuthConfig holds configuration for Sign in with Apple.
+type AppleOAuthConfig struct {
+	ClientID     string        `env:"APPLE_OAUTH_CLIENT_ID,required"`   // Services ID
+	TeamID       string        `env:"APPLE_OAUTH_TEAM_ID,required"`     // Apple Developer team ID
+	KeyID        string        `env:"APPLE_OAUTH_KEY_ID,required"`      // ID of the Sign in with Apple private key
+	PrivateKey   string        `env:"APPLE_OAUTH_PRIVATE_KEY,required"` // PEM encoded .p8 key used to sign client secrets
+	RedirectURL  string        `env:"APPLE_OAUTH_REDIRECT_URL,required"`
+	Scopes       []string      `env:"APPLE_OAUTH_SCOPES" envSeparator:"," envDefault:"name,email"`
+	StateTTL     time.Duration `env:"APPLE_OAUTH_STATE_TTL" envDefault:"10m"`
+	VerifiedOnly bool          `env:"APPLE_OAUTH_VERIFIED_ONLY" envDefault:"true"`
+}
+
+type appleAdapter struct {
+	conf       *oauth2.Config
+	teamID     string
+	keyID      string
+	privateKey *ecdsa.PrivateKey
+
+	mu           sync.Mutex
+	clientSecret string
+	secretExpiry time.Time
+}
+
+// NewAppleAdapter creates a Sign in with Apple provider adapter.
+// Client secrets are minted as ES256 JWTs from the configured private key.
+// Panics if the private key is not a PEM encoded P-256 key, failing fast on misconfiguration.
+func NewAppleAdapter(cfg AppleOAuthConfig) ProviderAdapter {
+	key, err := parseApplePrivateKey(cfg.PrivateKey)
+	if err != nil {
+		panic(fmt.Sprintf("auth: invalid Apple private key: %v", err))
+	}
+
+	return &appleAdapter{
+		conf: &oauth2.Config{
+			ClientID:    cfg.ClientID,
+			RedirectURL: cfg.RedirectURL,
+			Scopes:      cfg.Scopes,
+			Endpoint:    appleEndpoint,
+		},
+		teamID:     cfg.TeamID,
+		keyID:      cfg.KeyID,
+		privateKey: key,
+	}
+}
+
+// ProviderID returns the Apple provider identifier.
+func (a *appleAdapter) ProviderID() string {
+	return OAuthProviderApple
+}
+
+// AuthURL builds the Apple authorization URL with the given state token.
+// Apple requires the form_post response mode whenever scopes are requested,
+// so the callback arrives as a POST; use ParseAppleCallback to read it.
+func (a *appleAdapter) AuthURL(state string) (string, error) {
+	return a.conf.AuthCodeURL(state, oauth2.SetAuthURLParam("response_mode", "form_post")), nil
+}
+
+// ResolveProfile exchanges the authorization code and reads the profile from the ID token.
+// Apple shares the user's name only on the first authorization, through the callback form;
+// it is set on the profile when the context comes from AppleCallback.Context.
+func (a *appleAdapter) ResolveProfile(ctx context.Context, code string) (ProviderProfile, error) {
+	conf, err := a.exchangeConfig()
+	if err != nil {
+		return ProviderProfile{}, err
+	}
+
+	tok, err := conf.Exchange(ctx, code)
+	if err != nil {
+		// Treat exchange failures as invalid code for the core flow.
+		return ProviderProfile{}, ErrInvalidCode
+	}
+
+	idToken, _ := tok.Extra("id_token").(string)
+	if idToken == "" {
+		return ProviderProfile{}, fmt.Errorf("apple token response without id token")
+	}
+
+	var claims appleIDTokenClaims
+	if err := decodeIDTokenClaims(idToken, &claims); err != nil {
+		return ProviderProfile{}, fmt.Errorf("decode apple id token: %w", err)
+	}
+	if claims.Issuer != appleIssuer || claims.Audience != a.conf.ClientID || claims.Subject == "" {
+		return ProviderProfile{}, fmt.Errorf("apple id token issued for another client")
+	}
+	if claims.Email == "" {
+		return ProviderProfile{}, ErrNoPrimaryEmail
+	}
+
+	var name string
+	if user, ok := ctx.Value(appleUserContextKey{}).(appleUser); ok {
+		name = strings.TrimSpace(user.Name.FirstName + " " + user.Name.LastName)
+	}
+
+	return ProviderProfile{
+		ProviderUserID: claims.Subject,
+		Email:          claims.Email,
+		// Private relay addresses (is_private_email) are unique to the user and this app
+		// and forward to their inbox, so Apple's email_verified holds for them as well.
+		EmailVerified: bool(claims.EmailVerified),
+		Name:          name,
+		Token:         providerToken(tok),
+	}, nil
+}
+
+// RefreshToken mints a new Apple access token from a refresh token.
+func (a *appleAdapter) RefreshToken(ctx context.Context, refreshToken string) (ProviderToken, error) {
+	conf, err := a.exchangeConfig()
+	if err != nil {
+		return ProviderToken{}, err
+	}
+	return refreshOAuth2Token(ctx, conf, refreshToken)
+}
+
+// exchangeConfig returns a copy of the OAuth config carrying a valid client secret.
+func (a *appleAdapter) exchangeConfig() (*oauth2.Config, error) {
+	secret, err := a.getClientSecret(time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("sign apple client secret: %w", err)
+	}
+	conf := *a.conf
+	conf.ClientSecret = secret
+	return &conf, nil
+}
+
+// getClientSecret returns the cached client secret, minting a new one shortly before expiry.
+func (a *appleAdapter) getClientSecret(now time.Time) (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.clientSecret != "" && now.Add(5*time.Minute).Before(a.secretExpiry) {
+		return a.clientSecret, nil
+	}
+
+	expiry := now.Add(appleClientSecretTTL)
+	secret, err := signES256JWT(a.privateKey,
+		map[string]any{"alg": "ES256", "kid": a.keyID},
+		map[string]any{
+			"iss": a.teamID,
+			"iat": now.Unix(),
+			"exp": expiry.Unix(),
+			"aud": appleIssuer,
+			"sub": a.conf.ClientID,
+		},
+	)
+	if err != nil {
+		return "", err
+	}
+
+	a.clientSecret, a.secretExpiry = secret, expiry
+	return secret, nil
+}
+
+// AppleCallback holds the fields Apple posts to the redirect URL in form_post mode.
+type AppleCallback struct {
+	Code  string
+	State string
+	user  *appleUser
+}
+
+// ParseAppleCallback reads the authorization response Apple posts to the redirect URL.
+// Returns ErrInvalidCode if Apple reported an error, e.g. when the user cancelled.
+//
+// The callback is a cross-site POST, so SameSite=Lax cookies aren't sent with it;
+// the state is validated against OAuthStorage, not a cookie.
+func ParseAppleCallback(r *http.Request) (AppleCallback, error) {
+	if err := r.ParseForm(); err != nil {
+		return AppleCallback{}, fmt.Errorf("parse apple callback: %w", err)
+	}
+	if e := r.Form.Get("error"); e != "" {
+		return AppleCallback{}, fmt.Errorf("%w: %s", ErrInvalidCode, e)
+	}
+
+	cb := AppleCallback{
+		Code:  r.Form.Get("code"),
+		State: r.Form.Get("state"),
+	}
+	if cb.Code == "" {
+		return AppleCallback{}, ErrInvalidCode
+	}
+
+	// Only present on the first authorization; a malformed value isn't worth failing the login
+	if raw := r.Form.Get("user"); raw != "" {
+		var user appleUser
+		if json.Unmarshal([]byte(raw), &user) == nil {
+			cb.user = &user
+		}
+	}
+
+	return cb, nil
+}
+
+// Context returns ctx carrying the user details from the callback, so the Apple adapter
+// can fill ProviderProfile.Name. Pass it to OAuthAuthenticator.Auth.
+func (c AppleCallback) Context(ctx context.Context) context.Context {
+	if c.user == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, appleUserContextKey{}, *c.user)
+}
+
+type appleUserContextKey struct{}
+
+type appleUser struct {
+	Name struct {
+		FirstName string `json:"firstName"`
+		LastName  string `json:"lastName"`
+	} `json:"name"`
+}
+
+type appleIDTokenClaims struct {
+	Issuer        string    `json:"iss"`
+	Audience      string    `json:"aud"`
+	Subject       string    `json:"sub"`
+	Email         string    `json:"email"`
+	EmailVerified claimBool `json:"email_verified"`
+}
+
+func parseApplePrivateKey(pemKey string) (*ecdsa.PrivateKey, error) {
+	block, _ := pem.Decode([]byte(pemKey))
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	ecKey, ok := key.(*ecdsa.PrivateKey)
+	if !ok || ecKey.Curve != elliptic.P256() {
+		return nil, errors.New("key is not a P-256 ECDSA key")
+	}
+	return ecKey, nil
+}
+
+// signES256JWT builds a compact JWS with a raw r||s ECDSA signature (RFC 7518 §3.4).
+func signES256JWT(key *ecdsa.PrivateKey, header, claims map[string]any) (string, error) {
+	h, err := json.Marshal(header)
+	if err != nil {
+		return "", err
+	}
+	c, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	signingInput := base64.RawURLEncoding.EncodeToString(h) + "." + base64.RawURLEncoding.EncodeToString(c)
+	digest := sha256.Sum256([]byte(signingInput))
+	r, s, err := ecdsa.Sign(rand.Reader, key, digest[:])
+	if err != nil {
+		return "", err
+	}
+
+	sig := make([]byte, 64)
+	r.FillBytes(sig[:32])
+	s.FillBytes(sig[32:])
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(sig), nil
+}
+
+// Compile-time interface assertion
+var (
+	_ ProviderAdapter = (*appleAdapter)(nil)
+	_ TokenRefresher  = (*appleAdapter)(nil)
+)
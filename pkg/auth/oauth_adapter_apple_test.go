@@ -0,0 +1,296 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+)
+
+// testIDToken builds an unsigned ID token carrying claims; adapters don't verify signatures.
+func testIDToken(t *testing.T, claims map[string]any) string {
+	t.Helper()
+	payload, err := json.Marshal(claims)
+	require.NoError(t, err)
+	return "eyJhbGciOiJub25lIn0." + base64.RawURLEncoding.EncodeToString(payload) + ".sig"
+}
+
+func newApplePrivateKey(t *testing.T) (*ecdsa.PrivateKey, string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	return key, string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+}
+
+func newTestAppleAdapter(t *testing.T) (*appleAdapter, *ecdsa.PrivateKey) {
+	t.Helper()
+	key, pemKey := newApplePrivateKey(t)
+	adapter := NewAppleAdapter(AppleOAuthConfig{
+		ClientID:    "com.example.web",
+		TeamID:      "TEAM123",
+		KeyID:       "KEY123",
+		PrivateKey:  pemKey,
+		RedirectURL: "https://example.com/callback",
+		Scopes:      []string{"name", "email"},
+	}).(*appleAdapter)
+	return adapter, key
+}
+
+// verifyAppleClientSecret checks the ES256 signature and claims of a minted client secret.
+func verifyAppleClientSecret(t *testing.T, pub *ecdsa.PublicKey, secret string) {
+	t.Helper()
+	parts := strings.Split(secret, ".")
+	require.Len(t, parts, 3)
+
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	require.NoError(t, err)
+	require.Len(t, sig, 64)
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	r, s := new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:])
+	assert.True(t, ecdsa.Verify(pub, digest[:], r, s), "client secret signature")
+
+	var header, claims map[string]any
+	raw, err := base64.RawURLEncoding.DecodeString(parts[0])
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(raw, &header))
+	raw, err = base64.RawURLEncoding.DecodeString(parts[1])
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(raw, &claims))
+
+	assert.Equal(t, "ES256", header["alg"])
+	assert.Equal(t, "KEY123", header["kid"])
+	assert.Equal(t, "TEAM123", claims["iss"])
+	assert.Equal(t, "com.example.web", claims["sub"])
+	assert.Equal(t, "https://appleid.apple.com", claims["aud"])
+	assert.Greater(t, claims["exp"], claims["iat"])
+}
+
+func TestAppleAdapter_AuthURL(t *testing.T) {
+	t.Parallel()
+
+	_, pemKey := newApplePrivateKey(t)
+	adapter := NewAppleAdapter(AppleOAuthConfig{
+		ClientID:    "com.example.web",
+		PrivateKey:  pemKey,
+		RedirectURL: "https://example.com/callback",
+		Scopes:      []string{"name", "email"},
+	})
+	assert.Equal(t, OAuthProviderApple, adapter.ProviderID())
+
+	authURL, err := adapter.AuthURL("test-state")
+	require.NoError(t, err)
+
+	u, err := url.Parse(authURL)
+	require.NoError(t, err)
+	assert.Equal(t, "appleid.apple.com", u.Host)
+	assert.Equal(t, "form_post", u.Query().Get("response_mode"))
+	assert.Equal(t, "test-state", u.Query().Get("state"))
+	assert.Equal(t, "name email", u.Query().Get("scope"))
+}
+
+func TestNewAppleAdapter_InvalidKey(t *testing.T) {
+	t.Parallel()
+
+	assert.Panics(t, func() { NewAppleAdapter(AppleOAuthConfig{PrivateKey: "not a key"}) })
+
+	notPKCS8 := string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: []byte("junk")}))
+	assert.Panics(t, func() { NewAppleAdapter(AppleOAuthConfig{PrivateKey: notPKCS8}) })
+}
+
+func TestAppleAdapter_ResolveProfile(t *testing.T) {
+	t.Parallel()
+
+	newTokenServer := func(t *testing.T, adapter *appleAdapter, pub *ecdsa.PublicKey, claims map[string]any) *httptest.Server {
+		t.Helper()
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.NoError(t, r.ParseForm())
+			assert.Equal(t, "com.example.web", r.Form.Get("client_id"))
+			verifyAppleClientSecret(t, pub, r.Form.Get("client_secret"))
+
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]any{
+				"access_token":  "apple-access",
+				"refresh_token": "apple-refresh",
+				"token_type":    "Bearer",
+				"expires_in":    3600,
+				"id_token":      testIDToken(t, claims),
+			})
+		}))
+		adapter.conf.Endpoint = oauth2.Endpoint{TokenURL: server.URL, AuthStyle: oauth2.AuthStyleInParams}
+		return server
+	}
+
+	baseClaims := func() map[string]any {
+		return map[string]any{
+			"iss":            "https://appleid.apple.com",
+			"aud":            "com.example.web",
+			"sub":            "001234.abcd",
+			"email":          "user@example.com",
+			"email_verified": "true",
+		}
+	}
+
+	t.Run("resolves verified email and name from callback", func(t *testing.T) {
+		t.Parallel()
+
+		adapter, key := newTestAppleAdapter(t)
+		server := newTokenServer(t, adapter, &key.PublicKey, baseClaims())
+		defer server.Close()
+
+		form := url.Values{
+			"code":  {"valid-code"},
+			"state": {"state-1"},
+			"user":  {`{"name":{"firstName":"Jane","lastName":"Doe"},"email":"user@example.com"}`},
+		}
+		req := httptest.NewRequest(http.MethodPost, "/callback", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+		cb, err := ParseAppleCallback(req)
+		require.NoError(t, err)
+		assert.Equal(t, "valid-code", cb.Code)
+		assert.Equal(t, "state-1", cb.State)
+
+		profile, err := adapter.ResolveProfile(cb.Context(context.Background()), cb.Code)
+		require.NoError(t, err)
+		assert.Equal(t, "001234.abcd", profile.ProviderUserID)
+		assert.Equal(t, "user@example.com", profile.Email)
+		assert.True(t, profile.EmailVerified)
+		assert.Equal(t, "Jane Doe", profile.Name)
+		assert.Empty(t, profile.AvatarURL)
+		require.NotNil(t, profile.Token)
+		assert.Equal(t, "apple-refresh", profile.Token.RefreshToken)
+	})
+
+	t.Run("private relay email is verified", func(t *testing.T) {
+		t.Parallel()
+
+		claims := baseClaims()
+		claims["email"] = "abc123@privaterelay.appleid.com"
+		claims["email_verified"] = true
+		claims["is_private_email"] = "true"
+
+		adapter, key := newTestAppleAdapter(t)
+		server := newTokenServer(t, adapter, &key.PublicKey, claims)
+		defer server.Close()
+
+		profile, err := adapter.ResolveProfile(context.Background(), "valid-code")
+		require.NoError(t, err)
+		assert.Equal(t, "abc123@privaterelay.appleid.com", profile.Email)
+		assert.True(t, profile.EmailVerified)
+		assert.Empty(t, profile.Name)
+	})
+
+	t.Run("rejects id token for another client", func(t *testing.T) {
+		t.Parallel()
+
+		claims := baseClaims()
+		claims["aud"] = "com.attacker.web"
+
+		adapter, key := newTestAppleAdapter(t)
+		server := newTokenServer(t, adapter, &key.PublicKey, claims)
+		defer server.Close()
+
+		_, err := adapter.ResolveProfile(context.Background(), "valid-code")
+		assert.Error(t, err)
+	})
+
+	t.Run("returns error when no email", func(t *testing.T) {
+		t.Parallel()
+
+		claims := baseClaims()
+		delete(claims, "email")
+
+		adapter, key := newTestAppleAdapter(t)
+		server := newTokenServer(t, adapter, &key.PublicKey, claims)
+		defer server.Close()
+
+		_, err := adapter.ResolveProfile(context.Background(), "valid-code")
+		assert.ErrorIs(t, err, ErrNoPrimaryEmail)
+	})
+
+	t.Run("returns invalid code for exchange failures", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":"invalid_grant"}`))
+		}))
+		defer server.Close()
+
+		adapter, _ := newTestAppleAdapter(t)
+		adapter.conf.Endpoint = oauth2.Endpoint{TokenURL: server.URL}
+		_, err := adapter.ResolveProfile(context.Background(), "bad-code")
+		assert.ErrorIs(t, err, ErrInvalidCode)
+	})
+}
+
+func TestAppleAdapter_ClientSecretCache(t *testing.T) {
+	t.Parallel()
+
+	adapter, key := newTestAppleAdapter(t)
+	now := time.Now()
+
+	first, err := adapter.getClientSecret(now)
+	require.NoError(t, err)
+	verifyAppleClientSecret(t, &key.PublicKey, first)
+
+	cached, err := adapter.getClientSecret(now.Add(30 * time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, first, cached)
+
+	renewed, err := adapter.getClientSecret(now.Add(appleClientSecretTTL - time.Minute))
+	require.NoError(t, err)
+	assert.NotEqual(t, first, renewed)
+}
+
+func TestParseAppleCallback(t *testing.T) {
+	t.Parallel()
+
+	post := func(form url.Values) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/callback", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		return req
+	}
+
+	t.Run("user cancelled", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := ParseAppleCallback(post(url.Values{"error": {"user_cancelled_authorize"}, "state": {"s"}}))
+		assert.ErrorIs(t, err, ErrInvalidCode)
+	})
+
+	t.Run("missing code", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := ParseAppleCallback(post(url.Values{"state": {"s"}}))
+		assert.ErrorIs(t, err, ErrInvalidCode)
+	})
+
+	t.Run("malformed user is ignored", func(t *testing.T) {
+		t.Parallel()
+
+		cb, err := ParseAppleCallback(post(url.Values{"code": {"c"}, "state": {"s"}, "user": {"{"}}))
+		require.NoError(t, err)
+		ctx := context.Background()
+		assert.Equal(t, ctx, cb.Context(ctx))
+	})
+}
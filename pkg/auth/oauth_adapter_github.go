@@ -103,9 +103,15 @@ func (a *githubAdapter) ResolveProfile(ctx context.Context, code string) (Provid
 		ProviderUserID: strconv.FormatInt(u.ID, 10),
 		Email:          email,
 		EmailVerified:  verified,
+		Token:          providerToken(tok),
 	}, nil
 }
 
+// RefreshToken mints a new GitHub access token from a refresh token.
+func (a *githubAdapter) RefreshToken(ctx context.Context, refreshToken string) (ProviderToken, error) {
+	return refreshOAuth2Token(ctx, a.conf, refreshToken)
+}
+
 func (a *githubAdapter) fetchGitHubUser(ctx context.Context, accessToken string) (*ghUser, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://api.github.com/user", nil)
 	if err != nil {
@@ -169,4 +175,7 @@ type ghEmail struct {
 }
 
 // Compile-time interface assertion
-var _ ProviderAdapter = (*githubAdapter)(nil)
+var (
+	_ ProviderAdapter = (*githubAdapter)(nil)
+	_ TokenRefresher  = (*githubAdapter)(nil)
+)
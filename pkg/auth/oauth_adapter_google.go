@@ -71,9 +71,15 @@ func (a *googleAdapter) ResolveProfile(ctx context.Context, code string) (Provid
 		ProviderUserID: u.ID,
 		Email:          u.Email,
 		EmailVerified:  u.VerifiedEmail,
+		Token:          providerToken(tok),
 	}, nil
 }
 
+// RefreshToken mints a new Google access token from a refresh token.
+func (a *googleAdapter) RefreshToken(ctx context.Context, refreshToken string) (ProviderToken, error) {
+	return refreshOAuth2Token(ctx, a.conf, refreshToken)
+}
+
 func (a *googleAdapter) fetchGoogleUser(ctx context.Context, accessToken string) (*gUser, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://www.googleapis.com/oauth2/v2/userinfo", nil)
 	if err != nil {
@@ -105,4 +111,7 @@ type gUser struct {
 }
 
 // Compile-time interface assertion
-var _ ProviderAdapter = (*googleAdapter)(nil)
+var (
+	_ ProviderAdapter = (*googleAdapter)(nil)
+	_ TokenRefresher  = (*googleAdapter)(nil)
+)
@@ -0,0 +1,161 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/microsoft"
+)
+
+// Microsoft Entra tenant aliases accepted in MicrosoftOAuthConfig.Tenant.
+const (
+	MicrosoftTenantCommon        = "common"        // work, school and personal accounts
+	MicrosoftTenantOrganizations = "organizations" // work and school accounts only
+	MicrosoftTenantConsumers     = "consumers"     // personal Microsoft accounts only
+)
+
+// microsoftConsumersTenantID is the tenant ID of all personal Microsoft accounts.
+const microsoftConsumersTenantID = "9188040d-6c67-4c5b-b112-36a304b66dad"
+
+// MicrosoftOAuthConfig holds configuration for Microsoft Entra ID (Azure AD) OAuth provider.
+// Tenant is a tenant alias (common, organizations, consumers), a tenant ID or a tenant domain.
+type MicrosoftOAuthConfig struct {
+	ClientID     string        `env:"MICROSOFT_OAUTH_CLIENT_ID,required"`
+	ClientSecret string        `env:"MICROSOFT_OAUTH_CLIENT_SECRET,required"`
+	RedirectURL  string        `env:"MICROSOFT_OAUTH_REDIRECT_URL,required"`
+	Tenant       string        `env:"MICROSOFT_OAUTH_TENANT" envDefault:"common"`
+	Scopes       []string      `env:"MICROSOFT_OAUTH_SCOPES" envSeparator:"," envDefault:"openid,email,profile,offline_access"`
+	StateTTL     time.Duration `env:"MICROSOFT_OAUTH_STATE_TTL" envDefault:"10m"`
+	VerifiedOnly bool          `env:"MICROSOFT_OAUTH_VERIFIED_ONLY" envDefault:"true"`
+}
+
+type microsoftAdapter struct {
+	conf   *oauth2.Config
+	tenant string
+}
+
+// NewMicrosoftAdapter creates a Microsoft Entra ID OAuth provider adapter using
+// the tenant-scoped v2.0 endpoints. An empty tenant defaults to "common".
+func NewMicrosoftAdapter(cfg MicrosoftOAuthConfig) ProviderAdapter {
+	tenant := cfg.Tenant
+	if tenant == "" {
+		tenant = MicrosoftTenantCommon
+	}
+
+	return &microsoftAdapter{
+		conf: &oauth2.Config{
+			ClientID:     cfg.ClientID,
+			ClientSecret: cfg.ClientSecret,
+			RedirectURL:  cfg.RedirectURL,
+			Scopes:       cfg.Scopes,
+			Endpoint:     microsoft.AzureADEndpoint(tenant),
+		},
+		tenant: tenant,
+	}
+}
+
+// ProviderID returns the Microsoft provider identifier.
+func (a *microsoftAdapter) ProviderID() string {
+	return OAuthProviderMicrosoft
+}
+
+// AuthURL builds the Microsoft authorization URL with the given state token.
+func (a *microsoftAdapter) AuthURL(state string) (string, error) {
+	return a.conf.AuthCodeURL(state), nil
+}
+
+// ResolveProfile exchanges the authorization code and reads the profile from the ID token.
+//
+// Entra lets tenant admins set arbitrary unverified emails, so EmailVerified is only
+// set for personal accounts or when the optional xms_edov claim (email domain owner
+// verified) is configured in the app registration and true.
+// Microsoft Graph serves profile photos as binary data only, so AvatarURL is left empty.
+func (a *microsoftAdapter) ResolveProfile(ctx context.Context, code string) (ProviderProfile, error) {
+	tok, err := a.conf.Exchange(ctx, code)
+	if err != nil {
+		// Treat exchange failures as invalid code for the core flow.
+		return ProviderProfile{}, ErrInvalidCode
+	}
+
+	idToken, _ := tok.Extra("id_token").(string)
+	if idToken == "" {
+		return ProviderProfile{}, fmt.Errorf("microsoft token response without id token, is the openid scope requested?")
+	}
+
+	var claims msIDTokenClaims
+	if err := decodeIDTokenClaims(idToken, &claims); err != nil {
+		return ProviderProfile{}, fmt.Errorf("decode microsoft id token: %w", err)
+	}
+	if err := a.validateClaims(claims); err != nil {
+		return ProviderProfile{}, err
+	}
+	if claims.Email == "" {
+		return ProviderProfile{}, ErrNoPrimaryEmail
+	}
+
+	return ProviderProfile{
+		// Microsoft recommends tid+oid as the user key; sub is pairwise per application
+		ProviderUserID: claims.TenantID + ":" + claims.ObjectID,
+		Email:          claims.Email,
+		EmailVerified:  claims.TenantID == microsoftConsumersTenantID || bool(claims.EmailDomainVerified),
+		Name:           claims.Name,
+		Token:          providerToken(tok),
+	}, nil
+}
+
+// RefreshToken mints a new Microsoft access token from a refresh token.
+func (a *microsoftAdapter) RefreshToken(ctx context.Context, refreshToken string) (ProviderToken, error) {
+	return refreshOAuth2Token(ctx, a.conf, refreshToken)
+}
+
+// validateClaims checks the ID token was issued for this client by a tenant the adapter accepts.
+func (a *microsoftAdapter) validateClaims(c msIDTokenClaims) error {
+	if c.Audience != a.conf.ClientID {
+		return fmt.Errorf("microsoft id token issued for another client")
+	}
+	if c.TenantID == "" || c.ObjectID == "" {
+		return fmt.Errorf("microsoft id token without tenant or object id")
+	}
+	if c.Issuer != "https://login.microsoftonline.com/"+c.TenantID+"/v2.0" {
+		return fmt.Errorf("microsoft id token has unexpected issuer %q", c.Issuer)
+	}
+
+	switch a.tenant {
+	case MicrosoftTenantCommon:
+	case MicrosoftTenantOrganizations:
+		if c.TenantID == microsoftConsumersTenantID {
+			return fmt.Errorf("personal microsoft accounts are not allowed")
+		}
+	case MicrosoftTenantConsumers:
+		if c.TenantID != microsoftConsumersTenantID {
+			return fmt.Errorf("work or school microsoft accounts are not allowed")
+		}
+	default:
+		// Tenant domains can't be compared to the tid claim; the tenant-scoped
+		// endpoint already refuses users from other tenants
+		if id, err := uuid.Parse(a.tenant); err == nil && c.TenantID != id.String() {
+			return fmt.Errorf("microsoft id token issued by another tenant")
+		}
+	}
+
+	return nil
+}
+
+type msIDTokenClaims struct {
+	Issuer              string    `json:"iss"`
+	Audience            string    `json:"aud"`
+	TenantID            string    `json:"tid"`
+	ObjectID            string    `json:"oid"`
+	Email               string    `json:"email"`
+	Name                string    `json:"name"`
+	EmailDomainVerified claimBool `json:"xms_edov"`
+}
+
+// Compile-time interface assertion
+var (
+	_ ProviderAdapter = (*microsoftAdapter)(nil)
+	_ TokenRefresher  = (*microsoftAdapter)(nil)
+)
@@ -0,0 +1,169 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+)
+
+const testMicrosoftTenantID = "72f988bf-86f1-41af-91ab-2d7cd011db47"
+
+func TestMicrosoftAdapter_AuthURL(t *testing.T) {
+	t.Parallel()
+
+	t.Run("defaults to common tenant", func(t *testing.T) {
+		t.Parallel()
+
+		adapter := NewMicrosoftAdapter(MicrosoftOAuthConfig{
+			ClientID:    "client-id",
+			RedirectURL: "https://example.com/callback",
+			Scopes:      []string{"openid", "email"},
+		})
+		assert.Equal(t, OAuthProviderMicrosoft, adapter.ProviderID())
+
+		authURL, err := adapter.AuthURL("test-state")
+		require.NoError(t, err)
+
+		u, err := url.Parse(authURL)
+		require.NoError(t, err)
+		assert.Equal(t, "login.microsoftonline.com", u.Host)
+		assert.Equal(t, "/common/oauth2/v2.0/authorize", u.Path)
+		assert.Equal(t, "test-state", u.Query().Get("state"))
+	})
+
+	t.Run("uses tenant-scoped endpoints", func(t *testing.T) {
+		t.Parallel()
+
+		adapter := NewMicrosoftAdapter(MicrosoftOAuthConfig{ClientID: "client-id", Tenant: testMicrosoftTenantID}).(*microsoftAdapter)
+		assert.Equal(t, "https://login.microsoftonline.com/"+testMicrosoftTenantID+"/oauth2/v2.0/authorize", adapter.conf.Endpoint.AuthURL)
+		assert.Equal(t, "https://login.microsoftonline.com/"+testMicrosoftTenantID+"/oauth2/v2.0/token", adapter.conf.Endpoint.TokenURL)
+	})
+}
+
+func TestMicrosoftAdapter_ResolveProfile(t *testing.T) {
+	t.Parallel()
+
+	claimsFor := func(tenantID string) map[string]any {
+		return map[string]any{
+			"iss":   "https://login.microsoftonline.com/" + tenantID + "/v2.0",
+			"aud":   "client-id",
+			"tid":   tenantID,
+			"oid":   "00000000-0000-0000-66f3-3332eca7ea81",
+			"email": "jane@contoso.com",
+			"name":  "Jane Doe",
+		}
+	}
+
+	resolve := func(t *testing.T, tenant string, claims map[string]any) (ProviderProfile, error) {
+		t.Helper()
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]any{
+				"access_token":  "ms-access",
+				"refresh_token": "ms-refresh",
+				"token_type":    "Bearer",
+				"expires_in":    3600,
+				"id_token":      testIDToken(t, claims),
+			})
+		}))
+		defer server.Close()
+
+		adapter := NewMicrosoftAdapter(MicrosoftOAuthConfig{ClientID: "client-id", ClientSecret: "secret", Tenant: tenant}).(*microsoftAdapter)
+		adapter.conf.Endpoint = oauth2.Endpoint{TokenURL: server.URL}
+		return adapter.ResolveProfile(context.Background(), "valid-code")
+	}
+
+	t.Run("work account email is unverified by default", func(t *testing.T) {
+		t.Parallel()
+
+		profile, err := resolve(t, "", claimsFor(testMicrosoftTenantID))
+		require.NoError(t, err)
+		assert.Equal(t, testMicrosoftTenantID+":00000000-0000-0000-66f3-3332eca7ea81", profile.ProviderUserID)
+		assert.Equal(t, "jane@contoso.com", profile.Email)
+		assert.Equal(t, "Jane Doe", profile.Name)
+		assert.False(t, profile.EmailVerified)
+		require.NotNil(t, profile.Token)
+		assert.Equal(t, "ms-access", profile.Token.AccessToken)
+	})
+
+	t.Run("work account with verified email domain", func(t *testing.T) {
+		t.Parallel()
+
+		claims := claimsFor(testMicrosoftTenantID)
+		claims["xms_edov"] = true
+
+		profile, err := resolve(t, MicrosoftTenantOrganizations, claims)
+		require.NoError(t, err)
+		assert.True(t, profile.EmailVerified)
+	})
+
+	t.Run("personal account email is verified", func(t *testing.T) {
+		t.Parallel()
+
+		profile, err := resolve(t, MicrosoftTenantConsumers, claimsFor(microsoftConsumersTenantID))
+		require.NoError(t, err)
+		assert.True(t, profile.EmailVerified)
+	})
+
+	t.Run("enforces tenant restrictions", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := resolve(t, MicrosoftTenantOrganizations, claimsFor(microsoftConsumersTenantID))
+		assert.Error(t, err)
+
+		_, err = resolve(t, MicrosoftTenantConsumers, claimsFor(testMicrosoftTenantID))
+		assert.Error(t, err)
+
+		_, err = resolve(t, "11111111-2222-3333-4444-555555555555", claimsFor(testMicrosoftTenantID))
+		assert.Error(t, err)
+
+		_, err = resolve(t, "contoso.onmicrosoft.com", claimsFor(testMicrosoftTenantID))
+		assert.NoError(t, err)
+	})
+
+	t.Run("rejects mismatched issuer or audience", func(t *testing.T) {
+		t.Parallel()
+
+		claims := claimsFor(testMicrosoftTenantID)
+		claims["iss"] = "https://login.microsoftonline.com/" + microsoftConsumersTenantID + "/v2.0"
+		_, err := resolve(t, "", claims)
+		assert.Error(t, err)
+
+		claims = claimsFor(testMicrosoftTenantID)
+		claims["aud"] = "other-client"
+		_, err = resolve(t, "", claims)
+		assert.Error(t, err)
+	})
+
+	t.Run("returns error when no email", func(t *testing.T) {
+		t.Parallel()
+
+		claims := claimsFor(testMicrosoftTenantID)
+		delete(claims, "email")
+		_, err := resolve(t, "", claims)
+		assert.ErrorIs(t, err, ErrNoPrimaryEmail)
+	})
+
+	t.Run("returns invalid code for exchange failures", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":"invalid_grant"}`))
+		}))
+		defer server.Close()
+
+		adapter := NewMicrosoftAdapter(MicrosoftOAuthConfig{ClientID: "client-id"}).(*microsoftAdapter)
+		adapter.conf.Endpoint = oauth2.Endpoint{TokenURL: server.URL}
+		_, err := adapter.ResolveProfile(context.Background(), "bad-code")
+		assert.ErrorIs(t, err, ErrInvalidCode)
+	})
+}
@@ -129,6 +129,8 @@ func TestGitHubAdapter_ResolveProfile(t *testing.T) {
 		assert.Equal(t, "12345", profile.ProviderUserID)
 		assert.Equal(t, "primary@example.com", profile.Email) // Should prefer primary
 		assert.True(t, profile.EmailVerified)
+		require.NotNil(t, profile.Token)
+		assert.Equal(t, "test-access-token", profile.Token.AccessToken)
 	})
 
 	t.Run("resolves profile with any verified email when no primary", func(t *testing.T) {
@@ -725,6 +727,78 @@ func TestAdapterConfigurations(t *testing.T) {
 	})
 }
 
+func TestAdapter_RefreshToken(t *testing.T) {
+	t.Parallel()
+
+	newTokenServer := func(t *testing.T, response map[string]any) *httptest.Server {
+		t.Helper()
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.NoError(t, r.ParseForm())
+			assert.Equal(t, "refresh_token", r.Form.Get("grant_type"))
+			assert.Equal(t, "old-refresh", r.Form.Get("refresh_token"))
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(response)
+		}))
+	}
+
+	t.Run("github rotates refresh token", func(t *testing.T) {
+		t.Parallel()
+
+		server := newTokenServer(t, map[string]any{
+			"access_token":  "new-access",
+			"refresh_token": "new-refresh",
+			"token_type":    "Bearer",
+			"expires_in":    3600,
+		})
+		defer server.Close()
+
+		adapter := NewGitHubAdapter(GitHubOAuthConfig{ClientID: "id", ClientSecret: "secret"}).(*githubAdapter)
+		adapter.conf.Endpoint = oauth2.Endpoint{TokenURL: server.URL}
+
+		token, err := adapter.RefreshToken(context.Background(), "old-refresh")
+		require.NoError(t, err)
+		assert.Equal(t, "new-access", token.AccessToken)
+		assert.Equal(t, "new-refresh", token.RefreshToken)
+		assert.WithinDuration(t, time.Now().Add(time.Hour), token.Expiry, time.Minute)
+	})
+
+	t.Run("google keeps refresh token", func(t *testing.T) {
+		t.Parallel()
+
+		server := newTokenServer(t, map[string]any{
+			"access_token": "new-access",
+			"token_type":   "Bearer",
+			"expires_in":   3600,
+		})
+		defer server.Close()
+
+		adapter := NewGoogleAdapter(GoogleOAuthConfig{ClientID: "id", ClientSecret: "secret"}).(*googleAdapter)
+		adapter.conf.Endpoint = oauth2.Endpoint{TokenURL: server.URL}
+
+		token, err := adapter.RefreshToken(context.Background(), "old-refresh")
+		require.NoError(t, err)
+		assert.Equal(t, "new-access", token.AccessToken)
+		assert.Empty(t, token.RefreshToken, "not rotated")
+	})
+
+	t.Run("refresh failure", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":"invalid_grant"}`))
+		}))
+		defer server.Close()
+
+		adapter := NewGoogleAdapter(GoogleOAuthConfig{ClientID: "id", ClientSecret: "secret"}).(*googleAdapter)
+		adapter.conf.Endpoint = oauth2.Endpoint{TokenURL: server.URL}
+
+		_, err := adapter.RefreshToken(context.Background(), "old-refresh")
+		assert.Error(t, err)
+	})
+}
+
 // mockTransport is a custom transport for redirecting API calls to mock servers
 type mockTransport struct {
 	userServer string
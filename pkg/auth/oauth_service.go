@@ -14,10 +14,13 @@ import (
 
 	"github.com/dmitrymomot/saaskit/pkg/logger"
 	"github.com/dmitrymomot/saaskit/pkg/sanitizer"
+	"github.com/dmitrymomot/saaskit/pkg/secrets"
 )
 
 // Ensure oauthService implements OAuthAuthenticator.
 var _ OAuthAuthenticator = (*oauthService)(nil)
+var _ OAuthTokenAuthenticator = (*oauthService)(nil)
+var _ OAuthProviderTokenRefresher = (*oauthService)(nil)
 
 type oauthService struct {
 	storage      OAuthStorage
@@ -25,6 +28,11 @@ type oauthService struct {
 	logger       *slog.Logger
 	stateTTL     time.Duration
 	verifiedOnly bool
+	tokenIssuer  *TokenIssuer
+
+	// Optional persistence of provider tokens, see WithProviderTokens
+	tokenStorage ProviderTokenStorage
+	tokenKeys    secrets.KeyProvider
 
 	// Hooks for extending OAuth behavior
 	afterAuth  func(ctx context.Context, user *User) error
@@ -60,6 +68,13 @@ func WithVerifiedOnly(verifiedOnly bool) OAuthOption {
 	}
 }
 
+// WithOAuthTokenIssuer configures the token issuer used by AuthWithTokens.
+func WithOAuthTokenIssuer(issuer *TokenIssuer) OAuthOption {
+	return func(s *oauthService) {
+		s.tokenIssuer = issuer
+	}
+}
+
 // WithAfterAuth configures a hook that runs after successful OAuth authentication (async).
 func WithAfterAuth(fn func(context.Context, *User) error) OAuthOption {
 	return func(s *oauthService) {
@@ -169,7 +184,28 @@ func (s *oauthService) Auth(ctx context.Context, code, state string, linkToUserI
 	return s.handleAuth(ctx, profile)
 }
 
-// Unlink removes the OAuth link for the configured provider from a user account.
+// AuthWithTokens handles the OAuth callback and issues an access+refresh token pair.
+// Returns ErrTokenIssuerNotConfigured if the service was built without WithOAuthTokenIssuer.
+func (s *oauthService) AuthWithTokens(ctx context.Context, code, state string, linkToUserID *uuid.UUID) (*User, *TokenPair, error) {
+	if s.tokenIssuer == nil {
+		return nil, nil, ErrTokenIssuerNotConfigured
+	}
+
+	user, err := s.Auth(ctx, code, state, linkToUserID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tokens, err := s.tokenIssuer.Issue(ctx, user)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return user, tokens, nil
+}
+
+// Unlink removes the OAuth link for the configured provider from a user account,
+// along with the stored provider token.
 func (s *oauthService) Unlink(ctx context.Context, userID uuid.UUID) error {
 	if err := s.storage.RemoveOAuthLink(ctx, userID, s.adapter.ProviderID()); err != nil {
 		if errors.Is(err, ErrNoProviderLink) {
@@ -177,6 +213,12 @@ func (s *oauthService) Unlink(ctx context.Context, userID uuid.UUID) error {
 		}
 		return fmt.Errorf("failed to unlink %s account: %w", s.adapter.ProviderID(), err)
 	}
+
+	if s.tokenStorage != nil {
+		if err := s.tokenStorage.DeleteProviderToken(ctx, userID, s.adapter.ProviderID()); err != nil {
+			return fmt.Errorf("failed to delete %s token: %w", s.adapter.ProviderID(), err)
+		}
+	}
 	return nil
 }
 
@@ -186,7 +228,8 @@ func (s *oauthService) handleLinking(ctx context.Context, userID uuid.UUID, prof
 		if existingUser.ID != userID {
 			return nil, ErrProviderLinked
 		}
-		// Already linked to this user, nothing to do
+		// Already linked to this user, only refresh the stored token
+		s.saveProviderToken(ctx, userID, profile.Token)
 		return existingUser, nil
 	}
 	if !errors.Is(err, ErrUserNotFound) {
@@ -208,6 +251,7 @@ func (s *oauthService) handleLinking(ctx context.Context, userID uuid.UUID, prof
 	if err := s.storage.StoreOAuthLink(ctx, userID, s.adapter.ProviderID(), profile.ProviderUserID); err != nil {
 		return nil, fmt.Errorf("failed to link %s account: %w", s.adapter.ProviderID(), err)
 	}
+	s.saveProviderToken(ctx, userID, profile.Token)
 
 	// Execute after link hook if set (only if actually linked)
 	if s.afterLink != nil {
@@ -230,6 +274,7 @@ func (s *oauthService) handleLinking(ctx context.Context, userID uuid.UUID, prof
 func (s *oauthService) handleAuth(ctx context.Context, profile ProviderProfile) (*User, error) {
 	user, err := s.storage.GetUserByOAuth(ctx, s.adapter.ProviderID(), profile.ProviderUserID)
 	if err == nil {
+		s.saveProviderToken(ctx, user.ID, profile.Token)
 		return user, nil
 	}
 	if !errors.Is(err, ErrUserNotFound) {
@@ -253,6 +298,10 @@ func (s *oauthService) handleAuth(ctx context.Context, profile ProviderProfile)
 				return MethodOAuthGoogle
 			case OAuthProviderGithub:
 				return MethodOAuthGithub
+			case OAuthProviderApple:
+				return MethodOAuthApple
+			case OAuthProviderMicrosoft:
+				return MethodOAuthMicrosoft
 			default:
 				return "oauth_" + s.adapter.ProviderID()
 			}
@@ -278,6 +327,7 @@ func (s *oauthService) handleAuth(ctx context.Context, profile ProviderProfile)
 		}
 		return nil, fmt.Errorf("failed to store oauth link: %w", err)
 	}
+	s.saveProviderToken(ctx, user.ID, profile.Token)
 
 	// Execute after auth hook if set
 	if s.afterAuth != nil {
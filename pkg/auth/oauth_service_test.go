@@ -880,6 +880,8 @@ func TestOAuthService_AuthMethodMapping(t *testing.T) {
 		}{
 			{"google", MethodOAuthGoogle},
 			{"github", MethodOAuthGithub},
+			{"apple", MethodOAuthApple},
+			{"microsoft", MethodOAuthMicrosoft},
 			{"custom-provider", "oauth_custom-provider"},
 		}
 
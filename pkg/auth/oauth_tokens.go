@@ -0,0 +1,177 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/dmitrymomot/saaskit/pkg/logger"
+	"github.com/dmitrymomot/saaskit/pkg/secrets"
+)
+
+// ProviderToken holds the provider's OAuth tokens for calling its API on behalf of the user.
+type ProviderToken struct {
+	AccessToken  string
+	RefreshToken string    // Empty when the provider didn't issue one
+	Expiry       time.Time // Zero when the access token doesn't expire
+}
+
+// EncryptedProviderToken is a ProviderToken as persisted by ProviderTokenStorage.
+// Both tokens are encrypted with pkg/secrets and base64-encoded.
+type EncryptedProviderToken struct {
+	AccessToken  string
+	RefreshToken string
+	Expiry       time.Time
+}
+
+// ProviderTokenStorage persists encrypted provider tokens, one per user and provider.
+// It's optional: tokens are only kept when configured with WithProviderTokens.
+type ProviderTokenStorage interface {
+	// StoreProviderToken creates or replaces the token for the user and provider.
+	StoreProviderToken(ctx context.Context, userID uuid.UUID, provider string, token EncryptedProviderToken) error
+	// GetProviderToken returns ErrProviderTokenNotFound when no token is stored.
+	GetProviderToken(ctx context.Context, userID uuid.UUID, provider string) (EncryptedProviderToken, error)
+	// DeleteProviderToken removes the token; deleting a missing token is not an error.
+	DeleteProviderToken(ctx context.Context, userID uuid.UUID, provider string) error
+}
+
+// TokenRefresher is implemented by provider adapters that can mint a new access
+// token from a refresh token. The built-in Google and GitHub adapters implement it.
+type TokenRefresher interface {
+	// RefreshToken exchanges the refresh token for a new token. The returned
+	// RefreshToken is empty when the provider doesn't rotate refresh tokens.
+	RefreshToken(ctx context.Context, refreshToken string) (ProviderToken, error)
+}
+
+// WithProviderTokens enables persisting provider tokens after OAuth login, which
+// RefreshProviderToken needs. Tokens are encrypted with keys derived by the key
+// provider for each user, e.g. secrets.NewStaticKeyProvider(appKey).
+// Both arguments are required.
+func WithProviderTokens(storage ProviderTokenStorage, keys secrets.KeyProvider) OAuthOption {
+	return func(s *oauthService) {
+		if storage == nil || keys == nil {
+			panic("auth: provider token storage and key provider are required")
+		}
+		s.tokenStorage = storage
+		s.tokenKeys = keys
+	}
+}
+
+// RefreshProviderToken mints a new provider access token from the stored refresh
+// token, persists the refreshed token and returns the new access token.
+func (s *oauthService) RefreshProviderToken(ctx context.Context, userID uuid.UUID, provider string) (string, error) {
+	if s.tokenStorage == nil {
+		return "", ErrProviderTokensNotConfigured
+	}
+	if provider != s.adapter.ProviderID() {
+		return "", fmt.Errorf("%w: service handles %s, not %s", ErrProviderMismatch, s.adapter.ProviderID(), provider)
+	}
+
+	refresher, ok := s.adapter.(TokenRefresher)
+	if !ok {
+		return "", ErrTokenRefreshNotSupported
+	}
+
+	stored, err := s.tokenStorage.GetProviderToken(ctx, userID, provider)
+	if err != nil {
+		if errors.Is(err, ErrProviderTokenNotFound) {
+			return "", ErrProviderTokenNotFound
+		}
+		return "", fmt.Errorf("failed to get provider token: %w", err)
+	}
+
+	token, err := s.decryptProviderToken(ctx, userID, stored)
+	if err != nil {
+		return "", err
+	}
+	if token.RefreshToken == "" {
+		return "", ErrNoRefreshToken
+	}
+
+	refreshed, err := refresher.RefreshToken(ctx, token.RefreshToken)
+	if err != nil {
+		return "", errors.Join(ErrProviderTokenRefreshFailed, err)
+	}
+	// Providers that don't rotate refresh tokens keep the old one valid
+	if refreshed.RefreshToken == "" {
+		refreshed.RefreshToken = token.RefreshToken
+	}
+
+	if err := s.storeProviderToken(ctx, userID, refreshed); err != nil {
+		return "", err
+	}
+
+	return refreshed.AccessToken, nil
+}
+
+// saveProviderToken persists the token from a login when token storage is configured.
+// Failures are logged rather than returned, so they don't fail an otherwise successful login.
+func (s *oauthService) saveProviderToken(ctx context.Context, userID uuid.UUID, token *ProviderToken) {
+	if s.tokenStorage == nil || token == nil || token.AccessToken == "" {
+		return
+	}
+
+	if err := s.storeProviderToken(ctx, userID, *token); err != nil {
+		s.logger.Error("failed to store provider token",
+			logger.UserID(userID.String()),
+			logger.Error(err),
+			logger.Component("oauth"),
+			slog.String("provider", s.adapter.ProviderID()),
+		)
+	}
+}
+
+func (s *oauthService) storeProviderToken(ctx context.Context, userID uuid.UUID, token ProviderToken) error {
+	workspaceKey := providerTokenKey(userID)
+
+	access, err := secrets.EncryptStringWithProvider(ctx, s.tokenKeys, workspaceKey, token.AccessToken)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt provider token: %w", err)
+	}
+
+	var refresh string
+	if token.RefreshToken != "" {
+		refresh, err = secrets.EncryptStringWithProvider(ctx, s.tokenKeys, workspaceKey, token.RefreshToken)
+		if err != nil {
+			return fmt.Errorf("failed to encrypt provider token: %w", err)
+		}
+	}
+
+	encrypted := EncryptedProviderToken{AccessToken: access, RefreshToken: refresh, Expiry: token.Expiry}
+	if err := s.tokenStorage.StoreProviderToken(ctx, userID, s.adapter.ProviderID(), encrypted); err != nil {
+		return fmt.Errorf("failed to store provider token: %w", err)
+	}
+	return nil
+}
+
+func (s *oauthService) decryptProviderToken(ctx context.Context, userID uuid.UUID, stored EncryptedProviderToken) (ProviderToken, error) {
+	workspaceKey := providerTokenKey(userID)
+	token := ProviderToken{Expiry: stored.Expiry}
+
+	var err error
+	token.AccessToken, err = secrets.DecryptStringWithProvider(ctx, s.tokenKeys, workspaceKey, stored.AccessToken)
+	if err != nil {
+		return ProviderToken{}, fmt.Errorf("failed to decrypt provider token: %w", err)
+	}
+
+	if stored.RefreshToken != "" {
+		token.RefreshToken, err = secrets.DecryptStringWithProvider(ctx, s.tokenKeys, workspaceKey, stored.RefreshToken)
+		if err != nil {
+			return ProviderToken{}, fmt.Errorf("failed to decrypt provider token: %w", err)
+		}
+	}
+
+	return token, nil
+}
+
+// providerTokenKey derives the per-user workspace key, so tokens of different
+// users are encrypted with different keys derived from the same application key.
+func providerTokenKey(userID uuid.UUID) []byte {
+	key := sha256.Sum256(append([]byte("auth-provider-token:"), userID[:]...))
+	return key[:]
+}
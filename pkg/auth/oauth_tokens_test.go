@@ -0,0 +1,227 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dmitrymomot/saaskit/pkg/secrets"
+)
+
+// memoryTokenStorage is an in-memory ProviderTokenStorage.
+type memoryTokenStorage struct {
+	mu     sync.Mutex
+	tokens map[string]EncryptedProviderToken
+}
+
+func newMemoryTokenStorage() *memoryTokenStorage {
+	return &memoryTokenStorage{tokens: make(map[string]EncryptedProviderToken)}
+}
+
+func (s *memoryTokenStorage) StoreProviderToken(_ context.Context, userID uuid.UUID, provider string, token EncryptedProviderToken) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tokens[userID.String()+provider] = token
+	return nil
+}
+
+func (s *memoryTokenStorage) GetProviderToken(_ context.Context, userID uuid.UUID, provider string) (EncryptedProviderToken, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	token, ok := s.tokens[userID.String()+provider]
+	if !ok {
+		return EncryptedProviderToken{}, ErrProviderTokenNotFound
+	}
+	return token, nil
+}
+
+func (s *memoryTokenStorage) DeleteProviderToken(_ context.Context, userID uuid.UUID, provider string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.tokens, userID.String()+provider)
+	return nil
+}
+
+// refreshingAdapter adds TokenRefresher to the mock adapter.
+type refreshingAdapter struct {
+	MockProviderAdapter
+}
+
+func (m *refreshingAdapter) RefreshToken(ctx context.Context, refreshToken string) (ProviderToken, error) {
+	args := m.Called(ctx, refreshToken)
+	return args.Get(0).(ProviderToken), args.Error(1)
+}
+
+func newTestKeyProvider(t *testing.T) secrets.KeyProvider {
+	t.Helper()
+	appKey, err := secrets.GenerateKey()
+	require.NoError(t, err)
+	keys, err := secrets.NewStaticKeyProvider(appKey)
+	require.NoError(t, err)
+	return keys
+}
+
+func TestOAuthService_ProviderTokens(t *testing.T) {
+	t.Parallel()
+
+	expiry := time.Now().Add(time.Hour).Truncate(time.Second)
+	profile := ProviderProfile{
+		ProviderUserID: "gh-1",
+		Email:          "user@example.com",
+		EmailVerified:  true,
+		Token: &ProviderToken{
+			AccessToken:  "access-1",
+			RefreshToken: "refresh-1",
+			Expiry:       expiry,
+		},
+	}
+
+	login := func(t *testing.T, svc OAuthAuthenticator, storage *MockOAuthStorage, adapter *refreshingAdapter, user *User) {
+		t.Helper()
+		adapter.On("ProviderID").Return("github")
+		adapter.On("ResolveProfile", mock.Anything, "code").Return(profile, nil).Once()
+		storage.On("ConsumeState", mock.Anything, "state").Return(nil).Once()
+		storage.On("GetUserByOAuth", mock.Anything, "github", "gh-1").Return(user, nil).Once()
+
+		_, err := svc.Auth(context.Background(), "code", "state", nil)
+		require.NoError(t, err)
+	}
+
+	t.Run("stores tokens encrypted on login", func(t *testing.T) {
+		t.Parallel()
+
+		storage := &MockOAuthStorage{}
+		adapter := &refreshingAdapter{}
+		tokens := newMemoryTokenStorage()
+		user := &User{ID: uuid.New()}
+		svc := NewOAuthService(storage, adapter, WithProviderTokens(tokens, newTestKeyProvider(t)))
+
+		login(t, svc, storage, adapter, user)
+
+		stored, err := tokens.GetProviderToken(context.Background(), user.ID, "github")
+		require.NoError(t, err)
+		assert.NotEmpty(t, stored.AccessToken)
+		assert.NotContains(t, stored.AccessToken, "access-1")
+		assert.NotContains(t, stored.RefreshToken, "refresh-1")
+		assert.Equal(t, expiry, stored.Expiry)
+	})
+
+	t.Run("refreshes access token", func(t *testing.T) {
+		t.Parallel()
+
+		storage := &MockOAuthStorage{}
+		adapter := &refreshingAdapter{}
+		tokens := newMemoryTokenStorage()
+		user := &User{ID: uuid.New()}
+		keys := newTestKeyProvider(t)
+		svc := NewOAuthService(storage, adapter, WithProviderTokens(tokens, keys))
+
+		login(t, svc, storage, adapter, user)
+
+		adapter.On("RefreshToken", mock.Anything, "refresh-1").
+			Return(ProviderToken{AccessToken: "access-2", Expiry: expiry.Add(time.Hour)}, nil).Once()
+
+		access, err := svc.(OAuthProviderTokenRefresher).RefreshProviderToken(context.Background(), user.ID, "github")
+		require.NoError(t, err)
+		assert.Equal(t, "access-2", access)
+
+		// The refresh token wasn't rotated, so the old one is kept for the next refresh
+		adapter.On("RefreshToken", mock.Anything, "refresh-1").
+			Return(ProviderToken{AccessToken: "access-3", RefreshToken: "refresh-2"}, nil).Once()
+
+		access, err = svc.(OAuthProviderTokenRefresher).RefreshProviderToken(context.Background(), user.ID, "github")
+		require.NoError(t, err)
+		assert.Equal(t, "access-3", access)
+
+		stored, err := tokens.GetProviderToken(context.Background(), user.ID, "github")
+		require.NoError(t, err)
+		refresh, err := secrets.DecryptStringWithProvider(context.Background(), keys, providerTokenKey(user.ID), stored.RefreshToken)
+		require.NoError(t, err)
+		assert.Equal(t, "refresh-2", refresh)
+
+		adapter.AssertExpectations(t)
+	})
+
+	t.Run("deletes tokens on unlink", func(t *testing.T) {
+		t.Parallel()
+
+		storage := &MockOAuthStorage{}
+		adapter := &refreshingAdapter{}
+		tokens := newMemoryTokenStorage()
+		user := &User{ID: uuid.New()}
+		svc := NewOAuthService(storage, adapter, WithProviderTokens(tokens, newTestKeyProvider(t)))
+
+		login(t, svc, storage, adapter, user)
+
+		storage.On("RemoveOAuthLink", mock.Anything, user.ID, "github").Return(nil)
+		require.NoError(t, svc.Unlink(context.Background(), user.ID))
+
+		_, err := svc.(OAuthProviderTokenRefresher).RefreshProviderToken(context.Background(), user.ID, "github")
+		assert.ErrorIs(t, err, ErrProviderTokenNotFound)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		userID := uuid.New()
+
+		adapter := &refreshingAdapter{}
+		adapter.On("ProviderID").Return("github")
+
+		_, err := NewOAuthService(&MockOAuthStorage{}, adapter).(OAuthProviderTokenRefresher).RefreshProviderToken(ctx, userID, "github")
+		assert.ErrorIs(t, err, ErrProviderTokensNotConfigured)
+
+		tokens := newMemoryTokenStorage()
+		keys := newTestKeyProvider(t)
+		svc := NewOAuthService(&MockOAuthStorage{}, adapter, WithProviderTokens(tokens, keys))
+
+		_, err = svc.(OAuthProviderTokenRefresher).RefreshProviderToken(ctx, userID, "google")
+		assert.ErrorIs(t, err, ErrProviderMismatch)
+
+		_, err = svc.(OAuthProviderTokenRefresher).RefreshProviderToken(ctx, userID, "github")
+		assert.ErrorIs(t, err, ErrProviderTokenNotFound)
+
+		plain := &MockProviderAdapter{}
+		plain.On("ProviderID").Return("github")
+		_, err = NewOAuthService(&MockOAuthStorage{}, plain, WithProviderTokens(tokens, keys)).(OAuthProviderTokenRefresher).RefreshProviderToken(ctx, userID, "github")
+		assert.ErrorIs(t, err, ErrTokenRefreshNotSupported)
+
+		impl := svc.(*oauthService)
+		require.NoError(t, impl.storeProviderToken(ctx, userID, ProviderToken{AccessToken: "access"}))
+		_, err = svc.(OAuthProviderTokenRefresher).RefreshProviderToken(ctx, userID, "github")
+		assert.ErrorIs(t, err, ErrNoRefreshToken)
+
+		require.NoError(t, impl.storeProviderToken(ctx, userID, ProviderToken{AccessToken: "access", RefreshToken: "revoked"}))
+		providerErr := errors.New("invalid_grant")
+		adapter.On("RefreshToken", mock.Anything, "revoked").Return(ProviderToken{}, providerErr)
+		_, err = svc.(OAuthProviderTokenRefresher).RefreshProviderToken(ctx, userID, "github")
+		assert.ErrorIs(t, err, ErrProviderTokenRefreshFailed)
+		assert.ErrorIs(t, err, providerErr)
+	})
+
+	t.Run("tokens are not stored without opt-in", func(t *testing.T) {
+		t.Parallel()
+
+		storage := &MockOAuthStorage{}
+		adapter := &refreshingAdapter{}
+		svc := NewOAuthService(storage, adapter)
+
+		login(t, svc, storage, adapter, &User{ID: uuid.New()})
+		storage.AssertExpectations(t)
+	})
+
+	t.Run("panics without storage or keys", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Panics(t, func() { WithProviderTokens(nil, newTestKeyProvider(t))(&oauthService{}) })
+		assert.Panics(t, func() { WithProviderTokens(newMemoryTokenStorage(), nil)(&oauthService{}) })
+	})
+}
@@ -0,0 +1,592 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/dmitrymomot/saaskit/pkg/logger"
+	"github.com/dmitrymomot/saaskit/pkg/sanitizer"
+)
+
+// User verification requirements for passkey ceremonies.
+const (
+	UserVerificationRequired    = "required"
+	UserVerificationPreferred   = "preferred"
+	UserVerificationDiscouraged = "discouraged"
+)
+
+// Purposes of a pending passkey challenge.
+const (
+	PasskeyPurposeRegistration = "registration"
+	PasskeyPurposeLogin        = "login"
+)
+
+// RelyingParty identifies the application to passkey authenticators.
+type RelyingParty struct {
+	ID      string   // Domain passkeys are scoped to, e.g. "example.com"
+	Name    string   // Human-readable name shown by authenticators
+	Origins []string // Origins of the pages calling WebAuthn, e.g. "https://app.example.com"
+}
+
+// PasskeyCredential is a passkey registered to a user.
+type PasskeyCredential struct {
+	ID         []byte // Credential ID chosen by the authenticator
+	UserID     uuid.UUID
+	PublicKey  []byte // COSE_Key encoded public key
+	SignCount  uint32 // Last seen signature counter, 0 if the authenticator doesn't keep one
+	AAGUID     []byte // Authenticator model identifier, all zeros when not disclosed
+	Transports []string
+	CreatedAt  time.Time
+	LastUsedAt time.Time
+}
+
+// PasskeyChallenge is a pending registration or login ceremony, keyed by its challenge.
+type PasskeyChallenge struct {
+	Purpose   string    // PasskeyPurposeRegistration or PasskeyPurposeLogin
+	UserID    uuid.UUID // uuid.Nil for logins with discoverable credentials
+	ExpiresAt time.Time
+}
+
+// PublicKeyCredentialCreationOptions is passed to navigator.credentials.create(),
+// e.g. through PublicKeyCredential.parseCreationOptionsFromJSON. Binary values are base64url encoded.
+type PublicKeyCredentialCreationOptions struct {
+	RP                     PublicKeyCredentialRPEntity     `json:"rp"`
+	User                   PublicKeyCredentialUserEntity   `json:"user"`
+	Challenge              string                          `json:"challenge"`
+	PubKeyCredParams       []PublicKeyCredentialParameters `json:"pubKeyCredParams"`
+	Timeout                int64                           `json:"timeout,omitempty"` // Milliseconds
+	ExcludeCredentials     []PublicKeyCredentialDescriptor `json:"excludeCredentials,omitempty"`
+	AuthenticatorSelection AuthenticatorSelectionCriteria  `json:"authenticatorSelection"`
+	Attestation            string                          `json:"attestation"`
+}
+
+// PublicKeyCredentialRequestOptions is passed to navigator.credentials.get(),
+// e.g. through PublicKeyCredential.parseRequestOptionsFromJSON. Binary values are base64url encoded.
+type PublicKeyCredentialRequestOptions struct {
+	Challenge        string                          `json:"challenge"`
+	Timeout          int64                           `json:"timeout,omitempty"` // Milliseconds
+	RPID             string                          `json:"rpId"`
+	AllowCredentials []PublicKeyCredentialDescriptor `json:"allowCredentials,omitempty"`
+	UserVerification string                          `json:"userVerification"`
+}
+
+// PublicKeyCredentialRPEntity describes the relying party.
+type PublicKeyCredentialRPEntity struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// PublicKeyCredentialUserEntity describes the user a passkey is created for.
+type PublicKeyCredentialUserEntity struct {
+	ID          string `json:"id"` // base64url encoded user ID bytes, returned as the user handle
+	Name        string `json:"name"`
+	DisplayName string `json:"displayName"`
+}
+
+// PublicKeyCredentialParameters names an acceptable credential algorithm.
+type PublicKeyCredentialParameters struct {
+	Type string `json:"type"`
+	Alg  int64  `json:"alg"`
+}
+
+// PublicKeyCredentialDescriptor references an existing credential.
+type PublicKeyCredentialDescriptor struct {
+	Type       string   `json:"type"`
+	ID         string   `json:"id"` // base64url encoded credential ID
+	Transports []string `json:"transports,omitempty"`
+}
+
+// AuthenticatorSelectionCriteria constrains which authenticators may create the passkey.
+type AuthenticatorSelectionCriteria struct {
+	ResidentKey        string `json:"residentKey"`
+	RequireResidentKey bool   `json:"requireResidentKey"`
+	UserVerification   string `json:"userVerification"`
+}
+
+// RegistrationResponse is the JSON form of the PublicKeyCredential returned by
+// navigator.credentials.create(), as produced by PublicKeyCredential.toJSON().
+type RegistrationResponse struct {
+	ID       string                           `json:"id"`
+	RawID    string                           `json:"rawId"`
+	Type     string                           `json:"type"`
+	Response AuthenticatorAttestationResponse `json:"response"`
+}
+
+// AuthenticatorAttestationResponse holds the base64url encoded registration data.
+type AuthenticatorAttestationResponse struct {
+	ClientDataJSON    string   `json:"clientDataJSON"`
+	AttestationObject string   `json:"attestationObject"`
+	Transports        []string `json:"transports,omitempty"`
+}
+
+// AssertionResponse is the JSON form of the PublicKeyCredential returned by
+// navigator.credentials.get(), as produced by PublicKeyCredential.toJSON().
+type AssertionResponse struct {
+	ID       string                         `json:"id"`
+	RawID    string                         `json:"rawId"`
+	Type     string                         `json:"type"`
+	Response AuthenticatorAssertionResponse `json:"response"`
+}
+
+// AuthenticatorAssertionResponse holds the base64url encoded login data.
+type AuthenticatorAssertionResponse struct {
+	ClientDataJSON    string `json:"clientDataJSON"`
+	AuthenticatorData string `json:"authenticatorData"`
+	Signature         string `json:"signature"`
+	UserHandle        string `json:"userHandle,omitempty"`
+}
+
+// PasskeyAuthenticator defines the interface for WebAuthn passkey registration and login.
+type PasskeyAuthenticator interface {
+	// BeginRegistration creates options for adding a passkey to an existing, signed-in user.
+	BeginRegistration(ctx context.Context, userID uuid.UUID) (*PublicKeyCredentialCreationOptions, error)
+	// FinishRegistration verifies the authenticator's response and stores the passkey.
+	FinishRegistration(ctx context.Context, userID uuid.UUID, response RegistrationResponse) (*PasskeyCredential, error)
+	// BeginLogin creates login options. An empty email allows any discoverable passkey.
+	BeginLogin(ctx context.Context, email string) (*PublicKeyCredentialRequestOptions, error)
+	// FinishLogin verifies the assertion and returns the authenticated user.
+	FinishLogin(ctx context.Context, response AssertionResponse) (*User, error)
+	// FinishLoginWithTokens verifies the assertion and issues a token pair via the configured TokenIssuer.
+	FinishLoginWithTokens(ctx context.Context, response AssertionResponse) (*User, *TokenPair, error)
+}
+
+// PasskeyStorage defines the storage interface required by the passkey service.
+type PasskeyStorage interface {
+	GetUserByID(ctx context.Context, id uuid.UUID) (*User, error)
+	GetUserByEmail(ctx context.Context, email string) (*User, error)
+
+	StorePasskey(ctx context.Context, credential *PasskeyCredential) error
+	// GetPasskey returns ErrPasskeyNotFound when no passkey has the credential ID.
+	GetPasskey(ctx context.Context, credentialID []byte) (*PasskeyCredential, error)
+	GetPasskeysByUser(ctx context.Context, userID uuid.UUID) ([]PasskeyCredential, error)
+	// UpdatePasskeySignCount records the counter and time of a successful login.
+	// Implementations should only update when signCount is greater than the stored
+	// value (e.g. WHERE sign_count < $2) and return ErrPasskeyCloned otherwise, so
+	// concurrent logins with a cloned authenticator can't both succeed.
+	// A signCount of 0 means the authenticator doesn't keep a counter.
+	UpdatePasskeySignCount(ctx context.Context, credentialID []byte, signCount uint32, lastUsedAt time.Time) error
+
+	StoreChallenge(ctx context.Context, challenge string, pending PasskeyChallenge) error
+	// ConsumeChallenge atomically returns and deletes the challenge, so each can be
+	// used once. Returns ErrPasskeyChallengeNotFound when it doesn't exist.
+	ConsumeChallenge(ctx context.Context, challenge string) (PasskeyChallenge, error)
+}
+
+type passkeyService struct {
+	storage          PasskeyStorage
+	rp               RelyingParty
+	rpIDHash         [32]byte
+	logger           *slog.Logger
+	timeout          time.Duration
+	userVerification string
+	tokenIssuer      *TokenIssuer
+}
+
+// PasskeyOption configures a passkey service during construction.
+type PasskeyOption func(*passkeyService)
+
+// WithPasskeyLogger configures the logger for the passkey service.
+func WithPasskeyLogger(logger *slog.Logger) PasskeyOption {
+	return func(s *passkeyService) {
+		s.logger = logger
+	}
+}
+
+// WithPasskeyTimeout configures how long the user has to complete a ceremony.
+// It's both the browser timeout and the challenge TTL. Default is 5 minutes.
+func WithPasskeyTimeout(timeout time.Duration) PasskeyOption {
+	return func(s *passkeyService) {
+		s.timeout = timeout
+	}
+}
+
+// WithUserVerification configures whether authenticators must verify the user,
+// e.g. with a fingerprint or PIN. Default is UserVerificationPreferred; with
+// UserVerificationRequired responses without verification are rejected.
+func WithUserVerification(requirement string) PasskeyOption {
+	return func(s *passkeyService) {
+		s.userVerification = requirement
+	}
+}
+
+// WithPasskeyTokenIssuer configures the token issuer used by FinishLoginWithTokens.
+func WithPasskeyTokenIssuer(issuer *TokenIssuer) PasskeyOption {
+	return func(s *passkeyService) {
+		s.tokenIssuer = issuer
+	}
+}
+
+// NewPasskeyService creates a passkey service for the relying party.
+// Panics if the relying party ID or origins are missing.
+func NewPasskeyService(storage PasskeyStorage, rp RelyingParty, opts ...PasskeyOption) PasskeyAuthenticator {
+	if rp.ID == "" || len(rp.Origins) == 0 {
+		panic("auth: passkey relying party ID and origins are required")
+	}
+	if rp.Name == "" {
+		rp.Name = rp.ID
+	}
+
+	s := &passkeyService{
+		storage:          storage,
+		rp:               rp,
+		rpIDHash:         sha256.Sum256([]byte(rp.ID)),
+		logger:           slog.New(slog.NewTextHandler(io.Discard, nil)),
+		timeout:          5 * time.Minute,
+		userVerification: UserVerificationPreferred,
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// BeginRegistration creates options for adding a passkey to the user. Passkeys the
+// user already has are excluded, so an authenticator isn't registered twice.
+func (s *passkeyService) BeginRegistration(ctx context.Context, userID uuid.UUID) (*PublicKeyCredentialCreationOptions, error) {
+	user, err := s.storage.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	existing, err := s.storage.GetPasskeysByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get passkeys: %w", err)
+	}
+
+	challenge, err := s.newChallenge(ctx, PasskeyPurposeRegistration, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PublicKeyCredentialCreationOptions{
+		RP: PublicKeyCredentialRPEntity{ID: s.rp.ID, Name: s.rp.Name},
+		User: PublicKeyCredentialUserEntity{
+			ID:          encodeBase64URL(user.ID[:]),
+			Name:        user.Email,
+			DisplayName: user.Email,
+		},
+		Challenge: challenge,
+		PubKeyCredParams: []PublicKeyCredentialParameters{
+			{Type: "public-key", Alg: COSEAlgES256},
+			{Type: "public-key", Alg: COSEAlgEdDSA},
+			{Type: "public-key", Alg: COSEAlgRS256},
+		},
+		Timeout:            s.timeout.Milliseconds(),
+		ExcludeCredentials: credentialDescriptors(existing),
+		AuthenticatorSelection: AuthenticatorSelectionCriteria{
+			ResidentKey:        "required", // Discoverable, so login works without an email
+			RequireResidentKey: true,
+			UserVerification:   s.userVerification,
+		},
+		Attestation: "none",
+	}, nil
+}
+
+// FinishRegistration verifies the registration response against the pending challenge
+// and stores the passkey. Attestation statements are not verified, since "none" is
+// requested: the passkey is trusted because the signed-in user registered it.
+func (s *passkeyService) FinishRegistration(ctx context.Context, userID uuid.UUID, response RegistrationResponse) (*PasskeyCredential, error) {
+	clientDataJSON, err := decodeBase64URL(response.Response.ClientDataJSON)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed client data", ErrInvalidPasskeyResponse)
+	}
+
+	pending, err := s.verifyClientData(ctx, clientDataJSON, "webauthn.create", PasskeyPurposeRegistration)
+	if err != nil {
+		return nil, err
+	}
+	if pending.UserID != userID {
+		return nil, fmt.Errorf("%w: challenge was issued to another user", ErrInvalidPasskeyResponse)
+	}
+
+	attestationObject, err := decodeBase64URL(response.Response.AttestationObject)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed attestation object", ErrInvalidPasskeyResponse)
+	}
+
+	authData, err := parseAttestationObject(attestationObject)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.verifyAuthenticatorData(authData); err != nil {
+		return nil, err
+	}
+	if len(authData.credentialID) == 0 || authData.publicKey == nil {
+		return nil, fmt.Errorf("%w: missing attested credential data", ErrInvalidPasskeyResponse)
+	}
+	if rawID, err := decodeBase64URL(response.RawID); err != nil || !bytes.Equal(rawID, authData.credentialID) {
+		return nil, fmt.Errorf("%w: credential ID mismatch", ErrInvalidPasskeyResponse)
+	}
+
+	if _, err := parseCOSEKey(authData.publicKey); err != nil {
+		return nil, err
+	}
+
+	// Credential IDs identify passkeys on login, so they must be unique across users
+	if _, err := s.storage.GetPasskey(ctx, authData.credentialID); err == nil {
+		return nil, ErrPasskeyAlreadyRegistered
+	} else if !errors.Is(err, ErrPasskeyNotFound) {
+		return nil, fmt.Errorf("failed to check passkey: %w", err)
+	}
+
+	now := time.Now()
+	credential := &PasskeyCredential{
+		ID:         bytes.Clone(authData.credentialID),
+		UserID:     userID,
+		PublicKey:  bytes.Clone(authData.publicKey),
+		SignCount:  authData.signCount,
+		AAGUID:     bytes.Clone(authData.aaguid),
+		Transports: response.Response.Transports,
+		CreatedAt:  now,
+		LastUsedAt: now,
+	}
+
+	if err := s.storage.StorePasskey(ctx, credential); err != nil {
+		return nil, fmt.Errorf("failed to store passkey: %w", err)
+	}
+
+	return credential, nil
+}
+
+// BeginLogin creates login options. With an email, only that user's passkeys are
+// allowed; unknown emails get options for discoverable passkeys instead of an error,
+// so the response doesn't reveal which emails are registered.
+func (s *passkeyService) BeginLogin(ctx context.Context, email string) (*PublicKeyCredentialRequestOptions, error) {
+	var (
+		userID uuid.UUID
+		allow  []PublicKeyCredentialDescriptor
+	)
+
+	if email != "" {
+		if user, err := s.storage.GetUserByEmail(ctx, sanitizer.NormalizeEmail(email)); err == nil {
+			passkeys, err := s.storage.GetPasskeysByUser(ctx, user.ID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get passkeys: %w", err)
+			}
+			if len(passkeys) > 0 {
+				userID = user.ID
+				allow = credentialDescriptors(passkeys)
+			}
+		}
+	}
+
+	challenge, err := s.newChallenge(ctx, PasskeyPurposeLogin, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PublicKeyCredentialRequestOptions{
+		Challenge:        challenge,
+		Timeout:          s.timeout.Milliseconds(),
+		RPID:             s.rp.ID,
+		AllowCredentials: allow,
+		UserVerification: s.userVerification,
+	}, nil
+}
+
+// FinishLogin verifies the assertion signature with the stored public key and returns
+// the passkey's user. A sign counter that didn't increase means the authenticator
+// may have been cloned, so the login is rejected with ErrPasskeyCloned.
+func (s *passkeyService) FinishLogin(ctx context.Context, response AssertionResponse) (*User, error) {
+	clientDataJSON, err := decodeBase64URL(response.Response.ClientDataJSON)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed client data", ErrInvalidPasskeyResponse)
+	}
+
+	pending, err := s.verifyClientData(ctx, clientDataJSON, "webauthn.get", PasskeyPurposeLogin)
+	if err != nil {
+		return nil, err
+	}
+
+	credentialID, err := decodeBase64URL(response.RawID)
+	if err != nil || len(credentialID) == 0 {
+		return nil, fmt.Errorf("%w: malformed credential ID", ErrInvalidPasskeyResponse)
+	}
+
+	credential, err := s.storage.GetPasskey(ctx, credentialID)
+	if err != nil {
+		if errors.Is(err, ErrPasskeyNotFound) {
+			return nil, ErrPasskeyNotFound
+		}
+		return nil, fmt.Errorf("failed to get passkey: %w", err)
+	}
+
+	if pending.UserID != uuid.Nil && pending.UserID != credential.UserID {
+		return nil, fmt.Errorf("%w: passkey belongs to another user", ErrInvalidPasskeyResponse)
+	}
+	if response.Response.UserHandle != "" {
+		userHandle, err := decodeBase64URL(response.Response.UserHandle)
+		if err != nil || !bytes.Equal(userHandle, credential.UserID[:]) {
+			return nil, fmt.Errorf("%w: user handle mismatch", ErrInvalidPasskeyResponse)
+		}
+	}
+
+	rawAuthData, err := decodeBase64URL(response.Response.AuthenticatorData)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed authenticator data", ErrInvalidPasskeyResponse)
+	}
+	authData, err := parseAuthenticatorData(rawAuthData)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.verifyAuthenticatorData(authData); err != nil {
+		return nil, err
+	}
+
+	key, err := parseCOSEKey(credential.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := decodeBase64URL(response.Response.Signature)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed signature", ErrInvalidPasskeyResponse)
+	}
+	clientDataHash := sha256.Sum256(clientDataJSON)
+	if !key.verify(append(bytes.Clone(rawAuthData), clientDataHash[:]...), signature) {
+		return nil, ErrInvalidCredentials
+	}
+
+	// Counters of 0 on both sides mean the authenticator doesn't implement one
+	if (authData.signCount != 0 || credential.SignCount != 0) && authData.signCount <= credential.SignCount {
+		s.logCloned(credential, authData.signCount)
+		return nil, ErrPasskeyCloned
+	}
+
+	if err := s.storage.UpdatePasskeySignCount(ctx, credential.ID, authData.signCount, time.Now()); err != nil {
+		if errors.Is(err, ErrPasskeyCloned) {
+			s.logCloned(credential, authData.signCount)
+			return nil, ErrPasskeyCloned
+		}
+		return nil, fmt.Errorf("failed to update passkey: %w", err)
+	}
+
+	user, err := s.storage.GetUserByID(ctx, credential.UserID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	return user, nil
+}
+
+// FinishLoginWithTokens verifies the assertion and issues a token pair.
+// Returns ErrTokenIssuerNotConfigured if the service was built without WithPasskeyTokenIssuer.
+func (s *passkeyService) FinishLoginWithTokens(ctx context.Context, response AssertionResponse) (*User, *TokenPair, error) {
+	if s.tokenIssuer == nil {
+		return nil, nil, ErrTokenIssuerNotConfigured
+	}
+
+	user, err := s.FinishLogin(ctx, response)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tokens, err := s.tokenIssuer.Issue(ctx, user)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return user, tokens, nil
+}
+
+// newChallenge generates and stores a random single-use challenge.
+func (s *passkeyService) newChallenge(ctx context.Context, purpose string, userID uuid.UUID) (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate passkey challenge: %w", err)
+	}
+	challenge := encodeBase64URL(b)
+
+	pending := PasskeyChallenge{
+		Purpose:   purpose,
+		UserID:    userID,
+		ExpiresAt: time.Now().Add(s.timeout),
+	}
+	if err := s.storage.StoreChallenge(ctx, challenge, pending); err != nil {
+		return "", fmt.Errorf("failed to store passkey challenge: %w", err)
+	}
+
+	return challenge, nil
+}
+
+// verifyClientData consumes the challenge signed by the authenticator and checks the
+// ceremony type and origin. The challenge is consumed first, so a response can't be
+// retried even when it fails verification.
+func (s *passkeyService) verifyClientData(ctx context.Context, raw []byte, ceremony, purpose string) (PasskeyChallenge, error) {
+	cd, err := parseClientData(raw)
+	if err != nil {
+		return PasskeyChallenge{}, err
+	}
+	if cd.Challenge == "" {
+		return PasskeyChallenge{}, ErrPasskeyChallengeNotFound
+	}
+
+	pending, err := s.storage.ConsumeChallenge(ctx, cd.Challenge)
+	if err != nil {
+		if errors.Is(err, ErrPasskeyChallengeNotFound) {
+			return PasskeyChallenge{}, ErrPasskeyChallengeNotFound
+		}
+		return PasskeyChallenge{}, fmt.Errorf("failed to consume passkey challenge: %w", err)
+	}
+	if pending.Purpose != purpose || time.Now().After(pending.ExpiresAt) {
+		return PasskeyChallenge{}, ErrPasskeyChallengeNotFound
+	}
+
+	if cd.Type != ceremony {
+		return PasskeyChallenge{}, fmt.Errorf("%w: unexpected client data type %q", ErrInvalidPasskeyResponse, cd.Type)
+	}
+	if cd.CrossOrigin || !slices.Contains(s.rp.Origins, cd.Origin) {
+		return PasskeyChallenge{}, fmt.Errorf("%w: origin %q not allowed", ErrInvalidPasskeyResponse, cd.Origin)
+	}
+
+	return pending, nil
+}
+
+// verifyAuthenticatorData checks the RP ID hash and the user presence and verification flags.
+func (s *passkeyService) verifyAuthenticatorData(authData authenticatorData) error {
+	if !bytes.Equal(authData.rpIDHash, s.rpIDHash[:]) {
+		return fmt.Errorf("%w: relying party ID mismatch", ErrInvalidPasskeyResponse)
+	}
+	if !authData.userPresent() {
+		return fmt.Errorf("%w: user not present", ErrInvalidPasskeyResponse)
+	}
+	if s.userVerification == UserVerificationRequired && !authData.userVerified() {
+		return fmt.Errorf("%w: user not verified", ErrInvalidPasskeyResponse)
+	}
+	return nil
+}
+
+func (s *passkeyService) logCloned(credential *PasskeyCredential, signCount uint32) {
+	s.logger.Warn("passkey sign counter regressed, possible cloned authenticator",
+		logger.UserID(credential.UserID.String()),
+		logger.Component("passkey"),
+		slog.Uint64("stored_count", uint64(credential.SignCount)),
+		slog.Uint64("received_count", uint64(signCount)),
+	)
+}
+
+func credentialDescriptors(passkeys []PasskeyCredential) []PublicKeyCredentialDescriptor {
+	descriptors := make([]PublicKeyCredentialDescriptor, 0, len(passkeys))
+	for _, p := range passkeys {
+		descriptors = append(descriptors, PublicKeyCredentialDescriptor{
+			Type:       "public-key",
+			ID:         encodeBase64URL(p.ID),
+			Transports: p.Transports,
+		})
+	}
+	return descriptors
+}
+
+// Compile-time interface assertion
+var _ PasskeyAuthenticator = (*passkeyService)(nil)
@@ -0,0 +1,658 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testRPID   = "example.com"
+	testOrigin = "https://app.example.com"
+)
+
+// memoryPasskeyStorage is an in-memory PasskeyStorage.
+type memoryPasskeyStorage struct {
+	mu         sync.Mutex
+	users      map[uuid.UUID]*User
+	passkeys   map[string]PasskeyCredential
+	challenges map[string]PasskeyChallenge
+}
+
+func newMemoryPasskeyStorage(users ...*User) *memoryPasskeyStorage {
+	s := &memoryPasskeyStorage{
+		users:      make(map[uuid.UUID]*User),
+		passkeys:   make(map[string]PasskeyCredential),
+		challenges: make(map[string]PasskeyChallenge),
+	}
+	for _, u := range users {
+		s.users[u.ID] = u
+	}
+	return s
+}
+
+func (s *memoryPasskeyStorage) GetUserByID(_ context.Context, id uuid.UUID) (*User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if u, ok := s.users[id]; ok {
+		return u, nil
+	}
+	return nil, ErrUserNotFound
+}
+
+func (s *memoryPasskeyStorage) GetUserByEmail(_ context.Context, email string) (*User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, u := range s.users {
+		if u.Email == email {
+			return u, nil
+		}
+	}
+	return nil, ErrUserNotFound
+}
+
+func (s *memoryPasskeyStorage) StorePasskey(_ context.Context, credential *PasskeyCredential) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.passkeys[string(credential.ID)] = *credential
+	return nil
+}
+
+func (s *memoryPasskeyStorage) GetPasskey(_ context.Context, credentialID []byte) (*PasskeyCredential, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if p, ok := s.passkeys[string(credentialID)]; ok {
+		return &p, nil
+	}
+	return nil, ErrPasskeyNotFound
+}
+
+func (s *memoryPasskeyStorage) GetPasskeysByUser(_ context.Context, userID uuid.UUID) ([]PasskeyCredential, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var result []PasskeyCredential
+	for _, p := range s.passkeys {
+		if p.UserID == userID {
+			result = append(result, p)
+		}
+	}
+	return result, nil
+}
+
+func (s *memoryPasskeyStorage) UpdatePasskeySignCount(_ context.Context, credentialID []byte, signCount uint32, lastUsedAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p, ok := s.passkeys[string(credentialID)]
+	if !ok {
+		return ErrPasskeyNotFound
+	}
+	if signCount != 0 && signCount <= p.SignCount {
+		return ErrPasskeyCloned
+	}
+	p.SignCount = signCount
+	p.LastUsedAt = lastUsedAt
+	s.passkeys[string(credentialID)] = p
+	return nil
+}
+
+func (s *memoryPasskeyStorage) StoreChallenge(_ context.Context, challenge string, pending PasskeyChallenge) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.challenges[challenge] = pending
+	return nil
+}
+
+func (s *memoryPasskeyStorage) ConsumeChallenge(_ context.Context, challenge string) (PasskeyChallenge, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	pending, ok := s.challenges[challenge]
+	if !ok {
+		return PasskeyChallenge{}, ErrPasskeyChallengeNotFound
+	}
+	delete(s.challenges, challenge)
+	return pending, nil
+}
+
+// softAuthenticator emulates a platform authenticator holding one passkey.
+type softAuthenticator struct {
+	rpID      string
+	origin    string
+	credID    []byte
+	userID    uuid.UUID
+	signCount uint32
+	counting  bool // Whether the sign counter increments, many synced passkeys keep it at 0
+	flags     byte
+
+	ecKey *ecdsa.PrivateKey
+	edKey ed25519.PrivateKey
+}
+
+func newSoftAuthenticator(t *testing.T) *softAuthenticator {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	credID := make([]byte, 16)
+	_, err = rand.Read(credID)
+	require.NoError(t, err)
+
+	return &softAuthenticator{
+		rpID:     testRPID,
+		origin:   testOrigin,
+		credID:   credID,
+		counting: true,
+		flags:    authDataFlagUserPresent | authDataFlagUserVerified,
+		ecKey:    key,
+	}
+}
+
+func (a *softAuthenticator) coseKey() []byte {
+	if a.edKey != nil {
+		return cborEncode(cborMap{
+			{int64(1), int64(1)},
+			{int64(3), int64(COSEAlgEdDSA)},
+			{int64(-1), int64(6)},
+			{int64(-2), []byte(a.edKey.Public().(ed25519.PublicKey))},
+		})
+	}
+	pub, err := a.ecKey.PublicKey.ECDH()
+	if err != nil {
+		panic(err)
+	}
+	point := pub.Bytes()
+	return cborEncode(cborMap{
+		{int64(1), int64(2)},
+		{int64(3), int64(COSEAlgES256)},
+		{int64(-1), int64(1)},
+		{int64(-2), point[1:33]},
+		{int64(-3), point[33:]},
+	})
+}
+
+func (a *softAuthenticator) authData(flags byte, attested []byte) []byte {
+	rpIDHash := sha256.Sum256([]byte(a.rpID))
+	data := append(rpIDHash[:], flags)
+	data = binary.BigEndian.AppendUint32(data, a.signCount)
+	return append(data, attested...)
+}
+
+func (a *softAuthenticator) clientData(typ, challenge string) []byte {
+	b, _ := json.Marshal(clientData{Type: typ, Challenge: challenge, Origin: a.origin})
+	return b
+}
+
+func (a *softAuthenticator) register(opts *PublicKeyCredentialCreationOptions) RegistrationResponse {
+	userID, _ := decodeBase64URL(opts.User.ID)
+	a.userID = uuid.UUID(userID)
+
+	attested := make([]byte, 16) // zero AAGUID
+	attested = binary.BigEndian.AppendUint16(attested, uint16(len(a.credID)))
+	attested = append(attested, a.credID...)
+	attested = append(attested, a.coseKey()...)
+
+	attestationObject := cborEncode(cborMap{
+		{"fmt", "none"},
+		{"attStmt", cborMap{}},
+		{"authData", a.authData(a.flags|authDataFlagAttestedData, attested)},
+	})
+
+	return RegistrationResponse{
+		ID:    encodeBase64URL(a.credID),
+		RawID: encodeBase64URL(a.credID),
+		Type:  "public-key",
+		Response: AuthenticatorAttestationResponse{
+			ClientDataJSON:    encodeBase64URL(a.clientData("webauthn.create", opts.Challenge)),
+			AttestationObject: encodeBase64URL(attestationObject),
+			Transports:        []string{"internal"},
+		},
+	}
+}
+
+func (a *softAuthenticator) login(opts *PublicKeyCredentialRequestOptions) AssertionResponse {
+	if a.counting {
+		a.signCount++
+	}
+	authData := a.authData(a.flags, nil)
+	clientDataJSON := a.clientData("webauthn.get", opts.Challenge)
+	clientDataHash := sha256.Sum256(clientDataJSON)
+	signed := append(bytes.Clone(authData), clientDataHash[:]...)
+
+	var sig []byte
+	if a.edKey != nil {
+		sig = ed25519.Sign(a.edKey, signed)
+	} else {
+		digest := sha256.Sum256(signed)
+		var err error
+		sig, err = ecdsa.SignASN1(rand.Reader, a.ecKey, digest[:])
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	return AssertionResponse{
+		ID:    encodeBase64URL(a.credID),
+		RawID: encodeBase64URL(a.credID),
+		Type:  "public-key",
+		Response: AuthenticatorAssertionResponse{
+			ClientDataJSON:    encodeBase64URL(clientDataJSON),
+			AuthenticatorData: encodeBase64URL(authData),
+			Signature:         encodeBase64URL(sig),
+			UserHandle:        encodeBase64URL(a.userID[:]),
+		},
+	}
+}
+
+// cborMap keeps map entries in order for deterministic encoding.
+type cborMap []struct{ k, v any }
+
+func cborEncode(v any) []byte {
+	head := func(major byte, n uint64) []byte {
+		switch {
+		case n < 24:
+			return []byte{major<<5 | byte(n)}
+		case n <= 0xff:
+			return []byte{major<<5 | 24, byte(n)}
+		case n <= 0xffff:
+			return binary.BigEndian.AppendUint16([]byte{major<<5 | 25}, uint16(n))
+		default:
+			return binary.BigEndian.AppendUint32([]byte{major<<5 | 26}, uint32(n))
+		}
+	}
+
+	switch v := v.(type) {
+	case int64:
+		if v < 0 {
+			return head(1, uint64(-1-v))
+		}
+		return head(0, uint64(v))
+	case []byte:
+		return append(head(2, uint64(len(v))), v...)
+	case string:
+		return append(head(3, uint64(len(v))), v...)
+	case []any:
+		out := head(4, uint64(len(v)))
+		for _, item := range v {
+			out = append(out, cborEncode(item)...)
+		}
+		return out
+	case cborMap:
+		out := head(5, uint64(len(v)))
+		for _, e := range v {
+			out = append(out, cborEncode(e.k)...)
+			out = append(out, cborEncode(e.v)...)
+		}
+		return out
+	case bool:
+		if v {
+			return []byte{0xf5}
+		}
+		return []byte{0xf4}
+	}
+	panic("unsupported CBOR value")
+}
+
+func newTestPasskeyService(t *testing.T, opts ...PasskeyOption) (PasskeyAuthenticator, *memoryPasskeyStorage, *User) {
+	t.Helper()
+	user := &User{ID: uuid.New(), Email: "user@example.com", IsVerified: true}
+	storage := newMemoryPasskeyStorage(user)
+	svc := NewPasskeyService(storage, RelyingParty{ID: testRPID, Name: "Example", Origins: []string{testOrigin}}, opts...)
+	return svc, storage, user
+}
+
+func registerPasskey(t *testing.T, svc PasskeyAuthenticator, userID uuid.UUID, a *softAuthenticator) *PasskeyCredential {
+	t.Helper()
+	opts, err := svc.BeginRegistration(context.Background(), userID)
+	require.NoError(t, err)
+	credential, err := svc.FinishRegistration(context.Background(), userID, a.register(opts))
+	require.NoError(t, err)
+	return credential
+}
+
+func TestNewPasskeyService(t *testing.T) {
+	t.Parallel()
+
+	t.Run("panics without relying party", func(t *testing.T) {
+		t.Parallel()
+		assert.Panics(t, func() { NewPasskeyService(newMemoryPasskeyStorage(), RelyingParty{ID: testRPID}) })
+		assert.Panics(t, func() { NewPasskeyService(newMemoryPasskeyStorage(), RelyingParty{Origins: []string{testOrigin}}) })
+	})
+
+	t.Run("applies defaults and options", func(t *testing.T) {
+		t.Parallel()
+		svc := NewPasskeyService(newMemoryPasskeyStorage(), RelyingParty{ID: testRPID, Origins: []string{testOrigin}},
+			WithPasskeyTimeout(time.Minute),
+			WithUserVerification(UserVerificationRequired),
+		)
+
+		impl := svc.(*passkeyService)
+		assert.Equal(t, testRPID, impl.rp.Name)
+		assert.Equal(t, time.Minute, impl.timeout)
+		assert.Equal(t, UserVerificationRequired, impl.userVerification)
+		assert.NotNil(t, impl.logger)
+	})
+}
+
+func TestPasskeyService_Registration(t *testing.T) {
+	t.Parallel()
+
+	t.Run("registers a passkey", func(t *testing.T) {
+		t.Parallel()
+		svc, storage, user := newTestPasskeyService(t)
+
+		opts, err := svc.BeginRegistration(context.Background(), user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, testRPID, opts.RP.ID)
+		assert.Equal(t, encodeBase64URL(user.ID[:]), opts.User.ID)
+		assert.Equal(t, "none", opts.Attestation)
+		assert.Equal(t, "required", opts.AuthenticatorSelection.ResidentKey)
+		assert.Empty(t, opts.ExcludeCredentials)
+
+		a := newSoftAuthenticator(t)
+		credential, err := svc.FinishRegistration(context.Background(), user.ID, a.register(opts))
+		require.NoError(t, err)
+		assert.Equal(t, a.credID, credential.ID)
+		assert.Equal(t, user.ID, credential.UserID)
+		assert.Equal(t, []string{"internal"}, credential.Transports)
+
+		stored, err := storage.GetPasskey(context.Background(), a.credID)
+		require.NoError(t, err)
+		assert.Equal(t, credential.PublicKey, stored.PublicKey)
+
+		// Existing passkeys are excluded from the next registration
+		opts, err = svc.BeginRegistration(context.Background(), user.ID)
+		require.NoError(t, err)
+		require.Len(t, opts.ExcludeCredentials, 1)
+		assert.Equal(t, encodeBase64URL(a.credID), opts.ExcludeCredentials[0].ID)
+	})
+
+	t.Run("rejects duplicate credential", func(t *testing.T) {
+		t.Parallel()
+		svc, _, user := newTestPasskeyService(t)
+		a := newSoftAuthenticator(t)
+		registerPasskey(t, svc, user.ID, a)
+
+		opts, err := svc.BeginRegistration(context.Background(), user.ID)
+		require.NoError(t, err)
+		_, err = svc.FinishRegistration(context.Background(), user.ID, a.register(opts))
+		assert.ErrorIs(t, err, ErrPasskeyAlreadyRegistered)
+	})
+
+	t.Run("rejects replayed challenge", func(t *testing.T) {
+		t.Parallel()
+		svc, _, user := newTestPasskeyService(t)
+
+		opts, err := svc.BeginRegistration(context.Background(), user.ID)
+		require.NoError(t, err)
+		response := newSoftAuthenticator(t).register(opts)
+
+		_, err = svc.FinishRegistration(context.Background(), user.ID, response)
+		require.NoError(t, err)
+		_, err = svc.FinishRegistration(context.Background(), user.ID, response)
+		assert.ErrorIs(t, err, ErrPasskeyChallengeNotFound)
+	})
+
+	t.Run("rejects challenge of another user", func(t *testing.T) {
+		t.Parallel()
+		svc, _, user := newTestPasskeyService(t)
+
+		opts, err := svc.BeginRegistration(context.Background(), user.ID)
+		require.NoError(t, err)
+		_, err = svc.FinishRegistration(context.Background(), uuid.New(), newSoftAuthenticator(t).register(opts))
+		assert.ErrorIs(t, err, ErrInvalidPasskeyResponse)
+	})
+
+	t.Run("rejects wrong origin and relying party", func(t *testing.T) {
+		t.Parallel()
+		svc, _, user := newTestPasskeyService(t)
+
+		a := newSoftAuthenticator(t)
+		a.origin = "https://evil.example.net"
+		opts, err := svc.BeginRegistration(context.Background(), user.ID)
+		require.NoError(t, err)
+		_, err = svc.FinishRegistration(context.Background(), user.ID, a.register(opts))
+		assert.ErrorIs(t, err, ErrInvalidPasskeyResponse)
+
+		a = newSoftAuthenticator(t)
+		a.rpID = "evil.example.net"
+		opts, err = svc.BeginRegistration(context.Background(), user.ID)
+		require.NoError(t, err)
+		_, err = svc.FinishRegistration(context.Background(), user.ID, a.register(opts))
+		assert.ErrorIs(t, err, ErrInvalidPasskeyResponse)
+	})
+
+	t.Run("requires user verification when configured", func(t *testing.T) {
+		t.Parallel()
+		svc, _, user := newTestPasskeyService(t, WithUserVerification(UserVerificationRequired))
+
+		a := newSoftAuthenticator(t)
+		a.flags = authDataFlagUserPresent
+		opts, err := svc.BeginRegistration(context.Background(), user.ID)
+		require.NoError(t, err)
+		_, err = svc.FinishRegistration(context.Background(), user.ID, a.register(opts))
+		assert.ErrorIs(t, err, ErrInvalidPasskeyResponse)
+	})
+
+	t.Run("unknown user", func(t *testing.T) {
+		t.Parallel()
+		svc, _, _ := newTestPasskeyService(t)
+		_, err := svc.BeginRegistration(context.Background(), uuid.New())
+		assert.ErrorIs(t, err, ErrUserNotFound)
+	})
+}
+
+func TestPasskeyService_Login(t *testing.T) {
+	t.Parallel()
+
+	t.Run("discoverable login", func(t *testing.T) {
+		t.Parallel()
+		svc, storage, user := newTestPasskeyService(t)
+		a := newSoftAuthenticator(t)
+		registerPasskey(t, svc, user.ID, a)
+
+		opts, err := svc.BeginLogin(context.Background(), "")
+		require.NoError(t, err)
+		assert.Equal(t, testRPID, opts.RPID)
+		assert.Empty(t, opts.AllowCredentials)
+
+		got, err := svc.FinishLogin(context.Background(), a.login(opts))
+		require.NoError(t, err)
+		assert.Equal(t, user.ID, got.ID)
+
+		stored, err := storage.GetPasskey(context.Background(), a.credID)
+		require.NoError(t, err)
+		assert.Equal(t, uint32(1), stored.SignCount)
+	})
+
+	t.Run("login with email allows only the user's passkeys", func(t *testing.T) {
+		t.Parallel()
+		svc, storage, user := newTestPasskeyService(t)
+		a := newSoftAuthenticator(t)
+		registerPasskey(t, svc, user.ID, a)
+
+		other := &User{ID: uuid.New(), Email: "other@example.com"}
+		storage.users[other.ID] = other
+		b := newSoftAuthenticator(t)
+		registerPasskey(t, svc, other.ID, b)
+
+		opts, err := svc.BeginLogin(context.Background(), " User@Example.com ")
+		require.NoError(t, err)
+		require.Len(t, opts.AllowCredentials, 1)
+		assert.Equal(t, encodeBase64URL(a.credID), opts.AllowCredentials[0].ID)
+
+		// Another user's passkey can't answer a challenge bound to this user
+		_, err = svc.FinishLogin(context.Background(), b.login(opts))
+		assert.ErrorIs(t, err, ErrInvalidPasskeyResponse)
+	})
+
+	t.Run("unknown email gets discoverable options", func(t *testing.T) {
+		t.Parallel()
+		svc, _, _ := newTestPasskeyService(t)
+
+		opts, err := svc.BeginLogin(context.Background(), "nobody@example.com")
+		require.NoError(t, err)
+		assert.Empty(t, opts.AllowCredentials)
+		assert.NotEmpty(t, opts.Challenge)
+	})
+
+	t.Run("rejects cloned authenticator", func(t *testing.T) {
+		t.Parallel()
+		svc, _, user := newTestPasskeyService(t)
+		a := newSoftAuthenticator(t)
+		registerPasskey(t, svc, user.ID, a)
+
+		clone := *a
+
+		opts, err := svc.BeginLogin(context.Background(), "")
+		require.NoError(t, err)
+		_, err = svc.FinishLogin(context.Background(), a.login(opts))
+		require.NoError(t, err)
+
+		// The clone's counter didn't advance with the original's
+		opts, err = svc.BeginLogin(context.Background(), "")
+		require.NoError(t, err)
+		_, err = svc.FinishLogin(context.Background(), clone.login(opts))
+		assert.ErrorIs(t, err, ErrPasskeyCloned)
+	})
+
+	t.Run("allows authenticators without counter", func(t *testing.T) {
+		t.Parallel()
+		svc, _, user := newTestPasskeyService(t)
+		a := newSoftAuthenticator(t)
+		a.counting = false
+		registerPasskey(t, svc, user.ID, a)
+
+		for range 2 {
+			opts, err := svc.BeginLogin(context.Background(), "")
+			require.NoError(t, err)
+			_, err = svc.FinishLogin(context.Background(), a.login(opts))
+			require.NoError(t, err)
+		}
+	})
+
+	t.Run("rejects replayed assertion", func(t *testing.T) {
+		t.Parallel()
+		svc, _, user := newTestPasskeyService(t)
+		a := newSoftAuthenticator(t)
+		registerPasskey(t, svc, user.ID, a)
+
+		opts, err := svc.BeginLogin(context.Background(), "")
+		require.NoError(t, err)
+		response := a.login(opts)
+
+		_, err = svc.FinishLogin(context.Background(), response)
+		require.NoError(t, err)
+		_, err = svc.FinishLogin(context.Background(), response)
+		assert.ErrorIs(t, err, ErrPasskeyChallengeNotFound)
+	})
+
+	t.Run("rejects registration challenge", func(t *testing.T) {
+		t.Parallel()
+		svc, _, user := newTestPasskeyService(t)
+		a := newSoftAuthenticator(t)
+		registerPasskey(t, svc, user.ID, a)
+
+		regOpts, err := svc.BeginRegistration(context.Background(), user.ID)
+		require.NoError(t, err)
+		_, err = svc.FinishLogin(context.Background(), a.login(&PublicKeyCredentialRequestOptions{Challenge: regOpts.Challenge}))
+		assert.ErrorIs(t, err, ErrPasskeyChallengeNotFound)
+	})
+
+	t.Run("rejects invalid signature", func(t *testing.T) {
+		t.Parallel()
+		svc, _, user := newTestPasskeyService(t)
+		a := newSoftAuthenticator(t)
+		registerPasskey(t, svc, user.ID, a)
+
+		// Signs with a different key than the registered one
+		other := newSoftAuthenticator(t)
+		a.ecKey = other.ecKey
+
+		opts, err := svc.BeginLogin(context.Background(), "")
+		require.NoError(t, err)
+		_, err = svc.FinishLogin(context.Background(), a.login(opts))
+		assert.ErrorIs(t, err, ErrInvalidCredentials)
+	})
+
+	t.Run("unknown passkey", func(t *testing.T) {
+		t.Parallel()
+		svc, _, _ := newTestPasskeyService(t)
+
+		opts, err := svc.BeginLogin(context.Background(), "")
+		require.NoError(t, err)
+		_, err = svc.FinishLogin(context.Background(), newSoftAuthenticator(t).login(opts))
+		assert.ErrorIs(t, err, ErrPasskeyNotFound)
+	})
+
+	t.Run("Ed25519 passkey", func(t *testing.T) {
+		t.Parallel()
+		svc, _, user := newTestPasskeyService(t)
+		a := newSoftAuthenticator(t)
+		_, edKey, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+		a.edKey = edKey
+		registerPasskey(t, svc, user.ID, a)
+
+		opts, err := svc.BeginLogin(context.Background(), "")
+		require.NoError(t, err)
+		_, err = svc.FinishLogin(context.Background(), a.login(opts))
+		assert.NoError(t, err)
+	})
+
+	t.Run("with tokens requires issuer", func(t *testing.T) {
+		t.Parallel()
+		svc, _, _ := newTestPasskeyService(t)
+		_, _, err := svc.FinishLoginWithTokens(context.Background(), AssertionResponse{})
+		assert.ErrorIs(t, err, ErrTokenIssuerNotConfigured)
+	})
+}
+
+func TestDecodeCBOR(t *testing.T) {
+	t.Parallel()
+
+	t.Run("round trip", func(t *testing.T) {
+		t.Parallel()
+		encoded := cborEncode(cborMap{
+			{"a", int64(-300)},
+			{int64(1), []any{[]byte{1, 2}, "x", true}},
+		})
+		encoded = append(encoded, 0xff)
+
+		v, rest, err := decodeCBOR(encoded)
+		require.NoError(t, err)
+		assert.Equal(t, []byte{0xff}, rest)
+		assert.Equal(t, map[any]any{
+			"a":      int64(-300),
+			int64(1): []any{[]byte{1, 2}, "x", true},
+		}, v)
+	})
+
+	t.Run("malformed input", func(t *testing.T) {
+		t.Parallel()
+		for name, data := range map[string][]byte{
+			"empty":             {},
+			"truncated string":  {0x45, 0x01},
+			"indefinite length": {0x5f},
+			"float":             {0xf9, 0x00, 0x00},
+			"map with map key":  {0xa1, 0xa0, 0x01},
+			"huge array":        {0x9a, 0xff, 0xff, 0xff, 0xff},
+			"too deep":          bytes.Repeat([]byte{0x81}, cborMaxDepth+2),
+		} {
+			_, _, err := decodeCBOR(data)
+			assert.True(t, errors.Is(err, errCBOR), name)
+		}
+	})
+}
@@ -0,0 +1,370 @@
+package auth
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+)
+
+// COSE algorithm identifiers supported for passkey signatures.
+const (
+	COSEAlgES256 = -7   // ECDSA P-256 with SHA-256
+	COSEAlgEdDSA = -8   // Ed25519
+	COSEAlgRS256 = -257 // RSASSA-PKCS1-v1_5 with SHA-256
+)
+
+// Authenticator data flags (WebAuthn §6.1).
+const (
+	authDataFlagUserPresent  = 0x01
+	authDataFlagUserVerified = 0x04
+	authDataFlagAttestedData = 0x40
+	authDataFlagExtensions   = 0x80
+)
+
+// clientData is the subset of CollectedClientData checked by the relying party.
+type clientData struct {
+	Type        string `json:"type"`
+	Challenge   string `json:"challenge"`
+	Origin      string `json:"origin"`
+	CrossOrigin bool   `json:"crossOrigin"`
+}
+
+func parseClientData(raw []byte) (clientData, error) {
+	var cd clientData
+	if err := json.Unmarshal(raw, &cd); err != nil {
+		return clientData{}, fmt.Errorf("%w: malformed client data", ErrInvalidPasskeyResponse)
+	}
+	return cd, nil
+}
+
+// authenticatorData is the parsed authenticator data (WebAuthn §6.1).
+type authenticatorData struct {
+	raw       []byte
+	rpIDHash  []byte
+	flags     byte
+	signCount uint32
+
+	// Attested credential data, present during registration
+	aaguid       []byte
+	credentialID []byte
+	publicKey    []byte // COSE_Key encoded
+}
+
+func (d authenticatorData) userPresent() bool  { return d.flags&authDataFlagUserPresent != 0 }
+func (d authenticatorData) userVerified() bool { return d.flags&authDataFlagUserVerified != 0 }
+
+func parseAuthenticatorData(raw []byte) (authenticatorData, error) {
+	const headerLen = 32 + 1 + 4
+	if len(raw) < headerLen {
+		return authenticatorData{}, fmt.Errorf("%w: authenticator data too short", ErrInvalidPasskeyResponse)
+	}
+
+	d := authenticatorData{
+		raw:       raw,
+		rpIDHash:  raw[:32],
+		flags:     raw[32],
+		signCount: binary.BigEndian.Uint32(raw[33:37]),
+	}
+
+	rest := raw[headerLen:]
+	if d.flags&authDataFlagAttestedData != 0 {
+		if len(rest) < 18 {
+			return authenticatorData{}, fmt.Errorf("%w: attested credential data too short", ErrInvalidPasskeyResponse)
+		}
+		d.aaguid = rest[:16]
+		idLen := int(binary.BigEndian.Uint16(rest[16:18]))
+		rest = rest[18:]
+		if len(rest) < idLen {
+			return authenticatorData{}, fmt.Errorf("%w: credential ID truncated", ErrInvalidPasskeyResponse)
+		}
+		d.credentialID = rest[:idLen]
+		rest = rest[idLen:]
+
+		// The COSE key has no length prefix, decoding it tells where it ends
+		_, remaining, err := decodeCBOR(rest)
+		if err != nil {
+			return authenticatorData{}, fmt.Errorf("%w: malformed credential public key", ErrInvalidPasskeyResponse)
+		}
+		d.publicKey = rest[:len(rest)-len(remaining)]
+		rest = remaining
+	}
+
+	if d.flags&authDataFlagExtensions != 0 {
+		_, remaining, err := decodeCBOR(rest)
+		if err != nil {
+			return authenticatorData{}, fmt.Errorf("%w: malformed extensions", ErrInvalidPasskeyResponse)
+		}
+		rest = remaining
+	}
+
+	if len(rest) != 0 {
+		return authenticatorData{}, fmt.Errorf("%w: trailing authenticator data", ErrInvalidPasskeyResponse)
+	}
+
+	return d, nil
+}
+
+// parseAttestationObject extracts the authenticator data from a CBOR attestation object.
+// Attestation statements are not verified, as the service requests "none" conveyance.
+func parseAttestationObject(raw []byte) (authenticatorData, error) {
+	v, rest, err := decodeCBOR(raw)
+	if err != nil || len(rest) != 0 {
+		return authenticatorData{}, fmt.Errorf("%w: malformed attestation object", ErrInvalidPasskeyResponse)
+	}
+
+	obj, ok := v.(map[any]any)
+	if !ok {
+		return authenticatorData{}, fmt.Errorf("%w: malformed attestation object", ErrInvalidPasskeyResponse)
+	}
+	authData, ok := obj["authData"].([]byte)
+	if !ok {
+		return authenticatorData{}, fmt.Errorf("%w: attestation object without authenticator data", ErrInvalidPasskeyResponse)
+	}
+
+	return parseAuthenticatorData(authData)
+}
+
+// coseKey is a credential public key decoded from its COSE_Key encoding.
+type coseKey struct {
+	alg int64
+	key crypto.PublicKey
+}
+
+// parseCOSEKey decodes an EC2 (P-256), OKP (Ed25519) or RSA COSE_Key.
+func parseCOSEKey(raw []byte) (coseKey, error) {
+	v, rest, err := decodeCBOR(raw)
+	if err != nil || len(rest) != 0 {
+		return coseKey{}, fmt.Errorf("%w: malformed public key", ErrInvalidPasskeyResponse)
+	}
+	m, ok := v.(map[any]any)
+	if !ok {
+		return coseKey{}, fmt.Errorf("%w: malformed public key", ErrInvalidPasskeyResponse)
+	}
+
+	kty, _ := m[int64(1)].(int64)
+	alg, _ := m[int64(3)].(int64)
+	crv, _ := m[int64(-1)].(int64)
+
+	switch {
+	case alg == COSEAlgES256 && kty == 2 && crv == 1:
+		x, _ := m[int64(-2)].([]byte)
+		y, _ := m[int64(-3)].([]byte)
+		if len(x) != 32 || len(y) != 32 {
+			return coseKey{}, fmt.Errorf("%w: invalid EC2 coordinates", ErrInvalidPasskeyResponse)
+		}
+		// Validates the uncompressed point is on the curve
+		point := append(append([]byte{0x04}, x...), y...)
+		if _, err := ecdh.P256().NewPublicKey(point); err != nil {
+			return coseKey{}, fmt.Errorf("%w: invalid EC2 public key", ErrInvalidPasskeyResponse)
+		}
+		pub := &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		return coseKey{alg: alg, key: pub}, nil
+
+	case alg == COSEAlgEdDSA && kty == 1 && crv == 6:
+		x, _ := m[int64(-2)].([]byte)
+		if len(x) != ed25519.PublicKeySize {
+			return coseKey{}, fmt.Errorf("%w: invalid Ed25519 public key", ErrInvalidPasskeyResponse)
+		}
+		return coseKey{alg: alg, key: ed25519.PublicKey(x)}, nil
+
+	case alg == COSEAlgRS256 && kty == 3:
+		n, _ := m[int64(-1)].([]byte)
+		e, _ := m[int64(-2)].([]byte)
+		if len(n) < 256 || len(e) == 0 || len(e) > 4 {
+			return coseKey{}, fmt.Errorf("%w: invalid RSA public key", ErrInvalidPasskeyResponse)
+		}
+		var exp int
+		for _, b := range e {
+			exp = exp<<8 | int(b)
+		}
+		if exp < 3 || exp%2 == 0 {
+			return coseKey{}, fmt.Errorf("%w: invalid RSA exponent", ErrInvalidPasskeyResponse)
+		}
+		return coseKey{alg: alg, key: &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: exp}}, nil
+	}
+
+	return coseKey{}, fmt.Errorf("%w: kty %d, alg %d", ErrUnsupportedPasskeyAlgorithm, kty, alg)
+}
+
+// verify checks a WebAuthn assertion signature over authenticatorData || SHA-256(clientDataJSON).
+func (k coseKey) verify(signed, sig []byte) bool {
+	switch pub := k.key.(type) {
+	case *ecdsa.PublicKey:
+		digest := sha256.Sum256(signed)
+		return ecdsa.VerifyASN1(pub, digest[:], sig)
+	case ed25519.PublicKey:
+		return ed25519.Verify(pub, signed, sig)
+	case *rsa.PublicKey:
+		digest := sha256.Sum256(signed)
+		return rsa.VerifyPKCS1v15(pub, crypto.SHA256, digest[:], sig) == nil
+	}
+	return false
+}
+
+// decodeBase64URL decodes the unpadded base64url used by WebAuthn JSON, tolerating padding.
+func decodeBase64URL(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+}
+
+func encodeBase64URL(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// Limits for decodeCBOR; WebAuthn structures are small, so anything larger is malformed.
+const (
+	cborMaxDepth = 16
+	cborMaxItems = 1024
+)
+
+var errCBOR = errors.New("malformed CBOR")
+
+// decodeCBOR decodes the first CBOR data item in data and returns the unconsumed rest.
+// It supports the definite-length subset used by WebAuthn: integers (as int64),
+// byte and text strings, arrays, maps (as map[any]any), booleans and null.
+func decodeCBOR(data []byte) (any, []byte, error) {
+	d := cborDecoder{data: data}
+	v, err := d.decode(0)
+	if err != nil {
+		return nil, nil, err
+	}
+	return v, d.data[d.pos:], nil
+}
+
+type cborDecoder struct {
+	data []byte
+	pos  int
+}
+
+func (d *cborDecoder) decode(depth int) (any, error) {
+	if depth > cborMaxDepth {
+		return nil, errCBOR
+	}
+
+	major, arg, err := d.head()
+	if err != nil {
+		return nil, err
+	}
+
+	switch major {
+	case 0: // unsigned integer
+		if arg > math.MaxInt64 {
+			return nil, errCBOR
+		}
+		return int64(arg), nil
+	case 1: // negative integer, -1 - arg
+		if arg > math.MaxInt64 {
+			return nil, errCBOR
+		}
+		return -1 - int64(arg), nil
+	case 2, 3: // byte string, text string
+		b, err := d.bytes(arg)
+		if err != nil {
+			return nil, err
+		}
+		if major == 3 {
+			return string(b), nil
+		}
+		return bytes.Clone(b), nil
+	case 4: // array
+		if arg > cborMaxItems {
+			return nil, errCBOR
+		}
+		items := make([]any, 0, arg)
+		for range arg {
+			v, err := d.decode(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, v)
+		}
+		return items, nil
+	case 5: // map
+		if arg > cborMaxItems {
+			return nil, errCBOR
+		}
+		m := make(map[any]any, arg)
+		for range arg {
+			k, err := d.decode(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			switch k.(type) {
+			case int64, string:
+			default:
+				return nil, errCBOR
+			}
+			v, err := d.decode(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			m[k] = v
+		}
+		return m, nil
+	case 6: // tag, the tagged item is returned as is
+		return d.decode(depth + 1)
+	case 7: // simple values
+		switch arg {
+		case 20:
+			return false, nil
+		case 21:
+			return true, nil
+		case 22:
+			return nil, nil
+		}
+	}
+
+	return nil, errCBOR
+}
+
+// head reads an item's major type and argument. Indefinite lengths are rejected.
+func (d *cborDecoder) head() (byte, uint64, error) {
+	if d.pos >= len(d.data) {
+		return 0, 0, errCBOR
+	}
+	initial := d.data[d.pos]
+	d.pos++
+
+	major, info := initial>>5, initial&0x1f
+	switch {
+	case info < 24:
+		return major, uint64(info), nil
+	case info <= 27:
+		size := 1 << (info - 24)
+		b, err := d.bytes(uint64(size))
+		if err != nil {
+			return 0, 0, err
+		}
+		var arg uint64
+		for _, c := range b {
+			arg = arg<<8 | uint64(c)
+		}
+		// Floats use the same encoding in major type 7 but aren't supported
+		if major == 7 {
+			return 0, 0, errCBOR
+		}
+		return major, arg, nil
+	}
+	return 0, 0, errCBOR
+}
+
+func (d *cborDecoder) bytes(n uint64) ([]byte, error) {
+	if n > uint64(len(d.data)-d.pos) {
+		return nil, errCBOR
+	}
+	b := d.data[d.pos : d.pos+int(n)]
+	d.pos += int(n)
+	return b, nil
+}
@@ -33,6 +33,28 @@ type PasswordAuthenticator interface {
 	ResetPassword(ctx context.Context, resetToken, newPassword string) (*User, error)
 }
 
+// PasswordTokenAuthenticator is implemented by the service returned from NewPasswordService
+// for token-based clients. It's separate from PasswordAuthenticator so existing
+// implementations of that interface keep compiling:
+//
+//	tokenAuth := passwordAuth.(auth.PasswordTokenAuthenticator)
+type PasswordTokenAuthenticator interface {
+	// AuthenticateWithTokens authenticates and issues a token pair via the configured TokenIssuer.
+	AuthenticateWithTokens(ctx context.Context, email, password string) (*User, *TokenPair, error)
+}
+
+// PasswordReauthenticator is implemented by the service returned from NewPasswordService
+// for step-up authentication. It's separate from PasswordAuthenticator so existing
+// implementations of that interface keep compiling:
+//
+//	reauth := passwordAuth.(auth.PasswordReauthenticator)
+type PasswordReauthenticator interface {
+	// Reauthenticate re-verifies the password of a signed-in user for step-up authentication.
+	Reauthenticate(ctx context.Context, userID uuid.UUID, password string) (time.Time, error)
+	// ReauthenticateWithTokens re-verifies the password and issues tokens with a fresh auth_at claim.
+	ReauthenticateWithTokens(ctx context.Context, userID uuid.UUID, password string) (*TokenPair, error)
+}
+
 // PasswordStorage defines the storage interface required by password services.
 type PasswordStorage interface {
 	CreateUser(ctx context.Context, user *User) error
@@ -47,9 +69,19 @@ type passwordService struct {
 	storage          PasswordStorage
 	tokenSecret      string
 	bcryptCost       int
+	hasher           Hasher
 	logger           *slog.Logger
 	resetTokenTTL    time.Duration
 	passwordStrength validator.PasswordStrengthConfig
+	tokenIssuer      *TokenIssuer
+	loginLimiter     LoginRateLimiter
+	loginKey         LoginKeyFunc
+	breachChecker    BreachChecker
+	passwordHistory  int // Number of recent passwords, current included, that ResetPassword rejects; 0 disables the check
+
+	// Account lockout, disabled when maxFailedAttempts is 0
+	maxFailedAttempts int
+	lockoutDuration   time.Duration
 
 	// Hooks for extending password authentication behavior
 	afterRegister func(ctx context.Context, user *User) error
@@ -68,6 +100,7 @@ func WithPasswordLogger(logger *slog.Logger) PasswordOption {
 }
 
 // WithBcryptCost configures the bcrypt cost parameter for password hashing.
+// Ignored when WithHasher is set.
 func WithBcryptCost(cost int) PasswordOption {
 	return func(s *passwordService) {
 		s.bcryptCost = cost
@@ -88,6 +121,13 @@ func WithPasswordStrength(config validator.PasswordStrengthConfig) PasswordOptio
 	}
 }
 
+// WithPasswordTokenIssuer configures the token issuer used by AuthenticateWithTokens.
+func WithPasswordTokenIssuer(issuer *TokenIssuer) PasswordOption {
+	return func(s *passwordService) {
+		s.tokenIssuer = issuer
+	}
+}
+
 // WithAfterRegister configures a hook that runs after successful user registration (async).
 func WithAfterRegister(fn func(context.Context, *User) error) PasswordOption {
 	return func(s *passwordService) {
@@ -109,14 +149,16 @@ func WithAfterLogin(fn func(context.Context, *User) error) PasswordOption {
 	}
 }
 
-// NewPasswordService creates a password service with bcrypt hashing and configurable options.
+// NewPasswordService creates a password service with bcrypt hashing (unless WithHasher is set) and configurable options.
 func NewPasswordService(storage PasswordStorage, tokenSecret string, opts ...PasswordOption) PasswordAuthenticator {
 	s := &passwordService{
-		storage:       storage,
-		tokenSecret:   tokenSecret,
-		bcryptCost:    bcrypt.DefaultCost,
-		logger:        slog.New(slog.NewTextHandler(io.Discard, nil)),
-		resetTokenTTL: 1 * time.Hour,
+		storage:         storage,
+		tokenSecret:     tokenSecret,
+		bcryptCost:      bcrypt.DefaultCost,
+		logger:          slog.New(slog.NewTextHandler(io.Discard, nil)),
+		resetTokenTTL:   1 * time.Hour,
+		loginKey:        LoginKeyByEmail,
+		lockoutDuration: DefaultLockoutDuration,
 		passwordStrength: validator.PasswordStrengthConfig{
 			MinLength:      8,
 			MaxLength:      128,
@@ -128,6 +170,22 @@ func NewPasswordService(storage PasswordStorage, tokenSecret string, opts ...Pas
 		opt(s)
 	}
 
+	if s.hasher == nil {
+		s.hasher = NewBcryptHasher(s.bcryptCost)
+	}
+
+	if s.maxFailedAttempts > 0 {
+		if _, ok := storage.(LoginAttemptStore); !ok {
+			panic("auth: WithMaxFailedAttempts requires storage implementing LoginAttemptStore")
+		}
+	}
+
+	if s.passwordHistory > 0 {
+		if _, ok := storage.(PasswordHistoryStore); !ok {
+			panic("auth: WithResetPasswordHistory requires storage implementing PasswordHistoryStore")
+		}
+	}
+
 	return s
 }
 
@@ -151,7 +209,11 @@ func (s *passwordService) Register(ctx context.Context, email, password string)
 		return nil, fmt.Errorf("failed to check existing user: %w", err)
 	}
 
-	hash, err := bcrypt.GenerateFromPassword([]byte(password), s.bcryptCost)
+	if err := checkBreached(ctx, s.breachChecker, password); err != nil {
+		return nil, err
+	}
+
+	hash, err := s.hasher.Hash(password)
 	if err != nil {
 		return nil, fmt.Errorf("failed to hash password: %w", err)
 	}
@@ -168,7 +230,7 @@ func (s *passwordService) Register(ctx context.Context, email, password string)
 		return nil, fmt.Errorf("failed to create user: %w", err)
 	}
 
-	if err := s.storage.StorePasswordHash(ctx, user.ID, hash); err != nil {
+	if err := s.storage.StorePasswordHash(ctx, user.ID, []byte(hash)); err != nil {
 		// Clean up the user record if password storage fails to maintain consistency
 		if deleteErr := s.storage.DeleteUser(ctx, user.ID); deleteErr != nil {
 			s.logger.Error("failed to cleanup user after password save failure",
@@ -199,10 +261,16 @@ func (s *passwordService) Register(ctx context.Context, email, password string)
 }
 
 // Authenticate verifies email and password, returns user if valid.
-// Returns generic ErrInvalidCredentials for any failure to prevent user enumeration attacks.
+// Returns generic ErrInvalidCredentials for any failure to prevent user enumeration attacks,
+// and ErrAccountLocked for accounts locked by WithMaxFailedAttempts, even with the right password.
 func (s *passwordService) Authenticate(ctx context.Context, email, password string) (*User, error) {
 	email = sanitizer.NormalizeEmail(email)
 
+	// Rate limit before any storage lookup or bcrypt work so throttled attempts stay cheap
+	if err := s.checkLoginRateLimit(ctx, email); err != nil {
+		return nil, err
+	}
+
 	// Execute before login hook if set
 	if s.beforeLogin != nil {
 		if err := s.beforeLogin(ctx, email); err != nil {
@@ -215,13 +283,8 @@ func (s *passwordService) Authenticate(ctx context.Context, email, password stri
 		return nil, ErrInvalidCredentials
 	}
 
-	hash, err := s.storage.GetPasswordHash(ctx, user.ID)
-	if err != nil {
-		return nil, ErrInvalidCredentials
-	}
-
-	if err := bcrypt.CompareHashAndPassword(hash, []byte(password)); err != nil {
-		return nil, ErrInvalidCredentials
+	if err := s.verifyLogin(ctx, user.ID, password); err != nil {
+		return nil, err
 	}
 
 	// Execute after login hook if set
@@ -241,6 +304,82 @@ func (s *passwordService) Authenticate(ctx context.Context, email, password stri
 	return user, nil
 }
 
+// verifyLogin checks the user's password, enforcing the account lockout when enabled,
+// and upgrades outdated hashes. Authenticate and Reauthenticate share it so both
+// count towards the same failed login limit.
+func (s *passwordService) verifyLogin(ctx context.Context, userID uuid.UUID, password string) error {
+	hash, err := s.storage.GetPasswordHash(ctx, userID)
+	if err != nil {
+		return ErrInvalidCredentials
+	}
+
+	lockout := s.maxFailedAttempts > 0
+	now := time.Now()
+	var failures int
+	if lockout {
+		failures, err = s.failedLogins(ctx, userID, now)
+		if err != nil {
+			return fmt.Errorf("failed to check failed logins: %w", err)
+		}
+	}
+
+	// The comparison runs even for locked accounts so response timing doesn't reveal the lockout
+	needsRehash, compareErr := verifyPassword(s.hasher, string(hash), password)
+
+	if lockout {
+		if failures >= s.maxFailedAttempts {
+			return ErrAccountLocked
+		}
+		// Concurrent attempts can all pass the check above, so each one is counted with an
+		// atomic increment before its result is revealed; only the first maxFailedAttempts
+		// get an answer, even if a later one has the right password
+		count, err := s.recordFailedLogin(ctx, userID, now)
+		if err != nil {
+			return fmt.Errorf("failed to record login attempt: %w", err)
+		}
+		if count > s.maxFailedAttempts {
+			return ErrAccountLocked
+		}
+		if compareErr != nil {
+			if count == s.maxFailedAttempts {
+				s.logger.Warn("account locked after repeated failed logins",
+					logger.UserID(userID.String()),
+					logger.Component("password"),
+				)
+			}
+			return ErrInvalidCredentials
+		}
+		s.clearFailedLogins(ctx, userID)
+	} else if compareErr != nil {
+		return ErrInvalidCredentials
+	}
+
+	if needsRehash {
+		s.rehashPassword(ctx, userID, password)
+	}
+	return nil
+}
+
+// AuthenticateWithTokens verifies credentials and issues an access+refresh token pair.
+// Returns ErrTokenIssuerNotConfigured if the service was built without WithPasswordTokenIssuer.
+func (s *passwordService) AuthenticateWithTokens(ctx context.Context, email, password string) (*User, *TokenPair, error) {
+	if s.tokenIssuer == nil {
+		return nil, nil, ErrTokenIssuerNotConfigured
+	}
+
+	user, err := s.Authenticate(ctx, email, password)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tokens, err := s.tokenIssuer.Issue(ctx, user)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return user, tokens, nil
+}
+
 // PasswordResetRequest contains the generated password reset token and metadata.
 type PasswordResetRequest struct {
 	Email     string
@@ -305,17 +444,54 @@ func (s *passwordService) ResetPassword(ctx context.Context, resetToken, newPass
 		return nil, ErrTokenInvalid
 	}
 
-	hash, err := bcrypt.GenerateFromPassword([]byte(newPassword), s.bcryptCost)
+	var currentHash []byte
+	if s.passwordHistory > 0 {
+		currentHash, err = s.storage.GetPasswordHash(ctx, userID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get password hash: %w", err)
+		}
+		store := s.storage.(PasswordHistoryStore)
+		if err := checkPasswordHistory(ctx, s.hasher, store, userID, currentHash, newPassword, s.passwordHistory); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := checkBreached(ctx, s.breachChecker, newPassword); err != nil {
+		return nil, err
+	}
+
+	hash, err := s.hasher.Hash(newPassword)
 	if err != nil {
 		return nil, fmt.Errorf("failed to hash password: %w", err)
 	}
 
-	if err := s.storage.StorePasswordHash(ctx, userID, hash); err != nil {
+	if err := s.storage.StorePasswordHash(ctx, userID, []byte(hash)); err != nil {
 		return nil, fmt.Errorf("failed to update password: %w", err)
 	}
 
+	if s.passwordHistory > 0 {
+		recordPasswordHistory(ctx, s.storage.(PasswordHistoryStore), s.logger, "password", userID, currentHash, s.passwordHistory)
+	}
+
 	return s.storage.GetUserByID(ctx, userID)
 }
 
 // Compile-time interface assertion
 var _ PasswordAuthenticator = (*passwordService)(nil)
+var _ PasswordTokenAuthenticator = (*passwordService)(nil)
+
+// rehashPassword upgrades a verified password to the configured hasher's current scheme.
+// The login already succeeded, so failures are logged and retried on the next login.
+func (s *passwordService) rehashPassword(ctx context.Context, userID uuid.UUID, password string) {
+	hash, err := s.hasher.Hash(password)
+	if err == nil {
+		err = s.storage.StorePasswordHash(ctx, userID, []byte(hash))
+	}
+	if err != nil {
+		s.logger.Error("failed to upgrade password hash",
+			logger.UserID(userID.String()),
+			logger.Error(err),
+			logger.Component("password"),
+		)
+	}
+}
@@ -0,0 +1,95 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/google/uuid"
+
+	"github.com/dmitrymomot/saaskit/pkg/logger"
+)
+
+// PasswordHistoryStore keeps previous password hashes so they can't be reused.
+// UserStorage implementations opt in by implementing it when WithPasswordHistory is used.
+type PasswordHistoryStore interface {
+	// GetPasswordHistory returns up to limit previous password hashes, newest first.
+	GetPasswordHistory(ctx context.Context, userID uuid.UUID, limit int) ([][]byte, error)
+	// AddPasswordHistory records a replaced password hash and may drop all but
+	// the newest keep entries for the user.
+	AddPasswordHistory(ctx context.Context, userID uuid.UUID, hash []byte, keep int) error
+}
+
+// WithPasswordHistory makes ChangePassword reject the last n passwords of a user,
+// the current one included, with ErrPasswordReused. The storage passed to
+// NewUserService must implement PasswordHistoryStore.
+//
+// Every check costs one hash comparison per stored hash, so keep n small (5-10).
+// The store only needs to retain n-1 hashes per user; older entries and the
+// history of deleted users should be purged.
+func WithPasswordHistory(n int) UserOption {
+	return func(s *userService) {
+		s.passwordHistory = n
+	}
+}
+
+// WithResetPasswordHistory makes ResetPassword reject the last n passwords of a
+// user, the current one included, with ErrPasswordReused, like WithPasswordHistory
+// does for ChangePassword. Use the same n for both. The storage passed to
+// NewPasswordService must implement PasswordHistoryStore, and GetPasswordHash must
+// return an empty hash rather than an error for users without a password.
+func WithResetPasswordHistory(n int) PasswordOption {
+	return func(s *passwordService) {
+		s.passwordHistory = n
+	}
+}
+
+// checkPasswordHistory returns ErrPasswordReused if password matches the current hash
+// or one of the n-1 newest recorded hashes. Only called when history is enabled.
+func checkPasswordHistory(ctx context.Context, hasher Hasher, store PasswordHistoryStore, userID uuid.UUID, currentHash []byte, password string, n int) error {
+	if len(currentHash) > 0 && passwordMatches(hasher, currentHash, password) {
+		return ErrPasswordReused
+	}
+
+	previous := n - 1
+	if previous <= 0 {
+		return nil
+	}
+
+	hashes, err := store.GetPasswordHistory(ctx, userID, previous)
+	if err != nil {
+		return fmt.Errorf("failed to get password history: %w", err)
+	}
+
+	// Storage may return more than asked for; only the newest ones count
+	for _, hash := range hashes[:min(len(hashes), previous)] {
+		if passwordMatches(hasher, hash, password) {
+			return ErrPasswordReused
+		}
+	}
+
+	return nil
+}
+
+// recordPasswordHistory stores the replaced hash, keeping the n-1 newest.
+// The password is already changed, so failures are logged rather than returned.
+func recordPasswordHistory(ctx context.Context, store PasswordHistoryStore, log *slog.Logger, component string, userID uuid.UUID, replacedHash []byte, n int) {
+	keep := n - 1
+	if keep <= 0 || len(replacedHash) == 0 {
+		return
+	}
+
+	if err := store.AddPasswordHistory(ctx, userID, replacedHash, keep); err != nil {
+		log.Error("failed to record password history",
+			logger.UserID(userID.String()),
+			logger.Error(err),
+			logger.Component(component),
+		)
+	}
+}
+
+// passwordMatches reports whether password matches hash in any supported scheme.
+func passwordMatches(hasher Hasher, hash []byte, password string) bool {
+	_, err := verifyPassword(hasher, string(hash), password)
+	return err == nil
+}
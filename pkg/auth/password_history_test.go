@@ -0,0 +1,279 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/dmitrymomot/saaskit/pkg/token"
+)
+
+// MockUserHistoryStorage is a UserStorage that also implements PasswordHistoryStore.
+type MockUserHistoryStorage struct {
+	MockUserStorage
+}
+
+func (m *MockUserHistoryStorage) GetPasswordHistory(ctx context.Context, userID uuid.UUID, limit int) ([][]byte, error) {
+	args := m.Called(ctx, userID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([][]byte), args.Error(1)
+}
+
+func (m *MockUserHistoryStorage) AddPasswordHistory(ctx context.Context, userID uuid.UUID, hash []byte, keep int) error {
+	args := m.Called(ctx, userID, hash, keep)
+	return args.Error(0)
+}
+
+func TestUserService_PasswordHistory(t *testing.T) {
+	t.Parallel()
+
+	const (
+		tokenSecret     = "test-secret-32-chars-long-12345"
+		currentPassword = "CurrentPassword1!"
+		usedPassword    = "UsedPassword22!"
+		freshPassword   = "FreshPassword33!"
+	)
+
+	hash := func(t *testing.T, password string) []byte {
+		t.Helper()
+		h, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+		require.NoError(t, err)
+		return h
+	}
+
+	setup := func(t *testing.T) (*MockUserHistoryStorage, UserManager, uuid.UUID, []byte) {
+		t.Helper()
+		storage := &MockUserHistoryStorage{}
+		svc := NewUserService(storage, tokenSecret, WithUserBcryptCost(bcrypt.MinCost), WithPasswordHistory(3))
+
+		userID := uuid.New()
+		currentHash := hash(t, currentPassword)
+		storage.On("GetUserByID", mock.Anything, userID).Return(&User{ID: userID}, nil)
+		storage.On("GetPasswordHash", mock.Anything, userID).Return(currentHash, nil)
+		return storage, svc, userID, currentHash
+	}
+
+	t.Run("rejects a recently used password", func(t *testing.T) {
+		t.Parallel()
+
+		storage, svc, userID, _ := setup(t)
+		storage.On("GetPasswordHistory", mock.Anything, userID, 2).
+			Return([][]byte{hash(t, "Other1Password!"), hash(t, usedPassword)}, nil)
+
+		err := svc.ChangePassword(context.Background(), userID, currentPassword, usedPassword)
+		assert.ErrorIs(t, err, ErrPasswordReused)
+		storage.AssertNotCalled(t, "UpdatePasswordHash", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("rejects the current password", func(t *testing.T) {
+		t.Parallel()
+
+		storage, svc, userID, _ := setup(t)
+
+		err := svc.ChangePassword(context.Background(), userID, currentPassword, currentPassword)
+		assert.ErrorIs(t, err, ErrPasswordReused)
+		storage.AssertNotCalled(t, "GetPasswordHistory", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("ignores hashes beyond the limit", func(t *testing.T) {
+		t.Parallel()
+
+		// With a history of 3, the current password and the 2 before it are rejected;
+		// usedPassword is the 4th most recent
+		storage, svc, userID, currentHash := setup(t)
+		storage.On("GetPasswordHistory", mock.Anything, userID, 2).
+			Return([][]byte{hash(t, "A1Password!"), hash(t, "B2Password!"), hash(t, usedPassword)}, nil)
+		storage.On("UpdatePasswordHash", mock.Anything, userID, mock.AnythingOfType("[]uint8")).Return(nil)
+		storage.On("AddPasswordHistory", mock.Anything, userID, currentHash, 2).Return(nil)
+
+		err := svc.ChangePassword(context.Background(), userID, currentPassword, usedPassword)
+		require.NoError(t, err)
+	})
+
+	t.Run("records the replaced hash", func(t *testing.T) {
+		t.Parallel()
+
+		storage, svc, userID, currentHash := setup(t)
+		storage.On("GetPasswordHistory", mock.Anything, userID, 2).Return([][]byte{hash(t, usedPassword)}, nil)
+		storage.On("UpdatePasswordHash", mock.Anything, userID, mock.AnythingOfType("[]uint8")).Return(nil)
+		storage.On("AddPasswordHistory", mock.Anything, userID, currentHash, 2).Return(nil)
+
+		err := svc.ChangePassword(context.Background(), userID, currentPassword, freshPassword)
+		require.NoError(t, err)
+		storage.AssertExpectations(t)
+	})
+
+	t.Run("history lookup error", func(t *testing.T) {
+		t.Parallel()
+
+		storage, svc, userID, _ := setup(t)
+		storage.On("GetPasswordHistory", mock.Anything, userID, 2).Return(nil, errors.New("db down"))
+
+		err := svc.ChangePassword(context.Background(), userID, currentPassword, freshPassword)
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, ErrPasswordReused)
+		storage.AssertNotCalled(t, "UpdatePasswordHash", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("history record error does not fail the change", func(t *testing.T) {
+		t.Parallel()
+
+		storage, svc, userID, _ := setup(t)
+		storage.On("GetPasswordHistory", mock.Anything, userID, 2).Return([][]byte{}, nil)
+		storage.On("UpdatePasswordHash", mock.Anything, userID, mock.AnythingOfType("[]uint8")).Return(nil)
+		storage.On("AddPasswordHistory", mock.Anything, userID, mock.Anything, 2).Return(errors.New("db down"))
+
+		err := svc.ChangePassword(context.Background(), userID, currentPassword, freshPassword)
+		require.NoError(t, err)
+	})
+
+	t.Run("history of one only rejects the current password", func(t *testing.T) {
+		t.Parallel()
+
+		storage := &MockUserHistoryStorage{}
+		svc := NewUserService(storage, tokenSecret, WithUserBcryptCost(bcrypt.MinCost), WithPasswordHistory(1))
+		userID := uuid.New()
+		storage.On("GetUserByID", mock.Anything, userID).Return(&User{ID: userID}, nil)
+		storage.On("GetPasswordHash", mock.Anything, userID).Return(hash(t, currentPassword), nil)
+		storage.On("UpdatePasswordHash", mock.Anything, userID, mock.AnythingOfType("[]uint8")).Return(nil)
+
+		err := svc.ChangePassword(context.Background(), userID, currentPassword, currentPassword)
+		assert.ErrorIs(t, err, ErrPasswordReused)
+
+		err = svc.ChangePassword(context.Background(), userID, currentPassword, freshPassword)
+		require.NoError(t, err)
+		storage.AssertNotCalled(t, "GetPasswordHistory", mock.Anything, mock.Anything, mock.Anything)
+		storage.AssertNotCalled(t, "AddPasswordHistory", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("requires history store", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Panics(t, func() {
+			NewUserService(&MockUserStorage{}, tokenSecret, WithPasswordHistory(3))
+		})
+		assert.NotPanics(t, func() {
+			NewUserService(&MockUserStorage{}, tokenSecret, WithPasswordHistory(0))
+		})
+	})
+}
+
+// MockPasswordHistoryStorage is a PasswordStorage that also implements PasswordHistoryStore.
+type MockPasswordHistoryStorage struct {
+	MockPasswordStorage
+}
+
+func (m *MockPasswordHistoryStorage) GetPasswordHistory(ctx context.Context, userID uuid.UUID, limit int) ([][]byte, error) {
+	args := m.Called(ctx, userID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([][]byte), args.Error(1)
+}
+
+func (m *MockPasswordHistoryStorage) AddPasswordHistory(ctx context.Context, userID uuid.UUID, hash []byte, keep int) error {
+	args := m.Called(ctx, userID, hash, keep)
+	return args.Error(0)
+}
+
+func TestPasswordService_ResetPasswordHistory(t *testing.T) {
+	t.Parallel()
+
+	const (
+		tokenSecret     = "test-secret-32-chars-long-12345"
+		currentPassword = "CurrentPassword1!"
+		usedPassword    = "UsedPassword22!"
+		freshPassword   = "FreshPassword33!"
+	)
+
+	hash := func(t *testing.T, password string) []byte {
+		t.Helper()
+		h, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+		require.NoError(t, err)
+		return h
+	}
+
+	setup := func(t *testing.T, currentHash []byte) (*MockPasswordHistoryStorage, PasswordAuthenticator, uuid.UUID, string) {
+		t.Helper()
+		storage := &MockPasswordHistoryStorage{}
+		svc := NewPasswordService(storage, tokenSecret, WithBcryptCost(bcrypt.MinCost), WithResetPasswordHistory(3))
+
+		userID := uuid.New()
+		storage.On("GetPasswordHash", mock.Anything, userID).Return(currentHash, nil)
+		storage.On("GetUserByID", mock.Anything, userID).Return(&User{ID: userID}, nil)
+
+		resetToken, err := token.GenerateToken(PasswordResetTokenPayload{
+			ID:       userID.String(),
+			Email:    "user@example.com",
+			Subject:  SubjectPasswordReset,
+			ExpireAt: time.Now().Add(time.Hour).Unix(),
+		}, tokenSecret)
+		require.NoError(t, err)
+		return storage, svc, userID, resetToken
+	}
+
+	t.Run("rejects the current password", func(t *testing.T) {
+		t.Parallel()
+
+		storage, svc, _, resetToken := setup(t, hash(t, currentPassword))
+
+		_, err := svc.ResetPassword(context.Background(), resetToken, currentPassword)
+		assert.ErrorIs(t, err, ErrPasswordReused)
+		storage.AssertNotCalled(t, "StorePasswordHash", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("rejects a recently used password", func(t *testing.T) {
+		t.Parallel()
+
+		storage, svc, userID, resetToken := setup(t, hash(t, currentPassword))
+		storage.On("GetPasswordHistory", mock.Anything, userID, 2).Return([][]byte{hash(t, usedPassword)}, nil)
+
+		_, err := svc.ResetPassword(context.Background(), resetToken, usedPassword)
+		assert.ErrorIs(t, err, ErrPasswordReused)
+		storage.AssertNotCalled(t, "StorePasswordHash", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("records the replaced hash", func(t *testing.T) {
+		t.Parallel()
+
+		currentHash := hash(t, currentPassword)
+		storage, svc, userID, resetToken := setup(t, currentHash)
+		storage.On("GetPasswordHistory", mock.Anything, userID, 2).Return([][]byte{hash(t, usedPassword)}, nil)
+		storage.On("StorePasswordHash", mock.Anything, userID, mock.AnythingOfType("[]uint8")).Return(nil)
+		storage.On("AddPasswordHistory", mock.Anything, userID, currentHash, 2).Return(nil)
+
+		user, err := svc.ResetPassword(context.Background(), resetToken, freshPassword)
+		require.NoError(t, err)
+		assert.Equal(t, userID, user.ID)
+		storage.AssertExpectations(t)
+	})
+
+	t.Run("user without a password", func(t *testing.T) {
+		t.Parallel()
+
+		storage, svc, userID, resetToken := setup(t, []byte{})
+		storage.On("GetPasswordHistory", mock.Anything, userID, 2).Return([][]byte{}, nil)
+		storage.On("StorePasswordHash", mock.Anything, userID, mock.AnythingOfType("[]uint8")).Return(nil)
+
+		_, err := svc.ResetPassword(context.Background(), resetToken, freshPassword)
+		require.NoError(t, err)
+		storage.AssertNotCalled(t, "AddPasswordHistory", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("requires history store", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Panics(t, func() {
+			NewPasswordService(&MockPasswordStorage{}, tokenSecret, WithResetPasswordHistory(3))
+		})
+	})
+}
@@ -0,0 +1,96 @@
+package auth
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/dmitrymomot/saaskit/pkg/sanitizer"
+)
+
+// authTimeContextKey stores when the current user last authenticated.
+type authTimeContextKey struct{}
+
+type authTime struct {
+	userID string
+	at     time.Time
+}
+
+// WithAuthTime stores when the user behind the request last authenticated, typically
+// in middleware from the auth_at token claim or a timestamp kept in the session:
+//
+//	ctx = auth.WithAuthTime(ctx, claims.Subject, claims.AuthTime())
+func WithAuthTime(ctx context.Context, userID string, at time.Time) context.Context {
+	return context.WithValue(ctx, authTimeContextKey{}, authTime{userID: userID, at: at})
+}
+
+// AuthTimeFromContext returns the user ID and authentication time set by WithAuthTime.
+func AuthTimeFromContext(ctx context.Context) (userID string, at time.Time, ok bool) {
+	v, ok := ctx.Value(authTimeContextKey{}).(authTime)
+	if !ok {
+		return "", time.Time{}, false
+	}
+	return v.userID, v.at, true
+}
+
+// RequireRecentAuth guards sensitive actions such as changing the email or deleting
+// the account. It returns ErrReauthRequired unless the context carries an authentication
+// time for userID that is at most maxAge old; the client should then prompt for the
+// password and call Reauthenticate.
+func RequireRecentAuth(ctx context.Context, userID string, maxAge time.Duration) error {
+	authUserID, at, ok := AuthTimeFromContext(ctx)
+	if !ok || at.IsZero() || authUserID != userID {
+		return ErrReauthRequired
+	}
+	if time.Since(at) > maxAge {
+		return ErrReauthRequired
+	}
+	return nil
+}
+
+// Reauthenticate verifies the password of an already signed-in user and returns the new
+// authentication time. Store it where RequireRecentAuth reads it from, e.g. the session,
+// or use ReauthenticateWithTokens to get tokens with a fresh auth_at claim.
+// Attempts go through the same login rate limiter and account lockout as Authenticate,
+// so a hijacked session can't be used to guess the password.
+// Login hooks don't run, since the user doesn't start a new session.
+func (s *passwordService) Reauthenticate(ctx context.Context, userID uuid.UUID, password string) (time.Time, error) {
+	if _, err := s.reauthenticate(ctx, userID, password); err != nil {
+		return time.Time{}, err
+	}
+	return time.Now(), nil
+}
+
+// ReauthenticateWithTokens verifies the password and issues a token pair whose auth_at
+// claim is now, so requests made with it pass RequireRecentAuth.
+// Returns ErrTokenIssuerNotConfigured if the service was built without WithPasswordTokenIssuer.
+func (s *passwordService) ReauthenticateWithTokens(ctx context.Context, userID uuid.UUID, password string) (*TokenPair, error) {
+	if s.tokenIssuer == nil {
+		return nil, ErrTokenIssuerNotConfigured
+	}
+
+	user, err := s.reauthenticate(ctx, userID, password)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.tokenIssuer.Issue(ctx, user)
+}
+
+func (s *passwordService) reauthenticate(ctx context.Context, userID uuid.UUID, password string) (*User, error) {
+	user, err := s.storage.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, ErrInvalidCredentials
+	}
+
+	// Keyed by email like logins, so both share one attempt budget
+	if err := s.checkLoginRateLimit(ctx, sanitizer.NormalizeEmail(user.Email)); err != nil {
+		return nil, err
+	}
+
+	if err := s.verifyLogin(ctx, user.ID, password); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
@@ -0,0 +1,178 @@
+package auth
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestRequireRecentAuth(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New().String()
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		wantErr bool
+	}{
+		{name: "recent", ctx: WithAuthTime(context.Background(), userID, time.Now().Add(-time.Minute))},
+		{name: "too old", ctx: WithAuthTime(context.Background(), userID, time.Now().Add(-time.Hour)), wantErr: true},
+		{name: "other user", ctx: WithAuthTime(context.Background(), uuid.New().String(), time.Now()), wantErr: true},
+		{name: "zero time", ctx: WithAuthTime(context.Background(), userID, time.Time{}), wantErr: true},
+		{name: "missing", ctx: context.Background(), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := RequireRecentAuth(tt.ctx, userID, 5*time.Minute)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrReauthRequired)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+var _ PasswordReauthenticator = (*passwordService)(nil)
+
+func newReauthenticator(t *testing.T, storage PasswordStorage, opts ...PasswordOption) PasswordReauthenticator {
+	t.Helper()
+	opts = append([]PasswordOption{WithBcryptCost(bcrypt.MinCost)}, opts...)
+	svc, ok := NewPasswordService(storage, "secret", opts...).(PasswordReauthenticator)
+	require.True(t, ok)
+	return svc
+}
+
+func TestPasswordService_Reauthenticate(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	user := &User{ID: userID, Email: "user@example.com"}
+	hash, err := bcrypt.GenerateFromPassword([]byte("correct-password"), bcrypt.MinCost)
+	require.NoError(t, err)
+
+	t.Run("valid password", func(t *testing.T) {
+		t.Parallel()
+
+		storage := &MockPasswordStorage{}
+		storage.On("GetUserByID", mock.Anything, userID).Return(user, nil)
+		storage.On("GetPasswordHash", mock.Anything, userID).Return(hash, nil)
+		svc := newReauthenticator(t, storage)
+
+		at, err := svc.Reauthenticate(context.Background(), userID, "correct-password")
+		require.NoError(t, err)
+		assert.WithinDuration(t, time.Now(), at, time.Second)
+
+		ctx := WithAuthTime(context.Background(), userID.String(), at)
+		assert.NoError(t, RequireRecentAuth(ctx, userID.String(), time.Minute))
+	})
+
+	t.Run("wrong password", func(t *testing.T) {
+		t.Parallel()
+
+		storage := &MockPasswordStorage{}
+		storage.On("GetUserByID", mock.Anything, userID).Return(user, nil)
+		storage.On("GetPasswordHash", mock.Anything, userID).Return(hash, nil)
+		svc := newReauthenticator(t, storage)
+
+		_, err := svc.Reauthenticate(context.Background(), userID, "wrong-password")
+		assert.ErrorIs(t, err, ErrInvalidCredentials)
+	})
+
+	t.Run("shares the login rate limit", func(t *testing.T) {
+		t.Parallel()
+
+		storage := &MockPasswordStorage{}
+		storage.On("GetUserByID", mock.Anything, userID).Return(user, nil)
+		storage.On("GetPasswordHash", mock.Anything, userID).Return(hash, nil).Once()
+
+		limiter := newCountingLimiter(1)
+		svc := newReauthenticator(t, storage, WithLoginRateLimiter(limiter))
+
+		_, err := svc.Reauthenticate(context.Background(), userID, "wrong-password")
+		assert.ErrorIs(t, err, ErrInvalidCredentials)
+
+		_, err = svc.Reauthenticate(context.Background(), userID, "correct-password")
+		assert.ErrorIs(t, err, ErrTooManyAttempts)
+		assert.Equal(t, 2, limiter.seen["login:email:user@example.com"])
+		storage.AssertExpectations(t)
+	})
+
+	t.Run("counts towards the account lockout", func(t *testing.T) {
+		t.Parallel()
+
+		storage := &lockoutStorage{}
+		storage.On("GetUserByID", mock.Anything, userID).Return(user, nil)
+		storage.On("GetPasswordHash", mock.Anything, userID).Return(hash, nil)
+		svc := newReauthenticator(t, storage, WithMaxFailedAttempts(2), WithLockoutDuration(time.Hour))
+
+		for range 2 {
+			_, err := svc.Reauthenticate(context.Background(), userID, "wrong-password")
+			require.ErrorIs(t, err, ErrInvalidCredentials)
+		}
+
+		_, err := svc.Reauthenticate(context.Background(), userID, "correct-password")
+		assert.ErrorIs(t, err, ErrAccountLocked)
+	})
+
+	t.Run("tokens carry fresh auth time", func(t *testing.T) {
+		t.Parallel()
+
+		storage := &MockPasswordStorage{}
+		storage.On("GetPasswordHash", mock.Anything, userID).Return(hash, nil)
+		storage.On("GetUserByID", mock.Anything, userID).Return(user, nil)
+
+		issuer, _ := newTestTokenIssuer(t)
+		svc := newReauthenticator(t, storage, WithPasswordTokenIssuer(issuer))
+
+		pair, err := svc.ReauthenticateWithTokens(context.Background(), userID, "correct-password")
+		require.NoError(t, err)
+
+		claims, err := issuer.ParseAccessToken(pair.AccessToken)
+		require.NoError(t, err)
+		assert.WithinDuration(t, time.Now(), claims.AuthTime(), 2*time.Second)
+
+		ctx := WithAuthTime(context.Background(), claims.Subject, claims.AuthTime())
+		assert.NoError(t, RequireRecentAuth(ctx, userID.String(), time.Minute))
+	})
+
+	t.Run("tokens require issuer", func(t *testing.T) {
+		t.Parallel()
+
+		svc := newReauthenticator(t, &MockPasswordStorage{})
+		_, err := svc.ReauthenticateWithTokens(context.Background(), userID, "correct-password")
+		assert.ErrorIs(t, err, ErrTokenIssuerNotConfigured)
+	})
+}
+
+func TestTokenIssuer_RefreshKeepsAuthTime(t *testing.T) {
+	t.Parallel()
+
+	issuer, store := newTestTokenIssuer(t)
+	userID := uuid.New()
+
+	// Simulate a session that authenticated an hour ago
+	authAt := time.Now().Add(-time.Hour).Unix()
+	pair, err := issuer.issue(context.Background(), userID, "user@example.com", authAt)
+	require.NoError(t, err)
+	require.Len(t, store.tokens, 1)
+
+	refreshed, err := issuer.Refresh(context.Background(), pair.RefreshToken)
+	require.NoError(t, err)
+
+	claims, err := issuer.ParseAccessToken(refreshed.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, authAt, claims.AuthAt)
+
+	ctx := WithAuthTime(context.Background(), claims.Subject, claims.AuthTime())
+	assert.ErrorIs(t, RequireRecentAuth(ctx, userID.String(), 5*time.Minute), ErrReauthRequired)
+}
@@ -0,0 +1,58 @@
+package auth
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryTokenStore is an in-memory reference implementation of single-use
+// token consumption, suitable for tests and single-instance deployments.
+// Embed it in a MagicLinkStorage implementation to satisfy ConsumeToken.
+//
+// Multi-instance deployments need a shared store with the same semantics,
+// e.g. Redis SET key NX PX ttl.
+type MemoryTokenStore struct {
+	mu       sync.Mutex
+	consumed map[string]time.Time // token ID -> expiration
+	pruned   time.Time
+}
+
+// NewMemoryTokenStore creates an empty in-memory token store.
+func NewMemoryTokenStore() *MemoryTokenStore {
+	return &MemoryTokenStore{consumed: make(map[string]time.Time)}
+}
+
+// ConsumeToken marks the token as consumed, returning ErrTokenAlreadyUsed if it
+// already was. The check and the mark happen under one lock, so exactly one of
+// several concurrent calls for the same token succeeds.
+// Entries are kept for ttl, after which the token has expired anyway.
+func (s *MemoryTokenStore) ConsumeToken(_ context.Context, tokenID string, ttl time.Duration) error {
+	now := time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if expiresAt, ok := s.consumed[tokenID]; ok && now.Before(expiresAt) {
+		return ErrTokenAlreadyUsed
+	}
+
+	s.consumed[tokenID] = now.Add(ttl)
+	s.pruneLocked(now)
+	return nil
+}
+
+// pruneLocked drops expired entries at most once a minute so the map doesn't
+// grow without bound.
+func (s *MemoryTokenStore) pruneLocked(now time.Time) {
+	if now.Sub(s.pruned) < time.Minute {
+		return
+	}
+	s.pruned = now
+
+	for id, expiresAt := range s.consumed {
+		if !now.Before(expiresAt) {
+			delete(s.consumed, id)
+		}
+	}
+}
@@ -0,0 +1,213 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/dmitrymomot/saaskit/pkg/jwt"
+)
+
+// Token types distinguish access from refresh tokens so one can't be used as the other.
+const (
+	TokenTypeAccess  = "access"
+	TokenTypeRefresh = "refresh"
+)
+
+// TokenPair contains the tokens issued to a client after successful authentication.
+type TokenPair struct {
+	AccessToken      string    `json:"access_token"`
+	RefreshToken     string    `json:"refresh_token"`
+	TokenType        string    `json:"token_type"` // Always "Bearer"
+	AccessExpiresAt  time.Time `json:"access_expires_at"`
+	RefreshExpiresAt time.Time `json:"refresh_expires_at"`
+}
+
+// TokenClaims are the JWT claims carried by access and refresh tokens.
+// Subject holds the user ID; ID (jti) identifies refresh tokens for rotation.
+type TokenClaims struct {
+	jwt.StandardClaims
+	Email     string `json:"email,omitempty"`
+	TokenType string `json:"typ"`
+	AuthAt    int64  `json:"auth_at,omitempty"` // Unix time the user last proved their identity, kept across refreshes
+}
+
+// AuthTime returns when the user last authenticated, or the zero time for tokens without the claim.
+func (c TokenClaims) AuthTime() time.Time {
+	if c.AuthAt == 0 {
+		return time.Time{}
+	}
+	return time.Unix(c.AuthAt, 0)
+}
+
+// RefreshStore tracks issued refresh tokens to enforce one-time use.
+type RefreshStore interface {
+	// Save records a newly issued refresh token ID until expiresAt.
+	Save(ctx context.Context, tokenID string, userID uuid.UUID, expiresAt time.Time) error
+	// Consume atomically checks the token ID exists and removes it.
+	// Returns ErrTokenAlreadyUsed if the token was already consumed or never issued.
+	Consume(ctx context.Context, tokenID string) error
+}
+
+// TokenIssuer issues access+refresh token pairs for authenticated users and
+// rotates refresh tokens so each can be exchanged exactly once.
+type TokenIssuer struct {
+	jwt        *jwt.Service
+	store      RefreshStore
+	accessTTL  time.Duration
+	refreshTTL time.Duration
+	issuer     string
+}
+
+// TokenIssuerOption configures a TokenIssuer during construction.
+type TokenIssuerOption func(*TokenIssuer)
+
+// WithAccessTokenTTL configures the lifetime of access tokens.
+func WithAccessTokenTTL(ttl time.Duration) TokenIssuerOption {
+	return func(i *TokenIssuer) {
+		i.accessTTL = ttl
+	}
+}
+
+// WithRefreshTokenTTL configures the lifetime of refresh tokens.
+func WithRefreshTokenTTL(ttl time.Duration) TokenIssuerOption {
+	return func(i *TokenIssuer) {
+		i.refreshTTL = ttl
+	}
+}
+
+// WithTokenIssuerName sets the "iss" claim of issued tokens.
+func WithTokenIssuerName(issuer string) TokenIssuerOption {
+	return func(i *TokenIssuer) {
+		i.issuer = issuer
+	}
+}
+
+// NewTokenIssuer creates a token issuer backed by the jwt service.
+// Defaults: access TTL = 15 minutes, refresh TTL = 30 days.
+func NewTokenIssuer(jwtService *jwt.Service, store RefreshStore, opts ...TokenIssuerOption) *TokenIssuer {
+	i := &TokenIssuer{
+		jwt:        jwtService,
+		store:      store,
+		accessTTL:  15 * time.Minute,
+		refreshTTL: 30 * 24 * time.Hour,
+	}
+
+	for _, opt := range opts {
+		opt(i)
+	}
+
+	return i
+}
+
+// Issue generates a new token pair for the user and records the refresh token.
+// Call it only right after the user authenticated: the auth_at claim is set to now.
+func (i *TokenIssuer) Issue(ctx context.Context, user *User) (*TokenPair, error) {
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+	return i.issue(ctx, user.ID, user.Email, time.Now().Unix())
+}
+
+// Refresh exchanges a refresh token for a new token pair.
+// The presented token is consumed, so replaying it fails with ErrTokenAlreadyUsed.
+func (i *TokenIssuer) Refresh(ctx context.Context, refreshToken string) (*TokenPair, error) {
+	claims, err := i.parse(refreshToken, TokenTypeRefresh)
+	if err != nil {
+		return nil, err
+	}
+
+	userID, err := uuid.Parse(claims.Subject)
+	if err != nil || claims.ID == "" {
+		return nil, ErrTokenInvalid
+	}
+
+	if err := i.store.Consume(ctx, claims.ID); err != nil {
+		if errors.Is(err, ErrTokenAlreadyUsed) {
+			return nil, ErrTokenAlreadyUsed
+		}
+		return nil, fmt.Errorf("failed to consume refresh token: %w", err)
+	}
+
+	// Refreshing isn't authenticating, so the original auth time carries over
+	return i.issue(ctx, userID, claims.Email, claims.AuthAt)
+}
+
+// ParseAccessToken validates an access token and returns its claims.
+func (i *TokenIssuer) ParseAccessToken(accessToken string) (*TokenClaims, error) {
+	return i.parse(accessToken, TokenTypeAccess)
+}
+
+func (i *TokenIssuer) issue(ctx context.Context, userID uuid.UUID, email string, authAt int64) (*TokenPair, error) {
+	now := time.Now()
+	accessExpiresAt := now.Add(i.accessTTL)
+	refreshExpiresAt := now.Add(i.refreshTTL)
+
+	accessToken, err := i.jwt.Generate(TokenClaims{
+		StandardClaims: jwt.StandardClaims{
+			ID:        uuid.New().String(),
+			Subject:   userID.String(),
+			Issuer:    i.issuer,
+			IssuedAt:  now.Unix(),
+			ExpiresAt: accessExpiresAt.Unix(),
+		},
+		Email:     email,
+		TokenType: TokenTypeAccess,
+		AuthAt:    authAt,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate access token: %w", err)
+	}
+
+	refreshID := uuid.New().String()
+	refreshToken, err := i.jwt.Generate(TokenClaims{
+		StandardClaims: jwt.StandardClaims{
+			ID:        refreshID,
+			Subject:   userID.String(),
+			Issuer:    i.issuer,
+			IssuedAt:  now.Unix(),
+			ExpiresAt: refreshExpiresAt.Unix(),
+		},
+		Email:     email,
+		TokenType: TokenTypeRefresh,
+		AuthAt:    authAt,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate refresh token: %w", err)
+	}
+
+	if err := i.store.Save(ctx, refreshID, userID, refreshExpiresAt); err != nil {
+		return nil, fmt.Errorf("failed to store refresh token: %w", err)
+	}
+
+	return &TokenPair{
+		AccessToken:      accessToken,
+		RefreshToken:     refreshToken,
+		TokenType:        "Bearer",
+		AccessExpiresAt:  accessExpiresAt,
+		RefreshExpiresAt: refreshExpiresAt,
+	}, nil
+}
+
+func (i *TokenIssuer) parse(tokenStr, tokenType string) (*TokenClaims, error) {
+	var claims TokenClaims
+	if err := i.jwt.Parse(tokenStr, &claims); err != nil {
+		if errors.Is(err, jwt.ErrExpiredToken) {
+			return nil, ErrTokenExpired
+		}
+		return nil, ErrTokenInvalid
+	}
+
+	if claims.TokenType != tokenType {
+		return nil, ErrTokenInvalid
+	}
+
+	if i.issuer != "" && claims.Issuer != i.issuer {
+		return nil, ErrTokenInvalid
+	}
+
+	return &claims, nil
+}
@@ -0,0 +1,217 @@
+package auth
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/dmitrymomot/saaskit/pkg/jwt"
+)
+
+type memoryRefreshStore struct {
+	mu     sync.Mutex
+	tokens map[string]uuid.UUID
+}
+
+func newMemoryRefreshStore() *memoryRefreshStore {
+	return &memoryRefreshStore{tokens: make(map[string]uuid.UUID)}
+}
+
+func (s *memoryRefreshStore) Save(_ context.Context, tokenID string, userID uuid.UUID, _ time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tokens[tokenID] = userID
+	return nil
+}
+
+func (s *memoryRefreshStore) Consume(_ context.Context, tokenID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.tokens[tokenID]; !ok {
+		return ErrTokenAlreadyUsed
+	}
+	delete(s.tokens, tokenID)
+	return nil
+}
+
+func newTestTokenIssuer(t *testing.T, opts ...TokenIssuerOption) (*TokenIssuer, *memoryRefreshStore) {
+	t.Helper()
+	jwtSvc, err := jwt.NewFromString("test-signing-key-32-chars-long-123")
+	require.NoError(t, err)
+	store := newMemoryRefreshStore()
+	return NewTokenIssuer(jwtSvc, store, opts...), store
+}
+
+func TestTokenIssuer_Issue(t *testing.T) {
+	t.Parallel()
+
+	t.Run("issues access and refresh tokens", func(t *testing.T) {
+		t.Parallel()
+		issuer, store := newTestTokenIssuer(t, WithTokenIssuerName("saaskit"))
+		user := &User{ID: uuid.New(), Email: "user@example.com"}
+
+		pair, err := issuer.Issue(context.Background(), user)
+		require.NoError(t, err)
+		assert.Equal(t, "Bearer", pair.TokenType)
+		assert.NotEqual(t, pair.AccessToken, pair.RefreshToken)
+		assert.True(t, pair.RefreshExpiresAt.After(pair.AccessExpiresAt))
+		assert.Len(t, store.tokens, 1)
+
+		claims, err := issuer.ParseAccessToken(pair.AccessToken)
+		require.NoError(t, err)
+		assert.Equal(t, user.ID.String(), claims.Subject)
+		assert.Equal(t, user.Email, claims.Email)
+		assert.Equal(t, "saaskit", claims.Issuer)
+	})
+
+	t.Run("refresh token is not accepted as access token", func(t *testing.T) {
+		t.Parallel()
+		issuer, _ := newTestTokenIssuer(t)
+
+		pair, err := issuer.Issue(context.Background(), &User{ID: uuid.New()})
+		require.NoError(t, err)
+
+		_, err = issuer.ParseAccessToken(pair.RefreshToken)
+		assert.ErrorIs(t, err, ErrTokenInvalid)
+	})
+
+	t.Run("expired access token", func(t *testing.T) {
+		t.Parallel()
+		issuer, _ := newTestTokenIssuer(t, WithAccessTokenTTL(-time.Minute))
+
+		pair, err := issuer.Issue(context.Background(), &User{ID: uuid.New()})
+		require.NoError(t, err)
+
+		_, err = issuer.ParseAccessToken(pair.AccessToken)
+		assert.ErrorIs(t, err, ErrTokenExpired)
+	})
+
+	t.Run("nil user", func(t *testing.T) {
+		t.Parallel()
+		issuer, _ := newTestTokenIssuer(t)
+
+		_, err := issuer.Issue(context.Background(), nil)
+		assert.ErrorIs(t, err, ErrUserNotFound)
+	})
+}
+
+func TestTokenIssuer_Refresh(t *testing.T) {
+	t.Parallel()
+
+	t.Run("rotates refresh token", func(t *testing.T) {
+		t.Parallel()
+		issuer, store := newTestTokenIssuer(t)
+		user := &User{ID: uuid.New(), Email: "user@example.com"}
+
+		pair, err := issuer.Issue(context.Background(), user)
+		require.NoError(t, err)
+
+		rotated, err := issuer.Refresh(context.Background(), pair.RefreshToken)
+		require.NoError(t, err)
+		assert.NotEqual(t, pair.RefreshToken, rotated.RefreshToken)
+		assert.Len(t, store.tokens, 1)
+
+		claims, err := issuer.ParseAccessToken(rotated.AccessToken)
+		require.NoError(t, err)
+		assert.Equal(t, user.ID.String(), claims.Subject)
+		assert.Equal(t, user.Email, claims.Email)
+	})
+
+	t.Run("refresh token can be used only once", func(t *testing.T) {
+		t.Parallel()
+		issuer, _ := newTestTokenIssuer(t)
+
+		pair, err := issuer.Issue(context.Background(), &User{ID: uuid.New()})
+		require.NoError(t, err)
+
+		_, err = issuer.Refresh(context.Background(), pair.RefreshToken)
+		require.NoError(t, err)
+
+		_, err = issuer.Refresh(context.Background(), pair.RefreshToken)
+		assert.ErrorIs(t, err, ErrTokenAlreadyUsed)
+	})
+
+	t.Run("access token cannot be used to refresh", func(t *testing.T) {
+		t.Parallel()
+		issuer, _ := newTestTokenIssuer(t)
+
+		pair, err := issuer.Issue(context.Background(), &User{ID: uuid.New()})
+		require.NoError(t, err)
+
+		_, err = issuer.Refresh(context.Background(), pair.AccessToken)
+		assert.ErrorIs(t, err, ErrTokenInvalid)
+	})
+
+	t.Run("token signed with another key is rejected", func(t *testing.T) {
+		t.Parallel()
+		issuer, _ := newTestTokenIssuer(t)
+		otherJWT, err := jwt.NewFromString("another-signing-key-32-chars-long")
+		require.NoError(t, err)
+		other := NewTokenIssuer(otherJWT, newMemoryRefreshStore())
+
+		pair, err := other.Issue(context.Background(), &User{ID: uuid.New()})
+		require.NoError(t, err)
+
+		_, err = issuer.Refresh(context.Background(), pair.RefreshToken)
+		assert.ErrorIs(t, err, ErrTokenInvalid)
+	})
+}
+
+func TestPasswordService_AuthenticateWithTokens(t *testing.T) {
+	t.Parallel()
+
+	const tokenSecret = "test-secret-32-chars-long-12345"
+
+	t.Run("returns user and tokens", func(t *testing.T) {
+		t.Parallel()
+		issuer, _ := newTestTokenIssuer(t)
+		storage := &MockPasswordStorage{}
+		svc := NewPasswordService(storage, tokenSecret, WithPasswordTokenIssuer(issuer), WithBcryptCost(bcrypt.MinCost)).(PasswordTokenAuthenticator)
+
+		user := &User{ID: uuid.New(), Email: "user@example.com"}
+		hash, err := bcrypt.GenerateFromPassword([]byte("correct-password"), bcrypt.MinCost)
+		require.NoError(t, err)
+
+		storage.On("GetUserByEmail", mock.Anything, user.Email).Return(user, nil)
+		storage.On("GetPasswordHash", mock.Anything, user.ID).Return(hash, nil)
+
+		gotUser, tokens, err := svc.AuthenticateWithTokens(context.Background(), user.Email, "correct-password")
+		require.NoError(t, err)
+		assert.Equal(t, user.ID, gotUser.ID)
+		require.NotNil(t, tokens)
+
+		claims, err := issuer.ParseAccessToken(tokens.AccessToken)
+		require.NoError(t, err)
+		assert.Equal(t, user.ID.String(), claims.Subject)
+		storage.AssertExpectations(t)
+	})
+
+	t.Run("invalid credentials issue no tokens", func(t *testing.T) {
+		t.Parallel()
+		issuer, store := newTestTokenIssuer(t)
+		storage := &MockPasswordStorage{}
+		svc := NewPasswordService(storage, tokenSecret, WithPasswordTokenIssuer(issuer)).(PasswordTokenAuthenticator)
+
+		storage.On("GetUserByEmail", mock.Anything, "user@example.com").Return(nil, ErrUserNotFound)
+
+		_, tokens, err := svc.AuthenticateWithTokens(context.Background(), "user@example.com", "password")
+		assert.ErrorIs(t, err, ErrInvalidCredentials)
+		assert.Nil(t, tokens)
+		assert.Empty(t, store.tokens)
+	})
+
+	t.Run("requires token issuer", func(t *testing.T) {
+		t.Parallel()
+		svc := NewPasswordService(&MockPasswordStorage{}, tokenSecret).(PasswordTokenAuthenticator)
+
+		_, _, err := svc.AuthenticateWithTokens(context.Background(), "user@example.com", "password")
+		assert.ErrorIs(t, err, ErrTokenIssuerNotConfigured)
+	})
+}
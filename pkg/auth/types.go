@@ -8,10 +8,13 @@ import (
 
 // Authentication method identifiers used to track how users authenticate.
 const (
-	MethodPassword    = "password"
-	MethodMagicLink   = "magic_link"
-	MethodOAuthGoogle = "oauth_google"
-	MethodOAuthGithub = "oauth_github"
+	MethodPassword       = "password"
+	MethodMagicLink      = "magic_link"
+	MethodOAuthGoogle    = "oauth_google"
+	MethodOAuthGithub    = "oauth_github"
+	MethodOAuthApple     = "oauth_apple"
+	MethodOAuthMicrosoft = "oauth_microsoft"
+	MethodPasskey        = "passkey"
 )
 
 // Token subjects used in JWT tokens for various authentication operations.
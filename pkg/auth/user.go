@@ -60,6 +60,9 @@ type userService struct {
 	logger           *slog.Logger
 	emailChangeTTL   time.Duration
 	passwordStrength validator.PasswordStrengthConfig
+	hasher           Hasher
+	passwordHistory  int // Number of recent passwords, current included, that can't be reused; 0 disables the check
+	breachChecker    BreachChecker
 
 	// Hooks for extending user management behavior
 	beforeUpdate func(ctx context.Context, userID uuid.UUID) error
@@ -79,6 +82,7 @@ func WithUserLogger(logger *slog.Logger) UserOption {
 }
 
 // WithUserBcryptCost configures the bcrypt cost parameter for password hashing.
+// Ignored when WithUserHasher is set.
 func WithUserBcryptCost(cost int) UserOption {
 	return func(s *userService) {
 		s.bcryptCost = cost
@@ -147,6 +151,16 @@ func NewUserService(storage UserStorage, tokenSecret string, opts ...UserOption)
 		opt(s)
 	}
 
+	if s.hasher == nil {
+		s.hasher = NewBcryptHasher(s.bcryptCost)
+	}
+
+	if s.passwordHistory > 0 {
+		if _, ok := storage.(PasswordHistoryStore); !ok {
+			panic("auth: WithPasswordHistory requires storage implementing PasswordHistoryStore")
+		}
+	}
+
 	return s
 }
 
@@ -185,19 +199,34 @@ func (s *userService) ChangePassword(ctx context.Context, userID uuid.UUID, oldP
 		return ErrUserNotFound
 	}
 
-	if err := bcrypt.CompareHashAndPassword(hash, []byte(oldPassword)); err != nil {
+	if _, err := verifyPassword(s.hasher, string(hash), oldPassword); err != nil {
 		return ErrInvalidCredentials
 	}
 
-	newHash, err := bcrypt.GenerateFromPassword([]byte(newPassword), s.bcryptCost)
+	if s.passwordHistory > 0 {
+		store := s.storage.(PasswordHistoryStore)
+		if err := checkPasswordHistory(ctx, s.hasher, store, userID, hash, newPassword, s.passwordHistory); err != nil {
+			return err
+		}
+	}
+
+	if err := checkBreached(ctx, s.breachChecker, newPassword); err != nil {
+		return err
+	}
+
+	newHash, err := s.hasher.Hash(newPassword)
 	if err != nil {
 		return fmt.Errorf("failed to hash password: %w", err)
 	}
 
-	if err := s.storage.UpdatePasswordHash(ctx, userID, newHash); err != nil {
+	if err := s.storage.UpdatePasswordHash(ctx, userID, []byte(newHash)); err != nil {
 		return fmt.Errorf("failed to update password: %w", err)
 	}
 
+	if s.passwordHistory > 0 {
+		recordPasswordHistory(ctx, s.storage.(PasswordHistoryStore), s.logger, "user", userID, hash, s.passwordHistory)
+	}
+
 	// Execute after update hook if set
 	if s.afterUpdate != nil {
 		hookCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
@@ -248,7 +277,7 @@ func (s *userService) RequestEmailChange(ctx context.Context, userID uuid.UUID,
 		return nil, ErrUserNotFound
 	}
 
-	if err := bcrypt.CompareHashAndPassword(hash, []byte(currentPassword)); err != nil {
+	if _, err := verifyPassword(s.hasher, string(hash), currentPassword); err != nil {
 		return nil, ErrInvalidCredentials
 	}
 
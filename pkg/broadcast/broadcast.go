@@ -7,8 +7,11 @@ import (
 )
 
 var (
-	ErrBroadcasterClosed = errors.New("broadcaster: closed")
-	ErrSubscriberClosed  = errors.New("subscriber: closed")
+	ErrBroadcasterClosed   = errors.New("broadcaster: closed")
+	ErrBroadcasterDraining = errors.New("broadcaster: draining")
+	ErrSubscriberClosed    = errors.New("subscriber: closed")
+	ErrSubscribeFailed     = errors.New("broadcaster: subscribe failed")
+	ErrPublishFailed       = errors.New("broadcaster: publish failed")
 )
 
 type Message[T any] struct {
@@ -53,13 +56,15 @@ type Broadcaster[T any] interface {
 
 type subscriber[T any] struct {
 	ch     chan Message[T]
+	done   chan struct{} // closed by Close, stops the broadcaster's cleanup goroutine
 	closed bool
 	mu     sync.RWMutex
 }
 
 func newSubscriber[T any](bufferSize int) *subscriber[T] {
 	return &subscriber[T]{
-		ch: make(chan Message[T], bufferSize),
+		ch:   make(chan Message[T], bufferSize),
+		done: make(chan struct{}),
 	}
 }
 
@@ -73,6 +78,7 @@ func (s *subscriber[T]) Close() error {
 
 	if !s.closed {
 		close(s.ch)
+		close(s.done)
 		s.closed = true
 	}
 	return nil
@@ -95,3 +101,15 @@ func (s *subscriber[T]) send(msg Message[T]) bool {
 		return false
 	}
 }
+
+// pending returns the number of buffered messages not yet received.
+// Closed subscribers report zero as nobody is going to receive them.
+func (s *subscriber[T]) pending() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.closed {
+		return 0
+	}
+	return len(s.ch)
+}
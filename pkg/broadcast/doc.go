@@ -13,9 +13,10 @@
 //   - Subscriber[T]: Interface for message consumption
 //   - Message[T]: Type-safe wrapper for broadcast data
 //   - MemoryBroadcaster[T]: In-memory implementation with buffered channels
+//   - RedisBroadcaster[T]: Redis pub/sub implementation for multiple processes
 //
-// The architecture supports future adapters (Redis, NATS, etc.) while maintaining
-// the same API contract. All operations are thread-safe and optimized for minimal
+// Adapters share the same API contract, so implementations can be swapped
+// without changing subscribers or publishers. All operations are thread-safe and optimized for minimal
 // lock contention using RWMutex.
 //
 // # Usage
@@ -99,11 +100,14 @@
 //
 // # Error Handling
 //
-// The package defines two error conditions:
+// The package defines the following error conditions:
 //
 //	var (
-//		ErrBroadcasterClosed = errors.New("broadcaster: closed")
-//		ErrSubscriberClosed  = errors.New("subscriber: closed")
+//		ErrBroadcasterClosed   = errors.New("broadcaster: closed")
+//		ErrBroadcasterDraining = errors.New("broadcaster: draining")
+//		ErrSubscriberClosed    = errors.New("subscriber: closed")
+//		ErrSubscribeFailed     = errors.New("broadcaster: subscribe failed")
+//		ErrPublishFailed       = errors.New("broadcaster: publish failed")
 //	)
 //
 // Operations on closed resources are safe and idempotent:
@@ -117,6 +121,27 @@
 //	// Subscribe after close returns closed subscriber
 //	sub := broadcaster.Subscribe(ctx) // Returns already-closed subscriber
 //
+// Close closes subscriber channels right away, so consumers that stop on
+// shutdown may miss buffered messages. CloseGraceful rejects new broadcasts
+// (ErrBroadcasterDraining) and subscriptions, waits until subscribers drained
+// their buffers or ctx expires, and reports how many messages were left undelivered:
+//
+//	undelivered, err := broadcaster.CloseGraceful(shutdownCtx)
+//
+// # Redis Adapter
+//
+// RedisBroadcaster publishes JSON-encoded messages to a Redis pub/sub channel and fans
+// messages received from the channel out to local subscribers through an internal
+// MemoryBroadcaster, so local delivery keeps the same slow-consumer drop semantics:
+//
+//	broadcaster, err := broadcast.NewRedisBroadcaster[Event](ctx, client, "events", 100)
+//	if err != nil {
+//		return err
+//	}
+//	defer broadcaster.Close()
+//
+// Close unsubscribes from Redis and closes all local subscribers; the client stays open.
+//
 // # Performance Characteristics
 //
 // The memory implementation is optimized for high throughput:
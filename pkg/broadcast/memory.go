@@ -2,7 +2,10 @@ package broadcast
 
 import (
 	"context"
+	"maps"
+	"slices"
 	"sync"
+	"time"
 )
 
 // MemoryBroadcaster drops messages for slow consumers rather than blocking the broadcast operation.
@@ -11,6 +14,7 @@ type MemoryBroadcaster[T any] struct {
 	subscribers map[*subscriber[T]]struct{}
 	bufferSize  int
 	closed      bool
+	draining    bool // CloseGraceful in progress: no new subscribers or messages
 	mu          sync.RWMutex
 	cleanupWg   sync.WaitGroup // tracks cleanup goroutines
 }
@@ -35,7 +39,7 @@ func (b *MemoryBroadcaster[T]) Subscribe(ctx context.Context) Subscriber[T] {
 	b.mu.Lock()
 	defer b.mu.Unlock()
 
-	if b.closed {
+	if b.closed || b.draining {
 		sub := newSubscriber[T](b.bufferSize)
 		_ = sub.Close()
 		return sub
@@ -44,13 +48,17 @@ func (b *MemoryBroadcaster[T]) Subscribe(ctx context.Context) Subscriber[T] {
 	sub := newSubscriber[T](b.bufferSize)
 	b.subscribers[sub] = struct{}{}
 
-	// Auto-cleanup on context cancellation
+	// Auto-cleanup on context cancellation; exits early once the subscriber is closed
+	// so Close doesn't wait for subscription contexts
 	if ctx.Done() != nil {
 		b.cleanupWg.Add(1)
 		go func() {
 			defer b.cleanupWg.Done()
-			<-ctx.Done()
-			b.unsubscribe(sub)
+			select {
+			case <-ctx.Done():
+				b.unsubscribe(sub)
+			case <-sub.done:
+			}
 		}()
 	}
 
@@ -60,7 +68,8 @@ func (b *MemoryBroadcaster[T]) Subscribe(ctx context.Context) Subscriber[T] {
 // Broadcast sends a message to all active subscribers.
 // Messages are sent non-blocking - if a subscriber's channel is full,
 // the message is dropped for that subscriber and they are marked for removal.
-// Returns nil even if some subscribers didn't receive the message.
+// Returns nil even if some subscribers didn't receive the message, and
+// ErrBroadcasterDraining while CloseGraceful is in progress.
 func (b *MemoryBroadcaster[T]) Broadcast(ctx context.Context, msg Message[T]) error {
 	// Use RLock for read-heavy operations: broadcasts are frequent,
 	// subscriber map changes (add/remove) are infrequent
@@ -70,6 +79,9 @@ func (b *MemoryBroadcaster[T]) Broadcast(ctx context.Context, msg Message[T]) er
 	if b.closed {
 		return nil
 	}
+	if b.draining {
+		return ErrBroadcasterDraining
+	}
 
 	for sub := range b.subscribers {
 		if !sub.send(msg) {
@@ -112,6 +124,53 @@ func (b *MemoryBroadcaster[T]) Close() error {
 	return nil
 }
 
+// drainPollInterval is how often CloseGraceful checks subscriber buffers
+const drainPollInterval = 10 * time.Millisecond
+
+// CloseGraceful stops accepting new subscriptions and broadcasts (Broadcast returns
+// ErrBroadcasterDraining), waits for subscribers to consume their buffered messages,
+// and then closes them.
+// Returns the number of messages still buffered when ctx expired, along with
+// the context error. Subscribers that unsubscribe while draining are not waited for.
+// Calling Close during the drain closes all subscribers immediately.
+func (b *MemoryBroadcaster[T]) CloseGraceful(ctx context.Context) (int, error) {
+	b.mu.Lock()
+	if b.closed || b.draining {
+		b.mu.Unlock()
+		return 0, nil
+	}
+	b.draining = true
+	subs := slices.Collect(maps.Keys(b.subscribers))
+	b.mu.Unlock()
+
+	ticker := time.NewTicker(drainPollInterval)
+	defer ticker.Stop()
+
+	var err error
+drain:
+	for pendingMessages(subs) > 0 {
+		select {
+		case <-ctx.Done():
+			err = ctx.Err()
+			break drain
+		case <-ticker.C:
+		}
+	}
+
+	undelivered := pendingMessages(subs)
+	_ = b.Close()
+
+	return undelivered, err
+}
+
+func pendingMessages[T any](subs []*subscriber[T]) int {
+	total := 0
+	for _, sub := range subs {
+		total += sub.pending()
+	}
+	return total
+}
+
 func (b *MemoryBroadcaster[T]) unsubscribe(sub *subscriber[T]) {
 	b.mu.Lock()
 	defer b.mu.Unlock()
@@ -194,6 +194,136 @@ func TestMemoryBroadcaster_Close(t *testing.T) {
 	})
 }
 
+func TestMemoryBroadcaster_CloseGraceful(t *testing.T) {
+	t.Run("delivers buffered messages before closing", func(t *testing.T) {
+		b := NewMemoryBroadcaster[int](10)
+		ctx := context.Background()
+		sub := b.Subscribe(ctx)
+
+		for i := range 5 {
+			require.NoError(t, b.Broadcast(ctx, Message[int]{Data: i}))
+		}
+
+		received := make(chan []int)
+		go func() {
+			var got []int
+			for msg := range sub.Receive(ctx) {
+				got = append(got, msg.Data)
+			}
+			received <- got
+		}()
+
+		undelivered, err := b.CloseGraceful(ctx)
+		require.NoError(t, err)
+		assert.Zero(t, undelivered)
+		assert.Equal(t, []int{0, 1, 2, 3, 4}, <-received)
+	})
+
+	t.Run("reports undelivered messages on deadline", func(t *testing.T) {
+		b := NewMemoryBroadcaster[int](10)
+		ctx := context.Background()
+		sub := b.Subscribe(ctx)
+
+		for i := range 3 {
+			require.NoError(t, b.Broadcast(ctx, Message[int]{Data: i}))
+		}
+
+		// Nobody reads from sub
+		closeCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+		defer cancel()
+
+		undelivered, err := b.CloseGraceful(closeCtx)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Equal(t, 3, undelivered)
+
+		for range sub.Receive(ctx) {
+		}
+	})
+
+	t.Run("rejects new broadcasts and subscriptions while draining", func(t *testing.T) {
+		b := NewMemoryBroadcaster[int](10)
+		ctx := context.Background()
+		sub := b.Subscribe(ctx)
+		require.NoError(t, b.Broadcast(ctx, Message[int]{Data: 1}))
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			_, _ = b.CloseGraceful(ctx)
+		}()
+
+		require.Eventually(t, func() bool {
+			b.mu.RLock()
+			defer b.mu.RUnlock()
+			return b.draining
+		}, time.Second, time.Millisecond)
+
+		require.ErrorIs(t, b.Broadcast(ctx, Message[int]{Data: 2}), ErrBroadcasterDraining)
+		_, ok := <-b.Subscribe(ctx).Receive(ctx)
+		assert.False(t, ok, "subscribe during drain should return closed subscriber")
+
+		msg := <-sub.Receive(ctx)
+		assert.Equal(t, 1, msg.Data)
+		<-done
+
+		_, ok = <-sub.Receive(ctx)
+		assert.False(t, ok, "message broadcast during drain must not be delivered")
+	})
+
+	t.Run("close during drain closes immediately", func(t *testing.T) {
+		b := NewMemoryBroadcaster[int](10)
+		ctx := context.Background()
+		sub := b.Subscribe(ctx)
+		require.NoError(t, b.Broadcast(ctx, Message[int]{Data: 1}))
+
+		done := make(chan int)
+		go func() {
+			undelivered, _ := b.CloseGraceful(ctx)
+			done <- undelivered
+		}()
+
+		require.Eventually(t, func() bool {
+			b.mu.RLock()
+			defer b.mu.RUnlock()
+			return b.draining
+		}, time.Second, time.Millisecond)
+
+		require.NoError(t, b.Close())
+		assert.Zero(t, <-done, "messages of force-closed subscribers are not counted")
+
+		for range sub.Receive(ctx) {
+		}
+	})
+
+	t.Run("does not wait for subscription contexts", func(t *testing.T) {
+		b := NewMemoryBroadcaster[int](10)
+		subCtx, cancelSub := context.WithCancel(context.Background())
+		defer cancelSub() // never cancelled before the close returns
+		sub := b.Subscribe(subCtx)
+
+		closeCtx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		start := time.Now()
+		undelivered, err := b.CloseGraceful(closeCtx)
+		require.NoError(t, err)
+		assert.Zero(t, undelivered)
+		assert.Less(t, time.Since(start), 500*time.Millisecond)
+
+		_, ok := <-sub.Receive(subCtx)
+		assert.False(t, ok)
+	})
+
+	t.Run("graceful close of closed broadcaster is a no-op", func(t *testing.T) {
+		b := NewMemoryBroadcaster[int](10)
+		require.NoError(t, b.Close())
+
+		undelivered, err := b.CloseGraceful(context.Background())
+		require.NoError(t, err)
+		assert.Zero(t, undelivered)
+	})
+}
+
 func TestMemoryBroadcaster_Generic(t *testing.T) {
 	type CustomMessage struct {
 		ID   int
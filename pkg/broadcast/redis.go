@@ -0,0 +1,152 @@
+package broadcast
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/dmitrymomot/saaskit/pkg/logger"
+)
+
+// RedisBroadcaster distributes messages across processes through a Redis pub/sub channel.
+// Broadcast publishes JSON-encoded messages to Redis, and every instance subscribed to
+// the channel fans them out to its local subscribers, including the publishing instance.
+// Local delivery has the same non-blocking, slow-consumer drop semantics as MemoryBroadcaster.
+// All methods are safe for concurrent use.
+type RedisBroadcaster[T any] struct {
+	client  redis.UniversalClient
+	channel string
+	pubsub  *redis.PubSub
+	local   *MemoryBroadcaster[T]
+	log     *slog.Logger
+
+	closeOnce sync.Once
+	closed    chan struct{}
+	done      chan struct{} // closed when the receive loop exits
+}
+
+// RedisOption configures a RedisBroadcaster.
+type RedisOption func(*redisOptions)
+
+type redisOptions struct {
+	logger *slog.Logger
+}
+
+// WithRedisLogger sets the logger used to report messages that can't be decoded.
+// Nothing is logged by default.
+func WithRedisLogger(logger *slog.Logger) RedisOption {
+	return func(o *redisOptions) {
+		if logger != nil {
+			o.logger = logger
+		}
+	}
+}
+
+// NewRedisBroadcaster subscribes to the Redis channel and returns a broadcaster that
+// fans messages out to local subscribers, each with a buffer of bufferSize messages.
+// Any redis.UniversalClient works, as pub/sub messages reach every node of a cluster.
+// The subscription is confirmed before returning, so messages published afterwards
+// are not missed. Panics if client is nil.
+func NewRedisBroadcaster[T any](ctx context.Context, client redis.UniversalClient, channel string, bufferSize int, opts ...RedisOption) (*RedisBroadcaster[T], error) {
+	if client == nil {
+		panic("broadcast: redis client is required")
+	}
+
+	options := redisOptions{
+		logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	pubsub := client.Subscribe(ctx, channel)
+	if _, err := pubsub.Receive(ctx); err != nil {
+		_ = pubsub.Close()
+		return nil, errors.Join(ErrSubscribeFailed, err)
+	}
+
+	b := &RedisBroadcaster[T]{
+		client:  client,
+		channel: channel,
+		pubsub:  pubsub,
+		local:   NewMemoryBroadcaster[T](bufferSize),
+		log:     options.logger,
+		closed:  make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+
+	go b.receive(pubsub.Channel())
+
+	return b, nil
+}
+
+// Subscribe creates a local subscriber that receives messages from all instances.
+// The subscription is automatically cleaned up when the provided context is cancelled.
+// If the broadcaster is already closed, returns a closed subscriber.
+func (b *RedisBroadcaster[T]) Subscribe(ctx context.Context) Subscriber[T] {
+	return b.local.Subscribe(ctx)
+}
+
+// Broadcast publishes the message to the Redis channel.
+// Local subscribers receive it once it comes back from Redis, like subscribers of other instances.
+// Returns ErrPublishFailed if the message can't be encoded or published.
+// Broadcast after Close has no effect.
+func (b *RedisBroadcaster[T]) Broadcast(ctx context.Context, msg Message[T]) error {
+	select {
+	case <-b.closed:
+		return nil
+	default:
+	}
+
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return errors.Join(ErrPublishFailed, err)
+	}
+
+	if err := b.client.Publish(ctx, b.channel, payload).Err(); err != nil {
+		return errors.Join(ErrPublishFailed, err)
+	}
+
+	return nil
+}
+
+// Close unsubscribes from Redis and closes all local subscribers.
+// It is safe to call Close multiple times. The Redis client is not closed.
+func (b *RedisBroadcaster[T]) Close() error {
+	var err error
+	b.closeOnce.Do(func() {
+		close(b.closed)
+		// Closing the pubsub unsubscribes and closes its channel, which ends the receive loop
+		err = b.pubsub.Close()
+		<-b.done
+		_ = b.local.Close()
+	})
+	return err
+}
+
+// receive delivers messages from Redis to local subscribers until the pubsub is closed.
+func (b *RedisBroadcaster[T]) receive(ch <-chan *redis.Message) {
+	defer close(b.done)
+	for msg := range ch {
+		b.dispatch(msg.Payload)
+	}
+}
+
+// dispatch decodes a Redis payload and broadcasts it locally.
+// Payloads that don't decode into Message[T] are logged and skipped.
+func (b *RedisBroadcaster[T]) dispatch(payload string) {
+	var msg Message[T]
+	if err := json.Unmarshal([]byte(payload), &msg); err != nil {
+		b.log.Warn("broadcast: failed to decode redis message",
+			slog.String("channel", b.channel),
+			logger.Error(err),
+		)
+		return
+	}
+	_ = b.local.Broadcast(context.Background(), msg)
+}
@@ -0,0 +1,139 @@
+package broadcast
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var _ Broadcaster[string] = (*RedisBroadcaster[string])(nil)
+
+func TestRedisBroadcaster_Dispatch(t *testing.T) {
+	type event struct {
+		Name  string `json:"name"`
+		Count int    `json:"count"`
+	}
+
+	b := &RedisBroadcaster[event]{
+		channel: "events",
+		local:   NewMemoryBroadcaster[event](10),
+		log:     slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}
+	defer b.local.Close()
+
+	ctx := context.Background()
+	sub := b.Subscribe(ctx)
+
+	t.Run("decodes and fans out messages", func(t *testing.T) {
+		payload, err := json.Marshal(Message[event]{Data: event{Name: "signup", Count: 2}})
+		require.NoError(t, err)
+
+		b.dispatch(string(payload))
+
+		select {
+		case msg := <-sub.Receive(ctx):
+			assert.Equal(t, event{Name: "signup", Count: 2}, msg.Data)
+		case <-time.After(time.Second):
+			t.Fatal("message not delivered")
+		}
+	})
+
+	t.Run("skips undecodable payloads", func(t *testing.T) {
+		b.dispatch("not json")
+		b.dispatch(`{"Data":"wrong type"}`)
+
+		select {
+		case msg := <-sub.Receive(ctx):
+			t.Fatalf("unexpected message: %+v", msg)
+		case <-time.After(50 * time.Millisecond):
+		}
+	})
+}
+
+func TestNewRedisBroadcaster_NilClient(t *testing.T) {
+	assert.Panics(t, func() {
+		_, _ = NewRedisBroadcaster[string](context.Background(), nil, "events", 10)
+	})
+}
+
+func TestRedisBroadcaster_Integration(t *testing.T) {
+	url := os.Getenv("REDIS_URL")
+	if url == "" {
+		t.Skip("REDIS_URL is not set")
+	}
+	opts, err := redis.ParseURL(url)
+	require.NoError(t, err)
+
+	client := redis.NewClient(opts)
+	defer client.Close()
+
+	ctx := context.Background()
+	require.NoError(t, client.Ping(ctx).Err())
+
+	// Two instances on the same channel
+	channel := "broadcast-test:" + uuid.NewString()
+	a, err := NewRedisBroadcaster[string](ctx, client, channel, 10)
+	require.NoError(t, err)
+	defer a.Close()
+	b, err := NewRedisBroadcaster[string](ctx, client, channel, 10)
+	require.NoError(t, err)
+	defer b.Close()
+
+	subA := a.Subscribe(ctx)
+	subB1 := b.Subscribe(ctx)
+	subB2 := b.Subscribe(ctx)
+
+	receive := func(t *testing.T, sub Subscriber[string]) string {
+		t.Helper()
+		select {
+		case msg, ok := <-sub.Receive(ctx):
+			require.True(t, ok, "subscriber closed")
+			return msg.Data
+		case <-time.After(2 * time.Second):
+			t.Fatal("message not delivered")
+			return ""
+		}
+	}
+
+	t.Run("fans out to every instance", func(t *testing.T) {
+		require.NoError(t, a.Broadcast(ctx, Message[string]{Data: "from a"}))
+		assert.Equal(t, "from a", receive(t, subA))
+		assert.Equal(t, "from a", receive(t, subB1))
+		assert.Equal(t, "from a", receive(t, subB2))
+
+		require.NoError(t, b.Broadcast(ctx, Message[string]{Data: "from b"}))
+		assert.Equal(t, "from b", receive(t, subA))
+		assert.Equal(t, "from b", receive(t, subB1))
+		assert.Equal(t, "from b", receive(t, subB2))
+	})
+
+	t.Run("close unsubscribes", func(t *testing.T) {
+		require.Equal(t, int64(2), client.PubSubNumSub(ctx, channel).Val()[channel])
+
+		require.NoError(t, a.Close())
+		require.NoError(t, a.Close())
+
+		_, ok := <-subA.Receive(ctx)
+		assert.False(t, ok, "local subscribers are closed")
+		assert.Equal(t, int64(1), client.PubSubNumSub(ctx, channel).Val()[channel])
+
+		// Nothing is published after Close
+		require.NoError(t, a.Broadcast(ctx, Message[string]{Data: "after close"}))
+		require.NoError(t, b.Broadcast(ctx, Message[string]{Data: "still open"}))
+		assert.Equal(t, "still open", receive(t, subB1))
+		assert.Equal(t, "still open", receive(t, subB2))
+
+		// A subscriber of the closed instance is closed right away
+		_, ok = <-a.Subscribe(ctx).Receive(ctx)
+		assert.False(t, ok)
+	})
+}
@@ -52,6 +52,35 @@
 //	cache.Put("db1", db1)
 //	cache.Put("db2", db2)
 //
+// # Layered Cache
+//
+// LayeredCache puts an LRU in front of a slower backing store (database,
+// Redis, remote API). Misses are loaded with single-flight deduplication and
+// writes are propagated either synchronously or on eviction:
+//
+//	users := cache.NewLayeredCache(1000,
+//		func(id string) (*User, error) { return repo.Find(ctx, id) },
+//		func(id string, u *User) error { return repo.Save(ctx, u) },
+//		cache.WithWriteMode(cache.WriteBack),
+//	)
+//
+//	u, err := users.Get("user:123") // loads from repo on miss
+//	err = users.Put("user:123", u)  // buffered until eviction in WriteBack mode
+//	err = users.Flush()             // persist all dirty entries, e.g. on shutdown
+//
+// Evicted dirty entries are served from a write buffer until they are written,
+// and writes of the same key are serialized. Eviction write-back failures go to
+// WithFlushErrorHandler; the value stays buffered until Flush succeeds.
+//
+// GetOrLoadCtx passes a context into a per-call loader. The first caller's
+// context governs the shared load (values and deadline, bounded by
+// WithLoadTimeout), but cancelling any caller, including the first, only stops
+// that caller's wait; the load completes for everyone else still waiting:
+//
+//	u, err := users.GetOrLoadCtx(r.Context(), "user:123", func(ctx context.Context) (*User, error) {
+//		return repo.Find(ctx, "123")
+//	})
+//
 // # Thread Safety
 //
 // All operations are thread-safe and can be called concurrently from multiple
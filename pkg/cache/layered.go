@@ -0,0 +1,398 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrFlushFailed is returned when a dirty entry could not be written to the backing store.
+var ErrFlushFailed = errors.New("failed to flush entry to backing store")
+
+// WriteMode controls how Put propagates values to the backing store.
+type WriteMode int
+
+const (
+	// WriteThrough writes to the backing store synchronously on every Put.
+	WriteThrough WriteMode = iota
+	// WriteBack buffers writes in the cache and flushes dirty entries
+	// when they are evicted, removed, or on an explicit Flush.
+	WriteBack
+)
+
+// LayeredOption configures a LayeredCache.
+type LayeredOption func(*layeredConfig)
+
+type layeredConfig struct {
+	mode         WriteMode
+	loadTimeout  time.Duration
+	errorHandler func(error)
+}
+
+// WithWriteMode sets the write propagation mode (default WriteThrough).
+func WithWriteMode(mode WriteMode) LayeredOption {
+	return func(c *layeredConfig) {
+		c.mode = mode
+	}
+}
+
+// WithLoadTimeout bounds every load started by GetOrLoadCtx, so a hanging loader
+// can't hold a key's single-flight slot forever. No timeout by default.
+func WithLoadTimeout(d time.Duration) LayeredOption {
+	return func(c *layeredConfig) {
+		c.loadTimeout = d
+	}
+}
+
+// WithFlushErrorHandler sets a callback for failed write-backs of evicted entries.
+// Those writes aren't tied to the call that triggered the eviction, so they aren't
+// returned from it. Failed entries stay buffered and are retried by Flush.
+func WithFlushErrorHandler(fn func(err error)) LayeredOption {
+	return func(c *layeredConfig) {
+		c.errorHandler = fn
+	}
+}
+
+type layeredEntry[V any] struct {
+	value V
+	dirty bool
+}
+
+type loadCall[V any] struct {
+	done  chan struct{}
+	value V
+	err   error
+}
+
+// pendingWrite is a dirty value waiting in the write buffer.
+// Compared by pointer to detect a newer write for the same key.
+type pendingWrite[V any] struct {
+	value V
+}
+
+// keyFlush serializes writes of one key; refs counts the flushers holding or waiting for it.
+type keyFlush struct {
+	mu   sync.Mutex
+	refs int
+}
+
+// LayeredCache is an LRU cache in front of a slower backing store.
+// Misses are loaded through loader with single-flight deduplication, so
+// concurrent Gets for the same key trigger exactly one load.
+// Writes go through writer either immediately (WriteThrough) or when a
+// dirty entry leaves the cache (WriteBack). Evicted dirty entries are served
+// from a write buffer until they are persisted, and writes of the same key
+// reach the backing store in order.
+type LayeredCache[K comparable, V any] struct {
+	lru          *LRUCache[K, layeredEntry[V]]
+	loader       func(K) (V, error)
+	writer       func(K, V) error
+	mode         WriteMode
+	loadTimeout  time.Duration
+	errorHandler func(error)
+
+	mu       sync.Mutex
+	inflight map[K]*loadCall[V]
+	buffer   map[K]*pendingWrite[V] // Dirty values that left the LRU or are being flushed
+	flushing map[K]*keyFlush
+	pending  []K // Keys evicted by the LRU, flushed after unlocking
+}
+
+// NewLayeredCache creates a read-through cache with the given capacity.
+// The loader is required and panics if nil. The writer may be nil for
+// read-only backing stores, in which case Put only updates the cache.
+func NewLayeredCache[K comparable, V any](capacity int, loader func(K) (V, error), writer func(K, V) error, opts ...LayeredOption) *LayeredCache[K, V] {
+	if loader == nil {
+		panic("layered cache loader must not be nil")
+	}
+
+	cfg := &layeredConfig{mode: WriteThrough}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	c := &LayeredCache[K, V]{
+		lru:          NewLRUCache[K, layeredEntry[V]](capacity),
+		loader:       loader,
+		writer:       writer,
+		mode:         cfg.mode,
+		loadTimeout:  cfg.loadTimeout,
+		errorHandler: cfg.errorHandler,
+		inflight:     make(map[K]*loadCall[V]),
+		buffer:       make(map[K]*pendingWrite[V]),
+		flushing:     make(map[K]*keyFlush),
+	}
+
+	// Called by the LRU with c.mu held, since every LRU mutation goes through c.mu
+	c.lru.SetEvictCallback(func(key K, entry layeredEntry[V]) {
+		if entry.dirty {
+			c.buffer[key] = &pendingWrite[V]{value: entry.value}
+			c.pending = append(c.pending, key)
+		}
+	})
+
+	return c
+}
+
+// Get returns the cached value or loads it from the backing store on miss.
+// Values evicted but not yet written back are returned from the write buffer.
+// Loader errors are returned as-is and nothing is cached.
+func (c *LayeredCache[K, V]) Get(key K) (V, error) {
+	c.mu.Lock()
+	if value, ok := c.lookup(key); ok {
+		c.mu.Unlock()
+		return value, nil
+	}
+
+	if call, ok := c.inflight[key]; ok {
+		c.mu.Unlock()
+		<-call.done
+		return call.value, call.err
+	}
+
+	call := &loadCall[V]{done: make(chan struct{})}
+	c.inflight[key] = call
+	c.mu.Unlock()
+
+	value, err := c.loader(key)
+	c.complete(key, call, value, err)
+
+	return call.value, call.err
+}
+
+// GetOrLoadCtx returns the cached value or loads it with loader on miss.
+// Loads are single-flight and shared with Get: concurrent callers for the same key
+// wait for one load, whichever method started it.
+//
+// Each caller stops waiting when its own ctx is done and gets ctx.Err(), but that
+// doesn't cancel the shared load, which keeps running for the other callers and is
+// still cached on success. The load runs in its own goroutine with a context derived
+// from the first caller's ctx: it keeps the first caller's values and deadline, but
+// not its cancellation, and is further bounded by WithLoadTimeout. Loaders should
+// honor the context, otherwise a hanging loader holds the key until it returns.
+// Loader errors are returned as-is and nothing is cached.
+func (c *LayeredCache[K, V]) GetOrLoadCtx(ctx context.Context, key K, loader func(ctx context.Context) (V, error)) (V, error) {
+	if err := ctx.Err(); err != nil {
+		var zero V
+		return zero, err
+	}
+
+	c.mu.Lock()
+	if value, ok := c.lookup(key); ok {
+		c.mu.Unlock()
+		return value, nil
+	}
+
+	call, loading := c.inflight[key]
+	if !loading {
+		call = &loadCall[V]{done: make(chan struct{})}
+		c.inflight[key] = call
+
+		loadCtx, cancel := c.loadContext(ctx)
+		go func() {
+			defer cancel()
+			value, err := loader(loadCtx)
+			c.complete(key, call, value, err)
+		}()
+	}
+	c.mu.Unlock()
+
+	select {
+	case <-call.done:
+		return call.value, call.err
+	case <-ctx.Done():
+		var zero V
+		return zero, ctx.Err()
+	}
+}
+
+// loadContext derives the context of a shared load from the first caller's ctx.
+func (c *LayeredCache[K, V]) loadContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	loadCtx := context.WithoutCancel(ctx)
+	cancels := make([]context.CancelFunc, 0, 2)
+
+	if deadline, ok := ctx.Deadline(); ok {
+		var cancel context.CancelFunc
+		loadCtx, cancel = context.WithDeadline(loadCtx, deadline)
+		cancels = append(cancels, cancel)
+	}
+	if c.loadTimeout > 0 {
+		var cancel context.CancelFunc
+		loadCtx, cancel = context.WithTimeout(loadCtx, c.loadTimeout)
+		cancels = append(cancels, cancel)
+	}
+
+	return loadCtx, func() {
+		for _, cancel := range cancels {
+			cancel()
+		}
+	}
+}
+
+// lookup returns the cached or buffered value of key. Must be called with c.mu held.
+func (c *LayeredCache[K, V]) lookup(key K) (V, bool) {
+	if entry, ok := c.lru.Get(key); ok {
+		return entry.value, true
+	}
+	if w, ok := c.buffer[key]; ok {
+		return w.value, true
+	}
+	var zero V
+	return zero, false
+}
+
+// complete stores a loaded value, releases the waiters of call and flushes evicted dirty entries.
+func (c *LayeredCache[K, V]) complete(key K, call *loadCall[V], value V, err error) {
+	c.mu.Lock()
+	delete(c.inflight, key)
+	call.value, call.err = value, err
+	if err == nil {
+		// A concurrent Put wins over the loaded value, even if it was evicted since
+		if entry, ok := c.lru.Get(key); ok {
+			call.value = entry.value
+		} else if w, ok := c.buffer[key]; ok {
+			call.value = w.value
+		} else {
+			c.lru.Put(key, layeredEntry[V]{value: value})
+		}
+	}
+	pending := c.takePending()
+	c.mu.Unlock()
+
+	close(call.done)
+	c.flushEvicted(pending)
+}
+
+// Put stores the value in the cache.
+// In WriteThrough mode the value is written to the backing store first and
+// the cache is only updated on success. In WriteBack mode the entry is marked
+// dirty and written when evicted, removed, or flushed; failed write-backs of
+// entries evicted by Put go to WithFlushErrorHandler.
+func (c *LayeredCache[K, V]) Put(key K, value V) error {
+	dirty := false
+	if c.writer != nil {
+		if c.mode == WriteThrough {
+			if err := c.writer(key, value); err != nil {
+				return err
+			}
+		} else {
+			dirty = true
+		}
+	}
+
+	c.mu.Lock()
+	c.lru.Put(key, layeredEntry[V]{value: value, dirty: dirty})
+	pending := c.takePending()
+	c.mu.Unlock()
+
+	c.flushEvicted(pending)
+	return nil
+}
+
+// Remove drops the key from the cache, flushing it first if it is dirty.
+// The backing store entry is left untouched. A failed flush is returned and
+// the value stays buffered until Flush succeeds.
+func (c *LayeredCache[K, V]) Remove(key K) error {
+	c.mu.Lock()
+	c.lru.Remove(key)
+	pending := c.takePending()
+	c.mu.Unlock()
+
+	var errs []error
+	for _, k := range pending {
+		errs = append(errs, c.flushKey(k))
+	}
+	return errors.Join(errs...)
+}
+
+// Flush writes all dirty entries to the backing store and marks them clean,
+// including evicted entries whose earlier write-back failed.
+// Entries stay cached. It is a no-op in WriteThrough mode.
+func (c *LayeredCache[K, V]) Flush() error {
+	if c.writer == nil || c.mode == WriteThrough {
+		return nil
+	}
+
+	c.mu.Lock()
+	c.lru.updateAll(func(key K, entry layeredEntry[V]) layeredEntry[V] {
+		if entry.dirty {
+			c.buffer[key] = &pendingWrite[V]{value: entry.value}
+			entry.dirty = false
+		}
+		return entry
+	})
+	keys := make([]K, 0, len(c.buffer))
+	for key := range c.buffer {
+		keys = append(keys, key)
+	}
+	c.mu.Unlock()
+
+	var errs []error
+	for _, key := range keys {
+		errs = append(errs, c.flushKey(key))
+	}
+	return errors.Join(errs...)
+}
+
+// Len returns the number of cached entries.
+func (c *LayeredCache[K, V]) Len() int {
+	return c.lru.Len()
+}
+
+// Must be called with c.mu held.
+func (c *LayeredCache[K, V]) takePending() []K {
+	pending := c.pending
+	c.pending = nil
+	return pending
+}
+
+// flushEvicted writes back evicted entries and reports failures to the error handler.
+func (c *LayeredCache[K, V]) flushEvicted(keys []K) {
+	for _, key := range keys {
+		if err := c.flushKey(key); err != nil && c.errorHandler != nil {
+			c.errorHandler(err)
+		}
+	}
+}
+
+// flushKey writes the buffered value of key. Writes of the same key are serialized,
+// and each one takes the newest buffered value, so the store never goes back to an
+// older value. The value leaves the buffer only once it's written.
+func (c *LayeredCache[K, V]) flushKey(key K) error {
+	c.mu.Lock()
+	kf, ok := c.flushing[key]
+	if !ok {
+		kf = &keyFlush{}
+		c.flushing[key] = kf
+	}
+	kf.refs++
+	c.mu.Unlock()
+
+	kf.mu.Lock()
+	defer kf.mu.Unlock()
+
+	c.mu.Lock()
+	w, ok := c.buffer[key]
+	c.mu.Unlock()
+
+	var err error
+	if ok {
+		err = c.writer(key, w.value)
+	}
+
+	c.mu.Lock()
+	if ok && err == nil && c.buffer[key] == w {
+		delete(c.buffer, key)
+	}
+	if kf.refs--; kf.refs == 0 {
+		delete(c.flushing, key)
+	}
+	c.mu.Unlock()
+
+	if err != nil {
+		return fmt.Errorf("key %v: %w", key, errors.Join(ErrFlushFailed, err))
+	}
+	return nil
+}
@@ -0,0 +1,168 @@
+package cache_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dmitrymomot/saaskit/pkg/cache"
+)
+
+func TestLayeredCache_GetOrLoadCtx(t *testing.T) {
+	t.Run("loads on miss and caches", func(t *testing.T) {
+		store := newBackingStore()
+		c := cache.NewLayeredCache(2, store.load, nil)
+
+		var loads atomic.Int32
+		loader := func(ctx context.Context) (int, error) {
+			loads.Add(1)
+			return 7, nil
+		}
+
+		for range 2 {
+			v, err := c.GetOrLoadCtx(context.Background(), "a", loader)
+			require.NoError(t, err)
+			assert.Equal(t, 7, v)
+		}
+		assert.Equal(t, int32(1), loads.Load())
+		assert.Equal(t, int32(0), store.loads.Load())
+	})
+
+	t.Run("loader error is returned and not cached", func(t *testing.T) {
+		c := cache.NewLayeredCache(2, newBackingStore().load, nil)
+		loadErr := errors.New("boom")
+
+		_, err := c.GetOrLoadCtx(context.Background(), "a", func(context.Context) (int, error) {
+			return 0, loadErr
+		})
+		require.ErrorIs(t, err, loadErr)
+		assert.Equal(t, 0, c.Len())
+	})
+
+	t.Run("loader receives first caller context values", func(t *testing.T) {
+		type ctxKey struct{}
+		c := cache.NewLayeredCache(2, newBackingStore().load, nil)
+		ctx := context.WithValue(context.Background(), ctxKey{}, 5)
+
+		v, err := c.GetOrLoadCtx(ctx, "a", func(ctx context.Context) (int, error) {
+			return ctx.Value(ctxKey{}).(int), nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 5, v)
+	})
+
+	t.Run("cancelled context returns without loading", func(t *testing.T) {
+		c := cache.NewLayeredCache(2, newBackingStore().load, nil)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := c.GetOrLoadCtx(ctx, "a", func(context.Context) (int, error) {
+			t.Error("loader must not be called")
+			return 0, nil
+		})
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("cancelled caller does not cancel shared load", func(t *testing.T) {
+		c := cache.NewLayeredCache(2, newBackingStore().load, nil)
+		release := make(chan struct{})
+		started := make(chan struct{})
+		var loads atomic.Int32
+		loader := func(ctx context.Context) (int, error) {
+			loads.Add(1)
+			close(started)
+			select {
+			case <-release:
+				return 42, nil
+			case <-ctx.Done():
+				return 0, ctx.Err()
+			}
+		}
+
+		firstCtx, cancelFirst := context.WithCancel(context.Background())
+		firstErr := make(chan error, 1)
+		go func() {
+			_, err := c.GetOrLoadCtx(firstCtx, "k", loader)
+			firstErr <- err
+		}()
+		<-started
+
+		type result struct {
+			value int
+			err   error
+		}
+		second := make(chan result, 1)
+		go func() {
+			v, err := c.GetOrLoadCtx(context.Background(), "k", loader)
+			second <- result{v, err}
+		}()
+
+		cancelFirst()
+		select {
+		case err := <-firstErr:
+			assert.ErrorIs(t, err, context.Canceled)
+		case <-time.After(time.Second):
+			t.Fatal("cancelled caller kept waiting")
+		}
+
+		close(release)
+		select {
+		case r := <-second:
+			require.NoError(t, r.err)
+			assert.Equal(t, 42, r.value)
+		case <-time.After(time.Second):
+			t.Fatal("waiting caller did not get the shared result")
+		}
+
+		assert.Equal(t, int32(1), loads.Load())
+		v, err := c.Get("k")
+		require.NoError(t, err)
+		assert.Equal(t, 42, v)
+	})
+
+	t.Run("shares in-flight load with Get", func(t *testing.T) {
+		release := make(chan struct{})
+		var loads atomic.Int32
+		c := cache.NewLayeredCache(2, func(string) (int, error) {
+			loads.Add(1)
+			<-release
+			return 1, nil
+		}, nil)
+
+		got := make(chan int, 1)
+		go func() {
+			v, _ := c.Get("k")
+			got <- v
+		}()
+		time.Sleep(20 * time.Millisecond)
+
+		go func() {
+			time.Sleep(20 * time.Millisecond)
+			close(release)
+		}()
+		v, err := c.GetOrLoadCtx(context.Background(), "k", func(context.Context) (int, error) {
+			loads.Add(1)
+			return 2, nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 1, v)
+		assert.Equal(t, 1, <-got)
+		assert.Equal(t, int32(1), loads.Load())
+	})
+
+	t.Run("load timeout bounds the loader", func(t *testing.T) {
+		c := cache.NewLayeredCache(2, newBackingStore().load, nil, cache.WithLoadTimeout(20*time.Millisecond))
+
+		_, err := c.GetOrLoadCtx(context.Background(), "k", func(ctx context.Context) (int, error) {
+			<-ctx.Done()
+			return 0, ctx.Err()
+		})
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Equal(t, 0, c.Len())
+	})
+}
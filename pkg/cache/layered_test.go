@@ -0,0 +1,327 @@
+package cache_test
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dmitrymomot/saaskit/pkg/cache"
+)
+
+type backingStore struct {
+	mu     sync.Mutex
+	data   map[string]int
+	loads  atomic.Int32
+	writes atomic.Int32
+	fail   bool
+}
+
+func newBackingStore() *backingStore {
+	return &backingStore{data: make(map[string]int)}
+}
+
+func (s *backingStore) load(key string) (int, error) {
+	s.loads.Add(1)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, ok := s.data[key]
+	if !ok {
+		return 0, errors.New("not found")
+	}
+	return v, nil
+}
+
+func (s *backingStore) write(key string, value int) error {
+	s.writes.Add(1)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.fail {
+		return errors.New("write failed")
+	}
+	s.data[key] = value
+	return nil
+}
+
+func (s *backingStore) get(key string) (int, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, ok := s.data[key]
+	return v, ok
+}
+
+func TestLayeredCache_ReadThrough(t *testing.T) {
+	t.Run("loads on miss and caches", func(t *testing.T) {
+		store := newBackingStore()
+		store.data["a"] = 1
+		c := cache.NewLayeredCache(2, store.load, store.write)
+
+		v, err := c.Get("a")
+		require.NoError(t, err)
+		assert.Equal(t, 1, v)
+
+		v, err = c.Get("a")
+		require.NoError(t, err)
+		assert.Equal(t, 1, v)
+		assert.Equal(t, int32(1), store.loads.Load())
+	})
+
+	t.Run("loader error is returned and not cached", func(t *testing.T) {
+		store := newBackingStore()
+		c := cache.NewLayeredCache(2, store.load, store.write)
+
+		_, err := c.Get("missing")
+		require.Error(t, err)
+		assert.Equal(t, 0, c.Len())
+	})
+
+	t.Run("concurrent misses share a single load", func(t *testing.T) {
+		release := make(chan struct{})
+		var loads atomic.Int32
+		loader := func(key string) (int, error) {
+			loads.Add(1)
+			<-release
+			return 42, nil
+		}
+		c := cache.NewLayeredCache[string, int](2, loader, nil)
+
+		var wg sync.WaitGroup
+		results := make([]int, 10)
+		for i := range 10 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				v, err := c.Get("k")
+				assert.NoError(t, err)
+				results[i] = v
+			}()
+		}
+
+		time.Sleep(20 * time.Millisecond)
+		close(release)
+		wg.Wait()
+
+		assert.Equal(t, int32(1), loads.Load())
+		for _, v := range results {
+			assert.Equal(t, 42, v)
+		}
+	})
+
+	t.Run("panics without loader", func(t *testing.T) {
+		assert.Panics(t, func() {
+			cache.NewLayeredCache[string, int](1, nil, nil)
+		})
+	})
+}
+
+func TestLayeredCache_WriteThrough(t *testing.T) {
+	t.Run("put writes to backing store", func(t *testing.T) {
+		store := newBackingStore()
+		c := cache.NewLayeredCache(2, store.load, store.write)
+
+		require.NoError(t, c.Put("a", 1))
+
+		v, ok := store.get("a")
+		assert.True(t, ok)
+		assert.Equal(t, 1, v)
+
+		got, err := c.Get("a")
+		require.NoError(t, err)
+		assert.Equal(t, 1, got)
+		assert.Equal(t, int32(0), store.loads.Load())
+	})
+
+	t.Run("failed write does not update cache", func(t *testing.T) {
+		store := newBackingStore()
+		store.fail = true
+		c := cache.NewLayeredCache(2, store.load, store.write)
+
+		require.Error(t, c.Put("a", 1))
+		assert.Equal(t, 0, c.Len())
+	})
+}
+
+func TestLayeredCache_WriteBack(t *testing.T) {
+	t.Run("put defers write until eviction", func(t *testing.T) {
+		store := newBackingStore()
+		c := cache.NewLayeredCache(2, store.load, store.write, cache.WithWriteMode(cache.WriteBack))
+
+		require.NoError(t, c.Put("a", 1))
+		require.NoError(t, c.Put("b", 2))
+		_, ok := store.get("a")
+		assert.False(t, ok)
+
+		require.NoError(t, c.Put("c", 3))
+
+		v, ok := store.get("a")
+		assert.True(t, ok)
+		assert.Equal(t, 1, v)
+		_, ok = store.get("b")
+		assert.False(t, ok)
+	})
+
+	t.Run("clean entries are not written on eviction", func(t *testing.T) {
+		store := newBackingStore()
+		store.data["a"] = 1
+		c := cache.NewLayeredCache(1, store.load, store.write, cache.WithWriteMode(cache.WriteBack))
+
+		_, err := c.Get("a")
+		require.NoError(t, err)
+		require.NoError(t, c.Put("b", 2))
+
+		assert.Equal(t, int32(0), store.writes.Load())
+	})
+
+	t.Run("remove flushes dirty entry", func(t *testing.T) {
+		store := newBackingStore()
+		c := cache.NewLayeredCache(2, store.load, store.write, cache.WithWriteMode(cache.WriteBack))
+
+		require.NoError(t, c.Put("a", 1))
+		require.NoError(t, c.Remove("a"))
+
+		v, ok := store.get("a")
+		assert.True(t, ok)
+		assert.Equal(t, 1, v)
+	})
+
+	t.Run("flush writes all dirty entries once", func(t *testing.T) {
+		store := newBackingStore()
+		c := cache.NewLayeredCache(3, store.load, store.write, cache.WithWriteMode(cache.WriteBack))
+
+		require.NoError(t, c.Put("a", 1))
+		require.NoError(t, c.Put("b", 2))
+		require.NoError(t, c.Flush())
+		require.NoError(t, c.Flush())
+
+		assert.Equal(t, int32(2), store.writes.Load())
+		assert.Equal(t, 2, c.Len())
+	})
+
+	t.Run("failed flush keeps entry dirty", func(t *testing.T) {
+		store := newBackingStore()
+		c := cache.NewLayeredCache(2, store.load, store.write, cache.WithWriteMode(cache.WriteBack))
+
+		require.NoError(t, c.Put("a", 1))
+		store.fail = true
+		err := c.Flush()
+		require.Error(t, err)
+		assert.ErrorIs(t, err, cache.ErrFlushFailed)
+
+		store.fail = false
+		require.NoError(t, c.Flush())
+		v, ok := store.get("a")
+		assert.True(t, ok)
+		assert.Equal(t, 1, v)
+	})
+
+	t.Run("eviction flush failure is reported to the handler", func(t *testing.T) {
+		store := newBackingStore()
+		var flushErr error
+		c := cache.NewLayeredCache(1, store.load, store.write,
+			cache.WithWriteMode(cache.WriteBack),
+			cache.WithFlushErrorHandler(func(err error) { flushErr = err }),
+		)
+
+		require.NoError(t, c.Put("a", 1))
+		store.fail = true
+		require.NoError(t, c.Put("b", 2), "eviction failures aren't returned from Put")
+		assert.ErrorIs(t, flushErr, cache.ErrFlushFailed)
+
+		// The failed value is still served and written by the next Flush
+		v, err := c.Get("a")
+		require.NoError(t, err)
+		assert.Equal(t, 1, v)
+		assert.Equal(t, int32(0), store.loads.Load())
+
+		store.fail = false
+		require.NoError(t, c.Flush())
+		v, ok := store.get("a")
+		assert.True(t, ok)
+		assert.Equal(t, 1, v)
+	})
+
+	t.Run("get serves evicted value while it is written", func(t *testing.T) {
+		store := newBackingStore()
+		started := make(chan struct{})
+		release := make(chan struct{})
+		writer := func(key string, value int) error {
+			if key == "a" {
+				close(started)
+				<-release
+			}
+			return store.write(key, value)
+		}
+		c := cache.NewLayeredCache(1, store.load, writer, cache.WithWriteMode(cache.WriteBack))
+
+		require.NoError(t, c.Put("a", 1))
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			assert.NoError(t, c.Put("b", 2)) // evicts "a" and blocks in its write
+		}()
+		<-started
+
+		v, err := c.Get("a")
+		require.NoError(t, err)
+		assert.Equal(t, 1, v)
+		assert.Equal(t, int32(0), store.loads.Load(), "pending write is served without loading")
+
+		close(release)
+		<-done
+	})
+
+	t.Run("writes of a key reach the store in order", func(t *testing.T) {
+		store := newBackingStore()
+		started := make(chan struct{}, 1)
+		release := make(chan struct{})
+		var blocked atomic.Bool
+		writer := func(key string, value int) error {
+			if key == "a" && blocked.CompareAndSwap(false, true) {
+				started <- struct{}{}
+				<-release
+			}
+			return store.write(key, value)
+		}
+		c := cache.NewLayeredCache(1, store.load, writer, cache.WithWriteMode(cache.WriteBack))
+
+		require.NoError(t, c.Put("a", 1))
+		first := make(chan struct{})
+		go func() {
+			defer close(first)
+			assert.NoError(t, c.Put("b", 2)) // evicts a=1, write blocks
+		}()
+		<-started
+
+		second := make(chan struct{})
+		require.NoError(t, c.Put("a", 3)) // evicts b=2
+		go func() {
+			defer close(second)
+			assert.NoError(t, c.Remove("a")) // flushes a=3, waits for the a=1 write
+		}()
+
+		time.Sleep(20 * time.Millisecond)
+		close(release)
+		<-first
+		<-second
+
+		v, ok := store.get("a")
+		assert.True(t, ok)
+		assert.Equal(t, 3, v)
+	})
+
+	t.Run("get does not return flush errors", func(t *testing.T) {
+		store := newBackingStore()
+		store.data["c"] = 3
+		c := cache.NewLayeredCache(1, store.load, store.write, cache.WithWriteMode(cache.WriteBack))
+
+		require.NoError(t, c.Put("a", 1))
+		store.fail = true
+		v, err := c.Get("c") // evicts dirty "a"
+		require.NoError(t, err)
+		assert.Equal(t, 3, v)
+	})
+}
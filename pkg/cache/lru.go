@@ -123,6 +123,17 @@ func (c *LRUCache[K, V]) Clear() {
 	c.eviction.Init()
 }
 
+// updateAll replaces every value with fn's result without changing recency.
+func (c *LRUCache[K, V]) updateAll(fn func(key K, value V) V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, elem := range c.items {
+		entry := elem.Value.(*lruEntry[K, V])
+		entry.value = fn(key, entry.value)
+	}
+}
+
 // Must be called with lock held.
 func (c *LRUCache[K, V]) evictOldest() {
 	elem := c.eviction.Back()
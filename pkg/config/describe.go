@@ -0,0 +1,142 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// FieldDoc describes one environment variable read by a configuration struct.
+type FieldDoc struct {
+	Name       string // Environment variable name, including any envPrefix
+	Field      string // Go field path, e.g. "Database.Host"
+	Type       string // Go type of the field
+	Default    string // Value of the envDefault tag
+	HasDefault bool   // Whether envDefault is set, as the default may be empty
+	Required   bool   // Set by the required or notEmpty tag options
+	Doc        string // Value of the doc tag
+}
+
+// Describe reflects over the env, envDefault, envPrefix and doc tags of target,
+// which must be a struct or a pointer to one, and returns its variables in field order.
+// Nested structs are described recursively, like env.Parse does when loading them.
+// It returns nil for any other type.
+//
+// Example:
+//
+//	type Config struct {
+//		Port int    `env:"PORT" envDefault:"8080" doc:"HTTP listen port"`
+//		DSN  string `env:"DATABASE_URL,required" doc:"Postgres connection string"`
+//	}
+//
+//	for _, f := range config.Describe(Config{}) {
+//		fmt.Println(f.Name, f.Type, f.Required, f.Doc)
+//	}
+func Describe(target any) []FieldDoc {
+	t := reflect.TypeOf(target)
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var docs []FieldDoc
+	describeStruct(t, "", "", &docs)
+	return docs
+}
+
+func describeStruct(t reflect.Type, prefix, path string, docs *[]FieldDoc) {
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		fieldPath := field.Name
+		if path != "" {
+			fieldPath = path + "." + field.Name
+		}
+
+		tag, hasTag := field.Tag.Lookup("env")
+		if !hasTag {
+			// Untagged structs are parsed recursively with an optional prefix
+			ft := field.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				describeStruct(ft, prefix+field.Tag.Get("envPrefix"), fieldPath, docs)
+			}
+			continue
+		}
+
+		name, opts, _ := strings.Cut(tag, ",")
+		if name == "" || name == "-" {
+			continue
+		}
+
+		doc := FieldDoc{
+			Name:  prefix + name,
+			Field: fieldPath,
+			Type:  field.Type.String(),
+			Doc:   field.Tag.Get("doc"),
+		}
+		doc.Default, doc.HasDefault = field.Tag.Lookup("envDefault")
+		for opt := range strings.SplitSeq(opts, ",") {
+			if opt == "required" || opt == "notEmpty" {
+				doc.Required = true
+			}
+		}
+
+		*docs = append(*docs, doc)
+	}
+}
+
+// PrintEnvTemplate writes a .env.example for target to w. Each variable is
+// preceded by its doc comment and type, required variables are marked as such,
+// and defaults are filled in as values.
+//
+// Example output:
+//
+//	# HTTP listen port
+//	# int
+//	PORT=8080
+//
+//	# Postgres connection string
+//	# string, required
+//	DATABASE_URL=
+func PrintEnvTemplate(w io.Writer, target any) error {
+	for i, f := range Describe(target) {
+		var b strings.Builder
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		if f.Doc != "" {
+			for line := range strings.SplitSeq(f.Doc, "\n") {
+				b.WriteString("# " + line + "\n")
+			}
+		}
+		b.WriteString("# " + f.Type)
+		if f.Required {
+			b.WriteString(", required")
+		}
+		b.WriteString("\n")
+		b.WriteString(f.Name + "=" + envValue(f.Default) + "\n")
+
+		if _, err := io.WriteString(w, b.String()); err != nil {
+			return fmt.Errorf("failed to write env template: %w", err)
+		}
+	}
+	return nil
+}
+
+// envValue quotes values that godotenv would otherwise read differently.
+func envValue(v string) string {
+	if strings.ContainsAny(v, " \t#\"'\\\n") {
+		return strconv.Quote(v)
+	}
+	return v
+}
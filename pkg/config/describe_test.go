@@ -0,0 +1,91 @@
+package config_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dmitrymomot/saaskit/pkg/config"
+)
+
+type DescribeDBConfig struct {
+	Host string `env:"HOST" envDefault:"localhost" doc:"Database host"`
+	Pass string `env:"PASS,required"`
+}
+
+type DescribeConfig struct {
+	Port     int              `env:"PORT" envDefault:"8080" doc:"HTTP listen port"`
+	Timeout  time.Duration    `env:"TIMEOUT" envDefault:"30s"`
+	Greeting string           `env:"GREETING" envDefault:"hello world"`
+	APIKey   string           `env:"API_KEY,notEmpty" doc:"Third-party API key"`
+	DB       DescribeDBConfig `envPrefix:"DB_"`
+	Cache    *struct {
+		URL string `env:"URL"`
+	} `envPrefix:"CACHE_"`
+	Ignored  string `env:"-"`
+	Untagged string
+	internal string `env:"INTERNAL"` //nolint:unused
+}
+
+func TestDescribe(t *testing.T) {
+	docs := config.Describe(&DescribeConfig{})
+
+	require.Len(t, docs, 7)
+	assert.Equal(t, config.FieldDoc{
+		Name: "PORT", Field: "Port", Type: "int",
+		Default: "8080", HasDefault: true, Doc: "HTTP listen port",
+	}, docs[0])
+	assert.Equal(t, "time.Duration", docs[1].Type)
+	assert.Equal(t, config.FieldDoc{
+		Name: "API_KEY", Field: "APIKey", Type: "string",
+		Required: true, Doc: "Third-party API key",
+	}, docs[3])
+	assert.Equal(t, config.FieldDoc{
+		Name: "DB_HOST", Field: "DB.Host", Type: "string",
+		Default: "localhost", HasDefault: true, Doc: "Database host",
+	}, docs[4])
+	assert.Equal(t, "DB_PASS", docs[5].Name)
+	assert.True(t, docs[5].Required)
+	assert.Equal(t, "CACHE_URL", docs[6].Name)
+	assert.Equal(t, "Cache.URL", docs[6].Field)
+
+	// Struct values and pointers are described the same way
+	assert.Equal(t, docs, config.Describe(DescribeConfig{}))
+
+	assert.Nil(t, config.Describe(nil))
+	assert.Nil(t, config.Describe("not a struct"))
+}
+
+func TestPrintEnvTemplate(t *testing.T) {
+	var b strings.Builder
+	require.NoError(t, config.PrintEnvTemplate(&b, DescribeDBConfig{}))
+
+	expected := `# Database host
+# string
+HOST=localhost
+
+# string, required
+PASS=
+`
+	assert.Equal(t, expected, b.String())
+
+	t.Run("quotes defaults with spaces", func(t *testing.T) {
+		var b strings.Builder
+		require.NoError(t, config.PrintEnvTemplate(&b, DescribeConfig{}))
+		assert.Contains(t, b.String(), `GREETING="hello world"`)
+		assert.Contains(t, b.String(), "TIMEOUT=30s\n")
+	})
+
+	t.Run("write error", func(t *testing.T) {
+		err := config.PrintEnvTemplate(failingWriter{}, DescribeDBConfig{})
+		assert.Error(t, err)
+	})
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) { return 0, errors.New("write failed") }
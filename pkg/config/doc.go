@@ -50,6 +50,21 @@
 // Subsequent calls to `config.Load(&db)` will be served from the in-memory cache
 // without re-parsing.
 //
+// # Documenting Configuration
+//
+// `Describe` lists the variables a config struct reads, using the same tags plus
+// an optional `doc` tag, and `PrintEnvTemplate` renders them as a `.env.example`:
+//
+//	type ServerConfig struct {
+//	    Port int `env:"PORT" envDefault:"8080" doc:"HTTP listen port"`
+//	}
+//
+//	for _, f := range config.Describe(ServerConfig{}) {
+//	    fmt.Println(f.Name, f.Type, f.Default, f.Required, f.Doc)
+//	}
+//
+//	_ = config.PrintEnvTemplate(os.Stdout, ServerConfig{})
+//
 // # Error Handling
 //
 // The package defines sentinel errors that can be compared with `errors.Is`:
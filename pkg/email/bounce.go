@@ -0,0 +1,104 @@
+package email
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// maxBounceBodySize caps webhook payloads; Postmark bounces are a few KB at most.
+const maxBounceBodySize = 1 << 20
+
+// Bounce is a delivery failure or spam complaint reported by the email provider.
+type Bounce struct {
+	Email       string
+	Type        string // provider bounce type, e.g. HardBounce, SoftBounce, SpamComplaint
+	Description string
+	// Permanent is set when the address will never accept mail, so sending again
+	// only damages sender reputation. Soft bounces and auto-replies are transient.
+	Permanent bool
+	Complaint bool // recipient marked the email as spam
+	BouncedAt time.Time
+}
+
+// SuppressionReason maps a permanent bounce to the reason to store.
+// Returns false for transient bounces that shouldn't be suppressed.
+func (b Bounce) SuppressionReason() (SuppressionReason, bool) {
+	switch {
+	case b.Complaint:
+		return SuppressionSpamComplaint, true
+	case b.Permanent:
+		return SuppressionHardBounce, true
+	default:
+		return "", false
+	}
+}
+
+// postmarkBounce is the subset of Postmark bounce and spam complaint webhook payloads we use.
+type postmarkBounce struct {
+	RecordType  string    `json:"RecordType"`
+	Type        string    `json:"Type"`
+	Email       string    `json:"Email"`
+	Inactive    bool      `json:"Inactive"`
+	Description string    `json:"Description"`
+	BouncedAt   time.Time `json:"BouncedAt"`
+}
+
+// ParsePostmarkBounce decodes a Postmark bounce or spam complaint webhook body.
+// Addresses Postmark deactivated (Inactive) are treated as permanent failures.
+func ParsePostmarkBounce(r io.Reader) (Bounce, error) {
+	var payload postmarkBounce
+	if err := json.NewDecoder(io.LimitReader(r, maxBounceBodySize)).Decode(&payload); err != nil {
+		return Bounce{}, errors.Join(ErrInvalidBounce, err)
+	}
+
+	if payload.RecordType != "Bounce" && payload.RecordType != "SpamComplaint" {
+		return Bounce{}, fmt.Errorf("%w: unsupported record type %q", ErrInvalidBounce, payload.RecordType)
+	}
+	if !emailRegex.MatchString(strings.TrimSpace(payload.Email)) {
+		return Bounce{}, fmt.Errorf("%w: invalid email address", ErrInvalidBounce)
+	}
+
+	complaint := payload.RecordType == "SpamComplaint" || payload.Type == "SpamComplaint"
+
+	return Bounce{
+		Email:       strings.TrimSpace(payload.Email),
+		Type:        payload.Type,
+		Description: payload.Description,
+		Permanent:   complaint || payload.Inactive || payload.Type == "HardBounce",
+		Complaint:   complaint,
+		BouncedAt:   payload.BouncedAt,
+	}, nil
+}
+
+// PostmarkBounceHandler returns a webhook handler that adds permanently bouncing
+// and complaining addresses to the suppression list. Transient bounces are
+// acknowledged without changes. Protect the route with basic auth or a secret
+// path, as Postmark webhooks aren't signed.
+func PostmarkBounceHandler(store SuppressionStore) http.Handler {
+	if store == nil {
+		panic("email: suppression store is required")
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bounce, err := ParsePostmarkBounce(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if reason, ok := bounce.SuppressionReason(); ok {
+			if err := store.Suppress(r.Context(), bounce.Email, reason); err != nil {
+				// Non-2xx makes Postmark retry the webhook later
+				http.Error(w, "failed to store suppression", http.StatusInternalServerError)
+				return
+			}
+		}
+
+		w.WriteHeader(http.StatusOK)
+	})
+}
@@ -0,0 +1,125 @@
+package email_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dmitrymomot/saaskit/pkg/email"
+)
+
+const hardBouncePayload = `{
+	"RecordType": "Bounce",
+	"ID": 42,
+	"Type": "HardBounce",
+	"TypeCode": 1,
+	"Email": "gone@example.com",
+	"Inactive": true,
+	"Description": "The server was unable to deliver your message",
+	"BouncedAt": "2025-01-15T10:00:00Z"
+}`
+
+const softBouncePayload = `{
+	"RecordType": "Bounce",
+	"Type": "SoftBounce",
+	"Email": "full@example.com",
+	"Inactive": false
+}`
+
+const spamComplaintPayload = `{
+	"RecordType": "SpamComplaint",
+	"Type": "SpamComplaint",
+	"Email": "angry@example.com",
+	"Inactive": true
+}`
+
+func TestParsePostmarkBounce(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		payload   string
+		email     string
+		reason    email.SuppressionReason
+		suppress  bool
+		wantError bool
+	}{
+		{name: "hard bounce", payload: hardBouncePayload, email: "gone@example.com", reason: email.SuppressionHardBounce, suppress: true},
+		{name: "soft bounce", payload: softBouncePayload, email: "full@example.com"},
+		{name: "spam complaint", payload: spamComplaintPayload, email: "angry@example.com", reason: email.SuppressionSpamComplaint, suppress: true},
+		{name: "malformed json", payload: `{"RecordType":`, wantError: true},
+		{name: "unsupported record type", payload: `{"RecordType":"Delivery","Email":"a@example.com"}`, wantError: true},
+		{name: "invalid email", payload: `{"RecordType":"Bounce","Type":"HardBounce","Email":"nope"}`, wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			bounce, err := email.ParsePostmarkBounce(strings.NewReader(tt.payload))
+			if tt.wantError {
+				assert.ErrorIs(t, err, email.ErrInvalidBounce)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.email, bounce.Email)
+
+			reason, ok := bounce.SuppressionReason()
+			assert.Equal(t, tt.suppress, ok)
+			assert.Equal(t, tt.reason, reason)
+		})
+	}
+}
+
+func TestPostmarkBounceHandler(t *testing.T) {
+	t.Parallel()
+
+	serve := func(store email.SuppressionStore, payload string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/webhooks/postmark/bounce", strings.NewReader(payload))
+		rec := httptest.NewRecorder()
+		email.PostmarkBounceHandler(store).ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("suppresses permanent bounces", func(t *testing.T) {
+		t.Parallel()
+		store := email.NewMemorySuppressionStore()
+
+		rec := serve(store, hardBouncePayload)
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		suppressed, reason, err := store.IsSuppressed(context.Background(), "gone@example.com")
+		require.NoError(t, err)
+		assert.True(t, suppressed)
+		assert.Equal(t, email.SuppressionHardBounce, reason)
+	})
+
+	t.Run("ignores transient bounces", func(t *testing.T) {
+		t.Parallel()
+		store := email.NewMemorySuppressionStore()
+
+		rec := serve(store, softBouncePayload)
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		suppressed, _, err := store.IsSuppressed(context.Background(), "full@example.com")
+		require.NoError(t, err)
+		assert.False(t, suppressed)
+	})
+
+	t.Run("rejects invalid payloads", func(t *testing.T) {
+		t.Parallel()
+		rec := serve(email.NewMemorySuppressionStore(), "not json")
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("store failure asks for retry", func(t *testing.T) {
+		t.Parallel()
+		rec := serve(failingSuppressionStore{}, hardBouncePayload)
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	})
+}
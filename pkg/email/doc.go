@@ -75,12 +75,28 @@
 // Use MustNewPostmarkClient for initialization that panics on invalid config,
 // following the framework pattern of failing fast during startup.
 //
+// # Suppression List
+//
+// WithSuppressionList makes the Postmark client skip recipients that bounced,
+// complained or unsubscribed, returning ErrRecipientSuppressed instead of
+// sending. It's not a delivery failure and shouldn't be retried. Set
+// SendEmailParams.BypassSuppression for transactional-critical mail such as
+// password resets.
+//
+// PostmarkBounceHandler populates the list from Postmark bounce webhooks:
+//
+//	store := email.NewMemorySuppressionStore()
+//	client := email.MustNewPostmarkClient(cfg, email.WithSuppressionList(store))
+//	mux.Handle("POST /webhooks/postmark/bounce", email.PostmarkBounceHandler(store))
+//
 // # Error Handling
 //
 // The package provides sentinel errors for common failure scenarios:
 //   - ErrInvalidConfig: Configuration validation failed
 //   - ErrInvalidParams: Email parameters validation failed
 //   - ErrFailedToSendEmail: Email delivery failed
+//   - ErrRecipientSuppressed: Recipient is on the suppression list
+//   - ErrInvalidBounce: Bounce webhook payload could not be parsed
 //
 // All errors can be checked using errors.Is() for programmatic handling:
 //
@@ -9,4 +9,9 @@ var (
 	ErrFailedToSendEmail = errors.New("failed to send email")
 	ErrInvalidConfig     = errors.New("invalid email configuration")
 	ErrInvalidParams     = errors.New("invalid email parameters")
+
+	// ErrRecipientSuppressed means the email was intentionally not sent.
+	// It's not a delivery failure, so callers shouldn't retry it.
+	ErrRecipientSuppressed = errors.New("email recipient is suppressed")
+	ErrInvalidBounce       = errors.New("invalid bounce webhook payload")
 )
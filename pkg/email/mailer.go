@@ -21,6 +21,10 @@ type SendEmailParams struct {
 	Subject  string `json:"subject"`
 	BodyHTML string `json:"body_html"`
 	Tag      string `json:"tag,omitempty"`
+
+	// BypassSuppression sends even to suppressed recipients. Reserve it for
+	// transactional-critical mail like password resets or security alerts.
+	BypassSuppression bool `json:"bypass_suppression,omitempty"`
 }
 
 // emailRegex is a simple regex for validating email addresses.
@@ -9,14 +9,26 @@ import (
 )
 
 type postmarkClient struct {
-	client *postmark.Client
-	config Config
+	client       *postmark.Client
+	config       Config
+	suppressions SuppressionStore
+}
+
+// PostmarkOption configures the Postmark client.
+type PostmarkOption func(*postmarkClient)
+
+// WithSuppressionList skips recipients found in the store, returning ErrRecipientSuppressed.
+// Store lookup errors fail the send, so a broken store can't hurt sender reputation.
+func WithSuppressionList(store SuppressionStore) PostmarkOption {
+	return func(c *postmarkClient) {
+		c.suppressions = store
+	}
 }
 
 // NewPostmarkClient creates a Postmark-backed email sender.
 // Both tokens are required for runtime operation - this enforces
 // explicit configuration rather than silent failures in production.
-func NewPostmarkClient(cfg Config) (EmailSender, error) {
+func NewPostmarkClient(cfg Config, opts ...PostmarkOption) (EmailSender, error) {
 	if cfg.PostmarkServerToken == "" {
 		return nil, fmt.Errorf("%w: PostmarkServerToken is required", ErrInvalidConfig)
 	}
@@ -36,17 +48,22 @@ func NewPostmarkClient(cfg Config) (EmailSender, error) {
 		return nil, fmt.Errorf("%w: SupportEmail must be a valid email address", ErrInvalidConfig)
 	}
 
-	return &postmarkClient{
+	c := &postmarkClient{
 		client: postmark.NewClient(cfg.PostmarkServerToken, cfg.PostmarkAccountToken),
 		config: cfg,
-	}, nil
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c, nil
 }
 
 // MustNewPostmarkClient creates a Postmark client that panics on invalid config.
 // Follows framework pattern of failing fast during initialization rather than
 // allowing broken services to start.
-func MustNewPostmarkClient(cfg Config) EmailSender {
-	client, err := NewPostmarkClient(cfg)
+func MustNewPostmarkClient(cfg Config, opts ...PostmarkOption) EmailSender {
+	client, err := NewPostmarkClient(cfg, opts...)
 	if err != nil {
 		panic(err)
 	}
@@ -62,6 +79,12 @@ func (c *postmarkClient) SendEmail(ctx context.Context, params SendEmailParams)
 		return err
 	}
 
+	if c.suppressions != nil && !params.BypassSuppression {
+		if err := checkSuppression(ctx, c.suppressions, params.SendTo); err != nil {
+			return err
+		}
+	}
+
 	resp, err := c.client.SendEmail(ctx, postmark.Email{
 		From:       c.config.SenderEmail,
 		ReplyTo:    c.config.SupportEmail,
@@ -0,0 +1,81 @@
+package email
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// SuppressionReason explains why a recipient must not receive emails.
+type SuppressionReason string
+
+const (
+	SuppressionHardBounce    SuppressionReason = "hard_bounce"
+	SuppressionSpamComplaint SuppressionReason = "spam_complaint"
+	SuppressionUnsubscribed  SuppressionReason = "unsubscribed"
+	SuppressionManual        SuppressionReason = "manual"
+)
+
+// SuppressionStore keeps addresses that bounced, complained or unsubscribed.
+// Implementations should match addresses case-insensitively.
+type SuppressionStore interface {
+	IsSuppressed(ctx context.Context, email string) (bool, SuppressionReason, error)
+	Suppress(ctx context.Context, email string, reason SuppressionReason) error
+}
+
+// checkSuppression returns ErrRecipientSuppressed with the reason for suppressed recipients.
+func checkSuppression(ctx context.Context, store SuppressionStore, email string) error {
+	suppressed, reason, err := store.IsSuppressed(ctx, email)
+	if err != nil {
+		return errors.Join(ErrFailedToSendEmail, err)
+	}
+	if suppressed {
+		return fmt.Errorf("%w: %s", ErrRecipientSuppressed, reason)
+	}
+	return nil
+}
+
+// MemorySuppressionStore is an in-memory SuppressionStore for development and tests.
+// Entries are lost on restart, so use a persistent store in production.
+type MemorySuppressionStore struct {
+	mu      sync.RWMutex
+	entries map[string]SuppressionReason
+}
+
+// NewMemorySuppressionStore creates an empty in-memory suppression list.
+func NewMemorySuppressionStore() *MemorySuppressionStore {
+	return &MemorySuppressionStore{entries: make(map[string]SuppressionReason)}
+}
+
+// IsSuppressed reports whether the address is on the list.
+func (s *MemorySuppressionStore) IsSuppressed(_ context.Context, email string) (bool, SuppressionReason, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	reason, ok := s.entries[normalizeAddress(email)]
+	return ok, reason, nil
+}
+
+// Suppress adds the address to the list, replacing any previous reason.
+func (s *MemorySuppressionStore) Suppress(_ context.Context, email string, reason SuppressionReason) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries[normalizeAddress(email)] = reason
+	return nil
+}
+
+// Remove takes the address off the list, e.g. after the user fixed their mailbox.
+func (s *MemorySuppressionStore) Remove(_ context.Context, email string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.entries, normalizeAddress(email))
+	return nil
+}
+
+func normalizeAddress(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
@@ -0,0 +1,102 @@
+package email_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dmitrymomot/saaskit/pkg/email"
+)
+
+type failingSuppressionStore struct{}
+
+func (failingSuppressionStore) IsSuppressed(context.Context, string) (bool, email.SuppressionReason, error) {
+	return false, "", errors.New("store unavailable")
+}
+
+func (failingSuppressionStore) Suppress(context.Context, string, email.SuppressionReason) error {
+	return errors.New("store unavailable")
+}
+
+func newSuppressingClient(t *testing.T, store email.SuppressionStore) email.EmailSender {
+	t.Helper()
+
+	client, err := email.NewPostmarkClient(email.Config{
+		PostmarkServerToken:  "test-server-token",
+		PostmarkAccountToken: "test-account-token",
+		SenderEmail:          "sender@example.com",
+		SupportEmail:         "support@example.com",
+	}, email.WithSuppressionList(store))
+	require.NoError(t, err)
+	return client
+}
+
+func TestMemorySuppressionStore(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	store := email.NewMemorySuppressionStore()
+
+	suppressed, _, err := store.IsSuppressed(ctx, "user@example.com")
+	require.NoError(t, err)
+	assert.False(t, suppressed)
+
+	require.NoError(t, store.Suppress(ctx, " User@Example.com ", email.SuppressionHardBounce))
+
+	suppressed, reason, err := store.IsSuppressed(ctx, "user@example.com")
+	require.NoError(t, err)
+	assert.True(t, suppressed)
+	assert.Equal(t, email.SuppressionHardBounce, reason)
+
+	require.NoError(t, store.Remove(ctx, "USER@example.com"))
+	suppressed, _, err = store.IsSuppressed(ctx, "user@example.com")
+	require.NoError(t, err)
+	assert.False(t, suppressed)
+}
+
+func TestPostmarkClient_SendEmail_Suppression(t *testing.T) {
+	t.Parallel()
+
+	params := email.SendEmailParams{
+		SendTo:   "bounced@example.com",
+		Subject:  "Weekly digest",
+		BodyHTML: "<p>Hello</p>",
+	}
+
+	t.Run("short-circuits suppressed recipients", func(t *testing.T) {
+		t.Parallel()
+		store := email.NewMemorySuppressionStore()
+		require.NoError(t, store.Suppress(context.Background(), params.SendTo, email.SuppressionUnsubscribed))
+
+		err := newSuppressingClient(t, store).SendEmail(context.Background(), params)
+		assert.ErrorIs(t, err, email.ErrRecipientSuppressed)
+		assert.NotErrorIs(t, err, email.ErrFailedToSendEmail)
+		assert.Contains(t, err.Error(), string(email.SuppressionUnsubscribed))
+	})
+
+	t.Run("bypass skips the suppression check", func(t *testing.T) {
+		t.Parallel()
+		store := email.NewMemorySuppressionStore()
+		require.NoError(t, store.Suppress(context.Background(), params.SendTo, email.SuppressionHardBounce))
+
+		// Cancelled context fails the provider call without reaching the network
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		critical := params
+		critical.BypassSuppression = true
+		err := newSuppressingClient(t, store).SendEmail(ctx, critical)
+		assert.NotErrorIs(t, err, email.ErrRecipientSuppressed)
+		assert.ErrorIs(t, err, email.ErrFailedToSendEmail)
+	})
+
+	t.Run("store errors fail the send", func(t *testing.T) {
+		t.Parallel()
+
+		err := newSuppressingClient(t, failingSuppressionStore{}).SendEmail(context.Background(), params)
+		assert.ErrorIs(t, err, email.ErrFailedToSendEmail)
+		assert.NotErrorIs(t, err, email.ErrRecipientSuppressed)
+	})
+}
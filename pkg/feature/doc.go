@@ -18,7 +18,7 @@
 // on/off toggles and sophisticated rollout rules.
 //
 // The Provider interface is organized into three logical method groups:
-//   - Evaluation methods: IsEnabled, GetFlag
+//   - Evaluation methods: IsEnabled, IsEnabledBatch, GetFlag
 //   - Management methods: ListFlags, CreateFlag, UpdateFlag, DeleteFlag
 //   - Lifecycle methods: Close
 //
@@ -58,6 +58,7 @@
 // AlwaysStrategy - Returns a constant value (on/off)
 // TargetedStrategy - Enables features for specific users, groups, or percentages
 // EnvironmentStrategy - Activates features in specific environments
+// ScheduleStrategy - Enables features only within a UTC time window
 // CompositeStrategy - Combines multiple strategies with AND/OR logic
 //
 // Example of percentage-based rollout:
@@ -74,6 +75,22 @@
 //		),
 //	}
 //
+// Set TargetCriteria.BucketBy to BucketByTenant and pass WithTenantIDExtractor to
+// bucket by tenant instead, so a tenant sees the feature for all of its users or
+// none. BucketBy only affects Percentage: deny/allow lists, UserIDs and Groups
+// still match the user, and a missing tenant ID never falls back to the user ID.
+//
+// Time-boxed promos use ScheduleStrategy, which is enabled from StartsAt (inclusive)
+// until EndsAt (exclusive) and disabled outside the window even when Flag.Enabled is
+// true. Bounds are stored in UTC; WithClock injects the clock for tests:
+//
+//	start := time.Date(2026, 11, 27, 0, 0, 0, 0, time.UTC)
+//	end := start.Add(72 * time.Hour)
+//	strategy := feature.NewAndStrategy(
+//		feature.NewScheduleStrategy(&start, &end),
+//		feature.NewEnvironmentStrategy([]string{"production"}, feature.WithEnvironmentExtractor(getEnv)),
+//	)
+//
 // # Context Extractors
 //
 // The package uses extractor functions to retrieve evaluation data from context,
@@ -91,6 +108,61 @@
 //		feature.WithUserIDExtractor(getUserID),
 //	)
 //
+// # Redis Provider
+//
+// RedisProvider shares flags across instances. Flags and their strategies are stored
+// as JSON in a Redis hash, and IsEnabled and GetFlag are served from a local cache.
+// CreateFlag, UpdateFlag and DeleteFlag publish the flag name on a pub/sub channel so
+// every instance evicts its copy; the cache is also flushed when the subscription
+// reconnects, and WithRedisCacheTTL bounds staleness if a message is lost:
+//
+//	provider, err := feature.NewRedisProvider(ctx, redisClient,
+//		feature.WithRedisUserIDExtractor(getUserID),
+//	)
+//	defer provider.Close()
+//
+// As with ImportFlags, extractors are supplied by each instance and custom Strategy
+// implementations can't be stored (ErrInvalidStrategy).
+//
+// # Change Tracking
+//
+// NewTrackingProvider wraps any provider and reports every successful
+// CreateFlag, UpdateFlag and DeleteFlag with the before/after flag state and
+// the caller identity read from the context. MemoryChangeLog stores these
+// changes and answers FlagHistory queries:
+//
+//	changeLog := feature.NewMemoryChangeLog()
+//	provider := feature.NewTrackingProvider(memoryProvider,
+//		feature.WithChangeRecorder(changeLog.Record),
+//		feature.WithActorExtractor(func(ctx context.Context) string {
+//			return adminIDFromContext(ctx)
+//		}),
+//	)
+//
+//	history, err := changeLog.FlagHistory(ctx, "new-ui")
+//
+// For durable audit trails, pass a recorder that writes to your own history
+// table or to pkg/audit.
+//
+// # Import and Export
+//
+// ExportFlags serializes all flags of a provider and their strategies to JSON sorted
+// by flag name, and ImportFlags restores them, so flag configuration can be promoted
+// between environments and reviewed as code. Both work with any Provider, and imported
+// changes go through CreateFlag, UpdateFlag and DeleteFlag, so NewTrackingProvider
+// records them. Imports merge by default; WithReplace deletes flags missing from the
+// data, and WithDryRun returns the ImportResult diff without applying it:
+//
+//	data, err := feature.ExportFlags(ctx, staging)
+//
+//	diff, err := feature.ImportFlags(ctx, production, data,
+//		feature.WithReplace(),
+//		feature.WithDryRun(),
+//	)
+//
+// Extractors are not part of the export; pass them with WithImportUserIDExtractor,
+// WithImportUserGroupsExtractor, WithImportTenantIDExtractor and WithImportEnvironmentExtractor.
+//
 // # Error Handling
 //
 // The package defines specific errors for different failure scenarios:
@@ -105,14 +177,17 @@
 // # Performance Considerations
 //
 // The MemoryProvider uses read-write locks for thread-safe concurrent access.
-// For high-throughput applications, consider caching IsEnabled results at the
-// application level to reduce lock contention.
+// When a request checks many flags, IsEnabledBatch acquires the read lock once
+// for all of them. Missing flags map to false and are reported in the joined
+// error, so errors.Is(err, ErrFlagNotFound) still works:
+//
+//	flags, err := provider.IsEnabledBatch(ctx, []string{"new-ui", "dark-mode"})
 //
 // Percentage-based rollouts use consistent hashing (FNV-1a) to ensure users
 // always receive the same feature state across evaluations.
 //
 // The package includes comprehensive benchmarks for performance monitoring:
-//   - Provider operations (IsEnabled, ListFlags) under various scenarios
+//   - Provider operations (IsEnabled, IsEnabledBatch, ListFlags) under various scenarios
 //   - Strategy evaluation performance for all strategy types
 //   - Concurrent access patterns
 //
@@ -0,0 +1,276 @@
+package feature
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// exportVersion is the current flag export format version.
+const exportVersion = 1
+
+// flagExport is the serialized flag set. Timestamps are environment-specific
+// and left out, so exports from different environments diff cleanly.
+type flagExport struct {
+	Version int           `json:"version"`
+	Flags   []*flagRecord `json:"flags"`
+}
+
+type flagRecord struct {
+	Name        string        `json:"name"`
+	Description string        `json:"description,omitempty"`
+	Enabled     bool          `json:"enabled"`
+	Strategy    *strategyJSON `json:"strategy,omitempty"`
+	Tags        []string      `json:"tags,omitempty"`
+}
+
+// ImportResult describes the changes made by ImportFlags, or the changes it would make in dry-run mode.
+// Each list contains flag names sorted alphabetically.
+type ImportResult struct {
+	Created   []string `json:"created,omitempty"`
+	Updated   []string `json:"updated,omitempty"`
+	Deleted   []string `json:"deleted,omitempty"`
+	Unchanged []string `json:"unchanged,omitempty"`
+	DryRun    bool     `json:"dry_run"`
+}
+
+// HasChanges reports whether the import creates, updates or deletes any flag.
+func (r *ImportResult) HasChanges() bool {
+	return len(r.Created) > 0 || len(r.Updated) > 0 || len(r.Deleted) > 0
+}
+
+type importOptions struct {
+	replace    bool
+	dryRun     bool
+	extractors strategyExtractors
+}
+
+// ImportOption configures ImportFlags.
+type ImportOption func(*importOptions)
+
+// WithReplace deletes existing flags that are missing from the imported data.
+// By default imports merge: missing flags are kept.
+func WithReplace() ImportOption {
+	return func(o *importOptions) {
+		o.replace = true
+	}
+}
+
+// WithDryRun computes the import diff without applying it.
+func WithDryRun() ImportOption {
+	return func(o *importOptions) {
+		o.dryRun = true
+	}
+}
+
+// WithImportUserIDExtractor sets the user ID extractor for imported targeted strategies.
+func WithImportUserIDExtractor(extractor UserIDExtractor) ImportOption {
+	return func(o *importOptions) {
+		o.extractors.userID = extractor
+	}
+}
+
+// WithImportUserGroupsExtractor sets the user groups extractor for imported targeted strategies.
+func WithImportUserGroupsExtractor(extractor UserGroupsExtractor) ImportOption {
+	return func(o *importOptions) {
+		o.extractors.userGroups = extractor
+	}
+}
+
+// WithImportTenantIDExtractor sets the tenant ID extractor for imported targeted strategies.
+func WithImportTenantIDExtractor(extractor TenantIDExtractor) ImportOption {
+	return func(o *importOptions) {
+		o.extractors.tenantID = extractor
+	}
+}
+
+// WithImportEnvironmentExtractor sets the environment extractor for imported environment strategies.
+func WithImportEnvironmentExtractor(extractor EnvironmentExtractor) ImportOption {
+	return func(o *importOptions) {
+		o.extractors.environment = extractor
+	}
+}
+
+// ExportFlags serializes all flags of provider, including their strategies, to indented
+// JSON sorted by flag name, so the output is stable and can be reviewed as code.
+// Flags with custom strategy implementations can't be exported and return ErrInvalidStrategy.
+func ExportFlags(ctx context.Context, provider Provider) ([]byte, error) {
+	flags, err := provider.ListFlags(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]*flagRecord, 0, len(flags))
+	for _, flag := range flags {
+		record, err := toRecord(flag)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+	slices.SortFunc(records, func(a, b *flagRecord) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return json.MarshalIndent(flagExport{Version: exportVersion, Flags: records}, "", "  ")
+}
+
+// ImportFlags restores flags exported by ExportFlags into provider.
+//
+// New flags are created and existing flags are overwritten; with WithReplace,
+// flags missing from data are deleted. Changes go through the provider's
+// CreateFlag, UpdateFlag and DeleteFlag, so a provider wrapped by
+// NewTrackingProvider records each of them. The import is validated in full
+// before anything is applied. Applying is not atomic: on a provider error the
+// remaining changes are skipped and the error is returned; running the import
+// again completes it, as unchanged flags are left alone. With WithDryRun the
+// returned result describes the changes without applying them.
+//
+// Context extractors can't be serialized, so imported strategies get the
+// extractors passed with WithImportUserIDExtractor and related options.
+func ImportFlags(ctx context.Context, provider Provider, data []byte, opts ...ImportOption) (*ImportResult, error) {
+	options := importOptions{}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	imported, err := decodeExport(data, options.extractors)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := provider.ListFlags(ctx)
+	if err != nil {
+		return nil, err
+	}
+	existing := make(map[string]*Flag, len(current))
+	for _, flag := range current {
+		existing[flag.Name] = flag
+	}
+
+	result := &ImportResult{DryRun: options.dryRun}
+	var created, updated []*Flag
+
+	for _, flag := range imported {
+		prev, exists := existing[flag.Name]
+		if !exists {
+			result.Created = append(result.Created, flag.Name)
+			created = append(created, flag)
+			continue
+		}
+
+		if sameFlag(prev, flag) {
+			result.Unchanged = append(result.Unchanged, flag.Name)
+			continue
+		}
+		result.Updated = append(result.Updated, flag.Name)
+		updated = append(updated, flag)
+	}
+
+	if options.replace {
+		for name := range existing {
+			if !slices.ContainsFunc(imported, func(f *Flag) bool { return f.Name == name }) {
+				result.Deleted = append(result.Deleted, name)
+			}
+		}
+	}
+
+	slices.Sort(result.Created)
+	slices.Sort(result.Updated)
+	slices.Sort(result.Deleted)
+	slices.Sort(result.Unchanged)
+
+	if options.dryRun {
+		return result, nil
+	}
+
+	for _, flag := range created {
+		if err := provider.CreateFlag(ctx, flag); err != nil {
+			return nil, fmt.Errorf("create flag %q: %w", flag.Name, err)
+		}
+	}
+	for _, flag := range updated {
+		if err := provider.UpdateFlag(ctx, flag); err != nil {
+			return nil, fmt.Errorf("update flag %q: %w", flag.Name, err)
+		}
+	}
+	for _, name := range result.Deleted {
+		if err := provider.DeleteFlag(ctx, name); err != nil {
+			return nil, fmt.Errorf("delete flag %q: %w", name, err)
+		}
+	}
+
+	return result, nil
+}
+
+func toRecord(flag *Flag) (*flagRecord, error) {
+	strategy, err := encodeStrategy(flag.Strategy)
+	if err != nil {
+		return nil, fmt.Errorf("flag %q: %w", flag.Name, err)
+	}
+	return &flagRecord{
+		Name:        flag.Name,
+		Description: flag.Description,
+		Enabled:     flag.Enabled,
+		Strategy:    strategy,
+		Tags:        slices.Clone(flag.Tags),
+	}, nil
+}
+
+// decodeExport parses and validates exported data into flags.
+func decodeExport(data []byte, ex strategyExtractors) ([]*Flag, error) {
+	var export flagExport
+	if err := json.Unmarshal(data, &export); err != nil {
+		return nil, errors.Join(ErrInvalidFlag, err)
+	}
+	if export.Version != exportVersion {
+		return nil, errors.Join(ErrInvalidFlag, fmt.Errorf("unsupported export version %d", export.Version))
+	}
+
+	flags := make([]*Flag, 0, len(export.Flags))
+	seen := make(map[string]bool, len(export.Flags))
+	for _, record := range export.Flags {
+		if record == nil || record.Name == "" {
+			return nil, errors.Join(ErrInvalidFlag, errors.New("flag name cannot be empty"))
+		}
+		if seen[record.Name] {
+			return nil, errors.Join(ErrInvalidFlag, fmt.Errorf("duplicate flag %q", record.Name))
+		}
+		seen[record.Name] = true
+
+		strategy, err := decodeStrategy(record.Strategy, ex)
+		if err != nil {
+			return nil, fmt.Errorf("flag %q: %w", record.Name, err)
+		}
+
+		flags = append(flags, &Flag{
+			Name:        record.Name,
+			Description: record.Description,
+			Enabled:     record.Enabled,
+			Strategy:    strategy,
+			Tags:        slices.Clone(record.Tags),
+		})
+	}
+
+	return flags, nil
+}
+
+// sameFlag compares the exported representation of two flags, ignoring timestamps.
+// Flags that can't be serialized, e.g. with a custom strategy, are treated as different.
+func sameFlag(a, b *Flag) bool {
+	ja, errA := flagJSON(a)
+	jb, errB := flagJSON(b)
+	return errA == nil && errB == nil && bytes.Equal(ja, jb)
+}
+
+func flagJSON(flag *Flag) ([]byte, error) {
+	record, err := toRecord(flag)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(record)
+}
@@ -0,0 +1,283 @@
+package feature_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dmitrymomot/saaskit/pkg/feature"
+)
+
+type customStrategy struct{}
+
+func (customStrategy) Evaluate(context.Context) (bool, error) { return true, nil }
+
+func newExportSource(t *testing.T) *feature.MemoryProvider {
+	t.Helper()
+
+	percentage := 30
+	provider, err := feature.NewMemoryProvider(
+		&feature.Flag{
+			Name:     "always",
+			Enabled:  true,
+			Strategy: feature.NewAlwaysOffStrategy(),
+			Tags:     []string{"ui"},
+		},
+		&feature.Flag{
+			Name:        "beta",
+			Description: "Beta rollout",
+			Enabled:     true,
+			Strategy: feature.NewOrStrategy(
+				feature.NewTargetedStrategy(feature.TargetCriteria{
+					UserIDs:    []string{"user-1"},
+					Percentage: &percentage,
+				}, feature.WithUserIDExtractor(testMemoryUserIDExtractor)),
+				feature.NewAndStrategy(
+					feature.NewEnvironmentStrategy([]string{"staging"}),
+					feature.NewAlwaysOnStrategy(),
+				),
+			),
+		},
+		&feature.Flag{Name: "plain", Enabled: false},
+	)
+	require.NoError(t, err)
+	return provider
+}
+
+func TestExportImportFlags(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	t.Run("round-trips strategies", func(t *testing.T) {
+		t.Parallel()
+
+		data, err := feature.ExportFlags(ctx, newExportSource(t))
+		require.NoError(t, err)
+
+		target, err := feature.NewMemoryProvider()
+		require.NoError(t, err)
+		result, err := feature.ImportFlags(ctx, target, data,
+			feature.WithImportUserIDExtractor(testMemoryUserIDExtractor),
+			feature.WithImportEnvironmentExtractor(func(context.Context) string { return "production" }),
+		)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"always", "beta", "plain"}, result.Created)
+
+		beta, err := target.GetFlag(ctx, "beta")
+		require.NoError(t, err)
+		assert.Equal(t, "Beta rollout", beta.Description)
+		composite, ok := beta.Strategy.(*feature.CompositeStrategy)
+		require.True(t, ok)
+		assert.Equal(t, "or", composite.Operator)
+		require.Len(t, composite.Strategies, 2)
+		targeted, ok := composite.Strategies[0].(*feature.TargetedStrategy)
+		require.True(t, ok)
+		assert.Equal(t, 30, *targeted.Criteria.Percentage)
+
+		// Extractors are attached to imported strategies
+		enabled, err := target.IsEnabled(context.WithValue(ctx, testMemoryUserIDKey{}, "user-1"), "beta")
+		require.NoError(t, err)
+		assert.True(t, enabled)
+
+		always, err := target.GetFlag(ctx, "always")
+		require.NoError(t, err)
+		assert.Equal(t, &feature.AlwaysStrategy{Value: false}, always.Strategy)
+		assert.Equal(t, []string{"ui"}, always.Tags)
+		assert.False(t, always.CreatedAt.IsZero())
+
+		exported, err := feature.ExportFlags(ctx, target)
+		require.NoError(t, err)
+		assert.JSONEq(t, string(data), string(exported))
+	})
+
+	t.Run("round-trips tenant bucketing", func(t *testing.T) {
+		t.Parallel()
+
+		all := 100
+		source, err := feature.NewMemoryProvider(&feature.Flag{
+			Name:    "workspace-rollout",
+			Enabled: true,
+			Strategy: feature.NewTargetedStrategy(feature.TargetCriteria{
+				Percentage: &all,
+				BucketBy:   feature.BucketByTenant,
+			}),
+		})
+		require.NoError(t, err)
+		data, err := feature.ExportFlags(ctx, source)
+		require.NoError(t, err)
+		assert.Contains(t, string(data), `"bucket_by": "tenant"`)
+
+		target, err := feature.NewMemoryProvider()
+		require.NoError(t, err)
+		_, err = feature.ImportFlags(ctx, target, data, feature.WithImportTenantIDExtractor(testTenantIDExtractor))
+		require.NoError(t, err)
+
+		flag, err := target.GetFlag(ctx, "workspace-rollout")
+		require.NoError(t, err)
+		targeted, ok := flag.Strategy.(*feature.TargetedStrategy)
+		require.True(t, ok)
+		assert.Equal(t, feature.BucketByTenant, targeted.Criteria.BucketBy)
+
+		enabled, err := target.IsEnabled(context.WithValue(ctx, testTenantIDKey{}, "tenant-1"), "workspace-rollout")
+		require.NoError(t, err)
+		assert.True(t, enabled)
+	})
+
+	t.Run("round-trips schedules in UTC", func(t *testing.T) {
+		t.Parallel()
+
+		start := time.Date(2026, 11, 27, 9, 0, 0, 0, time.FixedZone("CET", 3600))
+		source, err := feature.NewMemoryProvider(&feature.Flag{
+			Name:     "black-friday",
+			Enabled:  true,
+			Strategy: feature.NewScheduleStrategy(&start, nil),
+		})
+		require.NoError(t, err)
+		data, err := feature.ExportFlags(ctx, source)
+		require.NoError(t, err)
+		assert.Contains(t, string(data), `"starts_at": "2026-11-27T08:00:00Z"`)
+
+		target, err := feature.NewMemoryProvider()
+		require.NoError(t, err)
+		_, err = feature.ImportFlags(ctx, target, data)
+		require.NoError(t, err)
+
+		flag, err := target.GetFlag(ctx, "black-friday")
+		require.NoError(t, err)
+		schedule, ok := flag.Strategy.(*feature.ScheduleStrategy)
+		require.True(t, ok)
+		assert.True(t, schedule.StartsAt.Equal(start))
+		assert.Nil(t, schedule.EndsAt)
+	})
+
+	t.Run("merge keeps missing flags", func(t *testing.T) {
+		t.Parallel()
+
+		data, err := feature.ExportFlags(ctx, newExportSource(t))
+		require.NoError(t, err)
+
+		target, err := feature.NewMemoryProvider(
+			&feature.Flag{Name: "local", Enabled: true},
+			&feature.Flag{Name: "plain", Enabled: true},
+			&feature.Flag{Name: "always", Enabled: true, Strategy: feature.NewAlwaysOffStrategy(), Tags: []string{"ui"}},
+		)
+		require.NoError(t, err)
+
+		result, err := feature.ImportFlags(ctx, target, data)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"beta"}, result.Created)
+		assert.Equal(t, []string{"plain"}, result.Updated)
+		assert.Equal(t, []string{"always"}, result.Unchanged)
+		assert.Empty(t, result.Deleted)
+
+		_, err = target.GetFlag(ctx, "local")
+		require.NoError(t, err)
+		plain, err := target.GetFlag(ctx, "plain")
+		require.NoError(t, err)
+		assert.False(t, plain.Enabled)
+	})
+
+	t.Run("replace deletes missing flags", func(t *testing.T) {
+		t.Parallel()
+
+		data, err := feature.ExportFlags(ctx, newExportSource(t))
+		require.NoError(t, err)
+
+		target, err := feature.NewMemoryProvider(&feature.Flag{Name: "local", Enabled: true})
+		require.NoError(t, err)
+
+		result, err := feature.ImportFlags(ctx, target, data, feature.WithReplace())
+		require.NoError(t, err)
+		assert.Equal(t, []string{"local"}, result.Deleted)
+
+		_, err = target.GetFlag(ctx, "local")
+		assert.ErrorIs(t, err, feature.ErrFlagNotFound)
+	})
+
+	t.Run("dry run returns diff without applying", func(t *testing.T) {
+		t.Parallel()
+
+		data, err := feature.ExportFlags(ctx, newExportSource(t))
+		require.NoError(t, err)
+
+		target, err := feature.NewMemoryProvider(&feature.Flag{Name: "local", Enabled: true})
+		require.NoError(t, err)
+
+		result, err := feature.ImportFlags(ctx, target, data, feature.WithReplace(), feature.WithDryRun())
+		require.NoError(t, err)
+		assert.True(t, result.DryRun)
+		assert.True(t, result.HasChanges())
+		assert.Equal(t, []string{"always", "beta", "plain"}, result.Created)
+		assert.Equal(t, []string{"local"}, result.Deleted)
+
+		flags, err := target.ListFlags(ctx)
+		require.NoError(t, err)
+		require.Len(t, flags, 1)
+		assert.Equal(t, "local", flags[0].Name)
+	})
+
+	t.Run("changes go through the provider", func(t *testing.T) {
+		t.Parallel()
+
+		data, err := feature.ExportFlags(ctx, newExportSource(t))
+		require.NoError(t, err)
+
+		memory, err := feature.NewMemoryProvider(
+			&feature.Flag{Name: "local", Enabled: true},
+			&feature.Flag{Name: "plain", Enabled: true},
+		)
+		require.NoError(t, err)
+		changeLog := feature.NewMemoryChangeLog()
+		target := feature.NewTrackingProvider(memory, feature.WithChangeRecorder(changeLog.Record))
+
+		_, err = feature.ImportFlags(ctx, target, data, feature.WithReplace())
+		require.NoError(t, err)
+
+		for name, action := range map[string]feature.ChangeAction{
+			"beta":  feature.ChangeCreated,
+			"plain": feature.ChangeUpdated,
+			"local": feature.ChangeDeleted,
+		} {
+			history, err := changeLog.FlagHistory(ctx, name)
+			require.NoError(t, err)
+			require.Len(t, history, 1, name)
+			assert.Equal(t, action, history[0].Action, name)
+		}
+	})
+
+	t.Run("rejects invalid data", func(t *testing.T) {
+		t.Parallel()
+
+		target, err := feature.NewMemoryProvider(&feature.Flag{Name: "local", Enabled: true})
+		require.NoError(t, err)
+
+		for name, data := range map[string]string{
+			"malformed":        `{`,
+			"unknown version":  `{"version":99,"flags":[]}`,
+			"empty name":       `{"version":1,"flags":[{"name":""}]}`,
+			"duplicate":        `{"version":1,"flags":[{"name":"a"},{"name":"a"}]}`,
+			"unknown strategy": `{"version":1,"flags":[{"name":"a","strategy":{"type":"magic"}}]}`,
+			"bad operator":     `{"version":1,"flags":[{"name":"a","strategy":{"type":"composite","operator":"xor"}}]}`,
+		} {
+			_, err := feature.ImportFlags(ctx, target, []byte(data), feature.WithReplace())
+			assert.Error(t, err, name)
+		}
+
+		// Nothing is applied when validation fails
+		_, err = target.GetFlag(ctx, "local")
+		require.NoError(t, err)
+	})
+
+	t.Run("custom strategies cannot be exported", func(t *testing.T) {
+		t.Parallel()
+
+		provider, err := feature.NewMemoryProvider(&feature.Flag{Name: "custom", Enabled: true, Strategy: customStrategy{}})
+		require.NoError(t, err)
+
+		_, err = feature.ExportFlags(ctx, provider)
+		assert.ErrorIs(t, err, feature.ErrInvalidStrategy)
+	})
+}
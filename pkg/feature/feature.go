@@ -2,6 +2,8 @@ package feature
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"time"
 )
 
@@ -32,21 +34,70 @@ type TargetCriteria struct {
 	AllowList []string `json:"allow_list,omitempty"`
 	// DenyList overrides all other criteria (highest precedence)
 	DenyList []string `json:"deny_list,omitempty"`
+	// BucketBy selects the ID hashed for Percentage: BucketByUser (default) or BucketByTenant
+	BucketBy string `json:"bucket_by,omitempty"`
 }
 
+// Percentage bucketing keys for TargetCriteria.BucketBy.
+const (
+	// BucketByUser rolls out per user; an empty BucketBy means the same
+	BucketByUser = "user"
+	// BucketByTenant rolls out per tenant, so all users of a tenant get the same result
+	BucketByTenant = "tenant"
+)
+
 // Extractor function types for retrieving data from context.
 // These allow users to define how to extract feature flag evaluation data
 // from their application's context, maintaining decoupling from the feature package.
 type (
 	UserIDExtractor      func(ctx context.Context) string
 	UserGroupsExtractor  func(ctx context.Context) []string
+	TenantIDExtractor    func(ctx context.Context) string
 	EnvironmentExtractor func(ctx context.Context) string
 )
 
+// evaluate applies a flag's global state and strategy, as IsEnabled does.
+func evaluate(ctx context.Context, flag *Flag) (bool, error) {
+	// Global disabled state overrides all strategies
+	if !flag.Enabled {
+		return false, nil
+	}
+
+	if flag.Strategy == nil {
+		return flag.Enabled, nil
+	}
+	return flag.Strategy.Evaluate(ctx)
+}
+
+// evaluateBatch evaluates looked-up flags for IsEnabledBatch; nil flags are missing.
+func evaluateBatch(ctx context.Context, flagNames []string, flags []*Flag) (map[string]bool, error) {
+	result := make(map[string]bool, len(flagNames))
+	var errs []error
+	for i, name := range flagNames {
+		if flags[i] == nil {
+			result[name] = false
+			errs = append(errs, fmt.Errorf("flag %q: %w", name, ErrFlagNotFound))
+			continue
+		}
+
+		enabled, err := evaluate(ctx, flags[i])
+		if err != nil {
+			errs = append(errs, fmt.Errorf("flag %q: %w", name, err))
+		}
+		result[name] = enabled && err == nil
+	}
+	return result, errors.Join(errs...)
+}
+
 // Provider is the interface that all feature flag providers must implement.
 type Provider interface {
 	// Evaluation methods
 	IsEnabled(ctx context.Context, flagName string) (bool, error)
+	// IsEnabledBatch evaluates several flags at once, e.g. all flags used by a page.
+	// The map has an entry for every name; flags that are missing or fail to evaluate
+	// map to false and their errors are joined into err, so errors.Is(err, ErrFlagNotFound)
+	// reports missing flags. The map is nil only when the provider itself fails.
+	IsEnabledBatch(ctx context.Context, flagNames []string) (map[string]bool, error)
 	GetFlag(ctx context.Context, flagName string) (*Flag, error)
 
 	// Management methods
@@ -55,15 +55,21 @@ func (m *MemoryProvider) IsEnabled(ctx context.Context, flagName string) (bool,
 		return false, ErrFlagNotFound
 	}
 
-	// Global disabled state overrides all strategies
-	if !flag.Enabled {
-		return false, nil
-	}
+	return evaluate(ctx, flag)
+}
+
+// IsEnabledBatch takes the read lock once for all flags instead of once per flag.
+// Strategies are evaluated after the lock is released, as in IsEnabled.
+func (m *MemoryProvider) IsEnabledBatch(ctx context.Context, flagNames []string) (map[string]bool, error) {
+	flags := make([]*Flag, len(flagNames))
 
-	if flag.Strategy == nil {
-		return flag.Enabled, nil
+	m.mu.RLock()
+	for i, name := range flagNames {
+		flags[i] = m.flags[name]
 	}
-	return flag.Strategy.Evaluate(ctx)
+	m.mu.RUnlock()
+
+	return evaluateBatch(ctx, flagNames, flags)
 }
 
 func (m *MemoryProvider) GetFlag(ctx context.Context, flagName string) (*Flag, error) {
@@ -131,3 +131,52 @@ func BenchmarkMemoryProvider_ConcurrentAccess(b *testing.B) {
 		}
 	})
 }
+
+func BenchmarkMemoryProvider_IsEnabledBatch(b *testing.B) {
+	const numFlags = 15
+
+	flags := make([]*feature.Flag, numFlags)
+	names := make([]string, numFlags)
+	for i := range numFlags {
+		names[i] = fmt.Sprintf("page-flag-%d", i)
+		flags[i] = &feature.Flag{Name: names[i], Enabled: i%2 == 0}
+	}
+	provider, err := feature.NewMemoryProvider(flags...)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	ctx := context.Background()
+
+	b.Run("single-calls", func(b *testing.B) {
+		for b.Loop() {
+			for _, name := range names {
+				_, _ = provider.IsEnabled(ctx, name)
+			}
+		}
+	})
+
+	b.Run("batch", func(b *testing.B) {
+		for b.Loop() {
+			_, _ = provider.IsEnabledBatch(ctx, names)
+		}
+	})
+
+	b.Run("single-calls-parallel", func(b *testing.B) {
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				for _, name := range names {
+					_, _ = provider.IsEnabled(ctx, name)
+				}
+			}
+		})
+	})
+
+	b.Run("batch-parallel", func(b *testing.B) {
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				_, _ = provider.IsEnabledBatch(ctx, names)
+			}
+		})
+	})
+}
@@ -298,6 +298,40 @@ func TestMemoryProvider(t *testing.T) {
 		assert.Equal(t, feature.ErrFlagNotFound, err)
 	})
 
+	t.Run("IsEnabledBatch", func(t *testing.T) {
+		t.Parallel()
+		provider, _ := feature.NewMemoryProvider(
+			&feature.Flag{Name: "always-on", Enabled: true, Strategy: feature.NewAlwaysOnStrategy()},
+			&feature.Flag{Name: "disabled-flag", Enabled: false, Strategy: feature.NewAlwaysOnStrategy()},
+			&feature.Flag{
+				Name:    "targeted-flag",
+				Enabled: true,
+				Strategy: feature.NewTargetedStrategy(feature.TargetCriteria{
+					UserIDs: []string{"test-user"},
+				}, feature.WithUserIDExtractor(testMemoryUserIDExtractor)),
+			},
+		)
+
+		userCtx := context.WithValue(ctx, testMemoryUserIDKey{}, "test-user")
+		result, err := provider.IsEnabledBatch(userCtx, []string{"always-on", "disabled-flag", "targeted-flag"})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]bool{
+			"always-on":     true,
+			"disabled-flag": false,
+			"targeted-flag": true,
+		}, result)
+
+		// Missing flags map to false and are reported in the joined error
+		result, err = provider.IsEnabledBatch(ctx, []string{"always-on", "non-existent"})
+		require.ErrorIs(t, err, feature.ErrFlagNotFound)
+		assert.Contains(t, err.Error(), `"non-existent"`)
+		assert.Equal(t, map[string]bool{"always-on": true, "non-existent": false}, result)
+
+		result, err = provider.IsEnabledBatch(ctx, nil)
+		require.NoError(t, err)
+		assert.Empty(t, result)
+	})
+
 	t.Run("Close", func(t *testing.T) {
 		t.Parallel()
 		provider, _ := feature.NewMemoryProvider()
@@ -305,3 +339,35 @@ func TestMemoryProvider(t *testing.T) {
 		require.NoError(t, err)
 	})
 }
+
+func TestMemoryProvider_IsEnabled_Schedule(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	start := time.Date(2026, 11, 27, 0, 0, 0, 0, time.UTC)
+	end := start.Add(72 * time.Hour)
+
+	var now time.Time
+	provider, err := feature.NewMemoryProvider(&feature.Flag{
+		Name:    "promo",
+		Enabled: true,
+		Strategy: feature.NewScheduleStrategy(&start, &end,
+			feature.WithClock(func() time.Time { return now }),
+		),
+	})
+	require.NoError(t, err)
+
+	for _, tt := range []struct {
+		now  time.Time
+		want bool
+	}{
+		{now: start.Add(-time.Minute), want: false},
+		{now: start.Add(time.Hour), want: true},
+		{now: end, want: false},
+	} {
+		now = tt.now
+		enabled, err := provider.IsEnabled(ctx, "promo")
+		require.NoError(t, err)
+		assert.Equal(t, tt.want, enabled, "at %s", tt.now)
+	}
+}
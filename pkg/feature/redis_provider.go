@@ -0,0 +1,569 @@
+package feature
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisProvider defaults
+const (
+	// DefaultRedisKeyPrefix is prepended to the flags hash and the change channel
+	DefaultRedisKeyPrefix = "feature:"
+
+	// DefaultRedisCacheTTL bounds how long a cached flag is served if an invalidation is lost
+	DefaultRedisCacheTTL = time.Minute
+)
+
+// updateFlagScript overwrites an existing flag only, so UpdateFlag can't
+// resurrect a flag deleted in the meantime. Returns 0 if the flag is missing.
+//
+// KEYS[1] - flags hash
+// ARGV    - flag name, flag JSON
+var updateFlagScript = redis.NewScript(`
+if redis.call('HEXISTS', KEYS[1], ARGV[1]) == 0 then
+	return 0
+end
+redis.call('HSET', KEYS[1], ARGV[1], ARGV[2])
+return 1
+`)
+
+// redisFlagRecord is the JSON stored per flag. Unlike exports, timestamps are kept.
+type redisFlagRecord struct {
+	flagRecord
+	CreatedAt time.Time `json:"created_at,omitzero"`
+	UpdatedAt time.Time `json:"updated_at,omitzero"`
+}
+
+// cachedFlag is a local cache entry; a nil flag caches a missing flag
+type cachedFlag struct {
+	flag    *Flag
+	expires time.Time
+}
+
+// RedisProvider implements Provider on top of Redis, so flags are shared across instances.
+//
+// Flags are stored as JSON in a single hash, including their strategies; custom
+// Strategy implementations can't be serialized and are rejected with ErrInvalidStrategy.
+// Context extractors can't be stored either and are passed with WithRedisUserIDExtractor
+// and related options, like ImportFlags.
+//
+// IsEnabled and GetFlag are served from a local cache. Every CreateFlag, UpdateFlag
+// and DeleteFlag publishes the flag name on a pub/sub channel and all instances,
+// including the writer, drop their cached copy. The whole cache is dropped when the
+// subscription reconnects, and entries expire after the cache TTL in case a message
+// is lost anyway. ListFlags always reads from Redis.
+//
+// All methods are safe for concurrent use.
+type RedisProvider struct {
+	client     redis.UniversalClient
+	key        string
+	channel    string
+	cacheTTL   time.Duration
+	extractors strategyExtractors
+	log        *slog.Logger
+
+	mu         sync.RWMutex
+	cache      map[string]cachedFlag
+	generation uint64 // Incremented on every invalidation, guards cache fills racing with it
+
+	pubsub    *redis.PubSub
+	closeOnce sync.Once
+	closed    chan struct{}
+	done      chan struct{} // closed when the receive loop exits
+}
+
+// RedisProviderOption configures a RedisProvider.
+type RedisProviderOption func(*RedisProvider)
+
+// WithRedisKeyPrefix sets the prefix of the flags hash ("<prefix>flags") and
+// the change channel ("<prefix>changes"). Default is DefaultRedisKeyPrefix.
+func WithRedisKeyPrefix(prefix string) RedisProviderOption {
+	return func(p *RedisProvider) {
+		if prefix != "" {
+			p.key = prefix + "flags"
+			p.channel = prefix + "changes"
+		}
+	}
+}
+
+// WithRedisCacheTTL sets how long flags are cached locally. Invalidation messages
+// normally evict them much sooner; the TTL only bounds staleness when one is lost.
+// A TTL <= 0 keeps entries until they are invalidated. Default is DefaultRedisCacheTTL.
+func WithRedisCacheTTL(ttl time.Duration) RedisProviderOption {
+	return func(p *RedisProvider) {
+		p.cacheTTL = ttl
+	}
+}
+
+// WithRedisLogger sets the logger used to report unexpected pub/sub messages.
+// Nothing is logged by default.
+func WithRedisLogger(logger *slog.Logger) RedisProviderOption {
+	return func(p *RedisProvider) {
+		if logger != nil {
+			p.log = logger
+		}
+	}
+}
+
+// WithRedisUserIDExtractor sets the user ID extractor for stored targeted strategies.
+func WithRedisUserIDExtractor(extractor UserIDExtractor) RedisProviderOption {
+	return func(p *RedisProvider) {
+		p.extractors.userID = extractor
+	}
+}
+
+// WithRedisUserGroupsExtractor sets the user groups extractor for stored targeted strategies.
+func WithRedisUserGroupsExtractor(extractor UserGroupsExtractor) RedisProviderOption {
+	return func(p *RedisProvider) {
+		p.extractors.userGroups = extractor
+	}
+}
+
+// WithRedisTenantIDExtractor sets the tenant ID extractor for stored targeted strategies.
+func WithRedisTenantIDExtractor(extractor TenantIDExtractor) RedisProviderOption {
+	return func(p *RedisProvider) {
+		p.extractors.tenantID = extractor
+	}
+}
+
+// WithRedisEnvironmentExtractor sets the environment extractor for stored environment strategies.
+func WithRedisEnvironmentExtractor(extractor EnvironmentExtractor) RedisProviderOption {
+	return func(p *RedisProvider) {
+		p.extractors.environment = extractor
+	}
+}
+
+// NewRedisProvider subscribes to the change channel and returns a provider backed by client,
+// which can be any redis.UniversalClient; all flags live in one hash, so a cluster works too.
+// The subscription is confirmed before returning, so no change made afterwards is missed.
+// Panics if client is nil.
+func NewRedisProvider(ctx context.Context, client redis.UniversalClient, opts ...RedisProviderOption) (*RedisProvider, error) {
+	if client == nil {
+		panic("feature: redis client is required")
+	}
+
+	p := newRedisProvider(client, opts...)
+
+	pubsub := client.Subscribe(ctx, p.channel)
+	if _, err := pubsub.Receive(ctx); err != nil {
+		_ = pubsub.Close()
+		return nil, errors.Join(ErrProviderNotInitialized, err)
+	}
+	p.pubsub = pubsub
+
+	go p.receive(pubsub.ChannelWithSubscriptions())
+
+	return p, nil
+}
+
+// newRedisProvider applies the options without subscribing.
+func newRedisProvider(client redis.UniversalClient, opts ...RedisProviderOption) *RedisProvider {
+	p := &RedisProvider{
+		client:   client,
+		key:      DefaultRedisKeyPrefix + "flags",
+		channel:  DefaultRedisKeyPrefix + "changes",
+		cacheTTL: DefaultRedisCacheTTL,
+		log:      slog.New(slog.NewTextHandler(io.Discard, nil)),
+		cache:    make(map[string]cachedFlag),
+		closed:   make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+func (p *RedisProvider) IsEnabled(ctx context.Context, flagName string) (bool, error) {
+	flag, err := p.load(ctx, flagName)
+	if err != nil {
+		return false, err
+	}
+
+	return evaluate(ctx, flag)
+}
+
+// IsEnabledBatch reads cached flags under a single lock and fetches all
+// misses from Redis in one HMGET round trip.
+func (p *RedisProvider) IsEnabledBatch(ctx context.Context, flagNames []string) (map[string]bool, error) {
+	flags, err := p.loadMany(ctx, flagNames)
+	if err != nil {
+		return nil, err
+	}
+
+	return evaluateBatch(ctx, flagNames, flags)
+}
+
+func (p *RedisProvider) GetFlag(ctx context.Context, flagName string) (*Flag, error) {
+	flag, err := p.load(ctx, flagName)
+	if err != nil {
+		return nil, err
+	}
+
+	flagCopy := *flag
+	if flag.Tags != nil {
+		flagCopy.Tags = slices.Clone(flag.Tags)
+	}
+	return &flagCopy, nil
+}
+
+// ListFlags reads all flags from Redis, sorted by name. A flag matches if it has any of the tags.
+func (p *RedisProvider) ListFlags(ctx context.Context, tags ...string) ([]*Flag, error) {
+	values, err := p.client.HGetAll(ctx, p.key).Result()
+	if err != nil {
+		return nil, errors.Join(ErrOperationFailed, err)
+	}
+
+	result := make([]*Flag, 0, len(values))
+	for _, data := range values {
+		flag, err := p.decode([]byte(data))
+		if err != nil {
+			return nil, err
+		}
+		if len(tags) > 0 && !slices.ContainsFunc(tags, func(tag string) bool { return slices.Contains(flag.Tags, tag) }) {
+			continue
+		}
+		result = append(result, flag)
+	}
+
+	slices.SortFunc(result, func(a, b *Flag) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return result, nil
+}
+
+func (p *RedisProvider) CreateFlag(ctx context.Context, flag *Flag) error {
+	if err := validateFlag(flag); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	data, err := p.encode(flag, now, now)
+	if err != nil {
+		return err
+	}
+
+	created, err := p.client.HSetNX(ctx, p.key, flag.Name, data).Result()
+	if err != nil {
+		return errors.Join(ErrOperationFailed, err)
+	}
+	if !created {
+		return errors.Join(ErrInvalidFlag, errors.New("flag already exists"))
+	}
+
+	flag.CreatedAt = now
+	flag.UpdatedAt = now
+
+	return p.publish(ctx, flag.Name)
+}
+
+func (p *RedisProvider) UpdateFlag(ctx context.Context, flag *Flag) error {
+	if err := validateFlag(flag); err != nil {
+		return err
+	}
+
+	existing, err := p.fetch(ctx, flag.Name)
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		return ErrFlagNotFound
+	}
+
+	now := time.Now()
+	data, err := p.encode(flag, existing.CreatedAt, now)
+	if err != nil {
+		return err
+	}
+
+	updated, err := updateFlagScript.Run(ctx, p.client, []string{p.key}, flag.Name, data).Int()
+	if err != nil {
+		return errors.Join(ErrOperationFailed, err)
+	}
+	if updated == 0 {
+		return ErrFlagNotFound
+	}
+
+	flag.CreatedAt = existing.CreatedAt
+	flag.UpdatedAt = now
+
+	return p.publish(ctx, flag.Name)
+}
+
+func (p *RedisProvider) DeleteFlag(ctx context.Context, flagName string) error {
+	deleted, err := p.client.HDel(ctx, p.key, flagName).Result()
+	if err != nil {
+		return errors.Join(ErrOperationFailed, err)
+	}
+	if deleted == 0 {
+		return ErrFlagNotFound
+	}
+
+	return p.publish(ctx, flagName)
+}
+
+// Close unsubscribes from the change channel. Afterwards the local cache is
+// bypassed, since it would no longer be invalidated. The Redis client is not closed.
+// It is safe to call Close multiple times.
+func (p *RedisProvider) Close() error {
+	var err error
+	p.closeOnce.Do(func() {
+		close(p.closed)
+		p.flush()
+		if p.pubsub != nil {
+			// Closing the pubsub closes its channel, which ends the receive loop
+			err = p.pubsub.Close()
+			<-p.done
+		}
+	})
+	return err
+}
+
+// load returns the flag from the local cache, reading it from Redis on a miss.
+func (p *RedisProvider) load(ctx context.Context, flagName string) (*Flag, error) {
+	if p.isClosed() {
+		return p.fetchExisting(ctx, flagName)
+	}
+
+	p.mu.RLock()
+	entry, ok := p.cache[flagName]
+	generation := p.generation
+	p.mu.RUnlock()
+
+	if ok && (entry.expires.IsZero() || time.Now().Before(entry.expires)) {
+		if entry.flag == nil {
+			return nil, ErrFlagNotFound
+		}
+		return entry.flag, nil
+	}
+
+	flag, err := p.fetch(ctx, flagName)
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	// Skip the fill if an invalidation arrived while reading, the value may be stale
+	if p.generation == generation {
+		entry := cachedFlag{flag: flag}
+		if p.cacheTTL > 0 {
+			entry.expires = time.Now().Add(p.cacheTTL)
+		}
+		p.cache[flagName] = entry
+	}
+	p.mu.Unlock()
+
+	if flag == nil {
+		return nil, ErrFlagNotFound
+	}
+	return flag, nil
+}
+
+// loadMany is the batch version of load; missing flags are returned as nil.
+func (p *RedisProvider) loadMany(ctx context.Context, flagNames []string) ([]*Flag, error) {
+	flags := make([]*Flag, len(flagNames))
+	var misses []int
+
+	useCache := !p.isClosed()
+	var generation uint64
+	if useCache {
+		now := time.Now()
+		p.mu.RLock()
+		for i, name := range flagNames {
+			entry, ok := p.cache[name]
+			if ok && (entry.expires.IsZero() || now.Before(entry.expires)) {
+				flags[i] = entry.flag
+				continue
+			}
+			misses = append(misses, i)
+		}
+		generation = p.generation
+		p.mu.RUnlock()
+	} else {
+		for i := range flagNames {
+			misses = append(misses, i)
+		}
+	}
+
+	if len(misses) == 0 {
+		return flags, nil
+	}
+
+	fields := make([]string, len(misses))
+	for j, i := range misses {
+		fields[j] = flagNames[i]
+	}
+	values, err := p.client.HMGet(ctx, p.key, fields...).Result()
+	if err != nil {
+		return nil, errors.Join(ErrOperationFailed, err)
+	}
+
+	for j, i := range misses {
+		data, ok := values[j].(string)
+		if !ok {
+			continue // Missing flag
+		}
+		flags[i], err = p.decode([]byte(data))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if useCache {
+		p.mu.Lock()
+		// Skip the fill if an invalidation arrived while reading, values may be stale
+		if p.generation == generation {
+			var expires time.Time
+			if p.cacheTTL > 0 {
+				expires = time.Now().Add(p.cacheTTL)
+			}
+			for _, i := range misses {
+				p.cache[flagNames[i]] = cachedFlag{flag: flags[i], expires: expires}
+			}
+		}
+		p.mu.Unlock()
+	}
+
+	return flags, nil
+}
+
+// fetchExisting reads a flag from Redis, returning ErrFlagNotFound if it's missing.
+func (p *RedisProvider) fetchExisting(ctx context.Context, flagName string) (*Flag, error) {
+	flag, err := p.fetch(ctx, flagName)
+	if err != nil {
+		return nil, err
+	}
+	if flag == nil {
+		return nil, ErrFlagNotFound
+	}
+	return flag, nil
+}
+
+// fetch reads a flag from Redis; a missing flag is returned as nil without error.
+func (p *RedisProvider) fetch(ctx context.Context, flagName string) (*Flag, error) {
+	data, err := p.client.HGet(ctx, p.key, flagName).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Join(ErrOperationFailed, err)
+	}
+	return p.decode(data)
+}
+
+// encode serializes a flag with its strategy and timestamps.
+func (p *RedisProvider) encode(flag *Flag, createdAt, updatedAt time.Time) ([]byte, error) {
+	record, err := toRecord(flag)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(redisFlagRecord{
+		flagRecord: *record,
+		CreatedAt:  createdAt,
+		UpdatedAt:  updatedAt,
+	})
+}
+
+// decode rebuilds a stored flag and attaches the configured extractors to its strategy.
+func (p *RedisProvider) decode(data []byte) (*Flag, error) {
+	var record redisFlagRecord
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, errors.Join(ErrInvalidFlag, err)
+	}
+
+	strategy, err := decodeStrategy(record.Strategy, p.extractors)
+	if err != nil {
+		return nil, fmt.Errorf("flag %q: %w", record.Name, err)
+	}
+
+	return &Flag{
+		Name:        record.Name,
+		Description: record.Description,
+		Enabled:     record.Enabled,
+		Strategy:    strategy,
+		Tags:        record.Tags,
+		CreatedAt:   record.CreatedAt,
+		UpdatedAt:   record.UpdatedAt,
+	}, nil
+}
+
+// publish evicts the flag locally right away, so the writer reads its own
+// change, and notifies the other instances.
+func (p *RedisProvider) publish(ctx context.Context, flagName string) error {
+	p.invalidate(flagName)
+
+	if err := p.client.Publish(ctx, p.channel, flagName).Err(); err != nil {
+		// The change is stored; other instances pick it up when their cache expires
+		return errors.Join(ErrOperationFailed, fmt.Errorf("flag %q saved but change event not published: %w", flagName, err))
+	}
+	return nil
+}
+
+// receive processes change events until the pubsub is closed.
+func (p *RedisProvider) receive(ch <-chan any) {
+	defer close(p.done)
+	for msg := range ch {
+		switch msg := msg.(type) {
+		case *redis.Message:
+			p.invalidate(msg.Payload)
+		case *redis.Subscription:
+			// Resubscribed after a reconnect: events may have been missed meanwhile
+			if msg.Kind == "subscribe" {
+				p.flush()
+			}
+		default:
+			p.log.Warn("feature: unexpected redis pubsub message",
+				slog.String("channel", p.channel),
+				slog.String("type", fmt.Sprintf("%T", msg)),
+			)
+		}
+	}
+}
+
+// invalidate evicts one flag from the local cache.
+func (p *RedisProvider) invalidate(flagName string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	delete(p.cache, flagName)
+	p.generation++
+}
+
+// flush evicts all flags from the local cache.
+func (p *RedisProvider) flush() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	clear(p.cache)
+	p.generation++
+}
+
+func (p *RedisProvider) isClosed() bool {
+	select {
+	case <-p.closed:
+		return true
+	default:
+		return false
+	}
+}
+
+// validateFlag checks the arguments of CreateFlag and UpdateFlag.
+func validateFlag(flag *Flag) error {
+	if flag == nil {
+		return errors.Join(ErrInvalidFlag, errors.New("flag cannot be nil"))
+	}
+	if flag.Name == "" {
+		return errors.Join(ErrInvalidFlag, errors.New("flag name cannot be empty"))
+	}
+	return nil
+}
@@ -0,0 +1,408 @@
+package feature
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var _ Provider = (*RedisProvider)(nil)
+
+type redisTestUserIDKey struct{}
+
+// newUnreachableRedisProvider returns a provider whose Redis commands fail without
+// a network round trip, as nothing listens on port 0. Only the local cache works.
+func newUnreachableRedisProvider(t *testing.T, opts ...RedisProviderOption) *RedisProvider {
+	t.Helper()
+	client := redis.NewClient(&redis.Options{
+		Addr:        "127.0.0.1:0",
+		DialTimeout: 100 * time.Millisecond,
+		MaxRetries:  -1,
+	})
+	t.Cleanup(func() { client.Close() })
+	return newRedisProvider(client, opts...)
+}
+
+// newTestRedisClient connects to the server at REDIS_URL and returns a unique key prefix
+// whose keys are removed after the test. The test is skipped if REDIS_URL is unset.
+func newTestRedisClient(t *testing.T) (*redis.Client, string) {
+	t.Helper()
+
+	url := os.Getenv("REDIS_URL")
+	if url == "" {
+		t.Skip("REDIS_URL is not set")
+	}
+	opts, err := redis.ParseURL(url)
+	require.NoError(t, err)
+	// Named, so a test can find its own pub/sub connection in CLIENT LIST
+	opts.ClientName = "feature-test-" + strings.ReplaceAll(uuid.NewString(), "-", "")
+
+	client := redis.NewClient(opts)
+	require.NoError(t, client.Ping(context.Background()).Err())
+
+	prefix := "feature-test:" + uuid.NewString() + ":"
+	t.Cleanup(func() {
+		client.Del(context.Background(), prefix+"flags")
+		client.Close()
+	})
+	return client, prefix
+}
+
+func TestNewRedisProvider(t *testing.T) {
+	t.Parallel()
+
+	t.Run("panics without client", func(t *testing.T) {
+		t.Parallel()
+		assert.Panics(t, func() {
+			_, _ = NewRedisProvider(context.Background(), nil)
+		})
+	})
+
+	t.Run("fails when subscription fails", func(t *testing.T) {
+		t.Parallel()
+		p := newUnreachableRedisProvider(t)
+		_, err := NewRedisProvider(context.Background(), p.client)
+		assert.ErrorIs(t, err, ErrProviderNotInitialized)
+	})
+
+	t.Run("key prefix", func(t *testing.T) {
+		t.Parallel()
+		p := newUnreachableRedisProvider(t, WithRedisKeyPrefix("app:ff:"))
+		assert.Equal(t, "app:ff:flags", p.key)
+		assert.Equal(t, "app:ff:changes", p.channel)
+	})
+}
+
+func TestRedisProvider_EncodeDecode(t *testing.T) {
+	t.Parallel()
+
+	getUserID := func(ctx context.Context) string {
+		userID, _ := ctx.Value(redisTestUserIDKey{}).(string)
+		return userID
+	}
+	p := newUnreachableRedisProvider(t, WithRedisUserIDExtractor(getUserID))
+
+	percentage := 10
+	start := time.Date(2026, 11, 27, 0, 0, 0, 0, time.UTC)
+	flag := &Flag{
+		Name:        "checkout-v2",
+		Description: "New checkout",
+		Enabled:     true,
+		Tags:        []string{"billing"},
+		Strategy: NewOrStrategy(
+			NewTargetedStrategy(TargetCriteria{
+				UserIDs:    []string{"user-1"},
+				Percentage: &percentage,
+				BucketBy:   BucketByTenant,
+			}),
+			NewAndStrategy(
+				NewEnvironmentStrategy([]string{"staging"}),
+				NewScheduleStrategy(&start, nil),
+				NewAlwaysOnStrategy(),
+			),
+		),
+	}
+	createdAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	updatedAt := createdAt.Add(time.Hour)
+
+	data, err := p.encode(flag, createdAt, updatedAt)
+	require.NoError(t, err)
+
+	decoded, err := p.decode(data)
+	require.NoError(t, err)
+	assert.Equal(t, "checkout-v2", decoded.Name)
+	assert.Equal(t, "New checkout", decoded.Description)
+	assert.True(t, decoded.Enabled)
+	assert.Equal(t, []string{"billing"}, decoded.Tags)
+	assert.True(t, decoded.CreatedAt.Equal(createdAt))
+	assert.True(t, decoded.UpdatedAt.Equal(updatedAt))
+
+	// Nested composite strategies round-trip
+	or, ok := decoded.Strategy.(*CompositeStrategy)
+	require.True(t, ok)
+	assert.Equal(t, "or", or.Operator)
+	require.Len(t, or.Strategies, 2)
+
+	targeted, ok := or.Strategies[0].(*TargetedStrategy)
+	require.True(t, ok)
+	assert.Equal(t, []string{"user-1"}, targeted.Criteria.UserIDs)
+	assert.Equal(t, 10, *targeted.Criteria.Percentage)
+	assert.Equal(t, BucketByTenant, targeted.Criteria.BucketBy)
+
+	and, ok := or.Strategies[1].(*CompositeStrategy)
+	require.True(t, ok)
+	assert.Equal(t, "and", and.Operator)
+	require.Len(t, and.Strategies, 3)
+	assert.Equal(t, []string{"staging"}, and.Strategies[0].(*EnvironmentStrategy).EnabledEnvironments)
+	assert.True(t, and.Strategies[1].(*ScheduleStrategy).StartsAt.Equal(start))
+	assert.Equal(t, &AlwaysStrategy{Value: true}, and.Strategies[2])
+
+	// Configured extractors are attached to decoded strategies
+	enabled, err := decoded.Strategy.Evaluate(context.WithValue(context.Background(), redisTestUserIDKey{}, "user-1"))
+	require.NoError(t, err)
+	assert.True(t, enabled)
+
+	reencoded, err := p.encode(decoded, createdAt, updatedAt)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(data), string(reencoded))
+}
+
+func TestRedisProvider_Cache(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	t.Run("serves cached flags without redis", func(t *testing.T) {
+		t.Parallel()
+		p := newUnreachableRedisProvider(t)
+		p.cache["on"] = cachedFlag{flag: &Flag{Name: "on", Enabled: true, Tags: []string{"a"}}}
+		p.cache["off"] = cachedFlag{flag: &Flag{Name: "off", Enabled: true, Strategy: NewAlwaysOffStrategy()}}
+		p.cache["missing"] = cachedFlag{}
+
+		enabled, err := p.IsEnabled(ctx, "on")
+		require.NoError(t, err)
+		assert.True(t, enabled)
+
+		enabled, err = p.IsEnabled(ctx, "off")
+		require.NoError(t, err)
+		assert.False(t, enabled)
+
+		_, err = p.IsEnabled(ctx, "missing")
+		assert.ErrorIs(t, err, ErrFlagNotFound)
+
+		// GetFlag returns a copy
+		flag, err := p.GetFlag(ctx, "on")
+		require.NoError(t, err)
+		flag.Tags[0] = "changed"
+		assert.Equal(t, "a", p.cache["on"].flag.Tags[0])
+	})
+
+	t.Run("batch serves cached flags without redis", func(t *testing.T) {
+		t.Parallel()
+		p := newUnreachableRedisProvider(t)
+		p.cache["on"] = cachedFlag{flag: &Flag{Name: "on", Enabled: true}}
+		p.cache["off"] = cachedFlag{flag: &Flag{Name: "off", Enabled: true, Strategy: NewAlwaysOffStrategy()}}
+		p.cache["missing"] = cachedFlag{}
+
+		result, err := p.IsEnabledBatch(ctx, []string{"on", "off", "missing"})
+		require.ErrorIs(t, err, ErrFlagNotFound)
+		assert.Equal(t, map[string]bool{"on": true, "off": false, "missing": false}, result)
+
+		// Misses are fetched from redis in one call; its failure fails the batch
+		result, err = p.IsEnabledBatch(ctx, []string{"on", "uncached"})
+		assert.ErrorIs(t, err, ErrOperationFailed)
+		assert.Nil(t, result)
+	})
+
+	t.Run("expired entries are read from redis", func(t *testing.T) {
+		t.Parallel()
+		p := newUnreachableRedisProvider(t)
+		p.cache["on"] = cachedFlag{flag: &Flag{Name: "on", Enabled: true}, expires: time.Now().Add(-time.Second)}
+
+		_, err := p.IsEnabled(ctx, "on")
+		assert.ErrorIs(t, err, ErrOperationFailed)
+	})
+
+	t.Run("change events invalidate the cache", func(t *testing.T) {
+		t.Parallel()
+		p := newUnreachableRedisProvider(t)
+		p.cache["a"] = cachedFlag{flag: &Flag{Name: "a", Enabled: true}}
+		p.cache["b"] = cachedFlag{flag: &Flag{Name: "b", Enabled: true}}
+
+		ch := make(chan any, 2)
+		ch <- &redis.Message{Channel: p.channel, Payload: "a"}
+		close(ch)
+		p.receive(ch)
+
+		_, err := p.IsEnabled(ctx, "a")
+		assert.ErrorIs(t, err, ErrOperationFailed, "invalidated flag should be read from redis")
+		enabled, err := p.IsEnabled(ctx, "b")
+		require.NoError(t, err)
+		assert.True(t, enabled)
+	})
+
+	t.Run("resubscribing flushes the cache", func(t *testing.T) {
+		t.Parallel()
+		p := newUnreachableRedisProvider(t)
+		p.cache["a"] = cachedFlag{flag: &Flag{Name: "a", Enabled: true}}
+		generation := p.generation
+
+		ch := make(chan any, 1)
+		ch <- &redis.Subscription{Kind: "subscribe", Channel: p.channel, Count: 1}
+		close(ch)
+		p.receive(ch)
+
+		assert.Empty(t, p.cache)
+		assert.Greater(t, p.generation, generation)
+	})
+
+	t.Run("closed provider bypasses the cache", func(t *testing.T) {
+		t.Parallel()
+		p := newUnreachableRedisProvider(t)
+		p.cache["a"] = cachedFlag{flag: &Flag{Name: "a", Enabled: true}}
+
+		require.NoError(t, p.Close())
+		require.NoError(t, p.Close())
+
+		_, err := p.IsEnabled(ctx, "a")
+		assert.ErrorIs(t, err, ErrOperationFailed)
+	})
+}
+
+func TestRedisProvider_Validation(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	p := newUnreachableRedisProvider(t)
+
+	assert.ErrorIs(t, p.CreateFlag(ctx, nil), ErrInvalidFlag)
+	assert.ErrorIs(t, p.CreateFlag(ctx, &Flag{}), ErrInvalidFlag)
+	assert.ErrorIs(t, p.UpdateFlag(ctx, nil), ErrInvalidFlag)
+	assert.ErrorIs(t, p.UpdateFlag(ctx, &Flag{}), ErrInvalidFlag)
+
+	// Custom strategies can't be stored
+	err := p.CreateFlag(ctx, &Flag{Name: "custom", Strategy: redisCustomStrategy{}})
+	assert.ErrorIs(t, err, ErrInvalidStrategy)
+
+	// Redis failures are reported as operation errors
+	assert.ErrorIs(t, p.DeleteFlag(ctx, "a"), ErrOperationFailed)
+	_, err = p.ListFlags(ctx)
+	assert.ErrorIs(t, err, ErrOperationFailed)
+}
+
+type redisCustomStrategy struct{}
+
+func (redisCustomStrategy) Evaluate(context.Context) (bool, error) { return true, nil }
+
+func TestRedisProvider_Integration(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	// Two instances sharing flags; without a cache TTL only change events evict entries
+	newInstances := func(t *testing.T) (a, b *RedisProvider, clientB *redis.Client) {
+		t.Helper()
+		clientA, prefix := newTestRedisClient(t)
+		clientB, _ = newTestRedisClient(t)
+
+		var err error
+		a, err = NewRedisProvider(ctx, clientA, WithRedisKeyPrefix(prefix), WithRedisCacheTTL(0))
+		require.NoError(t, err)
+		t.Cleanup(func() { a.Close() })
+		b, err = NewRedisProvider(ctx, clientB, WithRedisKeyPrefix(prefix), WithRedisCacheTTL(0))
+		require.NoError(t, err)
+		t.Cleanup(func() { b.Close() })
+		return a, b, clientB
+	}
+
+	eventuallyEnabled := func(t *testing.T, p *RedisProvider, name string, want bool) {
+		t.Helper()
+		assert.Eventually(t, func() bool {
+			enabled, err := p.IsEnabled(ctx, name)
+			return err == nil && enabled == want
+		}, 5*time.Second, 10*time.Millisecond)
+	}
+
+	t.Run("crud", func(t *testing.T) {
+		t.Parallel()
+		a, _, _ := newInstances(t)
+
+		flag := &Flag{Name: "beta", Enabled: true, Strategy: NewAlwaysOnStrategy(), Tags: []string{"ui"}}
+		require.NoError(t, a.CreateFlag(ctx, flag))
+		assert.ErrorIs(t, a.CreateFlag(ctx, &Flag{Name: "beta"}), ErrInvalidFlag)
+		require.NoError(t, a.CreateFlag(ctx, &Flag{Name: "alpha", Strategy: NewAlwaysOffStrategy()}))
+
+		stored, err := a.GetFlag(ctx, "beta")
+		require.NoError(t, err)
+		assert.True(t, stored.Enabled)
+		assert.Equal(t, []string{"ui"}, stored.Tags)
+		createdAt := stored.CreatedAt
+
+		flags, err := a.ListFlags(ctx)
+		require.NoError(t, err)
+		require.Len(t, flags, 2)
+		assert.Equal(t, "alpha", flags[0].Name)
+		assert.Equal(t, "beta", flags[1].Name)
+
+		flags, err = a.ListFlags(ctx, "ui")
+		require.NoError(t, err)
+		require.Len(t, flags, 1)
+		assert.Equal(t, "beta", flags[0].Name)
+
+		require.NoError(t, a.UpdateFlag(ctx, &Flag{Name: "beta", Enabled: false}))
+		stored, err = a.GetFlag(ctx, "beta")
+		require.NoError(t, err)
+		assert.False(t, stored.Enabled)
+		assert.True(t, createdAt.Equal(stored.CreatedAt), "update keeps the creation time")
+
+		require.NoError(t, a.DeleteFlag(ctx, "beta"))
+		_, err = a.GetFlag(ctx, "beta")
+		assert.ErrorIs(t, err, ErrFlagNotFound)
+
+		// A deleted flag can't be updated back into existence
+		assert.ErrorIs(t, a.UpdateFlag(ctx, &Flag{Name: "beta", Enabled: true}), ErrFlagNotFound)
+		assert.ErrorIs(t, a.DeleteFlag(ctx, "beta"), ErrFlagNotFound)
+		_, err = a.GetFlag(ctx, "beta")
+		assert.ErrorIs(t, err, ErrFlagNotFound)
+	})
+
+	t.Run("changes invalidate other instances", func(t *testing.T) {
+		t.Parallel()
+		a, b, _ := newInstances(t)
+
+		require.NoError(t, a.CreateFlag(ctx, &Flag{Name: "shared", Enabled: true, Strategy: NewAlwaysOnStrategy()}))
+		eventuallyEnabled(t, b, "shared", true)
+
+		require.NoError(t, a.UpdateFlag(ctx, &Flag{Name: "shared", Enabled: false, Strategy: NewAlwaysOnStrategy()}))
+		eventuallyEnabled(t, b, "shared", false)
+
+		require.NoError(t, a.DeleteFlag(ctx, "shared"))
+		assert.Eventually(t, func() bool {
+			_, err := b.IsEnabled(ctx, "shared")
+			return errors.Is(err, ErrFlagNotFound)
+		}, 5*time.Second, 10*time.Millisecond)
+
+		// A cached miss is evicted by a later create as well
+		require.NoError(t, a.CreateFlag(ctx, &Flag{Name: "shared", Enabled: true, Strategy: NewAlwaysOnStrategy()}))
+		eventuallyEnabled(t, b, "shared", true)
+
+		flags, err := b.IsEnabledBatch(ctx, []string{"shared", "missing"})
+		assert.ErrorIs(t, err, ErrFlagNotFound)
+		assert.Equal(t, map[string]bool{"shared": true, "missing": false}, flags)
+	})
+
+	t.Run("resubscribing flushes the cache", func(t *testing.T) {
+		t.Parallel()
+		a, b, clientB := newInstances(t)
+
+		require.NoError(t, a.CreateFlag(ctx, &Flag{Name: "flag", Enabled: true, Strategy: NewAlwaysOnStrategy()}))
+		eventuallyEnabled(t, b, "flag", true)
+
+		// Change the flag without publishing, as if the event was lost
+		data, err := a.encode(&Flag{Name: "flag", Enabled: false, Strategy: NewAlwaysOnStrategy()}, time.Now(), time.Now())
+		require.NoError(t, err)
+		require.NoError(t, clientB.HSet(ctx, b.key, "flag", data).Err())
+
+		enabled, err := b.IsEnabled(ctx, "flag")
+		require.NoError(t, err)
+		assert.True(t, enabled, "stale value is served from the cache")
+
+		// Drop B's pub/sub connection; the client reconnects and resubscribes
+		var killed int64
+		for line := range strings.SplitSeq(clientB.ClientList(ctx).Val(), "\n") {
+			if strings.Contains(line, "name="+clientB.Options().ClientName+" ") && strings.Contains(line, "flags=P") {
+				id := strings.TrimPrefix(strings.Fields(line)[0], "id=")
+				killed += clientB.ClientKillByFilter(ctx, "ID", id).Val()
+			}
+		}
+		require.Equal(t, int64(1), killed)
+
+		eventuallyEnabled(t, b, "flag", false)
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"hash/fnv"
 	"slices"
+	"time"
 )
 
 type AlwaysStrategy struct {
@@ -28,6 +29,7 @@ type TargetedStrategy struct {
 
 	userIDExtractor     UserIDExtractor
 	userGroupsExtractor UserGroupsExtractor
+	tenantIDExtractor   TenantIDExtractor
 }
 
 // Evaluate determines feature enablement using a strict precedence hierarchy:
@@ -36,6 +38,11 @@ type TargetedStrategy struct {
 // 3. UserIDs - direct user targeting
 // 4. Groups - group membership targeting
 // 5. Percentage - consistent hash-based rollout (lowest precedence)
+//
+// Lists, UserIDs and Groups always match the user. Criteria.BucketBy only selects
+// the ID hashed for Percentage: with BucketByTenant the tenant ID is used even when
+// a user ID extractor is configured, and a missing tenant ID disables the rollout
+// rather than falling back to the user, so a tenant is never split.
 func (s *TargetedStrategy) Evaluate(ctx context.Context) (bool, error) {
 	if s.isEmptyCriteria() {
 		return false, ErrInvalidStrategy
@@ -63,12 +70,32 @@ func (s *TargetedStrategy) Evaluate(ctx context.Context) (bool, error) {
 	}
 
 	if s.Criteria.Percentage != nil {
-		return s.evaluatePercentage(userID)
+		bucketID, err := s.bucketID(ctx, userID)
+		if err != nil {
+			return false, err
+		}
+		return s.evaluatePercentage(bucketID)
 	}
 
 	return false, nil
 }
 
+// bucketID returns the ID hashed for percentage rollouts according to Criteria.BucketBy.
+func (s *TargetedStrategy) bucketID(ctx context.Context, userID string) (string, error) {
+	switch s.Criteria.BucketBy {
+	case "", BucketByUser:
+		return userID, nil
+	case BucketByTenant:
+		if s.tenantIDExtractor == nil {
+			return "", nil
+		}
+		return s.tenantIDExtractor(ctx), nil
+	default:
+		return "", errors.Join(ErrInvalidStrategy,
+			errors.New("bucket_by must be 'user' or 'tenant'"))
+	}
+}
+
 func (s *TargetedStrategy) isEmptyCriteria() bool {
 	return s.Criteria.UserIDs == nil && s.Criteria.Groups == nil &&
 		s.Criteria.Percentage == nil && s.Criteria.AllowList == nil &&
@@ -117,9 +144,9 @@ func (s *TargetedStrategy) isInTargetedGroup(ctx context.Context) bool {
 	return false
 }
 
-// evaluatePercentage uses FNV-1a hash for consistent user bucketing.
-// Same user always gets same result, ensuring stable feature rollouts.
-func (s *TargetedStrategy) evaluatePercentage(userID string) (bool, error) {
+// evaluatePercentage uses FNV-1a hash for consistent user or tenant bucketing.
+// Same ID always gets same result, ensuring stable feature rollouts.
+func (s *TargetedStrategy) evaluatePercentage(bucketID string) (bool, error) {
 	percentage := *s.Criteria.Percentage
 	if percentage < 0 || percentage > 100 {
 		return false, errors.Join(ErrInvalidStrategy,
@@ -134,13 +161,13 @@ func (s *TargetedStrategy) evaluatePercentage(userID string) (bool, error) {
 		return true, nil
 	}
 
-	if userID == "" {
+	if bucketID == "" {
 		return false, nil
 	}
 
-	// Hash userID to get consistent 0-99 bucket assignment
+	// Hash the ID to get consistent 0-99 bucket assignment
 	hash := fnv.New32a()
-	hash.Write([]byte(userID))
+	hash.Write([]byte(bucketID))
 	hashValue := hash.Sum32() % 100
 	return int(hashValue) < percentage, nil
 }
@@ -159,6 +186,14 @@ func WithUserGroupsExtractor(extractor UserGroupsExtractor) TargetedStrategyOpti
 	}
 }
 
+// WithTenantIDExtractor sets how the tenant ID is read for percentage rollouts
+// with TargetCriteria.BucketBy set to BucketByTenant.
+func WithTenantIDExtractor(extractor TenantIDExtractor) TargetedStrategyOption {
+	return func(s *TargetedStrategy) {
+		s.tenantIDExtractor = extractor
+	}
+}
+
 func NewTargetedStrategy(criteria TargetCriteria, opts ...TargetedStrategyOption) Strategy {
 	s := &TargetedStrategy{
 		Criteria: criteria,
@@ -213,6 +248,74 @@ func NewEnvironmentStrategy(environments []string, opts ...EnvironmentStrategyOp
 	return s
 }
 
+// ScheduleStrategy enables a feature only within a time window, e.g. a time-boxed promo.
+// StartsAt is inclusive and EndsAt exclusive; a nil bound leaves that side open.
+// Bounds are stored in UTC.
+type ScheduleStrategy struct {
+	StartsAt *time.Time
+	EndsAt   *time.Time
+
+	clock func() time.Time
+}
+
+func (s *ScheduleStrategy) Evaluate(ctx context.Context) (bool, error) {
+	if s.StartsAt == nil && s.EndsAt == nil {
+		return false, ErrInvalidStrategy
+	}
+	if s.StartsAt != nil && s.EndsAt != nil && !s.EndsAt.After(*s.StartsAt) {
+		return false, errors.Join(ErrInvalidStrategy,
+			errors.New("schedule must end after it starts"))
+	}
+
+	now := time.Now()
+	if s.clock != nil {
+		now = s.clock()
+	}
+
+	if s.StartsAt != nil && now.Before(*s.StartsAt) {
+		return false, nil
+	}
+	if s.EndsAt != nil && !now.Before(*s.EndsAt) {
+		return false, nil
+	}
+
+	return true, nil
+}
+
+type ScheduleStrategyOption func(*ScheduleStrategy)
+
+// WithClock replaces time.Now, e.g. to test a schedule at fixed instants.
+func WithClock(clock func() time.Time) ScheduleStrategyOption {
+	return func(s *ScheduleStrategy) {
+		s.clock = clock
+	}
+}
+
+// NewScheduleStrategy creates a strategy enabled from startsAt until endsAt.
+// Either bound may be nil; both are converted to UTC so the window doesn't
+// depend on the location of the times passed in.
+func NewScheduleStrategy(startsAt, endsAt *time.Time, opts ...ScheduleStrategyOption) Strategy {
+	s := &ScheduleStrategy{
+		StartsAt: utcTime(startsAt),
+		EndsAt:   utcTime(endsAt),
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// utcTime returns a UTC copy of t, or nil
+func utcTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	utc := t.UTC()
+	return &utc
+}
+
 type CompositeStrategy struct {
 	Strategies []Strategy
 	Operator   string // "and" or "or"
@@ -2,7 +2,9 @@ package feature_test
 
 import (
 	"context"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -14,8 +16,14 @@ type (
 	testUserIDKey      struct{}
 	testUserGroupsKey  struct{}
 	testEnvironmentKey struct{}
+	testTenantIDKey    struct{}
 )
 
+func testTenantIDExtractor(ctx context.Context) string {
+	tenantID, _ := ctx.Value(testTenantIDKey{}).(string)
+	return tenantID
+}
+
 func testUserIDExtractor(ctx context.Context) string {
 	userID, _ := ctx.Value(testUserIDKey{}).(string)
 	return userID
@@ -202,6 +210,189 @@ func TestTargetedStrategy(t *testing.T) {
 	})
 }
 
+func TestTargetedStrategy_BucketByTenant(t *testing.T) {
+	t.Parallel()
+
+	percentage := 50
+	strategy := feature.NewTargetedStrategy(
+		feature.TargetCriteria{Percentage: &percentage, BucketBy: feature.BucketByTenant},
+		feature.WithUserIDExtractor(testUserIDExtractor),
+		feature.WithTenantIDExtractor(testTenantIDExtractor),
+	)
+
+	ctxFor := func(tenantID, userID string) context.Context {
+		ctx := context.WithValue(context.Background(), testTenantIDKey{}, tenantID)
+		return context.WithValue(ctx, testUserIDKey{}, userID)
+	}
+
+	t.Run("all users of a tenant get the same result", func(t *testing.T) {
+		t.Parallel()
+
+		enabledTenants := 0
+		for i := range 100 {
+			tenantID := fmt.Sprintf("tenant-%d", i)
+			first, err := strategy.Evaluate(ctxFor(tenantID, "user-0"))
+			require.NoError(t, err)
+			if first {
+				enabledTenants++
+			}
+
+			for j := 1; j < 20; j++ {
+				enabled, err := strategy.Evaluate(ctxFor(tenantID, fmt.Sprintf("user-%d", j)))
+				require.NoError(t, err)
+				assert.Equal(t, first, enabled, "tenant %s was split", tenantID)
+			}
+		}
+
+		// Roughly half of the tenants, not all or none
+		assert.Greater(t, enabledTenants, 30)
+		assert.Less(t, enabledTenants, 70)
+	})
+
+	t.Run("missing tenant disables the rollout", func(t *testing.T) {
+		t.Parallel()
+
+		for i := range 20 {
+			enabled, err := strategy.Evaluate(ctxFor("", fmt.Sprintf("user-%d", i)))
+			require.NoError(t, err)
+			assert.False(t, enabled, "should not fall back to user bucketing")
+		}
+	})
+
+	t.Run("lists still match the user", func(t *testing.T) {
+		t.Parallel()
+
+		zero := 0
+		s := feature.NewTargetedStrategy(
+			feature.TargetCriteria{Percentage: &zero, BucketBy: feature.BucketByTenant, UserIDs: []string{"vip"}},
+			feature.WithUserIDExtractor(testUserIDExtractor),
+			feature.WithTenantIDExtractor(testTenantIDExtractor),
+		)
+
+		enabled, err := s.Evaluate(ctxFor("tenant-1", "vip"))
+		require.NoError(t, err)
+		assert.True(t, enabled)
+
+		enabled, err = s.Evaluate(ctxFor("tenant-1", "regular"))
+		require.NoError(t, err)
+		assert.False(t, enabled)
+	})
+
+	t.Run("default buckets by user", func(t *testing.T) {
+		t.Parallel()
+
+		s := feature.NewTargetedStrategy(
+			feature.TargetCriteria{Percentage: &percentage},
+			feature.WithUserIDExtractor(testUserIDExtractor),
+			feature.WithTenantIDExtractor(testTenantIDExtractor),
+		)
+
+		// Users of one tenant are split when bucketing by user
+		results := map[bool]int{}
+		for i := range 100 {
+			enabled, err := s.Evaluate(ctxFor("tenant-1", fmt.Sprintf("user-%d", i)))
+			require.NoError(t, err)
+			results[enabled]++
+		}
+		assert.Positive(t, results[true])
+		assert.Positive(t, results[false])
+	})
+
+	t.Run("invalid bucket_by", func(t *testing.T) {
+		t.Parallel()
+
+		s := feature.NewTargetedStrategy(feature.TargetCriteria{Percentage: &percentage, BucketBy: "org"})
+		_, err := s.Evaluate(ctxFor("tenant-1", "user-1"))
+		assert.ErrorIs(t, err, feature.ErrInvalidStrategy)
+	})
+}
+
+func TestScheduleStrategy(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2026, 11, 27, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2026, 12, 1, 0, 0, 0, 0, time.UTC)
+	clockAt := func(now time.Time) feature.ScheduleStrategyOption {
+		return feature.WithClock(func() time.Time { return now })
+	}
+
+	tests := []struct {
+		name     string
+		startsAt *time.Time
+		endsAt   *time.Time
+		now      time.Time
+		want     bool
+	}{
+		{name: "before window", startsAt: &start, endsAt: &end, now: start.Add(-time.Second), want: false},
+		{name: "at start", startsAt: &start, endsAt: &end, now: start, want: true},
+		{name: "inside window", startsAt: &start, endsAt: &end, now: start.Add(48 * time.Hour), want: true},
+		{name: "at end", startsAt: &start, endsAt: &end, now: end, want: false},
+		{name: "after window", startsAt: &start, endsAt: &end, now: end.Add(time.Hour), want: false},
+		{name: "open end", startsAt: &start, now: end.Add(24 * time.Hour), want: true},
+		{name: "open start", endsAt: &end, now: start.Add(-24 * time.Hour), want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			strategy := feature.NewScheduleStrategy(tt.startsAt, tt.endsAt, clockAt(tt.now))
+			enabled, err := strategy.Evaluate(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, enabled)
+		})
+	}
+
+	t.Run("stores bounds in UTC", func(t *testing.T) {
+		t.Parallel()
+
+		newYork, err := time.LoadLocation("America/New_York")
+		require.NoError(t, err)
+
+		// Midnight in New York is 05:00 UTC
+		localStart := time.Date(2026, 11, 27, 0, 0, 0, 0, newYork)
+		strategy := feature.NewScheduleStrategy(&localStart, nil,
+			clockAt(time.Date(2026, 11, 27, 4, 59, 0, 0, time.UTC)),
+		).(*feature.ScheduleStrategy)
+
+		assert.Equal(t, time.UTC, strategy.StartsAt.Location())
+		assert.True(t, strategy.StartsAt.Equal(localStart))
+
+		enabled, err := strategy.Evaluate(context.Background())
+		require.NoError(t, err)
+		assert.False(t, enabled)
+	})
+
+	t.Run("invalid window", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := feature.NewScheduleStrategy(nil, nil).Evaluate(context.Background())
+		assert.ErrorIs(t, err, feature.ErrInvalidStrategy)
+
+		_, err = feature.NewScheduleStrategy(&end, &start).Evaluate(context.Background())
+		assert.ErrorIs(t, err, feature.ErrInvalidStrategy)
+	})
+
+	t.Run("composes with other strategies", func(t *testing.T) {
+		t.Parallel()
+
+		promo := feature.NewAndStrategy(
+			feature.NewScheduleStrategy(&start, &end, clockAt(start.Add(time.Hour))),
+			feature.NewEnvironmentStrategy([]string{"production"}, feature.WithEnvironmentExtractor(testEnvironmentExtractor)),
+		)
+
+		ctx := context.WithValue(context.Background(), testEnvironmentKey{}, "production")
+		enabled, err := promo.Evaluate(ctx)
+		require.NoError(t, err)
+		assert.True(t, enabled)
+
+		ctx = context.WithValue(context.Background(), testEnvironmentKey{}, "staging")
+		enabled, err = promo.Evaluate(ctx)
+		require.NoError(t, err)
+		assert.False(t, enabled)
+	})
+}
+
 func TestEnvironmentStrategy(t *testing.T) {
 	t.Parallel()
 	t.Run("EmptyEnvironments", func(t *testing.T) {
@@ -0,0 +1,125 @@
+package feature
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Strategy type identifiers used in the JSON representation of strategies.
+const (
+	strategyTypeAlways      = "always"
+	strategyTypeTargeted    = "targeted"
+	strategyTypeEnvironment = "environment"
+	strategyTypeSchedule    = "schedule"
+	strategyTypeComposite   = "composite"
+)
+
+// strategyJSON is the serialized form of a built-in strategy. The type field
+// selects the concrete strategy, so nested composite strategies round-trip.
+type strategyJSON struct {
+	Type         string          `json:"type"`
+	Value        *bool           `json:"value,omitempty"`
+	Criteria     *TargetCriteria `json:"criteria,omitempty"`
+	Environments []string        `json:"environments,omitempty"`
+	StartsAt     *time.Time      `json:"starts_at,omitempty"`
+	EndsAt       *time.Time      `json:"ends_at,omitempty"`
+	Operator     string          `json:"operator,omitempty"`
+	Strategies   []*strategyJSON `json:"strategies,omitempty"`
+}
+
+// strategyExtractors holds the context extractors attached to decoded strategies.
+// Extractors are functions, so they can't be serialized and are supplied by the importer.
+type strategyExtractors struct {
+	userID      UserIDExtractor
+	userGroups  UserGroupsExtractor
+	tenantID    TenantIDExtractor
+	environment EnvironmentExtractor
+}
+
+// encodeStrategy converts a built-in strategy to its serialized form.
+// Custom strategy implementations can't be serialized and return ErrInvalidStrategy.
+func encodeStrategy(s Strategy) (*strategyJSON, error) {
+	switch s := s.(type) {
+	case nil:
+		return nil, nil
+	case *AlwaysStrategy:
+		value := s.Value
+		return &strategyJSON{Type: strategyTypeAlways, Value: &value}, nil
+	case *TargetedStrategy:
+		criteria := s.Criteria
+		return &strategyJSON{Type: strategyTypeTargeted, Criteria: &criteria}, nil
+	case *EnvironmentStrategy:
+		return &strategyJSON{Type: strategyTypeEnvironment, Environments: s.EnabledEnvironments}, nil
+	case *ScheduleStrategy:
+		return &strategyJSON{Type: strategyTypeSchedule, StartsAt: utcTime(s.StartsAt), EndsAt: utcTime(s.EndsAt)}, nil
+	case *CompositeStrategy:
+		out := &strategyJSON{Type: strategyTypeComposite, Operator: s.Operator}
+		for _, child := range s.Strategies {
+			if child == nil {
+				return nil, errors.Join(ErrInvalidStrategy, errors.New("composite strategy contains nil strategy"))
+			}
+			encoded, err := encodeStrategy(child)
+			if err != nil {
+				return nil, err
+			}
+			out.Strategies = append(out.Strategies, encoded)
+		}
+		return out, nil
+	default:
+		return nil, errors.Join(ErrInvalidStrategy, fmt.Errorf("strategy type %T cannot be serialized", s))
+	}
+}
+
+// decodeStrategy rebuilds a strategy from its serialized form and attaches the extractors.
+func decodeStrategy(s *strategyJSON, ex strategyExtractors) (Strategy, error) {
+	if s == nil {
+		return nil, nil
+	}
+
+	switch s.Type {
+	case strategyTypeAlways:
+		if s.Value == nil {
+			return nil, errors.Join(ErrInvalidStrategy, errors.New("always strategy requires a value"))
+		}
+		return &AlwaysStrategy{Value: *s.Value}, nil
+	case strategyTypeTargeted:
+		if s.Criteria == nil {
+			return nil, errors.Join(ErrInvalidStrategy, errors.New("targeted strategy requires criteria"))
+		}
+		return &TargetedStrategy{
+			Criteria:            *s.Criteria,
+			userIDExtractor:     ex.userID,
+			userGroupsExtractor: ex.userGroups,
+			tenantIDExtractor:   ex.tenantID,
+		}, nil
+	case strategyTypeEnvironment:
+		return &EnvironmentStrategy{
+			EnabledEnvironments:  s.Environments,
+			environmentExtractor: ex.environment,
+		}, nil
+	case strategyTypeSchedule:
+		if s.StartsAt == nil && s.EndsAt == nil {
+			return nil, errors.Join(ErrInvalidStrategy, errors.New("schedule strategy requires starts_at or ends_at"))
+		}
+		return NewScheduleStrategy(s.StartsAt, s.EndsAt), nil
+	case strategyTypeComposite:
+		if s.Operator != "and" && s.Operator != "or" {
+			return nil, errors.Join(ErrInvalidStrategy, errors.New("composite operator must be 'and' or 'or'"))
+		}
+		out := &CompositeStrategy{Operator: s.Operator}
+		for _, child := range s.Strategies {
+			decoded, err := decodeStrategy(child, ex)
+			if err != nil {
+				return nil, err
+			}
+			if decoded == nil {
+				return nil, errors.Join(ErrInvalidStrategy, errors.New("composite strategy contains nil strategy"))
+			}
+			out.Strategies = append(out.Strategies, decoded)
+		}
+		return out, nil
+	default:
+		return nil, errors.Join(ErrInvalidStrategy, fmt.Errorf("unknown strategy type %q", s.Type))
+	}
+}
@@ -0,0 +1,202 @@
+package feature
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+)
+
+// ChangeAction identifies the kind of flag mutation.
+type ChangeAction string
+
+const (
+	ChangeCreated ChangeAction = "created"
+	ChangeUpdated ChangeAction = "updated"
+	ChangeDeleted ChangeAction = "deleted"
+)
+
+// FlagChange describes a single mutation of a flag.
+// Before is nil for creations and After is nil for deletions.
+type FlagChange struct {
+	Flag      string       `json:"flag"`
+	Action    ChangeAction `json:"action"`
+	Before    *Flag        `json:"before,omitempty"`
+	After     *Flag        `json:"after,omitempty"`
+	Actor     string       `json:"actor,omitempty"`
+	Timestamp time.Time    `json:"timestamp"`
+}
+
+// ActorExtractor returns the identity of the caller making a change, e.g. an admin user ID.
+type ActorExtractor func(ctx context.Context) string
+
+// TrackingOption configures a change-tracking provider.
+type TrackingOption func(*trackingProvider)
+
+// WithChangeRecorder registers a callback fired after every successful
+// CreateFlag, UpdateFlag and DeleteFlag. It runs synchronously on the
+// caller's goroutine, so it should not block.
+func WithChangeRecorder(fn func(FlagChange)) TrackingOption {
+	return func(p *trackingProvider) {
+		if fn != nil {
+			p.recorders = append(p.recorders, fn)
+		}
+	}
+}
+
+// WithActorExtractor sets how the caller identity is read from the context.
+func WithActorExtractor(extractor ActorExtractor) TrackingOption {
+	return func(p *trackingProvider) {
+		p.actor = extractor
+	}
+}
+
+// trackingProvider decorates a Provider, reporting every management call to recorders.
+type trackingProvider struct {
+	Provider
+	recorders []func(FlagChange)
+	actor     ActorExtractor
+	mu        sync.Mutex
+}
+
+// NewTrackingProvider wraps a provider so that flag mutations are reported to
+// the configured change recorders with before/after state and caller identity.
+// Evaluation methods are passed through untouched.
+//
+// Mutations made through the returned provider are serialized so Before always
+// reflects the state the change was applied to. Changes made directly on the
+// wrapped provider bypass tracking.
+func NewTrackingProvider(provider Provider, opts ...TrackingOption) Provider {
+	if provider == nil {
+		panic("feature: provider is required")
+	}
+
+	p := &trackingProvider{
+		Provider: provider,
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+func (p *trackingProvider) CreateFlag(ctx context.Context, flag *Flag) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err := p.Provider.CreateFlag(ctx, flag); err != nil {
+		return err
+	}
+
+	p.record(ctx, ChangeCreated, flag.Name, nil, p.current(ctx, flag.Name, flag))
+	return nil
+}
+
+func (p *trackingProvider) UpdateFlag(ctx context.Context, flag *Flag) error {
+	if flag == nil {
+		return p.Provider.UpdateFlag(ctx, flag)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	before, _ := p.Provider.GetFlag(ctx, flag.Name)
+	if err := p.Provider.UpdateFlag(ctx, flag); err != nil {
+		return err
+	}
+
+	p.record(ctx, ChangeUpdated, flag.Name, before, p.current(ctx, flag.Name, flag))
+	return nil
+}
+
+func (p *trackingProvider) DeleteFlag(ctx context.Context, flagName string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	before, _ := p.Provider.GetFlag(ctx, flagName)
+	if err := p.Provider.DeleteFlag(ctx, flagName); err != nil {
+		return err
+	}
+
+	p.record(ctx, ChangeDeleted, flagName, before, nil)
+	return nil
+}
+
+// current reads the stored flag so After carries provider-assigned fields
+// such as timestamps, falling back to the input if the read fails.
+func (p *trackingProvider) current(ctx context.Context, name string, fallback *Flag) *Flag {
+	if flag, err := p.Provider.GetFlag(ctx, name); err == nil {
+		return flag
+	}
+	return cloneFlag(fallback)
+}
+
+func (p *trackingProvider) record(ctx context.Context, action ChangeAction, name string, before, after *Flag) {
+	if len(p.recorders) == 0 {
+		return
+	}
+
+	change := FlagChange{
+		Flag:      name,
+		Action:    action,
+		Before:    before,
+		After:     after,
+		Timestamp: time.Now(),
+	}
+	if p.actor != nil {
+		change.Actor = p.actor(ctx)
+	}
+
+	for _, fn := range p.recorders {
+		fn(change)
+	}
+}
+
+// MemoryChangeLog keeps flag changes in memory and answers history queries.
+// Use its Record method as a change recorder. Suitable for tests and
+// single-instance deployments; persist changes elsewhere for compliance.
+type MemoryChangeLog struct {
+	changes map[string][]FlagChange
+	mu      sync.RWMutex
+}
+
+func NewMemoryChangeLog() *MemoryChangeLog {
+	return &MemoryChangeLog{
+		changes: make(map[string][]FlagChange),
+	}
+}
+
+// Record stores a change. Its signature matches WithChangeRecorder.
+func (l *MemoryChangeLog) Record(change FlagChange) {
+	change.Before = cloneFlag(change.Before)
+	change.After = cloneFlag(change.After)
+
+	l.mu.Lock()
+	l.changes[change.Flag] = append(l.changes[change.Flag], change)
+	l.mu.Unlock()
+}
+
+// FlagHistory returns all recorded changes of a flag, oldest first.
+func (l *MemoryChangeLog) FlagHistory(ctx context.Context, flagName string) ([]FlagChange, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	history := make([]FlagChange, 0, len(l.changes[flagName]))
+	for _, change := range l.changes[flagName] {
+		change.Before = cloneFlag(change.Before)
+		change.After = cloneFlag(change.After)
+		history = append(history, change)
+	}
+	return history, nil
+}
+
+func cloneFlag(flag *Flag) *Flag {
+	if flag == nil {
+		return nil
+	}
+	flagCopy := *flag
+	if flag.Tags != nil {
+		flagCopy.Tags = slices.Clone(flag.Tags)
+	}
+	return &flagCopy
+}
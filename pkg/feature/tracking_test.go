@@ -0,0 +1,150 @@
+package feature_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dmitrymomot/saaskit/pkg/feature"
+)
+
+type testActorKey struct{}
+
+func testActorExtractor(ctx context.Context) string {
+	actor, _ := ctx.Value(testActorKey{}).(string)
+	return actor
+}
+
+func TestTrackingProvider(t *testing.T) {
+	t.Parallel()
+
+	newTracked := func(t *testing.T) (feature.Provider, *feature.MemoryChangeLog) {
+		t.Helper()
+		memory, err := feature.NewMemoryProvider()
+		require.NoError(t, err)
+		changeLog := feature.NewMemoryChangeLog()
+		provider := feature.NewTrackingProvider(memory,
+			feature.WithChangeRecorder(changeLog.Record),
+			feature.WithActorExtractor(testActorExtractor),
+		)
+		return provider, changeLog
+	}
+
+	t.Run("records create, update and delete with actor", func(t *testing.T) {
+		t.Parallel()
+		provider, changeLog := newTracked(t)
+		ctx := context.WithValue(context.Background(), testActorKey{}, "admin-1")
+
+		require.NoError(t, provider.CreateFlag(ctx, &feature.Flag{Name: "beta", Enabled: false}))
+		require.NoError(t, provider.UpdateFlag(ctx, &feature.Flag{Name: "beta", Enabled: true}))
+		require.NoError(t, provider.DeleteFlag(ctx, "beta"))
+
+		history, err := changeLog.FlagHistory(ctx, "beta")
+		require.NoError(t, err)
+		require.Len(t, history, 3)
+
+		assert.Equal(t, feature.ChangeCreated, history[0].Action)
+		assert.Nil(t, history[0].Before)
+		require.NotNil(t, history[0].After)
+		assert.False(t, history[0].After.Enabled)
+		assert.False(t, history[0].After.CreatedAt.IsZero())
+
+		assert.Equal(t, feature.ChangeUpdated, history[1].Action)
+		require.NotNil(t, history[1].Before)
+		require.NotNil(t, history[1].After)
+		assert.False(t, history[1].Before.Enabled)
+		assert.True(t, history[1].After.Enabled)
+
+		assert.Equal(t, feature.ChangeDeleted, history[2].Action)
+		require.NotNil(t, history[2].Before)
+		assert.True(t, history[2].Before.Enabled)
+		assert.Nil(t, history[2].After)
+
+		for _, change := range history {
+			assert.Equal(t, "beta", change.Flag)
+			assert.Equal(t, "admin-1", change.Actor)
+			assert.False(t, change.Timestamp.IsZero())
+		}
+	})
+
+	t.Run("failed mutations are not recorded", func(t *testing.T) {
+		t.Parallel()
+		provider, changeLog := newTracked(t)
+		ctx := context.Background()
+
+		require.ErrorIs(t, provider.UpdateFlag(ctx, &feature.Flag{Name: "missing"}), feature.ErrFlagNotFound)
+		require.ErrorIs(t, provider.DeleteFlag(ctx, "missing"), feature.ErrFlagNotFound)
+		require.ErrorIs(t, provider.CreateFlag(ctx, nil), feature.ErrInvalidFlag)
+
+		history, err := changeLog.FlagHistory(ctx, "missing")
+		require.NoError(t, err)
+		assert.Empty(t, history)
+	})
+
+	t.Run("evaluation passes through", func(t *testing.T) {
+		t.Parallel()
+		provider, _ := newTracked(t)
+		ctx := context.Background()
+
+		require.NoError(t, provider.CreateFlag(ctx, &feature.Flag{Name: "on", Enabled: true}))
+		enabled, err := provider.IsEnabled(ctx, "on")
+		require.NoError(t, err)
+		assert.True(t, enabled)
+	})
+
+	t.Run("history is isolated from recorded flags", func(t *testing.T) {
+		t.Parallel()
+		provider, changeLog := newTracked(t)
+		ctx := context.Background()
+
+		require.NoError(t, provider.CreateFlag(ctx, &feature.Flag{Name: "tags", Tags: []string{"a"}}))
+
+		history, err := changeLog.FlagHistory(ctx, "tags")
+		require.NoError(t, err)
+		history[0].After.Tags[0] = "mutated"
+
+		history, err = changeLog.FlagHistory(ctx, "tags")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"a"}, history[0].After.Tags)
+	})
+
+	t.Run("concurrent updates record consistent before state", func(t *testing.T) {
+		t.Parallel()
+		var (
+			mu      sync.Mutex
+			changes []feature.FlagChange
+		)
+		memory, err := feature.NewMemoryProvider(&feature.Flag{Name: "counter"})
+		require.NoError(t, err)
+		provider := feature.NewTrackingProvider(memory, feature.WithChangeRecorder(func(c feature.FlagChange) {
+			mu.Lock()
+			changes = append(changes, c)
+			mu.Unlock()
+		}))
+
+		var wg sync.WaitGroup
+		for i := range 20 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_ = provider.UpdateFlag(context.Background(), &feature.Flag{Name: "counter", Enabled: i%2 == 0, Description: string(rune('a' + i))})
+			}()
+		}
+		wg.Wait()
+
+		require.Len(t, changes, 20)
+		for i := 1; i < len(changes); i++ {
+			assert.Equal(t, changes[i-1].After.Description, changes[i].Before.Description)
+		}
+	})
+
+	t.Run("nil provider panics", func(t *testing.T) {
+		t.Parallel()
+		assert.Panics(t, func() {
+			feature.NewTrackingProvider(nil)
+		})
+	})
+}
@@ -21,6 +21,11 @@
 //   - Middleware – standard `net/http` middleware that injects the
 //     fingerprint into the request context so that downstream handlers
 //     can retrieve it via `GetFingerprintFromContext`.
+//   - StrictMiddleware – binds fingerprint components to a session via a
+//     `FingerprintStore` and rejects requests from another network or
+//     whose fingerprint drifts below a similarity threshold from the
+//     baseline (see `Similarity`), protecting against session hijacking
+//     without failing on benign changes.
 //   - Context helpers – `SetFingerprintToContext` /
 //     `GetFingerprintFromContext` allow manual manipulation when the
 //     middleware is not used.
@@ -54,6 +59,15 @@
 //
 //	fp := fingerprint.GetFingerprintFromContext(r.Context())
 //
+// Rejecting requests whose fingerprint changes mid-session:
+//
+//	store := fingerprint.NewMemoryStore()
+//	strict := fingerprint.StrictMiddleware(store, nil,
+//	    fingerprint.WithSessionCookie("sid"),
+//	    fingerprint.WithThreshold(0.85),
+//	)
+//	http.Handle("/", strict(yourHandler))
+//
 // # Error Handling
 //
 // All functions are side-effect-free and do not return errors; the hash
@@ -14,13 +14,50 @@ import (
 // It combines User-Agent, Accept headers, client IP, and header order
 // to create a 32-character hex string identifying the device/browser.
 func Generate(r *http.Request) string {
+	return Extract(r).Hash()
+}
+
+// Validate compares the current request fingerprint with a stored fingerprint.
+// Returns true if they match, false otherwise.
+func Validate(r *http.Request, sessionFingerprint string) bool {
+	currentFingerprint := Generate(r)
+	return currentFingerprint == sessionFingerprint
+}
+
+// Components holds the raw request attributes a fingerprint is derived from.
+// Keeping them around (instead of only the hash) allows fuzzy comparison
+// via Similarity, so benign drift doesn't look like a different device.
+type Components struct {
+	UserAgent      string `json:"user_agent,omitempty"`
+	AcceptLanguage string `json:"accept_language,omitempty"`
+	AcceptEncoding string `json:"accept_encoding,omitempty"`
+	Accept         string `json:"accept,omitempty"`
+	IP             string `json:"ip,omitempty"`
+	HeaderOrder    string `json:"header_order,omitempty"`
+}
+
+// Extract collects fingerprint components from the HTTP request.
+func Extract(r *http.Request) Components {
+	return Components{
+		UserAgent:      r.UserAgent(),
+		AcceptLanguage: r.Header.Get("Accept-Language"),
+		AcceptEncoding: r.Header.Get("Accept-Encoding"),
+		Accept:         r.Header.Get("Accept"),
+		IP:             clientip.GetIP(r),
+		HeaderOrder:    getHeaderOrder(r),
+	}
+}
+
+// Hash returns the 32-character hex fingerprint for the components.
+// The result is identical to Generate for the request they were extracted from.
+func (c Components) Hash() string {
 	components := []string{
-		r.UserAgent(),
-		r.Header.Get("Accept-Language"),
-		r.Header.Get("Accept-Encoding"),
-		r.Header.Get("Accept"),
-		clientip.GetIP(r),
-		getHeaderOrder(r),
+		c.UserAgent,
+		c.AcceptLanguage,
+		c.AcceptEncoding,
+		c.Accept,
+		c.IP,
+		c.HeaderOrder,
 	}
 
 	// Filter out empty components
@@ -39,13 +76,6 @@ func Generate(r *http.Request) string {
 	return hex.EncodeToString(hash[:16])
 }
 
-// Validate compares the current request fingerprint with a stored fingerprint.
-// Returns true if they match, false otherwise.
-func Validate(r *http.Request, sessionFingerprint string) bool {
-	currentFingerprint := Generate(r)
-	return currentFingerprint == sessionFingerprint
-}
-
 // getHeaderOrder creates a fingerprint based on the order of HTTP headers.
 // Different browsers and clients send headers in different orders,
 // making this a useful distinguishing characteristic.
@@ -0,0 +1,100 @@
+package fingerprint
+
+import (
+	"net"
+	"strings"
+)
+
+// Component weights used by Similarity. User-Agent and IP dominate because
+// they are the strongest device signals; Accept* headers change on browser
+// updates or locale switches and therefore carry less weight.
+const (
+	weightUserAgent      = 0.35
+	weightIP             = 0.20
+	weightAcceptLanguage = 0.15
+	weightAcceptEncoding = 0.10
+	weightAccept         = 0.10
+	weightHeaderOrder    = 0.10
+)
+
+// Similarity returns a score in [0, 1] describing how alike two sets of
+// fingerprint components are. Identical components score 1.
+//
+// Header values are compared by token overlap, so a browser minor version
+// bump or an added language only lowers the score slightly. IP addresses
+// in the same network (/24 for IPv4, /64 for IPv6) get partial credit to
+// tolerate DHCP and mobile network churn.
+func Similarity(a, b Components) float64 {
+	score := weightUserAgent*tokenSimilarity(a.UserAgent, b.UserAgent) +
+		weightIP*ipSimilarity(a.IP, b.IP) +
+		weightAcceptLanguage*tokenSimilarity(a.AcceptLanguage, b.AcceptLanguage) +
+		weightAcceptEncoding*tokenSimilarity(a.AcceptEncoding, b.AcceptEncoding) +
+		weightAccept*tokenSimilarity(a.Accept, b.Accept) +
+		weightHeaderOrder*tokenSimilarity(a.HeaderOrder, b.HeaderOrder)
+
+	// Guard against floating point drift above 1 for identical inputs
+	return min(score, 1)
+}
+
+// tokenSimilarity computes the Jaccard index of the tokens in both strings.
+func tokenSimilarity(a, b string) float64 {
+	if a == b {
+		return 1
+	}
+
+	ta, tb := tokenize(a), tokenize(b)
+	if len(ta) == 0 || len(tb) == 0 {
+		return 0
+	}
+
+	intersection := 0
+	for tok := range ta {
+		if _, ok := tb[tok]; ok {
+			intersection++
+		}
+	}
+
+	union := len(ta) + len(tb) - intersection
+	return float64(intersection) / float64(union)
+}
+
+func tokenize(s string) map[string]struct{} {
+	fields := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		switch r {
+		case ' ', ',', ';', '(', ')', '/':
+			return true
+		}
+		return false
+	})
+
+	tokens := make(map[string]struct{}, len(fields))
+	for _, f := range fields {
+		tokens[f] = struct{}{}
+	}
+	return tokens
+}
+
+func ipSimilarity(a, b string) float64 {
+	if a == b {
+		return 1
+	}
+
+	ipA, ipB := net.ParseIP(a), net.ParseIP(b)
+	if ipA == nil || ipB == nil {
+		return 0
+	}
+
+	if v4a, v4b := ipA.To4(), ipB.To4(); v4a != nil && v4b != nil {
+		mask := net.CIDRMask(24, 32)
+		if v4a.Mask(mask).Equal(v4b.Mask(mask)) {
+			return 0.5
+		}
+		return 0
+	}
+
+	mask := net.CIDRMask(64, 128)
+	if ipA.Mask(mask).Equal(ipB.Mask(mask)) {
+		return 0.5
+	}
+	return 0
+}
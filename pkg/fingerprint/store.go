@@ -0,0 +1,62 @@
+package fingerprint
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrNotFound is returned by a FingerprintStore when no fingerprint is bound to the session.
+var ErrNotFound = errors.New("fingerprint not found")
+
+// FingerprintStore persists the fingerprint components bound to a session.
+// Implementations must be safe for concurrent use.
+type FingerprintStore interface {
+	// Get returns the components bound to the session or ErrNotFound.
+	Get(ctx context.Context, sessionID string) (Components, error)
+
+	// Set binds components to the session, replacing any previous value.
+	Set(ctx context.Context, sessionID string, c Components) error
+}
+
+// MemoryStore is an in-memory FingerprintStore suitable for tests and
+// single-instance deployments. Entries live until explicitly deleted.
+type MemoryStore struct {
+	mu    sync.RWMutex
+	items map[string]Components
+}
+
+// NewMemoryStore creates an empty in-memory fingerprint store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{items: make(map[string]Components)}
+}
+
+// Get returns the components bound to the session.
+func (s *MemoryStore) Get(_ context.Context, sessionID string) (Components, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	c, ok := s.items[sessionID]
+	if !ok {
+		return Components{}, ErrNotFound
+	}
+	return c, nil
+}
+
+// Set binds components to the session.
+func (s *MemoryStore) Set(_ context.Context, sessionID string, c Components) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.items[sessionID] = c
+	return nil
+}
+
+// Delete removes the fingerprint bound to the session, e.g. on logout.
+func (s *MemoryStore) Delete(_ context.Context, sessionID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.items, sessionID)
+	return nil
+}
@@ -0,0 +1,131 @@
+package fingerprint
+
+import (
+	"errors"
+	"net/http"
+)
+
+// DefaultSimilarityThreshold is the minimum Similarity score for a request
+// to be considered the same device as the one bound to the session.
+const DefaultSimilarityThreshold = 0.8
+
+// SessionIDFunc extracts the session identifier from the request.
+// An empty result means the request has no session and is passed through.
+type SessionIDFunc func(r *http.Request) string
+
+type strictConfig struct {
+	threshold float64
+	sessionID SessionIDFunc
+}
+
+// StrictOption configures StrictMiddleware.
+type StrictOption func(*strictConfig)
+
+// WithThreshold sets the minimum similarity score required to accept a request.
+// Values outside (0, 1] are ignored.
+func WithThreshold(threshold float64) StrictOption {
+	return func(c *strictConfig) {
+		if threshold > 0 && threshold <= 1 {
+			c.threshold = threshold
+		}
+	}
+}
+
+// WithSessionIDFunc sets how the session identifier is resolved from the request.
+func WithSessionIDFunc(fn SessionIDFunc) StrictOption {
+	return func(c *strictConfig) {
+		if fn != nil {
+			c.sessionID = fn
+		}
+	}
+}
+
+// WithSessionCookie resolves the session identifier from the named cookie.
+func WithSessionCookie(name string) StrictOption {
+	return WithSessionIDFunc(cookieSessionID(name))
+}
+
+// StrictMiddleware binds a fingerprint to each session and rejects requests
+// whose fingerprint drifts too far from the bound one.
+//
+// On the first request of a session the fingerprint components are stored as
+// the session's baseline. Subsequent requests are compared with the baseline
+// using Similarity and must come from the same network (/24 for IPv4, /64 for
+// IPv6); otherwise onMismatch is invoked (401 Unauthorized when nil). The
+// baseline is never updated, so an attacker can't move it step by step with
+// requests that each drift just below the threshold. The current fingerprint
+// is added to the request context.
+//
+// By default the session identifier is read from the "sid" cookie; use
+// WithSessionCookie or WithSessionIDFunc to change it.
+func StrictMiddleware(store FingerprintStore, onMismatch http.HandlerFunc, opts ...StrictOption) func(http.Handler) http.Handler {
+	if store == nil {
+		panic("fingerprint: store is required")
+	}
+
+	cfg := &strictConfig{
+		threshold: DefaultSimilarityThreshold,
+		sessionID: cookieSessionID("sid"),
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	if onMismatch == nil {
+		onMismatch = func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		}
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			current := Extract(r)
+			ctx := SetFingerprintToContext(r.Context(), current.Hash())
+			r = r.WithContext(ctx)
+
+			sessionID := cfg.sessionID(r)
+			if sessionID == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			bound, err := store.Get(ctx, sessionID)
+			switch {
+			case errors.Is(err, ErrNotFound):
+				if err := store.Set(ctx, sessionID, current); err != nil {
+					http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+					return
+				}
+				next.ServeHTTP(w, r)
+				return
+			case err != nil:
+				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+				return
+			}
+
+			if bound == current {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			// Headers are attacker-controlled, so matching them must not
+			// compensate for a request from a different network
+			if ipSimilarity(bound.IP, current.IP) == 0 || Similarity(bound, current) < cfg.threshold {
+				onMismatch(w, r)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func cookieSessionID(name string) SessionIDFunc {
+	return func(r *http.Request) string {
+		c, err := r.Cookie(name)
+		if err != nil {
+			return ""
+		}
+		return c.Value
+	}
+}
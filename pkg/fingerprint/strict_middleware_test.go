@@ -0,0 +1,231 @@
+package fingerprint_test
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dmitrymomot/saaskit/pkg/fingerprint"
+)
+
+var chromeHeaders = map[string]string{
+	"User-Agent":      "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+	"Accept":          "text/html,application/xhtml+xml",
+	"Accept-Language": "en-US,en;q=0.9",
+	"Accept-Encoding": "gzip, deflate, br",
+}
+
+func withSession(req *http.Request, sid string) *http.Request {
+	req.AddCookie(&http.Cookie{Name: "sid", Value: sid})
+	return req
+}
+
+func cloneHeaders(src map[string]string, overrides map[string]string) map[string]string {
+	dst := maps.Clone(src)
+	maps.Copy(dst, overrides)
+	return dst
+}
+
+type failingStore struct{}
+
+func (failingStore) Get(context.Context, string) (fingerprint.Components, error) {
+	return fingerprint.Components{}, errors.New("boom")
+}
+
+func (failingStore) Set(context.Context, string, fingerprint.Components) error {
+	return errors.New("boom")
+}
+
+func TestSimilarity(t *testing.T) {
+	t.Parallel()
+
+	base := fingerprint.Extract(createTestRequest(chromeHeaders, "192.168.1.100:1234"))
+
+	t.Run("identical components score 1", func(t *testing.T) {
+		t.Parallel()
+		assert.InDelta(t, 1.0, fingerprint.Similarity(base, base), 0.0001)
+	})
+
+	t.Run("browser update keeps high score", func(t *testing.T) {
+		t.Parallel()
+		updated := fingerprint.Extract(createTestRequest(cloneHeaders(chromeHeaders, map[string]string{
+			"User-Agent": "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/121.0.0.0 Safari/537.36",
+		}), "192.168.1.100:1234"))
+
+		score := fingerprint.Similarity(base, updated)
+		assert.Less(t, score, 1.0)
+		assert.Greater(t, score, fingerprint.DefaultSimilarityThreshold)
+	})
+
+	t.Run("same subnet gets partial IP credit", func(t *testing.T) {
+		t.Parallel()
+		sameNet := fingerprint.Extract(createTestRequest(chromeHeaders, "192.168.1.200:1234"))
+		otherNet := fingerprint.Extract(createTestRequest(chromeHeaders, "10.0.0.1:1234"))
+
+		assert.Greater(t, fingerprint.Similarity(base, sameNet), fingerprint.Similarity(base, otherNet))
+	})
+
+	t.Run("different device scores below threshold", func(t *testing.T) {
+		t.Parallel()
+		other := fingerprint.Extract(createTestRequest(map[string]string{
+			"User-Agent":      "curl/8.4.0",
+			"Accept":          "*/*",
+			"Accept-Language": "de-DE",
+		}, "10.0.0.1:1234"))
+
+		assert.Less(t, fingerprint.Similarity(base, other), fingerprint.DefaultSimilarityThreshold)
+	})
+}
+
+func TestComponentsHash(t *testing.T) {
+	t.Parallel()
+
+	req := createTestRequest(chromeHeaders, "192.168.1.100:1234")
+	assert.Equal(t, fingerprint.Generate(req), fingerprint.Extract(req).Hash())
+}
+
+func TestStrictMiddleware(t *testing.T) {
+	t.Parallel()
+
+	okHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.NotEmpty(t, fingerprint.GetFingerprintFromContext(r.Context()))
+		w.WriteHeader(http.StatusOK)
+	})
+
+	serve := func(h http.Handler, req *http.Request) int {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	t.Run("binds fingerprint on first request", func(t *testing.T) {
+		t.Parallel()
+		store := fingerprint.NewMemoryStore()
+		h := fingerprint.StrictMiddleware(store, nil)(okHandler)
+
+		req := withSession(createTestRequest(chromeHeaders, "192.168.1.100:1234"), "s1")
+		assert.Equal(t, http.StatusOK, serve(h, req))
+
+		bound, err := store.Get(context.Background(), "s1")
+		require.NoError(t, err)
+		assert.Equal(t, fingerprint.Extract(req), bound)
+	})
+
+	t.Run("passes through requests without session", func(t *testing.T) {
+		t.Parallel()
+		store := fingerprint.NewMemoryStore()
+		h := fingerprint.StrictMiddleware(store, nil)(okHandler)
+
+		assert.Equal(t, http.StatusOK, serve(h, createTestRequest(chromeHeaders, "192.168.1.100:1234")))
+	})
+
+	t.Run("rejects different device with default 401", func(t *testing.T) {
+		t.Parallel()
+		store := fingerprint.NewMemoryStore()
+		h := fingerprint.StrictMiddleware(store, nil)(okHandler)
+
+		serve(h, withSession(createTestRequest(chromeHeaders, "192.168.1.100:1234"), "s1"))
+
+		hijacker := withSession(createTestRequest(map[string]string{
+			"User-Agent": "curl/8.4.0",
+			"Accept":     "*/*",
+		}, "10.0.0.1:1234"), "s1")
+		assert.Equal(t, http.StatusUnauthorized, serve(h, hijacker))
+	})
+
+	t.Run("tolerates benign drift and keeps baseline", func(t *testing.T) {
+		t.Parallel()
+		store := fingerprint.NewMemoryStore()
+		h := fingerprint.StrictMiddleware(store, nil)(okHandler)
+
+		first := withSession(createTestRequest(chromeHeaders, "192.168.1.100:1234"), "s1")
+		serve(h, first)
+
+		drifted := withSession(createTestRequest(cloneHeaders(chromeHeaders, map[string]string{
+			"Accept-Language": "en-US,en;q=0.9,fr;q=0.8",
+		}), "192.168.1.101:1234"), "s1")
+		assert.Equal(t, http.StatusOK, serve(h, drifted))
+
+		bound, err := store.Get(context.Background(), "s1")
+		require.NoError(t, err)
+		assert.Equal(t, fingerprint.Extract(first), bound)
+	})
+
+	t.Run("rejects same headers from a different network", func(t *testing.T) {
+		t.Parallel()
+		store := fingerprint.NewMemoryStore()
+		h := fingerprint.StrictMiddleware(store, nil)(okHandler)
+
+		serve(h, withSession(createTestRequest(chromeHeaders, "192.168.1.100:1234"), "s1"))
+
+		stolen := withSession(createTestRequest(chromeHeaders, "203.0.113.7:1234"), "s1")
+		assert.Equal(t, http.StatusUnauthorized, serve(h, stolen))
+	})
+
+	t.Run("stepwise drift cannot move the baseline", func(t *testing.T) {
+		t.Parallel()
+		store := fingerprint.NewMemoryStore()
+		h := fingerprint.StrictMiddleware(store, nil)(okHandler)
+
+		serve(h, withSession(createTestRequest(chromeHeaders, "192.168.1.100:1234"), "s1"))
+
+		// Each step alone stays above the threshold relative to the previous one
+		step1 := cloneHeaders(chromeHeaders, map[string]string{"Accept-Language": "de-DE"})
+		assert.Equal(t, http.StatusOK, serve(h, withSession(createTestRequest(step1, "192.168.1.100:1234"), "s1")))
+
+		step2 := cloneHeaders(step1, map[string]string{"Accept-Encoding": "identity"})
+		req1 := withSession(createTestRequest(step1, "192.168.1.100:1234"), "s1")
+		req2 := withSession(createTestRequest(step2, "192.168.1.100:1234"), "s1")
+		require.GreaterOrEqual(t, fingerprint.Similarity(fingerprint.Extract(req1), fingerprint.Extract(req2)), fingerprint.DefaultSimilarityThreshold)
+
+		assert.Equal(t, http.StatusUnauthorized, serve(h, req2))
+	})
+
+	t.Run("custom mismatch handler and threshold", func(t *testing.T) {
+		t.Parallel()
+		store := fingerprint.NewMemoryStore()
+		onMismatch := func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusForbidden)
+		}
+		h := fingerprint.StrictMiddleware(store, onMismatch, fingerprint.WithThreshold(1))(okHandler)
+
+		serve(h, withSession(createTestRequest(chromeHeaders, "192.168.1.100:1234"), "s1"))
+
+		drifted := withSession(createTestRequest(chromeHeaders, "192.168.1.101:1234"), "s1")
+		assert.Equal(t, http.StatusForbidden, serve(h, drifted))
+	})
+
+	t.Run("custom session id func", func(t *testing.T) {
+		t.Parallel()
+		store := fingerprint.NewMemoryStore()
+		h := fingerprint.StrictMiddleware(store, nil, fingerprint.WithSessionIDFunc(func(r *http.Request) string {
+			return r.Header.Get("X-Session-ID")
+		}))(okHandler)
+
+		req := createTestRequest(chromeHeaders, "192.168.1.100:1234")
+		req.Header.Set("X-Session-ID", "abc")
+		assert.Equal(t, http.StatusOK, serve(h, req))
+
+		_, err := store.Get(context.Background(), "abc")
+		require.NoError(t, err)
+	})
+
+	t.Run("store failure returns 500", func(t *testing.T) {
+		t.Parallel()
+		h := fingerprint.StrictMiddleware(failingStore{}, nil)(okHandler)
+
+		req := withSession(createTestRequest(chromeHeaders, "192.168.1.100:1234"), "s1")
+		assert.Equal(t, http.StatusInternalServerError, serve(h, req))
+	})
+
+	t.Run("panics without store", func(t *testing.T) {
+		t.Parallel()
+		assert.Panics(t, func() { fingerprint.StrictMiddleware(nil, nil) })
+	})
+}
@@ -46,6 +46,21 @@
 //	msg := translator.T("en", "welcome", "name", "John")
 //	// msg == "Welcome, John!"
 //
+// # Fallback Chains
+//
+// Regional variants can override only a few strings and inherit the rest. With
+// WithLocaleFallback a missing key in "pt-BR" is looked up in "pt", then in the default
+// language; WithFallbackChain sets the chain for a language explicitly:
+//
+//	translator, err := i18n.NewTranslator(ctx, adapter,
+//		i18n.WithLocaleFallback(true),
+//		i18n.WithFallbackChain("es-MX", "es-419", "es"),
+//	)
+//
+// The resolution order is: requested language, its chain, the default language, and
+// finally the key itself when WithFallbackToKey is enabled. Without either option only
+// the requested language is searched.
+//
 // # HTTP Middleware
 //
 // The middleware automatically determines the request language (Accept-Language header by
@@ -58,6 +73,15 @@
 //
 //	http.Handle("/", i18n.Middleware(translator, nil)(handler))
 //
+// # Client-side Dumps
+//
+// ExportJSON returns every translation of a language. To keep browser bundles small,
+// DumpJSON returns only a namespace (plural forms included), with missing keys filled
+// in from the language's fallback chain:
+//
+//	data, err := translator.DumpJSON("en", "checkout.")
+//	// {"checkout":{"title":"Checkout","items":{"one":"...","other":"..."}}}
+//
 // # Error Handling
 //
 // Custom error values such as ErrLanguageNotSupported allow fine-grained error checks, e.g.:
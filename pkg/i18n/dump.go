@@ -0,0 +1,131 @@
+package i18n
+
+import (
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+)
+
+// DumpJSON returns the translations of a language restricted to a namespace
+// prefix as JSON, so client-side code can load only the keys a page needs.
+//
+// The prefix is a dot-separated namespace such as "checkout." or "checkout.cart";
+// the trailing dot is optional and an empty prefix dumps the whole language.
+// Matching happens on whole key segments, so plural forms nested under a base
+// key (.zero, .one, .other) are always shipped together. The result keeps the
+// full key path, letting the client resolve the same keys as the server:
+//
+//	data, err := translator.DumpJSON("en", "checkout.")
+//	// {"checkout":{"items":{"one":"%{count} item","other":"%{count} items"},...}}
+//
+// Keys missing in lang are filled in from its fallback chain (see WithFallbackChain
+// and WithLocaleFallback), so every key has the value T would return for it.
+// The merged translations are cached per resolved chain of loaded languages, which
+// keeps the cache bounded no matter which lang and prefix callers pass.
+// A prefix that matches nothing yields "{}".
+func (t *Translator) DumpJSON(lang, prefix string) ([]byte, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	merged, ok := t.resolvedTranslations(lang)
+	if !ok {
+		return nil, &ErrLanguageNotSupported{Lang: lang}
+	}
+
+	data, err := json.Marshal(filterNamespace(merged, strings.Trim(prefix, ".")))
+	if err != nil {
+		return nil, errors.Join(ErrFailedToMarshalJSON, err)
+	}
+	return data, nil
+}
+
+// resolvedTranslations merges the loaded languages of lang's fallback chain,
+// more specific languages winning. Must be called with t.mu held.
+func (t *Translator) resolvedTranslations(lang string) (map[string]any, bool) {
+	var chain []string
+	for _, l := range t.fallbackChain(lang) {
+		if _, ok := t.translations[l]; ok {
+			chain = append(chain, l)
+		}
+	}
+	if len(chain) == 0 {
+		return nil, false
+	}
+	key := strings.Join(chain, ",")
+
+	t.dumpMu.Lock()
+	defer t.dumpMu.Unlock()
+
+	if merged, ok := t.dumpCache[key]; ok {
+		return merged, true
+	}
+
+	merged := make(map[string]any)
+	for _, l := range slices.Backward(chain) {
+		mergeTranslations(merged, t.translations[l])
+	}
+	t.dumpCache[key] = merged
+	return merged, true
+}
+
+// mergeTranslations deep-merges src into dst, src values winning on conflicts.
+// Nested maps are copied, so the loaded translations are never modified.
+func mergeTranslations(dst, src map[string]any) {
+	for k, v := range src {
+		child, ok := toStringMap(v)
+		if !ok {
+			dst[k] = v
+			continue
+		}
+		existing, ok := dst[k].(map[string]any)
+		if !ok {
+			existing = make(map[string]any, len(child))
+			dst[k] = existing
+		}
+		mergeTranslations(existing, child)
+	}
+}
+
+// filterNamespace returns the subtree under prefix wrapped in its parent keys.
+func filterNamespace(m map[string]any, prefix string) map[string]any {
+	if prefix == "" {
+		return m
+	}
+
+	part, rest, _ := strings.Cut(prefix, ".")
+	val, ok := m[part]
+	if !ok {
+		return map[string]any{}
+	}
+	if rest == "" {
+		return map[string]any{part: val}
+	}
+
+	child, ok := toStringMap(val)
+	if !ok {
+		return map[string]any{}
+	}
+	filtered := filterNamespace(child, rest)
+	if len(filtered) == 0 {
+		return filtered
+	}
+	return map[string]any{part: filtered}
+}
+
+// toStringMap normalizes nested maps, since YAML decoders may produce map[any]any.
+func toStringMap(v any) (map[string]any, bool) {
+	switch m := v.(type) {
+	case map[string]any:
+		return m, true
+	case map[any]any:
+		out := make(map[string]any, len(m))
+		for k, v := range m {
+			if ks, ok := k.(string); ok {
+				out[ks] = v
+			}
+		}
+		return out, true
+	}
+	return nil, false
+}
@@ -0,0 +1,178 @@
+package i18n_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dmitrymomot/saaskit/pkg/i18n"
+)
+
+func TestTranslatorDumpJSON(t *testing.T) {
+	t.Parallel()
+
+	adapter := &i18n.MapAdapter{
+		Data: map[string]map[string]any{
+			"en": {
+				"hello": "Hello",
+				"checkout": map[string]any{
+					"title": "Checkout",
+					"items": map[string]any{
+						"zero":  "No items",
+						"one":   "%{count} item",
+						"other": "%{count} items",
+					},
+					"cart": map[string]any{
+						"empty": "Your cart is empty",
+					},
+				},
+				"checkoutx": map[string]any{
+					"title": "Not a checkout key",
+				},
+			},
+		},
+	}
+
+	translator, err := i18n.NewTranslator(context.Background(), adapter)
+	require.NoError(t, err)
+
+	decode := func(t *testing.T, data []byte) map[string]any {
+		t.Helper()
+		var out map[string]any
+		require.NoError(t, json.Unmarshal(data, &out))
+		return out
+	}
+
+	t.Run("filters by namespace and keeps plural forms", func(t *testing.T) {
+		t.Parallel()
+		data, err := translator.DumpJSON("en", "checkout.")
+		require.NoError(t, err)
+
+		out := decode(t, data)
+		require.Len(t, out, 1)
+		checkout := out["checkout"].(map[string]any)
+		assert.Equal(t, "Checkout", checkout["title"])
+		assert.Equal(t, map[string]any{
+			"zero":  "No items",
+			"one":   "%{count} item",
+			"other": "%{count} items",
+		}, checkout["items"])
+	})
+
+	t.Run("nested namespace keeps full key path", func(t *testing.T) {
+		t.Parallel()
+		data, err := translator.DumpJSON("en", "checkout.cart")
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"checkout":{"cart":{"empty":"Your cart is empty"}}}`, string(data))
+	})
+
+	t.Run("plural base key selects all forms", func(t *testing.T) {
+		t.Parallel()
+		data, err := translator.DumpJSON("en", "checkout.items")
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"checkout":{"items":{"zero":"No items","one":"%{count} item","other":"%{count} items"}}}`, string(data))
+	})
+
+	t.Run("empty prefix dumps everything", func(t *testing.T) {
+		t.Parallel()
+		data, err := translator.DumpJSON("en", "")
+		require.NoError(t, err)
+		assert.Len(t, decode(t, data), 3)
+	})
+
+	t.Run("unknown namespace yields empty object", func(t *testing.T) {
+		t.Parallel()
+		data, err := translator.DumpJSON("en", "billing.")
+		require.NoError(t, err)
+		assert.JSONEq(t, `{}`, string(data))
+
+		data, err = translator.DumpJSON("en", "hello.world")
+		require.NoError(t, err)
+		assert.JSONEq(t, `{}`, string(data))
+	})
+
+	t.Run("cached result is not shared", func(t *testing.T) {
+		t.Parallel()
+		first, err := translator.DumpJSON("en", "checkout.cart")
+		require.NoError(t, err)
+		first[0] = 'X'
+
+		second, err := translator.DumpJSON("en", "checkout.cart")
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"checkout":{"cart":{"empty":"Your cart is empty"}}}`, string(second))
+	})
+
+	t.Run("language not supported", func(t *testing.T) {
+		t.Parallel()
+		_, err := translator.DumpJSON("es", "checkout.")
+		require.Error(t, err)
+		assert.IsType(t, &i18n.ErrLanguageNotSupported{}, err)
+	})
+}
+
+func TestTranslatorDumpJSON_Fallback(t *testing.T) {
+	t.Parallel()
+
+	adapter := &i18n.MapAdapter{
+		Data: map[string]map[string]any{
+			"en": {
+				"checkout": map[string]any{
+					"title": "Checkout",
+					"pay":   "Pay now",
+					"items": map[string]any{
+						"one":   "%{count} item",
+						"other": "%{count} items",
+					},
+				},
+			},
+			"pt": {
+				"checkout": map[string]any{
+					"title": "Finalizar compra",
+				},
+			},
+			"pt-BR": {
+				"checkout": map[string]any{
+					"pay": "Pagar agora",
+				},
+			},
+		},
+	}
+
+	translator, err := i18n.NewTranslator(context.Background(), adapter, i18n.WithLocaleFallback(true))
+	require.NoError(t, err)
+
+	t.Run("missing keys resolve through the chain", func(t *testing.T) {
+		t.Parallel()
+		data, err := translator.DumpJSON("pt-BR", "checkout")
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"checkout":{
+			"title":"Finalizar compra",
+			"pay":"Pagar agora",
+			"items":{"one":"%{count} item","other":"%{count} items"}
+		}}`, string(data))
+
+		// Every dumped key matches what the server renders
+		assert.Equal(t, "Finalizar compra", translator.T("pt-BR", "checkout.title"))
+		assert.Equal(t, "Pagar agora", translator.T("pt-BR", "checkout.pay"))
+	})
+
+	t.Run("unloaded locale resolves to its parent", func(t *testing.T) {
+		t.Parallel()
+		data, err := translator.DumpJSON("pt-PT", "checkout.title")
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"checkout":{"title":"Finalizar compra"}}`, string(data))
+	})
+
+	t.Run("loaded translations are not modified", func(t *testing.T) {
+		t.Parallel()
+		_, err := translator.DumpJSON("pt-BR", "")
+		require.NoError(t, err)
+
+		data, err := translator.DumpJSON("pt", "checkout.pay")
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"checkout":{"pay":"Pay now"}}`, string(data))
+	})
+}
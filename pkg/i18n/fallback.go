@@ -0,0 +1,74 @@
+package i18n
+
+import "strings"
+
+// fallbackChain returns the languages to search for lang, in resolution order:
+//
+//  1. lang itself
+//  2. the explicit chain set with WithFallbackChain, or, with WithLocaleFallback,
+//     the parent locales derived by dropping subtags ("pt-BR" -> "pt")
+//  3. the default language
+//
+// Without a configured chain only lang is searched. Duplicates are removed.
+func (t *Translator) fallbackChain(lang string) []string {
+	explicit, hasExplicit := t.fallbackChains[lang]
+	if !hasExplicit && !t.localeFallback {
+		return []string{lang}
+	}
+
+	chain := []string{lang}
+	add := func(l string) {
+		for _, existing := range chain {
+			if existing == l {
+				return
+			}
+		}
+		chain = append(chain, l)
+	}
+
+	if hasExplicit {
+		for _, l := range explicit {
+			if l != "" {
+				add(l)
+			}
+		}
+	} else {
+		for _, l := range parentLocales(lang) {
+			add(l)
+		}
+	}
+	add(t.defaultLang)
+
+	return chain
+}
+
+// parentLocales returns the locales obtained by dropping trailing subtags,
+// most specific first: "zh-Hant-TW" -> ["zh-Hant", "zh"]. Both "-" and "_" are separators.
+func parentLocales(lang string) []string {
+	var parents []string
+	for {
+		i := strings.LastIndexAny(lang, "-_")
+		if i <= 0 {
+			return parents
+		}
+		lang = lang[:i]
+		parents = append(parents, lang)
+	}
+}
+
+// lookup finds key in the first language of the fallback chain that has it.
+// supported reports whether any language in the chain has translations at all,
+// which distinguishes unsupported languages from missing keys in logs.
+func (t *Translator) lookup(lang, key string) (val any, found, supported bool) {
+	for _, l := range t.fallbackChain(lang) {
+		langMap, ok := t.translations[l]
+		if !ok {
+			continue
+		}
+		supported = true
+		if val, ok := t.getTranslation(langMap, key); ok {
+			return val, true, true
+		}
+	}
+	return nil, false, supported
+}
@@ -0,0 +1,157 @@
+package i18n_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dmitrymomot/saaskit/pkg/i18n"
+)
+
+func newFallbackTranslator(t *testing.T, opts ...i18n.Option) *i18n.Translator {
+	t.Helper()
+
+	adapter := &i18n.MapAdapter{
+		Data: map[string]map[string]any{
+			"en": {
+				"hello":    "Hello",
+				"goodbye":  "Goodbye",
+				"checkout": "Checkout",
+				"items": map[string]any{
+					"one":   "%{count} item",
+					"other": "%{count} items",
+				},
+				"datetime": map[string]any{
+					"hours": map[string]any{
+						"one":   "%{count} hour",
+						"other": "%{count} hours",
+					},
+				},
+			},
+			"pt": {
+				"hello":   "Olá",
+				"goodbye": "Adeus",
+				"items": map[string]any{
+					"one":   "%{count} item",
+					"other": "%{count} itens",
+				},
+			},
+			"pt-BR": {
+				"goodbye": "Tchau",
+			},
+			"zh": {
+				"hello": "你好",
+			},
+		},
+	}
+
+	translator, err := i18n.NewTranslator(context.Background(), adapter, opts...)
+	require.NoError(t, err)
+	return translator
+}
+
+func TestTranslatorLocaleFallback(t *testing.T) {
+	t.Parallel()
+
+	translator := newFallbackTranslator(t, i18n.WithLocaleFallback(true))
+
+	tests := []struct {
+		name     string
+		lang     string
+		key      string
+		expected string
+	}{
+		{name: "regional translation", lang: "pt-BR", key: "goodbye", expected: "Tchau"},
+		{name: "falls back to base language", lang: "pt-BR", key: "hello", expected: "Olá"},
+		{name: "falls back to default language", lang: "pt-BR", key: "checkout", expected: "Checkout"},
+		{name: "key is the final step", lang: "pt-BR", key: "missing", expected: "missing"},
+		{name: "unsupported region of supported base", lang: "pt-PT", key: "goodbye", expected: "Adeus"},
+		{name: "multiple subtags", lang: "zh-Hant-TW", key: "hello", expected: "你好"},
+		{name: "underscore separator", lang: "pt_BR", key: "hello", expected: "Olá"},
+		{name: "unsupported language uses default", lang: "es", key: "hello", expected: "Hello"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.expected, translator.T(tt.lang, tt.key))
+		})
+	}
+
+	t.Run("pluralization", func(t *testing.T) {
+		t.Parallel()
+		assert.Equal(t, "3 itens", translator.N("pt-BR", "items", 3))
+		assert.Equal(t, "1 item", translator.N("pt-BR", "items", 1))
+	})
+
+	t.Run("duration uses the chain", func(t *testing.T) {
+		t.Parallel()
+		assert.Equal(t, "2 hours", translator.Duration("pt-BR", 2*time.Hour))
+	})
+
+	t.Run("explicit default value comes after the chain", func(t *testing.T) {
+		t.Parallel()
+		assert.Equal(t, "Olá", translator.Td("pt-BR", "hello", "Hi"))
+		assert.Equal(t, "Hi", translator.Td("pt-BR", "missing", "Hi"))
+	})
+
+	t.Run("has translation uses the chain", func(t *testing.T) {
+		t.Parallel()
+		assert.True(t, translator.HasTranslation("pt-BR", "hello"))
+		assert.False(t, translator.HasTranslation("pt-BR", "missing"))
+	})
+}
+
+func TestTranslatorFallbackChain(t *testing.T) {
+	t.Parallel()
+
+	t.Run("explicit chain", func(t *testing.T) {
+		t.Parallel()
+
+		translator := newFallbackTranslator(t,
+			i18n.WithDefaultLanguage("en"),
+			i18n.WithFallbackChain("pt-BR", "pt"),
+		)
+
+		assert.Equal(t, "Olá", translator.T("pt-BR", "hello"))
+		assert.Equal(t, "Checkout", translator.T("pt-BR", "checkout"))
+
+		// Languages without a chain keep exact lookups
+		assert.Equal(t, "goodbye", translator.T("zh", "goodbye"))
+	})
+
+	t.Run("explicit chain replaces the derived one", func(t *testing.T) {
+		t.Parallel()
+
+		translator := newFallbackTranslator(t,
+			i18n.WithLocaleFallback(true),
+			i18n.WithDefaultLanguage("zh"),
+			i18n.WithFallbackChain("pt-BR", "en"),
+		)
+
+		assert.Equal(t, "Hello", translator.T("pt-BR", "hello"))
+		assert.Equal(t, "Olá", translator.T("pt-PT", "hello"))
+	})
+
+	t.Run("fallback to key disabled", func(t *testing.T) {
+		t.Parallel()
+
+		translator := newFallbackTranslator(t,
+			i18n.WithLocaleFallback(true),
+			i18n.WithFallbackToKey(false),
+		)
+
+		assert.Equal(t, "Checkout", translator.T("pt-BR", "checkout"))
+		assert.Equal(t, "", translator.T("pt-BR", "missing"))
+	})
+
+	t.Run("disabled by default", func(t *testing.T) {
+		t.Parallel()
+
+		translator := newFallbackTranslator(t)
+		assert.Equal(t, "hello", translator.T("pt-BR", "hello"))
+	})
+}
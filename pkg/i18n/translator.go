@@ -32,10 +32,15 @@ type Translator struct {
 	translations   map[string]map[string]any
 	defaultLang    string
 	fallbackToKey  bool
+	fallbackChains map[string][]string
+	localeFallback bool
 	missingLogMode bool
 	logger         *slog.Logger
 	mu             sync.RWMutex
 	adapter        TranslationAdapter
+
+	dumpMu    sync.Mutex
+	dumpCache map[string]map[string]any // merged translations keyed by resolved fallback chain
 }
 
 // NewTranslator creates a new Translator instance with the given adapter and options.
@@ -50,6 +55,7 @@ func NewTranslator(ctx context.Context, adapter TranslationAdapter, options ...O
 		missingLogMode: false,
 		logger:         slog.New(slog.NewTextHandler(io.Discard, nil)), // Nope-logger by default
 		adapter:        adapter,
+		dumpCache:      make(map[string]map[string]any),
 	}
 
 	// Apply options
@@ -109,6 +115,11 @@ func (t *Translator) SupportedLanguages() []string {
 	return t.supportedLanguages()
 }
 
+// DefaultLanguage returns the language used when no other language is requested.
+func (t *Translator) DefaultLanguage() string {
+	return t.defaultLang
+}
+
 // getTranslation traverses a nested map using dot-separated keys.
 // For example, key "datetime.days.other" will traverse m["datetime"] then ["days"] then ["other"].
 func (t *Translator) getTranslation(m map[string]any, key string) (any, bool) {
@@ -148,18 +159,14 @@ func (t *Translator) getTranslation(m map[string]any, key string) (any, bool) {
 	return nil, false
 }
 
-// HasTranslation checks if a translation exists for the given language and key.
+// HasTranslation reports whether T would find key for lang, searching the
+// language's fallback chain the same way.
 func (t *Translator) HasTranslation(lang, key string) bool {
 	t.mu.RLock()
 	defer t.mu.RUnlock()
 
-	langMap, ok := t.translations[lang]
-	if !ok {
-		return false
-	}
-
-	_, ok = t.getTranslation(langMap, key)
-	return ok
+	_, found, _ := t.lookup(lang, key)
+	return found
 }
 
 // buildParams converts a slice of strings (expected as key, value, key, value, …)
@@ -199,8 +206,9 @@ func (t *Translator) namedSprintf(tmpl string, params map[string]string) string
 // It supports formatting with additional arguments provided as key-value pairs.
 // For example: translator.T("en", "welcome", "name", "John") will substitute "%{name}" in the template.
 //
-// If the requested translation is not found and FallbackToKey is true, the function returns
-// the key as a fallback. Otherwise, it returns an empty string and logs the error if
+// A missing key is looked up along the language's fallback chain (see WithFallbackChain
+// and WithLocaleFallback). If it's not found in any of them and FallbackToKey is true,
+// the function returns the key as a fallback. Otherwise, it returns an empty string and logs the error if
 // missingLogMode is enabled.
 //
 // Example:
@@ -216,9 +224,9 @@ func (t *Translator) T(lang, key string, args ...string) string {
 	t.mu.RLock()
 	defer t.mu.RUnlock()
 
-	// Check if the language is supported
-	langMap, ok := t.translations[lang]
-	if !ok {
+	// Get the translation from the first language in the fallback chain that has it
+	val, found, supported := t.lookup(lang, key)
+	if !supported {
 		if t.missingLogMode {
 			t.logger.Warn("Language not supported", "lang", lang, "key", key)
 		}
@@ -227,10 +235,7 @@ func (t *Translator) T(lang, key string, args ...string) string {
 		}
 		return ""
 	}
-
-	// Get the translation
-	val, ok := t.getTranslation(langMap, key)
-	if !ok {
+	if !found {
 		if t.missingLogMode {
 			t.logger.Warn("Translation not found", "lang", lang, "key", key)
 		}
@@ -279,6 +284,7 @@ func (t *Translator) T(lang, key string, args ...string) string {
 // - n>1: use .other (general plural form)
 //
 // This covers most European languages while remaining simple enough for rapid i18n.
+// If a language has no form for the key, the next language of its fallback chain is tried.
 //
 // Example:
 //
@@ -299,9 +305,24 @@ func (t *Translator) N(lang, key string, n int, args ...string) string {
 	t.mu.RLock()
 	defer t.mu.RUnlock()
 
-	// Check if the language is supported
-	langMap, ok := t.translations[lang]
-	if !ok {
+	// Find the plural form in the first language of the fallback chain that has one
+	var (
+		val       any
+		found     bool
+		supported bool
+	)
+	for _, l := range t.fallbackChain(lang) {
+		langMap, ok := t.translations[l]
+		if !ok {
+			continue
+		}
+		supported = true
+		if val, found = t.pluralValue(langMap, key, n); found {
+			break
+		}
+	}
+
+	if !supported {
 		if t.missingLogMode {
 			t.logger.Warn("Language not supported", "lang", lang, "key", key, "n", n)
 		}
@@ -310,40 +331,6 @@ func (t *Translator) N(lang, key string, n int, args ...string) string {
 		}
 		return ""
 	}
-
-	// Try to get the translation with appropriate plural form
-	var val any
-	var found bool
-
-	// Try pluralization forms in CLDR-compatible order
-	if n == 0 {
-		val, found = t.getTranslation(langMap, key+".zero")
-		if found {
-			goto translate
-		}
-		// Many languages don't distinguish zero, fallback to other
-		val, found = t.getTranslation(langMap, key+".other")
-		if found {
-			goto translate
-		}
-	}
-
-	if n == 1 {
-		val, found = t.getTranslation(langMap, key+".one")
-		if found {
-			goto translate
-		}
-	}
-
-	if n != 0 && n != 1 {
-		val, found = t.getTranslation(langMap, key+".other")
-		if found {
-			goto translate
-		}
-	}
-
-	// Try the key itself (might be a string with embedded pluralization logic)
-	val, found = t.getTranslation(langMap, key)
 	if !found {
 		if t.missingLogMode {
 			t.logger.Warn("Pluralization not found", "lang", lang, "key", key, "n", n)
@@ -354,7 +341,6 @@ func (t *Translator) N(lang, key string, n int, args ...string) string {
 		return ""
 	}
 
-translate:
 	switch v := val.(type) {
 	case string:
 		// Auto-inject count parameter for convenience
@@ -384,6 +370,32 @@ translate:
 	}
 }
 
+// pluralValue picks the plural form of key for n within a single language,
+// trying forms in CLDR-compatible order before the key itself.
+func (t *Translator) pluralValue(langMap map[string]any, key string, n int) (any, bool) {
+	switch n {
+	case 0:
+		if val, ok := t.getTranslation(langMap, key+".zero"); ok {
+			return val, true
+		}
+		// Many languages don't distinguish zero, fallback to other
+		if val, ok := t.getTranslation(langMap, key+".other"); ok {
+			return val, true
+		}
+	case 1:
+		if val, ok := t.getTranslation(langMap, key+".one"); ok {
+			return val, true
+		}
+	default:
+		if val, ok := t.getTranslation(langMap, key+".other"); ok {
+			return val, true
+		}
+	}
+
+	// Try the key itself (might be a string with embedded pluralization logic)
+	return t.getTranslation(langMap, key)
+}
+
 // Duration formats time.Duration with UX-optimized rounding for human readability.
 // Aggressive rounding reduces cognitive load by presenting meaningful units rather than
 // precise values. The thresholds balance accuracy with usability for typical UI contexts.
@@ -568,23 +580,21 @@ func (t *Translator) ExportJSON(lang string) (string, error) {
 }
 
 // Td translates a key with a default fallback if not found
-// Provides an explicit fallback rather than using the key itself
+// Provides an explicit fallback rather than using the key itself,
+// after the language's fallback chain has been searched
 func (t *Translator) Td(lang, key, defaultValue string, args ...string) string {
 	t.mu.RLock()
 	defer t.mu.RUnlock()
 
-	// Check if the language is supported
-	langMap, ok := t.translations[lang]
-	if !ok {
+	// Try to get the translation from the fallback chain
+	val, found, supported := t.lookup(lang, key)
+	if !supported {
 		if t.missingLogMode {
 			t.logger.Warn("Language not supported", "lang", lang, "key", key)
 		}
 		return t.sprintf(defaultValue, args)
 	}
-
-	// Try to get the translation
-	val, ok := t.getTranslation(langMap, key)
-	if !ok {
+	if !found {
 		if t.missingLogMode {
 			t.logger.Warn("Translation not found", "lang", lang, "key", key)
 		}
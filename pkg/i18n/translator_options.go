@@ -52,3 +52,28 @@ func WithNoLogging() Option {
 		t.missingLogMode = false
 	}
 }
+
+// WithFallbackChain sets the languages tried, in order, when a key is missing in lang.
+// For example WithFallbackChain("pt-BR", "pt") looks up missing pt-BR keys in pt,
+// then in the default language. An explicit chain replaces the one derived by
+// WithLocaleFallback for that language.
+func WithFallbackChain(lang string, fallbacks ...string) Option {
+	return func(t *Translator) {
+		if lang == "" {
+			return
+		}
+		if t.fallbackChains == nil {
+			t.fallbackChains = make(map[string][]string)
+		}
+		t.fallbackChains[lang] = fallbacks
+	}
+}
+
+// WithLocaleFallback derives fallback chains from the locale itself: a missing key in
+// "zh-Hant-TW" is looked up in "zh-Hant", then "zh", then the default language.
+// Disabled by default, so a missing key resolves to the key (see WithFallbackToKey).
+func WithLocaleFallback(enabled bool) Option {
+	return func(t *Translator) {
+		t.localeFallback = enabled
+	}
+}
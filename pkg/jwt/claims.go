@@ -0,0 +1,189 @@
+package jwt
+
+import (
+	"fmt"
+	"maps"
+	"time"
+)
+
+// Claims is a flat set of JWT claims as produced by ClaimsBuilder.
+// It can also be passed to Parse to decode a token without a dedicated struct.
+type Claims map[string]any
+
+// reservedClaims are set through dedicated builder methods only.
+var reservedClaims = map[string]string{
+	"sub": "Subject",
+	"iss": "Issuer",
+	"aud": "Audience",
+	"jti": "ID",
+	"exp": "ExpiresIn or ExpiresAt",
+	"nbf": "NotBefore",
+	"iat": "Build",
+}
+
+// Valid validates the temporal claims against current time, like StandardClaims.Valid.
+func (c Claims) Valid() error {
+	now := time.Now().Unix()
+
+	if exp, ok := c.unix("exp"); ok && now > exp {
+		return ErrExpiredToken
+	}
+
+	if nbf, ok := c.unix("nbf"); ok && now < nbf {
+		return ErrInvalidToken
+	}
+
+	return nil
+}
+
+// unix reads a numeric claim, which is float64 after JSON decoding and int64 when built.
+func (c Claims) unix(name string) (int64, bool) {
+	switch v := c[name].(type) {
+	case int64:
+		return v, v > 0
+	case float64:
+		return int64(v), v > 0
+	default:
+		return 0, false
+	}
+}
+
+// ClaimsBuilder builds Claims fluently:
+//
+//	claims, err := jwt.NewClaims().
+//		Subject(userID).
+//		Audience("invite").
+//		ExpiresIn(24 * time.Hour).
+//		Claim("role", "admin").
+//		Build()
+//
+// Errors are collected and returned by Build, so calls can be chained freely.
+type ClaimsBuilder struct {
+	claims    Claims
+	expiresIn time.Duration
+	expiresAt time.Time
+	notBefore time.Time
+	err       error
+}
+
+// NewClaims starts building a new set of claims.
+func NewClaims() *ClaimsBuilder {
+	return &ClaimsBuilder{claims: make(Claims)}
+}
+
+// Subject sets the "sub" claim.
+func (b *ClaimsBuilder) Subject(sub string) *ClaimsBuilder {
+	return b.set("sub", sub)
+}
+
+// Issuer sets the "iss" claim.
+func (b *ClaimsBuilder) Issuer(iss string) *ClaimsBuilder {
+	return b.set("iss", iss)
+}
+
+// Audience sets the "aud" claim.
+func (b *ClaimsBuilder) Audience(aud string) *ClaimsBuilder {
+	return b.set("aud", aud)
+}
+
+// ID sets the "jti" claim.
+func (b *ClaimsBuilder) ID(id string) *ClaimsBuilder {
+	return b.set("jti", id)
+}
+
+// ExpiresIn sets "exp" relative to the issue time computed by Build.
+func (b *ClaimsBuilder) ExpiresIn(d time.Duration) *ClaimsBuilder {
+	if d <= 0 {
+		b.fail(fmt.Errorf("%w: expiration must be positive, got %v", ErrInvalidClaims, d))
+		return b
+	}
+	b.expiresIn = d
+	b.expiresAt = time.Time{}
+	return b
+}
+
+// ExpiresAt sets "exp" to an absolute time.
+func (b *ClaimsBuilder) ExpiresAt(t time.Time) *ClaimsBuilder {
+	b.expiresAt = t
+	b.expiresIn = 0
+	return b
+}
+
+// NotBefore sets "nbf", the time before which the token must be rejected.
+func (b *ClaimsBuilder) NotBefore(t time.Time) *ClaimsBuilder {
+	b.notBefore = t
+	return b
+}
+
+// Claim sets a custom claim. Registered claim names must be set with their
+// dedicated methods instead.
+func (b *ClaimsBuilder) Claim(name string, value any) *ClaimsBuilder {
+	if method, ok := reservedClaims[name]; ok {
+		b.fail(fmt.Errorf("%w: claim %q is registered, use %s", ErrInvalidClaims, name, method))
+		return b
+	}
+	if name == "" {
+		b.fail(fmt.Errorf("%w: claim name is empty", ErrInvalidClaims))
+		return b
+	}
+	b.claims[name] = value
+	return b
+}
+
+// Build sets "iat" to now, computes "exp" and validates the result.
+// Subject and expiration are required, and the expiration must be in the
+// future and after "nbf". The builder can be reused to build more claims.
+func (b *ClaimsBuilder) Build() (Claims, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+
+	now := time.Now()
+	claims := make(Claims, len(b.claims)+3)
+	maps.Copy(claims, b.claims)
+
+	if sub, _ := claims["sub"].(string); sub == "" {
+		return nil, fmt.Errorf("%w: subject is required", ErrInvalidClaims)
+	}
+
+	var exp time.Time
+	switch {
+	case b.expiresIn > 0:
+		exp = now.Add(b.expiresIn)
+	case !b.expiresAt.IsZero():
+		exp = b.expiresAt
+	default:
+		return nil, fmt.Errorf("%w: expiration is required", ErrInvalidClaims)
+	}
+	if !exp.After(now) {
+		return nil, fmt.Errorf("%w: expiration must be in the future", ErrInvalidClaims)
+	}
+
+	if !b.notBefore.IsZero() {
+		if !exp.After(b.notBefore) {
+			return nil, fmt.Errorf("%w: expiration must be after not-before", ErrInvalidClaims)
+		}
+		claims["nbf"] = b.notBefore.Unix()
+	}
+
+	claims["iat"] = now.Unix()
+	claims["exp"] = exp.Unix()
+
+	return claims, nil
+}
+
+func (b *ClaimsBuilder) set(name, value string) *ClaimsBuilder {
+	if value == "" {
+		delete(b.claims, name)
+		return b
+	}
+	b.claims[name] = value
+	return b
+}
+
+// fail keeps the first error so it can be reported by Build.
+func (b *ClaimsBuilder) fail(err error) {
+	if b.err == nil {
+		b.err = err
+	}
+}
@@ -0,0 +1,127 @@
+package jwt_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dmitrymomot/saaskit/pkg/jwt"
+)
+
+func TestClaimsBuilder(t *testing.T) {
+	t.Parallel()
+
+	t.Run("builds registered and custom claims", func(t *testing.T) {
+		t.Parallel()
+		before := time.Now().Unix()
+
+		claims, err := jwt.NewClaims().
+			Subject("user-1").
+			Issuer("saaskit").
+			Audience("invite").
+			ID("token-1").
+			ExpiresIn(24*time.Hour).
+			Claim("role", "admin").
+			Build()
+		require.NoError(t, err)
+
+		assert.Equal(t, "user-1", claims["sub"])
+		assert.Equal(t, "saaskit", claims["iss"])
+		assert.Equal(t, "invite", claims["aud"])
+		assert.Equal(t, "token-1", claims["jti"])
+		assert.Equal(t, "admin", claims["role"])
+
+		iat := claims["iat"].(int64)
+		assert.GreaterOrEqual(t, iat, before)
+		assert.Equal(t, iat+int64((24*time.Hour).Seconds()), claims["exp"])
+		assert.NotContains(t, claims, "nbf")
+	})
+
+	t.Run("sets not before and absolute expiration", func(t *testing.T) {
+		t.Parallel()
+		nbf := time.Now().Add(time.Hour)
+		exp := time.Now().Add(2 * time.Hour)
+
+		claims, err := jwt.NewClaims().Subject("user-1").NotBefore(nbf).ExpiresAt(exp).Build()
+		require.NoError(t, err)
+		assert.Equal(t, nbf.Unix(), claims["nbf"])
+		assert.Equal(t, exp.Unix(), claims["exp"])
+	})
+
+	t.Run("validates required fields", func(t *testing.T) {
+		t.Parallel()
+		tests := []struct {
+			name    string
+			builder *jwt.ClaimsBuilder
+		}{
+			{"missing subject", jwt.NewClaims().ExpiresIn(time.Hour)},
+			{"missing expiration", jwt.NewClaims().Subject("user-1")},
+			{"non-positive duration", jwt.NewClaims().Subject("user-1").ExpiresIn(0)},
+			{"expiration in the past", jwt.NewClaims().Subject("user-1").ExpiresAt(time.Now().Add(-time.Minute))},
+			{"expiration before not before", jwt.NewClaims().Subject("user-1").ExpiresIn(time.Hour).NotBefore(time.Now().Add(2 * time.Hour))},
+			{"registered claim via Claim", jwt.NewClaims().Subject("user-1").ExpiresIn(time.Hour).Claim("exp", 0)},
+			{"empty claim name", jwt.NewClaims().Subject("user-1").ExpiresIn(time.Hour).Claim("", 1)},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				_, err := tt.builder.Build()
+				assert.ErrorIs(t, err, jwt.ErrInvalidClaims)
+			})
+		}
+	})
+
+	t.Run("builder is reusable", func(t *testing.T) {
+		t.Parallel()
+		builder := jwt.NewClaims().Subject("user-1").ExpiresIn(time.Hour)
+
+		first, err := builder.Build()
+		require.NoError(t, err)
+		first["role"] = "mutated"
+
+		second, err := builder.Subject("user-2").Build()
+		require.NoError(t, err)
+		assert.Equal(t, "user-2", second["sub"])
+		assert.NotContains(t, second, "role")
+	})
+}
+
+func TestClaims_Parse(t *testing.T) {
+	t.Parallel()
+	svc, err := jwt.NewFromString("secret")
+	require.NoError(t, err)
+
+	t.Run("round trips through generate and parse", func(t *testing.T) {
+		t.Parallel()
+		claims, err := jwt.NewClaims().Subject("user-1").ExpiresIn(time.Hour).Claim("role", "admin").Build()
+		require.NoError(t, err)
+
+		token, err := svc.Generate(claims)
+		require.NoError(t, err)
+
+		var parsed jwt.Claims
+		require.NoError(t, svc.Parse(token, &parsed))
+		assert.Equal(t, "user-1", parsed["sub"])
+		assert.Equal(t, "admin", parsed["role"])
+	})
+
+	t.Run("rejects expired claims", func(t *testing.T) {
+		t.Parallel()
+		token, err := svc.Generate(jwt.Claims{"sub": "user-1", "exp": time.Now().Add(-time.Minute).Unix()})
+		require.NoError(t, err)
+
+		var parsed jwt.Claims
+		assert.ErrorIs(t, svc.Parse(token, &parsed), jwt.ErrExpiredToken)
+	})
+
+	t.Run("rejects not yet valid claims", func(t *testing.T) {
+		t.Parallel()
+		token, err := svc.Generate(jwt.Claims{"sub": "user-1", "nbf": time.Now().Add(time.Hour).Unix()})
+		require.NoError(t, err)
+
+		var parsed jwt.Claims
+		assert.ErrorIs(t, svc.Parse(token, &parsed), jwt.ErrInvalidToken)
+	})
+}
@@ -49,6 +49,20 @@
 // // Use middleware in an http.Handler chain.
 // http.Handle("/api", jwt.Middleware(svc)(yourHandler))
 //
+// # Claims Builder
+//
+// NewClaims builds a Claims map fluently, computing "iat" and "exp" and
+// requiring a subject and an expiration:
+//
+//	claims, err := jwt.NewClaims().
+//	    Subject(userID).
+//	    ExpiresIn(24 * time.Hour).
+//	    Claim("role", "admin").
+//	    Build()
+//
+// GenerateBatch signs many claims sets at once, encoding the header once and
+// reusing the HMAC state between tokens.
+//
 // # Error Handling
 //
 // Errors such as ErrExpiredToken or ErrInvalidSignature are returned as
@@ -114,6 +114,45 @@ func (s *Service) Generate(claims any) (string, error) {
 	return token, nil
 }
 
+// GenerateBatch signs many tokens at once, e.g. for bulk invite links.
+// The header is encoded once and the HMAC state is reused across the batch.
+// Tokens are returned in the order of claimsList; any failure aborts the batch.
+func (s *Service) GenerateBatch(claimsList []Claims) ([]string, error) {
+	headerJSON, err := json.Marshal(Header{
+		Type:      HeaderType,
+		Algorithm: HeaderAlgorithm,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal header: %w", err)
+	}
+	headerEncoded := base64URLEncode(headerJSON)
+
+	h := hmac.New(sha256.New, s.signingKey)
+	sum := make([]byte, 0, h.Size())
+	tokens := make([]string, 0, len(claimsList))
+
+	for i, claims := range claimsList {
+		if claims == nil {
+			return nil, fmt.Errorf("%w: at index %d", ErrMissingClaims, i)
+		}
+
+		claimsJSON, err := json.Marshal(claims)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal claims at index %d: %w", i, err)
+		}
+
+		payload := headerEncoded + "." + base64URLEncode(claimsJSON)
+
+		h.Reset()
+		h.Write([]byte(payload))
+		sum = h.Sum(sum[:0])
+
+		tokens = append(tokens, payload+"."+base64URLEncode(sum))
+	}
+
+	return tokens, nil
+}
+
 // Parse validates a JWT token and unmarshals its claims into the provided structure.
 // Performs cryptographic verification, algorithm validation, and temporal claim checks.
 func (s *Service) Parse(tokenString string, claims any) error {
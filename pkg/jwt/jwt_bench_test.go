@@ -242,3 +242,37 @@ func BenchmarkEnd2End(b *testing.B) {
 		}
 	})
 }
+
+// BenchmarkGenerateBatch compares batch generation with generating tokens one by one
+func BenchmarkGenerateBatch(b *testing.B) {
+	service, err := jwt.New([]byte("benchmark-secret-key"))
+	require.NoError(b, err)
+
+	claimsList := make([]jwt.Claims, 100)
+	for i := range claimsList {
+		claimsList[i], err = jwt.NewClaims().
+			Subject(fmt.Sprintf("user-%d", i)).
+			ExpiresIn(time.Hour).
+			Claim("role", "member").
+			Build()
+		require.NoError(b, err)
+	}
+
+	b.Run("Batch", func(b *testing.B) {
+		for b.Loop() {
+			if _, err := service.GenerateBatch(claimsList); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("Sequential", func(b *testing.B) {
+		for b.Loop() {
+			for _, claims := range claimsList {
+				if _, err := service.Generate(claims); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	})
+}
@@ -241,3 +241,49 @@ func TestSigningKeyDifference(t *testing.T) {
 	require.Error(t, err)
 	require.Equal(t, jwt.ErrInvalidSignature, err)
 }
+
+func TestService_GenerateBatch(t *testing.T) {
+	t.Parallel()
+	service, err := jwt.NewFromString("secret")
+	require.NoError(t, err)
+
+	t.Run("generates tokens matching Generate", func(t *testing.T) {
+		claimsList := make([]jwt.Claims, 0, 3)
+		for _, sub := range []string{"user-1", "user-2", "user-3"} {
+			claims, err := jwt.NewClaims().Subject(sub).ExpiresIn(time.Hour).Build()
+			require.NoError(t, err)
+			claimsList = append(claimsList, claims)
+		}
+
+		tokens, err := service.GenerateBatch(claimsList)
+		require.NoError(t, err)
+		require.Len(t, tokens, 3)
+
+		for i, token := range tokens {
+			single, err := service.Generate(claimsList[i])
+			require.NoError(t, err)
+			assert.Equal(t, single, token)
+
+			var parsed jwt.Claims
+			require.NoError(t, service.Parse(token, &parsed))
+			assert.Equal(t, claimsList[i]["sub"], parsed["sub"])
+		}
+	})
+
+	t.Run("empty batch", func(t *testing.T) {
+		tokens, err := service.GenerateBatch(nil)
+		require.NoError(t, err)
+		assert.Empty(t, tokens)
+	})
+
+	t.Run("nil claims abort batch", func(t *testing.T) {
+		tokens, err := service.GenerateBatch([]jwt.Claims{{"sub": "user-1"}, nil})
+		assert.ErrorIs(t, err, jwt.ErrMissingClaims)
+		assert.Nil(t, tokens)
+	})
+
+	t.Run("unmarshalable claims abort batch", func(t *testing.T) {
+		_, err := service.GenerateBatch([]jwt.Claims{{"bad": make(chan int)}})
+		assert.Error(t, err)
+	})
+}
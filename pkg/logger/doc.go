@@ -18,6 +18,11 @@
 // This design adds ~50ns overhead per log call but eliminates manual context
 // attribute extraction in application code.
 //
+// With WithContextGroup, context attributes go under their own top-level group,
+// e.g. {"ctx":{"request_id":"abc"}}, instead of into the groups opened with
+// Logger.WithGroup. The decorator then applies those groups itself when handling
+// a record, so attributes added inside a group are no longer pre-formatted.
+//
 // # Standardized Attributes
 //
 // Helper constructors in attr.go enforce consistent naming across microservices:
@@ -55,6 +60,26 @@
 //   - WithLevel – custom log level threshold
 //   - WithAttr – static attributes added to all records
 //   - WithContextExtractors/WithContextValue – dynamic context injection
+//   - WithContextGroup – nest context attributes under a top-level group
+//   - WithHandler – replace the built-in handler (e.g. with HTTPHandler)
+//
+// # Shipping Logs over HTTP
+//
+// For serverless and edge deployments without a log agent, HTTPHandler buffers
+// records and POSTs them to a collector as JSON array batches. Handle never waits
+// on the network: a bounded buffer absorbs bursts and the OverflowPolicy decides
+// which record to drop when the collector falls behind, so logging can't deadlock
+// the application. Transient failures (network errors, 429, 5xx) are retried with
+// exponential backoff.
+//
+//	h := logger.NewHTTPHandler("https://logs.example.com/ingest",
+//	    logger.WithHTTPHeader("Authorization", "Bearer "+token),
+//	    logger.WithBatchSize(200),
+//	    logger.WithOverflowPolicy(logger.DropOldest),
+//	)
+//	defer h.Close(shutdownCtx)
+//
+//	log := logger.New(logger.WithHandler(h), logger.WithContextValue("request_id", ctxKeyRequestID))
 //
 // # Nil-Safe Error Attributes
 //
@@ -82,6 +82,16 @@ func WithAttr(attrs ...slog.Attr) Option {
 	}
 }
 
+// WithHandler replaces the built-in text/JSON handler, e.g. with an HTTPHandler.
+// Static attributes and context extractors are still applied on top of it.
+func WithHandler(h slog.Handler) Option {
+	return func(c *config) {
+		if h != nil {
+			c.handler = h
+		}
+	}
+}
+
 // WithContextExtractors registers functions that inject dynamic attributes from context.
 // Nil extractors are filtered out defensively to prevent runtime panics.
 func WithContextExtractors(extractors ...ContextExtractor) Option {
@@ -111,6 +121,15 @@ func WithContextValue(name string, key any) Option {
 	}
 }
 
+// WithContextGroup nests all context-extracted attributes under the named group,
+// e.g. {"ctx":{"request_id":"abc"}}, so they can't clash with handler-supplied
+// attributes and aren't pulled into groups opened with logger.WithGroup.
+func WithContextGroup(name string) Option {
+	return func(c *config) {
+		c.contextGroup = name
+	}
+}
+
 // WithDevelopment configures development defaults.
 // Uses text format for readability and debug level for detailed diagnostics.
 func WithDevelopment(service string) Option {
@@ -190,6 +209,8 @@ type config struct {
 	attrs          []slog.Attr
 	handlerOptions *slog.HandlerOptions
 	extractors     []ContextExtractor
+	contextGroup   string
+	handler        slog.Handler
 }
 
 // defaultConfig provides production-safe defaults: JSON format with INFO level.
@@ -216,17 +237,19 @@ func New(opts ...Option) *slog.Logger {
 		handlerOpts = &slog.HandlerOptions{Level: cfg.level}
 	}
 
-	var handler slog.Handler
-	if cfg.format == FormatText {
-		handler = slog.NewTextHandler(cfg.output, handlerOpts)
-	} else {
-		handler = slog.NewJSONHandler(cfg.output, handlerOpts)
+	handler := cfg.handler
+	if handler == nil {
+		if cfg.format == FormatText {
+			handler = slog.NewTextHandler(cfg.output, handlerOpts)
+		} else {
+			handler = slog.NewJSONHandler(cfg.output, handlerOpts)
+		}
 	}
 
 	if len(cfg.attrs) > 0 {
 		handler = handler.WithAttrs(cfg.attrs)
 	}
 
-	decorated := NewLogHandlerDecorator(handler, cfg.extractors...)
+	decorated := NewGroupedLogHandlerDecorator(handler, cfg.contextGroup, cfg.extractors...)
 	return slog.New(decorated)
 }
@@ -3,6 +3,7 @@ package logger
 import (
 	"context"
 	"log/slog"
+	"slices"
 )
 
 // ContextExtractor extracts a slog attribute from context.
@@ -11,9 +12,24 @@ type ContextExtractor func(ctx context.Context) (slog.Attr, bool)
 // LogHandlerDecorator wraps a slog.Handler and injects attributes from context.
 // Uses the decorator pattern for minimal performance overhead - extraction only
 // occurs during actual logging, avoiding the cost of creating new handlers.
+//
+// With a context group, context attributes are nested under that group at the
+// top level of the record, and groups opened with WithGroup only apply to the
+// record's own and handler-supplied attributes.
 type LogHandlerDecorator struct {
 	next       slog.Handler
 	extractors []ContextExtractor
+
+	// contextGroup namespaces context attributes; empty keeps them inline.
+	contextGroup string
+	// groups opened after the context group was set, with the attributes added
+	// inside each of them. Applied in Handle so context attributes stay outside.
+	groups []handlerGroup
+}
+
+type handlerGroup struct {
+	name  string
+	attrs []slog.Attr
 }
 
 // NewLogHandlerDecorator creates a new decorated handler.
@@ -29,6 +45,16 @@ func NewLogHandlerDecorator(next slog.Handler, extractors ...ContextExtractor) s
 	return &LogHandlerDecorator{next: next, extractors: clean}
 }
 
+// NewGroupedLogHandlerDecorator creates a decorated handler that nests context
+// attributes under the group name, e.g. {"ctx":{"request_id":"abc"}}, keeping
+// them apart from handler-supplied attributes. The group is not affected by
+// WithGroup on derived loggers. An empty name behaves like NewLogHandlerDecorator.
+func NewGroupedLogHandlerDecorator(next slog.Handler, group string, extractors ...ContextExtractor) slog.Handler {
+	h := NewLogHandlerDecorator(next, extractors...).(*LogHandlerDecorator)
+	h.contextGroup = group
+	return h
+}
+
 func (h *LogHandlerDecorator) Enabled(ctx context.Context, level slog.Level) bool {
 	return h.next.Enabled(ctx, level)
 }
@@ -38,32 +64,89 @@ func (h *LogHandlerDecorator) Enabled(ctx context.Context, level slog.Level) boo
 // like request IDs are captured, rather than stale cached values.
 func (h *LogHandlerDecorator) Handle(ctx context.Context, rec slog.Record) error {
 	// Early exit when no extractors to avoid unnecessary processing
-	if len(h.extractors) == 0 {
+	if len(h.extractors) == 0 && len(h.groups) == 0 {
+		return h.next.Handle(ctx, rec)
+	}
+
+	if h.contextGroup == "" {
+		for _, ex := range h.extractors {
+			if attr, ok := ex(ctx); ok {
+				rec.AddAttrs(attr)
+			}
+		}
 		return h.next.Handle(ctx, rec)
 	}
 
+	var ctxAttrs []any
 	for _, ex := range h.extractors {
 		if attr, ok := ex(ctx); ok {
-			rec.AddAttrs(attr)
+			ctxAttrs = append(ctxAttrs, attr)
 		}
 	}
-	return h.next.Handle(ctx, rec)
+	if len(ctxAttrs) == 0 && len(h.groups) == 0 {
+		return h.next.Handle(ctx, rec)
+	}
+
+	// Rebuild the record: context group first, then the record attributes
+	// nested in the open groups, innermost last
+	out := slog.NewRecord(rec.Time, rec.Level, rec.Message, rec.PC)
+	if len(ctxAttrs) > 0 {
+		out.AddAttrs(slog.Group(h.contextGroup, ctxAttrs...))
+	}
+
+	attrs := make([]slog.Attr, 0, rec.NumAttrs())
+	rec.Attrs(func(a slog.Attr) bool {
+		attrs = append(attrs, a)
+		return true
+	})
+	for i := len(h.groups) - 1; i >= 0; i-- {
+		g := h.groups[i]
+		nested := make([]any, 0, len(g.attrs)+len(attrs))
+		for _, a := range g.attrs {
+			nested = append(nested, a)
+		}
+		for _, a := range attrs {
+			nested = append(nested, a)
+		}
+		attrs = []slog.Attr{slog.Group(g.name, nested...)}
+	}
+	out.AddAttrs(attrs...)
+
+	return h.next.Handle(ctx, out)
 }
 
 // WithAttrs creates a new decorated handler with additional static attributes.
 // Preserves context extractors while delegating attribute handling to the underlying handler.
 func (h *LogHandlerDecorator) WithAttrs(attrs []slog.Attr) slog.Handler {
-	return &LogHandlerDecorator{
-		next:       h.next.WithAttrs(attrs),
-		extractors: h.extractors,
+	if len(h.groups) > 0 {
+		// Attributes belong to the innermost group, which is applied in Handle
+		groups := slices.Clone(h.groups)
+		last := &groups[len(groups)-1]
+		last.attrs = append(slices.Clip(last.attrs), attrs...)
+		return h.with(h.next, groups)
 	}
+	return h.with(h.next.WithAttrs(attrs), nil)
 }
 
 // WithGroup creates a new decorated handler with attribute grouping.
 // Preserves context extractors while delegating grouping to the underlying handler.
+// With a context group, groups are applied in Handle instead, so that context
+// attributes stay at the top level.
 func (h *LogHandlerDecorator) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	if h.contextGroup != "" {
+		return h.with(h.next, append(slices.Clip(h.groups), handlerGroup{name: name}))
+	}
+	return h.with(h.next.WithGroup(name), nil)
+}
+
+func (h *LogHandlerDecorator) with(next slog.Handler, groups []handlerGroup) *LogHandlerDecorator {
 	return &LogHandlerDecorator{
-		next:       h.next.WithGroup(name),
-		extractors: h.extractors,
+		next:         next,
+		extractors:   h.extractors,
+		contextGroup: h.contextGroup,
+		groups:       groups,
 	}
 }
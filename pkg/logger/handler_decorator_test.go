@@ -0,0 +1,90 @@
+package logger_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dmitrymomot/saaskit/pkg/logger"
+)
+
+type decoratorCtxKey struct{}
+
+func decodeEntry(t *testing.T, buf *bytes.Buffer) map[string]any {
+	t.Helper()
+	var entry map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	return entry
+}
+
+func TestWithContextGroup(t *testing.T) {
+	ctx := context.WithValue(context.Background(), decoratorCtxKey{}, "req-1")
+
+	t.Run("nests context attributes under the group", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		log := logger.New(
+			logger.WithOutput(buf),
+			logger.WithContextGroup("ctx"),
+			logger.WithContextValue("request_id", decoratorCtxKey{}),
+			logger.WithContextExtractors(func(context.Context) (slog.Attr, bool) {
+				return slog.String("tenant", "acme"), true
+			}),
+		)
+
+		log.InfoContext(ctx, "hello", slog.String("request_id", "handler"))
+
+		entry := decodeEntry(t, buf)
+		assert.Equal(t, "handler", entry["request_id"])
+		assert.Equal(t, map[string]any{"request_id": "req-1", "tenant": "acme"}, entry["ctx"])
+	})
+
+	t.Run("context group stays outside logger groups", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		log := logger.New(
+			logger.WithOutput(buf),
+			logger.WithAttr(slog.String("service", "api")),
+			logger.WithContextGroup("ctx"),
+			logger.WithContextValue("request_id", decoratorCtxKey{}),
+		)
+
+		log.With(slog.Int("top", 1)).
+			WithGroup("billing").With(slog.String("plan", "pro")).
+			WithGroup("invoice").
+			InfoContext(ctx, "paid", slog.Int("amount", 10))
+
+		entry := decodeEntry(t, buf)
+		assert.Equal(t, "api", entry["service"])
+		assert.Equal(t, float64(1), entry["top"])
+		assert.Equal(t, map[string]any{"request_id": "req-1"}, entry["ctx"])
+		assert.Equal(t, map[string]any{
+			"plan":    "pro",
+			"invoice": map[string]any{"amount": float64(10)},
+		}, entry["billing"])
+	})
+
+	t.Run("groups apply without context values", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		log := logger.New(logger.WithOutput(buf), logger.WithContextGroup("ctx"))
+
+		log.WithGroup("sub").Info("hello", slog.String("k", "v"))
+
+		entry := decodeEntry(t, buf)
+		assert.NotContains(t, entry, "ctx")
+		assert.Equal(t, map[string]any{"k": "v"}, entry["sub"])
+	})
+
+	t.Run("without a context group attributes follow logger groups", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		log := logger.New(logger.WithOutput(buf), logger.WithContextValue("request_id", decoratorCtxKey{}))
+
+		log.WithGroup("sub").InfoContext(ctx, "hello")
+
+		entry := decodeEntry(t, buf)
+		assert.Equal(t, map[string]any{"request_id": "req-1"}, entry["sub"])
+	})
+}
@@ -0,0 +1,367 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Default configuration values for HTTPHandler, tuned for low-volume serverless workloads.
+const (
+	DefaultHTTPBatchSize     = 100
+	DefaultHTTPBufferSize    = 10000
+	DefaultHTTPFlushInterval = 5 * time.Second
+	DefaultHTTPMaxRetries    = 3
+	DefaultHTTPRetryBackoff  = 500 * time.Millisecond
+	DefaultHTTPTimeout       = 10 * time.Second
+)
+
+// ErrCollectorRejected is reported when the collector responds with a non-2xx status.
+var ErrCollectorRejected = errors.New("log collector rejected batch")
+
+// OverflowPolicy decides which record is dropped when the buffer is full.
+// Records are never queued synchronously, so a slow collector can't block logging.
+type OverflowPolicy int
+
+const (
+	// DropNewest discards the record being logged, keeping the buffered backlog.
+	DropNewest OverflowPolicy = iota
+	// DropOldest discards the oldest buffered record to make room for the new one.
+	DropOldest
+)
+
+// HTTPOption configures an HTTPHandler.
+type HTTPOption func(*httpConfig)
+
+type httpConfig struct {
+	client        *http.Client
+	headers       http.Header
+	level         slog.Leveler
+	batchSize     int
+	bufferSize    int
+	flushInterval time.Duration
+	maxRetries    int
+	retryBackoff  time.Duration
+	overflow      OverflowPolicy
+	onError       func(error)
+}
+
+// WithHTTPClient sets the client used to ship batches, ignoring nil.
+func WithHTTPClient(client *http.Client) HTTPOption {
+	return func(c *httpConfig) {
+		if client != nil {
+			c.client = client
+		}
+	}
+}
+
+// WithHTTPHeader adds a header to every collector request, e.g. an API key.
+func WithHTTPHeader(key, value string) HTTPOption {
+	return func(c *httpConfig) {
+		c.headers.Add(key, value)
+	}
+}
+
+// WithHTTPLevel sets the minimum level shipped to the collector (default INFO).
+func WithHTTPLevel(level slog.Leveler) HTTPOption {
+	return func(c *httpConfig) {
+		if level != nil {
+			c.level = level
+		}
+	}
+}
+
+// WithBatchSize sets how many records trigger an immediate flush.
+func WithBatchSize(size int) HTTPOption {
+	return func(c *httpConfig) {
+		if size > 0 {
+			c.batchSize = size
+		}
+	}
+}
+
+// WithBufferSize sets how many records may wait in memory before the overflow policy applies.
+func WithBufferSize(size int) HTTPOption {
+	return func(c *httpConfig) {
+		if size > 0 {
+			c.bufferSize = size
+		}
+	}
+}
+
+// WithFlushInterval sets the maximum time a partial batch waits before being shipped.
+func WithFlushInterval(interval time.Duration) HTTPOption {
+	return func(c *httpConfig) {
+		if interval > 0 {
+			c.flushInterval = interval
+		}
+	}
+}
+
+// WithRetry configures retries for transient failures (network errors, 429 and 5xx).
+// The backoff doubles after each attempt.
+func WithRetry(maxRetries int, backoff time.Duration) HTTPOption {
+	return func(c *httpConfig) {
+		if maxRetries >= 0 {
+			c.maxRetries = maxRetries
+		}
+		if backoff > 0 {
+			c.retryBackoff = backoff
+		}
+	}
+}
+
+// WithOverflowPolicy sets which record is dropped when the buffer is full (default DropNewest).
+func WithOverflowPolicy(policy OverflowPolicy) HTTPOption {
+	return func(c *httpConfig) {
+		c.overflow = policy
+	}
+}
+
+// WithHTTPErrorHandler registers a callback for batches that could not be delivered.
+// It runs on the shipping goroutine, so it must not log through the same handler.
+func WithHTTPErrorHandler(fn func(error)) HTTPOption {
+	return func(c *httpConfig) {
+		c.onError = fn
+	}
+}
+
+// HTTPHandler is a slog.Handler that buffers records and ships them to an HTTP
+// collector as JSON array batches. Handle never blocks on the network: records
+// are queued in a bounded buffer and a background goroutine flushes them when
+// the batch fills up or the flush interval elapses.
+//
+// Records are encoded by slog.JSONHandler, so WithAttrs/WithGroup and attributes
+// added by LogHandlerDecorator are preserved. Call Close during shutdown to
+// deliver buffered records.
+type HTTPHandler struct {
+	json slog.Handler
+	sink *httpSink
+}
+
+type httpSink struct {
+	endpoint string
+	cfg      httpConfig
+
+	queue   chan []byte
+	done    chan struct{}
+	stopped chan struct{}
+	closed  atomic.Bool
+	once    sync.Once
+	dropped atomic.Uint64
+}
+
+// NewHTTPHandler creates a handler shipping logs to endpoint and starts its background worker.
+// Panics on an empty endpoint to enforce fail-fast initialization.
+func NewHTTPHandler(endpoint string, opts ...HTTPOption) *HTTPHandler {
+	if endpoint == "" {
+		panic("logger: http collector endpoint is required")
+	}
+
+	cfg := httpConfig{
+		client:        &http.Client{Timeout: DefaultHTTPTimeout},
+		headers:       make(http.Header),
+		level:         slog.LevelInfo,
+		batchSize:     DefaultHTTPBatchSize,
+		bufferSize:    DefaultHTTPBufferSize,
+		flushInterval: DefaultHTTPFlushInterval,
+		maxRetries:    DefaultHTTPMaxRetries,
+		retryBackoff:  DefaultHTTPRetryBackoff,
+		overflow:      DropNewest,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	sink := &httpSink{
+		endpoint: endpoint,
+		cfg:      cfg,
+		queue:    make(chan []byte, cfg.bufferSize),
+		done:     make(chan struct{}),
+		stopped:  make(chan struct{}),
+	}
+	go sink.run()
+
+	return &HTTPHandler{
+		json: slog.NewJSONHandler(sink, &slog.HandlerOptions{Level: cfg.level}),
+		sink: sink,
+	}
+}
+
+func (h *HTTPHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.json.Enabled(ctx, level)
+}
+
+// Handle encodes the record and queues it without waiting for the collector.
+func (h *HTTPHandler) Handle(ctx context.Context, rec slog.Record) error {
+	return h.json.Handle(ctx, rec)
+}
+
+func (h *HTTPHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &HTTPHandler{json: h.json.WithAttrs(attrs), sink: h.sink}
+}
+
+func (h *HTTPHandler) WithGroup(name string) slog.Handler {
+	return &HTTPHandler{json: h.json.WithGroup(name), sink: h.sink}
+}
+
+// Dropped returns the number of records discarded due to overflow or after Close.
+func (h *HTTPHandler) Dropped() uint64 {
+	return h.sink.dropped.Load()
+}
+
+// Close stops accepting records and flushes the buffer to the collector.
+// The context bounds the wait - if it expires, remaining records may be lost.
+// Handlers derived via WithAttrs/WithGroup share the buffer, so closing any of them closes all.
+func (h *HTTPHandler) Close(ctx context.Context) error {
+	h.sink.once.Do(func() {
+		h.sink.closed.Store(true)
+		close(h.sink.done)
+	})
+
+	select {
+	case <-h.sink.stopped:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Write receives one encoded record from slog.JSONHandler, which writes each
+// record with a single call while holding its lock.
+func (s *httpSink) Write(p []byte) (int, error) {
+	if s.closed.Load() {
+		s.dropped.Add(1)
+		return len(p), nil
+	}
+
+	// JSONHandler reuses its buffer, so the record must be copied
+	line := bytes.Clone(bytes.TrimRight(p, "\n"))
+
+	select {
+	case s.queue <- line:
+		return len(p), nil
+	default:
+	}
+
+	if s.cfg.overflow == DropOldest {
+		select {
+		case <-s.queue:
+			s.dropped.Add(1)
+		default:
+		}
+		select {
+		case s.queue <- line:
+			return len(p), nil
+		default:
+		}
+	}
+
+	s.dropped.Add(1)
+	return len(p), nil
+}
+
+func (s *httpSink) run() {
+	defer close(s.stopped)
+
+	ticker := time.NewTicker(s.cfg.flushInterval)
+	defer ticker.Stop()
+
+	batch := make([][]byte, 0, s.cfg.batchSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		if err := s.ship(batch); err != nil && s.cfg.onError != nil {
+			s.cfg.onError(err)
+		}
+		clear(batch)
+		batch = batch[:0]
+	}
+
+	for {
+		select {
+		case line := <-s.queue:
+			batch = append(batch, line)
+			if len(batch) >= s.cfg.batchSize {
+				flush()
+			}
+
+		case <-ticker.C:
+			flush()
+
+		case <-s.done:
+			// Drain without closing the queue, so late writers can't panic on a closed channel
+			for {
+				select {
+				case line := <-s.queue:
+					batch = append(batch, line)
+					if len(batch) >= s.cfg.batchSize {
+						flush()
+					}
+				default:
+					flush()
+					return
+				}
+			}
+		}
+	}
+}
+
+func (s *httpSink) ship(batch [][]byte) error {
+	var body bytes.Buffer
+	body.WriteByte('[')
+	body.Write(bytes.Join(batch, []byte{','}))
+	body.WriteByte(']')
+	payload := body.Bytes()
+
+	backoff := s.cfg.retryBackoff
+	var err error
+	for attempt := range s.cfg.maxRetries + 1 {
+		if attempt > 0 {
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+
+		var retry bool
+		retry, err = s.post(payload)
+		if err == nil || !retry {
+			break
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to ship %d log records: %w", len(batch), err)
+	}
+	return nil
+}
+
+// post sends one batch and reports whether a failure is worth retrying.
+func (s *httpSink) post(payload []byte) (bool, error) {
+	req, err := http.NewRequest(http.MethodPost, s.endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return false, err
+	}
+	req.Header = s.cfg.headers.Clone()
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.cfg.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+
+	err = fmt.Errorf("%w: status %d", ErrCollectorRejected, resp.StatusCode)
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500, err
+}
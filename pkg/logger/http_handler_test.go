@@ -0,0 +1,208 @@
+package logger_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dmitrymomot/saaskit/pkg/logger"
+)
+
+type collector struct {
+	mu      sync.Mutex
+	batches [][]map[string]any
+	headers http.Header
+}
+
+func (c *collector) handler(t *testing.T) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		var batch []map[string]any
+		require.NoError(t, json.Unmarshal(body, &batch))
+
+		c.mu.Lock()
+		c.batches = append(c.batches, batch)
+		c.headers = r.Header.Clone()
+		c.mu.Unlock()
+		w.WriteHeader(http.StatusAccepted)
+	}
+}
+
+func (c *collector) records() []map[string]any {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var all []map[string]any
+	for _, b := range c.batches {
+		all = append(all, b...)
+	}
+	return all
+}
+
+func TestHTTPHandler(t *testing.T) {
+	t.Parallel()
+
+	t.Run("flushes on close with context attributes", func(t *testing.T) {
+		t.Parallel()
+		c := &collector{}
+		srv := httptest.NewServer(c.handler(t))
+		defer srv.Close()
+
+		h := logger.NewHTTPHandler(srv.URL, logger.WithHTTPHeader("Authorization", "Bearer secret"))
+		type ctxKey struct{}
+		log := logger.New(
+			logger.WithHandler(h),
+			logger.WithAttr(logger.Component("billing")),
+			logger.WithContextValue("request_id", ctxKey{}),
+		)
+
+		ctx := context.WithValue(context.Background(), ctxKey{}, "req-1")
+		log.InfoContext(ctx, "charged", logger.UserID(42))
+		log.Debug("filtered by level")
+
+		require.NoError(t, h.Close(context.Background()))
+
+		records := c.records()
+		require.Len(t, records, 1)
+		assert.Equal(t, "charged", records[0]["msg"])
+		assert.Equal(t, "req-1", records[0]["request_id"])
+		assert.Equal(t, "billing", records[0]["component"])
+		assert.Equal(t, "Bearer secret", c.headers.Get("Authorization"))
+	})
+
+	t.Run("flushes when batch is full", func(t *testing.T) {
+		t.Parallel()
+		c := &collector{}
+		srv := httptest.NewServer(c.handler(t))
+		defer srv.Close()
+
+		h := logger.NewHTTPHandler(srv.URL, logger.WithBatchSize(2), logger.WithFlushInterval(time.Hour))
+		defer h.Close(context.Background())
+		log := logger.New(logger.WithHandler(h))
+
+		log.Info("one")
+		log.Info("two")
+
+		assert.Eventually(t, func() bool { return len(c.records()) == 2 }, time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("flushes on interval", func(t *testing.T) {
+		t.Parallel()
+		c := &collector{}
+		srv := httptest.NewServer(c.handler(t))
+		defer srv.Close()
+
+		h := logger.NewHTTPHandler(srv.URL, logger.WithFlushInterval(20*time.Millisecond))
+		defer h.Close(context.Background())
+		logger.New(logger.WithHandler(h)).Info("tick")
+
+		assert.Eventually(t, func() bool { return len(c.records()) == 1 }, time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("retries transient failures", func(t *testing.T) {
+		t.Parallel()
+		c := &collector{}
+		var calls atomic.Int32
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if calls.Add(1) < 3 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			c.handler(t)(w, r)
+		}))
+		defer srv.Close()
+
+		h := logger.NewHTTPHandler(srv.URL, logger.WithRetry(3, time.Millisecond))
+		logger.New(logger.WithHandler(h)).Info("eventually delivered")
+		require.NoError(t, h.Close(context.Background()))
+
+		assert.Equal(t, int32(3), calls.Load())
+		assert.Len(t, c.records(), 1)
+	})
+
+	t.Run("does not retry client errors", func(t *testing.T) {
+		t.Parallel()
+		var calls atomic.Int32
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			w.WriteHeader(http.StatusBadRequest)
+		}))
+		defer srv.Close()
+
+		var reported atomic.Value
+		h := logger.NewHTTPHandler(srv.URL,
+			logger.WithRetry(3, time.Millisecond),
+			logger.WithHTTPErrorHandler(func(err error) { reported.Store(err) }),
+		)
+		logger.New(logger.WithHandler(h)).Info("rejected")
+		require.NoError(t, h.Close(context.Background()))
+
+		assert.Equal(t, int32(1), calls.Load())
+		err, _ := reported.Load().(error)
+		assert.ErrorIs(t, err, logger.ErrCollectorRejected)
+	})
+
+	t.Run("never blocks when collector is slow", func(t *testing.T) {
+		t.Parallel()
+		release := make(chan struct{})
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-release
+		}))
+		defer srv.Close()
+
+		h := logger.NewHTTPHandler(srv.URL,
+			logger.WithBatchSize(1),
+			logger.WithBufferSize(2),
+			logger.WithOverflowPolicy(logger.DropOldest),
+		)
+		log := logger.New(logger.WithHandler(h))
+
+		done := make(chan struct{})
+		go func() {
+			for range 100 {
+				log.Info("burst")
+			}
+			close(done)
+		}()
+
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("logging blocked on a slow collector")
+		}
+		assert.Positive(t, h.Dropped())
+
+		close(release)
+		require.NoError(t, h.Close(context.Background()))
+	})
+
+	t.Run("records after close are dropped", func(t *testing.T) {
+		t.Parallel()
+		c := &collector{}
+		srv := httptest.NewServer(c.handler(t))
+		defer srv.Close()
+
+		h := logger.NewHTTPHandler(srv.URL)
+		require.NoError(t, h.Close(context.Background()))
+		require.NoError(t, h.Close(context.Background()))
+
+		logger.New(logger.WithHandler(h)).Info("late")
+		assert.Equal(t, uint64(1), h.Dropped())
+		assert.Empty(t, c.records())
+	})
+
+	t.Run("panics without endpoint", func(t *testing.T) {
+		t.Parallel()
+		assert.Panics(t, func() { logger.NewHTTPHandler("") })
+	})
+}
@@ -46,6 +46,19 @@
 // for config file management and enables secure credential handling through
 // environment variables or secret management systems.
 //
+// # Aggregation
+//
+// Pipeline builds aggregation pipelines fluently, and Aggregate decodes the results
+// into typed structs:
+//
+//	totals, err := mongo.Aggregate[StatusTotal](ctx, orders, mongo.NewPipeline().
+//		Match(bson.M{"tenant_id": tenantID}).
+//		Group("$status", bson.D{{Key: "total", Value: bson.M{"$sum": "$amount"}}}).
+//		Sort(bson.D{{Key: "total", Value: -1}}).
+//		Limit(10))
+//
+// The builder only produces bson; Stage adds any stage without a dedicated helper.
+//
 // # Error Handling
 //
 // Connection failures are wrapped in domain-specific errors to enable proper
@@ -9,4 +9,5 @@ import "errors"
 var (
 	ErrFailedToConnectToMongo = errors.New("failed to connect to mongo")
 	ErrHealthcheckFailed      = errors.New("mongo healthcheck failed")
+	ErrAggregationFailed      = errors.New("mongo aggregation failed")
 )
@@ -0,0 +1,105 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// Pipeline builds an aggregation pipeline stage by stage.
+// Each method appends one stage and returns the pipeline, so calls can be chained.
+// It only assembles bson; any stage not covered by a helper can be added with Stage.
+type Pipeline struct {
+	stages mongo.Pipeline
+}
+
+// NewPipeline creates an empty aggregation pipeline.
+func NewPipeline() *Pipeline {
+	return &Pipeline{}
+}
+
+// Match adds a $match stage that filters documents.
+func (p *Pipeline) Match(filter any) *Pipeline {
+	return p.Stage("$match", filter)
+}
+
+// Group adds a $group stage grouping documents by id, which may be a field path
+// such as "$status", a document for compound keys, or nil for a single group.
+// Accumulators are the output fields, e.g. bson.D{{Key: "total", Value: bson.M{"$sum": "$amount"}}}.
+func (p *Pipeline) Group(id any, accumulators bson.D) *Pipeline {
+	group := make(bson.D, 0, len(accumulators)+1)
+	group = append(group, bson.E{Key: "_id", Value: id})
+	group = append(group, accumulators...)
+	return p.Stage("$group", group)
+}
+
+// Sort adds a $sort stage. Use bson.D to keep the order of sort keys,
+// e.g. bson.D{{Key: "total", Value: -1}}.
+func (p *Pipeline) Sort(sort bson.D) *Pipeline {
+	return p.Stage("$sort", sort)
+}
+
+// Limit adds a $limit stage.
+func (p *Pipeline) Limit(n int64) *Pipeline {
+	return p.Stage("$limit", n)
+}
+
+// Lookup adds a $lookup stage that joins documents from another collection
+// where localField equals foreignField, storing the matches in the as array field.
+func (p *Pipeline) Lookup(from, localField, foreignField, as string) *Pipeline {
+	return p.Stage("$lookup", bson.D{
+		{Key: "from", Value: from},
+		{Key: "localField", Value: localField},
+		{Key: "foreignField", Value: foreignField},
+		{Key: "as", Value: as},
+	})
+}
+
+// Project adds a $project stage that reshapes documents.
+func (p *Pipeline) Project(projection any) *Pipeline {
+	return p.Stage("$project", projection)
+}
+
+// Stage adds an arbitrary stage, e.g. Stage("$unwind", "$items").
+func (p *Pipeline) Stage(operator string, value any) *Pipeline {
+	p.stages = append(p.stages, bson.D{{Key: operator, Value: value}})
+	return p
+}
+
+// Stages returns a copy of the pipeline stages, ready to pass to the driver.
+func (p *Pipeline) Stages() mongo.Pipeline {
+	if p == nil {
+		return mongo.Pipeline{}
+	}
+	return slices.Clone(p.stages)
+}
+
+// Aggregate runs the pipeline on the collection and decodes all results into T,
+// using the `bson` struct tags of T. Returns an empty slice when nothing matches.
+//
+//	type StatusTotal struct {
+//		Status string `bson:"_id"`
+//		Total  int64  `bson:"total"`
+//	}
+//
+//	totals, err := mongo.Aggregate[StatusTotal](ctx, orders, mongo.NewPipeline().
+//		Match(bson.M{"tenant_id": tenantID}).
+//		Group("$status", bson.D{{Key: "total", Value: bson.M{"$sum": "$amount"}}}).
+//		Sort(bson.D{{Key: "total", Value: -1}}))
+func Aggregate[T any](ctx context.Context, coll *mongo.Collection, pipeline *Pipeline, opts ...options.Lister[options.AggregateOptions]) ([]T, error) {
+	cursor, err := coll.Aggregate(ctx, pipeline.Stages(), opts...)
+	if err != nil {
+		return nil, errors.Join(ErrAggregationFailed, err)
+	}
+
+	results := make([]T, 0)
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, errors.Join(ErrAggregationFailed, err)
+	}
+
+	return results, nil
+}
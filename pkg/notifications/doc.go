@@ -71,6 +71,19 @@
 //	    return nil
 //	}
 //
+// # Web Push
+//
+// WebPushDeliverer sends browser push notifications using VAPID keys and the
+// subscriptions returned by a PushSubscriptionStore. Payloads are encrypted per
+// RFC 8291, only PriorityHigh and above are pushed by default, and subscriptions
+// the push service reports as gone (404/410) are removed from the store:
+//
+//	push, err := notifications.NewWebPushDeliverer(vapidPublic, vapidPrivate,
+//	    "mailto:ops@example.com", subscriptionStore,
+//	    notifications.WithMinPushPriority(notifications.PriorityHigh),
+//	)
+//	deliverer := notifications.NewMultiDeliverer([]notifications.Deliverer{broadcastDeliverer, push})
+//
 // # Storage Implementations
 //
 // The package includes a memory-based storage for development.
@@ -84,6 +97,23 @@
 //	    // Store in PostgreSQL
 //	}
 //
+// # Localized Templates
+//
+// A notification with a Template is rendered when it's sent: Title and Message are
+// resolved from the "<key>.title" and "<key>.message" translations of the translator
+// set with WithTranslator, in the language returned by the LocaleResolver for the
+// recipient. Raw Title and Message act as fallbacks for missing translations.
+//
+//	manager := notifications.NewManager(storage, deliverer,
+//	    notifications.WithTranslator(translator),
+//	    notifications.WithLocaleResolver(users.PreferredLanguage),
+//	)
+//
+//	err := manager.Send(ctx, notifications.Notification{
+//	    UserID:   "user123",
+//	    Template: &notifications.Template{Key: "invite", Data: map[string]any{"team": "Acme"}},
+//	})
+//
 // # Notification Types
 //
 // Four notification types are provided:
@@ -8,14 +8,17 @@ import (
 
 	"github.com/google/uuid"
 
+	"github.com/dmitrymomot/saaskit/pkg/i18n"
 	"github.com/dmitrymomot/saaskit/pkg/logger"
 )
 
 // Manager orchestrates notification storage and delivery.
 type Manager struct {
-	storage   Storage
-	deliverer Deliverer
-	logger    *slog.Logger
+	storage        Storage
+	deliverer      Deliverer
+	logger         *slog.Logger
+	translator     *i18n.Translator
+	localeResolver LocaleResolver
 }
 
 // ManagerOption configures a Manager.
@@ -58,6 +61,11 @@ func (m *Manager) Send(ctx context.Context, notif Notification) error {
 		notif.CreatedAt = time.Now()
 	}
 
+	// Render templated content in the recipient's language before it's persisted
+	if err := m.render(ctx, &notif); err != nil {
+		return err
+	}
+
 	// Store first to ensure persistence even if real-time delivery fails
 	if err := m.storage.Create(ctx, notif); err != nil {
 		return fmt.Errorf("failed to store notification: %w", err)
@@ -88,6 +96,11 @@ func (m *Manager) SendToUsers(ctx context.Context, userIDs []string, template No
 		notif.UserID = userID
 		notif.CreatedAt = time.Now()
 
+		// Each recipient gets the template rendered in their own language
+		if err := m.render(ctx, &notif); err != nil {
+			return err
+		}
+
 		// Store notification
 		if err := m.storage.Create(ctx, notif); err != nil {
 			return fmt.Errorf("failed to store notification for user %s: %w", userID, err)
@@ -122,6 +135,10 @@ func (m *Manager) SendBatch(ctx context.Context, notifications []Notification) e
 			notifications[i].CreatedAt = time.Now()
 		}
 
+		if err := m.render(ctx, &notifications[i]); err != nil {
+			return err
+		}
+
 		// Store notification
 		if err := m.storage.Create(ctx, notifications[i]); err != nil {
 			return fmt.Errorf("failed to store notification %s: %w", notifications[i].ID, err)
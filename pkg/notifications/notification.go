@@ -39,8 +39,9 @@ type Notification struct {
 	Priority  Priority       `json:"priority"`
 	Title     string         `json:"title"`
 	Message   string         `json:"message"`
-	Data      map[string]any `json:"data,omitempty"`    // Arbitrary data for transport layers to use
-	Actions   []Action       `json:"actions,omitempty"` // Call-to-action buttons
+	Data      map[string]any `json:"data,omitempty"`     // Arbitrary data for transport layers to use
+	Actions   []Action       `json:"actions,omitempty"`  // Call-to-action buttons
+	Template  *Template      `json:"template,omitempty"` // Localized content rendered into Title and Message on send
 	Read      bool           `json:"read"`
 	ReadAt    *time.Time     `json:"read_at,omitempty"`
 	CreatedAt time.Time      `json:"created_at"`
@@ -0,0 +1,107 @@
+package notifications
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+
+	"github.com/dmitrymomot/saaskit/pkg/i18n"
+	"github.com/dmitrymomot/saaskit/pkg/logger"
+)
+
+// ErrTranslatorRequired is returned when a templated notification is sent
+// by a Manager without a translator.
+var ErrTranslatorRequired = errors.New("notification template requires a translator")
+
+// Template describes localized notification content that is rendered at delivery time.
+//
+// Title and Message are resolved from the "<Key>.title" and "<Key>.message" translation
+// keys, with Data values substituted as named parameters (%{name}). Keys missing in the
+// recipient's language (including unsupported languages) are rendered in the translator's
+// default language; when that has no translation either, the notification's raw Title or
+// Message is used instead.
+type Template struct {
+	Key  string         `json:"key"`
+	Data map[string]any `json:"data,omitempty"`
+}
+
+// LocaleResolver returns the preferred language of a recipient.
+// An empty language or an error falls back to the translator's default language.
+type LocaleResolver func(ctx context.Context, userID string) (string, error)
+
+// WithTranslator sets the translator used to render notification templates.
+func WithTranslator(translator *i18n.Translator) ManagerOption {
+	return func(m *Manager) {
+		m.translator = translator
+	}
+}
+
+// WithLocaleResolver sets the callback that resolves a recipient's language.
+// Without it, templates are rendered in the translator's default language.
+func WithLocaleResolver(resolver LocaleResolver) ManagerOption {
+	return func(m *Manager) {
+		m.localeResolver = resolver
+	}
+}
+
+// render fills the notification's Title and Message from its template.
+// Notifications without a template are left unchanged.
+func (m *Manager) render(ctx context.Context, notif *Notification) error {
+	if notif.Template == nil {
+		return nil
+	}
+	if m.translator == nil {
+		return ErrTranslatorRequired
+	}
+
+	lang := m.resolveLocale(ctx, notif.UserID)
+	args := notif.Template.args()
+
+	notif.Title = m.translate(lang, notif.Template.Key+".title", notif.Title, args)
+	notif.Message = m.translate(lang, notif.Template.Key+".message", notif.Message, args)
+
+	return nil
+}
+
+// translate renders key in lang, or in the default language when lang and its
+// fallback chain have no translation for it.
+func (m *Manager) translate(lang, key, fallback string, args []string) string {
+	if !m.translator.HasTranslation(lang, key) {
+		lang = m.translator.DefaultLanguage()
+	}
+	return m.translator.Td(lang, key, fallback, args...)
+}
+
+// resolveLocale returns the recipient's language, or the translator's default language
+// when it can't be resolved.
+func (m *Manager) resolveLocale(ctx context.Context, userID string) string {
+	if m.localeResolver != nil {
+		lang, err := m.localeResolver(ctx, userID)
+		if err != nil {
+			m.logger.LogAttrs(ctx, slog.LevelWarn, "Failed to resolve notification locale, using default language",
+				logger.UserID(userID),
+				logger.Error(err),
+			)
+		} else if lang != "" {
+			return lang
+		}
+	}
+	return m.translator.DefaultLanguage()
+}
+
+// args converts template data to i18n named parameters, in key order.
+func (t *Template) args() []string {
+	keys := make([]string, 0, len(t.Data))
+	for k := range t.Data {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+
+	args := make([]string, 0, len(keys)*2)
+	for _, k := range keys {
+		args = append(args, k, fmt.Sprint(t.Data[k]))
+	}
+	return args
+}
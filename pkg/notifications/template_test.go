@@ -0,0 +1,121 @@
+package notifications
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dmitrymomot/saaskit/pkg/i18n"
+)
+
+func newTestTranslator(t *testing.T) *i18n.Translator {
+	t.Helper()
+
+	translator, err := i18n.NewTranslator(context.Background(), &i18n.MapAdapter{
+		Data: map[string]map[string]any{
+			"en": {
+				"invite": map[string]any{
+					"title":   "You're invited",
+					"message": "%{inviter} invited you to %{team}",
+				},
+			},
+			"de": {
+				"invite": map[string]any{
+					"title":   "Du bist eingeladen",
+					"message": "%{inviter} hat dich zu %{team} eingeladen",
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+	return translator
+}
+
+func TestManager_SendTemplate(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	locales := map[string]string{"user-de": "de", "user-fr": "fr"}
+	resolver := func(_ context.Context, userID string) (string, error) {
+		if userID == "user-broken" {
+			return "", errors.New("profile unavailable")
+		}
+		return locales[userID], nil
+	}
+
+	invite := &Template{Key: "invite", Data: map[string]any{"inviter": "Alice", "team": "Acme"}}
+
+	t.Run("renders in the recipient language", func(t *testing.T) {
+		t.Parallel()
+
+		storage := NewMemoryStorage()
+		manager := NewManager(storage, nil, WithTranslator(newTestTranslator(t)), WithLocaleResolver(resolver))
+
+		require.NoError(t, manager.Send(ctx, Notification{UserID: "user-de", Template: invite}))
+
+		list, err := storage.List(ctx, "user-de", ListOptions{})
+		require.NoError(t, err)
+		require.Len(t, list, 1)
+		assert.Equal(t, "Du bist eingeladen", list[0].Title)
+		assert.Equal(t, "Alice hat dich zu Acme eingeladen", list[0].Message)
+		assert.Equal(t, invite, list[0].Template)
+	})
+
+	t.Run("falls back to default language", func(t *testing.T) {
+		t.Parallel()
+
+		storage := NewMemoryStorage()
+		manager := NewManager(storage, nil, WithTranslator(newTestTranslator(t)), WithLocaleResolver(resolver))
+
+		require.NoError(t, manager.SendToUsers(ctx, []string{"user-en", "user-broken", "user-de", "user-fr"}, Notification{Template: invite}))
+
+		for userID, title := range map[string]string{
+			"user-en":     "You're invited",
+			"user-broken": "You're invited",
+			"user-de":     "Du bist eingeladen",
+			"user-fr":     "You're invited", // unsupported language
+		} {
+			list, err := storage.List(ctx, userID, ListOptions{})
+			require.NoError(t, err)
+			require.Len(t, list, 1)
+			assert.Equal(t, title, list[0].Title, userID)
+		}
+	})
+
+	t.Run("keeps raw strings for missing translations", func(t *testing.T) {
+		t.Parallel()
+
+		storage := NewMemoryStorage()
+		manager := NewManager(storage, nil, WithTranslator(newTestTranslator(t)))
+
+		notifs := []Notification{
+			{UserID: "user-1", Title: "Raw title", Message: "Raw message"},
+			{UserID: "user-1", Title: "Raw title", Message: "Raw message", Template: &Template{Key: "unknown"}},
+		}
+		require.NoError(t, manager.SendBatch(ctx, notifs))
+
+		for _, n := range notifs {
+			assert.Equal(t, "Raw title", n.Title)
+			assert.Equal(t, "Raw message", n.Message)
+		}
+	})
+
+	t.Run("requires translator for templates", func(t *testing.T) {
+		t.Parallel()
+
+		storage := NewMemoryStorage()
+		manager := NewManager(storage, nil)
+
+		err := manager.Send(ctx, Notification{UserID: "user-1", Template: invite})
+		assert.ErrorIs(t, err, ErrTranslatorRequired)
+
+		count, err := storage.CountUnread(ctx, "user-1")
+		require.NoError(t, err)
+		assert.Zero(t, count)
+
+		require.NoError(t, manager.Send(ctx, Notification{UserID: "user-1", Title: "Plain"}))
+	})
+}
@@ -0,0 +1,403 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dmitrymomot/saaskit/pkg/logger"
+)
+
+const (
+	// webPushRecordSize is the aes128gcm record size; the whole payload is sent as one record.
+	webPushRecordSize = 4096
+	// webPushMaxPayload keeps the request body within the 4096 bytes push services accept:
+	// 86 bytes of header, a padding delimiter and the 16-byte AEAD tag.
+	webPushMaxPayload = 4096 - 86 - 1 - 16
+	// vapidTokenTTL must not exceed 24 hours per RFC 8292.
+	vapidTokenTTL = 12 * time.Hour
+)
+
+var (
+	ErrInvalidVAPIDKeys      = errors.New("invalid VAPID keys")
+	ErrInvalidSubscription   = errors.New("invalid push subscription")
+	ErrPushPayloadTooLarge   = errors.New("push payload exceeds 4KB limit")
+	ErrPushDeliveryFailed    = errors.New("push service rejected notification")
+	ErrSubscriptionStoreNil  = errors.New("push subscription store is required")
+	errSubscriptionNotActive = errors.New("push subscription expired or unsubscribed")
+)
+
+// PushSubscription is a browser push subscription as produced by
+// PushSubscription.toJSON() in the Push API.
+type PushSubscription struct {
+	Endpoint string               `json:"endpoint"`
+	Keys     PushSubscriptionKeys `json:"keys"`
+}
+
+// PushSubscriptionKeys holds the base64url encoded client keys of a subscription.
+type PushSubscriptionKeys struct {
+	P256dh string `json:"p256dh"`
+	Auth   string `json:"auth"`
+}
+
+// PushSubscriptionStore provides the push subscriptions of each user.
+type PushSubscriptionStore interface {
+	// Subscriptions returns all subscriptions of a user, one per browser or device.
+	Subscriptions(ctx context.Context, userID string) ([]PushSubscription, error)
+
+	// RemoveSubscription deletes a subscription the push service reported as gone (404/410).
+	RemoveSubscription(ctx context.Context, userID, endpoint string) error
+}
+
+// WebPushDeliverer sends notifications as Web Push messages (RFC 8030),
+// encrypted per RFC 8291 and authenticated with VAPID (RFC 8292).
+type WebPushDeliverer struct {
+	subscriptions PushSubscriptionStore
+	privateKey    *ecdsa.PrivateKey
+	publicKey     string
+	subject       string
+	client        *http.Client
+	minPriority   Priority
+	ttl           time.Duration
+	logger        *slog.Logger
+}
+
+// WebPushOption configures a WebPushDeliverer.
+type WebPushOption func(*WebPushDeliverer)
+
+// WithWebPushHTTPClient sets the HTTP client used to reach push services.
+func WithWebPushHTTPClient(client *http.Client) WebPushOption {
+	return func(d *WebPushDeliverer) {
+		if client != nil {
+			d.client = client
+		}
+	}
+}
+
+// WithMinPushPriority sets the lowest priority delivered as push.
+// Default is PriorityHigh, so routine notifications stay in-app only.
+func WithMinPushPriority(p Priority) WebPushOption {
+	return func(d *WebPushDeliverer) {
+		d.minPriority = p
+	}
+}
+
+// WithPushTTL sets how long the push service keeps a message for an offline device.
+// Notifications with ExpiresAt use the time left until expiry instead. Default is 24 hours.
+func WithPushTTL(ttl time.Duration) WebPushOption {
+	return func(d *WebPushDeliverer) {
+		if ttl >= 0 {
+			d.ttl = ttl
+		}
+	}
+}
+
+// WithWebPushLogger sets the logger for the WebPushDeliverer.
+func WithWebPushLogger(logger *slog.Logger) WebPushOption {
+	return func(d *WebPushDeliverer) {
+		d.logger = logger
+	}
+}
+
+// NewWebPushDeliverer creates a Web Push deliverer from a base64url encoded VAPID
+// key pair (uncompressed P-256 public key and raw private scalar, as generated by
+// most web-push tooling). Subject is a "mailto:" or "https:" contact URL for the
+// push service operator.
+func NewWebPushDeliverer(vapidPublicKey, vapidPrivateKey, subject string, subscriptions PushSubscriptionStore, opts ...WebPushOption) (*WebPushDeliverer, error) {
+	if subscriptions == nil {
+		return nil, ErrSubscriptionStoreNil
+	}
+
+	privateKey, err := parseVAPIDKeys(vapidPublicKey, vapidPrivateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &WebPushDeliverer{
+		subscriptions: subscriptions,
+		privateKey:    privateKey,
+		publicKey:     strings.TrimRight(vapidPublicKey, "="),
+		subject:       subject,
+		client:        &http.Client{Timeout: 30 * time.Second},
+		minPriority:   PriorityHigh,
+		ttl:           24 * time.Hour,
+		logger:        slog.Default(),
+	}
+
+	for _, opt := range opts {
+		opt(d)
+	}
+
+	return d, nil
+}
+
+// Deliver pushes the notification to every subscription of its user.
+// Subscriptions the push service reports as gone are removed from the store.
+// Notifications below the minimum priority or already expired are skipped.
+func (d *WebPushDeliverer) Deliver(ctx context.Context, notif Notification) error {
+	if notif.Priority < d.minPriority || notif.IsExpired() {
+		return nil
+	}
+
+	payload, err := json.Marshal(notif)
+	if err != nil {
+		return fmt.Errorf("failed to marshal notification: %w", err)
+	}
+	if len(payload) > webPushMaxPayload {
+		return ErrPushPayloadTooLarge
+	}
+
+	subs, err := d.subscriptions.Subscriptions(ctx, notif.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to load push subscriptions: %w", err)
+	}
+
+	var errs []error
+	for _, sub := range subs {
+		err := d.push(ctx, sub, payload, d.ttlFor(notif), urgency(notif.Priority))
+		if errors.Is(err, errSubscriptionNotActive) {
+			if err := d.subscriptions.RemoveSubscription(ctx, notif.UserID, sub.Endpoint); err != nil {
+				errs = append(errs, fmt.Errorf("failed to remove push subscription: %w", err))
+			}
+			continue
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (d *WebPushDeliverer) DeliverBatch(ctx context.Context, notifs []Notification) error {
+	for _, notif := range notifs {
+		if err := d.Deliver(ctx, notif); err != nil {
+			// Continue with remaining notifications even if one fails
+			d.logger.LogAttrs(ctx, slog.LevelError, "Failed to push notification",
+				slog.String("notification_id", notif.ID),
+				logger.UserID(notif.UserID),
+				logger.Error(err),
+			)
+		}
+	}
+	return nil
+}
+
+func (d *WebPushDeliverer) push(ctx context.Context, sub PushSubscription, payload []byte, ttl time.Duration, urgency string) error {
+	endpoint, err := url.Parse(sub.Endpoint)
+	if err != nil || endpoint.Scheme != "https" && endpoint.Scheme != "http" || endpoint.Host == "" {
+		return fmt.Errorf("%w: bad endpoint %q", ErrInvalidSubscription, sub.Endpoint)
+	}
+
+	body, err := encryptPushPayload(sub.Keys, payload)
+	if err != nil {
+		return err
+	}
+
+	token, err := d.vapidToken(endpoint.Scheme + "://" + endpoint.Host)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("Content-Encoding", "aes128gcm")
+	req.Header.Set("TTL", strconv.Itoa(int(ttl.Seconds())))
+	req.Header.Set("Urgency", urgency)
+	req.Header.Set("Authorization", "vapid t="+token+", k="+d.publicKey)
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send push: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return nil
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		return errSubscriptionNotActive
+	default:
+		return fmt.Errorf("%w: status %d", ErrPushDeliveryFailed, resp.StatusCode)
+	}
+}
+
+func (d *WebPushDeliverer) ttlFor(notif Notification) time.Duration {
+	if notif.ExpiresAt != nil {
+		return min(d.ttl, time.Until(*notif.ExpiresAt))
+	}
+	return d.ttl
+}
+
+// vapidToken signs an ES256 JWT for the push service origin (RFC 8292).
+func (d *WebPushDeliverer) vapidToken(audience string) (string, error) {
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"typ":"JWT","alg":"ES256"}`))
+	claims, err := json.Marshal(map[string]any{
+		"aud": audience,
+		"exp": time.Now().Add(vapidTokenTTL).Unix(),
+		"sub": d.subject,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	unsigned := header + "." + base64.RawURLEncoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(unsigned))
+
+	r, s, err := ecdsa.Sign(rand.Reader, d.privateKey, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("failed to sign VAPID token: %w", err)
+	}
+
+	// JWS uses fixed-size r||s instead of ASN.1
+	sig := make([]byte, 64)
+	r.FillBytes(sig[:32])
+	s.FillBytes(sig[32:])
+
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(sig), nil
+}
+
+// encryptPushPayload encrypts payload for a subscription using the aes128gcm
+// content coding with the Web Push key derivation (RFC 8291, RFC 8188).
+func encryptPushPayload(keys PushSubscriptionKeys, payload []byte) ([]byte, error) {
+	uaPublicBytes, err := decodeBase64URL(keys.P256dh)
+	if err != nil {
+		return nil, fmt.Errorf("%w: p256dh: %w", ErrInvalidSubscription, err)
+	}
+	authSecret, err := decodeBase64URL(keys.Auth)
+	if err != nil || len(authSecret) == 0 {
+		return nil, fmt.Errorf("%w: bad auth secret", ErrInvalidSubscription)
+	}
+
+	curve := ecdh.P256()
+	uaPublic, err := curve.NewPublicKey(uaPublicBytes)
+	if err != nil {
+		return nil, fmt.Errorf("%w: p256dh: %w", ErrInvalidSubscription, err)
+	}
+
+	asPrivate, err := curve.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	asPublicBytes := asPrivate.PublicKey().Bytes()
+
+	ecdhSecret, err := asPrivate.ECDH(uaPublic)
+	if err != nil {
+		return nil, err
+	}
+
+	keyInfo := make([]byte, 0, 14+len(uaPublicBytes)+len(asPublicBytes))
+	keyInfo = append(keyInfo, "WebPush: info\x00"...)
+	keyInfo = append(keyInfo, uaPublicBytes...)
+	keyInfo = append(keyInfo, asPublicBytes...)
+
+	ikm, err := hkdf.Key(sha256.New, ecdhSecret, authSecret, string(keyInfo), 32)
+	if err != nil {
+		return nil, err
+	}
+
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+
+	cek, err := hkdf.Key(sha256.New, ikm, salt, "Content-Encoding: aes128gcm\x00", 16)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := hkdf.Key(sha256.New, ikm, salt, "Content-Encoding: nonce\x00", 12)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(cek)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	// Single record: payload followed by the last-record padding delimiter
+	plaintext := append(bytes.Clone(payload), 0x02)
+
+	// Header: salt || record size || key id length || key id (sender public key)
+	body := make([]byte, 0, 16+4+1+len(asPublicBytes)+len(plaintext)+gcm.Overhead())
+	body = append(body, salt...)
+	body = binary.BigEndian.AppendUint32(body, webPushRecordSize)
+	body = append(body, byte(len(asPublicBytes)))
+	body = append(body, asPublicBytes...)
+
+	return gcm.Seal(body, nonce, plaintext, nil), nil
+}
+
+func parseVAPIDKeys(publicKey, privateKey string) (*ecdsa.PrivateKey, error) {
+	pub, err := decodeBase64URL(publicKey)
+	if err != nil {
+		return nil, fmt.Errorf("%w: public key: %w", ErrInvalidVAPIDKeys, err)
+	}
+	priv, err := decodeBase64URL(privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("%w: private key: %w", ErrInvalidVAPIDKeys, err)
+	}
+
+	ecdhKey, err := ecdh.P256().NewPrivateKey(priv)
+	if err != nil {
+		return nil, fmt.Errorf("%w: private key: %w", ErrInvalidVAPIDKeys, err)
+	}
+	derived := ecdhKey.PublicKey().Bytes()
+	if !bytes.Equal(derived, pub) {
+		return nil, fmt.Errorf("%w: public key does not match private key", ErrInvalidVAPIDKeys)
+	}
+
+	return &ecdsa.PrivateKey{
+		PublicKey: ecdsa.PublicKey{
+			Curve: elliptic.P256(),
+			X:     new(big.Int).SetBytes(derived[1:33]),
+			Y:     new(big.Int).SetBytes(derived[33:]),
+		},
+		D: new(big.Int).SetBytes(priv),
+	}, nil
+}
+
+// decodeBase64URL accepts base64url with or without padding, as browsers and tools differ.
+func decodeBase64URL(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+}
+
+// urgency maps notification priority to the Web Push Urgency header (RFC 8030).
+func urgency(p Priority) string {
+	switch {
+	case p >= PriorityHigh:
+		return "high"
+	case p == PriorityNormal:
+		return "normal"
+	default:
+		return "low"
+	}
+}
@@ -0,0 +1,315 @@
+package notifications
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type memoryPushSubscriptions struct {
+	mu      sync.Mutex
+	subs    map[string][]PushSubscription
+	removed []string
+}
+
+func (m *memoryPushSubscriptions) Subscriptions(ctx context.Context, userID string) ([]PushSubscription, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.subs[userID], nil
+}
+
+func (m *memoryPushSubscriptions) RemoveSubscription(ctx context.Context, userID, endpoint string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.removed = append(m.removed, endpoint)
+	return nil
+}
+
+// pushClient is a browser-side subscription able to decrypt received messages.
+type pushClient struct {
+	private *ecdh.PrivateKey
+	auth    []byte
+}
+
+func newPushClient(t *testing.T) *pushClient {
+	t.Helper()
+	key, err := ecdh.P256().GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	auth := make([]byte, 16)
+	_, err = rand.Read(auth)
+	require.NoError(t, err)
+	return &pushClient{private: key, auth: auth}
+}
+
+func (c *pushClient) subscription(endpoint string) PushSubscription {
+	return PushSubscription{
+		Endpoint: endpoint,
+		Keys: PushSubscriptionKeys{
+			P256dh: base64.RawURLEncoding.EncodeToString(c.private.PublicKey().Bytes()),
+			Auth:   base64.RawURLEncoding.EncodeToString(c.auth),
+		},
+	}
+}
+
+// decrypt reverses the aes128gcm content coding as a user agent does (RFC 8291).
+func (c *pushClient) decrypt(t *testing.T, body []byte) []byte {
+	t.Helper()
+	require.Greater(t, len(body), 21)
+
+	salt := body[:16]
+	rs := binary.BigEndian.Uint32(body[16:20])
+	idLen := int(body[20])
+	asPublicBytes := body[21 : 21+idLen]
+	ciphertext := body[21+idLen:]
+	assert.Equal(t, uint32(4096), rs)
+
+	asPublic, err := ecdh.P256().NewPublicKey(asPublicBytes)
+	require.NoError(t, err)
+	secret, err := c.private.ECDH(asPublic)
+	require.NoError(t, err)
+
+	info := append([]byte("WebPush: info\x00"), c.private.PublicKey().Bytes()...)
+	info = append(info, asPublicBytes...)
+	ikm, err := hkdf.Key(sha256.New, secret, c.auth, string(info), 32)
+	require.NoError(t, err)
+	cek, err := hkdf.Key(sha256.New, ikm, salt, "Content-Encoding: aes128gcm\x00", 16)
+	require.NoError(t, err)
+	nonce, err := hkdf.Key(sha256.New, ikm, salt, "Content-Encoding: nonce\x00", 12)
+	require.NoError(t, err)
+
+	block, err := aes.NewCipher(cek)
+	require.NoError(t, err)
+	gcm, err := cipher.NewGCM(block)
+	require.NoError(t, err)
+
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	require.NoError(t, err)
+	require.Equal(t, byte(0x02), plaintext[len(plaintext)-1], "missing last record delimiter")
+	return plaintext[:len(plaintext)-1]
+}
+
+func generateVAPIDKeys(t *testing.T) (string, string) {
+	t.Helper()
+	key, err := ecdh.P256().GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	return base64.RawURLEncoding.EncodeToString(key.PublicKey().Bytes()),
+		base64.RawURLEncoding.EncodeToString(key.Bytes())
+}
+
+func verifyVAPIDHeader(t *testing.T, header, publicKey, audience string) {
+	t.Helper()
+	require.True(t, strings.HasPrefix(header, "vapid t="))
+	token, k, ok := strings.Cut(strings.TrimPrefix(header, "vapid t="), ", k=")
+	require.True(t, ok)
+	assert.Equal(t, publicKey, k)
+
+	parts := strings.Split(token, ".")
+	require.Len(t, parts, 3)
+
+	claimsJSON, err := base64.RawURLEncoding.DecodeString(parts[1])
+	require.NoError(t, err)
+	var claims struct {
+		Aud string `json:"aud"`
+		Exp int64  `json:"exp"`
+		Sub string `json:"sub"`
+	}
+	require.NoError(t, json.Unmarshal(claimsJSON, &claims))
+	assert.Equal(t, audience, claims.Aud)
+	assert.Equal(t, "mailto:ops@example.com", claims.Sub)
+	assert.LessOrEqual(t, claims.Exp, time.Now().Add(24*time.Hour).Unix())
+
+	pub, err := base64.RawURLEncoding.DecodeString(publicKey)
+	require.NoError(t, err)
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	require.NoError(t, err)
+	require.Len(t, sig, 64)
+
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	ecdsaPub := &ecdsa.PublicKey{
+		Curve: elliptic.P256(),
+		X:     new(big.Int).SetBytes(pub[1:33]),
+		Y:     new(big.Int).SetBytes(pub[33:]),
+	}
+	assert.True(t, ecdsa.Verify(ecdsaPub, digest[:], new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:])))
+}
+
+func TestNewWebPushDeliverer(t *testing.T) {
+	t.Parallel()
+	pub, priv := generateVAPIDKeys(t)
+	otherPub, _ := generateVAPIDKeys(t)
+	store := &memoryPushSubscriptions{}
+
+	t.Run("valid keys", func(t *testing.T) {
+		t.Parallel()
+		d, err := NewWebPushDeliverer(pub, priv, "mailto:ops@example.com", store)
+		require.NoError(t, err)
+		assert.NotNil(t, d)
+	})
+
+	t.Run("mismatched keys", func(t *testing.T) {
+		t.Parallel()
+		_, err := NewWebPushDeliverer(otherPub, priv, "mailto:ops@example.com", store)
+		assert.ErrorIs(t, err, ErrInvalidVAPIDKeys)
+	})
+
+	t.Run("malformed private key", func(t *testing.T) {
+		t.Parallel()
+		_, err := NewWebPushDeliverer(pub, "not-a-key!", "mailto:ops@example.com", store)
+		assert.ErrorIs(t, err, ErrInvalidVAPIDKeys)
+	})
+
+	t.Run("nil store", func(t *testing.T) {
+		t.Parallel()
+		d, err := NewWebPushDeliverer(pub, priv, "mailto:ops@example.com", nil)
+		assert.ErrorIs(t, err, ErrSubscriptionStoreNil)
+		assert.Nil(t, d)
+	})
+}
+
+func TestWebPushDeliverer_Deliver(t *testing.T) {
+	t.Parallel()
+	pub, priv := generateVAPIDKeys(t)
+
+	t.Run("encrypts payload and signs request", func(t *testing.T) {
+		t.Parallel()
+		client := newPushClient(t)
+
+		var (
+			gotBody   []byte
+			gotHeader http.Header
+		)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			gotBody, _ = io.ReadAll(r.Body)
+			gotHeader = r.Header.Clone()
+			w.WriteHeader(http.StatusCreated)
+		}))
+		defer server.Close()
+
+		store := &memoryPushSubscriptions{subs: map[string][]PushSubscription{
+			"user1": {client.subscription(server.URL + "/push/abc")},
+		}}
+		d, err := NewWebPushDeliverer(pub, priv, "mailto:ops@example.com", store, WithPushTTL(time.Hour))
+		require.NoError(t, err)
+
+		notif := Notification{ID: "n1", UserID: "user1", Priority: PriorityUrgent, Title: "Payment failed", Message: "Update your card"}
+		require.NoError(t, d.Deliver(context.Background(), notif))
+
+		assert.Equal(t, "aes128gcm", gotHeader.Get("Content-Encoding"))
+		assert.Equal(t, "3600", gotHeader.Get("TTL"))
+		assert.Equal(t, "high", gotHeader.Get("Urgency"))
+		verifyVAPIDHeader(t, gotHeader.Get("Authorization"), pub, server.URL)
+
+		var received Notification
+		require.NoError(t, json.Unmarshal(client.decrypt(t, gotBody), &received))
+		assert.Equal(t, "n1", received.ID)
+		assert.Equal(t, "Payment failed", received.Title)
+	})
+
+	t.Run("skips notifications below minimum priority", func(t *testing.T) {
+		t.Parallel()
+		var calls int
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			w.WriteHeader(http.StatusCreated)
+		}))
+		defer server.Close()
+
+		store := &memoryPushSubscriptions{subs: map[string][]PushSubscription{
+			"user1": {newPushClient(t).subscription(server.URL)},
+		}}
+		d, err := NewWebPushDeliverer(pub, priv, "mailto:ops@example.com", store)
+		require.NoError(t, err)
+
+		require.NoError(t, d.Deliver(context.Background(), Notification{UserID: "user1", Priority: PriorityNormal}))
+		assert.Zero(t, calls)
+
+		d, err = NewWebPushDeliverer(pub, priv, "mailto:ops@example.com", store, WithMinPushPriority(PriorityLow))
+		require.NoError(t, err)
+		require.NoError(t, d.Deliver(context.Background(), Notification{UserID: "user1", Priority: PriorityNormal}))
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("removes gone subscriptions", func(t *testing.T) {
+		t.Parallel()
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if strings.HasSuffix(r.URL.Path, "/gone") {
+				w.WriteHeader(http.StatusGone)
+				return
+			}
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		defer server.Close()
+
+		store := &memoryPushSubscriptions{subs: map[string][]PushSubscription{
+			"user1": {
+				newPushClient(t).subscription(server.URL + "/gone"),
+				newPushClient(t).subscription(server.URL + "/missing"),
+			},
+		}}
+		d, err := NewWebPushDeliverer(pub, priv, "mailto:ops@example.com", store)
+		require.NoError(t, err)
+
+		require.NoError(t, d.Deliver(context.Background(), Notification{UserID: "user1", Priority: PriorityHigh}))
+		assert.ElementsMatch(t, []string{server.URL + "/gone", server.URL + "/missing"}, store.removed)
+	})
+
+	t.Run("returns error on push service failure", func(t *testing.T) {
+		t.Parallel()
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusTooManyRequests)
+		}))
+		defer server.Close()
+
+		store := &memoryPushSubscriptions{subs: map[string][]PushSubscription{
+			"user1": {newPushClient(t).subscription(server.URL)},
+		}}
+		d, err := NewWebPushDeliverer(pub, priv, "mailto:ops@example.com", store)
+		require.NoError(t, err)
+
+		err = d.Deliver(context.Background(), Notification{UserID: "user1", Priority: PriorityHigh})
+		assert.ErrorIs(t, err, ErrPushDeliveryFailed)
+		assert.Empty(t, store.removed)
+	})
+
+	t.Run("rejects oversized payload", func(t *testing.T) {
+		t.Parallel()
+		store := &memoryPushSubscriptions{}
+		d, err := NewWebPushDeliverer(pub, priv, "mailto:ops@example.com", store)
+		require.NoError(t, err)
+
+		err = d.Deliver(context.Background(), Notification{UserID: "user1", Priority: PriorityHigh, Message: strings.Repeat("x", 5000)})
+		assert.ErrorIs(t, err, ErrPushPayloadTooLarge)
+	})
+
+	t.Run("rejects invalid subscription keys", func(t *testing.T) {
+		t.Parallel()
+		store := &memoryPushSubscriptions{subs: map[string][]PushSubscription{
+			"user1": {{Endpoint: "https://push.example.com/x", Keys: PushSubscriptionKeys{P256dh: "bad", Auth: "bad"}}},
+		}}
+		d, err := NewWebPushDeliverer(pub, priv, "mailto:ops@example.com", store)
+		require.NoError(t, err)
+
+		err = d.Deliver(context.Background(), Notification{UserID: "user1", Priority: PriorityHigh})
+		assert.ErrorIs(t, err, ErrInvalidSubscription)
+	})
+}
@@ -16,6 +16,10 @@
 //   - Healthcheck – returns a function suitable for liveness / readiness probes
 //     (for example in HTTP /health endpoints).
 //
+//   - EnsureIndex, PutAlias, Reindex – schema management helpers for creating
+//     indices with mappings, detecting mapping drift and moving aliases for
+//     zero-downtime reindexing.
+//
 // Errors specific to connectivity are exposed as ErrConnectionFailed and
 // ErrHealthcheckFailed so that callers can distinguish infrastructure problems
 // from business logic errors.
@@ -51,6 +55,17 @@
 //	cfg, _ := config.Load[opensearch.Config]()
 //	client, _ := opensearch.New(context.Background(), cfg)
 //
+// # Index Management
+//
+//	mapping := json.RawMessage(`{"properties":{"title":{"type":"text"}}}`)
+//	if err := opensearch.EnsureIndex(ctx, client, "articles_v2", mapping,
+//	    opensearch.WithMappingVerification()); err != nil {
+//	    // errors.Is(err, opensearch.ErrMappingDrift) means the index must be migrated
+//	}
+//
+//	_ = opensearch.Reindex(ctx, client, "articles_v1", "articles_v2")
+//	_ = opensearch.PutAlias(ctx, client, "articles", "articles_v2")
+//
 // # Error Handling
 //
 // Use the standard errors.Is / errors.As helpers to check for sentinel errors:
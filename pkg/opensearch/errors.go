@@ -10,4 +10,11 @@ var (
 	// ErrHealthcheckFailed indicates the cluster is unreachable or unhealthy.
 	// Returned by both New() during initialization and Healthcheck() during monitoring.
 	ErrHealthcheckFailed = errors.New("opensearch healthcheck failed")
+
+	// ErrIndexOperationFailed indicates an index, alias or reindex request was rejected.
+	ErrIndexOperationFailed = errors.New("opensearch index operation failed")
+
+	// ErrMappingDrift indicates an existing index mapping doesn't match the expected one.
+	// Returned by EnsureIndex when WithMappingVerification is used.
+	ErrMappingDrift = errors.New("opensearch index mapping drift")
 )
@@ -0,0 +1,294 @@
+package opensearch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/opensearch-project/opensearch-go/v2"
+	"github.com/opensearch-project/opensearch-go/v2/opensearchapi"
+)
+
+// IndexOption configures EnsureIndex.
+type IndexOption func(*indexConfig)
+
+type indexConfig struct {
+	settings     json.RawMessage
+	verifyMapped bool
+}
+
+// WithIndexSettings sets the index settings (shards, analyzers, etc.) used on creation.
+func WithIndexSettings(settings json.RawMessage) IndexOption {
+	return func(c *indexConfig) {
+		c.settings = settings
+	}
+}
+
+// WithMappingVerification makes EnsureIndex compare the mapping of an existing
+// index against the expected one and return ErrMappingDrift on mismatch.
+// Fields present only in the existing index (e.g. dynamically mapped) are allowed.
+func WithMappingVerification() IndexOption {
+	return func(c *indexConfig) {
+		c.verifyMapped = true
+	}
+}
+
+// EnsureIndex creates the index with the given mapping if it doesn't exist and
+// is a no-op otherwise. The mapping is the body of "mappings", e.g.
+// {"properties":{"title":{"type":"text"}}}.
+//
+// Combine with PutAlias and Reindex for zero-downtime mapping changes: create a
+// new versioned index, reindex into it, then move the alias.
+func EnsureIndex(ctx context.Context, client *opensearch.Client, name string, mapping json.RawMessage, opts ...IndexOption) error {
+	cfg := &indexConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	exists, err := indexExists(ctx, client, name)
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		return createIndex(ctx, client, name, mapping, cfg.settings)
+	}
+
+	if cfg.verifyMapped && len(mapping) > 0 {
+		return verifyMapping(ctx, client, name, mapping)
+	}
+	return nil
+}
+
+// PutAlias points alias at index, atomically removing it from any other
+// indices it currently references, so readers never see a missing alias.
+func PutAlias(ctx context.Context, client *opensearch.Client, alias, index string) error {
+	current, err := aliasIndices(ctx, client, alias)
+	if err != nil {
+		return err
+	}
+
+	actions := make([]map[string]any, 0, len(current)+1)
+	for _, idx := range current {
+		if idx != index {
+			actions = append(actions, map[string]any{"remove": map[string]string{"index": idx, "alias": alias}})
+		}
+	}
+	actions = append(actions, map[string]any{"add": map[string]string{"index": index, "alias": alias}})
+
+	body, err := json.Marshal(map[string]any{"actions": actions})
+	if err != nil {
+		return errors.Join(ErrIndexOperationFailed, err)
+	}
+
+	res, err := client.Indices.UpdateAliases(bytes.NewReader(body),
+		client.Indices.UpdateAliases.WithContext(ctx),
+	)
+	if err != nil {
+		return errors.Join(ErrIndexOperationFailed, err)
+	}
+	defer res.Body.Close()
+
+	return responseError(res, "put alias "+alias)
+}
+
+// Reindex copies all documents from src into dst and waits for completion.
+// The destination index should be created with EnsureIndex beforehand so it
+// gets the intended mapping instead of a dynamic one.
+func Reindex(ctx context.Context, client *opensearch.Client, src, dst string) error {
+	body, err := json.Marshal(map[string]any{
+		"source": map[string]string{"index": src},
+		"dest":   map[string]string{"index": dst},
+	})
+	if err != nil {
+		return errors.Join(ErrIndexOperationFailed, err)
+	}
+
+	res, err := client.Reindex(bytes.NewReader(body),
+		client.Reindex.WithContext(ctx),
+		client.Reindex.WithWaitForCompletion(true),
+		client.Reindex.WithRefresh(true),
+	)
+	if err != nil {
+		return errors.Join(ErrIndexOperationFailed, err)
+	}
+	defer res.Body.Close()
+
+	if err := responseError(res, "reindex "+src+" to "+dst); err != nil {
+		return err
+	}
+
+	var result struct {
+		Failures []json.RawMessage `json:"failures"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&result); err != nil {
+		return errors.Join(ErrIndexOperationFailed, err)
+	}
+	if len(result.Failures) > 0 {
+		return fmt.Errorf("%w: reindex %s to %s: %d document failures, first: %s",
+			ErrIndexOperationFailed, src, dst, len(result.Failures), result.Failures[0])
+	}
+	return nil
+}
+
+func indexExists(ctx context.Context, client *opensearch.Client, name string) (bool, error) {
+	res, err := client.Indices.Exists([]string{name}, client.Indices.Exists.WithContext(ctx))
+	if err != nil {
+		return false, errors.Join(ErrIndexOperationFailed, err)
+	}
+	defer res.Body.Close()
+
+	switch res.StatusCode {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusNotFound:
+		return false, nil
+	default:
+		return false, responseError(res, "check index "+name)
+	}
+}
+
+func createIndex(ctx context.Context, client *opensearch.Client, name string, mapping, settings json.RawMessage) error {
+	payload := map[string]json.RawMessage{}
+	if len(mapping) > 0 {
+		payload["mappings"] = mapping
+	}
+	if len(settings) > 0 {
+		payload["settings"] = settings
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return errors.Join(ErrIndexOperationFailed, err)
+	}
+
+	res, err := client.Indices.Create(name,
+		client.Indices.Create.WithContext(ctx),
+		client.Indices.Create.WithBody(bytes.NewReader(body)),
+	)
+	if err != nil {
+		return errors.Join(ErrIndexOperationFailed, err)
+	}
+	defer res.Body.Close()
+
+	// Another instance may have created the index concurrently
+	if res.StatusCode == http.StatusBadRequest {
+		raw, _ := io.ReadAll(res.Body)
+		if bytes.Contains(raw, []byte("resource_already_exists_exception")) {
+			return nil
+		}
+		return fmt.Errorf("%w: create index %s: status %d: %s", ErrIndexOperationFailed, name, res.StatusCode, raw)
+	}
+
+	return responseError(res, "create index "+name)
+}
+
+func verifyMapping(ctx context.Context, client *opensearch.Client, name string, expected json.RawMessage) error {
+	res, err := client.Indices.GetMapping(
+		client.Indices.GetMapping.WithContext(ctx),
+		client.Indices.GetMapping.WithIndex(name),
+	)
+	if err != nil {
+		return errors.Join(ErrIndexOperationFailed, err)
+	}
+	defer res.Body.Close()
+
+	if err := responseError(res, "get mapping "+name); err != nil {
+		return err
+	}
+
+	var result map[string]struct {
+		Mappings map[string]any `json:"mappings"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&result); err != nil {
+		return errors.Join(ErrIndexOperationFailed, err)
+	}
+
+	var want map[string]any
+	if err := json.Unmarshal(expected, &want); err != nil {
+		return errors.Join(ErrIndexOperationFailed, err)
+	}
+
+	// The response is keyed by the concrete index name, which differs when name is an alias
+	for _, idx := range result {
+		if path, ok := mappingSubset(want, idx.Mappings, ""); !ok {
+			return fmt.Errorf("%w: index %s differs at %q", ErrMappingDrift, name, path)
+		}
+	}
+	return nil
+}
+
+// mappingSubset reports whether every field in want exists in got with the
+// same definition, returning the path of the first mismatch.
+func mappingSubset(want, got map[string]any, path string) (string, bool) {
+	keys := make([]string, 0, len(want))
+	for k := range want {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+
+	for _, k := range keys {
+		p := strings.TrimPrefix(path+"."+k, ".")
+		gv, ok := got[k]
+		if !ok {
+			return p, false
+		}
+
+		wm, wIsMap := want[k].(map[string]any)
+		gm, gIsMap := gv.(map[string]any)
+		switch {
+		case wIsMap && gIsMap:
+			if mp, ok := mappingSubset(wm, gm, p); !ok {
+				return mp, false
+			}
+		case !reflect.DeepEqual(want[k], gv):
+			return p, false
+		}
+	}
+	return "", true
+}
+
+func aliasIndices(ctx context.Context, client *opensearch.Client, alias string) ([]string, error) {
+	res, err := client.Indices.GetAlias(
+		client.Indices.GetAlias.WithContext(ctx),
+		client.Indices.GetAlias.WithName(alias),
+	)
+	if err != nil {
+		return nil, errors.Join(ErrIndexOperationFailed, err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if err := responseError(res, "get alias "+alias); err != nil {
+		return nil, err
+	}
+
+	var result map[string]json.RawMessage
+	if err := json.NewDecoder(res.Body).Decode(&result); err != nil {
+		return nil, errors.Join(ErrIndexOperationFailed, err)
+	}
+
+	indices := make([]string, 0, len(result))
+	for idx := range result {
+		indices = append(indices, idx)
+	}
+	slices.Sort(indices)
+	return indices, nil
+}
+
+// responseError converts a non-2xx response into ErrIndexOperationFailed with the server's reason.
+func responseError(res *opensearchapi.Response, op string) error {
+	if !res.IsError() {
+		return nil
+	}
+	raw, _ := io.ReadAll(res.Body)
+	return fmt.Errorf("%w: %s: status %d: %s", ErrIndexOperationFailed, op, res.StatusCode, bytes.TrimSpace(raw))
+}
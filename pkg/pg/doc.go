@@ -69,6 +69,34 @@
 // they can be tuned per-environment without code changes. Refer to the field
 // tags in Config for exact variable names and defaults.
 //
+// # Querying
+//
+// QueryRows and QueryRow scan results into structs by column name, matching
+// the `db` struct tag or, without a tag, the field name case-insensitively:
+//
+//	type User struct {
+//	    ID    uuid.UUID `db:"id"`
+//	    Email string    `db:"email"`
+//	}
+//
+//	user, err := pg.QueryRow[User](ctx, pool, "SELECT id, email FROM users WHERE id = $1", id)
+//	if errors.Is(err, pg.ErrNotFound) { ... }
+//
+// # Soft Deletes and Optimistic Locking
+//
+// SoftDelete sets a row's deleted_at column instead of removing it, and NotDeleted
+// returns the matching filter for queries. UpdateWithVersion applies an update only
+// while the row's version column is unchanged and increments it, returning
+// ErrStaleVersion on concurrent modification:
+//
+//	err := pg.SoftDelete(ctx, pool, "users", id)
+//
+//	sql := "SELECT id, email FROM users WHERE " + pg.NotDeleted("")
+//
+//	version, err := pg.UpdateWithVersion(ctx, pool, "documents", id, doc.Version,
+//	    map[string]any{"title": title})
+//	if errors.Is(err, pg.ErrStaleVersion) { ... }
+//
 // # Error Handling
 //
 // Convenience helpers such as [pg.IsDuplicateKeyError] or
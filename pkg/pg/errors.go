@@ -15,6 +15,9 @@ var (
 	ErrFailedToApplyMigrations  = errors.New("failed to apply migrations")
 	ErrMigrationsDirNotFound    = errors.New("migrations directory not found")
 	ErrMigrationPathNotProvided = errors.New("migration path not provided")
+	ErrNotFound                 = errors.New("record not found")
+	ErrStaleVersion             = errors.New("record was modified concurrently")
+	ErrInvalidInput             = errors.New("invalid input")
 )
 
 // IsNotFoundError detects pgx.ErrNoRows for consistent "not found" handling across queries.
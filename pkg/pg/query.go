@@ -0,0 +1,44 @@
+package pg
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Querier is satisfied by *pgxpool.Pool, *pgx.Conn and pgx.Tx, so the query
+// helpers work both inside and outside transactions.
+type Querier interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+}
+
+// QueryRows runs the query and scans every row into T by column name.
+// Columns map to fields through the `db` struct tag, falling back to a
+// case-insensitive match on the field name; `db:"-"` skips a field.
+// Every column must have a matching field and vice versa, so select exactly
+// the columns the struct declares. Returns an empty slice when nothing matches.
+func QueryRows[T any](ctx context.Context, q Querier, sql string, args ...any) ([]T, error) {
+	rows, err := q.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowToStructByName[T])
+}
+
+// QueryRow runs the query and scans the first row into T using the same
+// column mapping as QueryRows. Returns ErrNotFound when the query yields no rows.
+func QueryRow[T any](ctx context.Context, q Querier, sql string, args ...any) (T, error) {
+	rows, err := q.Query(ctx, sql, args...)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+
+	row, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[T])
+	if errors.Is(err, pgx.ErrNoRows) {
+		// Joined so IsNotFoundError keeps working for callers checking pgx.ErrNoRows
+		return row, errors.Join(ErrNotFound, err)
+	}
+	return row, err
+}
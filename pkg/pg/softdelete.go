@@ -0,0 +1,45 @@
+package pg
+
+import (
+	"context"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Execer is satisfied by *pgxpool.Pool, *pgx.Conn and pgx.Tx.
+type Execer interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+}
+
+// SoftDelete marks the row with the given id as deleted by setting its deleted_at
+// column to now(). The table needs `id` and nullable `deleted_at` columns.
+// Returns ErrNotFound when no live row matches, including rows already deleted.
+func SoftDelete(ctx context.Context, e Execer, table string, id any) error {
+	sql := "UPDATE " + quoteIdent(table) + " SET deleted_at = now() WHERE id = $1 AND deleted_at IS NULL"
+	tag, err := e.Exec(ctx, sql, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// NotDeleted returns a WHERE condition that excludes soft-deleted rows.
+// Pass a table name or alias to qualify the column in joins, or an empty string.
+//
+//	sql := "SELECT id, email FROM users u WHERE u.tenant_id = $1 AND " + pg.NotDeleted("u")
+func NotDeleted(table string) string {
+	if table == "" {
+		return "deleted_at IS NULL"
+	}
+	return quoteIdent(table) + ".deleted_at IS NULL"
+}
+
+// quoteIdent quotes a possibly schema-qualified identifier such as "billing.invoices".
+func quoteIdent(name string) string {
+	return pgx.Identifier(strings.Split(name, ".")).Sanitize()
+}
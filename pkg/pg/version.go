@@ -0,0 +1,87 @@
+package pg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// UpdateWithVersion updates fields of the row with the given id using optimistic locking.
+// The update only applies while the row's `version` column still equals version, and
+// increments it. Returns the new version, ErrStaleVersion when the row was modified
+// concurrently, or ErrNotFound when the row doesn't exist.
+//
+// fields must not be empty and can't set `id` or `version`; such calls fail with
+// ErrInvalidInput before any query runs.
+//
+//	newVersion, err := pg.UpdateWithVersion(ctx, pool, "documents", doc.ID, doc.Version,
+//	    map[string]any{"title": title, "updated_at": time.Now()})
+//	if errors.Is(err, pg.ErrStaleVersion) {
+//	    // reload and retry, or report a conflict
+//	}
+func UpdateWithVersion(ctx context.Context, q Querier, table string, id any, version int64, fields map[string]any) (int64, error) {
+	if len(fields) == 0 {
+		return 0, errors.Join(ErrInvalidInput, errors.New("no fields to update"))
+	}
+	for _, column := range []string{"id", "version"} {
+		if _, ok := fields[column]; ok {
+			return 0, errors.Join(ErrInvalidInput, fmt.Errorf("field %q can't be updated with UpdateWithVersion", column))
+		}
+	}
+
+	// Sorted for a stable statement text, so prepared statement caching works
+	columns := make([]string, 0, len(fields))
+	for column := range fields {
+		columns = append(columns, column)
+	}
+	slices.Sort(columns)
+
+	args := make([]any, 0, len(columns)+2)
+	args = append(args, id, version)
+
+	var sql strings.Builder
+	sql.WriteString("UPDATE ")
+	sql.WriteString(quoteIdent(table))
+	sql.WriteString(" SET ")
+	for _, column := range columns {
+		args = append(args, fields[column])
+		sql.WriteString(pgx.Identifier{column}.Sanitize())
+		sql.WriteString(" = $")
+		sql.WriteString(strconv.Itoa(len(args)))
+		sql.WriteString(", ")
+	}
+	sql.WriteString("version = version + 1 WHERE id = $1 AND version = $2 RETURNING version")
+
+	newVersion, err := queryScalar[int64](ctx, q, sql.String(), args...)
+	if err == nil {
+		return newVersion, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return 0, err
+	}
+
+	// No row matched: tell a missing row apart from a concurrent modification
+	exists, err := queryScalar[bool](ctx, q, "SELECT EXISTS (SELECT 1 FROM "+quoteIdent(table)+" WHERE id = $1)", id)
+	if err != nil {
+		return 0, err
+	}
+	if !exists {
+		return 0, ErrNotFound
+	}
+	return 0, ErrStaleVersion
+}
+
+// queryScalar runs a query returning a single column and scans the first row.
+func queryScalar[T any](ctx context.Context, q Querier, sql string, args ...any) (T, error) {
+	rows, err := q.Query(ctx, sql, args...)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return pgx.CollectOneRow(rows, pgx.RowTo[T])
+}
@@ -0,0 +1,31 @@
+package pg_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dmitrymomot/saaskit/pkg/pg"
+)
+
+func TestUpdateWithVersion_InvalidFields(t *testing.T) {
+	t.Parallel()
+
+	// Rejected before a query runs, so no connection is needed
+	tests := map[string]map[string]any{
+		"empty":   {},
+		"nil":     nil,
+		"version": {"title": "new", "version": int64(7)},
+		"id":      {"title": "new", "id": 2},
+	}
+
+	for name, fields := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := pg.UpdateWithVersion(context.Background(), nil, "documents", 1, 1, fields)
+			assert.ErrorIs(t, err, pg.ErrInvalidInput)
+		})
+	}
+}
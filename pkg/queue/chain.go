@@ -0,0 +1,74 @@
+package queue
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// TaskSpec describes one step of a task chain
+type TaskSpec struct {
+	Payload any
+	Options []EnqueueOption
+}
+
+// EnqueueAfter adds a task that can only be claimed once the dependsOn task has completed.
+// If the dependency fails permanently, the task is failed or skipped according to
+// WithOnDependencyFailure. Returns the ID of the new task so further steps can depend on it.
+func (e *Enqueuer) EnqueueAfter(ctx context.Context, payload any, dependsOn uuid.UUID, opts ...EnqueueOption) (uuid.UUID, error) {
+	if dependsOn == uuid.Nil {
+		return uuid.Nil, ErrInvalidDependency
+	}
+
+	task, err := e.prepareTask(payload, opts)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	dependOn(task, dependsOn)
+
+	if err := e.createTask(ctx, task); err != nil {
+		return uuid.Nil, err
+	}
+
+	return task.ID, nil
+}
+
+// EnqueueChain adds tasks that run one after another: each task depends on the previous
+// one, so a failure stops the rest of the chain. Returns task IDs in chain order.
+//
+// Tasks are validated up front but stored one by one, so if storing fails midway
+// the already stored head of the chain is kept and will run.
+func (e *Enqueuer) EnqueueChain(ctx context.Context, tasks ...TaskSpec) ([]uuid.UUID, error) {
+	if len(tasks) == 0 {
+		return nil, ErrNoItemsToEnqueue
+	}
+
+	built := make([]*Task, len(tasks))
+	for i, spec := range tasks {
+		task, err := e.prepareTask(spec.Payload, spec.Options)
+		if err != nil {
+			return nil, err
+		}
+		if i > 0 {
+			dependOn(task, built[i-1].ID)
+		}
+		built[i] = task
+	}
+
+	ids := make([]uuid.UUID, 0, len(built))
+	for _, task := range built {
+		if err := e.createTask(ctx, task); err != nil {
+			return ids, err
+		}
+		ids = append(ids, task.ID)
+	}
+
+	return ids, nil
+}
+
+func dependOn(task *Task, dependsOn uuid.UUID) {
+	task.DependsOn = &dependsOn
+	if task.OnDependencyFailure == "" {
+		task.OnDependencyFailure = CascadeFail
+	}
+}
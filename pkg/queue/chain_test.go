@@ -0,0 +1,190 @@
+package queue_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dmitrymomot/saaskit/pkg/queue"
+)
+
+type chainStepPayload struct {
+	Step int `json:"step"`
+}
+
+func TestEnqueuer_EnqueueChain(t *testing.T) {
+	t.Parallel()
+
+	t.Run("links tasks in order", func(t *testing.T) {
+		t.Parallel()
+
+		repo := &mockEnqueuerRepo{}
+		enqueuer, err := queue.NewEnqueuer(repo)
+		require.NoError(t, err)
+
+		ids, err := enqueuer.EnqueueChain(context.Background(),
+			queue.TaskSpec{Payload: chainStepPayload{Step: 1}},
+			queue.TaskSpec{Payload: chainStepPayload{Step: 2}, Options: []queue.EnqueueOption{queue.WithOnDependencyFailure(queue.CascadeSkip)}},
+			queue.TaskSpec{Payload: chainStepPayload{Step: 3}, Options: []queue.EnqueueOption{queue.WithQueue("reports")}},
+		)
+		require.NoError(t, err)
+		require.Len(t, ids, 3)
+		require.Len(t, repo.tasks, 3)
+
+		assert.Nil(t, repo.tasks[0].DependsOn)
+		assert.Equal(t, ids[0], *repo.tasks[1].DependsOn)
+		assert.Equal(t, queue.CascadeSkip, repo.tasks[1].OnDependencyFailure)
+		assert.Equal(t, ids[1], *repo.tasks[2].DependsOn)
+		assert.Equal(t, queue.CascadeFail, repo.tasks[2].OnDependencyFailure)
+		assert.Equal(t, "reports", repo.tasks[2].Queue)
+	})
+
+	t.Run("validates all tasks before storing", func(t *testing.T) {
+		t.Parallel()
+
+		repo := &mockEnqueuerRepo{}
+		enqueuer, err := queue.NewEnqueuer(repo)
+		require.NoError(t, err)
+
+		_, err = enqueuer.EnqueueChain(context.Background(),
+			queue.TaskSpec{Payload: chainStepPayload{Step: 1}},
+			queue.TaskSpec{Payload: nil},
+		)
+		assert.ErrorIs(t, err, queue.ErrPayloadNil)
+		assert.Empty(t, repo.tasks)
+	})
+
+	t.Run("empty chain", func(t *testing.T) {
+		t.Parallel()
+
+		enqueuer, err := queue.NewEnqueuer(&mockEnqueuerRepo{})
+		require.NoError(t, err)
+
+		_, err = enqueuer.EnqueueChain(context.Background())
+		assert.ErrorIs(t, err, queue.ErrNoItemsToEnqueue)
+	})
+}
+
+func TestEnqueuer_EnqueueAfter(t *testing.T) {
+	t.Parallel()
+
+	repo := &mockEnqueuerRepo{}
+	enqueuer, err := queue.NewEnqueuer(repo)
+	require.NoError(t, err)
+
+	parent := uuid.New()
+	id, err := enqueuer.EnqueueAfter(context.Background(), chainStepPayload{Step: 2}, parent)
+	require.NoError(t, err)
+	require.Len(t, repo.tasks, 1)
+	assert.Equal(t, id, repo.tasks[0].ID)
+	assert.Equal(t, parent, *repo.tasks[0].DependsOn)
+
+	_, err = enqueuer.EnqueueAfter(context.Background(), chainStepPayload{Step: 2}, uuid.Nil)
+	assert.ErrorIs(t, err, queue.ErrInvalidDependency)
+}
+
+func TestMemoryStorage_Chain(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	workerID := uuid.New()
+	queues := []string{queue.DefaultQueueName}
+
+	setup := func(t *testing.T) (*queue.MemoryStorage, *queue.Enqueuer) {
+		t.Helper()
+		storage := queue.NewMemoryStorage()
+		t.Cleanup(func() { _ = storage.Close() })
+		enqueuer, err := queue.NewEnqueuer(storage)
+		require.NoError(t, err)
+		return storage, enqueuer
+	}
+
+	t.Run("claims dependent only after dependency completes", func(t *testing.T) {
+		t.Parallel()
+
+		storage, enqueuer := setup(t)
+		ids, err := enqueuer.EnqueueChain(ctx,
+			queue.TaskSpec{Payload: chainStepPayload{Step: 1}, Options: []queue.EnqueueOption{queue.WithPriority(queue.PriorityMin)}},
+			queue.TaskSpec{Payload: chainStepPayload{Step: 2}, Options: []queue.EnqueueOption{queue.WithPriority(queue.PriorityMax)}},
+		)
+		require.NoError(t, err)
+
+		// The higher priority step 2 is not claimable yet
+		task, err := storage.ClaimTask(ctx, workerID, queues, time.Minute)
+		require.NoError(t, err)
+		assert.Equal(t, ids[0], task.ID)
+
+		_, err = storage.ClaimTask(ctx, workerID, queues, time.Minute)
+		assert.ErrorIs(t, err, queue.ErrNoTaskToClaim)
+
+		require.NoError(t, storage.CompleteTask(ctx, ids[0]))
+
+		task, err = storage.ClaimTask(ctx, workerID, queues, time.Minute)
+		require.NoError(t, err)
+		assert.Equal(t, ids[1], task.ID)
+	})
+
+	t.Run("cascades failure through the chain", func(t *testing.T) {
+		t.Parallel()
+
+		storage, enqueuer := setup(t)
+		ids, err := enqueuer.EnqueueChain(ctx,
+			queue.TaskSpec{Payload: chainStepPayload{Step: 1}, Options: []queue.EnqueueOption{queue.WithMaxRetries(0)}},
+			queue.TaskSpec{Payload: chainStepPayload{Step: 2}, Options: []queue.EnqueueOption{queue.WithOnDependencyFailure(queue.CascadeSkip)}},
+			queue.TaskSpec{Payload: chainStepPayload{Step: 3}},
+		)
+		require.NoError(t, err)
+
+		_, err = storage.ClaimTask(ctx, workerID, queues, time.Minute)
+		require.NoError(t, err)
+		require.NoError(t, storage.FailTask(ctx, ids[0], "boom"))
+		require.NoError(t, storage.MoveToDLQ(ctx, ids[0]))
+
+		skipped, err := storage.GetTask(ctx, ids[1])
+		require.NoError(t, err)
+		assert.Equal(t, queue.TaskStatusSkipped, skipped.Status)
+		require.NotNil(t, skipped.Error)
+		assert.Contains(t, *skipped.Error, ids[0].String())
+
+		// Step 3 fails by default, which moves it to the DLQ
+		_, err = storage.GetTask(ctx, ids[2])
+		assert.Error(t, err)
+
+		_, err = storage.ClaimTask(ctx, workerID, queues, time.Minute)
+		assert.ErrorIs(t, err, queue.ErrNoTaskToClaim)
+	})
+
+	t.Run("enqueue after a failed task cascades immediately", func(t *testing.T) {
+		t.Parallel()
+
+		storage, enqueuer := setup(t)
+		ids, err := enqueuer.EnqueueChain(ctx,
+			queue.TaskSpec{Payload: chainStepPayload{Step: 1}, Options: []queue.EnqueueOption{queue.WithMaxRetries(0)}},
+		)
+		require.NoError(t, err)
+
+		_, err = storage.ClaimTask(ctx, workerID, queues, time.Minute)
+		require.NoError(t, err)
+		require.NoError(t, storage.FailTask(ctx, ids[0], "boom"))
+		require.NoError(t, storage.MoveToDLQ(ctx, ids[0]))
+
+		id, err := enqueuer.EnqueueAfter(ctx, chainStepPayload{Step: 2}, ids[0], queue.WithOnDependencyFailure(queue.CascadeSkip))
+		require.NoError(t, err)
+
+		task, err := storage.GetTask(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, queue.TaskStatusSkipped, task.Status)
+	})
+
+	t.Run("rejects unknown dependency", func(t *testing.T) {
+		t.Parallel()
+
+		_, enqueuer := setup(t)
+		_, err := enqueuer.EnqueueAfter(ctx, chainStepPayload{Step: 2}, uuid.New())
+		assert.ErrorIs(t, err, queue.ErrInvalidDependency)
+	})
+}
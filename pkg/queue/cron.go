@@ -0,0 +1,262 @@
+package queue
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronSearchYears bounds the search for the next run time. Five years always
+// includes a leap year, so "0 0 29 2 *" is still found.
+const cronSearchYears = 5
+
+var cronDescriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+var (
+	monthNames = map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}
+	weekdayNames = map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}
+)
+
+type cronField struct {
+	name  string
+	min   int
+	max   int
+	names map[string]int
+}
+
+var cronFields = [5]cronField{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12, names: monthNames},
+	{name: "day of week", min: 0, max: 7, names: weekdayNames}, // 7 is an alias for Sunday
+}
+
+// cronSchedule runs at times matching a 5-field cron expression
+type cronSchedule struct {
+	expr    string
+	minute  uint64
+	hour    uint64
+	dom     uint64
+	month   uint64
+	dow     uint64
+	domStar bool
+	dowStar bool
+}
+
+// Cron parses a standard 5-field cron expression (minute hour day-of-month month day-of-week).
+//
+// Supported syntax: "*", values, ranges ("1-5"), steps ("*/15", "0-30/10", "5/10"),
+// lists ("1,15,30"), month and weekday names ("JAN", "MON-FRI") and the descriptors
+// @yearly, @annually, @monthly, @weekly, @daily, @midnight and @hourly. As in
+// standard cron, when both day-of-month and day-of-week are restricted, a time
+// matching either field runs. Times are evaluated in the location of the time
+// passed to Next.
+//
+// Invalid expressions and expressions that can never fire (e.g. "0 0 30 2 *")
+// return an error wrapping ErrInvalidSchedule.
+func Cron(expr string) (Schedule, error) {
+	spec := strings.TrimSpace(expr)
+	if d, ok := cronDescriptors[strings.ToLower(spec)]; ok {
+		spec = d
+	}
+
+	parts := strings.Fields(spec)
+	if len(parts) != len(cronFields) {
+		return nil, fmt.Errorf("%w: cron %q: expected 5 fields, got %d", ErrInvalidSchedule, expr, len(parts))
+	}
+
+	var bits [5]uint64
+	for i, part := range parts {
+		b, err := parseCronField(part, cronFields[i])
+		if err != nil {
+			return nil, fmt.Errorf("%w: cron %q: %s field: %w", ErrInvalidSchedule, expr, cronFields[i].name, err)
+		}
+		bits[i] = b
+	}
+
+	// Fold Sunday=7 into Sunday=0
+	if bits[4]&(1<<7) != 0 {
+		bits[4] = bits[4]&^(1<<7) | 1
+	}
+
+	s := cronSchedule{
+		expr:    strings.TrimSpace(expr),
+		minute:  bits[0],
+		hour:    bits[1],
+		dom:     bits[2],
+		month:   bits[3],
+		dow:     bits[4],
+		domStar: parts[2] == "*" || parts[2] == "?",
+		dowStar: parts[4] == "*" || parts[4] == "?",
+	}
+
+	probe := time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)
+	if s.Next(probe).IsZero() {
+		return nil, fmt.Errorf("%w: cron %q: never matches any date", ErrInvalidSchedule, expr)
+	}
+
+	return s, nil
+}
+
+// MustCron is like Cron but panics on an invalid expression.
+// Intended for package-level schedule definitions.
+func MustCron(expr string) Schedule {
+	s, err := Cron(expr)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// Next returns the first matching minute strictly after from,
+// or the zero time if none exists within the search window.
+//
+// Wall-clock times skipped by a DST transition never match, and times repeated
+// by one match on each occurrence.
+func (s cronSchedule) Next(from time.Time) time.Time {
+	loc := from.Location()
+	t := from.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(cronSearchYears, 0, 0)
+
+	for t.Before(limit) {
+		switch {
+		case s.month&(1<<uint(t.Month())) == 0:
+			t = advance(t, time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc))
+		case !s.dayMatches(t):
+			t = advance(t, time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc))
+		case s.hour&(1<<uint(t.Hour())) == 0:
+			// Step in absolute time: a wall-clock hour may not exist or may repeat
+			t = t.Add(time.Duration(60-t.Minute()) * time.Minute)
+		case s.minute&(1<<uint(t.Minute())) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+
+	return time.Time{}
+}
+
+// advance returns next if it is after t. A wall-clock candidate built with
+// time.Date can land at or before t around a DST transition; the search then
+// falls back to the next minute so it always makes progress.
+func advance(t, next time.Time) time.Time {
+	if next.After(t) {
+		return next
+	}
+	return t.Add(time.Minute)
+}
+
+func (s cronSchedule) String() string {
+	return fmt.Sprintf("cron %s", s.expr)
+}
+
+func (s cronSchedule) dayMatches(t time.Time) bool {
+	domMatch := s.dom&(1<<uint(t.Day())) != 0
+	dowMatch := s.dow&(1<<uint(t.Weekday())) != 0
+
+	switch {
+	case s.domStar && s.dowStar:
+		return true
+	case s.domStar:
+		return dowMatch
+	case s.dowStar:
+		return domMatch
+	default:
+		return domMatch || dowMatch
+	}
+}
+
+// parseCronField converts a comma-separated field into a bitset of allowed values.
+func parseCronField(field string, f cronField) (uint64, error) {
+	var bits uint64
+	for item := range strings.SplitSeq(field, ",") {
+		b, err := parseCronItem(item, f)
+		if err != nil {
+			return 0, err
+		}
+		bits |= b
+	}
+	return bits, nil
+}
+
+func parseCronItem(item string, f cronField) (uint64, error) {
+	if item == "" {
+		return 0, fmt.Errorf("empty list item")
+	}
+
+	rangePart, stepPart, hasStep := strings.Cut(item, "/")
+	step := 1
+	if hasStep {
+		n, err := strconv.Atoi(stepPart)
+		if err != nil || n <= 0 {
+			return 0, fmt.Errorf("invalid step %q", stepPart)
+		}
+		step = n
+	}
+
+	var lo, hi int
+	switch {
+	case rangePart == "*" || rangePart == "?":
+		lo, hi = f.min, f.max
+		if f.max == 7 {
+			hi = 6 // "*" in day-of-week must not count Sunday twice
+		}
+	case strings.Contains(rangePart, "-"):
+		loStr, hiStr, _ := strings.Cut(rangePart, "-")
+		var err error
+		if lo, err = parseCronValue(loStr, f); err != nil {
+			return 0, err
+		}
+		if hi, err = parseCronValue(hiStr, f); err != nil {
+			return 0, err
+		}
+		if lo > hi {
+			return 0, fmt.Errorf("invalid range %q: start after end", rangePart)
+		}
+	default:
+		v, err := parseCronValue(rangePart, f)
+		if err != nil {
+			return 0, err
+		}
+		lo, hi = v, v
+		if hasStep {
+			hi = f.max // "5/10" means from 5 to max every 10
+		}
+	}
+
+	var bits uint64
+	for v := lo; v <= hi; v += step {
+		bits |= 1 << uint(v)
+	}
+	return bits, nil
+}
+
+func parseCronValue(s string, f cronField) (int, error) {
+	if v, ok := f.names[strings.ToLower(s)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", s)
+	}
+	if v < f.min || v > f.max {
+		return 0, fmt.Errorf("value %d out of range %d-%d", v, f.min, f.max)
+	}
+	return v, nil
+}
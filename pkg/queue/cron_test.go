@@ -0,0 +1,137 @@
+package queue_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dmitrymomot/saaskit/pkg/queue"
+)
+
+func TestCron(t *testing.T) {
+	t.Parallel()
+
+	// Wednesday
+	base := time.Date(2024, time.January, 10, 10, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		expr string
+		from time.Time
+		want time.Time
+	}{
+		{"every minute", "* * * * *", base, time.Date(2024, 1, 10, 10, 31, 0, 0, time.UTC)},
+		{"every 15 minutes", "*/15 * * * *", base, time.Date(2024, 1, 10, 10, 45, 0, 0, time.UTC)},
+		{"every 6 hours on weekdays", "0 */6 * * MON-FRI", base, time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC)},
+		{"weekdays skip weekend", "0 9 * * mon-fri", time.Date(2024, 1, 12, 10, 0, 0, 0, time.UTC), time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC)},
+		{"list of minutes", "5,20,40 * * * *", base, time.Date(2024, 1, 10, 10, 40, 0, 0, time.UTC)},
+		{"range with step", "0-30/10 * * * *", base, time.Date(2024, 1, 10, 11, 0, 0, 0, time.UTC)},
+		{"start with step", "5/20 * * * *", base, time.Date(2024, 1, 10, 10, 45, 0, 0, time.UTC)},
+		{"named month", "0 0 1 JUN *", base, time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)},
+		{"sunday as 7", "0 0 * * 7", base, time.Date(2024, 1, 14, 0, 0, 0, 0, time.UTC)},
+		{"dom or dow", "0 0 15 * FRI", base, time.Date(2024, 1, 12, 0, 0, 0, 0, time.UTC)},
+		{"leap day", "0 0 29 2 *", base, time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"strictly after from", "30 10 * * *", base, time.Date(2024, 1, 11, 10, 30, 0, 0, time.UTC)},
+		{"year rollover", "0 0 1 1 *", base, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"daily descriptor", "@daily", base, time.Date(2024, 1, 11, 0, 0, 0, 0, time.UTC)},
+		{"hourly descriptor", "@hourly", base, time.Date(2024, 1, 10, 11, 0, 0, 0, time.UTC)},
+		{"weekly descriptor", "@weekly", base, time.Date(2024, 1, 14, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			s, err := queue.Cron(tt.expr)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, s.Next(tt.from))
+		})
+	}
+}
+
+func TestCronLocation(t *testing.T) {
+	t.Parallel()
+
+	loc := time.FixedZone("UTC+3", 3*60*60)
+	s, err := queue.Cron("0 9 * * *")
+	require.NoError(t, err)
+
+	next := s.Next(time.Date(2024, 1, 10, 10, 0, 0, 0, loc))
+	assert.Equal(t, time.Date(2024, 1, 11, 9, 0, 0, 0, loc), next)
+	assert.Equal(t, "cron 0 9 * * *", s.String())
+}
+
+func TestCronInvalid(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		expr string
+		msg  string
+	}{
+		{"", "expected 5 fields"},
+		{"* * * *", "expected 5 fields"},
+		{"60 * * * *", "minute field: value 60 out of range"},
+		{"* 24 * * *", "hour field"},
+		{"* * 0 * *", "day of month field"},
+		{"* * * 13 *", "month field"},
+		{"* * * * 8", "day of week field"},
+		{"*/0 * * * *", "invalid step"},
+		{"5-1 * * * *", "start after end"},
+		{"a * * * *", "invalid value"},
+		{"1,,2 * * * *", "empty list item"},
+		{"0 0 30 2 *", "never matches"},
+		{"@every 5m", "expected 5 fields"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			t.Parallel()
+			_, err := queue.Cron(tt.expr)
+			require.ErrorIs(t, err, queue.ErrInvalidSchedule)
+			assert.Contains(t, err.Error(), tt.msg)
+		})
+	}
+
+	assert.Panics(t, func() { queue.MustCron("bogus") })
+}
+
+func TestCronDST(t *testing.T) {
+	t.Parallel()
+
+	ny, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+
+	// 2024-03-10: 02:00 EST jumps to 03:00 EDT. 2024-11-03: 02:00 EDT falls back to 01:00 EST.
+	utc := func(month time.Month, day, hour, minute int) time.Time {
+		return time.Date(2024, month, day, hour, minute, 0, 0, time.UTC).In(ny)
+	}
+
+	tests := []struct {
+		name string
+		expr string
+		from time.Time
+		want time.Time
+	}{
+		{"spring forward: hour after gap", "0 3 * * *", time.Date(2024, 3, 10, 0, 30, 0, 0, ny), time.Date(2024, 3, 10, 3, 0, 0, 0, ny)},
+		{"spring forward: skipped time runs next day", "30 2 * * *", time.Date(2024, 3, 10, 0, 30, 0, 0, ny), time.Date(2024, 3, 11, 2, 30, 0, 0, ny)},
+		{"spring forward: hourly skips missing hour", "0 * * * *", time.Date(2024, 3, 10, 1, 59, 0, 0, ny), time.Date(2024, 3, 10, 3, 0, 0, 0, ny)},
+		{"spring forward: daily midnight", "0 0 * * *", time.Date(2024, 3, 9, 12, 0, 0, 0, ny), time.Date(2024, 3, 10, 0, 0, 0, 0, ny)},
+		{"fall back: hourly in first 01:xx", "0 * * * *", utc(time.November, 3, 5, 59), utc(time.November, 3, 6, 0)},
+		{"fall back: hourly in second 01:xx", "0 * * * *", utc(time.November, 3, 6, 59), utc(time.November, 3, 7, 0)},
+		{"fall back: repeated time runs again", "30 1 * * *", utc(time.November, 3, 5, 45), utc(time.November, 3, 6, 30)},
+		{"fall back: daily after repeated hour", "0 3 * * *", utc(time.November, 3, 6, 30), time.Date(2024, 11, 3, 3, 0, 0, 0, ny)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			s, err := queue.Cron(tt.expr)
+			require.NoError(t, err)
+
+			next := s.Next(tt.from)
+			assert.True(t, next.After(tt.from), "next %s must be after from %s", next, tt.from)
+			assert.True(t, tt.want.Equal(next), "want %s, got %s", tt.want, next)
+		})
+	}
+}
@@ -64,6 +64,26 @@
 //
 // go s.Start(context.Background())
 //
+// Cron expressions cover schedules the helpers can't express:
+//
+//	every6h, err := queue.Cron("0 */6 * * MON-FRI")
+//	if err != nil {
+//	    // errors.Is(err, queue.ErrInvalidSchedule)
+//	}
+//	_ = s.AddTask("sync_reports", every6h)
+//
+// Task chains run steps only after the previous one has completed. If a step
+// fails permanently, later steps are failed (CascadeFail, default) or skipped
+// (CascadeSkip) by the repository:
+//
+//	ids, err := e.EnqueueChain(ctx,
+//	    queue.TaskSpec{Payload: ExportPayload{}},
+//	    queue.TaskSpec{Payload: NotifyPayload{}, Options: []queue.EnqueueOption{
+//	        queue.WithOnDependencyFailure(queue.CascadeSkip),
+//	    }},
+//	)
+//	_, err = e.EnqueueAfter(ctx, AuditPayload{}, ids[0])
+//
 // # Error Handling
 //
 // Package-level sentinel errors (e.g. ErrInvalidPriority, ErrNoHandlers) signal
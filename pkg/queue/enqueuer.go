@@ -45,8 +45,18 @@ func NewEnqueuer(repo EnqueuerRepository, opts ...EnqueuerOption) (*Enqueuer, er
 
 // Enqueue adds a new task to the queue
 func (e *Enqueuer) Enqueue(ctx context.Context, payload any, opts ...EnqueueOption) error {
+	task, err := e.prepareTask(payload, opts)
+	if err != nil {
+		return err
+	}
+
+	return e.createTask(ctx, task)
+}
+
+// prepareTask applies defaults and options and builds a validated task
+func (e *Enqueuer) prepareTask(payload any, opts []EnqueueOption) (*Task, error) {
 	if payload == nil {
-		return ErrPayloadNil
+		return nil, ErrPayloadNil
 	}
 
 	// Apply default options
@@ -63,20 +73,17 @@ func (e *Enqueuer) Enqueue(ctx context.Context, payload any, opts ...EnqueueOpti
 
 	// Validate priority
 	if !options.priority.Valid() {
-		return ErrInvalidPriority
+		return nil, ErrInvalidPriority
 	}
 
-	// Build and store task
-	task, err := e.buildTask(payload, options)
-	if err != nil {
-		return err
-	}
+	return e.buildTask(payload, options)
+}
 
-	// Store task
+// createTask stores a built task
+func (e *Enqueuer) createTask(ctx context.Context, task *Task) error {
 	if err := e.repo.CreateTask(ctx, task); err != nil {
 		return fmt.Errorf("failed to create task %q in queue %q: %w", task.TaskName, task.Queue, err)
 	}
-
 	return nil
 }
 
@@ -114,5 +121,7 @@ func (e *Enqueuer) buildTask(payload any, options *enqueueOptions) (*Task, error
 		MaxRetries:  options.maxRetries,
 		ScheduledAt: scheduledAt,
 		CreatedAt:   time.Now(),
+
+		OnDependencyFailure: options.cascade,
 	}, nil
 }
@@ -38,6 +38,7 @@ type enqueueOptions struct {
 	delay       time.Duration
 	scheduledAt *time.Time
 	taskName    string
+	cascade     CascadePolicy
 }
 
 // WithQueue sets the queue for the task
@@ -90,3 +91,13 @@ func WithTaskName(name string) EnqueueOption {
 		}
 	}
 }
+
+// WithOnDependencyFailure sets what happens to a chained task when its dependency
+// fails permanently. Default is CascadeFail.
+func WithOnDependencyFailure(policy CascadePolicy) EnqueueOption {
+	return func(o *enqueueOptions) {
+		if policy == CascadeFail || policy == CascadeSkip {
+			o.cascade = policy
+		}
+	}
+}
@@ -19,4 +19,5 @@ var (
 	ErrFailedToUpdateTaskStatus = errors.New("failed to update task status")
 	ErrFailedToMoveToDLQ        = errors.New("failed to move task to dead letter queue")
 	ErrNoTaskToClaim            = errors.New("no task available to claim")
+	ErrInvalidDependency        = errors.New("invalid task dependency")
 )
@@ -64,6 +64,20 @@ func (ms *MemoryStorage) CreateTask(ctx context.Context, task *Task) error {
 		return fmt.Errorf("task with ID %s already exists", task.ID)
 	}
 
+	// A dependency that already failed cascades right away, an unknown one is rejected
+	var dependencyFailed bool
+	if task.DependsOn != nil {
+		dep, exists := ms.tasks[*task.DependsOn]
+		switch {
+		case exists:
+			dependencyFailed = dep.Status == TaskStatusSkipped
+		case ms.inDLQ(*task.DependsOn):
+			dependencyFailed = true
+		default:
+			return fmt.Errorf("%w: dependency task %s not found", ErrInvalidDependency, *task.DependsOn)
+		}
+	}
+
 	// Clone task to prevent external modifications
 	taskCopy := *task
 	ms.tasks[task.ID] = &taskCopy
@@ -72,9 +86,27 @@ func (ms *MemoryStorage) CreateTask(ctx context.Context, task *Task) error {
 	ms.byQueue[task.Queue] = append(ms.byQueue[task.Queue], task.ID)
 	ms.byStatus[task.Status] = append(ms.byStatus[task.Status], task.ID)
 
+	if dependencyFailed {
+		ms.cascadeFailure(*task.DependsOn)
+	}
+
 	return nil
 }
 
+// GetTask returns a copy of a task that is not in the dead letter queue
+func (ms *MemoryStorage) GetTask(ctx context.Context, taskID uuid.UUID) (*Task, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	task, exists := ms.tasks[taskID]
+	if !exists {
+		return nil, fmt.Errorf("task %s not found", taskID)
+	}
+
+	taskCopy := *task
+	return &taskCopy, nil
+}
+
 // ClaimTask implements WorkerRepository
 func (ms *MemoryStorage) ClaimTask(ctx context.Context, workerID uuid.UUID, queues []string, lockDuration time.Duration) (*Task, error) {
 	ms.mu.Lock()
@@ -104,6 +136,13 @@ func (ms *MemoryStorage) ClaimTask(ctx context.Context, workerID uuid.UUID, queu
 			continue
 		}
 
+		// Skip chained tasks until their dependency has completed
+		if task.DependsOn != nil {
+			if dep, exists := ms.tasks[*task.DependsOn]; !exists || dep.Status != TaskStatusCompleted {
+				continue
+			}
+		}
+
 		// Priority-first selection: higher priority wins, earliest creation time breaks ties
 		if bestTask == nil ||
 			task.Priority > bestPriority ||
@@ -207,6 +246,16 @@ func (ms *MemoryStorage) MoveToDLQ(ctx context.Context, taskID uuid.UUID) error
 		return fmt.Errorf("task %s not found", taskID)
 	}
 
+	ms.moveToDLQ(task)
+
+	// Tasks chained after this one can never run now
+	ms.cascadeFailure(taskID)
+
+	return nil
+}
+
+// moveToDLQ must be called while holding the mutex
+func (ms *MemoryStorage) moveToDLQ(task *Task) {
 	// Create DLQ entry
 	dlqEntry := &TasksDlq{
 		ID:         uuid.New(),
@@ -229,11 +278,49 @@ func (ms *MemoryStorage) MoveToDLQ(ctx context.Context, taskID uuid.UUID) error
 	ms.dlq[dlqEntry.ID] = dlqEntry
 
 	// Remove from main storage and indexes
-	ms.removeFromStatusIndex(taskID, task.Status)
-	ms.removeFromQueueIndex(taskID, task.Queue)
-	delete(ms.tasks, taskID)
+	ms.removeFromStatusIndex(task.ID, task.Status)
+	ms.removeFromQueueIndex(task.ID, task.Queue)
+	delete(ms.tasks, task.ID)
+}
 
-	return nil
+// cascadeFailure fails or skips pending tasks that depend on a permanently failed task,
+// following each dependent's OnDependencyFailure policy, and recurses down the chain.
+// Must be called while holding the mutex.
+func (ms *MemoryStorage) cascadeFailure(failedID uuid.UUID) {
+	// Copy since the pending index changes while cascading
+	for _, taskID := range slices.Clone(ms.byStatus[TaskStatusPending]) {
+		// Recursion may have already failed and removed tasks from the copy
+		task, exists := ms.tasks[taskID]
+		if !exists || task.Status != TaskStatusPending || task.DependsOn == nil || *task.DependsOn != failedID {
+			continue
+		}
+
+		errorMsg := fmt.Sprintf("dependency %s failed", failedID)
+		task.Error = &errorMsg
+		ms.removeFromStatusIndex(taskID, TaskStatusPending)
+
+		if task.OnDependencyFailure == CascadeSkip {
+			now := time.Now()
+			task.Status = TaskStatusSkipped
+			task.ProcessedAt = &now
+			ms.byStatus[TaskStatusSkipped] = append(ms.byStatus[TaskStatusSkipped], taskID)
+		} else {
+			task.Status = TaskStatusFailed
+			ms.byStatus[TaskStatusFailed] = append(ms.byStatus[TaskStatusFailed], taskID)
+			ms.moveToDLQ(task)
+		}
+
+		ms.cascadeFailure(taskID)
+	}
+}
+
+func (ms *MemoryStorage) inDLQ(taskID uuid.UUID) bool {
+	for _, entry := range ms.dlq {
+		if entry.TaskID == taskID {
+			return true
+		}
+	}
+	return false
 }
 
 // ExtendLock implements WorkerRepository
@@ -25,6 +25,17 @@ const (
 	TaskStatusProcessing TaskStatus = "processing"
 	TaskStatusCompleted  TaskStatus = "completed"
 	TaskStatusFailed     TaskStatus = "failed"
+	TaskStatusSkipped    TaskStatus = "skipped" // Dependency failed and the task was not run
+)
+
+// CascadePolicy decides what happens to a task when a task it depends on fails permanently
+type CascadePolicy string
+
+const (
+	// CascadeFail marks the dependent task as failed and moves it to the dead letter queue
+	CascadeFail CascadePolicy = "fail"
+	// CascadeSkip marks the dependent task as skipped and keeps it in the queue storage
+	CascadeSkip CascadePolicy = "skip"
 )
 
 // Priority represents task priority (0-100, higher is more important)
@@ -63,6 +74,10 @@ type Task struct {
 	ProcessedAt *time.Time `json:"processed_at,omitempty"`
 	Error       *string    `json:"error,omitempty"`
 	CreatedAt   time.Time  `json:"created_at"`
+
+	// DependsOn holds the task that must complete before this one can be claimed
+	DependsOn           *uuid.UUID    `json:"depends_on,omitempty"`
+	OnDependencyFailure CascadePolicy `json:"on_dependency_failure,omitempty"`
 }
 
 // TasksDlq represents a task in the dead letter queue
@@ -15,7 +15,8 @@ import (
 
 // WorkerRepository defines the interface for worker operations
 type WorkerRepository interface {
-	// ClaimTask atomically claims the next available task
+	// ClaimTask atomically claims the next available task.
+	// Tasks with DependsOn set are only available once that task has completed.
 	ClaimTask(ctx context.Context, workerID uuid.UUID, queues []string, lockDuration time.Duration) (*Task, error)
 
 	// CompleteTask marks task as completed
@@ -24,7 +25,8 @@ type WorkerRepository interface {
 	// FailTask marks task as failed and increments retry count
 	FailTask(ctx context.Context, taskID uuid.UUID, errorMsg string) error
 
-	// MoveToDLQ moves task to dead letter queue
+	// MoveToDLQ moves task to dead letter queue. Pending tasks depending on it
+	// must be failed or skipped according to their OnDependencyFailure policy.
 	MoveToDLQ(ctx context.Context, taskID uuid.UUID) error
 
 	// ExtendLock extends the lock timeout for long-running tasks (optional)
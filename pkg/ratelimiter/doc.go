@@ -55,6 +55,18 @@
 //   - X-RateLimit-Limit: Maximum tokens
 //   - X-RateLimit-Remaining: Tokens remaining
 //   - X-RateLimit-Reset: Unix timestamp of next refill
+//   - Retry-After: Seconds until the next attempt, on denied requests only
+//
+// Use WithHeaderNames to rename them; an empty name suppresses that header:
+//
+//	middleware := ratelimiter.Middleware(limiter, keyFunc,
+//		ratelimiter.WithHeaderNames(ratelimiter.HeaderNames{
+//			Limit:      "RateLimit-Limit",
+//			Remaining:  "RateLimit-Remaining",
+//			Reset:      "RateLimit-Reset",
+//			RetryAfter: "Retry-After",
+//		}),
+//	)
 //
 // # Composite Key Functions
 //
@@ -94,6 +106,55 @@
 //		return err
 //	}
 //
+// # Per-Key Limits
+//
+// AllowWithConfig applies different limits to some keys without separate limiters:
+//
+//	result, err := limiter.AllowWithConfig(ctx, apiKey, ratelimiter.Config{
+//		Capacity:       1000,
+//		RefillRate:     100,
+//		RefillInterval: time.Second,
+//	})
+//
+// The store keeps the config with the key's bucket, so later Allow, AllowN and Status
+// calls for the key use it and Result.Limit reports its capacity. Reset clears it, and
+// it's dropped with the bucket when the key goes unused, so call AllowWithConfig on
+// every request of such keys rather than only once. Custom stores opt in by
+// implementing ConfigOverrideStore; otherwise AllowWithConfig returns
+// ErrOverridesNotSupported.
+//
+// # Reservations
+//
+// Reserve consumes tokens like AllowN but returns a Reservation, so tokens for an
+// expensive operation can be given back if it fails:
+//
+//	r, err := limiter.Reserve(ctx, "user:123", 10)
+//	if err != nil {
+//		return err
+//	}
+//	if !r.Allowed() {
+//		_ = r.Cancel(ctx) // or wait r.Delay() and proceed
+//		return errRateLimited
+//	}
+//	if err := export(ctx); err != nil {
+//		_ = r.Cancel(ctx)
+//		return err
+//	}
+//	r.Commit()
+//
+// Cancel returns the tokens through TokenReturner.ReturnTokens, capped at capacity.
+// MemoryStore and RedisStore implement TokenReturner; with other stores Cancel
+// returns ErrReturnNotSupported.
+//
+// # Retry Jitter
+//
+// Clients denied at the same moment get the same RetryAfter and return
+// together when tokens refill. WithRetryJitter adds a random delay of up to
+// factor * RetryAfter to the advertised retry time (and Retry-After header)
+// without changing refill behavior. A factor of 0.1-0.3 is recommended:
+//
+//	limiter, err := ratelimiter.NewBucket(store, config, ratelimiter.WithRetryJitter(0.2))
+//
 // # Memory Management
 //
 // The MemoryStore automatically cleans up stale buckets to prevent memory leaks:
@@ -105,6 +166,34 @@
 // Buckets are considered stale if they haven't been accessed for 1 hour.
 // Disable cleanup by setting the interval to 0.
 //
+// # Redis Store
+//
+// MemoryStore limits are per process. Behind several replicas use RedisStore so all
+// instances share buckets:
+//
+//	client, err := redis.Connect(ctx, redisConfig) // pkg/redis
+//	store := ratelimiter.NewRedisStore(client, ratelimiter.WithRedisKeyPrefix("api:"))
+//	limiter, err := ratelimiter.NewBucket(store, config)
+//
+// Token consumption runs as a Lua script, so it is atomic across instances and costs a
+// single round trip per Allow, AllowN or Status call. The script uses the Redis server
+// clock (Redis 5+) and keys expire once their bucket would be full again.
+//
+// # Persistence
+//
+// Export and Import snapshot and restore bucket state, skipping stale buckets on
+// import. WithPersistence does both with a file, loading it on start and saving it
+// on Close, so graceful restarts don't reset client allowances:
+//
+//	store := ratelimiter.NewMemoryStore(
+//		ratelimiter.WithPersistence("ratelimit.json"),
+//		ratelimiter.WithPersistenceErrorHandler(func(err error) { log.Println(err) }),
+//	)
+//	defer store.Close()
+//
+// Save writes the snapshot at any time, and CloseContext returns the save error
+// instead of passing it to the handler.
+//
 // # Custom Error Handling
 //
 // Customize error responses in the HTTP middleware:
@@ -116,8 +205,7 @@
 //		}
 //
 //		if result != nil && !result.Allowed() {
-//			retryAfter := int(result.RetryAfter().Seconds())
-//			w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+//			// Retry-After is already set by the middleware
 //			http.Error(w, "Rate limit exceeded", http.StatusTooManyRequests)
 //		}
 //	}
@@ -158,4 +246,18 @@
 //
 // This provides smooth rate limiting with burst tolerance, making it suitable for
 // web APIs, user rate limiting, and resource protection scenarios.
+//
+// # Sliding Window Log
+//
+// When bursts are not acceptable, SlidingWindowLimiter logs a timestamp per allowed
+// request and admits at most Limit requests within any rolling Window. Denied requests
+// are not logged, and ResetAt is when enough logged requests expire to admit them:
+//
+//	limiter, err := ratelimiter.NewSlidingWindow(store, ratelimiter.WindowConfig{
+//		Limit:  100,
+//		Window: time.Minute,
+//	})
+//
+// MemoryStore implements WindowStore alongside Store, and its cleanup and snapshots
+// cover window logs too. Memory grows with Limit, since every logged request is kept.
 package ratelimiter
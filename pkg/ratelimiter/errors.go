@@ -15,4 +15,16 @@ var (
 
 	// ErrStoreUnavailable indicates that the store backend is unavailable.
 	ErrStoreUnavailable = errors.New("store unavailable")
+
+	// ErrOverridesNotSupported indicates that the store doesn't implement ConfigOverrideStore.
+	ErrOverridesNotSupported = errors.New("store does not support config overrides")
+
+	// ErrReturnNotSupported indicates that the store doesn't implement TokenReturner.
+	ErrReturnNotSupported = errors.New("store does not support returning tokens")
+
+	// ErrInvalidSnapshot indicates that bucket state passed to Import can't be restored.
+	ErrInvalidSnapshot = errors.New("invalid rate limiter snapshot")
+
+	// ErrPersistenceNotConfigured indicates that Save was called on a store without WithPersistence.
+	ErrPersistenceNotConfigured = errors.New("persistence not configured")
 )
@@ -2,6 +2,8 @@ package ratelimiter
 
 import (
 	"context"
+	"errors"
+	"io/fs"
 	"sync"
 	"time"
 )
@@ -11,15 +13,35 @@ type bucket struct {
 	tokens     int
 	lastRefill time.Time
 	lastAccess time.Time // Used by cleanup to identify stale buckets
+	override   *Config   // Per-key config, see ConsumeTokensWithConfig
 }
 
-// MemoryStore implements Store interface using in-memory storage.
+// configFor returns the bucket's override, if any, or config.
+func (b *bucket) configFor(config Config) Config {
+	if b.override != nil {
+		return *b.override
+	}
+	return config
+}
+
+// requestLog represents a sliding window log state.
+type requestLog struct {
+	timestamps []time.Time // Allowed requests, oldest first
+	lastAccess time.Time   // Used by cleanup to identify stale logs
+}
+
+// MemoryStore implements Store and WindowStore interfaces using in-memory storage.
 type MemoryStore struct {
 	mu      sync.RWMutex
 	buckets map[string]*bucket
+	logs    map[string]*requestLog
 
 	cleanupInterval time.Duration
 	stopCleanup     chan struct{}
+	closeOnce       sync.Once
+
+	persistPath    string      // Snapshot file loaded on start and saved on Close, see WithPersistence
+	persistErrorFn func(error) // Receives snapshot errors that can't be returned, see WithPersistenceErrorHandler
 }
 
 // MemoryStoreOption configures a MemoryStore.
@@ -37,6 +59,7 @@ func WithCleanupInterval(interval time.Duration) MemoryStoreOption {
 func NewMemoryStore(opts ...MemoryStoreOption) *MemoryStore {
 	ms := &MemoryStore{
 		buckets:         make(map[string]*bucket),
+		logs:            make(map[string]*requestLog),
 		cleanupInterval: 5 * time.Minute,
 		stopCleanup:     make(chan struct{}),
 	}
@@ -45,6 +68,13 @@ func NewMemoryStore(opts ...MemoryStoreOption) *MemoryStore {
 		opt(ms)
 	}
 
+	if ms.persistPath != "" {
+		// An unreadable snapshot must not prevent startup, limits start fresh instead
+		if err := ms.load(); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			ms.reportPersistError(err)
+		}
+	}
+
 	// Start background cleanup only if interval is set
 	if ms.cleanupInterval > 0 {
 		go ms.cleanup()
@@ -55,12 +85,24 @@ func NewMemoryStore(opts ...MemoryStoreOption) *MemoryStore {
 
 // ConsumeTokens attempts to consume tokens from the bucket.
 func (ms *MemoryStore) ConsumeTokens(ctx context.Context, key string, tokens int, config Config) (remaining int, resetAt time.Time, err error) {
+	remaining, resetAt, _, err = ms.ConsumeTokensWithConfig(ctx, key, tokens, config, nil)
+	return remaining, resetAt, err
+}
+
+// ConsumeTokensWithConfig attempts to consume tokens from the bucket, storing override if set.
+func (ms *MemoryStore) ConsumeTokensWithConfig(ctx context.Context, key string, tokens int, config Config, override *Config) (remaining int, resetAt time.Time, applied Config, err error) {
 	ms.mu.Lock()
 	defer ms.mu.Unlock()
 
 	now := time.Now()
 	b, exists := ms.buckets[key]
 
+	if override != nil {
+		config = *override
+	} else if exists {
+		config = b.configFor(config)
+	}
+
 	if !exists {
 		b = &bucket{
 			tokens:     config.Capacity,
@@ -69,6 +111,10 @@ func (ms *MemoryStore) ConsumeTokens(ctx context.Context, key string, tokens int
 		}
 		ms.buckets[key] = b
 	}
+	if override != nil {
+		b.override = override
+		b.tokens = min(b.tokens, config.Capacity) // A lowered capacity applies right away
+	}
 
 	// Token bucket algorithm: calculate how many refill intervals have passed
 	// and add the corresponding tokens, then consume the requested amount
@@ -89,14 +135,71 @@ func (ms *MemoryStore) ConsumeTokens(ctx context.Context, key string, tokens int
 
 	resetAt = b.lastRefill.Add(config.RefillInterval)
 
+	return remaining, resetAt, config, nil
+}
+
+// ReturnTokens gives tokens back to the bucket, up to its capacity.
+func (ms *MemoryStore) ReturnTokens(ctx context.Context, key string, tokens int, config Config) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	if b, exists := ms.buckets[key]; exists {
+		b.tokens = min(b.tokens+tokens, b.configFor(config).Capacity)
+	}
+	return nil
+}
+
+// RecordRequests records requests in the key's sliding window log.
+func (ms *MemoryStore) RecordRequests(ctx context.Context, key string, n int, config WindowConfig) (remaining int, resetAt time.Time, err error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	now := time.Now()
+	l, exists := ms.logs[key]
+	if !exists {
+		l = &requestLog{}
+		ms.logs[key] = l
+	}
+	l.lastAccess = now
+
+	// Prune in place; timestamps are appended in order, so expired ones are a prefix
+	cutoff := now.Add(-config.Window)
+	expired := 0
+	for expired < len(l.timestamps) && !l.timestamps[expired].After(cutoff) {
+		expired++
+	}
+	if expired > 0 {
+		l.timestamps = append(l.timestamps[:0], l.timestamps[expired:]...)
+	}
+
+	remaining = config.Limit - len(l.timestamps) - n
+	if remaining >= 0 {
+		for range n {
+			l.timestamps = append(l.timestamps, now)
+		}
+	}
+
+	if len(l.timestamps) == 0 {
+		return remaining, now, nil
+	}
+
+	// Denied requests fit once enough of the oldest requests leave the window
+	idx := 0
+	if remaining < 0 {
+		idx = min(-remaining-1, len(l.timestamps)-1)
+	}
+	resetAt = l.timestamps[idx].Add(config.Window)
+
 	return remaining, resetAt, nil
 }
 
+// Reset removes the bucket with its config override and the window log stored under key.
 func (ms *MemoryStore) Reset(ctx context.Context, key string) error {
 	ms.mu.Lock()
 	defer ms.mu.Unlock()
 
 	delete(ms.buckets, key)
+	delete(ms.logs, key)
 	return nil
 }
 
@@ -115,27 +218,51 @@ func (ms *MemoryStore) cleanup() {
 	}
 }
 
-// removeStale removes buckets that haven't been accessed recently to prevent memory leaks.
+// staleBucketAge is how long a bucket can go unused before it's removed.
+const staleBucketAge = 1 * time.Hour
+
+// removeStale removes buckets and window logs that haven't been accessed recently to prevent memory leaks.
 func (ms *MemoryStore) removeStale() {
 	ms.mu.Lock()
 	defer ms.mu.Unlock()
 
 	now := time.Now()
-	staleThreshold := 1 * time.Hour
 
 	for key, b := range ms.buckets {
-		if now.Sub(b.lastAccess) > staleThreshold {
+		if now.Sub(b.lastAccess) > staleBucketAge {
 			delete(ms.buckets, key)
 		}
 	}
+	for key, l := range ms.logs {
+		if now.Sub(l.lastAccess) > staleBucketAge {
+			delete(ms.logs, key)
+		}
+	}
 }
 
-// Close stops the cleanup goroutine. Safe to call multiple times.
+// Close stops the cleanup goroutine and, with WithPersistence, saves bucket state.
+// A save error goes to the WithPersistenceErrorHandler callback; use CloseContext to get it instead.
+// Safe to call multiple times; state is saved only on the first call.
 func (ms *MemoryStore) Close() {
-	select {
-	case <-ms.stopCleanup:
-		// Already closed
-	default:
-		close(ms.stopCleanup)
+	if err := ms.CloseContext(context.Background()); err != nil {
+		ms.reportPersistError(err)
 	}
 }
+
+// CloseContext is like Close but returns the error from saving bucket state.
+// If ctx is done before the snapshot is written, its error is returned and nothing is saved.
+func (ms *MemoryStore) CloseContext(ctx context.Context) error {
+	var err error
+	ms.closeOnce.Do(func() {
+		close(ms.stopCleanup)
+		if ms.persistPath == "" {
+			return
+		}
+		if ctx.Err() != nil {
+			err = errors.Join(ErrContextCancelled, ctx.Err())
+			return
+		}
+		err = ms.save()
+	})
+	return err
+}
@@ -2,6 +2,7 @@ package ratelimiter
 
 import (
 	"hash/fnv"
+	"math"
 	"net/http"
 	"strconv"
 	"strings"
@@ -18,9 +19,27 @@ type KeyFunc func(r *http.Request) string
 // If err is nil and result.Allowed() is false, the rate limit was exceeded.
 type ErrorResponder func(w http.ResponseWriter, r *http.Request, result *Result, err error)
 
+// HeaderNames sets the response header names used by the middleware.
+// An empty name suppresses that header.
+type HeaderNames struct {
+	Limit      string // maximum tokens
+	Remaining  string // tokens remaining
+	Reset      string // Unix timestamp of next refill
+	RetryAfter string // seconds until retry, set only on denied requests
+}
+
+// DefaultHeaderNames are the X- prefixed names used unless WithHeaderNames is given.
+var DefaultHeaderNames = HeaderNames{
+	Limit:      "X-RateLimit-Limit",
+	Remaining:  "X-RateLimit-Remaining",
+	Reset:      "X-RateLimit-Reset",
+	RetryAfter: "Retry-After",
+}
+
 // middlewareConfig holds middleware configuration.
 type middlewareConfig struct {
 	errorResponder ErrorResponder
+	headers        HeaderNames
 }
 
 // MiddlewareOption configures the rate limiting middleware.
@@ -33,6 +52,14 @@ func WithErrorResponder(responder ErrorResponder) MiddlewareOption {
 	}
 }
 
+// WithHeaderNames overrides the rate limit header names, e.g. to use the
+// RateLimit-Limit/RateLimit-Remaining/RateLimit-Reset spelling of the IETF draft.
+func WithHeaderNames(names HeaderNames) MiddlewareOption {
+	return func(c *middlewareConfig) {
+		c.headers = names
+	}
+}
+
 // Composite combines multiple key functions into one rate limiting key.
 // Uses FNV-1a hashing to keep keys under 64 characters for storage efficiency.
 func Composite(keyFuncs ...KeyFunc) KeyFunc {
@@ -73,10 +100,6 @@ func defaultErrorResponder(w http.ResponseWriter, r *http.Request, result *Resul
 	}
 
 	if result != nil && !result.Allowed() {
-		retryAfter := int(result.RetryAfter().Seconds())
-		if retryAfter > 0 {
-			w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
-		}
 		http.Error(w, "Too Many Requests", http.StatusTooManyRequests)
 	}
 }
@@ -85,6 +108,7 @@ func defaultErrorResponder(w http.ResponseWriter, r *http.Request, result *Resul
 func Middleware(limiter RateLimiter, keyFunc KeyFunc, opts ...MiddlewareOption) func(http.Handler) http.Handler {
 	config := &middlewareConfig{
 		errorResponder: defaultErrorResponder,
+		headers:        DefaultHeaderNames,
 	}
 
 	for _, opt := range opts {
@@ -102,11 +126,17 @@ func Middleware(limiter RateLimiter, keyFunc KeyFunc, opts ...MiddlewareOption)
 			}
 
 			// Standard rate limit headers for client awareness
-			w.Header().Set("X-RateLimit-Limit", strconv.Itoa(result.Limit))
-			w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(max(0, result.Remaining)))
-			w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(result.ResetAt.Unix(), 10))
+			names := config.headers
+			setHeader(w, names.Limit, strconv.Itoa(result.Limit))
+			setHeader(w, names.Remaining, strconv.Itoa(max(0, result.Remaining)))
+			setHeader(w, names.Reset, strconv.FormatInt(result.ResetAt.Unix(), 10))
 
 			if !result.Allowed() {
+				// Set before the responder runs so custom responders get it too;
+				// rounded up and at least 1s so clients never retry immediately
+				retryAfter := max(1, int(math.Ceil(result.RetryAfter().Seconds())))
+				setHeader(w, names.RetryAfter, strconv.Itoa(retryAfter))
+
 				config.errorResponder(w, r, result, nil)
 				return
 			}
@@ -115,3 +145,10 @@ func Middleware(limiter RateLimiter, keyFunc KeyFunc, opts ...MiddlewareOption)
 		})
 	}
 }
+
+// setHeader sets the header unless its name is empty.
+func setHeader(w http.ResponseWriter, name, value string) {
+	if name != "" {
+		w.Header().Set(name, value)
+	}
+}
@@ -67,13 +67,9 @@ func TestMiddleware_RateLimitEnforcement(t *testing.T) {
 		assert.Equal(t, http.StatusTooManyRequests, rec.Code)
 		assert.Contains(t, rec.Body.String(), "Too Many Requests")
 		assert.Equal(t, "0", rec.Header().Get("X-RateLimit-Remaining"))
-		// Retry-After may or may not be set depending on timing
-		retryAfter := rec.Header().Get("Retry-After")
-		if retryAfter != "" {
-			retrySeconds, err := strconv.Atoi(retryAfter)
-			assert.NoError(t, err)
-			assert.GreaterOrEqual(t, retrySeconds, 0)
-		}
+		retrySeconds, err := strconv.Atoi(rec.Header().Get("Retry-After"))
+		assert.NoError(t, err)
+		assert.GreaterOrEqual(t, retrySeconds, 1)
 	})
 
 	t.Run("different keys have independent limits", func(t *testing.T) {
@@ -214,6 +210,72 @@ func TestMiddleware_CustomErrorResponder(t *testing.T) {
 		assert.Equal(t, http.StatusForbidden, rec2.Code)
 		assert.Equal(t, "Custom Rate Limit Message", rec2.Body.String())
 		assert.Equal(t, "rate-limited", rec2.Header().Get("X-Custom-Header"))
+		assert.NotEmpty(t, rec2.Header().Get("Retry-After"))
+	})
+}
+
+func TestMiddleware_HeaderNames(t *testing.T) {
+	t.Parallel()
+
+	newHandler := func(t *testing.T, opts ...ratelimiter.MiddlewareOption) http.Handler {
+		t.Helper()
+		store := ratelimiter.NewMemoryStore()
+		t.Cleanup(func() { store.Close() })
+
+		limiter, err := ratelimiter.NewBucket(store, ratelimiter.Config{
+			Capacity:       1,
+			RefillRate:     1,
+			RefillInterval: time.Minute,
+		})
+		require.NoError(t, err)
+
+		keyFunc := func(r *http.Request) string { return "test" }
+		return ratelimiter.Middleware(limiter, keyFunc, opts...)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+	}
+
+	serve := func(handler http.Handler) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest("GET", "/test", nil))
+		return rec
+	}
+
+	t.Run("uses custom names", func(t *testing.T) {
+		t.Parallel()
+		handler := newHandler(t, ratelimiter.WithHeaderNames(ratelimiter.HeaderNames{
+			Limit:      "RateLimit-Limit",
+			Remaining:  "RateLimit-Remaining",
+			Reset:      "RateLimit-Reset",
+			RetryAfter: "Retry-After",
+		}))
+
+		rec := serve(handler)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "1", rec.Header().Get("RateLimit-Limit"))
+		assert.Equal(t, "0", rec.Header().Get("RateLimit-Remaining"))
+		assert.NotEmpty(t, rec.Header().Get("RateLimit-Reset"))
+		assert.Empty(t, rec.Header().Get("X-RateLimit-Limit"))
+		assert.Empty(t, rec.Header().Get("Retry-After"))
+
+		rec = serve(handler)
+		assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+		assert.Equal(t, "60", rec.Header().Get("Retry-After"))
+	})
+
+	t.Run("empty names suppress headers", func(t *testing.T) {
+		t.Parallel()
+		handler := newHandler(t, ratelimiter.WithHeaderNames(ratelimiter.HeaderNames{
+			Limit: "X-RateLimit-Limit",
+		}))
+
+		serve(handler)
+		rec := serve(handler)
+		assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+		assert.Equal(t, "1", rec.Header().Get("X-RateLimit-Limit"))
+		assert.Empty(t, rec.Header().Get("X-RateLimit-Remaining"))
+		assert.Empty(t, rec.Header().Get("X-RateLimit-Reset"))
+		assert.Empty(t, rec.Header().Get("Retry-After"))
 	})
 }
 
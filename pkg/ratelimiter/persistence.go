@@ -0,0 +1,195 @@
+package ratelimiter
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+)
+
+// snapshotVersion is bumped when the snapshot format changes incompatibly.
+const snapshotVersion = 1
+
+// snapshot is the serialized state of a MemoryStore.
+type snapshot struct {
+	Version int                       `json:"version"`
+	Buckets map[string]bucketSnapshot `json:"buckets"`
+	Logs    map[string]logSnapshot    `json:"logs,omitempty"`
+}
+
+type bucketSnapshot struct {
+	Tokens     int             `json:"tokens"`
+	LastRefill time.Time       `json:"last_refill"`
+	LastAccess time.Time       `json:"last_access"`
+	Override   *configSnapshot `json:"override,omitempty"`
+}
+
+type configSnapshot struct {
+	Capacity       int           `json:"capacity"`
+	RefillRate     int           `json:"refill_rate"`
+	RefillInterval time.Duration `json:"refill_interval"`
+}
+
+type logSnapshot struct {
+	Timestamps []time.Time `json:"timestamps"`
+	LastAccess time.Time   `json:"last_access"`
+}
+
+// WithPersistence loads bucket state from path when the store is created and
+// saves it there on Close, so limits survive graceful restarts. A missing or
+// invalid file starts the store empty; see WithPersistenceErrorHandler. Buckets are refilled for the downtime
+// on their next use, as their refill timestamps are preserved.
+func WithPersistence(path string) MemoryStoreOption {
+	return func(ms *MemoryStore) {
+		ms.persistPath = path
+	}
+}
+
+// WithPersistenceErrorHandler sets a callback for snapshot errors that can't be returned:
+// a snapshot file that exists but can't be read or imported when the store is created,
+// and a failed save on Close. A missing file is not an error.
+func WithPersistenceErrorHandler(fn func(err error)) MemoryStoreOption {
+	return func(ms *MemoryStore) {
+		ms.persistErrorFn = fn
+	}
+}
+
+// Save writes bucket state to the WithPersistence file now, e.g. for periodic snapshots
+// that limit what a crash loses. It returns ErrPersistenceNotConfigured without WithPersistence.
+func (ms *MemoryStore) Save() error {
+	if ms.persistPath == "" {
+		return ErrPersistenceNotConfigured
+	}
+	return ms.save()
+}
+
+// Export returns a snapshot of all buckets: remaining tokens, refill and access
+// timestamps and config overrides, along with sliding window logs. Restore it with Import.
+func (ms *MemoryStore) Export() ([]byte, error) {
+	ms.mu.RLock()
+	snap := snapshot{
+		Version: snapshotVersion,
+		Buckets: make(map[string]bucketSnapshot, len(ms.buckets)),
+	}
+	for key, b := range ms.buckets {
+		bs := bucketSnapshot{
+			Tokens:     b.tokens,
+			LastRefill: b.lastRefill,
+			LastAccess: b.lastAccess,
+		}
+		if b.override != nil {
+			bs.Override = &configSnapshot{
+				Capacity:       b.override.Capacity,
+				RefillRate:     b.override.RefillRate,
+				RefillInterval: b.override.RefillInterval,
+			}
+		}
+		snap.Buckets[key] = bs
+	}
+	if len(ms.logs) > 0 {
+		snap.Logs = make(map[string]logSnapshot, len(ms.logs))
+		for key, l := range ms.logs {
+			snap.Logs[key] = logSnapshot{
+				Timestamps: slices.Clone(l.timestamps),
+				LastAccess: l.lastAccess,
+			}
+		}
+	}
+	ms.mu.RUnlock()
+
+	return json.Marshal(snap)
+}
+
+// Import restores buckets and window logs from a snapshot created by Export, replacing
+// those with the same key. Buckets and logs unused for longer than the stale threshold are skipped,
+// as the cleanup would remove them anyway.
+func (ms *MemoryStore) Import(data []byte) error {
+	var snap snapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return errors.Join(ErrInvalidSnapshot, err)
+	}
+	if snap.Version != snapshotVersion {
+		return ErrInvalidSnapshot
+	}
+
+	now := time.Now()
+
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	for key, b := range snap.Buckets {
+		if now.Sub(b.LastAccess) > staleBucketAge {
+			continue
+		}
+		restored := &bucket{
+			tokens:     b.Tokens,
+			lastRefill: b.LastRefill,
+			lastAccess: b.LastAccess,
+		}
+		if b.Override != nil {
+			override := Config{
+				Capacity:       b.Override.Capacity,
+				RefillRate:     b.Override.RefillRate,
+				RefillInterval: b.Override.RefillInterval,
+			}
+			if override.validate() != nil {
+				continue // A bucket without its limits would use the wrong ones, start it over
+			}
+			restored.override = &override
+		}
+		ms.buckets[key] = restored
+	}
+	for key, l := range snap.Logs {
+		if now.Sub(l.LastAccess) > staleBucketAge {
+			continue
+		}
+		ms.logs[key] = &requestLog{
+			timestamps: l.Timestamps,
+			lastAccess: l.LastAccess,
+		}
+	}
+
+	return nil
+}
+
+// reportPersistError passes err to the WithPersistenceErrorHandler callback, if set.
+func (ms *MemoryStore) reportPersistError(err error) {
+	if ms.persistErrorFn != nil {
+		ms.persistErrorFn(err)
+	}
+}
+
+// load imports the snapshot file, if there is one.
+func (ms *MemoryStore) load() error {
+	data, err := os.ReadFile(ms.persistPath)
+	if err != nil {
+		return err
+	}
+	return ms.Import(data)
+}
+
+// save writes the snapshot file atomically, so a crash mid-write keeps the previous snapshot.
+func (ms *MemoryStore) save() error {
+	data, err := ms.Export()
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(ms.persistPath), filepath.Base(ms.persistPath)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), ms.persistPath)
+}
@@ -0,0 +1,227 @@
+package ratelimiter_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dmitrymomot/saaskit/pkg/ratelimiter"
+)
+
+func TestMemoryStore_ExportImport(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	config := ratelimiter.Config{
+		Capacity:       10,
+		RefillRate:     1,
+		RefillInterval: time.Hour,
+	}
+
+	t.Run("restores remaining tokens", func(t *testing.T) {
+		t.Parallel()
+
+		store := ratelimiter.NewMemoryStore(ratelimiter.WithCleanupInterval(0))
+		defer store.Close()
+
+		_, _, err := store.ConsumeTokens(ctx, "user:1", 7, config)
+		require.NoError(t, err)
+		_, _, err = store.ConsumeTokens(ctx, "user:2", 2, config)
+		require.NoError(t, err)
+
+		data, err := store.Export()
+		require.NoError(t, err)
+
+		restored := ratelimiter.NewMemoryStore(ratelimiter.WithCleanupInterval(0))
+		defer restored.Close()
+		require.NoError(t, restored.Import(data))
+
+		remaining, _, err := restored.ConsumeTokens(ctx, "user:1", 0, config)
+		require.NoError(t, err)
+		assert.Equal(t, 3, remaining)
+
+		remaining, _, err = restored.ConsumeTokens(ctx, "user:2", 0, config)
+		require.NoError(t, err)
+		assert.Equal(t, 8, remaining)
+	})
+
+	t.Run("restores window logs", func(t *testing.T) {
+		t.Parallel()
+
+		windowConfig := ratelimiter.WindowConfig{Limit: 5, Window: time.Hour}
+
+		store := ratelimiter.NewMemoryStore(ratelimiter.WithCleanupInterval(0))
+		defer store.Close()
+
+		_, _, err := store.RecordRequests(ctx, "user:1", 4, windowConfig)
+		require.NoError(t, err)
+
+		data, err := store.Export()
+		require.NoError(t, err)
+
+		restored := ratelimiter.NewMemoryStore(ratelimiter.WithCleanupInterval(0))
+		defer restored.Close()
+		require.NoError(t, restored.Import(data))
+
+		remaining, _, err := restored.RecordRequests(ctx, "user:1", 0, windowConfig)
+		require.NoError(t, err)
+		assert.Equal(t, 1, remaining)
+	})
+
+	t.Run("prunes stale buckets", func(t *testing.T) {
+		t.Parallel()
+
+		now := time.Now()
+		data, err := json.Marshal(map[string]any{
+			"version": 1,
+			"buckets": map[string]any{
+				"fresh": map[string]any{"tokens": 1, "last_refill": now, "last_access": now},
+				"stale": map[string]any{"tokens": 1, "last_refill": now.Add(-2 * time.Hour), "last_access": now.Add(-2 * time.Hour)},
+			},
+		})
+		require.NoError(t, err)
+
+		store := ratelimiter.NewMemoryStore(ratelimiter.WithCleanupInterval(0))
+		defer store.Close()
+		require.NoError(t, store.Import(data))
+
+		remaining, _, err := store.ConsumeTokens(ctx, "fresh", 0, config)
+		require.NoError(t, err)
+		assert.Equal(t, 1, remaining)
+
+		// A pruned bucket starts over at full capacity
+		remaining, _, err = store.ConsumeTokens(ctx, "stale", 0, config)
+		require.NoError(t, err)
+		assert.Equal(t, 10, remaining)
+	})
+
+	t.Run("rejects invalid snapshots", func(t *testing.T) {
+		t.Parallel()
+
+		store := ratelimiter.NewMemoryStore(ratelimiter.WithCleanupInterval(0))
+		defer store.Close()
+
+		assert.ErrorIs(t, store.Import([]byte("not json")), ratelimiter.ErrInvalidSnapshot)
+		assert.ErrorIs(t, store.Import([]byte(`{"version":99,"buckets":{}}`)), ratelimiter.ErrInvalidSnapshot)
+	})
+}
+
+func TestMemoryStore_WithPersistence(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	config := ratelimiter.Config{
+		Capacity:       5,
+		RefillRate:     1,
+		RefillInterval: time.Hour,
+	}
+
+	t.Run("survives restart", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(t.TempDir(), "ratelimit.json")
+
+		store := ratelimiter.NewMemoryStore(ratelimiter.WithPersistence(path))
+		_, _, err := store.ConsumeTokens(ctx, "ip:1", 4, config)
+		require.NoError(t, err)
+		require.NoError(t, store.CloseContext(ctx))
+		require.NoError(t, store.CloseContext(ctx), "second close is a no-op")
+
+		restarted := ratelimiter.NewMemoryStore(ratelimiter.WithPersistence(path))
+		defer restarted.Close()
+
+		remaining, _, err := restarted.ConsumeTokens(ctx, "ip:1", 1, config)
+		require.NoError(t, err)
+		assert.Equal(t, 0, remaining)
+	})
+
+	t.Run("missing or corrupt file starts empty", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+		corrupt := filepath.Join(dir, "corrupt.json")
+		require.NoError(t, os.WriteFile(corrupt, []byte("{"), 0o600))
+
+		for _, path := range []string{filepath.Join(dir, "missing.json"), corrupt} {
+			var loadErr error
+			store := ratelimiter.NewMemoryStore(
+				ratelimiter.WithPersistence(path),
+				ratelimiter.WithPersistenceErrorHandler(func(err error) { loadErr = err }),
+			)
+			remaining, _, err := store.ConsumeTokens(ctx, "ip:1", 1, config)
+			require.NoError(t, err)
+			assert.Equal(t, 4, remaining)
+			require.NoError(t, store.CloseContext(ctx))
+
+			if path == corrupt {
+				assert.ErrorIs(t, loadErr, ratelimiter.ErrInvalidSnapshot)
+			} else {
+				assert.NoError(t, loadErr, "missing file is not an error")
+			}
+		}
+	})
+
+	t.Run("save error is returned from close context", func(t *testing.T) {
+		t.Parallel()
+
+		store := ratelimiter.NewMemoryStore(ratelimiter.WithPersistence(filepath.Join(t.TempDir(), "missing-dir", "state.json")))
+		assert.Error(t, store.CloseContext(ctx))
+	})
+
+	t.Run("save error is reported from close", func(t *testing.T) {
+		t.Parallel()
+
+		var saveErr error
+		store := ratelimiter.NewMemoryStore(
+			ratelimiter.WithPersistence(filepath.Join(t.TempDir(), "missing-dir", "state.json")),
+			ratelimiter.WithPersistenceErrorHandler(func(err error) { saveErr = err }),
+		)
+		store.Close()
+		assert.Error(t, saveErr)
+	})
+
+	t.Run("save writes a snapshot without closing", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(t.TempDir(), "ratelimit.json")
+
+		store := ratelimiter.NewMemoryStore(ratelimiter.WithPersistence(path))
+		defer store.Close()
+		_, _, err := store.ConsumeTokens(ctx, "ip:1", 2, config)
+		require.NoError(t, err)
+		require.NoError(t, store.Save())
+
+		restarted := ratelimiter.NewMemoryStore(ratelimiter.WithPersistence(path))
+		defer restarted.Close()
+
+		remaining, _, err := restarted.ConsumeTokens(ctx, "ip:1", 1, config)
+		require.NoError(t, err)
+		assert.Equal(t, 2, remaining)
+	})
+
+	t.Run("save requires persistence", func(t *testing.T) {
+		t.Parallel()
+
+		store := ratelimiter.NewMemoryStore()
+		defer store.Close()
+		assert.ErrorIs(t, store.Save(), ratelimiter.ErrPersistenceNotConfigured)
+	})
+
+	t.Run("cancelled close context skips saving", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(t.TempDir(), "ratelimit.json")
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+
+		store := ratelimiter.NewMemoryStore(ratelimiter.WithPersistence(path))
+		assert.ErrorIs(t, store.CloseContext(cancelled), ratelimiter.ErrContextCancelled)
+		assert.NoFileExists(t, path)
+	})
+}
@@ -3,6 +3,8 @@ package ratelimiter
 import (
 	"context"
 	"fmt"
+	"math/rand/v2"
+	"time"
 )
 
 // RateLimiter defines the interface for rate limiting implementations.
@@ -13,20 +15,44 @@ type RateLimiter interface {
 
 // Bucket implements a token bucket rate limiter.
 type Bucket struct {
-	store  Store
-	config Config
+	store       Store
+	config      Config
+	retryJitter float64
+}
+
+// BucketOption configures a Bucket.
+type BucketOption func(*Bucket)
+
+// WithRetryJitter spreads out retries of denied clients by adding a random
+// delay of up to factor * RetryAfter to the advertised retry time, so clients
+// denied together don't all come back the moment tokens are refilled.
+// Only Result.RetryAfter (and the Retry-After header) is affected; refill stays
+// deterministic. A factor of 0.1-0.3 is recommended; it must be within [0, 1].
+func WithRetryJitter(factor float64) BucketOption {
+	return func(tb *Bucket) {
+		tb.retryJitter = factor
+	}
 }
 
 // NewBucket creates a new token bucket rate limiter.
-func NewBucket(store Store, config Config) (*Bucket, error) {
+func NewBucket(store Store, config Config, opts ...BucketOption) (*Bucket, error) {
 	if err := config.validate(); err != nil {
 		return nil, err
 	}
 
-	return &Bucket{
+	tb := &Bucket{
 		store:  store,
 		config: config,
-	}, nil
+	}
+	for _, opt := range opts {
+		opt(tb)
+	}
+
+	if tb.retryJitter < 0 || tb.retryJitter > 1 {
+		return nil, fmt.Errorf("%w: retry jitter must be within [0, 1], got %v", ErrInvalidConfig, tb.retryJitter)
+	}
+
+	return tb, nil
 }
 
 func (tb *Bucket) Allow(ctx context.Context, key string) (*Result, error) {
@@ -38,33 +64,73 @@ func (tb *Bucket) AllowN(ctx context.Context, key string, n int) (*Result, error
 		return nil, fmt.Errorf("%w: must be positive, got %d", ErrInvalidTokenCount, n)
 	}
 
-	remaining, resetAt, err := tb.store.ConsumeTokens(ctx, key, n, tb.config)
-	if err != nil {
+	result, _, err := tb.consume(ctx, key, n, nil)
+	return result, err
+}
+
+// AllowWithConfig consumes one token using cfg for this key instead of the bucket's Config,
+// e.g. for higher limits on premium API keys. The store remembers cfg with the key's bucket,
+// so later Allow, AllowN and Status calls for the key use it as well, until Reset or until
+// the bucket is removed as stale. cfg is validated like the Config passed to NewBucket.
+// Returns ErrOverridesNotSupported if the store doesn't implement ConfigOverrideStore.
+func (tb *Bucket) AllowWithConfig(ctx context.Context, key string, cfg Config) (*Result, error) {
+	if err := cfg.validate(); err != nil {
 		return nil, err
 	}
+	if _, ok := tb.store.(ConfigOverrideStore); !ok {
+		return nil, ErrOverridesNotSupported
+	}
 
-	return &Result{
-		Limit:     tb.config.Capacity,
+	result, _, err := tb.consume(ctx, key, 1, &cfg)
+	return result, err
+}
+
+// consume consumes n tokens and returns the result along with the config that applied to the key.
+// override is only set by AllowWithConfig, which has checked that the store supports it.
+func (tb *Bucket) consume(ctx context.Context, key string, n int, override *Config) (*Result, Config, error) {
+	remaining, resetAt, applied, err := tb.consumeTokens(ctx, key, n, override)
+	if err != nil {
+		return nil, Config{}, err
+	}
+
+	result := &Result{
+		Limit:     applied.Capacity,
 		Remaining: remaining,
 		ResetAt:   resetAt,
-	}, nil
+	}
+	if tb.retryJitter > 0 && !result.Allowed() {
+		result.retryJitter = time.Duration(rand.Float64() * tb.retryJitter * float64(time.Until(resetAt)))
+	}
+
+	return result, applied, nil
 }
 
 // Status returns the current state without consuming tokens.
 func (tb *Bucket) Status(ctx context.Context, key string) (*Result, error) {
-	// ConsumeTokens with 0 tokens updates bucket state but doesn't actually consume
-	remaining, resetAt, err := tb.store.ConsumeTokens(ctx, key, 0, tb.config)
+	// Consuming 0 tokens updates bucket state but doesn't actually consume
+	remaining, resetAt, applied, err := tb.consumeTokens(ctx, key, 0, nil)
 	if err != nil {
 		return nil, err
 	}
 
 	return &Result{
-		Limit:     tb.config.Capacity,
+		Limit:     applied.Capacity,
 		Remaining: remaining,
 		ResetAt:   resetAt,
 	}, nil
 }
 
+// consumeTokens reports the config that applied to the key when the store tracks overrides,
+// so Result.Limit reflects them; other stores only ever apply the bucket's config.
+func (tb *Bucket) consumeTokens(ctx context.Context, key string, n int, override *Config) (int, time.Time, Config, error) {
+	if s, ok := tb.store.(ConfigOverrideStore); ok {
+		return s.ConsumeTokensWithConfig(ctx, key, n, tb.config, override)
+	}
+
+	remaining, resetAt, err := tb.store.ConsumeTokens(ctx, key, n, tb.config)
+	return remaining, resetAt, tb.config, err
+}
+
 func (tb *Bucket) Reset(ctx context.Context, key string) error {
 	return tb.store.Reset(ctx, key)
 }
@@ -0,0 +1,190 @@
+package ratelimiter
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// consumeScript runs the token bucket algorithm of MemoryStore.ConsumeTokensWithConfig
+// atomically in Redis, so instances sharing a key can't race. It uses the Redis server clock
+// to avoid skew between instances, and expires the key once the bucket would be full again.
+// Keys with a config override are kept for at least the override TTL.
+//
+// KEYS[1] - bucket key
+// ARGV    - tokens, capacity, refill rate, refill interval (ms), override (1 or 0), override TTL (ms)
+// Returns {remaining, resetAt (unix ms), capacity, refill rate, refill interval (ms)}.
+var consumeScript = redis.NewScript(`
+local key = KEYS[1]
+local tokens = tonumber(ARGV[1])
+local capacity = tonumber(ARGV[2])
+local rate = tonumber(ARGV[3])
+local interval = tonumber(ARGV[4])
+local override = ARGV[5] == '1'
+
+local time = redis.call('TIME')
+local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+
+local state = redis.call('HMGET', key, 'tokens', 'last_refill', 'capacity', 'rate', 'interval')
+local available = tonumber(state[1])
+local lastRefill = tonumber(state[2])
+local hasOverride = override or state[3] ~= false
+if override then
+	redis.call('HSET', key, 'capacity', capacity, 'rate', rate, 'interval', interval)
+elseif hasOverride then
+	capacity = tonumber(state[3])
+	rate = tonumber(state[4])
+	interval = tonumber(state[5])
+end
+
+if available == nil or lastRefill == nil then
+	available = capacity
+	lastRefill = now
+elseif override then
+	-- A lowered capacity applies right away
+	available = math.min(available, capacity)
+end
+
+local intervals = math.min(math.floor((now - lastRefill) / interval), math.floor(capacity / rate) + 1)
+if intervals > 0 then
+	available = math.min(available + intervals * rate, capacity)
+	lastRefill = now
+end
+
+available = available - tokens
+
+redis.call('HSET', key, 'tokens', available, 'last_refill', lastRefill)
+
+-- A missing key is a full bucket, so keep it only until it would be refilled
+local missing = capacity - available
+local ttl = interval
+if missing > 0 then
+	ttl = (math.ceil(missing / rate) + 1) * interval
+end
+if hasOverride then
+	ttl = math.max(ttl, tonumber(ARGV[6]))
+end
+redis.call('PEXPIRE', key, ttl)
+
+return {available, lastRefill + interval, capacity, rate, interval}
+`)
+
+// returnScript gives tokens back to an existing bucket, up to its capacity
+// or the capacity of its config override. The key's expiry is kept; it only
+// gets longer than needed.
+//
+// KEYS[1] - bucket key
+// ARGV    - tokens, capacity
+var returnScript = redis.NewScript(`
+local key = KEYS[1]
+local state = redis.call('HMGET', key, 'tokens', 'capacity')
+local available = tonumber(state[1])
+if available == nil then
+	return 0
+end
+
+local capacity = tonumber(state[2]) or tonumber(ARGV[2])
+available = math.min(available + tonumber(ARGV[1]), capacity)
+redis.call('HSET', key, 'tokens', available)
+return available
+`)
+
+// DefaultRedisKeyPrefix is prepended to rate limit keys stored in Redis.
+const DefaultRedisKeyPrefix = "ratelimit:"
+
+// RedisStore implements Store interface using Redis, sharing limits across instances.
+// Each ConsumeTokens call is a single round trip running a Lua script (EVALSHA),
+// so it costs one network call per Allow, AllowN or Status.
+type RedisStore struct {
+	client    redis.UniversalClient
+	keyPrefix string
+}
+
+// RedisStoreOption configures a RedisStore.
+type RedisStoreOption func(*RedisStore)
+
+// WithRedisKeyPrefix sets the prefix for keys stored in Redis.
+// Defaults to DefaultRedisKeyPrefix.
+func WithRedisKeyPrefix(prefix string) RedisStoreOption {
+	return func(rs *RedisStore) {
+		rs.keyPrefix = prefix
+	}
+}
+
+// NewRedisStore creates a Redis-backed store on any redis.UniversalClient.
+// Each bucket lives in a single key, so a cluster client works as well.
+// Panics if client is nil.
+func NewRedisStore(client redis.UniversalClient, opts ...RedisStoreOption) *RedisStore {
+	if client == nil {
+		panic("ratelimiter: redis client is required")
+	}
+
+	rs := &RedisStore{
+		client:    client,
+		keyPrefix: DefaultRedisKeyPrefix,
+	}
+	for _, opt := range opts {
+		opt(rs)
+	}
+
+	return rs
+}
+
+// ConsumeTokens attempts to consume tokens from the bucket.
+func (rs *RedisStore) ConsumeTokens(ctx context.Context, key string, tokens int, config Config) (remaining int, resetAt time.Time, err error) {
+	remaining, resetAt, _, err = rs.ConsumeTokensWithConfig(ctx, key, tokens, config, nil)
+	return remaining, resetAt, err
+}
+
+// ConsumeTokensWithConfig attempts to consume tokens from the bucket, storing override if set.
+func (rs *RedisStore) ConsumeTokensWithConfig(ctx context.Context, key string, tokens int, config Config, override *Config) (remaining int, resetAt time.Time, applied Config, err error) {
+	setOverride := 0
+	if override != nil {
+		config = *override
+		setOverride = 1
+	}
+
+	res, err := consumeScript.Run(ctx, rs.client, []string{rs.keyPrefix + key},
+		// Sub-millisecond intervals are rounded up, as the script works in milliseconds
+		tokens, config.Capacity, config.RefillRate, max(1, config.RefillInterval.Milliseconds()),
+		setOverride, staleBucketAge.Milliseconds(),
+	).Int64Slice()
+	if err != nil {
+		return 0, time.Time{}, Config{}, rs.wrapError(ctx, err)
+	}
+	if len(res) != 5 {
+		return 0, time.Time{}, Config{}, ErrStoreUnavailable
+	}
+
+	applied = Config{
+		Capacity:       int(res[2]),
+		RefillRate:     int(res[3]),
+		RefillInterval: time.Duration(res[4]) * time.Millisecond,
+	}
+	return int(res[0]), time.UnixMilli(res[1]), applied, nil
+}
+
+// ReturnTokens gives tokens back to the bucket, up to its capacity.
+func (rs *RedisStore) ReturnTokens(ctx context.Context, key string, tokens int, config Config) error {
+	if err := returnScript.Run(ctx, rs.client, []string{rs.keyPrefix + key}, tokens, config.Capacity).Err(); err != nil {
+		return rs.wrapError(ctx, err)
+	}
+	return nil
+}
+
+func (rs *RedisStore) Reset(ctx context.Context, key string) error {
+	if err := rs.client.Del(ctx, rs.keyPrefix+key).Err(); err != nil {
+		return rs.wrapError(ctx, err)
+	}
+	return nil
+}
+
+// wrapError distinguishes cancelled calls from an unreachable Redis.
+func (rs *RedisStore) wrapError(ctx context.Context, err error) error {
+	if ctx.Err() != nil {
+		return errors.Join(ErrContextCancelled, err)
+	}
+	return errors.Join(ErrStoreUnavailable, err)
+}
@@ -0,0 +1,281 @@
+package ratelimiter_test
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dmitrymomot/saaskit/pkg/ratelimiter"
+)
+
+var _ ratelimiter.Store = (*ratelimiter.RedisStore)(nil)
+
+func TestNewRedisStore(t *testing.T) {
+	t.Parallel()
+
+	t.Run("panics without client", func(t *testing.T) {
+		t.Parallel()
+		assert.Panics(t, func() {
+			ratelimiter.NewRedisStore(nil)
+		})
+	})
+
+	t.Run("works with NewBucket", func(t *testing.T) {
+		t.Parallel()
+		client := redis.NewClient(&redis.Options{Addr: "127.0.0.1:0"})
+		defer client.Close()
+
+		limiter, err := ratelimiter.NewBucket(ratelimiter.NewRedisStore(client, ratelimiter.WithRedisKeyPrefix("test:")), ratelimiter.Config{
+			Capacity:       10,
+			RefillRate:     1,
+			RefillInterval: time.Second,
+		})
+		require.NoError(t, err)
+		assert.NotNil(t, limiter)
+	})
+}
+
+func TestRedisStore_Errors(t *testing.T) {
+	t.Parallel()
+
+	// Nothing listens on port 0, so every command fails without a network round trip
+	client := redis.NewClient(&redis.Options{
+		Addr:        "127.0.0.1:0",
+		DialTimeout: 100 * time.Millisecond,
+		MaxRetries:  -1,
+	})
+	t.Cleanup(func() { client.Close() })
+
+	store := ratelimiter.NewRedisStore(client)
+	config := ratelimiter.Config{Capacity: 10, RefillRate: 1, RefillInterval: time.Second}
+
+	t.Run("unreachable redis", func(t *testing.T) {
+		t.Parallel()
+
+		_, _, err := store.ConsumeTokens(context.Background(), "user", 1, config)
+		assert.ErrorIs(t, err, ratelimiter.ErrStoreUnavailable)
+
+		assert.ErrorIs(t, store.Reset(context.Background(), "user"), ratelimiter.ErrStoreUnavailable)
+	})
+
+	t.Run("cancelled context", func(t *testing.T) {
+		t.Parallel()
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, _, err := store.ConsumeTokens(ctx, "user", 1, config)
+		assert.ErrorIs(t, err, ratelimiter.ErrContextCancelled)
+	})
+}
+
+// newTestRedisStore returns a RedisStore on the server at REDIS_URL, with keys under a
+// unique prefix that are removed after the test. The test is skipped if REDIS_URL is unset.
+func newTestRedisStore(t *testing.T) (*ratelimiter.RedisStore, *redis.Client, string) {
+	t.Helper()
+
+	url := os.Getenv("REDIS_URL")
+	if url == "" {
+		t.Skip("REDIS_URL is not set")
+	}
+	opts, err := redis.ParseURL(url)
+	require.NoError(t, err)
+
+	client := redis.NewClient(opts)
+	require.NoError(t, client.Ping(context.Background()).Err())
+
+	prefix := "ratelimiter-test:" + uuid.NewString() + ":"
+	t.Cleanup(func() {
+		ctx := context.Background()
+		keys, _ := client.Keys(ctx, prefix+"*").Result()
+		if len(keys) > 0 {
+			client.Del(ctx, keys...)
+		}
+		client.Close()
+	})
+
+	return ratelimiter.NewRedisStore(client, ratelimiter.WithRedisKeyPrefix(prefix)), client, prefix
+}
+
+// tokenBucketStore is implemented by MemoryStore and RedisStore.
+type tokenBucketStore interface {
+	ratelimiter.ConfigOverrideStore
+	ratelimiter.TokenReturner
+}
+
+// TestStore_TokenBucket runs the same cases against MemoryStore and RedisStore,
+// so the Lua script is held to the in-memory algorithm.
+func TestStore_TokenBucket(t *testing.T) {
+	t.Parallel()
+
+	stores := map[string]func(t *testing.T) tokenBucketStore{
+		"memory": func(t *testing.T) tokenBucketStore {
+			store := ratelimiter.NewMemoryStore()
+			t.Cleanup(func() { store.Close() })
+			return store
+		},
+		"redis": func(t *testing.T) tokenBucketStore {
+			store, _, _ := newTestRedisStore(t)
+			return store
+		},
+	}
+
+	ctx := context.Background()
+	config := ratelimiter.Config{
+		Capacity:       10,
+		RefillRate:     2,
+		RefillInterval: 100 * time.Millisecond,
+	}
+
+	for name, newStore := range stores {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			t.Run("consumes to empty", func(t *testing.T) {
+				t.Parallel()
+				store := newStore(t)
+
+				remaining, resetAt, err := store.ConsumeTokens(ctx, "empty", config.Capacity, config)
+				require.NoError(t, err)
+				assert.Equal(t, 0, remaining)
+				assert.WithinDuration(t, time.Now().Add(config.RefillInterval), resetAt, 50*time.Millisecond)
+
+				remaining, _, err = store.ConsumeTokens(ctx, "empty", 1, config)
+				require.NoError(t, err)
+				assert.Equal(t, -1, remaining)
+			})
+
+			t.Run("refills after the interval", func(t *testing.T) {
+				t.Parallel()
+				store := newStore(t)
+
+				remaining, _, err := store.ConsumeTokens(ctx, "refill", config.Capacity, config)
+				require.NoError(t, err)
+				assert.Equal(t, 0, remaining)
+
+				time.Sleep(config.RefillInterval + 10*time.Millisecond)
+
+				remaining, _, err = store.ConsumeTokens(ctx, "refill", 0, config)
+				require.NoError(t, err)
+				assert.Equal(t, config.RefillRate, remaining)
+			})
+
+			t.Run("caps refill at capacity", func(t *testing.T) {
+				t.Parallel()
+				store := newStore(t)
+
+				_, _, err := store.ConsumeTokens(ctx, "cap", 5, config)
+				require.NoError(t, err)
+
+				time.Sleep(config.RefillInterval * 10)
+
+				remaining, _, err := store.ConsumeTokens(ctx, "cap", 0, config)
+				require.NoError(t, err)
+				assert.Equal(t, config.Capacity, remaining)
+			})
+
+			t.Run("override lowers capacity and persists", func(t *testing.T) {
+				t.Parallel()
+				store := newStore(t)
+
+				remaining, _, err := store.ConsumeTokens(ctx, "override", 2, config)
+				require.NoError(t, err)
+				assert.Equal(t, 8, remaining)
+
+				lower := ratelimiter.Config{Capacity: 5, RefillRate: 1, RefillInterval: time.Second}
+				remaining, _, applied, err := store.ConsumeTokensWithConfig(ctx, "override", 1, config, &lower)
+				require.NoError(t, err)
+				assert.Equal(t, 4, remaining)
+				assert.Equal(t, lower, applied)
+
+				// Later calls use the stored override in place of their config
+				remaining, resetAt, applied, err := store.ConsumeTokensWithConfig(ctx, "override", 0, config, nil)
+				require.NoError(t, err)
+				assert.Equal(t, 4, remaining)
+				assert.Equal(t, lower, applied)
+				assert.WithinDuration(t, time.Now().Add(lower.RefillInterval), resetAt, 100*time.Millisecond)
+
+				require.NoError(t, store.ReturnTokens(ctx, "override", 10, config))
+				remaining, _, err = store.ConsumeTokens(ctx, "override", 0, config)
+				require.NoError(t, err)
+				assert.Equal(t, lower.Capacity, remaining)
+
+				// Reset drops the override
+				require.NoError(t, store.Reset(ctx, "override"))
+				remaining, _, applied, err = store.ConsumeTokensWithConfig(ctx, "override", 0, config, nil)
+				require.NoError(t, err)
+				assert.Equal(t, config.Capacity, remaining)
+				assert.Equal(t, config, applied)
+			})
+
+			t.Run("returned tokens are capped at capacity", func(t *testing.T) {
+				t.Parallel()
+				store := newStore(t)
+
+				_, _, err := store.ConsumeTokens(ctx, "return", 3, config)
+				require.NoError(t, err)
+
+				require.NoError(t, store.ReturnTokens(ctx, "return", 2, config))
+				remaining, _, err := store.ConsumeTokens(ctx, "return", 0, config)
+				require.NoError(t, err)
+				assert.Equal(t, 9, remaining)
+
+				require.NoError(t, store.ReturnTokens(ctx, "return", 5, config))
+				remaining, _, err = store.ConsumeTokens(ctx, "return", 0, config)
+				require.NoError(t, err)
+				assert.Equal(t, config.Capacity, remaining)
+			})
+
+			t.Run("returning to a missing bucket is a no-op", func(t *testing.T) {
+				t.Parallel()
+				store := newStore(t)
+
+				require.NoError(t, store.ReturnTokens(ctx, "missing", 5, config))
+				remaining, _, err := store.ConsumeTokens(ctx, "missing", 0, config)
+				require.NoError(t, err)
+				assert.Equal(t, config.Capacity, remaining)
+			})
+		})
+	}
+}
+
+func TestRedisStore_KeyTTL(t *testing.T) {
+	t.Parallel()
+
+	store, client, prefix := newTestRedisStore(t)
+	ctx := context.Background()
+	config := ratelimiter.Config{Capacity: 10, RefillRate: 2, RefillInterval: time.Second}
+
+	t.Run("full bucket expires after one interval", func(t *testing.T) {
+		_, _, err := store.ConsumeTokens(ctx, "full", 0, config)
+		require.NoError(t, err)
+
+		ttl, err := client.PTTL(ctx, prefix+"full").Result()
+		require.NoError(t, err)
+		assert.InDelta(t, config.RefillInterval, ttl, float64(100*time.Millisecond))
+	})
+
+	t.Run("kept until refilled", func(t *testing.T) {
+		// 5 missing tokens take 3 intervals at 2 per interval, plus one interval of slack
+		_, _, err := store.ConsumeTokens(ctx, "partial", 5, config)
+		require.NoError(t, err)
+
+		ttl, err := client.PTTL(ctx, prefix+"partial").Result()
+		require.NoError(t, err)
+		assert.InDelta(t, 4*config.RefillInterval, ttl, float64(100*time.Millisecond))
+	})
+
+	t.Run("override is kept for the stale bucket age", func(t *testing.T) {
+		_, _, _, err := store.ConsumeTokensWithConfig(ctx, "override", 0, config, &config)
+		require.NoError(t, err)
+
+		ttl, err := client.PTTL(ctx, prefix+"override").Result()
+		require.NoError(t, err)
+		assert.InDelta(t, time.Hour, ttl, float64(time.Second))
+	})
+}
@@ -0,0 +1,89 @@
+package ratelimiter
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Reservation holds tokens consumed by Bucket.Reserve until it is committed or cancelled.
+// It embeds the Result of the reservation, so Allowed, Remaining and ResetAt are available.
+// A reservation that isn't allowed has put the bucket into debt: wait Delay before
+// running the operation, or Cancel it to return the tokens.
+type Reservation struct {
+	*Result
+
+	store  Store
+	config Config // config that applied to the key, possibly an AllowWithConfig override
+	key    string
+	tokens int
+
+	mu   sync.Mutex
+	done bool
+}
+
+// Reserve consumes n tokens like AllowN, but returns a Reservation whose tokens can be
+// given back with Cancel if the operation fails. Call Commit once the operation succeeds.
+func (tb *Bucket) Reserve(ctx context.Context, key string, n int) (*Reservation, error) {
+	if n <= 0 {
+		return nil, fmt.Errorf("%w: must be positive, got %d", ErrInvalidTokenCount, n)
+	}
+
+	result, applied, err := tb.consume(ctx, key, n, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Reservation{
+		Result: result,
+		store:  tb.store,
+		config: applied,
+		key:    key,
+		tokens: n,
+	}, nil
+}
+
+// Delay returns how long to wait until the reserved tokens are refilled.
+// Returns 0 if the reservation was allowed immediately. Unlike RetryAfter,
+// which points at the next refill, it covers every refill needed to pay off
+// the debt, so it's the right wait for reservations larger than RefillRate.
+func (r *Reservation) Delay() time.Duration {
+	if r.Allowed() {
+		return 0
+	}
+
+	intervals := (-r.Remaining + r.config.RefillRate - 1) / r.config.RefillRate
+	return max(0, r.RetryAfter()+time.Duration(intervals-1)*r.config.RefillInterval)
+}
+
+// Commit keeps the reserved tokens consumed. Later Cancel calls are no-ops.
+func (r *Reservation) Commit() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.done = true
+}
+
+// Cancel returns the reserved tokens to the bucket, up to its capacity.
+// Only the first Cancel returns tokens, and none are returned after Commit.
+// Returns ErrReturnNotSupported if the store doesn't implement TokenReturner;
+// the tokens then stay consumed.
+func (r *Reservation) Cancel(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.done {
+		return nil
+	}
+
+	returner, ok := r.store.(TokenReturner)
+	if !ok {
+		return ErrReturnNotSupported
+	}
+	if err := returner.ReturnTokens(ctx, r.key, r.tokens, r.config); err != nil {
+		return err
+	}
+	r.done = true
+	return nil
+}
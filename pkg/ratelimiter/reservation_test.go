@@ -0,0 +1,126 @@
+package ratelimiter_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dmitrymomot/saaskit/pkg/ratelimiter"
+)
+
+func TestBucket_Reserve(t *testing.T) {
+	t.Parallel()
+
+	config := ratelimiter.Config{
+		Capacity:       10,
+		RefillRate:     2,
+		RefillInterval: time.Minute,
+	}
+
+	newBucket := func(t *testing.T) *ratelimiter.Bucket {
+		t.Helper()
+		store := ratelimiter.NewMemoryStore(ratelimiter.WithCleanupInterval(0))
+		t.Cleanup(func() { store.Close() })
+
+		limiter, err := ratelimiter.NewBucket(store, config)
+		require.NoError(t, err)
+		return limiter
+	}
+
+	t.Run("cancel returns tokens", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		limiter := newBucket(t)
+
+		r, err := limiter.Reserve(ctx, "user", 6)
+		require.NoError(t, err)
+		assert.True(t, r.Allowed())
+		assert.Equal(t, 4, r.Remaining)
+		assert.Zero(t, r.Delay())
+
+		require.NoError(t, r.Cancel(ctx))
+		require.NoError(t, r.Cancel(ctx), "second cancel is a no-op")
+
+		status, err := limiter.Status(ctx, "user")
+		require.NoError(t, err)
+		assert.Equal(t, 10, status.Remaining)
+	})
+
+	t.Run("commit keeps tokens consumed", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		limiter := newBucket(t)
+
+		r, err := limiter.Reserve(ctx, "user", 6)
+		require.NoError(t, err)
+		r.Commit()
+		require.NoError(t, r.Cancel(ctx))
+
+		status, err := limiter.Status(ctx, "user")
+		require.NoError(t, err)
+		assert.Equal(t, 4, status.Remaining)
+	})
+
+	t.Run("unsatisfied reservation reports delay", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		limiter := newBucket(t)
+
+		_, err := limiter.AllowN(ctx, "user", 10)
+		require.NoError(t, err)
+
+		r, err := limiter.Reserve(ctx, "user", 5)
+		require.NoError(t, err)
+		assert.False(t, r.Allowed())
+		assert.Equal(t, -5, r.Remaining)
+
+		// 5 tokens at 2 per minute take 3 refills
+		assert.InDelta(t, (3 * time.Minute).Seconds(), r.Delay().Seconds(), 1)
+		assert.InDelta(t, time.Minute.Seconds(), r.RetryAfter().Seconds(), 1)
+
+		require.NoError(t, r.Cancel(ctx))
+		status, err := limiter.Status(ctx, "user")
+		require.NoError(t, err)
+		assert.Equal(t, 0, status.Remaining)
+	})
+
+	t.Run("returned tokens are capped at capacity", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		limiter := newBucket(t)
+
+		r, err := limiter.Reserve(ctx, "user", 4)
+		require.NoError(t, err)
+		require.NoError(t, limiter.Reset(ctx, "user"))
+		_, err = limiter.Allow(ctx, "user")
+		require.NoError(t, err)
+
+		require.NoError(t, r.Cancel(ctx))
+		status, err := limiter.Status(ctx, "user")
+		require.NoError(t, err)
+		assert.Equal(t, 10, status.Remaining)
+	})
+
+	t.Run("rejects non-positive count", func(t *testing.T) {
+		t.Parallel()
+		_, err := newBucket(t).Reserve(context.Background(), "user", 0)
+		assert.ErrorIs(t, err, ratelimiter.ErrInvalidTokenCount)
+	})
+	t.Run("cancel on store without token returns", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		limiter, err := ratelimiter.NewBucket(newMockFailingStore(100, "unused"), ratelimiter.Config{
+			Capacity:       10,
+			RefillRate:     1,
+			RefillInterval: time.Second,
+		})
+		require.NoError(t, err)
+
+		r, err := limiter.Reserve(ctx, "user", 4)
+		require.NoError(t, err)
+		assert.ErrorIs(t, r.Cancel(ctx), ratelimiter.ErrReturnNotSupported)
+	})
+}
@@ -10,7 +10,41 @@ type Store interface {
 	// ConsumeTokens attempts to consume tokens and returns the state after consumption.
 	// If tokens is 0, updates bucket state without consuming (used for status checks).
 	// A negative remaining count indicates the request should be denied.
+	// Stores implementing ConfigOverrideStore use a config override stored for the key
+	// in place of config.
 	ConsumeTokens(ctx context.Context, key string, tokens int, config Config) (remaining int, resetAt time.Time, err error)
 
 	Reset(ctx context.Context, key string) error
 }
+
+// ConfigOverrideStore is implemented by stores that support per-key config overrides
+// (Bucket.AllowWithConfig). MemoryStore and RedisStore implement it.
+type ConfigOverrideStore interface {
+	Store
+
+	// ConsumeTokensWithConfig works like ConsumeTokens and also returns the config that applied.
+	// A non-nil override is stored with the bucket, replacing any previous override, so later
+	// calls for the key use it in place of their config until Reset.
+	ConsumeTokensWithConfig(ctx context.Context, key string, tokens int, config Config, override *Config) (remaining int, resetAt time.Time, applied Config, err error)
+}
+
+// TokenReturner is implemented by stores that can give tokens back to a bucket
+// (Reservation.Cancel). MemoryStore and RedisStore implement it.
+type TokenReturner interface {
+	// ReturnTokens gives previously consumed tokens back to the bucket, up to its capacity.
+	// A bucket that no longer exists is already full, so returning to it is a no-op.
+	ReturnTokens(ctx context.Context, key string, tokens int, config Config) error
+}
+
+// WindowStore defines the interface for sliding window log storage backends.
+type WindowStore interface {
+	// RecordRequests drops requests older than config.Window and records n requests at the
+	// current time if they fit within config.Limit. Denied requests are not recorded.
+	// If n is 0, only prunes the log (used for status checks).
+	// Returns the limit minus logged and requested counts; a negative value indicates
+	// the request should be denied. resetAt is when enough logged requests expire
+	// to admit n more, or when the oldest one expires for allowed requests.
+	RecordRequests(ctx context.Context, key string, n int, config WindowConfig) (remaining int, resetAt time.Time, err error)
+
+	Reset(ctx context.Context, key string) error
+}
@@ -2,6 +2,7 @@ package ratelimiter_test
 
 import (
 	"context"
+	"fmt"
 	"testing"
 	"time"
 
@@ -372,3 +373,221 @@ func TestBucket_MultipleKeys(t *testing.T) {
 		assert.Equal(t, config.Capacity-1, result2.Remaining)
 	})
 }
+
+func TestBucket_RetryJitter(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	config := ratelimiter.Config{
+		Capacity:       1,
+		RefillRate:     1,
+		RefillInterval: 10 * time.Second,
+	}
+
+	t.Run("adds bounded jitter to denied results", func(t *testing.T) {
+		t.Parallel()
+		store := ratelimiter.NewMemoryStore()
+		defer store.Close()
+
+		tb, err := ratelimiter.NewBucket(store, config, ratelimiter.WithRetryJitter(0.5))
+		require.NoError(t, err)
+
+		seen := make(map[time.Duration]bool)
+		for i := range 20 {
+			key := fmt.Sprintf("client-%d", i)
+			_, err := tb.Allow(ctx, key)
+			require.NoError(t, err)
+
+			result, err := tb.Allow(ctx, key)
+			require.NoError(t, err)
+			require.False(t, result.Allowed())
+
+			base := time.Until(result.ResetAt)
+			retryAfter := result.RetryAfter()
+			assert.GreaterOrEqual(t, retryAfter, base-10*time.Millisecond)
+			assert.LessOrEqual(t, retryAfter, base+base/2+10*time.Millisecond)
+			seen[(retryAfter-base)/time.Millisecond] = true
+		}
+		assert.Greater(t, len(seen), 1, "jitter should differ between clients")
+	})
+
+	t.Run("allowed results have no retry delay", func(t *testing.T) {
+		t.Parallel()
+		store := ratelimiter.NewMemoryStore()
+		defer store.Close()
+
+		tb, err := ratelimiter.NewBucket(store, config, ratelimiter.WithRetryJitter(0.3))
+		require.NoError(t, err)
+
+		result, err := tb.Allow(ctx, "allowed")
+		require.NoError(t, err)
+		assert.Zero(t, result.RetryAfter())
+	})
+
+	t.Run("rejects factor out of range", func(t *testing.T) {
+		t.Parallel()
+		store := ratelimiter.NewMemoryStore()
+		defer store.Close()
+
+		_, err := ratelimiter.NewBucket(store, config, ratelimiter.WithRetryJitter(-0.1))
+		assert.ErrorIs(t, err, ratelimiter.ErrInvalidConfig)
+
+		_, err = ratelimiter.NewBucket(store, config, ratelimiter.WithRetryJitter(1.5))
+		assert.ErrorIs(t, err, ratelimiter.ErrInvalidConfig)
+	})
+}
+
+func TestBucket_AllowWithConfig(t *testing.T) {
+	t.Parallel()
+
+	config := ratelimiter.Config{
+		Capacity:       2,
+		RefillRate:     1,
+		RefillInterval: time.Minute,
+	}
+	premium := ratelimiter.Config{
+		Capacity:       5,
+		RefillRate:     5,
+		RefillInterval: time.Minute,
+	}
+
+	newBucket := func(t *testing.T) (*ratelimiter.Bucket, *ratelimiter.MemoryStore) {
+		t.Helper()
+		store := ratelimiter.NewMemoryStore(ratelimiter.WithCleanupInterval(0))
+		t.Cleanup(func() { store.Close() })
+
+		limiter, err := ratelimiter.NewBucket(store, config)
+		require.NoError(t, err)
+		return limiter, store
+	}
+
+	t.Run("applies override capacity", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		limiter, _ := newBucket(t)
+
+		result, err := limiter.AllowWithConfig(ctx, "premium", premium)
+		require.NoError(t, err)
+		assert.Equal(t, 5, result.Limit)
+		assert.Equal(t, 4, result.Remaining)
+
+		// Other keys keep the bucket config
+		result, err = limiter.Allow(ctx, "free")
+		require.NoError(t, err)
+		assert.Equal(t, 2, result.Limit)
+		assert.Equal(t, 1, result.Remaining)
+	})
+
+	t.Run("later calls use the remembered override", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		limiter, _ := newBucket(t)
+
+		_, err := limiter.AllowWithConfig(ctx, "premium", premium)
+		require.NoError(t, err)
+
+		result, err := limiter.AllowN(ctx, "premium", 3)
+		require.NoError(t, err)
+		assert.True(t, result.Allowed())
+		assert.Equal(t, 5, result.Limit)
+		assert.Equal(t, 1, result.Remaining)
+
+		status, err := limiter.Status(ctx, "premium")
+		require.NoError(t, err)
+		assert.Equal(t, 5, status.Limit)
+		assert.Equal(t, 1, status.Remaining)
+	})
+
+	t.Run("reset clears the override", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		limiter, _ := newBucket(t)
+
+		_, err := limiter.AllowWithConfig(ctx, "premium", premium)
+		require.NoError(t, err)
+		require.NoError(t, limiter.Reset(ctx, "premium"))
+
+		status, err := limiter.Status(ctx, "premium")
+		require.NoError(t, err)
+		assert.Equal(t, 2, status.Limit)
+		assert.Equal(t, 2, status.Remaining)
+	})
+
+	t.Run("lowered capacity applies right away", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		limiter, _ := newBucket(t)
+
+		_, err := limiter.AllowWithConfig(ctx, "key", premium)
+		require.NoError(t, err)
+
+		result, err := limiter.AllowWithConfig(ctx, "key", config)
+		require.NoError(t, err)
+		assert.Equal(t, 2, result.Limit)
+		assert.Equal(t, 1, result.Remaining)
+	})
+
+	t.Run("override survives snapshots", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		limiter, store := newBucket(t)
+
+		_, err := limiter.AllowWithConfig(ctx, "premium", premium)
+		require.NoError(t, err)
+		data, err := store.Export()
+		require.NoError(t, err)
+
+		restored := ratelimiter.NewMemoryStore(ratelimiter.WithCleanupInterval(0))
+		defer restored.Close()
+		require.NoError(t, restored.Import(data))
+
+		restoredLimiter, err := ratelimiter.NewBucket(restored, config)
+		require.NoError(t, err)
+		status, err := restoredLimiter.Status(ctx, "premium")
+		require.NoError(t, err)
+		assert.Equal(t, 5, status.Limit)
+		assert.Equal(t, 4, status.Remaining)
+	})
+
+	t.Run("reservation uses the override", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		limiter, _ := newBucket(t)
+
+		_, err := limiter.AllowWithConfig(ctx, "premium", premium)
+		require.NoError(t, err)
+
+		r, err := limiter.Reserve(ctx, "premium", 4)
+		require.NoError(t, err)
+		assert.True(t, r.Allowed())
+		require.NoError(t, r.Cancel(ctx))
+
+		status, err := limiter.Status(ctx, "premium")
+		require.NoError(t, err)
+		assert.Equal(t, 4, status.Remaining)
+	})
+
+	t.Run("validates override", func(t *testing.T) {
+		t.Parallel()
+		limiter, _ := newBucket(t)
+
+		_, err := limiter.AllowWithConfig(context.Background(), "key", ratelimiter.Config{Capacity: 0, RefillRate: 1, RefillInterval: time.Second})
+		require.ErrorIs(t, err, ratelimiter.ErrInvalidConfig)
+		assert.Contains(t, err.Error(), "capacity must be positive")
+	})
+
+	t.Run("store without override support", func(t *testing.T) {
+		t.Parallel()
+		// A Store that only implements the base interface keeps working for AllowN
+		store := newMockFailingStore(100, "unused")
+		limiter, err := ratelimiter.NewBucket(store, ratelimiter.Config{Capacity: 5, RefillRate: 1, RefillInterval: time.Second})
+		require.NoError(t, err)
+
+		result, err := limiter.Allow(context.Background(), "key")
+		require.NoError(t, err)
+		assert.Equal(t, 5, result.Limit)
+
+		_, err = limiter.AllowWithConfig(context.Background(), "key", premium)
+		require.ErrorIs(t, err, ratelimiter.ErrOverridesNotSupported)
+	})
+}
@@ -7,6 +7,8 @@ type Result struct {
 	Limit     int       // Maximum tokens (bucket capacity)
 	Remaining int       // Tokens remaining (negative means denied)
 	ResetAt   time.Time // When next token refill occurs
+
+	retryJitter time.Duration // Extra delay advertised by RetryAfter, see WithRetryJitter
 }
 
 func (r *Result) Allowed() bool {
@@ -14,12 +16,19 @@ func (r *Result) Allowed() bool {
 }
 
 // RetryAfter returns how long to wait before retry is likely to succeed.
-// Returns 0 if the request was allowed.
+// Returns 0 if the request was allowed. Includes random jitter when the
+// bucket was created with WithRetryJitter.
 func (r *Result) RetryAfter() time.Duration {
 	if r.Allowed() {
 		return 0
 	}
-	return time.Until(r.ResetAt)
+	return time.Until(r.ResetAt) + r.retryJitter
+}
+
+// WindowConfig defines the sliding window log rate limiting parameters.
+type WindowConfig struct {
+	Limit  int           // Maximum requests within any window
+	Window time.Duration // Length of the rolling window
 }
 
 // Config defines the token bucket rate limiting parameters.
@@ -0,0 +1,75 @@
+package ratelimiter
+
+import (
+	"context"
+	"fmt"
+)
+
+// SlidingWindowLimiter implements a sliding window log rate limiter.
+// Unlike Bucket it allows no bursts beyond Limit within any rolling window,
+// at the cost of keeping a timestamp per allowed request.
+type SlidingWindowLimiter struct {
+	store  WindowStore
+	config WindowConfig
+}
+
+// NewSlidingWindow creates a new sliding window log rate limiter.
+func NewSlidingWindow(store WindowStore, config WindowConfig) (*SlidingWindowLimiter, error) {
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+
+	return &SlidingWindowLimiter{
+		store:  store,
+		config: config,
+	}, nil
+}
+
+func (sw *SlidingWindowLimiter) Allow(ctx context.Context, key string) (*Result, error) {
+	return sw.AllowN(ctx, key, 1)
+}
+
+func (sw *SlidingWindowLimiter) AllowN(ctx context.Context, key string, n int) (*Result, error) {
+	if n <= 0 {
+		return nil, fmt.Errorf("%w: must be positive, got %d", ErrInvalidTokenCount, n)
+	}
+
+	remaining, resetAt, err := sw.store.RecordRequests(ctx, key, n, sw.config)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Result{
+		Limit:     sw.config.Limit,
+		Remaining: remaining,
+		ResetAt:   resetAt,
+	}, nil
+}
+
+// Status returns the current state without recording a request.
+func (sw *SlidingWindowLimiter) Status(ctx context.Context, key string) (*Result, error) {
+	remaining, resetAt, err := sw.store.RecordRequests(ctx, key, 0, sw.config)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Result{
+		Limit:     sw.config.Limit,
+		Remaining: remaining,
+		ResetAt:   resetAt,
+	}, nil
+}
+
+func (sw *SlidingWindowLimiter) Reset(ctx context.Context, key string) error {
+	return sw.store.Reset(ctx, key)
+}
+
+func (c WindowConfig) validate() error {
+	if c.Limit <= 0 {
+		return fmt.Errorf("%w: limit must be positive, got %d", ErrInvalidConfig, c.Limit)
+	}
+	if c.Window <= 0 {
+		return fmt.Errorf("%w: window must be positive, got %v", ErrInvalidConfig, c.Window)
+	}
+	return nil
+}
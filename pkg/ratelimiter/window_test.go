@@ -0,0 +1,173 @@
+package ratelimiter_test
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dmitrymomot/saaskit/pkg/ratelimiter"
+)
+
+func TestNewSlidingWindow(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		config   ratelimiter.WindowConfig
+		errorMsg string
+	}{
+		{name: "valid config", config: ratelimiter.WindowConfig{Limit: 10, Window: time.Minute}},
+		{name: "zero limit", config: ratelimiter.WindowConfig{Limit: 0, Window: time.Minute}, errorMsg: "limit must be positive"},
+		{name: "zero window", config: ratelimiter.WindowConfig{Limit: 10}, errorMsg: "window must be positive"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			store := ratelimiter.NewMemoryStore()
+			defer store.Close()
+
+			limiter, err := ratelimiter.NewSlidingWindow(store, tt.config)
+			if tt.errorMsg != "" {
+				require.ErrorIs(t, err, ratelimiter.ErrInvalidConfig)
+				assert.Contains(t, err.Error(), tt.errorMsg)
+				assert.Nil(t, limiter)
+				return
+			}
+			require.NoError(t, err)
+			assert.NotNil(t, limiter)
+		})
+	}
+}
+
+func TestSlidingWindowLimiter(t *testing.T) {
+	t.Parallel()
+
+	newLimiter := func(t *testing.T, config ratelimiter.WindowConfig) *ratelimiter.SlidingWindowLimiter {
+		t.Helper()
+		store := ratelimiter.NewMemoryStore()
+		t.Cleanup(func() { store.Close() })
+
+		limiter, err := ratelimiter.NewSlidingWindow(store, config)
+		require.NoError(t, err)
+		return limiter
+	}
+
+	t.Run("denies requests over the limit without bursts", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		limiter := newLimiter(t, ratelimiter.WindowConfig{Limit: 3, Window: time.Minute})
+
+		for i := range 3 {
+			result, err := limiter.Allow(ctx, "user")
+			require.NoError(t, err)
+			assert.True(t, result.Allowed())
+			assert.Equal(t, 3, result.Limit)
+			assert.Equal(t, 2-i, result.Remaining)
+			assert.WithinDuration(t, time.Now().Add(time.Minute), result.ResetAt, time.Second)
+		}
+
+		result, err := limiter.Allow(ctx, "user")
+		require.NoError(t, err)
+		assert.False(t, result.Allowed())
+		assert.InDelta(t, time.Minute.Seconds(), result.RetryAfter().Seconds(), 1)
+
+		// Denied requests aren't logged, so status is unchanged
+		status, err := limiter.Status(ctx, "user")
+		require.NoError(t, err)
+		assert.Equal(t, 0, status.Remaining)
+	})
+
+	t.Run("frees slots as requests leave the window", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		limiter := newLimiter(t, ratelimiter.WindowConfig{Limit: 2, Window: 100 * time.Millisecond})
+
+		first, err := limiter.Allow(ctx, "user")
+		require.NoError(t, err)
+		time.Sleep(60 * time.Millisecond)
+		_, err = limiter.Allow(ctx, "user")
+		require.NoError(t, err)
+
+		denied, err := limiter.Allow(ctx, "user")
+		require.NoError(t, err)
+		require.False(t, denied.Allowed())
+		assert.Equal(t, first.ResetAt, denied.ResetAt, "retry once the oldest request expires")
+
+		time.Sleep(time.Until(denied.ResetAt) + 10*time.Millisecond)
+
+		result, err := limiter.Allow(ctx, "user")
+		require.NoError(t, err)
+		assert.True(t, result.Allowed())
+		assert.Equal(t, 0, result.Remaining)
+	})
+
+	t.Run("AllowN waits for enough slots", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		limiter := newLimiter(t, ratelimiter.WindowConfig{Limit: 5, Window: time.Minute})
+
+		result, err := limiter.AllowN(ctx, "user", 4)
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.Remaining)
+
+		result, err = limiter.AllowN(ctx, "user", 3)
+		require.NoError(t, err)
+		assert.False(t, result.Allowed())
+		assert.Equal(t, -2, result.Remaining)
+
+		_, err = limiter.AllowN(ctx, "user", 0)
+		require.ErrorIs(t, err, ratelimiter.ErrInvalidTokenCount)
+	})
+
+	t.Run("status of unused key", func(t *testing.T) {
+		t.Parallel()
+		limiter := newLimiter(t, ratelimiter.WindowConfig{Limit: 5, Window: time.Minute})
+
+		status, err := limiter.Status(context.Background(), "unused")
+		require.NoError(t, err)
+		assert.True(t, status.Allowed())
+		assert.Equal(t, 5, status.Remaining)
+	})
+
+	t.Run("reset clears the log", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		limiter := newLimiter(t, ratelimiter.WindowConfig{Limit: 1, Window: time.Minute})
+
+		_, err := limiter.Allow(ctx, "user")
+		require.NoError(t, err)
+		require.NoError(t, limiter.Reset(ctx, "user"))
+
+		result, err := limiter.Allow(ctx, "user")
+		require.NoError(t, err)
+		assert.True(t, result.Allowed())
+	})
+
+	t.Run("concurrent requests never exceed the limit", func(t *testing.T) {
+		t.Parallel()
+		limiter := newLimiter(t, ratelimiter.WindowConfig{Limit: 10, Window: time.Minute})
+
+		var allowed atomic.Int32
+		var wg sync.WaitGroup
+		for range 50 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				result, err := limiter.Allow(context.Background(), "shared")
+				assert.NoError(t, err)
+				if result != nil && result.Allowed() {
+					allowed.Add(1)
+				}
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, int32(10), allowed.Load())
+	})
+}
@@ -20,7 +20,7 @@
 //     tags & attributes, SQL/LDAP/shell metacharacters, path traversal, …) and
 //     that mask sensitive data before it is logged or rendered.
 //
-// The package is completely stateless and depends only on the Go standard
+// Apart from the named pipeline registry the package is stateless and depends only on the Go standard
 // library (plus the `maps` package from Go 1.21+). All helpers are implemented
 // as small, focused functions that can be freely combined.  For convenience the
 // higher-order Apply and Compose helpers allow the creation of sanitisation
@@ -34,6 +34,15 @@
 //
 //	safe := clean("  Mixed CASE   Input\n") // "mixed case input"
 //
+// Pipelines shared across packages can be registered once under a name, so
+// the same policy is guaranteed to be applied everywhere:
+//
+//	sanitizer.RegisterPipeline("username", sanitizer.Trim, sanitizer.ToLower)
+//
+//	username, err := sanitizer.ApplyPipeline("username", input)
+//
+// MustPipeline resolves a pipeline at startup and panics on unknown names.
+//
 // # Usage
 //
 // Import the package using its module-qualified path:
@@ -58,8 +67,8 @@
 // # Performance
 //
 // All operations are implemented with efficiency in mind and allocate only what
-// is necessary.  Because there is no global state the helpers are safe for use
-// from multiple goroutines concurrently.
+// is necessary.  The helpers are pure and the pipeline registry is guarded by a
+// mutex, so everything is safe for use from multiple goroutines concurrently.
 //
 // See the package-level examples and individual function documentation for
 // further details.
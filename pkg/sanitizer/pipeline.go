@@ -0,0 +1,96 @@
+package sanitizer
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+var (
+	ErrPipelineNotFound     = errors.New("sanitizer pipeline not found")
+	ErrPipelineTypeMismatch = errors.New("sanitizer pipeline registered for a different type")
+)
+
+// Pipeline is a named, reusable sanitization chain.
+// Register it once with RegisterPipeline so the same policy is applied everywhere.
+type Pipeline[T any] struct {
+	name       string
+	transforms []func(T) T
+}
+
+// Name returns the name the pipeline was registered under.
+func (p Pipeline[T]) Name() string {
+	return p.name
+}
+
+// Apply runs the pipeline transforms in order.
+func (p Pipeline[T]) Apply(value T) T {
+	return Apply(value, p.transforms...)
+}
+
+var (
+	pipelinesMu sync.RWMutex
+	pipelines   = make(map[string]any)
+)
+
+// RegisterPipeline registers a named pipeline for values of type T.
+// Intended to be called during initialization; panics if the name is empty
+// or already registered, since two policies under one name defeat the purpose.
+func RegisterPipeline[T any](name string, transforms ...func(T) T) Pipeline[T] {
+	if name == "" {
+		panic("sanitizer: pipeline name is required")
+	}
+
+	p := Pipeline[T]{name: name, transforms: append([]func(T) T(nil), transforms...)}
+
+	pipelinesMu.Lock()
+	defer pipelinesMu.Unlock()
+
+	if _, exists := pipelines[name]; exists {
+		panic(fmt.Sprintf("sanitizer: pipeline %q is already registered", name))
+	}
+	pipelines[name] = p
+
+	return p
+}
+
+// GetPipeline looks up a registered pipeline for values of type T.
+func GetPipeline[T any](name string) (Pipeline[T], error) {
+	pipelinesMu.RLock()
+	registered, ok := pipelines[name]
+	pipelinesMu.RUnlock()
+
+	if !ok {
+		return Pipeline[T]{}, fmt.Errorf("%w: %q", ErrPipelineNotFound, name)
+	}
+
+	p, ok := registered.(Pipeline[T])
+	if !ok {
+		return Pipeline[T]{}, fmt.Errorf("%w: %q", ErrPipelineTypeMismatch, name)
+	}
+
+	return p, nil
+}
+
+// MustPipeline is like GetPipeline but panics on unknown names or type mismatches.
+// Resolve pipelines at startup so misconfiguration fails fast:
+//
+//	var cleanUsername = sanitizer.MustPipeline[string]("username")
+func MustPipeline[T any](name string) Pipeline[T] {
+	p, err := GetPipeline[T](name)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// ApplyPipeline sanitizes value with the pipeline registered under name.
+// Unlike the other helpers it returns an error, because silently skipping
+// a missing pipeline would leave the input unsanitized.
+func ApplyPipeline[T any](name string, value T) (T, error) {
+	p, err := GetPipeline[T](name)
+	if err != nil {
+		return value, err
+	}
+	return p.Apply(value), nil
+}
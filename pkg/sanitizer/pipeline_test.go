@@ -0,0 +1,98 @@
+package sanitizer_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dmitrymomot/saaskit/pkg/sanitizer"
+)
+
+// Pipelines live in a global registry, so every test uses unique names.
+
+func TestRegisterPipeline(t *testing.T) {
+	t.Parallel()
+
+	t.Run("registered pipeline is applied by name", func(t *testing.T) {
+		t.Parallel()
+		p := sanitizer.RegisterPipeline("test-username", sanitizer.Trim, sanitizer.ToLower, sanitizer.RemoveControlChars)
+		assert.Equal(t, "test-username", p.Name())
+		assert.Equal(t, "john", p.Apply("  JoHN\x00 "))
+
+		result, err := sanitizer.ApplyPipeline("test-username", "  Alice ")
+		require.NoError(t, err)
+		assert.Equal(t, "alice", result)
+	})
+
+	t.Run("works with non-string types", func(t *testing.T) {
+		t.Parallel()
+		sanitizer.RegisterPipeline("test-percent", func(n int) int { return sanitizer.Clamp(n, 0, 100) })
+
+		result, err := sanitizer.ApplyPipeline("test-percent", 150)
+		require.NoError(t, err)
+		assert.Equal(t, 100, result)
+	})
+
+	t.Run("panics on duplicate name", func(t *testing.T) {
+		t.Parallel()
+		sanitizer.RegisterPipeline("test-duplicate", sanitizer.Trim)
+		assert.Panics(t, func() {
+			sanitizer.RegisterPipeline("test-duplicate", sanitizer.ToLower)
+		})
+	})
+
+	t.Run("panics on empty name", func(t *testing.T) {
+		t.Parallel()
+		assert.Panics(t, func() {
+			sanitizer.RegisterPipeline("", sanitizer.Trim)
+		})
+	})
+
+	t.Run("copies transforms", func(t *testing.T) {
+		t.Parallel()
+		transforms := []func(string) string{sanitizer.Trim}
+		p := sanitizer.RegisterPipeline("test-copy", transforms...)
+		transforms[0] = sanitizer.ToUpper
+
+		assert.Equal(t, "abc", p.Apply(" abc "))
+	})
+}
+
+func TestApplyPipeline(t *testing.T) {
+	t.Parallel()
+
+	t.Run("unknown pipeline returns input and error", func(t *testing.T) {
+		t.Parallel()
+		result, err := sanitizer.ApplyPipeline("test-unknown", " input ")
+		assert.ErrorIs(t, err, sanitizer.ErrPipelineNotFound)
+		assert.Equal(t, " input ", result)
+	})
+
+	t.Run("type mismatch returns error", func(t *testing.T) {
+		t.Parallel()
+		sanitizer.RegisterPipeline("test-mismatch", sanitizer.Trim)
+
+		_, err := sanitizer.ApplyPipeline("test-mismatch", 42)
+		assert.ErrorIs(t, err, sanitizer.ErrPipelineTypeMismatch)
+	})
+}
+
+func TestMustPipeline(t *testing.T) {
+	t.Parallel()
+
+	t.Run("returns registered pipeline", func(t *testing.T) {
+		t.Parallel()
+		sanitizer.RegisterPipeline("test-must", sanitizer.TrimToUpper)
+
+		p := sanitizer.MustPipeline[string]("test-must")
+		assert.Equal(t, "ABC", p.Apply(" abc "))
+	})
+
+	t.Run("panics on unknown name", func(t *testing.T) {
+		t.Parallel()
+		assert.Panics(t, func() {
+			sanitizer.MustPipeline[string]("test-must-unknown")
+		})
+	})
+}
@@ -5,8 +5,8 @@
 // workspace (tenant) key using HKDF-SHA-256. The derived key is then used with
 // AES-256 in GCM mode to protect arbitrary byte slices or UTF-8 strings.
 //
-// On successful encryption the nonce is prepended to the ciphertext so that
-// all necessary data is self-contained. All operations are constant-time with
+// On successful encryption a version byte and the nonce are prepended to the
+// ciphertext so that all necessary data is self-contained. All operations are constant-time with
 // respect to secret material.
 //
 // # Architecture
@@ -40,6 +40,48 @@
 //	    // handle error
 //	}
 //
+// # Key Providers and KMS
+//
+// The *WithProvider functions take a KeyProvider instead of a raw application
+// key, so the key-encryption key (KEK) can live in AWS KMS, Cloud KMS or an HSM
+// and never enter process memory. The provider only returns the derived
+// workspace key, which is used locally for AES-GCM and cleared afterwards.
+// StaticKeyProvider is the in-memory implementation and produces ciphertexts
+// compatible with EncryptBytes/DecryptBytes.
+//
+// A typical envelope setup computes the derived key inside the key service,
+// e.g. with an HMAC key that cannot be exported:
+//
+//	kms := secrets.KeyProviderFunc(func(ctx context.Context, workspaceKey []byte) ([]byte, error) {
+//	    out, err := kmsClient.GenerateMac(ctx, &kms.GenerateMacInput{
+//	        KeyId:        aws.String(macKeyID),
+//	        MacAlgorithm: types.MacAlgorithmSpecHmacSha256,
+//	        Message:      workspaceKey,
+//	    })
+//	    if err != nil {
+//	        return nil, err
+//	    }
+//	    return out.Mac, nil
+//	})
+//
+//	ct, err := secrets.EncryptStringWithProvider(ctx, kms, workspaceKey, "sk_live_...")
+//
+// Cache derived keys per workspace if KMS latency matters; the derivation is deterministic.
+//
+// # Ciphertext Versions
+//
+// The first byte of the ciphertext selects the algorithm (VersionAESGCM) and is
+// authenticated as additional data. Ciphertext written before versioning has no
+// header; Decrypt* functions detect it and decrypt it as VersionLegacy. New
+// algorithms are added as new versions while old ones stay readable, and
+// MigrateBytes, MigrateString and MigrateBytesWithProvider re-encrypt older
+// ciphertext with CurrentVersion:
+//
+//	out, migrated, err := secrets.MigrateString(appKey, workspaceKey, stored)
+//	if err == nil && migrated {
+//	    // write out back to storage
+//	}
+//
 // # Error Handling
 //
 // All public functions return rich errors that wrap a sentinel package error
@@ -14,4 +14,5 @@ var (
 
 	// Key derivation errors
 	ErrKeyDerivationFailed = errors.New("key derivation failed")
+	ErrKeyProviderRequired = errors.New("key provider is required")
 )
@@ -0,0 +1,123 @@
+package secrets
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+)
+
+// KeyProvider derives the per-workspace encryption key without exposing the
+// application key to the caller. Implementations backed by a KMS or HSM can keep
+// the application key (KEK) inside the key service and only return the derived
+// workspace key, which is used locally for AES-GCM.
+//
+// DeriveKey must be deterministic for a given workspace key and return a fresh
+// KeySize slice owned by the caller; it is cleared from memory after use.
+type KeyProvider interface {
+	DeriveKey(ctx context.Context, workspaceKey []byte) ([]byte, error)
+}
+
+// KeyProviderFunc adapts a function to the KeyProvider interface, which is
+// convenient for wrapping a KMS client call.
+type KeyProviderFunc func(ctx context.Context, workspaceKey []byte) ([]byte, error)
+
+// DeriveKey calls f(ctx, workspaceKey).
+func (f KeyProviderFunc) DeriveKey(ctx context.Context, workspaceKey []byte) ([]byte, error) {
+	return f(ctx, workspaceKey)
+}
+
+// StaticKeyProvider holds the application key in process memory and derives
+// workspace keys with HKDF, exactly like EncryptBytes/DecryptBytes.
+type StaticKeyProvider struct {
+	appKey []byte
+}
+
+var _ KeyProvider = (*StaticKeyProvider)(nil)
+
+// NewStaticKeyProvider creates a provider for a 32-byte application key.
+// The key is copied, so the caller may clear its own slice.
+func NewStaticKeyProvider(appKey []byte) (*StaticKeyProvider, error) {
+	if len(appKey) != KeySize {
+		return nil, ErrInvalidAppKey
+	}
+	return &StaticKeyProvider{appKey: append([]byte(nil), appKey...)}, nil
+}
+
+// DeriveKey derives the compound key for the workspace.
+func (p *StaticKeyProvider) DeriveKey(_ context.Context, workspaceKey []byte) ([]byte, error) {
+	if err := ValidateKeys(p.appKey, workspaceKey); err != nil {
+		return nil, err
+	}
+	return deriveKey(p.appKey, workspaceKey)
+}
+
+// EncryptBytesWithProvider encrypts data with the key derived by the provider.
+// The output format is identical to EncryptBytes, so a StaticKeyProvider
+// interoperates with the key-based functions.
+func EncryptBytesWithProvider(ctx context.Context, provider KeyProvider, workspaceKey, data []byte) ([]byte, error) {
+	key, err := providerKey(ctx, provider, workspaceKey)
+	if err != nil {
+		return nil, err
+	}
+	defer clearBytes(key)
+
+	return seal(key, data)
+}
+
+// DecryptBytesWithProvider decrypts ciphertext produced by EncryptBytesWithProvider or EncryptBytes.
+func DecryptBytesWithProvider(ctx context.Context, provider KeyProvider, workspaceKey, ciphertext []byte) ([]byte, error) {
+	key, err := providerKey(ctx, provider, workspaceKey)
+	if err != nil {
+		return nil, err
+	}
+	defer clearBytes(key)
+
+	return open(key, ciphertext)
+}
+
+// EncryptStringWithProvider encrypts a string and returns base64-encoded ciphertext.
+func EncryptStringWithProvider(ctx context.Context, provider KeyProvider, workspaceKey []byte, plaintext string) (string, error) {
+	ciphertext, err := EncryptBytesWithProvider(ctx, provider, workspaceKey, []byte(plaintext))
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(ciphertext), nil
+}
+
+// DecryptStringWithProvider decrypts a base64-encoded ciphertext back to string.
+func DecryptStringWithProvider(ctx context.Context, provider KeyProvider, workspaceKey []byte, ciphertext string) (string, error) {
+	ciphertextBytes, err := base64.StdEncoding.DecodeString(ciphertext)
+	if err != nil {
+		return "", errors.Join(ErrInvalidCiphertext, err)
+	}
+
+	plaintextBytes, err := DecryptBytesWithProvider(ctx, provider, workspaceKey, ciphertextBytes)
+	if err != nil {
+		return "", err
+	}
+
+	return string(plaintextBytes), nil
+}
+
+func providerKey(ctx context.Context, provider KeyProvider, workspaceKey []byte) ([]byte, error) {
+	if provider == nil {
+		return nil, ErrKeyProviderRequired
+	}
+	if len(workspaceKey) != KeySize {
+		return nil, ErrInvalidWorkspaceKey
+	}
+
+	key, err := provider.DeriveKey(ctx, workspaceKey)
+	if err != nil {
+		if errors.Is(err, ErrKeyDerivationFailed) || errors.Is(err, ErrInvalidAppKey) || errors.Is(err, ErrInvalidWorkspaceKey) {
+			return nil, err
+		}
+		return nil, errors.Join(ErrKeyDerivationFailed, err)
+	}
+	if len(key) != KeySize {
+		clearBytes(key)
+		return nil, errors.Join(ErrKeyDerivationFailed, fmt.Errorf("provider returned %d-byte key, want %d", len(key), KeySize))
+	}
+	return key, nil
+}
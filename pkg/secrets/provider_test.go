@@ -0,0 +1,128 @@
+package secrets_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"errors"
+	"testing"
+
+	"github.com/dmitrymomot/saaskit/pkg/secrets"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStaticKeyProvider(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	appKey, err := secrets.GenerateKey()
+	require.NoError(t, err)
+	workspaceKey, err := secrets.GenerateKey()
+	require.NoError(t, err)
+
+	provider, err := secrets.NewStaticKeyProvider(appKey)
+	require.NoError(t, err)
+
+	t.Run("roundtrip", func(t *testing.T) {
+		t.Parallel()
+		ct, err := secrets.EncryptStringWithProvider(ctx, provider, workspaceKey, "sk_live_123")
+		require.NoError(t, err)
+
+		plain, err := secrets.DecryptStringWithProvider(ctx, provider, workspaceKey, ct)
+		require.NoError(t, err)
+		require.Equal(t, "sk_live_123", plain)
+	})
+
+	t.Run("interoperates with key-based functions", func(t *testing.T) {
+		t.Parallel()
+		ct, err := secrets.EncryptBytes(appKey, workspaceKey, []byte("data"))
+		require.NoError(t, err)
+
+		plain, err := secrets.DecryptBytesWithProvider(ctx, provider, workspaceKey, ct)
+		require.NoError(t, err)
+		require.Equal(t, []byte("data"), plain)
+
+		ct, err = secrets.EncryptBytesWithProvider(ctx, provider, workspaceKey, []byte("data"))
+		require.NoError(t, err)
+
+		plain, err = secrets.DecryptBytes(appKey, workspaceKey, ct)
+		require.NoError(t, err)
+		require.Equal(t, []byte("data"), plain)
+	})
+
+	t.Run("provider copies app key", func(t *testing.T) {
+		t.Parallel()
+		key, err := secrets.GenerateKey()
+		require.NoError(t, err)
+		p, err := secrets.NewStaticKeyProvider(key)
+		require.NoError(t, err)
+
+		ct, err := secrets.EncryptBytesWithProvider(ctx, p, workspaceKey, []byte("data"))
+		require.NoError(t, err)
+		original := append([]byte(nil), key...)
+		secrets.ClearBytesForTesting(key)
+
+		plain, err := secrets.DecryptBytes(original, workspaceKey, ct)
+		require.NoError(t, err)
+		require.Equal(t, []byte("data"), plain)
+	})
+
+	t.Run("invalid keys", func(t *testing.T) {
+		t.Parallel()
+		_, err := secrets.NewStaticKeyProvider([]byte("short"))
+		require.ErrorIs(t, err, secrets.ErrInvalidAppKey)
+
+		_, err = secrets.EncryptBytesWithProvider(ctx, provider, []byte("short"), []byte("data"))
+		require.ErrorIs(t, err, secrets.ErrInvalidWorkspaceKey)
+
+		_, err = secrets.EncryptBytesWithProvider(ctx, nil, workspaceKey, []byte("data"))
+		require.ErrorIs(t, err, secrets.ErrKeyProviderRequired)
+	})
+}
+
+func TestKeyProviderFunc(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	workspaceKey, err := secrets.GenerateKey()
+	require.NoError(t, err)
+
+	// Simulates a KMS/HSM computing an HMAC with a key it never releases
+	kmsKey, err := secrets.GenerateKey()
+	require.NoError(t, err)
+	kms := secrets.KeyProviderFunc(func(_ context.Context, workspaceKey []byte) ([]byte, error) {
+		mac := hmac.New(sha256.New, kmsKey)
+		mac.Write(workspaceKey)
+		return mac.Sum(nil), nil
+	})
+
+	t.Run("roundtrip", func(t *testing.T) {
+		t.Parallel()
+		ct, err := secrets.EncryptBytesWithProvider(ctx, kms, workspaceKey, []byte("data"))
+		require.NoError(t, err)
+
+		plain, err := secrets.DecryptBytesWithProvider(ctx, kms, workspaceKey, ct)
+		require.NoError(t, err)
+		require.Equal(t, []byte("data"), plain)
+	})
+
+	t.Run("provider errors are wrapped", func(t *testing.T) {
+		t.Parallel()
+		failing := secrets.KeyProviderFunc(func(context.Context, []byte) ([]byte, error) {
+			return nil, errors.New("kms unavailable")
+		})
+		_, err := secrets.EncryptBytesWithProvider(ctx, failing, workspaceKey, []byte("data"))
+		require.ErrorIs(t, err, secrets.ErrKeyDerivationFailed)
+		require.ErrorContains(t, err, "kms unavailable")
+	})
+
+	t.Run("rejects wrong key size", func(t *testing.T) {
+		t.Parallel()
+		short := secrets.KeyProviderFunc(func(context.Context, []byte) ([]byte, error) {
+			return []byte("too-short"), nil
+		})
+		_, err := secrets.EncryptBytesWithProvider(ctx, short, workspaceKey, []byte("data"))
+		require.ErrorIs(t, err, secrets.ErrKeyDerivationFailed)
+	})
+}
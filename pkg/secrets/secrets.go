@@ -1,8 +1,6 @@
 package secrets
 
 import (
-	"crypto/aes"
-	"crypto/cipher"
 	"crypto/rand"
 	"encoding/base64"
 	"errors"
@@ -35,7 +33,7 @@ func DecryptString(appKey, workspaceKey []byte, ciphertext string) (string, erro
 }
 
 // EncryptBytes encrypts raw bytes using compound key from app and workspace keys.
-// Returns ciphertext in format: nonce + encrypted data + tag
+// Returns ciphertext in format: version + nonce + encrypted data + tag
 func EncryptBytes(appKey, workspaceKey []byte, data []byte) ([]byte, error) {
 	// Validate keys
 	if err := ValidateKeys(appKey, workspaceKey); err != nil {
@@ -50,32 +48,12 @@ func EncryptBytes(appKey, workspaceKey []byte, data []byte) ([]byte, error) {
 	// Clear key from memory when done
 	defer clearBytes(key)
 
-	// Create AES cipher
-	block, err := aes.NewCipher(key)
-	if err != nil {
-		return nil, errors.Join(ErrEncryptionFailed, err)
-	}
-
-	// Create GCM mode
-	aesGCM, err := cipher.NewGCM(block)
-	if err != nil {
-		return nil, errors.Join(ErrEncryptionFailed, err)
-	}
-
-	// Generate nonce
-	nonce := make([]byte, aesGCM.NonceSize())
-	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
-		return nil, errors.Join(ErrEncryptionFailed, err)
-	}
-
-	// Encrypt and prepend nonce to ciphertext for storage
-	ciphertext := aesGCM.Seal(nonce, nonce, data, nil)
-
-	return ciphertext, nil
+	return seal(key, data)
 }
 
 // DecryptBytes decrypts ciphertext back to raw bytes.
-// Expects ciphertext in format: nonce + encrypted data + tag
+// Expects ciphertext in format: version + nonce + encrypted data + tag;
+// legacy ciphertext without the version header is still accepted.
 func DecryptBytes(appKey, workspaceKey []byte, ciphertext []byte) ([]byte, error) {
 	// Validate keys
 	if err := ValidateKeys(appKey, workspaceKey); err != nil {
@@ -90,31 +68,31 @@ func DecryptBytes(appKey, workspaceKey []byte, ciphertext []byte) ([]byte, error
 	// Clear key from memory when done
 	defer clearBytes(key)
 
-	// Create AES cipher
-	block, err := aes.NewCipher(key)
-	if err != nil {
-		return nil, errors.Join(ErrDecryptionFailed, err)
-	}
+	return open(key, ciphertext)
+}
 
-	// Create GCM mode
-	aesGCM, err := cipher.NewGCM(block)
+// seal encrypts data with the CurrentVersion algorithm.
+// Returns ciphertext in format: version + nonce + encrypted data + tag.
+func seal(key, data []byte) ([]byte, error) {
+	aead, err := algorithms[CurrentVersion](key)
 	if err != nil {
-		return nil, errors.Join(ErrDecryptionFailed, err)
+		return nil, errors.Join(ErrEncryptionFailed, err)
 	}
 
-	// Extract nonce
-	nonceSize := aesGCM.NonceSize()
-	if len(ciphertext) < nonceSize {
-		return nil, ErrInvalidCiphertext
+	// Version header followed by a random nonce
+	header := make([]byte, 1+aead.NonceSize(), 1+aead.NonceSize()+len(data)+aead.Overhead())
+	header[0] = CurrentVersion
+	if _, err := io.ReadFull(rand.Reader, header[1:]); err != nil {
+		return nil, errors.Join(ErrEncryptionFailed, err)
 	}
 
-	nonce, ciphertext := ciphertext[:nonceSize], ciphertext[nonceSize:]
-
-	// Decrypt data
-	plaintext, err := aesGCM.Open(nil, nonce, ciphertext, nil)
-	if err != nil {
-		return nil, errors.Join(ErrDecryptionFailed, err)
-	}
+	// Authenticate the version byte so it can't be tampered with
+	return aead.Seal(header, header[1:], data, header[:1]), nil
+}
 
-	return plaintext, nil
+// open reverses seal, dispatching on the version header.
+// Headerless ciphertext from before versioning is decrypted as the legacy format.
+func open(key, ciphertext []byte) ([]byte, error) {
+	plaintext, _, err := openVersioned(key, ciphertext)
+	return plaintext, err
 }
@@ -0,0 +1,154 @@
+package secrets
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/base64"
+	"errors"
+)
+
+// Ciphertext format versions. Versioned ciphertext starts with a 1-byte header
+// that selects the algorithm; the header is authenticated as additional data,
+// so it can't be swapped or stripped without failing decryption.
+const (
+	// VersionLegacy is the original headerless format: nonce + encrypted data + tag.
+	// It is never written, only detected on decryption.
+	VersionLegacy byte = 0x00
+
+	// VersionAESGCM is AES-256-GCM with the format: version + nonce + encrypted data + tag.
+	VersionAESGCM byte = 0x01
+
+	// CurrentVersion is the format used for new ciphertext.
+	CurrentVersion = VersionAESGCM
+)
+
+// algorithms maps versions to AEAD constructors. A new algorithm, such as
+// XChaCha20-Poly1305, is added as a new version and becomes CurrentVersion,
+// while older versions stay decryptable.
+var algorithms = map[byte]func(key []byte) (cipher.AEAD, error){
+	VersionAESGCM: newAESGCM,
+}
+
+func newAESGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// openVersioned decrypts ciphertext in any supported format and reports its version.
+//
+// Legacy ciphertext starts with a random nonce, so its first byte can collide with a
+// version header. Authentication tells the two apart: when the versioned attempt
+// fails, the whole input is tried as legacy ciphertext.
+func openVersioned(key, ciphertext []byte) ([]byte, byte, error) {
+	if len(ciphertext) == 0 {
+		return nil, 0, ErrInvalidCiphertext
+	}
+
+	var versionErr error
+	if newAEAD, ok := algorithms[ciphertext[0]]; ok {
+		plaintext, err := openAEAD(newAEAD, key, ciphertext[1:], ciphertext[:1])
+		if err == nil {
+			return plaintext, ciphertext[0], nil
+		}
+		versionErr = err
+	}
+
+	plaintext, err := openAEAD(newAESGCM, key, ciphertext, nil)
+	if err != nil {
+		// Report the versioned failure when the header was recognized, it's the likely format
+		if versionErr != nil {
+			return nil, 0, versionErr
+		}
+		return nil, 0, err
+	}
+
+	return plaintext, VersionLegacy, nil
+}
+
+func openAEAD(newAEAD func([]byte) (cipher.AEAD, error), key, ciphertext, additionalData []byte) ([]byte, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, errors.Join(ErrDecryptionFailed, err)
+	}
+
+	// Extract nonce
+	nonceSize := aead.NonceSize()
+	if len(ciphertext) < nonceSize {
+		return nil, ErrInvalidCiphertext
+	}
+
+	nonce, ciphertext := ciphertext[:nonceSize], ciphertext[nonceSize:]
+
+	plaintext, err := aead.Open(nil, nonce, ciphertext, additionalData)
+	if err != nil {
+		return nil, errors.Join(ErrDecryptionFailed, err)
+	}
+
+	return plaintext, nil
+}
+
+// MigrateBytes re-encrypts ciphertext in an older format with CurrentVersion.
+// Ciphertext already in the current format is returned unchanged with migrated set to false,
+// so it's safe to run over a whole table and only write back migrated rows.
+func MigrateBytes(appKey, workspaceKey, ciphertext []byte) (out []byte, migrated bool, err error) {
+	if err := ValidateKeys(appKey, workspaceKey); err != nil {
+		return nil, false, err
+	}
+
+	key, err := deriveKey(appKey, workspaceKey)
+	if err != nil {
+		return nil, false, err
+	}
+	defer clearBytes(key)
+
+	return migrate(key, ciphertext)
+}
+
+// MigrateString is MigrateBytes for base64-encoded ciphertext produced by EncryptString.
+func MigrateString(appKey, workspaceKey []byte, ciphertext string) (string, bool, error) {
+	ciphertextBytes, err := base64.StdEncoding.DecodeString(ciphertext)
+	if err != nil {
+		return "", false, errors.Join(ErrInvalidCiphertext, err)
+	}
+
+	out, migrated, err := MigrateBytes(appKey, workspaceKey, ciphertextBytes)
+	if err != nil || !migrated {
+		return ciphertext, false, err
+	}
+
+	return base64.StdEncoding.EncodeToString(out), true, nil
+}
+
+// MigrateBytesWithProvider is MigrateBytes with the key derived by a KeyProvider.
+func MigrateBytesWithProvider(ctx context.Context, provider KeyProvider, workspaceKey, ciphertext []byte) ([]byte, bool, error) {
+	key, err := providerKey(ctx, provider, workspaceKey)
+	if err != nil {
+		return nil, false, err
+	}
+	defer clearBytes(key)
+
+	return migrate(key, ciphertext)
+}
+
+func migrate(key, ciphertext []byte) ([]byte, bool, error) {
+	plaintext, version, err := openVersioned(key, ciphertext)
+	if err != nil {
+		return nil, false, err
+	}
+	defer clearBytes(plaintext)
+
+	if version == CurrentVersion {
+		return ciphertext, false, nil
+	}
+
+	out, err := seal(key, plaintext)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return out, true, nil
+}
@@ -0,0 +1,169 @@
+package secrets_test
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dmitrymomot/saaskit/pkg/secrets"
+)
+
+// fixedKeyProvider returns the same key for every workspace, so tests can
+// build ciphertext in the legacy format by hand.
+func fixedKeyProvider(key []byte) secrets.KeyProvider {
+	return secrets.KeyProviderFunc(func(context.Context, []byte) ([]byte, error) {
+		return append([]byte(nil), key...), nil
+	})
+}
+
+// legacySeal produces headerless ciphertext: nonce + encrypted data + tag.
+func legacySeal(t *testing.T, key, nonce, data []byte) []byte {
+	t.Helper()
+	block, err := aes.NewCipher(key)
+	require.NoError(t, err)
+	aesGCM, err := cipher.NewGCM(block)
+	require.NoError(t, err)
+	return aesGCM.Seal(append([]byte(nil), nonce...), nonce, data, nil)
+}
+
+func TestVersionedCiphertext(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	key, err := secrets.GenerateKey()
+	require.NoError(t, err)
+	workspaceKey, err := secrets.GenerateKey()
+	require.NoError(t, err)
+	provider := fixedKeyProvider(key)
+
+	t.Run("writes current version header", func(t *testing.T) {
+		t.Parallel()
+
+		ciphertext, err := secrets.EncryptBytesWithProvider(ctx, provider, workspaceKey, []byte("secret"))
+		require.NoError(t, err)
+		assert.Equal(t, secrets.CurrentVersion, ciphertext[0])
+
+		plaintext, err := secrets.DecryptBytesWithProvider(ctx, provider, workspaceKey, ciphertext)
+		require.NoError(t, err)
+		assert.Equal(t, []byte("secret"), plaintext)
+	})
+
+	t.Run("decrypts legacy ciphertext", func(t *testing.T) {
+		t.Parallel()
+
+		nonce := make([]byte, 12)
+		_, err := rand.Read(nonce)
+		require.NoError(t, err)
+		nonce[0] = 0x02
+
+		plaintext, err := secrets.DecryptBytesWithProvider(ctx, provider, workspaceKey, legacySeal(t, key, nonce, []byte("legacy")))
+		require.NoError(t, err)
+		assert.Equal(t, []byte("legacy"), plaintext)
+	})
+
+	t.Run("decrypts legacy ciphertext whose nonce looks like a header", func(t *testing.T) {
+		t.Parallel()
+
+		nonce := make([]byte, 12)
+		_, err := rand.Read(nonce)
+		require.NoError(t, err)
+		nonce[0] = secrets.VersionAESGCM
+
+		plaintext, err := secrets.DecryptBytesWithProvider(ctx, provider, workspaceKey, legacySeal(t, key, nonce, []byte("legacy")))
+		require.NoError(t, err)
+		assert.Equal(t, []byte("legacy"), plaintext)
+	})
+
+	t.Run("rejects tampered header", func(t *testing.T) {
+		t.Parallel()
+
+		ciphertext, err := secrets.EncryptBytesWithProvider(ctx, provider, workspaceKey, []byte("secret"))
+		require.NoError(t, err)
+
+		ciphertext[0] = 0x7f
+		_, err = secrets.DecryptBytesWithProvider(ctx, provider, workspaceKey, ciphertext)
+		assert.ErrorIs(t, err, secrets.ErrDecryptionFailed)
+
+		_, err = secrets.DecryptBytesWithProvider(ctx, provider, workspaceKey, ciphertext[1:])
+		assert.ErrorIs(t, err, secrets.ErrDecryptionFailed)
+	})
+
+	t.Run("rejects empty ciphertext", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := secrets.DecryptBytesWithProvider(ctx, provider, workspaceKey, nil)
+		assert.ErrorIs(t, err, secrets.ErrInvalidCiphertext)
+	})
+}
+
+func TestMigrate(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	t.Run("re-encrypts legacy ciphertext", func(t *testing.T) {
+		t.Parallel()
+
+		key, err := secrets.GenerateKey()
+		require.NoError(t, err)
+		workspaceKey, err := secrets.GenerateKey()
+		require.NoError(t, err)
+		provider := fixedKeyProvider(key)
+
+		nonce := make([]byte, 12)
+		_, err = rand.Read(nonce)
+		require.NoError(t, err)
+		legacy := legacySeal(t, key, nonce, []byte("token"))
+
+		migrated, ok, err := secrets.MigrateBytesWithProvider(ctx, provider, workspaceKey, legacy)
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, secrets.CurrentVersion, migrated[0])
+
+		plaintext, err := secrets.DecryptBytesWithProvider(ctx, provider, workspaceKey, migrated)
+		require.NoError(t, err)
+		assert.Equal(t, []byte("token"), plaintext)
+
+		again, ok, err := secrets.MigrateBytesWithProvider(ctx, provider, workspaceKey, migrated)
+		require.NoError(t, err)
+		assert.False(t, ok)
+		assert.Equal(t, migrated, again)
+	})
+
+	t.Run("current ciphertext is unchanged", func(t *testing.T) {
+		t.Parallel()
+
+		appKey, err := secrets.GenerateKey()
+		require.NoError(t, err)
+		workspaceKey, err := secrets.GenerateKey()
+		require.NoError(t, err)
+
+		ciphertext, err := secrets.EncryptString(appKey, workspaceKey, "token")
+		require.NoError(t, err)
+
+		out, ok, err := secrets.MigrateString(appKey, workspaceKey, ciphertext)
+		require.NoError(t, err)
+		assert.False(t, ok)
+		assert.Equal(t, ciphertext, out)
+
+		outBytes, ok, err := secrets.MigrateBytes(appKey, workspaceKey, []byte("garbage ciphertext"))
+		assert.Error(t, err)
+		assert.False(t, ok)
+		assert.Nil(t, outBytes)
+	})
+
+	t.Run("invalid base64", func(t *testing.T) {
+		t.Parallel()
+
+		appKey, err := secrets.GenerateKey()
+		require.NoError(t, err)
+
+		_, _, err = secrets.MigrateString(appKey, appKey, "not base64!")
+		assert.ErrorIs(t, err, secrets.ErrInvalidCiphertext)
+	})
+}
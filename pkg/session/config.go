@@ -13,6 +13,11 @@ type Config struct {
 	AuthIdleTimeout time.Duration `env:"SESSION_AUTH_IDLE_TIMEOUT" envDefault:"2h"`
 	AuthMaxLifetime time.Duration `env:"SESSION_AUTH_MAX_LIFETIME" envDefault:"720h"`
 
+	// Absolute timeouts end sessions regardless of activity or refreshes (0 to disable).
+	// The deadline is fixed when the session is created, or when it's authenticated.
+	AnonAbsoluteTimeout time.Duration `env:"SESSION_ANON_ABSOLUTE_TIMEOUT" envDefault:"0"`
+	AuthAbsoluteTimeout time.Duration `env:"SESSION_AUTH_ABSOLUTE_TIMEOUT" envDefault:"0"`
+
 	// ActivityUpdateThreshold is the minimum time between activity updates
 	ActivityUpdateThreshold time.Duration `env:"SESSION_ACTIVITY_UPDATE_THRESHOLD" envDefault:"5m"`
 
@@ -45,6 +50,14 @@ func (c Config) GetTimeouts(isAuthenticated bool) (idle, max time.Duration) {
 	return c.AnonIdleTimeout, c.AnonMaxLifetime
 }
 
+// GetAbsoluteTimeout returns the absolute timeout based on session state, 0 if disabled
+func (c Config) GetAbsoluteTimeout(isAuthenticated bool) time.Duration {
+	if isAuthenticated {
+		return c.AuthAbsoluteTimeout
+	}
+	return c.AnonAbsoluteTimeout
+}
+
 // NewFromConfig creates a new Manager from the provided Config.
 // Requires Store via options. Cookie manager required for default cookie transport.
 func NewFromConfig(cfg Config, opts ...Option) *Manager {
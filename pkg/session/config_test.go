@@ -78,3 +78,14 @@ func TestConfig_GetTimeouts(t *testing.T) {
 		assert.Equal(t, 24*time.Hour, max)
 	})
 }
+
+func TestConfig_GetAbsoluteTimeout(t *testing.T) {
+	cfg := session.Config{
+		AnonAbsoluteTimeout: 1 * time.Hour,
+		AuthAbsoluteTimeout: 12 * time.Hour,
+	}
+
+	assert.Equal(t, 1*time.Hour, cfg.GetAbsoluteTimeout(false))
+	assert.Equal(t, 12*time.Hour, cfg.GetAbsoluteTimeout(true))
+	assert.Zero(t, session.DefaultConfig().GetAbsoluteTimeout(true), "disabled by default")
+}
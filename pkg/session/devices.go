@@ -0,0 +1,102 @@
+package session
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/dmitrymomot/saaskit/pkg/useragent"
+)
+
+// maxUserAgentLength bounds the User-Agent stored with each session
+const maxUserAgentLength = 512
+
+// SessionInfo describes a session for a "manage devices" UI, without its token
+type SessionInfo struct {
+	ID             uuid.UUID
+	CreatedAt      time.Time
+	LastActivityAt time.Time
+	ExpiresAt      time.Time
+	IPAddress      string
+	Device         string // friendly label, e.g. "Chrome/120.0 (Windows, desktop)"
+	Current        bool   // the session of the request that listed the sessions
+}
+
+// ListByUserID returns the user's active sessions, most recently active first.
+// The session in ctx (see WithSession, set by the middlewares) is marked Current.
+// The store must implement StoreWithUserSessions, otherwise ErrListingNotSupported is returned.
+func (m *Manager) ListByUserID(ctx context.Context, userID uuid.UUID) ([]SessionInfo, error) {
+	store, ok := m.store.(StoreWithUserSessions)
+	if !ok {
+		return nil, ErrListingNotSupported
+	}
+
+	sessions, err := store.ListByUserID(ctx, userID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	var currentToken string
+	if current, ok := FromContext(ctx); ok {
+		currentToken = current.Token
+	}
+
+	infos := make([]SessionInfo, 0, len(sessions))
+	for _, s := range sessions {
+		infos = append(infos, SessionInfo{
+			ID:             s.ID,
+			CreatedAt:      s.CreatedAt,
+			LastActivityAt: s.LastActivityAt,
+			ExpiresAt:      s.ExpiresAt,
+			IPAddress:      s.IPAddress,
+			Device:         deviceLabel(s.UserAgent),
+			Current:        currentToken != "" && constantTimeCompare(s.Token, currentToken),
+		})
+	}
+
+	slices.SortFunc(infos, func(a, b SessionInfo) int {
+		return b.LastActivityAt.Compare(a.LastActivityAt)
+	})
+
+	return infos, nil
+}
+
+// RevokeSession deletes one of the user's sessions by its ID, as listed by ListByUserID.
+// Returns ErrSessionNotFound if the user has no active session with that ID.
+func (m *Manager) RevokeSession(ctx context.Context, userID, sessionID uuid.UUID) error {
+	store, ok := m.store.(StoreWithUserSessions)
+	if !ok {
+		return ErrListingNotSupported
+	}
+
+	sessions, err := store.ListByUserID(ctx, userID.String())
+	if err != nil {
+		return err
+	}
+
+	for _, s := range sessions {
+		if s.ID == sessionID {
+			return m.store.Delete(ctx, s.Token)
+		}
+	}
+	return ErrSessionNotFound
+}
+
+// deviceLabel summarizes a User-Agent for display
+func deviceLabel(ua string) string {
+	parsed, err := useragent.Parse(ua)
+	if err != nil {
+		return "Unknown device"
+	}
+	return parsed.GetShortIdentifier()
+}
+
+// truncateUserAgent keeps oversized User-Agent headers from bloating the store
+func truncateUserAgent(ua string) string {
+	if len(ua) > maxUserAgentLength {
+		return ua[:maxUserAgentLength]
+	}
+	return ua
+}
@@ -0,0 +1,105 @@
+package session_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dmitrymomot/saaskit/pkg/session"
+)
+
+func TestManager_ListByUserID(t *testing.T) {
+	const chromeUA = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"
+
+	newManager := func(t *testing.T, store session.Store) *session.Manager {
+		manager := session.New(
+			session.WithStore(store),
+			session.WithTransport(session.NewHeaderTransport("X-Session-Token")),
+		)
+		t.Cleanup(func() { _ = manager.Close() })
+		return manager
+	}
+
+	loginFrom := func(t *testing.T, manager *session.Manager, userID uuid.UUID, ip, ua string) string {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPost, "/login", nil)
+		r.RemoteAddr = ip + ":1234"
+		r.Header.Set("User-Agent", ua)
+
+		require.NoError(t, manager.Authenticate(context.Background(), w, r, userID))
+		return strings.TrimPrefix(w.Header().Get("X-Session-Token"), "Bearer ")
+	}
+
+	t.Run("lists sessions without tokens", func(t *testing.T) {
+		mem := session.NewMemoryStore(0)
+		t.Cleanup(func() { _ = mem.Close() })
+		manager := newManager(t, mem)
+		ctx := context.Background()
+
+		userID := uuid.New()
+		older := loginFrom(t, manager, userID, "203.0.113.7", chromeUA)
+		time.Sleep(time.Millisecond)
+		newer := loginFrom(t, manager, userID, "203.0.113.8", "")
+		loginFrom(t, manager, uuid.New(), "203.0.113.9", chromeUA)
+
+		current, err := mem.Get(ctx, older)
+		require.NoError(t, err)
+
+		infos, err := manager.ListByUserID(session.WithSession(ctx, current), userID)
+		require.NoError(t, err)
+		require.Len(t, infos, 2)
+
+		newerSession, err := mem.Get(ctx, newer)
+		require.NoError(t, err)
+		assert.Equal(t, newerSession.ID, infos[0].ID, "most recently active first")
+		assert.Equal(t, "203.0.113.8", infos[0].IPAddress)
+		assert.Equal(t, "Unknown device", infos[0].Device)
+		assert.False(t, infos[0].Current)
+
+		assert.Equal(t, current.ID, infos[1].ID)
+		assert.Equal(t, "203.0.113.7", infos[1].IPAddress)
+		assert.Contains(t, infos[1].Device, "Chrome")
+		assert.True(t, infos[1].Current)
+	})
+
+	t.Run("revokes a single session", func(t *testing.T) {
+		mem := session.NewMemoryStore(0)
+		t.Cleanup(func() { _ = mem.Close() })
+		manager := newManager(t, mem)
+		ctx := context.Background()
+
+		userID := uuid.New()
+		keep := loginFrom(t, manager, userID, "203.0.113.7", chromeUA)
+		revoke := loginFrom(t, manager, userID, "203.0.113.8", chromeUA)
+
+		target, err := mem.Get(ctx, revoke)
+		require.NoError(t, err)
+
+		err = manager.RevokeSession(ctx, uuid.New(), target.ID)
+		assert.ErrorIs(t, err, session.ErrSessionNotFound, "other users can't revoke the session")
+
+		require.NoError(t, manager.RevokeSession(ctx, userID, target.ID))
+		_, err = mem.Get(ctx, revoke)
+		assert.ErrorIs(t, err, session.ErrSessionNotFound)
+		_, err = mem.Get(ctx, keep)
+		assert.NoError(t, err)
+	})
+
+	t.Run("unsupported store", func(t *testing.T) {
+		mem := session.NewMemoryStore(0)
+		t.Cleanup(func() { _ = mem.Close() })
+		manager := newManager(t, basicStore{Store: mem})
+
+		_, err := manager.ListByUserID(context.Background(), uuid.New())
+		assert.ErrorIs(t, err, session.ErrListingNotSupported)
+		err = manager.RevokeSession(context.Background(), uuid.New(), uuid.New())
+		assert.ErrorIs(t, err, session.ErrListingNotSupported)
+	})
+}
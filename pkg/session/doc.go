@@ -61,6 +61,38 @@
 // Configuration via Option functions or Config struct with NewFromConfig.
 // Environment variable support via DefaultConfig() for twelve-factor apps.
 //
+// Idle timeouts slide with activity up to the max lifetime. WithAbsoluteTimeout
+// additionally ends sessions a fixed time after creation or authentication,
+// regardless of activity; the deadline is stored in Session.AbsoluteExpiresAt.
+//
+// # Redis Store
+//
+// RedisStore keeps sessions in Redis so they survive restarts and are shared across
+// instances. Keys expire at each session's ExpiresAt, and UpdateActivity only sets the
+// last activity field without rewriting the session. It implements StoreWithCleanup
+// and StoreWithUserSessions (Redis 7+ for the per-user index).
+//
+// # Revocation
+//
+// RevokeByUserID deletes every session of a user, e.g. from the auth package's
+// WithAfterUpdate hook after a password change. It uses StoreWithCleanup when
+// available (MemoryStore and RedisStore index sessions by user) and falls back to
+// ListByUserID plus one Delete per session for StoreWithUserSessions.
+//
+// # Managing Devices
+//
+// Manager.ListByUserID returns SessionInfo values for a "manage devices" page:
+// IP, a device label derived from the User-Agent, timestamps and whether the
+// session is the one in the context. Tokens are never exposed; RevokeSession
+// signs out one device by session ID.
+//
+// # Concurrent Session Limits
+//
+// WithMaxSessionsPerUser caps authenticated sessions per user. When a login
+// would exceed the cap, EvictOldest revokes the oldest sessions (returned by
+// AuthenticateWithEvictions for UX messaging) and RejectNew fails the login
+// with ErrSessionLimitReached. The store must implement StoreWithUserSessions.
+//
 // # Error Handling
 //
 // Common error values returned by the package:
@@ -68,6 +100,7 @@
 //   - ErrInvalidSession   – fingerprint mismatch
 //   - ErrSessionExpired   – session has passed its expiry
 //   - ErrSessionNotFound  – no session associated with token
+//   - ErrSessionLimitReached – per-user session limit hit with RejectNew
 //
 // # Performance Considerations
 //
@@ -18,6 +18,15 @@ var (
 	// ErrNoTransport indicates no transport is configured
 	ErrNoTransport = errors.New("no session transport configured")
 
+	// ErrSessionLimitReached indicates the user already has the maximum number of sessions
+	ErrSessionLimitReached = errors.New("maximum number of sessions reached")
+
+	// ErrRevocationNotSupported indicates the store can't find sessions by user ID
+	ErrRevocationNotSupported = errors.New("session store does not support revocation by user ID")
+
+	// ErrListingNotSupported indicates the store can't list sessions by user ID
+	ErrListingNotSupported = errors.New("session store does not support listing by user ID")
+
 	// ErrNoStore indicates no store is configured
 	ErrNoStore = errors.New("no session store configured")
 )
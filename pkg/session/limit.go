@@ -0,0 +1,67 @@
+package session
+
+import (
+	"context"
+	"hash/fnv"
+	"slices"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// EvictPolicy decides what happens when a login would exceed the per-user session limit
+type EvictPolicy int
+
+const (
+	// EvictOldest revokes the user's oldest sessions to make room for the new one
+	EvictOldest EvictPolicy = iota
+	// RejectNew keeps existing sessions and fails the login with ErrSessionLimitReached
+	RejectNew
+)
+
+// userLockStripes bounds the memory used for per-user login locks
+const userLockStripes = 64
+
+// userLocks serializes logins of the same user within one Manager instance
+type userLocks [userLockStripes]sync.Mutex
+
+func (l *userLocks) get(userID uuid.UUID) *sync.Mutex {
+	h := fnv.New32a()
+	_, _ = h.Write(userID[:])
+	return &l[h.Sum32()%userLockStripes]
+}
+
+// enforceSessionLimit makes room for one more session of the user, returning evicted sessions.
+// currentToken is the session being upgraded, which doesn't count towards the limit.
+func (m *Manager) enforceSessionLimit(ctx context.Context, userID uuid.UUID, currentToken string) ([]*Session, error) {
+	store := m.store.(StoreWithUserSessions)
+
+	sessions, err := store.ListByUserID(ctx, userID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	sessions = slices.DeleteFunc(sessions, func(s *Session) bool {
+		return s.Token == currentToken
+	})
+	if len(sessions) < m.maxSessionsPerUser {
+		return nil, nil
+	}
+
+	if m.evictPolicy == RejectNew {
+		return nil, ErrSessionLimitReached
+	}
+
+	slices.SortFunc(sessions, func(a, b *Session) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+
+	evicted := sessions[:len(sessions)-m.maxSessionsPerUser+1]
+	for _, s := range evicted {
+		if err := m.store.Delete(ctx, s.Token); err != nil {
+			return nil, err
+		}
+	}
+
+	return evicted, nil
+}
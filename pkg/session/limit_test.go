@@ -0,0 +1,131 @@
+package session_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dmitrymomot/saaskit/pkg/session"
+)
+
+func setupLimitedManager(t *testing.T, n int, policy session.EvictPolicy) (*session.Manager, *session.MemoryStore) {
+	store := session.NewMemoryStore(0)
+	t.Cleanup(func() { _ = store.Close() })
+
+	manager := session.New(
+		session.WithStore(store),
+		session.WithTransport(session.NewHeaderTransport("X-Session-Token")),
+		session.WithMaxSessionsPerUser(n, policy),
+	)
+	t.Cleanup(func() { _ = manager.Close() })
+
+	return manager, store
+}
+
+func login(t *testing.T, manager *session.Manager, userID uuid.UUID) ([]*session.Session, string, error) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/login", nil)
+
+	evicted, err := manager.AuthenticateWithEvictions(context.Background(), w, r, userID)
+	return evicted, strings.TrimPrefix(w.Header().Get("X-Session-Token"), "Bearer "), err
+}
+
+func TestManager_MaxSessionsPerUser(t *testing.T) {
+	t.Run("evicts oldest session", func(t *testing.T) {
+		manager, store := setupLimitedManager(t, 2, session.EvictOldest)
+		userID := uuid.New()
+
+		_, first, err := login(t, manager, userID)
+		require.NoError(t, err)
+		time.Sleep(time.Millisecond)
+		_, second, err := login(t, manager, userID)
+		require.NoError(t, err)
+
+		evicted, third, err := login(t, manager, userID)
+		require.NoError(t, err)
+		require.Len(t, evicted, 1)
+		assert.Equal(t, first, evicted[0].Token)
+
+		_, err = store.Get(context.Background(), first)
+		assert.ErrorIs(t, err, session.ErrSessionNotFound)
+		for _, token := range []string{second, third} {
+			_, err = store.Get(context.Background(), token)
+			assert.NoError(t, err)
+		}
+	})
+
+	t.Run("rejects new login", func(t *testing.T) {
+		manager, store := setupLimitedManager(t, 1, session.RejectNew)
+		userID := uuid.New()
+
+		_, first, err := login(t, manager, userID)
+		require.NoError(t, err)
+
+		_, _, err = login(t, manager, userID)
+		assert.ErrorIs(t, err, session.ErrSessionLimitReached)
+
+		sessions, err := store.ListByUserID(context.Background(), userID.String())
+		require.NoError(t, err)
+		require.Len(t, sessions, 1)
+		assert.Equal(t, first, sessions[0].Token)
+	})
+
+	t.Run("limits are per user", func(t *testing.T) {
+		manager, _ := setupLimitedManager(t, 1, session.RejectNew)
+
+		_, _, err := login(t, manager, uuid.New())
+		require.NoError(t, err)
+		_, _, err = login(t, manager, uuid.New())
+		assert.NoError(t, err)
+	})
+
+	t.Run("re-authenticating current session does not count", func(t *testing.T) {
+		manager, _ := setupLimitedManager(t, 1, session.RejectNew)
+		userID := uuid.New()
+
+		_, token, err := login(t, manager, userID)
+		require.NoError(t, err)
+
+		r := httptest.NewRequest(http.MethodPost, "/login", nil)
+		r.Header.Set("X-Session-Token", "Bearer "+token)
+		err = manager.Authenticate(context.Background(), httptest.NewRecorder(), r, userID)
+		assert.NoError(t, err)
+	})
+
+	t.Run("concurrent logins respect limit", func(t *testing.T) {
+		manager, store := setupLimitedManager(t, 3, session.RejectNew)
+		userID := uuid.New()
+
+		var wg sync.WaitGroup
+		for range 20 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, _, _ = login(t, manager, userID)
+			}()
+		}
+		wg.Wait()
+
+		sessions, err := store.ListByUserID(context.Background(), userID.String())
+		require.NoError(t, err)
+		assert.Len(t, sessions, 3)
+	})
+
+	t.Run("panics when store cannot list sessions", func(t *testing.T) {
+		assert.Panics(t, func() {
+			session.New(
+				session.WithStore(struct{ session.Store }{session.NewMemoryStore(0)}),
+				session.WithTransport(session.NewHeaderTransport("X-Session-Token")),
+				session.WithMaxSessionsPerUser(1, session.EvictOldest),
+			)
+		})
+	})
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/google/uuid"
 
+	"github.com/dmitrymomot/saaskit/pkg/clientip"
 	"github.com/dmitrymomot/saaskit/pkg/cookie"
 )
 
@@ -26,6 +27,10 @@ type Manager struct {
 	cookieOptions   []cookie.Option
 	activityChan    chan activityUpdate
 	done            chan struct{}
+
+	maxSessionsPerUser int
+	evictPolicy        EvictPolicy
+	userLocks          userLocks
 }
 
 // activityUpdate represents a session activity update
@@ -50,6 +55,12 @@ func New(opts ...Option) *Manager {
 		m.store = NewMemoryStore(m.config.CleanupInterval)
 	}
 
+	if m.maxSessionsPerUser > 0 {
+		if _, ok := m.store.(StoreWithUserSessions); !ok {
+			panic("session: store must implement StoreWithUserSessions to limit sessions per user")
+		}
+	}
+
 	if m.transport == nil {
 		if m.cookieManager == nil {
 			// Fail fast on misconfiguration to prevent insecure runtime behavior
@@ -112,35 +123,68 @@ func (m *Manager) Get(ctx context.Context, r *http.Request) (*Session, error) {
 
 // Authenticate upgrades an anonymous session to authenticated
 func (m *Manager) Authenticate(ctx context.Context, w http.ResponseWriter, r *http.Request, userID uuid.UUID) error {
-	session, err := m.Get(ctx, r)
-	if err != nil {
+	_, err := m.AuthenticateWithEvictions(ctx, w, r, userID)
+	return err
+}
+
+// AuthenticateWithEvictions is like Authenticate but also returns the sessions
+// revoked to respect WithMaxSessionsPerUser, e.g. to tell the user which device
+// was signed out. Without a limit the result is always empty.
+//
+// Logins of the same user are serialized within the Manager, so concurrent
+// logins can't both claim the last free slot. Across instances the store is
+// the only coordination point and the limit may be briefly exceeded.
+func (m *Manager) AuthenticateWithEvictions(ctx context.Context, w http.ResponseWriter, r *http.Request, userID uuid.UUID) ([]*Session, error) {
+	session, getErr := m.Get(ctx, r)
+
+	var evicted []*Session
+	if m.maxSessionsPerUser > 0 {
+		mu := m.userLocks.get(userID)
+		mu.Lock()
+		defer mu.Unlock()
+
+		var currentToken string
+		if getErr == nil {
+			currentToken = session.Token
+		}
+
+		var err error
+		if evicted, err = m.enforceSessionLimit(ctx, userID, currentToken); err != nil {
+			return nil, err
+		}
+	}
+
+	if getErr != nil {
 		// Create new authenticated session
+		var err error
 		session, err = m.createSession(ctx, &userID, r)
 		if err != nil {
-			return err
+			return evicted, err
 		}
 	} else {
 		session.UserID = &userID
 
 		newToken, err := generateToken()
 		if err != nil {
-			return err
+			return evicted, err
 		}
 
 		_ = m.store.Delete(ctx, session.Token)
 
+		now := time.Now()
 		session.Token = newToken
+		session.AbsoluteExpiresAt = m.absoluteExpiry(now, true)
 		idle, max := m.config.GetTimeouts(true)
-		session.ExpiresAt = m.calculateExpiry(session.CreatedAt, time.Now(), idle, max)
+		session.ExpiresAt = m.calculateExpiry(session, now, idle, max)
 		session.Touch()
 
 		if err := m.store.Create(ctx, session); err != nil {
-			return err
+			return evicted, err
 		}
 	}
 
 	idle, _ := m.config.GetTimeouts(true)
-	return m.transport.SetToken(w, session.Token, idle)
+	return evicted, m.transport.SetToken(w, session.Token, idle)
 }
 
 // Destroy deletes the session
@@ -182,7 +226,7 @@ func (m *Manager) Refresh(ctx context.Context, w http.ResponseWriter, r *http.Re
 	}
 
 	idle, max := m.config.GetTimeouts(session.IsAuthenticated())
-	session.ExpiresAt = m.calculateExpiry(session.CreatedAt, time.Now(), idle, max)
+	session.ExpiresAt = m.calculateExpiry(session, time.Now(), idle, max)
 	session.Touch()
 
 	if err := m.store.Update(ctx, session); err != nil {
@@ -200,14 +244,19 @@ func (m *Manager) createSession(ctx context.Context, userID *uuid.UUID, r *http.
 	}
 
 	idle, max := m.config.GetTimeouts(userID != nil)
-	now := time.Now()
 
 	var fingerprint string
 	if m.fingerprintFunc != nil {
 		fingerprint = m.fingerprintFunc(r)
 	}
 
-	session := NewSession(token, userID, fingerprint, m.calculateExpiry(now, now, idle, max).Sub(now))
+	session := NewSession(token, userID, fingerprint, idle)
+	session.AbsoluteExpiresAt = m.absoluteExpiry(session.CreatedAt, userID != nil)
+	session.ExpiresAt = m.calculateExpiry(session, session.CreatedAt, idle, max)
+	if r != nil {
+		session.IPAddress = clientip.GetIP(r)
+		session.UserAgent = truncateUserAgent(r.UserAgent())
+	}
 
 	if err := m.store.Create(ctx, session); err != nil {
 		return nil, err
@@ -277,15 +326,25 @@ func (m *Manager) Close() error {
 	}
 }
 
-// calculateExpiry returns the next expiry time (min of idle and max lifetime)
-func (m *Manager) calculateExpiry(createdAt, now time.Time, idle, max time.Duration) time.Time {
-	idleExpiry := now.Add(idle)
-	maxExpiry := createdAt.Add(max)
+// calculateExpiry returns the next expiry time (min of idle, max lifetime and absolute deadline)
+func (m *Manager) calculateExpiry(session *Session, now time.Time, idle, max time.Duration) time.Time {
+	expiry := now.Add(idle)
+	if maxExpiry := session.CreatedAt.Add(max); maxExpiry.Before(expiry) {
+		expiry = maxExpiry
+	}
+	if !session.AbsoluteExpiresAt.IsZero() && session.AbsoluteExpiresAt.Before(expiry) {
+		expiry = session.AbsoluteExpiresAt
+	}
+	return expiry
+}
 
-	if maxExpiry.Before(idleExpiry) {
-		return maxExpiry
+// absoluteExpiry returns the absolute deadline for a session starting at start, zero if disabled
+func (m *Manager) absoluteExpiry(start time.Time, isAuthenticated bool) time.Time {
+	timeout := m.config.GetAbsoluteTimeout(isAuthenticated)
+	if timeout <= 0 {
+		return time.Time{}
 	}
-	return idleExpiry
+	return start.Add(timeout)
 }
 
 // generateToken creates a cryptographically secure token
@@ -446,3 +446,48 @@ func TestManager_Close(t *testing.T) {
 	err = manager.Close()
 	assert.NoError(t, err)
 }
+
+func TestManager_AbsoluteTimeout(t *testing.T) {
+	manager := session.New(
+		session.WithTransport(session.NewHeaderTransport("X-Session-Token")),
+		session.WithIdleTimeout(time.Hour, time.Hour),
+		session.WithAbsoluteTimeout(50*time.Millisecond, 50*time.Millisecond),
+		session.WithActivityUpdateThreshold(0),
+	)
+	defer manager.Close()
+	ctx := context.Background()
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/login", nil)
+	require.NoError(t, manager.Authenticate(ctx, w, r, uuid.New()))
+	token := w.Header().Get("X-Session-Token")
+
+	request := func() *http.Request {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set("X-Session-Token", token)
+		return r
+	}
+
+	sess, err := manager.Get(ctx, request())
+	require.NoError(t, err)
+	deadline := sess.AbsoluteExpiresAt
+	require.False(t, deadline.IsZero())
+	assert.False(t, sess.ExpiresAt.After(deadline), "expiry is capped by the absolute deadline")
+
+	// Activity and refreshes don't extend the deadline
+	time.Sleep(20 * time.Millisecond)
+	require.NoError(t, manager.Refresh(ctx, httptest.NewRecorder(), request()))
+	sess, err = manager.Get(ctx, request())
+	require.NoError(t, err)
+	assert.Equal(t, deadline, sess.AbsoluteExpiresAt)
+	assert.False(t, sess.ExpiresAt.After(deadline))
+
+	time.Sleep(40 * time.Millisecond)
+	_, err = manager.Get(ctx, request())
+	assert.ErrorIs(t, err, session.ErrSessionExpired)
+
+	sess, err = manager.Ensure(ctx, httptest.NewRecorder(), request())
+	require.NoError(t, err)
+	assert.NotEqual(t, token, sess.Token, "an expired session is replaced")
+	assert.False(t, sess.IsAuthenticated())
+}
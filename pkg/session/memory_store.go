@@ -13,6 +13,7 @@ import (
 type MemoryStore struct {
 	mu       sync.RWMutex
 	sessions map[string]*Session
+	byUser   map[uuid.UUID]map[string]struct{} // user ID -> tokens, for per-user lookups without a full scan
 	ticker   *time.Ticker
 	done     chan struct{}
 }
@@ -21,6 +22,7 @@ type MemoryStore struct {
 func NewMemoryStore(cleanupInterval time.Duration) *MemoryStore {
 	store := &MemoryStore{
 		sessions: make(map[string]*Session),
+		byUser:   make(map[uuid.UUID]map[string]struct{}),
 		done:     make(chan struct{}),
 	}
 
@@ -47,7 +49,7 @@ func (m *MemoryStore) Create(ctx context.Context, session *Session) error {
 		maps.Copy(sessionCopy.Data, session.Data)
 	}
 
-	m.sessions[session.Token] = &sessionCopy
+	m.put(&sessionCopy)
 	return nil
 }
 
@@ -63,7 +65,7 @@ func (m *MemoryStore) Get(ctx context.Context, token string) (*Session, error) {
 
 	if session.IsExpired() {
 		m.mu.Lock()
-		delete(m.sessions, token)
+		m.remove(token)
 		m.mu.Unlock()
 		return nil, ErrSessionExpired
 	}
@@ -96,7 +98,7 @@ func (m *MemoryStore) Update(ctx context.Context, session *Session) error {
 		maps.Copy(sessionCopy.Data, session.Data)
 	}
 
-	m.sessions[session.Token] = &sessionCopy
+	m.put(&sessionCopy)
 	return nil
 }
 
@@ -119,7 +121,7 @@ func (m *MemoryStore) Delete(ctx context.Context, token string) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	delete(m.sessions, token)
+	m.remove(token)
 	return nil
 }
 
@@ -131,7 +133,7 @@ func (m *MemoryStore) DeleteExpired(ctx context.Context) error {
 	now := time.Now()
 	for token, session := range m.sessions {
 		if now.After(session.ExpiresAt) {
-			delete(m.sessions, token)
+			m.remove(token)
 		}
 	}
 
@@ -148,15 +150,78 @@ func (m *MemoryStore) DeleteByUserID(ctx context.Context, userID string) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	for token, session := range m.sessions {
-		if session.UserID != nil && *session.UserID == uid {
-			delete(m.sessions, token)
-		}
+	for token := range m.byUser[uid] {
+		delete(m.sessions, token)
 	}
+	delete(m.byUser, uid)
 
 	return nil
 }
 
+// ListByUserID returns all non-expired sessions for a specific user
+func (m *MemoryStore) ListByUserID(ctx context.Context, userID string) ([]*Session, error) {
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var sessions []*Session
+	for token := range m.byUser[uid] {
+		session := m.sessions[token]
+		if session.IsExpired() {
+			continue
+		}
+
+		sessionCopy := *session
+		if session.Data != nil {
+			sessionCopy.Data = make(map[string]any, len(session.Data))
+			maps.Copy(sessionCopy.Data, session.Data)
+		}
+		sessions = append(sessions, &sessionCopy)
+	}
+
+	return sessions, nil
+}
+
+// put stores the session and keeps the user index in sync. Callers must hold the write lock.
+func (m *MemoryStore) put(session *Session) {
+	if prev, exists := m.sessions[session.Token]; exists {
+		m.unindex(prev)
+	}
+
+	m.sessions[session.Token] = session
+	if session.UserID != nil {
+		tokens, ok := m.byUser[*session.UserID]
+		if !ok {
+			tokens = make(map[string]struct{})
+			m.byUser[*session.UserID] = tokens
+		}
+		tokens[session.Token] = struct{}{}
+	}
+}
+
+// remove deletes the session and its user index entry. Callers must hold the write lock.
+func (m *MemoryStore) remove(token string) {
+	if session, exists := m.sessions[token]; exists {
+		m.unindex(session)
+		delete(m.sessions, token)
+	}
+}
+
+func (m *MemoryStore) unindex(session *Session) {
+	if session.UserID == nil {
+		return
+	}
+	tokens := m.byUser[*session.UserID]
+	delete(tokens, session.Token)
+	if len(tokens) == 0 {
+		delete(m.byUser, *session.UserID)
+	}
+}
+
 // Close stops the cleanup goroutine
 func (m *MemoryStore) Close() error {
 	if m.ticker != nil {
@@ -280,6 +280,74 @@ func TestMemoryStore_DeleteByUserID(t *testing.T) {
 	})
 }
 
+func TestMemoryStore_ListByUserID(t *testing.T) {
+	store := session.NewMemoryStore(0)
+	defer store.Close()
+
+	ctx := context.Background()
+	userID1 := uuid.New()
+	userID2 := uuid.New()
+
+	require.NoError(t, store.Create(ctx, session.NewSession("user1-1", &userID1, "", 1*time.Hour)))
+	require.NoError(t, store.Create(ctx, session.NewSession("user1-2", &userID1, "", 1*time.Hour)))
+	require.NoError(t, store.Create(ctx, session.NewSession("user1-expired", &userID1, "", -1*time.Hour)))
+	require.NoError(t, store.Create(ctx, session.NewSession("user2-1", &userID2, "", 1*time.Hour)))
+	require.NoError(t, store.Create(ctx, session.NewSession("anon", nil, "", 1*time.Hour)))
+
+	sessions, err := store.ListByUserID(ctx, userID1.String())
+	require.NoError(t, err)
+
+	tokens := make([]string, 0, len(sessions))
+	for _, s := range sessions {
+		tokens = append(tokens, s.Token)
+	}
+	assert.ElementsMatch(t, []string{"user1-1", "user1-2"}, tokens)
+
+	t.Run("invalid user ID", func(t *testing.T) {
+		_, err := store.ListByUserID(ctx, "invalid-uuid")
+		assert.Error(t, err)
+	})
+}
+
+func TestMemoryStore_UserIndex(t *testing.T) {
+	store := session.NewMemoryStore(0)
+	defer store.Close()
+
+	ctx := context.Background()
+	userID1 := uuid.New()
+	userID2 := uuid.New()
+
+	sess := session.NewSession("token", nil, "", 1*time.Hour)
+	require.NoError(t, store.Create(ctx, sess))
+
+	listTokens := func(userID uuid.UUID) []string {
+		sessions, err := store.ListByUserID(ctx, userID.String())
+		require.NoError(t, err)
+		tokens := make([]string, 0, len(sessions))
+		for _, s := range sessions {
+			tokens = append(tokens, s.Token)
+		}
+		return tokens
+	}
+
+	sess.UserID = &userID1
+	require.NoError(t, store.Update(ctx, sess))
+	assert.Equal(t, []string{"token"}, listTokens(userID1))
+
+	sess.UserID = &userID2
+	require.NoError(t, store.Update(ctx, sess))
+	assert.Empty(t, listTokens(userID1))
+	assert.Equal(t, []string{"token"}, listTokens(userID2))
+
+	// A revoked user doesn't affect a session moved to another user
+	require.NoError(t, store.DeleteByUserID(ctx, userID1.String()))
+	_, err := store.Get(ctx, "token")
+	require.NoError(t, err)
+
+	require.NoError(t, store.Delete(ctx, "token"))
+	assert.Empty(t, listTokens(userID2))
+}
+
 func TestMemoryStore_Stats(t *testing.T) {
 	store := session.NewMemoryStore(0)
 	defer store.Close()
@@ -53,6 +53,14 @@ func WithMaxLifetime(anon, auth time.Duration) Option {
 	}
 }
 
+// WithAbsoluteTimeout sets how long sessions last regardless of activity (0 to disable)
+func WithAbsoluteTimeout(anon, auth time.Duration) Option {
+	return func(m *Manager) {
+		m.config.AnonAbsoluteTimeout = anon
+		m.config.AuthAbsoluteTimeout = auth
+	}
+}
+
 // WithActivityUpdateThreshold sets the minimum time between activity updates
 func WithActivityUpdateThreshold(threshold time.Duration) Option {
 	return func(m *Manager) {
@@ -81,3 +89,14 @@ func WithCookieManager(cookieMgr *cookie.Manager, opts ...cookie.Option) Option
 		m.cookieOptions = opts
 	}
 }
+
+// WithMaxSessionsPerUser limits how many authenticated sessions a user may hold at once.
+// When Authenticate would exceed the limit, policy decides whether the oldest sessions
+// are revoked or the new login fails with ErrSessionLimitReached.
+// The store must implement StoreWithUserSessions.
+func WithMaxSessionsPerUser(n int, policy EvictPolicy) Option {
+	return func(m *Manager) {
+		m.maxSessionsPerUser = n
+		m.evictPolicy = policy
+	}
+}
@@ -0,0 +1,374 @@
+package session
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// DefaultRedisKeyPrefix is prepended to session keys stored in Redis.
+const DefaultRedisKeyPrefix = "session:"
+
+// Hash fields of a session key. The session is stored as JSON, while the last
+// activity time lives in its own field so UpdateActivity doesn't rewrite the JSON.
+const (
+	redisFieldSession      = "session"
+	redisFieldLastActivity = "last_activity"
+	redisFieldUserID       = "user_id"
+)
+
+// updateScript overwrites an existing session only, so Update can't resurrect a
+// session deleted or expired in the meantime. Returns false if the key is missing,
+// otherwise the previous user ID (empty string for anonymous sessions).
+//
+// KEYS[1] - session key
+// ARGV    - session JSON, last activity, user ID, expires at (unix ms)
+var updateScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 0 then
+	return false
+end
+
+local prev = redis.call('HGET', KEYS[1], 'user_id') or ''
+redis.call('HSET', KEYS[1], 'session', ARGV[1], 'last_activity', ARGV[2])
+if ARGV[3] == '' then
+	redis.call('HDEL', KEYS[1], 'user_id')
+else
+	redis.call('HSET', KEYS[1], 'user_id', ARGV[3])
+end
+redis.call('PEXPIREAT', KEYS[1], ARGV[4])
+return prev
+`)
+
+// activityScript sets the last activity of an existing session, keeping its expiry.
+// Returns 0 if the key is missing.
+//
+// KEYS[1] - session key
+// ARGV    - last activity
+var activityScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 0 then
+	return 0
+end
+redis.call('HSET', KEYS[1], 'last_activity', ARGV[1])
+return 1
+`)
+
+// deleteScript removes a session and returns its user ID, so it can be dropped from the user's index.
+//
+// KEYS[1] - session key
+var deleteScript = redis.NewScript(`
+local userID = redis.call('HGET', KEYS[1], 'user_id')
+redis.call('DEL', KEYS[1])
+return userID or ''
+`)
+
+// RedisStore implements Store using Redis, so sessions survive restarts and are shared
+// across instances. Keys expire at the session's ExpiresAt, honoring the anonymous and
+// authenticated timeouts set by the Manager, so DeleteExpired is a no-op.
+//
+// Sessions are stored as hashes holding the JSON-encoded session. As with any JSON
+// round trip, numbers in Data come back as float64 (GetInt handles this).
+// Authenticated sessions are also indexed per user for DeleteByUserID and
+// ListByUserID, which WithMaxSessionsPerUser relies on. The index uses
+// EXPIRE NX/GT, so Redis 7+ is required.
+//
+// All operations are single commands, scripts or pipelines, so concurrent Get,
+// Update and UpdateActivity calls on the same token are safe across instances.
+type RedisStore struct {
+	client    redis.UniversalClient
+	keyPrefix string
+}
+
+// RedisStoreOption configures a RedisStore.
+type RedisStoreOption func(*RedisStore)
+
+// WithRedisKeyPrefix sets the prefix for keys stored in Redis.
+// Defaults to DefaultRedisKeyPrefix.
+func WithRedisKeyPrefix(prefix string) RedisStoreOption {
+	return func(s *RedisStore) {
+		s.keyPrefix = prefix
+	}
+}
+
+// NewRedisStore creates a Redis-backed session store on any redis.UniversalClient.
+// Scripts and transactions never span more than one key, so cluster clients are supported.
+// Panics if client is nil.
+func NewRedisStore(client redis.UniversalClient, opts ...RedisStoreOption) *RedisStore {
+	if client == nil {
+		panic("session: redis client is required")
+	}
+
+	s := &RedisStore{
+		client:    client,
+		keyPrefix: DefaultRedisKeyPrefix,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// Create stores a new session
+func (s *RedisStore) Create(ctx context.Context, session *Session) error {
+	if session == nil || session.Token == "" {
+		return ErrInvalidSession
+	}
+
+	data, err := json.Marshal(session)
+	if err != nil {
+		return err
+	}
+
+	key := s.sessionKey(session.Token)
+	_, err = s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, key)
+		pipe.HSet(ctx, key, s.fields(session, data)...)
+		pipe.PExpireAt(ctx, key, session.ExpiresAt)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if session.UserID != nil {
+		return s.indexUserSession(ctx, session.UserID.String(), session.Token, session.ExpiresAt)
+	}
+	return nil
+}
+
+// Get retrieves a session by token
+func (s *RedisStore) Get(ctx context.Context, token string) (*Session, error) {
+	values, err := s.client.HMGet(ctx, s.sessionKey(token), redisFieldSession, redisFieldLastActivity).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := decodeRedisSession(values)
+	if err != nil {
+		return nil, err
+	}
+	if session == nil {
+		return nil, ErrSessionNotFound
+	}
+
+	if session.IsExpired() {
+		_ = s.Delete(ctx, token)
+		return nil, ErrSessionExpired
+	}
+
+	return session, nil
+}
+
+// Update updates an existing session
+func (s *RedisStore) Update(ctx context.Context, session *Session) error {
+	if session == nil || session.Token == "" {
+		return ErrInvalidSession
+	}
+
+	data, err := json.Marshal(session)
+	if err != nil {
+		return err
+	}
+
+	var userID string
+	if session.UserID != nil {
+		userID = session.UserID.String()
+	}
+
+	prevUserID, err := updateScript.Run(ctx, s.client, []string{s.sessionKey(session.Token)},
+		data, session.LastActivityAt.Format(time.RFC3339Nano), userID, session.ExpiresAt.UnixMilli(),
+	).Text()
+	if errors.Is(err, redis.Nil) {
+		return ErrSessionNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	if prevUserID != "" && prevUserID != userID {
+		if err := s.client.SRem(ctx, s.userKey(prevUserID), session.Token).Err(); err != nil {
+			return err
+		}
+	}
+	if userID != "" {
+		return s.indexUserSession(ctx, userID, session.Token, session.ExpiresAt)
+	}
+	return nil
+}
+
+// UpdateActivity updates only the last activity time, without rewriting the session or its expiry
+func (s *RedisStore) UpdateActivity(ctx context.Context, token string, lastActivity time.Time) error {
+	updated, err := activityScript.Run(ctx, s.client, []string{s.sessionKey(token)},
+		lastActivity.Format(time.RFC3339Nano),
+	).Int()
+	if err != nil {
+		return err
+	}
+	if updated == 0 {
+		return ErrSessionNotFound
+	}
+	return nil
+}
+
+// Delete removes a session by token
+func (s *RedisStore) Delete(ctx context.Context, token string) error {
+	userID, err := deleteScript.Run(ctx, s.client, []string{s.sessionKey(token)}).Text()
+	if err != nil {
+		return err
+	}
+
+	if userID != "" {
+		return s.client.SRem(ctx, s.userKey(userID), token).Err()
+	}
+	return nil
+}
+
+// DeleteExpired is a no-op, as Redis expires sessions by itself
+func (s *RedisStore) DeleteExpired(ctx context.Context) error {
+	return nil
+}
+
+// DeleteByUserID removes all sessions for a specific user
+func (s *RedisStore) DeleteByUserID(ctx context.Context, userID string) error {
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return err
+	}
+
+	userKey := s.userKey(uid.String())
+	tokens, err := s.client.SMembers(ctx, userKey).Result()
+	if err != nil {
+		return err
+	}
+
+	// Keys may live on different cluster nodes, so delete them one by one in a pipeline
+	_, err = s.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, token := range tokens {
+			pipe.Del(ctx, s.sessionKey(token))
+		}
+		pipe.Del(ctx, userKey)
+		return nil
+	})
+	return err
+}
+
+// ListByUserID returns all non-expired sessions for a specific user
+func (s *RedisStore) ListByUserID(ctx context.Context, userID string) ([]*Session, error) {
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	userKey := s.userKey(uid.String())
+	tokens, err := s.client.SMembers(ctx, userKey).Result()
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, nil
+	}
+
+	cmds := make([]*redis.SliceCmd, len(tokens))
+	_, err = s.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i, token := range tokens {
+			cmds[i] = pipe.HMGet(ctx, s.sessionKey(token), redisFieldSession, redisFieldLastActivity)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var sessions []*Session
+	var stale []any
+	for i, cmd := range cmds {
+		session, err := decodeRedisSession(cmd.Val())
+		if err != nil {
+			return nil, err
+		}
+		if session == nil || session.IsExpired() || session.UserID == nil || *session.UserID != uid {
+			stale = append(stale, tokens[i])
+			continue
+		}
+		sessions = append(sessions, session)
+	}
+
+	// Tokens of expired sessions stay in the index until they're listed
+	if len(stale) > 0 {
+		_ = s.client.SRem(ctx, userKey, stale...).Err()
+	}
+
+	return sessions, nil
+}
+
+// indexUserSession adds the token to the user's index and keeps the index
+// alive at least as long as the session.
+func (s *RedisStore) indexUserSession(ctx context.Context, userID, token string, expiresAt time.Time) error {
+	ttl := time.Until(expiresAt)
+	if ttl <= 0 {
+		return nil // A negative TTL would delete the index
+	}
+	ttl = ttl.Truncate(time.Second) + time.Second // EXPIRE takes whole seconds, round up
+
+	userKey := s.userKey(userID)
+
+	_, err := s.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.SAdd(ctx, userKey, token)
+		pipe.ExpireNX(ctx, userKey, ttl)
+		pipe.ExpireGT(ctx, userKey, ttl)
+		return nil
+	})
+	return err
+}
+
+// fields returns the hash fields of a session for HSET.
+func (s *RedisStore) fields(session *Session, data []byte) []any {
+	fields := []any{
+		redisFieldSession, data,
+		redisFieldLastActivity, session.LastActivityAt.Format(time.RFC3339Nano),
+	}
+	if session.UserID != nil {
+		fields = append(fields, redisFieldUserID, session.UserID.String())
+	}
+	return fields
+}
+
+func (s *RedisStore) sessionKey(token string) string {
+	return s.keyPrefix + token
+}
+
+// userKey returns the key of a user's session index. Tokens are base64url
+// encoded and never contain ':', so it can't collide with a session key.
+func (s *RedisStore) userKey(userID string) string {
+	return s.keyPrefix + "user:" + userID
+}
+
+// decodeRedisSession decodes HMGET values of the session and last activity fields.
+// Returns nil without error if the session doesn't exist.
+func decodeRedisSession(values []any) (*Session, error) {
+	if len(values) != 2 {
+		return nil, nil
+	}
+
+	data, ok := values[0].(string)
+	if !ok {
+		return nil, nil
+	}
+
+	var session Session
+	if err := json.Unmarshal([]byte(data), &session); err != nil {
+		return nil, errors.Join(ErrInvalidSession, err)
+	}
+
+	if lastActivity, ok := values[1].(string); ok {
+		if t, err := time.Parse(time.RFC3339Nano, lastActivity); err == nil {
+			session.LastActivityAt = t
+		}
+	}
+
+	return &session, nil
+}
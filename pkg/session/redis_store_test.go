@@ -0,0 +1,415 @@
+package session_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dmitrymomot/saaskit/pkg/session"
+)
+
+var (
+	_ session.StoreWithCleanup      = (*session.RedisStore)(nil)
+	_ session.StoreWithUserSessions = (*session.RedisStore)(nil)
+)
+
+// newUnreachableRedisStore returns a store whose commands fail without a network round trip,
+// as nothing listens on port 0.
+func newUnreachableRedisStore(t *testing.T) *session.RedisStore {
+	t.Helper()
+	client := redis.NewClient(&redis.Options{
+		Addr:        "127.0.0.1:0",
+		DialTimeout: 100 * time.Millisecond,
+		MaxRetries:  -1,
+	})
+	t.Cleanup(func() { client.Close() })
+	return session.NewRedisStore(client, session.WithRedisKeyPrefix("test:"))
+}
+
+func TestNewRedisStore(t *testing.T) {
+	t.Parallel()
+
+	t.Run("panics without client", func(t *testing.T) {
+		t.Parallel()
+		assert.Panics(t, func() {
+			session.NewRedisStore(nil)
+		})
+	})
+
+	t.Run("supports session limits", func(t *testing.T) {
+		t.Parallel()
+		assert.NotPanics(t, func() {
+			mgr := session.New(
+				session.WithStore(newUnreachableRedisStore(t)),
+				session.WithTransport(session.NewHeaderTransport("X-Session-Token")),
+				session.WithMaxSessionsPerUser(3, session.EvictOldest),
+			)
+			defer mgr.Close()
+		})
+	})
+}
+
+func TestRedisStore_Validation(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	store := newUnreachableRedisStore(t)
+
+	t.Run("rejects invalid sessions", func(t *testing.T) {
+		t.Parallel()
+		assert.ErrorIs(t, store.Create(ctx, nil), session.ErrInvalidSession)
+		assert.ErrorIs(t, store.Create(ctx, &session.Session{}), session.ErrInvalidSession)
+		assert.ErrorIs(t, store.Update(ctx, nil), session.ErrInvalidSession)
+		assert.ErrorIs(t, store.Update(ctx, &session.Session{}), session.ErrInvalidSession)
+	})
+
+	t.Run("rejects invalid user IDs", func(t *testing.T) {
+		t.Parallel()
+		assert.Error(t, store.DeleteByUserID(ctx, "not-a-uuid"))
+		_, err := store.ListByUserID(ctx, "not-a-uuid")
+		assert.Error(t, err)
+	})
+
+	t.Run("delete expired is a no-op", func(t *testing.T) {
+		t.Parallel()
+		assert.NoError(t, store.DeleteExpired(ctx))
+	})
+
+	t.Run("returns redis errors", func(t *testing.T) {
+		t.Parallel()
+		userID := uuid.New()
+		sess := session.NewSession("token", &userID, "", time.Hour)
+
+		assert.Error(t, store.Create(ctx, sess))
+		_, err := store.Get(ctx, "token")
+		assert.Error(t, err)
+		assert.NotErrorIs(t, err, session.ErrSessionNotFound)
+		assert.Error(t, store.UpdateActivity(ctx, "token", time.Now()))
+		assert.Error(t, store.Delete(ctx, "token"))
+	})
+}
+
+// newTestRedisStore returns a RedisStore on the server at REDIS_URL, with keys under a
+// unique prefix that are removed after the test. The test is skipped if REDIS_URL is unset.
+func newTestRedisStore(t *testing.T) (*session.RedisStore, *redis.Client, string) {
+	t.Helper()
+
+	url := os.Getenv("REDIS_URL")
+	if url == "" {
+		t.Skip("REDIS_URL is not set")
+	}
+	opts, err := redis.ParseURL(url)
+	require.NoError(t, err)
+
+	client := redis.NewClient(opts)
+	require.NoError(t, client.Ping(context.Background()).Err())
+
+	prefix := "session-test:" + uuid.NewString() + ":"
+	t.Cleanup(func() {
+		ctx := context.Background()
+		keys, _ := client.Keys(ctx, prefix+"*").Result()
+		if len(keys) > 0 {
+			client.Del(ctx, keys...)
+		}
+		client.Close()
+	})
+
+	return session.NewRedisStore(client, session.WithRedisKeyPrefix(prefix)), client, prefix
+}
+
+// userSessionStore is implemented by MemoryStore and RedisStore.
+type userSessionStore interface {
+	session.StoreWithCleanup
+	session.StoreWithUserSessions
+}
+
+// TestStores runs the same behavior cases against MemoryStore and RedisStore.
+func TestStores(t *testing.T) {
+	t.Parallel()
+
+	stores := map[string]func(t *testing.T) userSessionStore{
+		"memory": func(t *testing.T) userSessionStore {
+			store := session.NewMemoryStore(0)
+			t.Cleanup(func() { store.Close() })
+			return store
+		},
+		"redis": func(t *testing.T) userSessionStore {
+			store, _, _ := newTestRedisStore(t)
+			return store
+		},
+	}
+
+	ctx := context.Background()
+
+	listTokens := func(t *testing.T, store userSessionStore, userID uuid.UUID) []string {
+		t.Helper()
+		sessions, err := store.ListByUserID(ctx, userID.String())
+		require.NoError(t, err)
+		tokens := make([]string, 0, len(sessions))
+		for _, s := range sessions {
+			tokens = append(tokens, s.Token)
+		}
+		return tokens
+	}
+
+	for name, newStore := range stores {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			t.Run("create and get", func(t *testing.T) {
+				t.Parallel()
+				store := newStore(t)
+
+				sess := session.NewSession("token", nil, "", time.Hour)
+				sess.Set("key", "value")
+				require.NoError(t, store.Create(ctx, sess))
+
+				// Later changes to the original don't leak into the store
+				sess.Set("key", "modified")
+
+				retrieved, err := store.Get(ctx, "token")
+				require.NoError(t, err)
+				assert.Equal(t, sess.ID, retrieved.ID)
+				val, _ := retrieved.GetString("key")
+				assert.Equal(t, "value", val)
+
+				_, err = store.Get(ctx, "missing")
+				assert.ErrorIs(t, err, session.ErrSessionNotFound)
+			})
+
+			t.Run("expired session is not returned", func(t *testing.T) {
+				t.Parallel()
+				store := newStore(t)
+
+				sess := session.NewSession("expired", nil, "", time.Hour)
+				sess.ExpiresAt = time.Now().Add(-time.Hour)
+				require.NoError(t, store.Create(ctx, sess))
+
+				// Redis drops a key expiring in the past right away
+				_, err := store.Get(ctx, "expired")
+				assert.True(t, errors.Is(err, session.ErrSessionExpired) || errors.Is(err, session.ErrSessionNotFound), err)
+			})
+
+			t.Run("update", func(t *testing.T) {
+				t.Parallel()
+				store := newStore(t)
+
+				sess := session.NewSession("token", nil, "", time.Hour)
+				sess.Set("key", "value1")
+				require.NoError(t, store.Create(ctx, sess))
+
+				sess.Set("key", "value2")
+				require.NoError(t, store.Update(ctx, sess))
+
+				retrieved, err := store.Get(ctx, "token")
+				require.NoError(t, err)
+				val, _ := retrieved.GetString("key")
+				assert.Equal(t, "value2", val)
+
+				err = store.Update(ctx, session.NewSession("missing", nil, "", time.Hour))
+				assert.ErrorIs(t, err, session.ErrSessionNotFound)
+			})
+
+			t.Run("update does not bring back a deleted session", func(t *testing.T) {
+				t.Parallel()
+				store := newStore(t)
+				userID := uuid.New()
+
+				sess := session.NewSession("token", &userID, "", time.Hour)
+				require.NoError(t, store.Create(ctx, sess))
+				require.NoError(t, store.Delete(ctx, "token"))
+
+				assert.ErrorIs(t, store.Update(ctx, sess), session.ErrSessionNotFound)
+				_, err := store.Get(ctx, "token")
+				assert.ErrorIs(t, err, session.ErrSessionNotFound)
+				assert.Empty(t, listTokens(t, store, userID))
+			})
+
+			t.Run("update activity", func(t *testing.T) {
+				t.Parallel()
+				store := newStore(t)
+
+				sess := session.NewSession("token", nil, "", time.Hour)
+				require.NoError(t, store.Create(ctx, sess))
+
+				newTime := time.Now().Add(10 * time.Minute)
+				require.NoError(t, store.UpdateActivity(ctx, "token", newTime))
+
+				retrieved, err := store.Get(ctx, "token")
+				require.NoError(t, err)
+				assert.Equal(t, newTime.Unix(), retrieved.LastActivityAt.Unix())
+
+				assert.ErrorIs(t, store.UpdateActivity(ctx, "missing", time.Now()), session.ErrSessionNotFound)
+			})
+
+			t.Run("delete", func(t *testing.T) {
+				t.Parallel()
+				store := newStore(t)
+
+				require.NoError(t, store.Create(ctx, session.NewSession("token", nil, "", time.Hour)))
+				require.NoError(t, store.Delete(ctx, "token"))
+
+				_, err := store.Get(ctx, "token")
+				assert.ErrorIs(t, err, session.ErrSessionNotFound)
+				assert.NoError(t, store.Delete(ctx, "missing"))
+			})
+
+			t.Run("delete by user ID", func(t *testing.T) {
+				t.Parallel()
+				store := newStore(t)
+				userID1 := uuid.New()
+				userID2 := uuid.New()
+
+				require.NoError(t, store.Create(ctx, session.NewSession("user1-1", &userID1, "", time.Hour)))
+				require.NoError(t, store.Create(ctx, session.NewSession("user1-2", &userID1, "", time.Hour)))
+				require.NoError(t, store.Create(ctx, session.NewSession("user2-1", &userID2, "", time.Hour)))
+				require.NoError(t, store.Create(ctx, session.NewSession("anon", nil, "", time.Hour)))
+
+				require.NoError(t, store.DeleteByUserID(ctx, userID1.String()))
+
+				_, err := store.Get(ctx, "user1-1")
+				assert.ErrorIs(t, err, session.ErrSessionNotFound)
+				_, err = store.Get(ctx, "user1-2")
+				assert.ErrorIs(t, err, session.ErrSessionNotFound)
+				assert.Empty(t, listTokens(t, store, userID1))
+
+				_, err = store.Get(ctx, "user2-1")
+				assert.NoError(t, err)
+				_, err = store.Get(ctx, "anon")
+				assert.NoError(t, err)
+			})
+
+			t.Run("list by user ID skips expired sessions", func(t *testing.T) {
+				t.Parallel()
+				store := newStore(t)
+				userID1 := uuid.New()
+				userID2 := uuid.New()
+
+				require.NoError(t, store.Create(ctx, session.NewSession("user1-1", &userID1, "", time.Hour)))
+				require.NoError(t, store.Create(ctx, session.NewSession("user1-2", &userID1, "", time.Hour)))
+				require.NoError(t, store.Create(ctx, session.NewSession("user1-expiring", &userID1, "", 200*time.Millisecond)))
+				require.NoError(t, store.Create(ctx, session.NewSession("user2-1", &userID2, "", time.Hour)))
+				require.NoError(t, store.Create(ctx, session.NewSession("anon", nil, "", time.Hour)))
+
+				assert.ElementsMatch(t, []string{"user1-1", "user1-2", "user1-expiring"}, listTokens(t, store, userID1))
+
+				time.Sleep(300 * time.Millisecond)
+				assert.ElementsMatch(t, []string{"user1-1", "user1-2"}, listTokens(t, store, userID1))
+			})
+
+			t.Run("session moves between users", func(t *testing.T) {
+				t.Parallel()
+				store := newStore(t)
+				userID1 := uuid.New()
+				userID2 := uuid.New()
+
+				sess := session.NewSession("token", nil, "", time.Hour)
+				require.NoError(t, store.Create(ctx, sess))
+
+				sess.UserID = &userID1
+				require.NoError(t, store.Update(ctx, sess))
+				assert.Equal(t, []string{"token"}, listTokens(t, store, userID1))
+
+				sess.UserID = &userID2
+				require.NoError(t, store.Update(ctx, sess))
+				assert.Empty(t, listTokens(t, store, userID1))
+				assert.Equal(t, []string{"token"}, listTokens(t, store, userID2))
+
+				// A revoked user doesn't affect a session moved to another user
+				require.NoError(t, store.DeleteByUserID(ctx, userID1.String()))
+				_, err := store.Get(ctx, "token")
+				require.NoError(t, err)
+
+				require.NoError(t, store.Delete(ctx, "token"))
+				assert.Empty(t, listTokens(t, store, userID2))
+			})
+		})
+	}
+}
+
+func TestRedisStore_Integration(t *testing.T) {
+	t.Parallel()
+
+	store, client, prefix := newTestRedisStore(t)
+	ctx := context.Background()
+	userKey := func(userID uuid.UUID) string { return prefix + "user:" + userID.String() }
+
+	t.Run("update does not bring back an expired session", func(t *testing.T) {
+		sess := session.NewSession("expiring", nil, "", 100*time.Millisecond)
+		require.NoError(t, store.Create(ctx, sess))
+
+		time.Sleep(200 * time.Millisecond)
+		sess.ExpiresAt = time.Now().Add(time.Hour)
+
+		assert.ErrorIs(t, store.Update(ctx, sess), session.ErrSessionNotFound)
+		assert.Zero(t, client.Exists(ctx, prefix+"expiring").Val())
+	})
+
+	t.Run("moving a session removes it from the previous user index", func(t *testing.T) {
+		userID1 := uuid.New()
+		userID2 := uuid.New()
+
+		sess := session.NewSession("moving", &userID1, "", time.Hour)
+		require.NoError(t, store.Create(ctx, sess))
+		assert.Equal(t, []string{"moving"}, client.SMembers(ctx, userKey(userID1)).Val())
+
+		sess.UserID = &userID2
+		require.NoError(t, store.Update(ctx, sess))
+		assert.Empty(t, client.SMembers(ctx, userKey(userID1)).Val())
+		assert.Equal(t, []string{"moving"}, client.SMembers(ctx, userKey(userID2)).Val())
+		assert.Equal(t, userID2.String(), client.HGet(ctx, prefix+"moving", "user_id").Val())
+
+		// Logging out drops the user ID and the index entry
+		sess.UserID = nil
+		require.NoError(t, store.Update(ctx, sess))
+		assert.Empty(t, client.SMembers(ctx, userKey(userID2)).Val())
+		assert.Zero(t, client.HExists(ctx, prefix+"moving", "user_id").Val())
+	})
+
+	t.Run("user index lives as long as its longest session", func(t *testing.T) {
+		userID := uuid.New()
+
+		require.NoError(t, store.Create(ctx, session.NewSession("index-1", &userID, "", time.Hour)))
+		assert.InDelta(t, time.Hour, client.TTL(ctx, userKey(userID)).Val(), float64(2*time.Second))
+
+		// A shorter session doesn't shorten the index
+		require.NoError(t, store.Create(ctx, session.NewSession("index-2", &userID, "", 10*time.Minute)))
+		assert.InDelta(t, time.Hour, client.TTL(ctx, userKey(userID)).Val(), float64(2*time.Second))
+
+		// A longer one extends it
+		require.NoError(t, store.Create(ctx, session.NewSession("index-3", &userID, "", 2*time.Hour)))
+		assert.InDelta(t, 2*time.Hour, client.TTL(ctx, userKey(userID)).Val(), float64(2*time.Second))
+	})
+
+	t.Run("listing prunes stale index members", func(t *testing.T) {
+		userID := uuid.New()
+
+		require.NoError(t, store.Create(ctx, session.NewSession("stale-live", &userID, "", time.Hour)))
+		require.NoError(t, store.Create(ctx, session.NewSession("stale-expiring", &userID, "", 100*time.Millisecond)))
+		assert.ElementsMatch(t, []string{"stale-live", "stale-expiring"}, client.SMembers(ctx, userKey(userID)).Val())
+
+		time.Sleep(200 * time.Millisecond)
+
+		sessions, err := store.ListByUserID(ctx, userID.String())
+		require.NoError(t, err)
+		require.Len(t, sessions, 1)
+		assert.Equal(t, "stale-live", sessions[0].Token)
+		assert.Equal(t, []string{"stale-live"}, client.SMembers(ctx, userKey(userID)).Val())
+	})
+
+	t.Run("delete by user ID removes the index", func(t *testing.T) {
+		userID := uuid.New()
+
+		require.NoError(t, store.Create(ctx, session.NewSession("revoke-1", &userID, "", time.Hour)))
+		require.NoError(t, store.Create(ctx, session.NewSession("revoke-2", &userID, "", time.Hour)))
+		require.NoError(t, store.DeleteByUserID(ctx, userID.String()))
+
+		assert.Zero(t, client.Exists(ctx, prefix+"revoke-1", prefix+"revoke-2", userKey(userID)).Val())
+	})
+}
@@ -0,0 +1,39 @@
+package session
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// RevokeByUserID deletes all sessions of the user, e.g. after a password change.
+//
+// Stores implementing StoreWithCleanup delete them with a single DeleteByUserID call;
+// MemoryStore and RedisStore use a per-user index for it. Otherwise, stores implementing
+// StoreWithUserSessions fall back to ListByUserID and one Delete per session, which costs
+// a listing plus N deletes. Other stores return ErrRevocationNotSupported.
+func (m *Manager) RevokeByUserID(ctx context.Context, userID uuid.UUID) error {
+	// Logins enforcing WithMaxSessionsPerUser take the same lock, so their listing
+	// and eviction can't interleave with the revocation
+	mu := m.userLocks.get(userID)
+	mu.Lock()
+	defer mu.Unlock()
+
+	switch store := m.store.(type) {
+	case StoreWithCleanup:
+		return store.DeleteByUserID(ctx, userID.String())
+	case StoreWithUserSessions:
+		sessions, err := store.ListByUserID(ctx, userID.String())
+		if err != nil {
+			return err
+		}
+		for _, s := range sessions {
+			if err := store.Delete(ctx, s.Token); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return ErrRevocationNotSupported
+	}
+}
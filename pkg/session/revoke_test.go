@@ -0,0 +1,76 @@
+package session_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dmitrymomot/saaskit/pkg/session"
+)
+
+// listOnlyStore hides MemoryStore.DeleteByUserID to exercise the ListByUserID fallback
+type listOnlyStore struct {
+	session.Store
+	mem *session.MemoryStore
+}
+
+func (s listOnlyStore) ListByUserID(ctx context.Context, userID string) ([]*session.Session, error) {
+	return s.mem.ListByUserID(ctx, userID)
+}
+
+// basicStore implements only Store
+type basicStore struct {
+	session.Store
+}
+
+func TestManager_RevokeByUserID(t *testing.T) {
+	newManager := func(t *testing.T, store session.Store) *session.Manager {
+		manager := session.New(
+			session.WithStore(store),
+			session.WithTransport(session.NewHeaderTransport("X-Session-Token")),
+		)
+		t.Cleanup(func() { _ = manager.Close() })
+		return manager
+	}
+
+	for name, wrap := range map[string]func(*session.MemoryStore) session.Store{
+		"delete by user ID": func(m *session.MemoryStore) session.Store { return m },
+		"list and delete":   func(m *session.MemoryStore) session.Store { return listOnlyStore{Store: m, mem: m} },
+	} {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			mem := session.NewMemoryStore(0)
+			t.Cleanup(func() { _ = mem.Close() })
+			manager := newManager(t, wrap(mem))
+
+			userID, otherID := uuid.New(), uuid.New()
+			_, first, err := login(t, manager, userID)
+			require.NoError(t, err)
+			_, second, err := login(t, manager, userID)
+			require.NoError(t, err)
+			_, other, err := login(t, manager, otherID)
+			require.NoError(t, err)
+
+			require.NoError(t, manager.RevokeByUserID(ctx, userID))
+
+			for _, token := range []string{first, second} {
+				_, err := mem.Get(ctx, token)
+				assert.ErrorIs(t, err, session.ErrSessionNotFound)
+			}
+			_, err = mem.Get(ctx, other)
+			assert.NoError(t, err, "other users keep their sessions")
+		})
+	}
+
+	t.Run("unsupported store", func(t *testing.T) {
+		mem := session.NewMemoryStore(0)
+		t.Cleanup(func() { _ = mem.Close() })
+		manager := newManager(t, basicStore{Store: mem})
+
+		err := manager.RevokeByUserID(context.Background(), uuid.New())
+		assert.ErrorIs(t, err, session.ErrRevocationNotSupported)
+	})
+}
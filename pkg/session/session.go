@@ -8,14 +8,17 @@ import (
 
 // Session represents a user session with associated data
 type Session struct {
-	ID             uuid.UUID      `json:"id"`
-	Token          string         `json:"token"`
-	UserID         *uuid.UUID     `json:"user_id,omitempty"`
-	Fingerprint    string         `json:"fingerprint,omitempty"`
-	Data           map[string]any `json:"data,omitempty"`
-	ExpiresAt      time.Time      `json:"expires_at"`
-	LastActivityAt time.Time      `json:"last_activity_at"`
-	CreatedAt      time.Time      `json:"created_at"`
+	ID                uuid.UUID      `json:"id"`
+	Token             string         `json:"token"`
+	UserID            *uuid.UUID     `json:"user_id,omitempty"`
+	Fingerprint       string         `json:"fingerprint,omitempty"`
+	IPAddress         string         `json:"ip_address,omitempty"` // client IP when the session was created
+	UserAgent         string         `json:"user_agent,omitempty"` // client User-Agent when the session was created
+	Data              map[string]any `json:"data,omitempty"`
+	ExpiresAt         time.Time      `json:"expires_at"`
+	AbsoluteExpiresAt time.Time      `json:"absolute_expires_at,omitzero"` // absolute timeout deadline, never extended; zero if none
+	LastActivityAt    time.Time      `json:"last_activity_at"`
+	CreatedAt         time.Time      `json:"created_at"`
 }
 
 // NewSession creates a new session with the given parameters
@@ -40,7 +43,11 @@ func (s *Session) IsAuthenticated() bool {
 
 // IsExpired returns true if the session has expired
 func (s *Session) IsExpired() bool {
-	return s != nil && time.Now().After(s.ExpiresAt)
+	if s == nil {
+		return false
+	}
+	now := time.Now()
+	return now.After(s.ExpiresAt) || (!s.AbsoluteExpiresAt.IsZero() && now.After(s.AbsoluteExpiresAt))
 }
 
 // Get retrieves a value from session data
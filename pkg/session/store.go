@@ -32,3 +32,11 @@ type StoreWithCleanup interface {
 	// DeleteByUserID removes all sessions for a specific user
 	DeleteByUserID(ctx context.Context, userID string) error
 }
+
+// StoreWithUserSessions is an optional interface for stores that can enumerate
+// a user's sessions. Required by WithMaxSessionsPerUser.
+type StoreWithUserSessions interface {
+	Store
+	// ListByUserID returns all non-expired sessions of a specific user
+	ListByUserID(ctx context.Context, userID string) ([]*Session, error)
+}
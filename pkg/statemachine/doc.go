@@ -62,6 +62,33 @@
 //	    return nil
 //	}
 //
+// # Timed Transitions
+//
+// WithTimedTransition fires an event automatically once the machine has spent
+// the given duration in a state, unless it leaves the state first:
+//
+//	statemachine.WithTimedTransition(PendingPayment, 30*time.Minute, Expire)
+//
+// When the machine is no longer needed, release pending timers through the
+// optional Stopper interface:
+//
+//	defer sm.(statemachine.Stopper).Stop()
+//
+// # Dry Runs
+//
+// DryRun checks a whole sequence of events from the current state without
+// executing actions or changing state, e.g. to pre-validate a multi-step
+// operation in a UI. It belongs to the optional DryRunner interface, which
+// machines from New and MustNew implement. It returns the final state, or the
+// state reached before the first blocking event together with an
+// *ErrDryRunFailed naming its step:
+//
+//	final, err := machine.(statemachine.DryRunner).DryRun(ctx, []statemachine.Event{Submit, Approve, Publish}, user)
+//	var failed *statemachine.ErrDryRunFailed
+//	if errors.As(err, &failed) {
+//	    log.Printf("step %d (%s) is blocked: %v", failed.Step, failed.EventName, failed.Err)
+//	}
+//
 // # Error Handling
 //
 // When Fire returns an error you can inspect it using helper functions:
@@ -72,7 +99,8 @@
 // # Concurrency
 //
 // SimpleStateMachine uses RWMutex for thread safety, making read operations
-// (Current, CanFire) cheap while serializing mutations (AddTransition, Fire, Reset).
+// (Current, CanFire, DryRun) cheap while serializing mutations (AddTransition, Fire, Reset).
+// Timed transitions fire on timer goroutines and take the same lock.
 //
 // # See Also
 //
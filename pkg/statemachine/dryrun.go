@@ -0,0 +1,49 @@
+package statemachine
+
+import (
+	"context"
+)
+
+// DryRunner is implemented by state machines that can evaluate an event sequence
+// without executing it. Machines returned by New and MustNew implement it:
+//
+//	final, err := machine.(statemachine.DryRunner).DryRun(ctx, events, data)
+type DryRunner interface {
+	// DryRun evaluates events in sequence from the current state without
+	// executing actions or changing state, returning the final state.
+	DryRun(ctx context.Context, events []Event, data any) (State, error)
+}
+
+var _ DryRunner = (*SimpleStateMachine)(nil)
+
+// DryRun evaluates a sequence of events from the current state without changing it,
+// e.g. to pre-validate a multi-step operation before committing to it. Guards are
+// evaluated with data as Fire would; Actions are not executed and timed transitions
+// are not scheduled.
+//
+// It returns the state the machine would end up in. If an event can't fire, the
+// returned state is the one reached before it and the error is an *ErrDryRunFailed
+// identifying the blocking step; the helper predicates such as
+// IsTransitionRejectedError see through it.
+func (sm *SimpleStateMachine) DryRun(ctx context.Context, events []Event, data any) (State, error) {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+
+	state := sm.currentState
+	for i, event := range events {
+		if event == nil {
+			return state, NewErrDryRunFailed(i, state.Name(), "", ErrInvalidEvent)
+		}
+		if err := ctx.Err(); err != nil {
+			return state, NewErrDryRunFailed(i, state.Name(), event.Name(), err)
+		}
+
+		t, err := sm.findTransitionLocked(ctx, state, event, data)
+		if err != nil {
+			return state, NewErrDryRunFailed(i, state.Name(), event.Name(), err)
+		}
+		state = t.To
+	}
+
+	return state, nil
+}
@@ -0,0 +1,166 @@
+package statemachine_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dmitrymomot/saaskit/pkg/statemachine"
+)
+
+func TestDryRun(t *testing.T) {
+	t.Parallel()
+
+	const (
+		Draft     = statemachine.StringState("draft")
+		InReview  = statemachine.StringState("in_review")
+		Approved  = statemachine.StringState("approved")
+		Published = statemachine.StringState("published")
+	)
+
+	const (
+		Submit  = statemachine.StringEvent("submit")
+		Approve = statemachine.StringEvent("approve")
+		Publish = statemachine.StringEvent("publish")
+	)
+
+	isEditor := func(_ context.Context, _ statemachine.State, _ statemachine.Event, data any) bool {
+		return data == "editor"
+	}
+
+	type dryRunMachine interface {
+		statemachine.StateMachine
+		statemachine.DryRunner
+	}
+
+	newMachine := func(actionCalls *int) dryRunMachine {
+		countAction := func(context.Context, statemachine.State, statemachine.State, statemachine.Event, any) error {
+			*actionCalls++
+			return nil
+		}
+		sm := statemachine.MustNew(Draft,
+			statemachine.WithTransition(Draft, InReview, Submit, statemachine.WithAction(countAction)),
+			statemachine.WithTransition(InReview, Approved, Approve,
+				statemachine.WithGuard(isEditor),
+				statemachine.WithAction(countAction),
+			),
+			statemachine.WithTransition(Approved, Published, Publish, statemachine.WithAction(countAction)),
+		)
+		return sm.(dryRunMachine)
+	}
+
+	t.Run("valid sequence", func(t *testing.T) {
+		t.Parallel()
+		var actionCalls int
+		sm := newMachine(&actionCalls)
+
+		final, err := sm.DryRun(context.Background(), []statemachine.Event{Submit, Approve, Publish}, "editor")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if final != Published {
+			t.Fatalf("Expected final state %s, got %s", Published, final)
+		}
+		if sm.Current() != Draft {
+			t.Fatalf("Expected current state to stay %s, got %s", Draft, sm.Current())
+		}
+		if actionCalls != 0 {
+			t.Fatalf("Expected no actions to run, got %d calls", actionCalls)
+		}
+	})
+
+	t.Run("empty sequence", func(t *testing.T) {
+		t.Parallel()
+		var actionCalls int
+		sm := newMachine(&actionCalls)
+
+		final, err := sm.DryRun(context.Background(), nil, nil)
+		if err != nil || final != Draft {
+			t.Fatalf("Expected %s and no error, got %s and %v", Draft, final, err)
+		}
+	})
+
+	t.Run("rejected by guard", func(t *testing.T) {
+		t.Parallel()
+		var actionCalls int
+		sm := newMachine(&actionCalls)
+
+		final, err := sm.DryRun(context.Background(), []statemachine.Event{Submit, Approve, Publish}, "viewer")
+		if final != InReview {
+			t.Fatalf("Expected state before the failing step %s, got %s", InReview, final)
+		}
+
+		var dryRunErr *statemachine.ErrDryRunFailed
+		if !errors.As(err, &dryRunErr) {
+			t.Fatalf("Expected ErrDryRunFailed, got %v", err)
+		}
+		if dryRunErr.Step != 1 || dryRunErr.EventName != "approve" || dryRunErr.StateName != "in_review" {
+			t.Fatalf("Unexpected failing step: %+v", dryRunErr)
+		}
+		if !statemachine.IsTransitionRejectedError(err) {
+			t.Fatalf("Expected cause to be TransitionRejectedError, got %v", err)
+		}
+		if sm.Current() != Draft || actionCalls != 0 {
+			t.Fatalf("Expected machine untouched, got state %s and %d action calls", sm.Current(), actionCalls)
+		}
+	})
+
+	t.Run("no transition available", func(t *testing.T) {
+		t.Parallel()
+		var actionCalls int
+		sm := newMachine(&actionCalls)
+
+		final, err := sm.DryRun(context.Background(), []statemachine.Event{Publish}, nil)
+		if final != Draft {
+			t.Fatalf("Expected state %s, got %s", Draft, final)
+		}
+		if !statemachine.IsDryRunFailedError(err) || !statemachine.IsNoTransitionAvailableError(err) {
+			t.Fatalf("Expected dry run failure caused by missing transition, got %v", err)
+		}
+	})
+
+	t.Run("nil event", func(t *testing.T) {
+		t.Parallel()
+		var actionCalls int
+		sm := newMachine(&actionCalls)
+
+		_, err := sm.DryRun(context.Background(), []statemachine.Event{Submit, nil}, nil)
+		if !errors.Is(err, statemachine.ErrInvalidEvent) {
+			t.Fatalf("Expected ErrInvalidEvent, got %v", err)
+		}
+	})
+
+	t.Run("canceled context", func(t *testing.T) {
+		t.Parallel()
+		var actionCalls int
+		sm := newMachine(&actionCalls)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := sm.DryRun(ctx, []statemachine.Event{Submit}, nil)
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("Expected context.Canceled, got %v", err)
+		}
+	})
+
+	t.Run("does not schedule timed transitions", func(t *testing.T) {
+		t.Parallel()
+		const Expire = statemachine.StringEvent("expire")
+		sm := statemachine.MustNew(Draft,
+			statemachine.WithTransition(Draft, InReview, Submit),
+			statemachine.WithTransition(InReview, Draft, Expire),
+			statemachine.WithTimedTransition(InReview, time.Millisecond, Expire),
+		)
+		defer sm.(statemachine.Stopper).Stop()
+
+		if _, err := sm.(statemachine.DryRunner).DryRun(context.Background(), []statemachine.Event{Submit}, nil); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		time.Sleep(10 * time.Millisecond)
+		if sm.Current() != Draft {
+			t.Fatalf("Expected state %s, got %s", Draft, sm.Current())
+		}
+	})
+}
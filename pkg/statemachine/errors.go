@@ -8,6 +8,8 @@ import (
 var (
 	ErrInvalidTransition = errors.New("invalid transition: from, to, or event cannot be nil")
 	ErrInvalidEvent      = errors.New("invalid event: event cannot be nil")
+	ErrInvalidTimeout    = errors.New("invalid timed transition: duration must be positive")
+	ErrDuplicateTimeout  = errors.New("invalid timed transition: state already has a timeout")
 )
 
 // ErrNoTransitionAvailable indicates no valid transition exists for the given state/event combination.
@@ -44,6 +46,32 @@ func NewErrTransitionRejected(stateName, eventName string) *ErrTransitionRejecte
 	}
 }
 
+// ErrDryRunFailed indicates the event at index Step of a DryRun sequence can't fire
+// from state StateName. Err is the cause, e.g. *ErrTransitionRejected.
+type ErrDryRunFailed struct {
+	Step      int
+	StateName string
+	EventName string
+	Err       error
+}
+
+func (e *ErrDryRunFailed) Error() string {
+	return fmt.Sprintf("dry run failed at step %d (event '%s' from state '%s'): %v", e.Step, e.EventName, e.StateName, e.Err)
+}
+
+func (e *ErrDryRunFailed) Unwrap() error {
+	return e.Err
+}
+
+func NewErrDryRunFailed(step int, stateName, eventName string, err error) *ErrDryRunFailed {
+	return &ErrDryRunFailed{
+		Step:      step,
+		StateName: stateName,
+		EventName: eventName,
+		Err:       err,
+	}
+}
+
 func IsNoTransitionAvailableError(err error) bool {
 	var e *ErrNoTransitionAvailable
 	return errors.As(err, &e)
@@ -53,3 +81,8 @@ func IsTransitionRejectedError(err error) bool {
 	var e *ErrTransitionRejected
 	return errors.As(err, &e)
 }
+
+func IsDryRunFailedError(err error) bool {
+	var e *ErrDryRunFailed
+	return errors.As(err, &e)
+}
@@ -38,6 +38,11 @@ func New(initialState State, opts ...Option) (StateMachine, error) {
 		}
 	}
 
+	// The initial state may itself have a timeout
+	sm.mu.Lock()
+	sm.scheduleTimedLocked()
+	sm.mu.Unlock()
+
 	return sm, nil
 }
 
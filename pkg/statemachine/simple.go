@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"sync"
+	"time"
 )
 
 // SimpleStateMachine provides a thread-safe in-memory state machine implementation.
@@ -13,6 +14,13 @@ type SimpleStateMachine struct {
 	currentState State
 	transitions  map[string]map[string][]Transition
 	mu           sync.RWMutex
+
+	// Timed transitions, see timed.go
+	timed        map[string]timedTransition
+	timer        *time.Timer
+	timerGen     uint64 // invalidates timers that fired while the state was changing
+	stopped      bool
+	onTimedError TimedErrorHandler
 }
 
 func newSimpleStateMachine(initialState State) *SimpleStateMachine {
@@ -20,6 +28,7 @@ func newSimpleStateMachine(initialState State) *SimpleStateMachine {
 		initialState: initialState,
 		currentState: initialState,
 		transitions:  make(map[string]map[string][]Transition),
+		timed:        make(map[string]timedTransition),
 	}
 	return sm
 }
@@ -66,36 +75,16 @@ func (sm *SimpleStateMachine) Fire(ctx context.Context, event Event, data any) e
 	sm.mu.Lock()
 	defer sm.mu.Unlock()
 
-	currentStateName := sm.currentState.Name()
-	eventName := event.Name()
-
-	if _, ok := sm.transitions[currentStateName]; !ok {
-		return NewErrNoTransitionAvailable(currentStateName, eventName)
-	}
-
-	transitions, ok := sm.transitions[currentStateName][eventName]
-	if !ok || len(transitions) == 0 {
-		return NewErrNoTransitionAvailable(currentStateName, eventName)
-	}
+	return sm.fireLocked(ctx, event, data)
+}
 
-	// First transition with passing guards wins (enables priority ordering)
-	var validTransition *Transition
-	for i, t := range transitions {
-		allGuardsPassed := true
-		for _, guard := range t.Guards {
-			if guard != nil && !guard(ctx, sm.currentState, event, data) {
-				allGuardsPassed = false
-				break
-			}
-		}
-		if allGuardsPassed {
-			validTransition = &transitions[i]
-			break
-		}
-	}
+// fireLocked performs the transition; the caller must hold the write lock.
+func (sm *SimpleStateMachine) fireLocked(ctx context.Context, event Event, data any) error {
+	currentStateName := sm.currentState.Name()
 
-	if validTransition == nil {
-		return NewErrTransitionRejected(currentStateName, eventName)
+	validTransition, err := sm.findTransitionLocked(ctx, sm.currentState, event, data)
+	if err != nil {
+		return err
 	}
 
 	// Execute actions before state change; any failure aborts transition
@@ -108,49 +97,57 @@ func (sm *SimpleStateMachine) Fire(ctx context.Context, event Event, data any) e
 	}
 
 	sm.currentState = validTransition.To
+	if validTransition.To.Name() != currentStateName {
+		sm.scheduleTimedLocked()
+	}
 	return nil
 }
 
-func (sm *SimpleStateMachine) CanFire(ctx context.Context, event Event, data any) bool {
-	if event == nil {
-		return false
-	}
-
-	sm.mu.RLock()
-	defer sm.mu.RUnlock()
-
-	currentStateName := sm.currentState.Name()
+// findTransitionLocked returns the transition event would take from state, evaluating
+// guards but not actions; the caller must hold the read or write lock.
+func (sm *SimpleStateMachine) findTransitionLocked(ctx context.Context, from State, event Event, data any) (*Transition, error) {
+	fromStateName := from.Name()
 	eventName := event.Name()
 
-	if _, ok := sm.transitions[currentStateName]; !ok {
-		return false
-	}
-
-	transitions, ok := sm.transitions[currentStateName][eventName]
+	transitions, ok := sm.transitions[fromStateName][eventName]
 	if !ok || len(transitions) == 0 {
-		return false
+		return nil, NewErrNoTransitionAvailable(fromStateName, eventName)
 	}
 
-	// Return true if any transition's guards would allow it
-	for _, t := range transitions {
+	// First transition with passing guards wins (enables priority ordering)
+	for i, t := range transitions {
 		allGuardsPassed := true
 		for _, guard := range t.Guards {
-			if guard != nil && !guard(ctx, sm.currentState, event, data) {
+			if guard != nil && !guard(ctx, from, event, data) {
 				allGuardsPassed = false
 				break
 			}
 		}
 		if allGuardsPassed {
-			return true
+			return &transitions[i], nil
 		}
 	}
 
-	return false
+	return nil, NewErrTransitionRejected(fromStateName, eventName)
+}
+
+func (sm *SimpleStateMachine) CanFire(ctx context.Context, event Event, data any) bool {
+	if event == nil {
+		return false
+	}
+
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+
+	// True if any transition's guards would allow it
+	_, err := sm.findTransitionLocked(ctx, sm.currentState, event, data)
+	return err == nil
 }
 
 func (sm *SimpleStateMachine) Reset() error {
 	sm.mu.Lock()
 	defer sm.mu.Unlock()
 	sm.currentState = sm.initialState
+	sm.scheduleTimedLocked()
 	return nil
 }
@@ -0,0 +1,124 @@
+package statemachine
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// TimedErrorHandler receives errors from automatic transitions, e.g. when a guard
+// rejects the timeout event. The machine stays in the state it was in.
+type TimedErrorHandler func(from State, event Event, err error)
+
+type timedTransition struct {
+	after time.Duration
+	event Event
+}
+
+// WithTimedTransition fires event automatically once the machine has been in
+// state from for the given duration, unless it leaves the state first:
+//
+//	statemachine.WithTimedTransition(PendingPayment, 30*time.Minute, Expire)
+//
+// The event goes through Fire as usual, so a transition for it must exist and
+// its guards and actions run with a background context and nil data.
+// Self-transitions don't restart the timer. Call Stop to release pending timers.
+func WithTimedTransition(from State, after time.Duration, event Event) Option {
+	return func(sm *SimpleStateMachine) error {
+		if from == nil || event == nil {
+			return ErrInvalidTransition
+		}
+		if after <= 0 {
+			return ErrInvalidTimeout
+		}
+
+		sm.mu.Lock()
+		defer sm.mu.Unlock()
+
+		if _, exists := sm.timed[from.Name()]; exists {
+			return fmt.Errorf("%w: %s", ErrDuplicateTimeout, from.Name())
+		}
+		sm.timed[from.Name()] = timedTransition{after: after, event: event}
+		return nil
+	}
+}
+
+// WithTimedErrorHandler sets a handler for failed automatic transitions.
+// Without it such errors are discarded.
+func WithTimedErrorHandler(handler TimedErrorHandler) Option {
+	return func(sm *SimpleStateMachine) error {
+		sm.mu.Lock()
+		defer sm.mu.Unlock()
+		sm.onTimedError = handler
+		return nil
+	}
+}
+
+// Stopper is implemented by state machines that schedule timed transitions.
+// Machines returned by New and MustNew implement it:
+//
+//	defer machine.(statemachine.Stopper).Stop()
+type Stopper interface {
+	// Stop cancels pending timed transitions. Call it when the machine is
+	// no longer needed to release timers; manual transitions keep working.
+	Stop()
+}
+
+var _ Stopper = (*SimpleStateMachine)(nil)
+
+// Stop cancels the pending timed transition and prevents new ones from being scheduled.
+func (sm *SimpleStateMachine) Stop() {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	sm.stopped = true
+	sm.cancelTimerLocked()
+}
+
+// scheduleTimedLocked replaces the pending timer with the current state's timeout, if any.
+func (sm *SimpleStateMachine) scheduleTimedLocked() {
+	sm.cancelTimerLocked()
+
+	if sm.stopped {
+		return
+	}
+
+	tt, ok := sm.timed[sm.currentState.Name()]
+	if !ok {
+		return
+	}
+
+	gen := sm.timerGen
+	sm.timer = time.AfterFunc(tt.after, func() {
+		sm.fireTimed(gen, tt.event)
+	})
+}
+
+func (sm *SimpleStateMachine) cancelTimerLocked() {
+	if sm.timer != nil {
+		sm.timer.Stop()
+		sm.timer = nil
+	}
+	sm.timerGen++
+}
+
+func (sm *SimpleStateMachine) fireTimed(gen uint64, event Event) {
+	sm.mu.Lock()
+
+	// The timer may fire while a manual transition holds the lock; Stop can't catch that
+	if gen != sm.timerGen || sm.stopped {
+		sm.mu.Unlock()
+		return
+	}
+	sm.timer = nil
+
+	from := sm.currentState
+	err := sm.fireLocked(context.Background(), event, nil)
+	handler := sm.onTimedError
+	sm.mu.Unlock()
+
+	// Called without the lock so the handler can inspect the machine
+	if err != nil && handler != nil {
+		handler(from, event, err)
+	}
+}
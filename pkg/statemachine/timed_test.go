@@ -0,0 +1,200 @@
+package statemachine_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/dmitrymomot/saaskit/pkg/statemachine"
+)
+
+func TestTimedTransition(t *testing.T) {
+	t.Parallel()
+
+	const (
+		Pending = statemachine.StringState("pending_payment")
+		Paid    = statemachine.StringState("paid")
+		Expired = statemachine.StringState("expired")
+	)
+
+	const (
+		Pay    = statemachine.StringEvent("pay")
+		Expire = statemachine.StringEvent("expire")
+		Retry  = statemachine.StringEvent("retry")
+		Reopen = statemachine.StringEvent("reopen")
+	)
+
+	waitForState := func(t *testing.T, sm statemachine.StateMachine, want statemachine.State) {
+		t.Helper()
+		deadline := time.Now().Add(time.Second)
+		for time.Now().Before(deadline) {
+			if sm.Current() == want {
+				return
+			}
+			time.Sleep(time.Millisecond)
+		}
+		t.Fatalf("expected state %s, got %s", want.Name(), sm.Current().Name())
+	}
+
+	t.Run("fires after timeout and runs actions", func(t *testing.T) {
+		t.Parallel()
+		var actionCalls atomic.Int32
+
+		sm := statemachine.MustNew(Pending,
+			statemachine.WithTransition(Pending, Paid, Pay),
+			statemachine.WithTransition(Pending, Expired, Expire,
+				statemachine.WithAction(func(ctx context.Context, from, to statemachine.State, event statemachine.Event, data any) error {
+					actionCalls.Add(1)
+					return nil
+				}),
+			),
+			statemachine.WithTimedTransition(Pending, 10*time.Millisecond, Expire),
+		)
+		defer sm.(statemachine.Stopper).Stop()
+
+		waitForState(t, sm, Expired)
+		if got := actionCalls.Load(); got != 1 {
+			t.Errorf("expected action to run once, ran %d times", got)
+		}
+	})
+
+	t.Run("manual transition cancels timer", func(t *testing.T) {
+		t.Parallel()
+		sm := statemachine.MustNew(Pending,
+			statemachine.WithTransition(Pending, Paid, Pay),
+			statemachine.WithTransition(Pending, Expired, Expire),
+			statemachine.WithTimedTransition(Pending, 20*time.Millisecond, Expire),
+		)
+		defer sm.(statemachine.Stopper).Stop()
+
+		if err := sm.Fire(context.Background(), Pay, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		time.Sleep(50 * time.Millisecond)
+		if sm.Current() != Paid {
+			t.Errorf("expected state to stay paid, got %s", sm.Current().Name())
+		}
+	})
+
+	t.Run("re-entering the state restarts the timer", func(t *testing.T) {
+		t.Parallel()
+		sm := statemachine.MustNew(Pending,
+			statemachine.WithTransition(Pending, Expired, Expire),
+			statemachine.WithTransition(Expired, Pending, Reopen),
+			statemachine.WithTimedTransition(Pending, 10*time.Millisecond, Expire),
+		)
+		defer sm.(statemachine.Stopper).Stop()
+
+		waitForState(t, sm, Expired)
+		if err := sm.Fire(context.Background(), Reopen, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		waitForState(t, sm, Expired)
+	})
+
+	t.Run("self-transition keeps the running timer", func(t *testing.T) {
+		t.Parallel()
+		sm := statemachine.MustNew(Pending,
+			statemachine.WithTransition(Pending, Pending, Retry),
+			statemachine.WithTransition(Pending, Expired, Expire),
+			statemachine.WithTimedTransition(Pending, 30*time.Millisecond, Expire),
+		)
+		defer sm.(statemachine.Stopper).Stop()
+
+		start := time.Now()
+		for range 5 {
+			time.Sleep(5 * time.Millisecond)
+			if err := sm.Fire(context.Background(), Retry, nil); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+		waitForState(t, sm, Expired)
+		if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+			t.Errorf("timer was restarted by self-transitions, expired after %v", elapsed)
+		}
+	})
+
+	t.Run("stop cancels pending timers", func(t *testing.T) {
+		t.Parallel()
+		sm := statemachine.MustNew(Pending,
+			statemachine.WithTransition(Pending, Expired, Expire),
+			statemachine.WithTimedTransition(Pending, 10*time.Millisecond, Expire),
+		)
+		sm.(statemachine.Stopper).Stop()
+
+		time.Sleep(40 * time.Millisecond)
+		if sm.Current() != Pending {
+			t.Errorf("expected state to stay pending after Stop, got %s", sm.Current().Name())
+		}
+	})
+
+	t.Run("reset reschedules initial state timer", func(t *testing.T) {
+		t.Parallel()
+		sm := statemachine.MustNew(Pending,
+			statemachine.WithTransition(Pending, Expired, Expire),
+			statemachine.WithTimedTransition(Pending, 10*time.Millisecond, Expire),
+		)
+		defer sm.(statemachine.Stopper).Stop()
+
+		waitForState(t, sm, Expired)
+		if err := sm.Reset(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		waitForState(t, sm, Expired)
+	})
+
+	t.Run("guard rejection is reported", func(t *testing.T) {
+		t.Parallel()
+		errCh := make(chan error, 1)
+
+		sm := statemachine.MustNew(Pending,
+			statemachine.WithTransition(Pending, Expired, Expire,
+				statemachine.WithGuard(func(ctx context.Context, from statemachine.State, event statemachine.Event, data any) bool {
+					return false
+				}),
+			),
+			statemachine.WithTimedTransition(Pending, 5*time.Millisecond, Expire),
+			statemachine.WithTimedErrorHandler(func(from statemachine.State, event statemachine.Event, err error) {
+				errCh <- err
+			}),
+		)
+		defer sm.(statemachine.Stopper).Stop()
+
+		select {
+		case err := <-errCh:
+			if !statemachine.IsTransitionRejectedError(err) {
+				t.Errorf("expected rejected transition error, got %v", err)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("timed error handler was not called")
+		}
+		if sm.Current() != Pending {
+			t.Errorf("expected state to stay pending, got %s", sm.Current().Name())
+		}
+	})
+
+	t.Run("invalid options", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := statemachine.New(Pending, statemachine.WithTimedTransition(Pending, 0, Expire))
+		if !errors.Is(err, statemachine.ErrInvalidTimeout) {
+			t.Errorf("expected ErrInvalidTimeout, got %v", err)
+		}
+
+		_, err = statemachine.New(Pending, statemachine.WithTimedTransition(nil, time.Second, Expire))
+		if !errors.Is(err, statemachine.ErrInvalidTransition) {
+			t.Errorf("expected ErrInvalidTransition, got %v", err)
+		}
+
+		_, err = statemachine.New(Pending,
+			statemachine.WithTimedTransition(Pending, time.Second, Expire),
+			statemachine.WithTimedTransition(Pending, time.Minute, Expire),
+		)
+		if !errors.Is(err, statemachine.ErrDuplicateTimeout) {
+			t.Errorf("expected ErrDuplicateTimeout, got %v", err)
+		}
+	})
+}
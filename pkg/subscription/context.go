@@ -18,6 +18,19 @@ func GetPlanIDFromContext(ctx context.Context) (string, bool) {
 	return planID, ok
 }
 
+type usageEventIDCtxKey struct{}
+
+// SetUsageEventIDToContext attaches an idempotency key to a RecordUsage call.
+// Usage recorded again with the same event ID for the tenant is ignored.
+func SetUsageEventIDToContext(ctx context.Context, eventID string) context.Context {
+	return context.WithValue(ctx, usageEventIDCtxKey{}, eventID)
+}
+
+func GetUsageEventIDFromContext(ctx context.Context) (string, bool) {
+	eventID, ok := ctx.Value(usageEventIDCtxKey{}).(string)
+	return eventID, ok
+}
+
 // PlanIDContextResolver is the default resolver that retrieves plan ID from context.
 // This resolver allows dynamic plan resolution without database lookups, useful for
 // multi-tenant applications where plan ID is determined during request processing.
@@ -0,0 +1,50 @@
+package subscription
+
+import (
+	"slices"
+	"time"
+)
+
+// CouponType defines how a coupon reduces the price.
+type CouponType string
+
+const (
+	CouponPercentage  CouponType = "percentage"    // Percentage off the price
+	CouponFlat        CouponType = "flat"          // Fixed amount off the total
+	CouponFlatPerSeat CouponType = "flat_per_seat" // Fixed amount off each seat
+)
+
+// CouponInfo describes a valid discount code, so the UI can show the discounted
+// price before redirecting to checkout.
+type CouponInfo struct {
+	Code               string     // discount code as entered by the customer
+	ID                 string     // provider's discount identifier
+	Type               CouponType // how the discount is applied
+	Percentage         float64    // percent off (0.01-100) for CouponPercentage
+	Amount             Money      // amount off for CouponFlat and CouponFlatPerSeat
+	Recurring          bool       // applies to renewals, not only the first payment
+	RecurringIntervals int        // number of billing periods when Recurring, 0 means forever
+	ExpiresAt          time.Time  // zero if the coupon never expires
+	RestrictTo         []string   // provider price or product IDs, empty if it applies to all
+}
+
+// AppliesTo reports whether the coupon can be used with the given provider ID.
+// Coupons without restrictions apply to everything.
+func (c *CouponInfo) AppliesTo(id string) bool {
+	return len(c.RestrictTo) == 0 || slices.Contains(c.RestrictTo, id)
+}
+
+// Apply returns the price after the discount, never below zero.
+// Flat discounts in another currency don't apply and return the price unchanged.
+func (c *CouponInfo) Apply(price Money) Money {
+	switch c.Type {
+	case CouponPercentage:
+		off := int64(float64(price.Amount) * min(c.Percentage, 100) / 100)
+		price.Amount -= off
+	case CouponFlat, CouponFlatPerSeat:
+		if c.Amount.Currency == price.Currency {
+			price.Amount = max(price.Amount-c.Amount.Amount, 0)
+		}
+	}
+	return price
+}
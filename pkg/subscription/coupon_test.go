@@ -0,0 +1,120 @@
+package subscription_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dmitrymomot/saaskit/pkg/subscription"
+)
+
+func TestCouponInfo_Apply(t *testing.T) {
+	t.Parallel()
+
+	price := subscription.Money{Amount: 2000, Currency: "USD"}
+
+	tests := []struct {
+		name   string
+		coupon subscription.CouponInfo
+		want   int64
+	}{
+		{"percentage", subscription.CouponInfo{Type: subscription.CouponPercentage, Percentage: 25}, 1500},
+		{"fractional percentage", subscription.CouponInfo{Type: subscription.CouponPercentage, Percentage: 12.5}, 1750},
+		{"percentage capped at 100", subscription.CouponInfo{Type: subscription.CouponPercentage, Percentage: 150}, 0},
+		{"flat", subscription.CouponInfo{Type: subscription.CouponFlat, Amount: subscription.Money{Amount: 500, Currency: "USD"}}, 1500},
+		{"flat never below zero", subscription.CouponInfo{Type: subscription.CouponFlatPerSeat, Amount: subscription.Money{Amount: 5000, Currency: "USD"}}, 0},
+		{"flat in other currency", subscription.CouponInfo{Type: subscription.CouponFlat, Amount: subscription.Money{Amount: 500, Currency: "EUR"}}, 2000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := tt.coupon.Apply(price)
+			assert.Equal(t, tt.want, got.Amount)
+			assert.Equal(t, "USD", got.Currency)
+		})
+	}
+}
+
+func TestCouponInfo_AppliesTo(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, (&subscription.CouponInfo{}).AppliesTo("basic"))
+
+	restricted := &subscription.CouponInfo{RestrictTo: []string{"pro"}}
+	assert.True(t, restricted.AppliesTo("pro"))
+	assert.False(t, restricted.AppliesTo("basic"))
+}
+
+func TestService_ValidateCoupon(t *testing.T) {
+	t.Parallel()
+
+	newService := func(t *testing.T, provider *mockProvider) subscription.Service {
+		t.Helper()
+		src := &mockPlansSource{}
+		src.On("Load", mock.Anything).Return(createTestPlans(), nil)
+		svc, err := subscription.NewService(context.Background(), src, provider, &mockStore{})
+		require.NoError(t, err)
+		return svc
+	}
+
+	t.Run("returns coupon from provider", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		provider := &mockProvider{}
+		coupon := &subscription.CouponInfo{Code: "LAUNCH20", ID: "dsc_1", Type: subscription.CouponPercentage, Percentage: 20}
+		provider.On("ValidateCoupon", ctx, "LAUNCH20").Return(coupon, nil)
+
+		got, err := newService(t, provider).ValidateCoupon(ctx, " LAUNCH20 ")
+		require.NoError(t, err)
+		assert.Equal(t, coupon, got)
+		provider.AssertExpectations(t)
+	})
+
+	t.Run("propagates typed provider errors", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		provider := &mockProvider{}
+		provider.On("ValidateCoupon", ctx, "OLD").Return(nil, subscription.ErrCouponExpired)
+
+		_, err := newService(t, provider).ValidateCoupon(ctx, "OLD")
+		assert.ErrorIs(t, err, subscription.ErrCouponExpired)
+	})
+
+	t.Run("empty code is invalid", func(t *testing.T) {
+		t.Parallel()
+		provider := &mockProvider{}
+
+		_, err := newService(t, provider).ValidateCoupon(context.Background(), "  ")
+		assert.ErrorIs(t, err, subscription.ErrInvalidCoupon)
+		provider.AssertNotCalled(t, "ValidateCoupon", mock.Anything, mock.Anything)
+	})
+}
+
+func TestService_CreateCheckoutLink_DiscountCode(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	tenantID := uuid.New()
+
+	src := &mockPlansSource{}
+	provider := &mockProvider{}
+	store := &mockStore{}
+
+	src.On("Load", mock.Anything).Return(createTestPlans(), nil)
+	store.On("Get", ctx, tenantID).Return(nil, subscription.ErrSubscriptionNotFound)
+	provider.On("CreateCheckoutLink", ctx, mock.MatchedBy(func(req subscription.CheckoutRequest) bool {
+		return req.PriceID == "basic" && req.DiscountCode == "LAUNCH20"
+	})).Return(nil, subscription.ErrInvalidCoupon)
+
+	svc, err := subscription.NewService(ctx, src, provider, store)
+	require.NoError(t, err)
+
+	_, err = svc.CreateCheckoutLink(ctx, tenantID, "basic", subscription.CheckoutOptions{DiscountCode: "LAUNCH20"})
+	assert.ErrorIs(t, err, subscription.ErrInvalidCoupon)
+	provider.AssertExpectations(t)
+}
@@ -113,6 +113,22 @@
 //	// Use in service creation
 //	svc, err := subscription.NewService(ctx, planSource, provider, store)
 //
+// # Stripe Integration
+//
+// StripeProvider uses Checkout Sessions for payment and the Billing customer portal
+// for self-service. The tenant ID is stored in the subscription metadata, and webhooks
+// are verified with the Stripe-Signature header and a timestamp tolerance:
+//
+//	provider, err := subscription.NewStripeProvider(subscription.StripeConfig{
+//		SecretKey:       "sk_live_...",
+//		WebhookSecret:   "whsec_...",
+//		PortalReturnURL: "https://app.example.com/billing",
+//	})
+//
+// Plan IDs must be Stripe price IDs (price_xxx). Subscribe the webhook endpoint to
+// customer.subscription.created, customer.subscription.updated,
+// customer.subscription.deleted, invoice.paid and invoice.payment_failed.
+//
 // # Resource Management
 //
 // Enforce resource limits before allowing resource creation:
@@ -138,6 +154,15 @@
 //	percentage := svc.GetUsagePercentage(ctx, tenantID, subscription.ResourceStorage)
 //	// Returns 0-100 for normal limits, -1 for unlimited
 //
+// Plans can define a SoftLimitThreshold per resource to warn users before the
+// hard limit is enforced. CheckSoftLimit reports whether usage reached it, and
+// WithOnSoftLimit registers a hook that CanCreate fires when it has:
+//
+//	status, err := svc.CheckSoftLimit(ctx, tenantID, subscription.ResourceProjects)
+//	if status.Exceeded {
+//		// Show upgrade nudge with status.Percentage
+//	}
+//
 // Counter functions must be fast as they're called frequently. Consider:
 //   - Database indexes on tenant_id columns
 //   - Cached counts with periodic refresh
@@ -167,6 +192,26 @@
 //   - FeatureAnalytics: Advanced analytics
 //   - And more...
 //
+// # Edge Entitlements
+//
+// For edge workers and CDN functions that can't call the Service per request,
+// issue a signed entitlement snapshot and verify it offline. Snapshots are signed
+// with Ed25519: the private key stays with the issuing service, and edges only get
+// the public key, so they can't mint snapshots:
+//
+//	publicKey, privateKey, _ := ed25519.GenerateKey(rand.Reader) // or load from secrets
+//	svc, _ := subscription.NewService(ctx, src, provider, store,
+//		subscription.WithEntitlementKey(privateKey, 15*time.Minute),
+//	)
+//
+//	e, err := svc.Entitlements(ctx, tenantID)
+//	// push e.Token to the edge cache, refresh before e.ExpiresAt
+//
+//	// At the edge, no storage access:
+//	if err := subscription.VerifyEntitlement(publicKey, token, subscription.FeatureAPI); err != nil {
+//		// ErrEntitlementExpired, ErrInvalidEntitlement or ErrFeatureNotEntitled
+//	}
+//
 // # Plan Management
 //
 // Set plan context for dynamic plan resolution:
@@ -190,6 +235,17 @@
 //		subscription.WithPlanIDResolver(dbResolver),
 //	)
 //
+// Before switching to a smaller plan, CanDowngrade compares every registered counter
+// with the target plan's limits. The returned *DowngradeError lists each resource over
+// its limit:
+//
+//	var downgradeErr *subscription.DowngradeError
+//	if errors.As(svc.CanDowngrade(ctx, tenantID, "basic"), &downgradeErr) {
+//		for _, o := range downgradeErr.Overages {
+//			fmt.Printf("delete %d %s\n", o.Excess(), o.Resource)
+//		}
+//	}
+//
 // # Checkout and Billing
 //
 // Create checkout sessions for plan upgrades:
@@ -220,6 +276,24 @@
 //
 // Free plans bypass payment processing and activate immediately.
 //
+// Discount codes are validated with ValidateCoupon and pre-applied by setting
+// CheckoutOptions.DiscountCode. Invalid or expired codes return ErrInvalidCoupon
+// or ErrCouponExpired rather than being ignored:
+//
+//	coupon, err := svc.ValidateCoupon(ctx, code)
+//	if err != nil {
+//		// Show why the code can't be used
+//	}
+//	price := coupon.Apply(plan.Price) // discounted price to display
+//
+// PreviewPlanChange shows what a plan change costs before the customer confirms it.
+// Paid subscriptions are previewed by the provider; free-to-paid changes have a zero
+// immediate charge. Providers that don't implement ProrationPreviewer return
+// ErrProrationNotSupported:
+//
+//	preview, err := svc.PreviewPlanChange(ctx, tenantID, "price_pro_monthly")
+//	// preview.ImmediateCharge, preview.NextRenewal, preview.EffectiveAt
+//
 // # Webhook Processing
 //
 // Process billing provider webhooks to sync subscription state:
@@ -241,6 +315,14 @@
 // Webhook events automatically update subscription status, plan changes,
 // and trial states in your SubscriptionStore implementation.
 //
+// Providers redeliver webhooks, so duplicates can re-apply plan changes. WithWebhookDedup
+// records each provider event ID before processing and skips IDs it has already seen.
+// IDs of events that fail to apply are forgotten, so the provider's retry still lands:
+//
+//	svc, err := subscription.NewService(ctx, plansSource, provider, store,
+//		subscription.WithWebhookDedup(subscription.NewInMemWebhookDedupStore(0)),
+//	)
+//
 // # Trial Management
 //
 // Plans can include trial periods that are automatically managed:
@@ -295,6 +377,38 @@
 //		redirectToPlans()
 //	}
 //
+// # Metered Billing
+//
+// Resources listed in Plan.MeteredResources are billed by recorded usage instead of
+// counted with a ResourceCounterFunc. Usage is stored per billing period in a UsageStore
+// registered with WithUsageStore; GetUsage returns the current period's total:
+//
+//	ctx = subscription.SetUsageEventIDToContext(ctx, requestID) // idempotent retries
+//	err := svc.RecordUsage(ctx, tenantID, "api_calls", 1)
+//
+// After a period closes, ReportUsageToProvider sends its totals to the billing provider
+// with an idempotency key. Providers that don't implement UsageReporter return
+// ErrUsageReportingNotSupported.
+//
+// # Grace Period
+//
+// WithGracePeriod keeps failed-payment subscriptions usable for a while instead of
+// cutting them off. A payment failure moves the subscription to StatusPastDue and sets
+// GracePeriodEndsAt; CanCreate, HasFeature and GetUsage keep working, and
+// Subscription.IsPastDue tells dashboards to show a warning.
+// Cancellation events are deferred until the period ends, and the subscription is
+// cancelled by the next webhook or GetSubscription call after that:
+//
+//	svc, err := subscription.NewService(ctx, src, provider, store,
+//		subscription.WithGracePeriod(7*24*time.Hour),
+//	)
+//
+//	if sub.IsPastDue() {
+//		showPaymentBanner(sub.DaysUntilCancellation())
+//	}
+//
+// A successful payment returns the subscription to StatusActive.
+//
 // # Performance Considerations
 //
 // For optimal performance:
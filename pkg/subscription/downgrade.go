@@ -0,0 +1,38 @@
+package subscription
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ResourceOverage describes a resource whose usage exceeds a plan limit.
+type ResourceOverage struct {
+	Resource Resource
+	Current  int64 // current usage
+	Limit    int64 // limit on the target plan
+}
+
+// Excess returns how many items must be removed to fit the limit.
+func (o ResourceOverage) Excess() int64 {
+	return o.Current - o.Limit
+}
+
+// DowngradeError is returned by CanDowngrade when usage exceeds the target plan's limits.
+// It matches ErrDowngradeNotPossible with errors.Is; use errors.As to show the user
+// what to delete before downgrading.
+type DowngradeError struct {
+	PlanID   string            // target plan
+	Overages []ResourceOverage // sorted by resource
+}
+
+func (e *DowngradeError) Error() string {
+	parts := make([]string, len(e.Overages))
+	for i, o := range e.Overages {
+		parts[i] = fmt.Sprintf("%s %d/%d", o.Resource, o.Current, o.Limit)
+	}
+	return fmt.Sprintf("%s: %s exceeds plan %s limits", ErrDowngradeNotPossible, strings.Join(parts, ", "), e.PlanID)
+}
+
+func (e *DowngradeError) Unwrap() error {
+	return ErrDowngradeNotPossible
+}
@@ -0,0 +1,197 @@
+package subscription
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/dmitrymomot/saaskit/pkg/jwt"
+)
+
+// DefaultEntitlementTTL is how long an entitlement snapshot stays valid when no TTL is configured.
+const DefaultEntitlementTTL = 15 * time.Minute
+
+// entitlementHeader is the JOSE header of every snapshot. Snapshots are signed with
+// Ed25519, so edge verifiers only hold the public key and can't mint snapshots.
+const entitlementHeader = `{"alg":"EdDSA","typ":"JWT"}`
+
+// Entitlement is a compact, signed snapshot of a tenant's plan features and limits.
+// Token carries the same data as an EdDSA-signed JWT and is what should be
+// pushed to edge caches; the remaining fields are provided for convenience.
+type Entitlement struct {
+	TenantID  uuid.UUID          `json:"tenant_id"`
+	PlanID    string             `json:"plan_id"`
+	Features  []Feature          `json:"features,omitempty"`
+	Limits    map[Resource]int64 `json:"limits,omitempty"`
+	IssuedAt  time.Time          `json:"issued_at"`
+	ExpiresAt time.Time          `json:"expires_at"`
+	Token     string             `json:"token"`
+}
+
+// HasFeature reports whether the snapshot includes the feature.
+func (e *Entitlement) HasFeature(feature Feature) bool {
+	return e != nil && slices.Contains(e.Features, feature)
+}
+
+// Limit returns the resource limit captured in the snapshot.
+func (e *Entitlement) Limit(res Resource) (int64, bool) {
+	if e == nil {
+		return 0, false
+	}
+	limit, ok := e.Limits[res]
+	return limit, ok
+}
+
+// entitlementClaims is the signed JWT representation of an Entitlement.
+// Subject holds the tenant ID.
+type entitlementClaims struct {
+	jwt.StandardClaims
+	PlanID   string             `json:"pid"`
+	Features []Feature          `json:"fts,omitempty"`
+	Limits   map[Resource]int64 `json:"lim,omitempty"`
+}
+
+// Entitlements returns a signed snapshot of the tenant's current plan that can be
+// verified offline with VerifyEntitlement or ParseEntitlement.
+func (s *service) Entitlements(ctx context.Context, tenantID uuid.UUID) (*Entitlement, error) {
+	if len(s.entitlementKey) != ed25519.PrivateKeySize {
+		return nil, ErrEntitlementSignerNotConfigured
+	}
+
+	planID, err := s.planIDResolver(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	plan, exists := s.plans[planID]
+	if !exists {
+		return nil, ErrPlanNotFound
+	}
+
+	now := time.Now().UTC()
+	e := &Entitlement{
+		TenantID:  tenantID,
+		PlanID:    planID,
+		Features:  slices.Clone(plan.Features),
+		Limits:    maps.Clone(plan.Limits),
+		IssuedAt:  now,
+		ExpiresAt: now.Add(s.entitlementTTL),
+	}
+
+	token, err := signEntitlement(s.entitlementKey, entitlementClaims{
+		StandardClaims: jwt.StandardClaims{
+			Subject:   tenantID.String(),
+			IssuedAt:  e.IssuedAt.Unix(),
+			ExpiresAt: e.ExpiresAt.Unix(),
+		},
+		PlanID:   e.PlanID,
+		Features: e.Features,
+		Limits:   e.Limits,
+	})
+	if err != nil {
+		return nil, errors.Join(ErrFailedToSignEntitlement, err)
+	}
+	e.Token = token
+
+	return e, nil
+}
+
+// ParseEntitlement validates the snapshot signature and expiry without touching storage.
+// publicKey is the public half of the key passed to WithEntitlementKey.
+func ParseEntitlement(publicKey ed25519.PublicKey, snapshot string) (*Entitlement, error) {
+	if len(publicKey) != ed25519.PublicKeySize {
+		return nil, ErrEntitlementSignerNotConfigured
+	}
+
+	var claims entitlementClaims
+	if err := verifyEntitlement(publicKey, snapshot, &claims); err != nil {
+		if errors.Is(err, jwt.ErrExpiredToken) {
+			return nil, ErrEntitlementExpired
+		}
+		return nil, errors.Join(ErrInvalidEntitlement, err)
+	}
+
+	tenantID, err := uuid.Parse(claims.Subject)
+	if err != nil || claims.ExpiresAt == 0 {
+		return nil, ErrInvalidEntitlement
+	}
+
+	return &Entitlement{
+		TenantID:  tenantID,
+		PlanID:    claims.PlanID,
+		Features:  claims.Features,
+		Limits:    claims.Limits,
+		IssuedAt:  time.Unix(claims.IssuedAt, 0).UTC(),
+		ExpiresAt: time.Unix(claims.ExpiresAt, 0).UTC(),
+		Token:     snapshot,
+	}, nil
+}
+
+// VerifyEntitlement checks that the snapshot is authentic, unexpired and grants the feature.
+// Intended for edge workers that can't call the Service per request.
+func VerifyEntitlement(publicKey ed25519.PublicKey, snapshot string, feature Feature) error {
+	e, err := ParseEntitlement(publicKey, snapshot)
+	if err != nil {
+		return err
+	}
+
+	if !e.HasFeature(feature) {
+		return ErrFeatureNotEntitled
+	}
+
+	return nil
+}
+
+// signEntitlement encodes claims as a compact JWT signed with Ed25519 (RFC 8037).
+func signEntitlement(key ed25519.PrivateKey, claims entitlementClaims) (string, error) {
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	signingInput := base64.RawURLEncoding.EncodeToString([]byte(entitlementHeader)) + "." +
+		base64.RawURLEncoding.EncodeToString(payload)
+	sig := ed25519.Sign(key, []byte(signingInput))
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(sig), nil
+}
+
+// verifyEntitlement checks the EdDSA signature of a compact JWT and decodes its claims.
+// Only the EdDSA algorithm is accepted, so an HMAC token can't be passed off as a snapshot.
+func verifyEntitlement(key ed25519.PublicKey, snapshot string, claims *entitlementClaims) error {
+	parts := strings.Split(snapshot, ".")
+	if len(parts) != 3 {
+		return jwt.ErrInvalidToken
+	}
+
+	headerJSON, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return jwt.ErrInvalidToken
+	}
+	var header jwt.Header
+	if err := json.Unmarshal(headerJSON, &header); err != nil || header.Algorithm != "EdDSA" {
+		return jwt.ErrUnexpectedSigningMethod
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil || !ed25519.Verify(key, []byte(parts[0]+"."+parts[1]), sig) {
+		return jwt.ErrInvalidSignature
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return jwt.ErrInvalidToken
+	}
+	if err := json.Unmarshal(payload, claims); err != nil {
+		return jwt.ErrInvalidToken
+	}
+
+	return claims.Valid()
+}
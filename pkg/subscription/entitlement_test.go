@@ -0,0 +1,150 @@
+package subscription_test
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dmitrymomot/saaskit/pkg/jwt"
+	"github.com/dmitrymomot/saaskit/pkg/subscription"
+)
+
+func newEntitlementKey(t *testing.T) (ed25519.PublicKey, ed25519.PrivateKey) {
+	t.Helper()
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	return pub, priv
+}
+
+func newEntitlementService(t *testing.T, key ed25519.PrivateKey, ttl time.Duration) subscription.Service {
+	t.Helper()
+
+	plans := createTestPlans()
+	src := subscription.NewInMemSource(plans["free"], plans["basic"], plans["pro"])
+
+	var opts []subscription.ServiceOption
+	if key != nil {
+		opts = append(opts, subscription.WithEntitlementKey(key, ttl))
+	}
+
+	svc, err := subscription.NewService(context.Background(), src, &mockProvider{}, &mockStore{}, opts...)
+	require.NoError(t, err)
+	return svc
+}
+
+func TestEntitlements(t *testing.T) {
+	t.Parallel()
+
+	publicKey, key := newEntitlementKey(t)
+
+	t.Run("issues signed snapshot of tenant plan", func(t *testing.T) {
+		t.Parallel()
+		svc := newEntitlementService(t, key, time.Hour)
+		tenantID := uuid.New()
+		ctx := subscription.SetPlanIDToContext(context.Background(), "pro")
+
+		e, err := svc.Entitlements(ctx, tenantID)
+		require.NoError(t, err)
+		assert.Equal(t, tenantID, e.TenantID)
+		assert.Equal(t, "pro", e.PlanID)
+		assert.True(t, e.HasFeature(subscription.FeatureSSO))
+		assert.NotEmpty(t, e.Token)
+		assert.WithinDuration(t, time.Now().Add(time.Hour), e.ExpiresAt, time.Minute)
+
+		limit, ok := e.Limit(subscription.ResourceTeamMembers)
+		assert.True(t, ok)
+		assert.Equal(t, subscription.Unlimited, limit)
+
+		parsed, err := subscription.ParseEntitlement(publicKey, e.Token)
+		require.NoError(t, err)
+		assert.Equal(t, e.TenantID, parsed.TenantID)
+		assert.Equal(t, e.PlanID, parsed.PlanID)
+		assert.ElementsMatch(t, e.Features, parsed.Features)
+		assert.Equal(t, e.Limits, parsed.Limits)
+	})
+
+	t.Run("requires key", func(t *testing.T) {
+		t.Parallel()
+		svc := newEntitlementService(t, nil, 0)
+		ctx := subscription.SetPlanIDToContext(context.Background(), "pro")
+
+		_, err := svc.Entitlements(ctx, uuid.New())
+		assert.ErrorIs(t, err, subscription.ErrEntitlementSignerNotConfigured)
+	})
+
+	t.Run("unknown plan", func(t *testing.T) {
+		t.Parallel()
+		svc := newEntitlementService(t, key, time.Hour)
+		ctx := subscription.SetPlanIDToContext(context.Background(), "enterprise")
+
+		_, err := svc.Entitlements(ctx, uuid.New())
+		assert.ErrorIs(t, err, subscription.ErrPlanNotFound)
+	})
+}
+
+func TestVerifyEntitlement(t *testing.T) {
+	t.Parallel()
+
+	publicKey, key := newEntitlementKey(t)
+	ctx := subscription.SetPlanIDToContext(context.Background(), "basic")
+
+	t.Run("grants included feature", func(t *testing.T) {
+		t.Parallel()
+		e, err := newEntitlementService(t, key, time.Hour).Entitlements(ctx, uuid.New())
+		require.NoError(t, err)
+
+		assert.NoError(t, subscription.VerifyEntitlement(publicKey, e.Token, subscription.FeatureAPI))
+		assert.ErrorIs(t, subscription.VerifyEntitlement(publicKey, e.Token, subscription.FeatureSSO), subscription.ErrFeatureNotEntitled)
+	})
+
+	t.Run("rejects expired snapshot", func(t *testing.T) {
+		t.Parallel()
+		svc := newEntitlementService(t, key, time.Nanosecond)
+		e, err := svc.Entitlements(ctx, uuid.New())
+		require.NoError(t, err)
+
+		time.Sleep(1100 * time.Millisecond)
+		assert.ErrorIs(t, subscription.VerifyEntitlement(publicKey, e.Token, subscription.FeatureAPI), subscription.ErrEntitlementExpired)
+	})
+
+	t.Run("rejects snapshot signed with another key", func(t *testing.T) {
+		t.Parallel()
+		_, other := newEntitlementKey(t)
+		e, err := newEntitlementService(t, other, time.Hour).Entitlements(ctx, uuid.New())
+		require.NoError(t, err)
+
+		assert.ErrorIs(t, subscription.VerifyEntitlement(publicKey, e.Token, subscription.FeatureAPI), subscription.ErrInvalidEntitlement)
+	})
+
+	t.Run("rejects tampered snapshot", func(t *testing.T) {
+		t.Parallel()
+		assert.ErrorIs(t, subscription.VerifyEntitlement(publicKey, "not.a.token", subscription.FeatureAPI), subscription.ErrInvalidEntitlement)
+	})
+
+	t.Run("rejects HMAC tokens", func(t *testing.T) {
+		t.Parallel()
+		hmac, err := jwt.NewFromString("entitlement-signing-key-32-chars-long")
+		require.NoError(t, err)
+		token, err := hmac.Generate(jwt.StandardClaims{
+			Subject:   uuid.NewString(),
+			ExpiresAt: time.Now().Add(time.Hour).Unix(),
+		})
+		require.NoError(t, err)
+
+		assert.ErrorIs(t, subscription.VerifyEntitlement(publicKey, token, subscription.FeatureAPI), subscription.ErrInvalidEntitlement)
+	})
+
+	t.Run("requires public key", func(t *testing.T) {
+		t.Parallel()
+		e, err := newEntitlementService(t, key, time.Hour).Entitlements(ctx, uuid.New())
+		require.NoError(t, err)
+
+		assert.ErrorIs(t, subscription.VerifyEntitlement(nil, e.Token, subscription.FeatureAPI), subscription.ErrEntitlementSignerNotConfigured)
+	})
+}
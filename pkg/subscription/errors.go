@@ -36,11 +36,31 @@ var (
 	ErrMissingTenantID            = errors.New("tenant ID is required")
 	ErrMissingPriceID             = errors.New("price ID is required")
 
+	// Coupon errors
+	ErrInvalidCoupon          = errors.New("invalid coupon code")
+	ErrCouponExpired          = errors.New("coupon code has expired")
+	ErrCouponNotApplicable    = errors.New("coupon code does not apply to this plan")
+	ErrFailedToValidateCoupon = errors.New("failed to validate coupon code")
+
+	// Metered usage errors
+	ErrUsageStoreNotConfigured    = errors.New("usage store not configured")
+	ErrResourceNotMetered         = errors.New("resource is not metered on this plan")
+	ErrInvalidUsageQuantity       = errors.New("usage quantity must be positive")
+	ErrUsageReportingNotSupported = errors.New("billing provider does not support usage reporting")
+	ErrFailedToRecordUsage        = errors.New("failed to record usage")
+	ErrFailedToReportUsage        = errors.New("failed to report usage to billing provider")
+
+	// Proration errors
+	ErrProrationNotSupported    = errors.New("billing provider does not support proration previews")
+	ErrAlreadyOnPlan            = errors.New("subscription is already on this plan")
+	ErrFailedToPreviewProration = errors.New("failed to preview plan change")
+
 	// Webhook processing errors
 	ErrMissingTenantIDInWebhook = errors.New("missing tenant ID in webhook event")
 	ErrWebhookVerification      = errors.New("webhook verification error")
 	ErrFailedToReadRequestBody  = errors.New("failed to read request body")
 	ErrFailedToParseWebhook     = errors.New("failed to parse webhook payload")
+	ErrFailedToCheckWebhookDup  = errors.New("failed to check webhook event for duplicates")
 
 	// Provider operation errors
 	ErrFailedToCreatePaddleClient  = errors.New("failed to create paddle client")
@@ -48,13 +68,24 @@ var (
 	ErrFailedToCreatePortalSession = errors.New("failed to create paddle customer portal session")
 	ErrNoPortalForFreePlan         = errors.New("no customer portal available for free plans")
 
+	ErrFailedToCreateCheckoutSession      = errors.New("failed to create stripe checkout session")
+	ErrFailedToCreateBillingPortalSession = errors.New("failed to create stripe billing portal session")
+
 	// Subscription operation errors
 	ErrFailedToSaveSubscription         = errors.New("failed to save subscription")
 	ErrFailedToUpdateSubscription       = errors.New("failed to update subscription")
 	ErrFailedToCancelSubscription       = errors.New("failed to cancel subscription")
 	ErrFailedToUpdateSubscriptionStatus = errors.New("failed to update subscription status")
 
+	// Entitlement errors
+	ErrEntitlementSignerNotConfigured = errors.New("entitlement signer not configured")
+	ErrFailedToSignEntitlement        = errors.New("failed to sign entitlement snapshot")
+	ErrInvalidEntitlement             = errors.New("invalid entitlement snapshot")
+	ErrEntitlementExpired             = errors.New("entitlement snapshot has expired")
+	ErrFeatureNotEntitled             = errors.New("feature not included in entitlement")
+
 	// Configuration errors
 	ErrPlanIDMismatch    = errors.New("plan ID mismatch in configuration")
 	ErrNegativeTrialDays = errors.New("plan has negative trial days")
+	ErrInvalidSoftLimit  = errors.New("plan has invalid soft limit threshold")
 )
@@ -0,0 +1,139 @@
+package subscription_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dmitrymomot/saaskit/pkg/subscription"
+)
+
+func TestService_GracePeriod(t *testing.T) {
+	t.Parallel()
+
+	const grace = 7 * 24 * time.Hour
+
+	newService := func(t *testing.T, provider *mockProvider, store *mockStore) subscription.Service {
+		t.Helper()
+		src := &mockPlansSource{}
+		src.On("Load", mock.Anything).Return(createTestPlans(), nil)
+		svc, err := subscription.NewService(context.Background(), src, provider, store,
+			subscription.WithGracePeriod(grace),
+			subscription.WithPlanIDResolver(func(context.Context, uuid.UUID) (string, error) { return "pro", nil }),
+			subscription.WithCounter(subscription.ResourceProjects, func(context.Context, uuid.UUID) (int64, error) { return 3, nil }),
+		)
+		require.NoError(t, err)
+		return svc
+	}
+
+	webhook := func(provider *mockProvider, event *subscription.WebhookEvent) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/webhook", bytes.NewReader([]byte(`{}`)))
+		provider.On("ParseWebhook", req).Return(event, nil).Once()
+		return req
+	}
+
+	t.Run("payment failure starts the grace period", func(t *testing.T) {
+		t.Parallel()
+		tenantID := uuid.New()
+		sub := &subscription.Subscription{TenantID: tenantID, PlanID: "pro", Status: subscription.StatusActive, ProviderSubID: "sub_123"}
+
+		provider := &mockProvider{}
+		store := &mockStore{}
+		store.On("Get", mock.Anything, tenantID).Return(sub, nil)
+		store.On("Save", mock.Anything, sub).Return(nil)
+		svc := newService(t, provider, store)
+
+		req := webhook(provider, &subscription.WebhookEvent{Type: subscription.EventPaymentFailed, TenantID: tenantID})
+		require.NoError(t, svc.HandleWebhook(req))
+
+		assert.True(t, sub.IsPastDue())
+		require.NotNil(t, sub.GracePeriodEndsAt)
+		assert.WithinDuration(t, time.Now().Add(grace), *sub.GracePeriodEndsAt, time.Minute)
+		assert.Equal(t, 7, sub.DaysUntilCancellation())
+		deadline := *sub.GracePeriodEndsAt
+
+		// Retries don't extend the deadline
+		req = webhook(provider, &subscription.WebhookEvent{Type: subscription.EventPaymentFailed, TenantID: tenantID})
+		require.NoError(t, svc.HandleWebhook(req))
+		assert.Equal(t, deadline, *sub.GracePeriodEndsAt)
+
+		// Access and usage are unaffected
+		ctx := context.Background()
+		assert.NoError(t, svc.CanCreate(ctx, tenantID, subscription.ResourceProjects))
+		assert.True(t, svc.HasFeature(ctx, tenantID, subscription.FeatureSSO))
+
+		used, limit, err := svc.GetUsage(ctx, tenantID, subscription.ResourceProjects)
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), used)
+		assert.Equal(t, int64(50), limit)
+		assert.Equal(t, 6, svc.GetUsagePercentage(ctx, tenantID, subscription.ResourceProjects))
+	})
+
+	t.Run("cancellation is deferred during the grace period", func(t *testing.T) {
+		t.Parallel()
+		tenantID := uuid.New()
+		endsAt := time.Now().Add(time.Hour)
+		sub := &subscription.Subscription{TenantID: tenantID, PlanID: "pro", Status: subscription.StatusPastDue, GracePeriodEndsAt: &endsAt}
+
+		provider := &mockProvider{}
+		store := &mockStore{}
+		store.On("Get", mock.Anything, tenantID).Return(sub, nil)
+		svc := newService(t, provider, store)
+
+		req := webhook(provider, &subscription.WebhookEvent{Type: subscription.EventSubscriptionCancelled, TenantID: tenantID})
+		require.NoError(t, svc.HandleWebhook(req))
+
+		assert.True(t, sub.IsPastDue())
+		assert.Nil(t, sub.CancelledAt)
+		store.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+	})
+
+	t.Run("expired grace period cancels the subscription", func(t *testing.T) {
+		t.Parallel()
+		tenantID := uuid.New()
+		endsAt := time.Now().Add(-time.Minute)
+		sub := &subscription.Subscription{TenantID: tenantID, PlanID: "pro", Status: subscription.StatusPastDue, GracePeriodEndsAt: &endsAt}
+
+		store := &mockStore{}
+		store.On("Get", mock.Anything, tenantID).Return(sub, nil)
+		store.On("Save", mock.Anything, mock.MatchedBy(func(s *subscription.Subscription) bool {
+			return s.Status == subscription.StatusCancelled && s.CancelledAt != nil
+		})).Return(nil).Once()
+		svc := newService(t, &mockProvider{}, store)
+
+		got, err := svc.GetSubscription(context.Background(), tenantID)
+		require.NoError(t, err)
+		assert.True(t, got.IsCancelled())
+		store.AssertExpectations(t)
+	})
+
+	t.Run("successful payment restores the subscription", func(t *testing.T) {
+		t.Parallel()
+		tenantID := uuid.New()
+		endsAt := time.Now().Add(time.Hour)
+		sub := &subscription.Subscription{TenantID: tenantID, PlanID: "pro", Status: subscription.StatusPastDue, GracePeriodEndsAt: &endsAt}
+
+		provider := &mockProvider{}
+		store := &mockStore{}
+		store.On("Get", mock.Anything, tenantID).Return(sub, nil)
+		store.On("Save", mock.Anything, sub).Return(nil).Once()
+		svc := newService(t, provider, store)
+
+		req := webhook(provider, &subscription.WebhookEvent{Type: subscription.EventPaymentSucceeded, TenantID: tenantID})
+		require.NoError(t, svc.HandleWebhook(req))
+
+		assert.True(t, sub.IsActive())
+		assert.Nil(t, sub.GracePeriodEndsAt)
+
+		_, _, err := svc.GetUsage(context.Background(), tenantID, subscription.ResourceProjects)
+		assert.NoError(t, err)
+	})
+}
@@ -23,15 +23,17 @@ func NewInMemSource(plans ...Plan) PlansListSource {
 	plansCopy := make(map[string]Plan, plansLen)
 	for _, plan := range plans {
 		plansCopy[plan.ID] = Plan{
-			ID:          plan.ID,
-			Name:        plan.Name,
-			Description: plan.Description,
-			Limits:      maps.Clone(plan.Limits),
-			Features:    slices.Clone(plan.Features),
-			Public:      plan.Public,
-			TrialDays:   plan.TrialDays,
-			Price:       plan.Price,
-			Interval:    plan.Interval,
+			ID:                 plan.ID,
+			Name:               plan.Name,
+			Description:        plan.Description,
+			Limits:             maps.Clone(plan.Limits),
+			Features:           slices.Clone(plan.Features),
+			Public:             plan.Public,
+			TrialDays:          plan.TrialDays,
+			Price:              plan.Price,
+			Interval:           plan.Interval,
+			SoftLimitThreshold: maps.Clone(plan.SoftLimitThreshold),
+			MeteredResources:   slices.Clone(plan.MeteredResources),
 		}
 	}
 
@@ -49,15 +51,17 @@ func (s *inMemSource) Load(ctx context.Context) (map[string]Plan, error) {
 	plansCopy := make(map[string]Plan, len(s.plans))
 	for id, plan := range s.plans {
 		plansCopy[id] = Plan{
-			ID:          plan.ID,
-			Name:        plan.Name,
-			Description: plan.Description,
-			Limits:      maps.Clone(plan.Limits),
-			Features:    slices.Clone(plan.Features),
-			Public:      plan.Public,
-			TrialDays:   plan.TrialDays,
-			Price:       plan.Price,
-			Interval:    plan.Interval,
+			ID:                 plan.ID,
+			Name:               plan.Name,
+			Description:        plan.Description,
+			Limits:             maps.Clone(plan.Limits),
+			Features:           slices.Clone(plan.Features),
+			Public:             plan.Public,
+			TrialDays:          plan.TrialDays,
+			Price:              plan.Price,
+			Interval:           plan.Interval,
+			SoftLimitThreshold: maps.Clone(plan.SoftLimitThreshold),
+			MeteredResources:   slices.Clone(plan.MeteredResources),
 		}
 	}
 	return plansCopy, nil
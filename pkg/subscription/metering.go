@@ -0,0 +1,126 @@
+package subscription
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UsageStore persists metered usage per billing period.
+// Register it with WithUsageStore to use RecordUsage for a plan's MeteredResources.
+type UsageStore interface {
+	// AddUsage adds qty to the tenant's usage of res for the period starting at periodStart.
+	// A non-empty eventID that was already recorded for the tenant must be ignored without error,
+	// so retried events aren't counted twice.
+	AddUsage(ctx context.Context, tenantID uuid.UUID, res Resource, periodStart time.Time, qty int64, eventID string) error
+
+	// GetUsage returns the total usage of res for the period starting at periodStart.
+	GetUsage(ctx context.Context, tenantID uuid.UUID, res Resource, periodStart time.Time) (int64, error)
+}
+
+// UsageReport contains the metered usage of one resource for a closed billing period.
+type UsageReport struct {
+	Subscription   *Subscription // subscription with provider IDs
+	Resource       Resource
+	Quantity       int64
+	PeriodStart    time.Time
+	PeriodEnd      time.Time
+	IdempotencyKey string // stable per tenant, resource and period so retries aren't billed twice
+}
+
+// UsageReporter is implemented by billing providers that can bill metered usage.
+// ReportUsageToProvider returns ErrUsageReportingNotSupported for providers that don't implement it.
+type UsageReporter interface {
+	// ReportUsage bills a closed period's metered usage. Implementations should use
+	// IdempotencyKey so a retried report isn't billed twice.
+	ReportUsage(ctx context.Context, report UsageReport) error
+}
+
+// billingPeriod returns the period containing at. Periods repeat every interval from anchor,
+// the subscription start; a zero anchor or a plan without an interval uses calendar months.
+func billingPeriod(anchor time.Time, interval BillingInterval, at time.Time) (start, end time.Time) {
+	if anchor.IsZero() || interval == BillingIntervalNone {
+		anchor = time.Date(at.Year(), at.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+
+	months := 1
+	if interval == BillingIntervalAnnual {
+		months = 12
+	}
+
+	// Offsets are always taken from the anchor, so a start on the 31st doesn't drift
+	n := ((at.Year()-anchor.Year())*12 + int(at.Month()-anchor.Month())) / months
+	start = addMonths(anchor, n*months)
+	for start.After(at) {
+		n--
+		start = addMonths(anchor, n*months)
+	}
+	return start, addMonths(anchor, (n+1)*months)
+}
+
+// addMonths adds n months to t, clamping the day to the end of the target month.
+// time.AddDate would roll Jan 31 + 1 month over to March.
+func addMonths(t time.Time, n int) time.Time {
+	y, m, d := t.Date()
+	first := time.Date(y, m+time.Month(n), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	if last := first.AddDate(0, 1, -1).Day(); d > last {
+		d = last
+	}
+	return first.AddDate(0, 0, d-1)
+}
+
+// usageIdempotencyKey identifies a period's usage report for a tenant and resource.
+func usageIdempotencyKey(tenantID uuid.UUID, res Resource, periodStart time.Time) string {
+	return fmt.Sprintf("%s:%s:%d", tenantID, res, periodStart.Unix())
+}
+
+type usageKey struct {
+	tenantID    uuid.UUID
+	resource    Resource
+	periodStart int64
+}
+
+type inMemUsageStore struct {
+	mu     sync.Mutex
+	usage  map[usageKey]int64
+	events map[uuid.UUID]map[string]struct{}
+}
+
+// NewInMemUsageStore returns an in-memory UsageStore for tests and single-instance deployments.
+// Usage is lost on restart and recorded event IDs are kept for the lifetime of the store.
+func NewInMemUsageStore() UsageStore {
+	return &inMemUsageStore{
+		usage:  make(map[usageKey]int64),
+		events: make(map[uuid.UUID]map[string]struct{}),
+	}
+}
+
+func (s *inMemUsageStore) AddUsage(_ context.Context, tenantID uuid.UUID, res Resource, periodStart time.Time, qty int64, eventID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if eventID != "" {
+		seen, ok := s.events[tenantID]
+		if !ok {
+			seen = make(map[string]struct{})
+			s.events[tenantID] = seen
+		}
+		if _, dup := seen[eventID]; dup {
+			return nil
+		}
+		seen[eventID] = struct{}{}
+	}
+
+	s.usage[usageKey{tenantID, res, periodStart.Unix()}] += qty
+	return nil
+}
+
+func (s *inMemUsageStore) GetUsage(_ context.Context, tenantID uuid.UUID, res Resource, periodStart time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.usage[usageKey{tenantID, res, periodStart.Unix()}], nil
+}
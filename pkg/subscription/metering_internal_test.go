@@ -0,0 +1,74 @@
+package subscription
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBillingPeriod(t *testing.T) {
+	t.Parallel()
+
+	date := func(y int, m time.Month, d int) time.Time {
+		return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name      string
+		anchor    time.Time
+		interval  BillingInterval
+		at        time.Time
+		wantStart time.Time
+		wantEnd   time.Time
+	}{
+		{
+			name:      "calendar month without anchor",
+			interval:  BillingIntervalMonthly,
+			at:        date(2025, time.March, 15),
+			wantStart: date(2025, time.March, 1),
+			wantEnd:   date(2025, time.April, 1),
+		},
+		{
+			name:      "jan 31 anchor ends february period on the last day",
+			anchor:    date(2025, time.January, 31),
+			interval:  BillingIntervalMonthly,
+			at:        date(2025, time.February, 15),
+			wantStart: date(2025, time.January, 31),
+			wantEnd:   date(2025, time.February, 28),
+		},
+		{
+			name:      "jan 31 anchor in march",
+			anchor:    date(2025, time.January, 31),
+			interval:  BillingIntervalMonthly,
+			at:        date(2025, time.March, 1),
+			wantStart: date(2025, time.February, 28),
+			wantEnd:   date(2025, time.March, 31),
+		},
+		{
+			name:      "jan 31 anchor in leap year",
+			anchor:    date(2024, time.January, 31),
+			interval:  BillingIntervalMonthly,
+			at:        date(2024, time.February, 29),
+			wantStart: date(2024, time.February, 29),
+			wantEnd:   date(2024, time.March, 31),
+		},
+		{
+			name:      "feb 29 annual anchor",
+			anchor:    date(2024, time.February, 29),
+			interval:  BillingIntervalAnnual,
+			at:        date(2025, time.March, 1),
+			wantStart: date(2025, time.February, 28),
+			wantEnd:   date(2026, time.February, 28),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			start, end := billingPeriod(tt.anchor, tt.interval, tt.at)
+			assert.Equal(t, tt.wantStart, start)
+			assert.Equal(t, tt.wantEnd, end)
+		})
+	}
+}
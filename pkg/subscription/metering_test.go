@@ -0,0 +1,177 @@
+package subscription_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dmitrymomot/saaskit/pkg/subscription"
+)
+
+const resourceAPICalls subscription.Resource = "api_calls"
+
+func createMeteredPlans() map[string]subscription.Plan {
+	plans := createTestPlans()
+	pro := plans["pro"]
+	pro.MeteredResources = []subscription.Resource{resourceAPICalls}
+	plans["pro"] = pro
+
+	basic := plans["basic"]
+	basic.MeteredResources = []subscription.Resource{resourceAPICalls}
+	basic.Limits[resourceAPICalls] = 100
+	plans["basic"] = basic
+	return plans
+}
+
+func TestService_RecordUsage(t *testing.T) {
+	t.Parallel()
+
+	newService := func(t *testing.T, store *mockStore, opts ...subscription.ServiceOption) subscription.Service {
+		t.Helper()
+		src := &mockPlansSource{}
+		src.On("Load", mock.Anything).Return(createMeteredPlans(), nil)
+		svc, err := subscription.NewService(context.Background(), src, &mockProvider{}, store, opts...)
+		require.NoError(t, err)
+		return svc
+	}
+
+	t.Run("accumulates usage for the billing period", func(t *testing.T) {
+		t.Parallel()
+		tenantID := uuid.New()
+		ctx := subscription.SetPlanIDToContext(context.Background(), "pro")
+
+		store := &mockStore{}
+		store.On("Get", mock.Anything, tenantID).Return(nil, subscription.ErrSubscriptionNotFound)
+		svc := newService(t, store, subscription.WithUsageStore(subscription.NewInMemUsageStore()))
+
+		require.NoError(t, svc.RecordUsage(ctx, tenantID, resourceAPICalls, 10))
+		require.NoError(t, svc.RecordUsage(ctx, tenantID, resourceAPICalls, 5))
+
+		used, limit, err := svc.GetUsage(ctx, tenantID, resourceAPICalls)
+		require.NoError(t, err)
+		assert.Equal(t, int64(15), used)
+		assert.Equal(t, subscription.Unlimited, limit)
+		assert.NoError(t, svc.CanCreate(ctx, tenantID, resourceAPICalls))
+
+		all, err := svc.GetAllUsage(ctx, tenantID)
+		require.NoError(t, err)
+		assert.Equal(t, subscription.UsageInfo{Current: 15, Limit: subscription.Unlimited}, all[resourceAPICalls])
+	})
+
+	t.Run("event IDs make retries idempotent", func(t *testing.T) {
+		t.Parallel()
+		tenantID := uuid.New()
+		ctx := subscription.SetPlanIDToContext(context.Background(), "pro")
+
+		store := &mockStore{}
+		store.On("Get", mock.Anything, tenantID).Return(&subscription.Subscription{TenantID: tenantID, PlanID: "pro", CreatedAt: time.Now().AddDate(0, -1, -3)}, nil)
+		svc := newService(t, store, subscription.WithUsageStore(subscription.NewInMemUsageStore()))
+
+		eventCtx := subscription.SetUsageEventIDToContext(ctx, "evt_1")
+		require.NoError(t, svc.RecordUsage(eventCtx, tenantID, resourceAPICalls, 7))
+		require.NoError(t, svc.RecordUsage(eventCtx, tenantID, resourceAPICalls, 7))
+		require.NoError(t, svc.RecordUsage(subscription.SetUsageEventIDToContext(ctx, "evt_2"), tenantID, resourceAPICalls, 3))
+
+		used, _, err := svc.GetUsage(ctx, tenantID, resourceAPICalls)
+		require.NoError(t, err)
+		assert.Equal(t, int64(10), used)
+	})
+
+	t.Run("metered limits are enforced", func(t *testing.T) {
+		t.Parallel()
+		tenantID := uuid.New()
+		ctx := subscription.SetPlanIDToContext(context.Background(), "basic")
+
+		store := &mockStore{}
+		store.On("Get", mock.Anything, tenantID).Return(nil, subscription.ErrSubscriptionNotFound)
+		svc := newService(t, store, subscription.WithUsageStore(subscription.NewInMemUsageStore()))
+
+		require.NoError(t, svc.RecordUsage(ctx, tenantID, resourceAPICalls, 100))
+		assert.ErrorIs(t, svc.CanCreate(ctx, tenantID, resourceAPICalls), subscription.ErrLimitExceeded)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		t.Parallel()
+		tenantID := uuid.New()
+		ctx := subscription.SetPlanIDToContext(context.Background(), "pro")
+
+		assert.ErrorIs(t, newService(t, &mockStore{}).RecordUsage(ctx, tenantID, resourceAPICalls, 1), subscription.ErrUsageStoreNotConfigured)
+
+		svc := newService(t, &mockStore{}, subscription.WithUsageStore(subscription.NewInMemUsageStore()))
+		assert.ErrorIs(t, svc.RecordUsage(ctx, tenantID, resourceAPICalls, 0), subscription.ErrInvalidUsageQuantity)
+		assert.ErrorIs(t, svc.RecordUsage(ctx, tenantID, subscription.ResourceProjects, 1), subscription.ErrResourceNotMetered)
+	})
+}
+
+type mockUsageProvider struct {
+	*mockProvider
+}
+
+func (m *mockUsageProvider) ReportUsage(ctx context.Context, report subscription.UsageReport) error {
+	args := m.Called(ctx, report)
+	return args.Error(0)
+}
+
+func TestService_ReportUsageToProvider(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	now := time.Now().UTC()
+	thisPeriod := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	lastPeriod := thisPeriod.AddDate(0, -1, 0)
+
+	tenantID := uuid.New()
+	sub := &subscription.Subscription{
+		TenantID:           tenantID,
+		PlanID:             "pro",
+		ProviderSubID:      "sub_123",
+		ProviderCustomerID: "cus_123",
+		CreatedAt:          thisPeriod.AddDate(0, -3, 0),
+	}
+
+	usage := subscription.NewInMemUsageStore()
+	require.NoError(t, usage.AddUsage(ctx, tenantID, resourceAPICalls, lastPeriod, 1200, ""))
+	require.NoError(t, usage.AddUsage(ctx, tenantID, resourceAPICalls, thisPeriod, 50, ""))
+
+	src := &mockPlansSource{}
+	src.On("Load", mock.Anything).Return(createMeteredPlans(), nil)
+	store := &mockStore{}
+	store.On("Get", ctx, tenantID).Return(sub, nil)
+
+	var reports []subscription.UsageReport
+	provider := &mockUsageProvider{&mockProvider{}}
+	provider.On("ReportUsage", ctx, mock.Anything).Run(func(args mock.Arguments) {
+		reports = append(reports, args.Get(1).(subscription.UsageReport))
+	}).Return(nil)
+
+	svc, err := subscription.NewService(ctx, src, provider, store, subscription.WithUsageStore(usage))
+	require.NoError(t, err)
+
+	require.NoError(t, svc.ReportUsageToProvider(ctx, tenantID))
+	require.NoError(t, svc.ReportUsageToProvider(ctx, tenantID))
+
+	require.Len(t, reports, 2)
+	report := reports[0]
+	assert.Equal(t, sub, report.Subscription)
+	assert.Equal(t, resourceAPICalls, report.Resource)
+	assert.Equal(t, int64(1200), report.Quantity, "only the closed period is reported")
+	assert.Equal(t, lastPeriod, report.PeriodStart)
+	assert.Equal(t, thisPeriod, report.PeriodEnd)
+	assert.NotEmpty(t, report.IdempotencyKey)
+	assert.Equal(t, report, reports[1], "retries reuse the idempotency key")
+
+	t.Run("unsupported provider", func(t *testing.T) {
+		t.Parallel()
+
+		svc, err := subscription.NewService(ctx, src, &mockProvider{}, store, subscription.WithUsageStore(usage))
+		require.NoError(t, err)
+
+		err = svc.ReportUsageToProvider(ctx, tenantID)
+		assert.ErrorIs(t, err, subscription.ErrUsageReportingNotSupported)
+	})
+}
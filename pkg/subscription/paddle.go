@@ -6,6 +6,7 @@ import (
 	"errors"
 	"io"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
@@ -35,7 +36,7 @@ func (c PaddleConfig) Validate() error {
 	return nil
 }
 
-// PaddleProvider implements BillingProvider for Paddle.
+// PaddleProvider implements BillingProvider for Paddle. Metered usage reporting isn't supported.
 type PaddleProvider struct {
 	client   *paddle.SDK
 	verifier *paddle.WebhookVerifier
@@ -105,8 +106,23 @@ func (p *PaddleProvider) CreateCheckoutLink(ctx context.Context, req CheckoutReq
 		}
 	}
 
+	// Transactions take a discount ID, so the code is resolved and validated first
+	if req.DiscountCode != "" {
+		coupon, err := p.ValidateCoupon(ctx, req.DiscountCode)
+		if err != nil {
+			return nil, err
+		}
+		transactionReq.DiscountID = paddle.PtrTo(coupon.ID)
+	}
+
 	transaction, err := p.client.CreateTransaction(ctx, transactionReq)
 	if err != nil {
+		switch {
+		case errors.Is(err, paddle.ErrTransactionDiscountNotEligible):
+			return nil, errors.Join(ErrCouponNotApplicable, err)
+		case errors.Is(err, paddle.ErrTransactionDiscountNotFound):
+			return nil, errors.Join(ErrInvalidCoupon, err)
+		}
 		return nil, errors.Join(ErrFailedToCreateTransaction, err)
 	}
 
@@ -182,6 +198,159 @@ func (p *PaddleProvider) GetCustomerPortalLink(ctx context.Context, subscription
 	return portalLink, nil
 }
 
+// ValidateCoupon finds an active Paddle discount by its code (case-insensitive)
+// that is enabled for checkout, not expired and not used up.
+func (p *PaddleProvider) ValidateCoupon(ctx context.Context, code string) (*CouponInfo, error) {
+	if code == "" {
+		return nil, ErrInvalidCoupon
+	}
+
+	discounts, err := p.client.ListDiscounts(ctx, &paddle.ListDiscountsRequest{
+		Code: []string{code},
+	})
+	if err != nil {
+		return nil, errors.Join(ErrFailedToValidateCoupon, err)
+	}
+
+	res := discounts.Next(ctx)
+	if res.Err() != nil {
+		return nil, errors.Join(ErrFailedToValidateCoupon, res.Err())
+	}
+	if !res.Ok() {
+		return nil, ErrInvalidCoupon
+	}
+
+	return couponFromPaddle(res.Value(), code, time.Now())
+}
+
+// couponFromPaddle validates a Paddle discount and converts it to CouponInfo.
+func couponFromPaddle(discount *paddle.Discount, code string, now time.Time) (*CouponInfo, error) {
+	if discount.Status != paddle.DiscountStatusActive || !discount.EnabledForCheckout {
+		return nil, ErrInvalidCoupon
+	}
+	if discount.UsageLimit != nil && discount.TimesUsed >= *discount.UsageLimit {
+		return nil, ErrInvalidCoupon
+	}
+
+	coupon := &CouponInfo{
+		Code:       code,
+		ID:         discount.ID,
+		Type:       CouponType(discount.Type),
+		Recurring:  discount.Recur,
+		RestrictTo: discount.RestrictTo,
+	}
+	if discount.Code != nil {
+		coupon.Code = *discount.Code
+	}
+	if discount.MaximumRecurringIntervals != nil {
+		coupon.RecurringIntervals = *discount.MaximumRecurringIntervals
+	}
+
+	if discount.ExpiresAt != nil {
+		expiresAt, err := time.Parse(time.RFC3339, *discount.ExpiresAt)
+		if err != nil {
+			return nil, errors.Join(ErrFailedToValidateCoupon, err)
+		}
+		if !now.Before(expiresAt) {
+			return nil, ErrCouponExpired
+		}
+		coupon.ExpiresAt = expiresAt
+	}
+
+	// Paddle sends percentages as decimal strings and flat amounts in the lowest denomination
+	switch coupon.Type {
+	case CouponPercentage:
+		percentage, err := strconv.ParseFloat(discount.Amount, 64)
+		if err != nil {
+			return nil, errors.Join(ErrFailedToValidateCoupon, err)
+		}
+		coupon.Percentage = percentage
+	default:
+		amount, err := strconv.ParseInt(discount.Amount, 10, 64)
+		if err != nil {
+			return nil, errors.Join(ErrFailedToValidateCoupon, err)
+		}
+		coupon.Amount = Money{Amount: amount}
+		if discount.CurrencyCode != nil {
+			coupon.Amount.Currency = string(*discount.CurrencyCode)
+		}
+	}
+
+	return coupon, nil
+}
+
+// ProviderPreviewProration previews replacing the subscription's items with the new price,
+// prorated and billed immediately, which is how Paddle applies upgrades by default.
+// The current quantity is kept so seat-based subscriptions are previewed correctly.
+func (p *PaddleProvider) ProviderPreviewProration(ctx context.Context, req ProrationRequest) (*ProrationPreview, error) {
+	if req.PriceID == "" {
+		return nil, ErrMissingPriceID
+	}
+	if req.Subscription == nil || req.Subscription.ProviderSubID == "" {
+		return nil, ErrMissingProviderSubID
+	}
+
+	current, err := p.client.GetSubscription(ctx, &paddle.GetSubscriptionRequest{
+		SubscriptionID: req.Subscription.ProviderSubID,
+	})
+	if err != nil {
+		return nil, errors.Join(ErrFailedToPreviewProration, err)
+	}
+
+	preview, err := p.client.PreviewSubscriptionUpdate(ctx, &paddle.PreviewSubscriptionUpdateRequest{
+		SubscriptionID: req.Subscription.ProviderSubID,
+		Items: paddle.NewPatchField([]paddle.PreviewSubscriptionUpdateItems{{
+			SubscriptionUpdateItemFromCatalog: &paddle.SubscriptionUpdateItemFromCatalog{
+				PriceID:  req.PriceID,
+				Quantity: paddleQuantity(current),
+			},
+		}}),
+		ProrationBillingMode: paddle.NewPatchField(paddle.ProrationBillingModeProratedImmediately),
+	})
+	if err != nil {
+		return nil, errors.Join(ErrFailedToPreviewProration, err)
+	}
+
+	return prorationFromPaddle(preview, time.Now().UTC())
+}
+
+// paddleQuantity returns the quantity of the subscription's first item, or 1 if it has none.
+func paddleQuantity(sub *paddle.Subscription) int {
+	if len(sub.Items) == 0 || sub.Items[0].Quantity <= 0 {
+		return 1
+	}
+	return sub.Items[0].Quantity
+}
+
+// prorationFromPaddle converts a Paddle subscription preview to ProrationPreview.
+// Totals are grand totals, so credits for unused time are already deducted.
+func prorationFromPaddle(preview *paddle.SubscriptionPreview, now time.Time) (*ProrationPreview, error) {
+	currency := string(preview.CurrencyCode)
+	result := &ProrationPreview{
+		ImmediateCharge: Money{Currency: currency},
+		NextRenewal:     Money{Currency: currency},
+		EffectiveAt:     now,
+	}
+
+	if preview.ImmediateTransaction != nil {
+		amount, err := strconv.ParseInt(preview.ImmediateTransaction.Details.Totals.GrandTotal, 10, 64)
+		if err != nil {
+			return nil, errors.Join(ErrFailedToPreviewProration, err)
+		}
+		result.ImmediateCharge.Amount = amount
+	}
+
+	if total := preview.RecurringTransactionDetails.Totals.GrandTotal; total != "" {
+		amount, err := strconv.ParseInt(total, 10, 64)
+		if err != nil {
+			return nil, errors.Join(ErrFailedToPreviewProration, err)
+		}
+		result.NextRenewal.Amount = amount
+	}
+
+	return result, nil
+}
+
 // ParseWebhook validates and parses incoming webhook data from HTTP request.
 func (p *PaddleProvider) ParseWebhook(req *http.Request) (*WebhookEvent, error) {
 	valid, err := p.verifier.Verify(req)
@@ -216,6 +385,7 @@ type paddleWebhookEvent struct {
 // extractWebhookData extracts relevant data from a Paddle webhook event.
 func (p *PaddleProvider) extractWebhookData(paddleEvent paddleWebhookEvent) (*WebhookEvent, error) {
 	event := &WebhookEvent{
+		EventID:       paddleEvent.EventID,
 		Type:          mapPaddleEventType(paddleEvent.EventType),
 		ProviderEvent: paddleEvent.EventType,
 		Raw:           paddleEvent.Data,
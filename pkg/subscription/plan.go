@@ -9,15 +9,17 @@ import (
 // The ID field should be set to the payment provider's price ID for paid plans
 // to enable direct mapping during checkout and webhook processing.
 type Plan struct {
-	ID          string // provider's price ID (e.g., price_starter_monthly)
-	Name        string
-	Description string
-	Limits      map[Resource]int64 // -1 represents unlimited
-	Features    []Feature
-	Public      bool // available for self-service signup
-	TrialDays   int
-	Price       Money
-	Interval    BillingInterval
+	ID                 string // provider's price ID (e.g., price_starter_monthly)
+	Name               string
+	Description        string
+	Limits             map[Resource]int64 // -1 represents unlimited
+	Features           []Feature
+	Public             bool // available for self-service signup
+	TrialDays          int
+	Price              Money
+	Interval           BillingInterval
+	SoftLimitThreshold map[Resource]int // warning threshold in percent (1-100), see CheckSoftLimit
+	MeteredResources   []Resource       // billed by recorded usage per billing period, see RecordUsage
 }
 
 // TrialEndsAt calculates when the trial period ends.
@@ -29,6 +31,11 @@ func (p Plan) TrialEndsAt(startedAt time.Time) time.Time {
 	return startedAt.AddDate(0, 0, p.TrialDays).UTC()
 }
 
+// IsMetered reports whether res is billed by recorded usage on this plan.
+func (p Plan) IsMetered(res Resource) bool {
+	return slices.Contains(p.MeteredResources, res)
+}
+
 // IsTrialActive reports whether the tenant is still in its trial window.
 func (p Plan) IsTrialActive(startedAt time.Time) bool {
 	if p.TrialDays <= 0 {
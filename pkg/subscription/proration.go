@@ -0,0 +1,28 @@
+package subscription
+
+import (
+	"context"
+	"time"
+)
+
+// ProrationRequest contains data needed to preview a plan change.
+type ProrationRequest struct {
+	Subscription *Subscription // current paid subscription with provider IDs
+	PriceID      string        // provider's price ID of the target plan
+}
+
+// ProrationPreview describes what a plan change costs, so the UI can show it
+// before the customer confirms.
+type ProrationPreview struct {
+	ImmediateCharge Money     // charged right away for the rest of the current period, zero if nothing is due
+	NextRenewal     Money     // regular renewal amount on the new plan
+	EffectiveAt     time.Time // when the new plan takes effect
+}
+
+// ProrationPreviewer is implemented by billing providers that can preview plan changes.
+// PreviewPlanChange returns ErrProrationNotSupported for providers that don't implement it.
+type ProrationPreviewer interface {
+	// ProviderPreviewProration calculates the charge for moving a paid subscription
+	// to another price without applying the change.
+	ProviderPreviewProration(ctx context.Context, req ProrationRequest) (*ProrationPreview, error)
+}
@@ -0,0 +1,120 @@
+package subscription_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dmitrymomot/saaskit/pkg/subscription"
+)
+
+type mockProrationProvider struct {
+	*mockProvider
+}
+
+func (m *mockProrationProvider) ProviderPreviewProration(ctx context.Context, req subscription.ProrationRequest) (*subscription.ProrationPreview, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*subscription.ProrationPreview), args.Error(1)
+}
+
+func TestService_PreviewPlanChange(t *testing.T) {
+	t.Parallel()
+
+	newService := func(t *testing.T, provider subscription.BillingProvider, store *mockStore) subscription.Service {
+		t.Helper()
+		src := &mockPlansSource{}
+		src.On("Load", mock.Anything).Return(createTestPlans(), nil)
+		svc, err := subscription.NewService(context.Background(), src, provider, store)
+		require.NoError(t, err)
+		return svc
+	}
+
+	t.Run("paid plan change asks the provider", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		tenantID := uuid.New()
+		sub := &subscription.Subscription{TenantID: tenantID, PlanID: "basic", ProviderSubID: "sub_123", Status: subscription.StatusActive}
+
+		store := &mockStore{}
+		store.On("Get", ctx, tenantID).Return(sub, nil)
+
+		preview := &subscription.ProrationPreview{
+			ImmediateCharge: subscription.Money{Amount: 2667, Currency: "USD"},
+			NextRenewal:     subscription.Money{Amount: 5000, Currency: "USD"},
+			EffectiveAt:     time.Now(),
+		}
+		provider := &mockProrationProvider{&mockProvider{}}
+		provider.On("ProviderPreviewProration", ctx, subscription.ProrationRequest{Subscription: sub, PriceID: "pro"}).Return(preview, nil)
+
+		got, err := newService(t, provider, store).PreviewPlanChange(ctx, tenantID, "pro")
+		require.NoError(t, err)
+		assert.Equal(t, preview, got)
+		provider.AssertExpectations(t)
+	})
+
+	t.Run("free to paid has no immediate charge", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		tenantID := uuid.New()
+
+		store := &mockStore{}
+		store.On("Get", ctx, tenantID).Return(&subscription.Subscription{TenantID: tenantID, PlanID: "free", Status: subscription.StatusActive}, nil)
+		provider := &mockProrationProvider{&mockProvider{}}
+
+		got, err := newService(t, provider, store).PreviewPlanChange(ctx, tenantID, "basic")
+		require.NoError(t, err)
+		assert.Equal(t, subscription.Money{Amount: 0, Currency: "USD"}, got.ImmediateCharge)
+		assert.Equal(t, subscription.Money{Amount: 1000, Currency: "USD"}, got.NextRenewal)
+		assert.WithinDuration(t, time.Now(), got.EffectiveAt, time.Minute)
+		provider.AssertNotCalled(t, "ProviderPreviewProration", mock.Anything, mock.Anything)
+	})
+
+	t.Run("no subscription yet has no immediate charge", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		tenantID := uuid.New()
+
+		store := &mockStore{}
+		store.On("Get", ctx, tenantID).Return(nil, subscription.ErrSubscriptionNotFound)
+
+		got, err := newService(t, &mockProvider{}, store).PreviewPlanChange(ctx, tenantID, "pro")
+		require.NoError(t, err)
+		assert.Zero(t, got.ImmediateCharge.Amount)
+		assert.Equal(t, int64(5000), got.NextRenewal.Amount)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		tenantID := uuid.New()
+		storeErr := errors.New("db down")
+
+		store := &mockStore{}
+		store.On("Get", ctx, tenantID).Return(&subscription.Subscription{TenantID: tenantID, PlanID: "basic", ProviderSubID: "sub_123"}, nil).Once()
+		store.On("Get", ctx, tenantID).Return(nil, storeErr).Once()
+		store.On("Get", ctx, tenantID).Return(&subscription.Subscription{TenantID: tenantID, PlanID: "basic", ProviderSubID: "sub_123"}, nil).Once()
+
+		svc := newService(t, &mockProvider{}, store)
+
+		_, err := svc.PreviewPlanChange(ctx, tenantID, "unknown")
+		assert.ErrorIs(t, err, subscription.ErrPlanNotFound)
+
+		_, err = svc.PreviewPlanChange(ctx, tenantID, "basic")
+		assert.ErrorIs(t, err, subscription.ErrAlreadyOnPlan)
+
+		_, err = svc.PreviewPlanChange(ctx, tenantID, "pro")
+		assert.ErrorIs(t, err, storeErr)
+
+		_, err = svc.PreviewPlanChange(ctx, tenantID, "pro")
+		assert.ErrorIs(t, err, subscription.ErrProrationNotSupported)
+	})
+}
@@ -36,15 +36,21 @@ type BillingProvider interface {
 	// Each provider looks for their specific signature headers (e.g., Paddle-Signature).
 	// Returns normalized event type and raw provider data.
 	ParseWebhook(r *http.Request) (*WebhookEvent, error)
+
+	// ValidateCoupon looks up a discount code and checks that it can be redeemed.
+	// Returns ErrInvalidCoupon for unknown or disabled codes and ErrCouponExpired
+	// for expired ones, so the UI can explain why the discount isn't applied.
+	ValidateCoupon(ctx context.Context, code string) (*CouponInfo, error)
 }
 
 // CheckoutRequest contains data needed to create a checkout session.
 type CheckoutRequest struct {
-	PriceID    string    // provider's price/plan identifier
-	TenantID   uuid.UUID // your internal tenant ID
-	Email      string    // optional billing email
-	SuccessURL string    // redirect after successful payment
-	CancelURL  string    // redirect if customer cancels
+	PriceID      string    // provider's price/plan identifier
+	TenantID     uuid.UUID // your internal tenant ID
+	Email        string    // optional billing email
+	SuccessURL   string    // redirect after successful payment
+	CancelURL    string    // redirect if customer cancels
+	DiscountCode string    // optional coupon; invalid codes fail instead of being ignored
 }
 
 // CheckoutLink represents a hosted checkout session.
@@ -64,6 +70,7 @@ type PortalLink struct {
 
 // WebhookEvent represents a normalized webhook event from the billing provider.
 type WebhookEvent struct {
+	EventID        string         // provider's unique event ID, used for deduplication
 	Type           EventType      // normalized event type
 	ProviderEvent  string         // original provider event name
 	SubscriptionID string         // provider's subscription ID
@@ -2,10 +2,12 @@ package subscription
 
 import (
 	"context"
+	"crypto/ed25519"
 	"errors"
 	"fmt"
 	"net/http"
 	"slices"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -21,8 +23,10 @@ type Service interface {
 	CheckTrial(ctx context.Context, tenantID uuid.UUID, startedAt time.Time) error
 	VerifyPlan(ctx context.Context, planID string) error
 	GetUsagePercentage(ctx context.Context, tenantID uuid.UUID, res Resource) int
+	CheckSoftLimit(ctx context.Context, tenantID uuid.UUID, res Resource) (SoftLimitStatus, error)
 	CanDowngrade(ctx context.Context, tenantID uuid.UUID, targetPlanID string) error
 	GetAllUsage(ctx context.Context, tenantID uuid.UUID) (map[Resource]UsageInfo, error)
+	Entitlements(ctx context.Context, tenantID uuid.UUID) (*Entitlement, error)
 
 	// Subscription management
 	GetSubscription(ctx context.Context, tenantID uuid.UUID) (*Subscription, error)
@@ -30,6 +34,12 @@ type Service interface {
 	// Billing provider interactions
 	CreateCheckoutLink(ctx context.Context, tenantID uuid.UUID, planID string, opts CheckoutOptions) (*CheckoutLink, error)
 	GetCustomerPortalLink(ctx context.Context, tenantID uuid.UUID) (*PortalLink, error)
+	ValidateCoupon(ctx context.Context, code string) (*CouponInfo, error)
+	PreviewPlanChange(ctx context.Context, tenantID uuid.UUID, newPlanID string) (*ProrationPreview, error)
+
+	// Metered billing
+	RecordUsage(ctx context.Context, tenantID uuid.UUID, res Resource, qty int64) error
+	ReportUsageToProvider(ctx context.Context, tenantID uuid.UUID) error
 	HandleWebhook(r *http.Request) error
 }
 
@@ -52,6 +62,13 @@ type service struct {
 	planIDResolver PlanIDResolver
 	provider       BillingProvider
 	store          SubscriptionStore
+	onSoftLimit    SoftLimitHook
+	gracePeriod    time.Duration
+	usageStore     UsageStore
+	webhookDedup   WebhookDedupStore
+
+	entitlementKey ed25519.PrivateKey
+	entitlementTTL time.Duration
 }
 
 // NewService creates a new Service with the given dependencies.
@@ -84,6 +101,7 @@ func NewService(ctx context.Context, src PlansListSource, provider BillingProvid
 		planIDResolver: PlanIDContextResolver,
 		provider:       provider,
 		store:          store,
+		entitlementTTL: DefaultEntitlementTTL,
 	}
 
 	for _, opt := range opts {
@@ -106,6 +124,10 @@ func (s *service) CanCreate(ctx context.Context, tenantID uuid.UUID, res Resourc
 
 	limit, exists := plan.Limits[res]
 	if !exists {
+		// Metered resources without a cap are pay-as-you-go
+		if plan.IsMetered(res) {
+			return nil
+		}
 		return ErrInvalidResource
 	}
 
@@ -113,20 +135,21 @@ func (s *service) CanCreate(ctx context.Context, tenantID uuid.UUID, res Resourc
 		return nil
 	}
 
-	counter, exists := s.counters[res]
-	if !exists {
-		return ErrNoCounterRegistered
-	}
-
-	current, err := counter(ctx, tenantID)
+	current, err := s.currentUsage(ctx, tenantID, plan, res)
 	if err != nil {
-		return errors.Join(ErrFailedToCountResourceUsage, err)
+		return err
 	}
 
 	if current >= limit {
 		return ErrLimitExceeded
 	}
 
+	if s.onSoftLimit != nil {
+		if status := newSoftLimitStatus(plan, res, current, limit); status.Exceeded {
+			s.onSoftLimit(ctx, tenantID, res, status.Percentage)
+		}
+	}
+
 	return nil
 }
 
@@ -143,17 +166,15 @@ func (s *service) GetUsage(ctx context.Context, tenantID uuid.UUID, res Resource
 
 	resourceLimit, exists := plan.Limits[res]
 	if !exists {
-		return 0, 0, ErrInvalidResource
-	}
-
-	counter, exists := s.counters[res]
-	if !exists {
-		return 0, 0, ErrNoCounterRegistered
+		if !plan.IsMetered(res) {
+			return 0, 0, ErrInvalidResource
+		}
+		resourceLimit = Unlimited
 	}
 
-	current, err := counter(ctx, tenantID)
+	current, err := s.currentUsage(ctx, tenantID, plan, res)
 	if err != nil {
-		return 0, 0, errors.Join(ErrFailedToCountResourceUsage, err)
+		return 0, 0, err
 	}
 
 	return current, resourceLimit, nil
@@ -230,51 +251,46 @@ func (s *service) GetUsagePercentage(ctx context.Context, tenantID uuid.UUID, re
 	return min(int((used*100)/limit), 100)
 }
 
+// CanDowngrade checks current usage against the target plan's limits using every registered
+// counter. Returns a *DowngradeError listing each resource over its target limit, which
+// matches ErrDowngradeNotPossible with errors.Is.
 func (s *service) CanDowngrade(ctx context.Context, tenantID uuid.UUID, targetPlanID string) error {
 	targetPlan, exists := s.plans[targetPlanID]
 	if !exists {
 		return ErrPlanNotFound
 	}
 
-	currentPlanID, err := s.planIDResolver(ctx, tenantID)
-	if err != nil {
-		return err
-	}
-
-	currentPlan, exists := s.plans[currentPlanID]
-	if !exists {
-		return ErrPlanNotFound
-	}
-
-	for resource, targetLimit := range targetPlan.Limits {
-		if targetLimit == Unlimited {
+	var overages []ResourceOverage
+	for resource, counter := range s.counters {
+		targetLimit, exists := targetPlan.Limits[resource]
+		// Metered usage resets every billing period, so it can't block a downgrade
+		if !exists || targetLimit == Unlimited || targetPlan.IsMetered(resource) {
 			continue
 		}
 
-		currentLimit, hasResource := currentPlan.Limits[resource]
-		if !hasResource {
-			continue
+		currentUsage, err := counter(ctx, tenantID)
+		if err != nil {
+			return errors.Join(ErrFailedToCountResourceUsage, err)
 		}
 
-		// Verify usage only when reducing limits to prevent data loss
-		if currentLimit != targetLimit && (currentLimit == Unlimited || currentLimit > targetLimit) {
-			counter, exists := s.counters[resource]
-			if !exists {
-				continue
-			}
-
-			currentUsage, err := counter(ctx, tenantID)
-			if err != nil {
-				return errors.Join(ErrFailedToCountResourceUsage, err)
-			}
-
-			if currentUsage > targetLimit {
-				return ErrDowngradeNotPossible
-			}
+		if currentUsage > targetLimit {
+			overages = append(overages, ResourceOverage{
+				Resource: resource,
+				Current:  currentUsage,
+				Limit:    targetLimit,
+			})
 		}
 	}
 
-	return nil
+	if len(overages) == 0 {
+		return nil
+	}
+
+	slices.SortFunc(overages, func(a, b ResourceOverage) int {
+		return strings.Compare(string(a.Resource), string(b.Resource))
+	})
+
+	return &DowngradeError{PlanID: targetPlanID, Overages: overages}
 }
 
 func (s *service) GetAllUsage(ctx context.Context, tenantID uuid.UUID) (map[Resource]UsageInfo, error) {
@@ -296,18 +312,166 @@ func (s *service) GetAllUsage(ctx context.Context, tenantID uuid.UUID) (map[Reso
 			Limit:   limit,
 		}
 
-		if counter, exists := s.counters[resource]; exists {
-			if current, err := counter(ctx, tenantID); err == nil {
-				usage.Current = current
-			}
+		if current, err := s.currentUsage(ctx, tenantID, plan, resource); err == nil {
+			usage.Current = current
 		}
 
 		result[resource] = usage
 	}
 
+	for _, resource := range plan.MeteredResources {
+		if _, exists := result[resource]; exists {
+			continue
+		}
+		usage := UsageInfo{Limit: Unlimited}
+		if current, err := s.currentUsage(ctx, tenantID, plan, resource); err == nil {
+			usage.Current = current
+		}
+		result[resource] = usage
+	}
+
 	return result, nil
 }
 
+// currentUsage returns the usage counted against a plan limit: the current billing
+// period's total for metered resources and the registered counter otherwise.
+func (s *service) currentUsage(ctx context.Context, tenantID uuid.UUID, plan Plan, res Resource) (int64, error) {
+	if plan.IsMetered(res) {
+		if s.usageStore == nil {
+			return 0, ErrUsageStoreNotConfigured
+		}
+		periodStart, _, err := s.usagePeriod(ctx, tenantID, plan, time.Now().UTC())
+		if err != nil {
+			return 0, err
+		}
+		current, err := s.usageStore.GetUsage(ctx, tenantID, res, periodStart)
+		if err != nil {
+			return 0, errors.Join(ErrFailedToCountResourceUsage, err)
+		}
+		return current, nil
+	}
+
+	counter, exists := s.counters[res]
+	if !exists {
+		return 0, ErrNoCounterRegistered
+	}
+
+	current, err := counter(ctx, tenantID)
+	if err != nil {
+		return 0, errors.Join(ErrFailedToCountResourceUsage, err)
+	}
+	return current, nil
+}
+
+// usagePeriod returns the billing period containing at, anchored at the tenant's subscription
+// start. Tenants without a subscription are metered per calendar month.
+func (s *service) usagePeriod(ctx context.Context, tenantID uuid.UUID, plan Plan, at time.Time) (start, end time.Time, err error) {
+	var anchor time.Time
+	subscription, err := s.store.Get(ctx, tenantID)
+	switch {
+	case err == nil:
+		anchor = subscription.CreatedAt
+	case !errors.Is(err, ErrSubscriptionNotFound):
+		return time.Time{}, time.Time{}, err
+	}
+
+	start, end = billingPeriod(anchor, plan.Interval, at)
+	return start, end, nil
+}
+
+// RecordUsage adds qty to the tenant's metered usage of res for the current billing period.
+// Attach an event ID with SetUsageEventIDToContext to make retries idempotent.
+func (s *service) RecordUsage(ctx context.Context, tenantID uuid.UUID, res Resource, qty int64) error {
+	if s.usageStore == nil {
+		return ErrUsageStoreNotConfigured
+	}
+	if qty <= 0 {
+		return ErrInvalidUsageQuantity
+	}
+
+	planID, err := s.planIDResolver(ctx, tenantID)
+	if err != nil {
+		return err
+	}
+
+	plan, exists := s.plans[planID]
+	if !exists {
+		return ErrPlanNotFound
+	}
+	if !plan.IsMetered(res) {
+		return ErrResourceNotMetered
+	}
+
+	periodStart, _, err := s.usagePeriod(ctx, tenantID, plan, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+
+	eventID, _ := GetUsageEventIDFromContext(ctx)
+	if err := s.usageStore.AddUsage(ctx, tenantID, res, periodStart, qty, eventID); err != nil {
+		return errors.Join(ErrFailedToRecordUsage, err)
+	}
+
+	return nil
+}
+
+// ReportUsageToProvider sends the metered usage of the most recently closed billing period
+// to the billing provider. Run it after each period closes, e.g. from a daily job; reports
+// carry an idempotency key, so running it again for the same period is safe.
+// Tenants without a paid subscription have nothing to report.
+// Returns ErrUsageReportingNotSupported when the provider doesn't implement UsageReporter.
+func (s *service) ReportUsageToProvider(ctx context.Context, tenantID uuid.UUID) error {
+	if s.usageStore == nil {
+		return ErrUsageStoreNotConfigured
+	}
+	reporter, ok := s.provider.(UsageReporter)
+	if !ok {
+		return ErrUsageReportingNotSupported
+	}
+
+	subscription, err := s.store.Get(ctx, tenantID)
+	if err != nil {
+		return err
+	}
+	if subscription.ProviderSubID == "" {
+		return nil
+	}
+
+	plan, exists := s.plans[subscription.PlanID]
+	if !exists {
+		return ErrPlanNotFound
+	}
+
+	currentStart, _ := billingPeriod(subscription.CreatedAt, plan.Interval, time.Now().UTC())
+	periodStart, periodEnd := billingPeriod(subscription.CreatedAt, plan.Interval, currentStart.Add(-time.Nanosecond))
+
+	var errs []error
+	for _, res := range plan.MeteredResources {
+		qty, err := s.usageStore.GetUsage(ctx, tenantID, res, periodStart)
+		if err != nil {
+			errs = append(errs, errors.Join(ErrFailedToCountResourceUsage, err))
+			continue
+		}
+		if qty == 0 {
+			continue
+		}
+
+		err = reporter.ReportUsage(ctx, UsageReport{
+			Subscription:   subscription,
+			Resource:       res,
+			Quantity:       qty,
+			PeriodStart:    periodStart,
+			PeriodEnd:      periodEnd,
+			IdempotencyKey: usageIdempotencyKey(tenantID, res, periodStart),
+		})
+		if err != nil {
+			errs = append(errs, errors.Join(ErrFailedToReportUsage, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
 func (s *service) CreateCheckoutLink(ctx context.Context, tenantID uuid.UUID, planID string, opts CheckoutOptions) (*CheckoutLink, error) {
 	plan, exists := s.plans[planID]
 	if !exists {
@@ -347,16 +511,91 @@ func (s *service) CreateCheckoutLink(ctx context.Context, tenantID uuid.UUID, pl
 
 	// Delegate to payment provider for paid plans
 	return s.provider.CreateCheckoutLink(ctx, CheckoutRequest{
-		PriceID:    plan.ID, // must match provider's price ID
-		TenantID:   tenantID,
-		Email:      opts.Email,
-		SuccessURL: opts.SuccessURL,
-		CancelURL:  opts.CancelURL,
+		PriceID:      plan.ID, // must match provider's price ID
+		TenantID:     tenantID,
+		Email:        opts.Email,
+		SuccessURL:   opts.SuccessURL,
+		CancelURL:    opts.CancelURL,
+		DiscountCode: strings.TrimSpace(opts.DiscountCode),
 	})
 }
 
+// ValidateCoupon checks a discount code with the billing provider before checkout.
+// Use CouponInfo.Apply with the plan price to show the discounted amount.
+func (s *service) ValidateCoupon(ctx context.Context, code string) (*CouponInfo, error) {
+	code = strings.TrimSpace(code)
+	if code == "" {
+		return nil, ErrInvalidCoupon
+	}
+	return s.provider.ValidateCoupon(ctx, code)
+}
+
+// PreviewPlanChange returns what moving the tenant to newPlanID would cost without changing anything.
+// Tenants without a paid subscription owe nothing now: the new plan is paid through checkout,
+// so the preview has a zero immediate charge and the plan price as the next renewal.
+// Returns ErrProrationNotSupported when the provider doesn't implement ProrationPreviewer.
+func (s *service) PreviewPlanChange(ctx context.Context, tenantID uuid.UUID, newPlanID string) (*ProrationPreview, error) {
+	newPlan, exists := s.plans[newPlanID]
+	if !exists {
+		return nil, ErrPlanNotFound
+	}
+
+	subscription, err := s.store.Get(ctx, tenantID)
+	if err != nil && !errors.Is(err, ErrSubscriptionNotFound) {
+		return nil, err
+	}
+	if subscription != nil && subscription.PlanID == newPlanID {
+		return nil, ErrAlreadyOnPlan
+	}
+
+	// Free plans have no provider subscription to prorate against
+	if subscription == nil || subscription.ProviderSubID == "" || newPlan.Interval == BillingIntervalNone {
+		return &ProrationPreview{
+			ImmediateCharge: Money{Currency: newPlan.Price.Currency},
+			NextRenewal:     newPlan.Price,
+			EffectiveAt:     time.Now().UTC(),
+		}, nil
+	}
+
+	previewer, ok := s.provider.(ProrationPreviewer)
+	if !ok {
+		return nil, ErrProrationNotSupported
+	}
+
+	return previewer.ProviderPreviewProration(ctx, ProrationRequest{
+		Subscription: subscription,
+		PriceID:      newPlan.ID, // must match provider's price ID
+	})
+}
+
+// GetSubscription returns the tenant's subscription, cancelling it first if its grace period has ended.
 func (s *service) GetSubscription(ctx context.Context, tenantID uuid.UUID) (*Subscription, error) {
-	return s.store.Get(ctx, tenantID)
+	subscription, err := s.store.Get(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.expireGracePeriod(ctx, subscription, time.Now().UTC()); err != nil {
+		return nil, err
+	}
+
+	return subscription, nil
+}
+
+// expireGracePeriod cancels a past-due subscription whose grace period has ended.
+func (s *service) expireGracePeriod(ctx context.Context, subscription *Subscription, now time.Time) error {
+	if !subscription.gracePeriodEnded(now) {
+		return nil
+	}
+
+	subscription.Status = StatusCancelled
+	subscription.CancelledAt = &now
+	subscription.UpdatedAt = now
+
+	if err := s.store.Save(ctx, subscription); err != nil {
+		return errors.Join(ErrFailedToCancelSubscription, err)
+	}
+	return nil
 }
 
 func (s *service) GetCustomerPortalLink(ctx context.Context, tenantID uuid.UUID) (*PortalLink, error) {
@@ -389,6 +628,31 @@ func (s *service) HandleWebhook(r *http.Request) error {
 	}
 	tenantID := event.TenantID
 
+	// Providers redeliver events; skip ones already processed before touching any state
+	if s.webhookDedup != nil && event.EventID != "" {
+		seen, err := s.webhookDedup.Seen(ctx, event.EventID)
+		if err != nil {
+			return errors.Join(ErrFailedToCheckWebhookDup, err)
+		}
+		if seen {
+			return nil
+		}
+
+		if err := s.applyWebhookEvent(ctx, event, tenantID); err != nil {
+			// Let the provider's retry apply the event instead of being skipped as a duplicate
+			if forgetErr := s.webhookDedup.Forget(ctx, event.EventID); forgetErr != nil {
+				return errors.Join(err, forgetErr)
+			}
+			return err
+		}
+		return nil
+	}
+
+	return s.applyWebhookEvent(ctx, event, tenantID)
+}
+
+// applyWebhookEvent updates the tenant's subscription for a parsed webhook event.
+func (s *service) applyWebhookEvent(ctx context.Context, event *WebhookEvent, tenantID uuid.UUID) error {
 	switch event.Type {
 	case EventSubscriptionCreated:
 		now := time.Now().UTC()
@@ -421,9 +685,10 @@ func (s *service) HandleWebhook(r *http.Request) error {
 			return fmt.Errorf("subscription not found for tenant %s: %w", tenantID, err)
 		}
 
+		now := time.Now().UTC()
 		subscription.PlanID = event.PlanID
-		subscription.Status = SubscriptionStatus(event.Status)
-		subscription.UpdatedAt = time.Now().UTC()
+		subscription.UpdatedAt = now
+		s.setPaymentStatus(subscription, SubscriptionStatus(event.Status), now)
 
 		if err := s.store.Save(ctx, subscription); err != nil {
 			return errors.Join(ErrFailedToUpdateSubscription, err)
@@ -436,6 +701,12 @@ func (s *service) HandleWebhook(r *http.Request) error {
 		}
 
 		now := time.Now().UTC()
+
+		// Provider dunning may give up before our grace period does
+		if subscription.IsPastDue() && subscription.GracePeriodEndsAt != nil && !subscription.gracePeriodEnded(now) {
+			return nil
+		}
+
 		subscription.Status = StatusCancelled
 		subscription.CancelledAt = &now
 		subscription.UpdatedAt = now
@@ -444,23 +715,54 @@ func (s *service) HandleWebhook(r *http.Request) error {
 			return errors.Join(ErrFailedToCancelSubscription, err)
 		}
 
-	case EventPaymentFailed:
+	case EventPaymentFailed, EventPaymentSucceeded:
 		subscription, err := s.store.Get(ctx, tenantID)
-		if err == nil {
-			subscription.Status = StatusPastDue
-			subscription.UpdatedAt = time.Now().UTC()
+		if errors.Is(err, ErrSubscriptionNotFound) {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("failed to get subscription: %w", err)
+		}
+
+		now := time.Now().UTC()
+		if subscription.gracePeriodEnded(now) {
+			return s.expireGracePeriod(ctx, subscription, now)
+		}
 
-			if err := s.store.Save(ctx, subscription); err != nil {
-				return errors.Join(ErrFailedToUpdateSubscriptionStatus, err)
+		status := StatusPastDue
+		if event.Type == EventPaymentSucceeded {
+			// Only recovery matters; other statuses are driven by subscription events
+			if !subscription.IsPastDue() {
+				return nil
 			}
-		} else if !errors.Is(err, ErrSubscriptionNotFound) {
-			return fmt.Errorf("failed to get subscription: %w", err)
+			status = StatusActive
+		}
+
+		subscription.UpdatedAt = now
+		s.setPaymentStatus(subscription, status, now)
+
+		if err := s.store.Save(ctx, subscription); err != nil {
+			return errors.Join(ErrFailedToUpdateSubscriptionStatus, err)
 		}
 	}
 
 	return nil
 }
 
+// setPaymentStatus applies a status change, starting the grace period when the subscription
+// becomes past due and clearing it once it leaves that state. Repeated failures keep the
+// original deadline so retries can't extend access.
+func (s *service) setPaymentStatus(subscription *Subscription, status SubscriptionStatus, now time.Time) {
+	if status == StatusPastDue {
+		if !subscription.IsPastDue() && s.gracePeriod > 0 {
+			gracePeriodEndsAt := now.Add(s.gracePeriod)
+			subscription.GracePeriodEndsAt = &gracePeriodEndsAt
+		}
+	} else {
+		subscription.GracePeriodEndsAt = nil
+	}
+	subscription.Status = status
+}
+
 // validatePlans ensures plan configurations are internally consistent.
 // Catches common configuration errors early to prevent runtime issues.
 func validatePlans(plans map[string]Plan) error {
@@ -474,6 +776,13 @@ func validatePlans(plans map[string]Plan) error {
 			return errors.Join(ErrInvalidPlanConfiguration,
 				fmt.Errorf("%w: plan %s has %d trial days", ErrNegativeTrialDays, planID, plan.TrialDays))
 		}
+
+		for res, threshold := range plan.SoftLimitThreshold {
+			if threshold < 1 || threshold > 100 {
+				return errors.Join(ErrInvalidPlanConfiguration,
+					fmt.Errorf("%w: plan %s has %d%% for %s", ErrInvalidSoftLimit, planID, threshold, res))
+			}
+		}
 	}
 	return nil
 }
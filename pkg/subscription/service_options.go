@@ -1,5 +1,10 @@
 package subscription
 
+import (
+	"crypto/ed25519"
+	"time"
+)
+
 // ServiceOption configures a Service instance.
 type ServiceOption func(*service)
 
@@ -32,3 +37,57 @@ func WithCounter(resource Resource, fn ResourceCounterFunc) ServiceOption {
 		s.counters[resource] = fn
 	}
 }
+
+// WithEntitlementKey enables Entitlements snapshots signed with the given Ed25519 key.
+// Edge verifiers (VerifyEntitlement, ParseEntitlement) only get its public half, so a
+// compromised edge can't mint snapshots. A non-positive ttl keeps DefaultEntitlementTTL.
+func WithEntitlementKey(key ed25519.PrivateKey, ttl time.Duration) ServiceOption {
+	return func(s *service) {
+		s.entitlementKey = key
+		if ttl > 0 {
+			s.entitlementTTL = ttl
+		}
+	}
+}
+
+// WithOnSoftLimit sets a hook fired by CanCreate when a creation is allowed but usage
+// has reached the plan's SoftLimitThreshold. Use it for upgrade nudges; it runs
+// synchronously, so hand slow work (emails, notifications) off to a queue.
+func WithOnSoftLimit(fn SoftLimitHook) ServiceOption {
+	return func(s *service) {
+		s.onSoftLimit = fn
+	}
+}
+
+// WithGracePeriod keeps access for past-due subscriptions for d after a failed payment.
+// Cancellation events received during the grace period are deferred, and the subscription
+// is cancelled once the period ends. Use Subscription.IsPastDue to warn the tenant meanwhile.
+func WithGracePeriod(d time.Duration) ServiceOption {
+	return func(s *service) {
+		if d > 0 {
+			s.gracePeriod = d
+		}
+	}
+}
+
+// WithUsageStore enables usage-based billing for plans with MeteredResources.
+// RecordUsage, ReportUsageToProvider and GetUsage for metered resources require it.
+func WithUsageStore(store UsageStore) ServiceOption {
+	return func(s *service) {
+		if store != nil {
+			s.usageStore = store
+		}
+	}
+}
+
+// WithWebhookDedup makes HandleWebhook ignore provider events whose ID was already seen,
+// so redelivered webhooks don't apply plan changes twice. Events are recorded before
+// processing so concurrent deliveries are applied once; if processing fails, the ID is
+// forgotten again so the provider's retry is applied.
+func WithWebhookDedup(store WebhookDedupStore) ServiceOption {
+	return func(s *service) {
+		if store != nil {
+			s.webhookDedup = store
+		}
+	}
+}
@@ -58,6 +58,14 @@ func (m *mockProvider) ParseWebhook(r *http.Request) (*subscription.WebhookEvent
 	return args.Get(0).(*subscription.WebhookEvent), args.Error(1)
 }
 
+func (m *mockProvider) ValidateCoupon(ctx context.Context, code string) (*subscription.CouponInfo, error) {
+	args := m.Called(ctx, code)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*subscription.CouponInfo), args.Error(1)
+}
+
 type mockStore struct {
 	mock.Mock
 }
@@ -405,6 +413,59 @@ func TestService_CanDowngrade(t *testing.T) {
 
 		src.AssertExpectations(t)
 	})
+
+	t.Run("lists every resource over the target limits", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		tenantID := uuid.New()
+
+		src := &mockPlansSource{}
+		src.On("Load", mock.Anything).Return(createTestPlans(), nil)
+
+		svc, err := subscription.NewService(ctx, src, &mockProvider{}, &mockStore{},
+			subscription.WithCounter(subscription.ResourceProjects, func(context.Context, uuid.UUID) (int64, error) {
+				return 25, nil // basic allows 10
+			}),
+			subscription.WithCounter(subscription.ResourceTeamMembers, func(context.Context, uuid.UUID) (int64, error) {
+				return 8, nil // basic allows 5
+			}),
+			subscription.WithCounter(subscription.ResourceAPIKeys, func(context.Context, uuid.UUID) (int64, error) {
+				return 2, nil // within basic limit of 2
+			}),
+		)
+		require.NoError(t, err)
+
+		err = svc.CanDowngrade(ctx, tenantID, "basic")
+		require.ErrorIs(t, err, subscription.ErrDowngradeNotPossible)
+
+		var downgradeErr *subscription.DowngradeError
+		require.ErrorAs(t, err, &downgradeErr)
+		assert.Equal(t, "basic", downgradeErr.PlanID)
+		assert.Equal(t, []subscription.ResourceOverage{
+			{Resource: subscription.ResourceProjects, Current: 25, Limit: 10},
+			{Resource: subscription.ResourceTeamMembers, Current: 8, Limit: 5},
+		}, downgradeErr.Overages)
+		assert.Equal(t, int64(15), downgradeErr.Overages[0].Excess())
+		assert.Contains(t, err.Error(), "projects 25/10")
+	})
+
+	t.Run("skips counters for unlimited target limits", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+
+		src := &mockPlansSource{}
+		src.On("Load", mock.Anything).Return(createTestPlans(), nil)
+
+		svc, err := subscription.NewService(ctx, src, &mockProvider{}, &mockStore{},
+			subscription.WithCounter(subscription.ResourceTeamMembers, func(context.Context, uuid.UUID) (int64, error) {
+				t.Error("counter must not run for an unlimited target limit")
+				return 0, nil
+			}),
+		)
+		require.NoError(t, err)
+
+		assert.NoError(t, svc.CanDowngrade(ctx, uuid.New(), "pro"))
+	})
 }
 
 func TestService_CheckTrial(t *testing.T) {
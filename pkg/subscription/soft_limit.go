@@ -0,0 +1,79 @@
+package subscription
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+// SoftLimitStatus reports resource usage against the plan's soft limit threshold.
+type SoftLimitStatus struct {
+	Exceeded   bool  // usage reached the warning threshold
+	Percentage int   // usage percentage (0-100, or -1 for unlimited)
+	Threshold  int   // warning threshold in percent, 0 if the plan defines none
+	Current    int64 // current usage
+	Limit      int64 // hard limit
+}
+
+// SoftLimitHook is called when usage crosses the soft limit threshold.
+type SoftLimitHook func(ctx context.Context, tenantID uuid.UUID, res Resource, percentage int)
+
+// CheckSoftLimit reports whether the tenant's usage reached the plan's warning
+// threshold for the resource. Resources without a threshold or with unlimited
+// quota are never reported as exceeded.
+func (s *service) CheckSoftLimit(ctx context.Context, tenantID uuid.UUID, res Resource) (SoftLimitStatus, error) {
+	planID, err := s.planIDResolver(ctx, tenantID)
+	if err != nil {
+		return SoftLimitStatus{}, err
+	}
+
+	plan, exists := s.plans[planID]
+	if !exists {
+		return SoftLimitStatus{}, ErrPlanNotFound
+	}
+
+	limit, exists := plan.Limits[res]
+	if !exists {
+		return SoftLimitStatus{}, ErrInvalidResource
+	}
+
+	if limit == Unlimited {
+		return newSoftLimitStatus(plan, res, 0, limit), nil
+	}
+
+	counter, exists := s.counters[res]
+	if !exists {
+		return SoftLimitStatus{}, ErrNoCounterRegistered
+	}
+
+	current, err := counter(ctx, tenantID)
+	if err != nil {
+		return SoftLimitStatus{}, errors.Join(ErrFailedToCountResourceUsage, err)
+	}
+
+	return newSoftLimitStatus(plan, res, current, limit), nil
+}
+
+func newSoftLimitStatus(plan Plan, res Resource, current, limit int64) SoftLimitStatus {
+	status := SoftLimitStatus{
+		Threshold: plan.SoftLimitThreshold[res],
+		Current:   current,
+		Limit:     limit,
+	}
+
+	switch {
+	case limit == Unlimited:
+		status.Percentage = -1
+		return status
+	case limit == 0:
+		status.Percentage = 100
+	default:
+		status.Percentage = min(int((current*100)/limit), 100)
+	}
+
+	// Compare unrounded values so 79.5% doesn't count as crossing an 80% threshold
+	status.Exceeded = status.Threshold > 0 && current*100 >= int64(status.Threshold)*limit
+
+	return status
+}
@@ -0,0 +1,157 @@
+package subscription_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dmitrymomot/saaskit/pkg/subscription"
+)
+
+func newSoftLimitService(t *testing.T, used int64, opts ...subscription.ServiceOption) subscription.Service {
+	t.Helper()
+
+	plan := subscription.Plan{
+		ID:       "basic",
+		Name:     "Basic",
+		Interval: subscription.BillingIntervalMonthly,
+		Limits: map[subscription.Resource]int64{
+			subscription.ResourceProjects:    10,
+			subscription.ResourceAPIKeys:     5,
+			subscription.ResourceTeamMembers: subscription.Unlimited,
+		},
+		SoftLimitThreshold: map[subscription.Resource]int{
+			subscription.ResourceProjects:    80,
+			subscription.ResourceTeamMembers: 80,
+		},
+	}
+
+	counter := func(context.Context, uuid.UUID) (int64, error) { return used, nil }
+	opts = append(opts,
+		subscription.WithCounter(subscription.ResourceProjects, counter),
+		subscription.WithCounter(subscription.ResourceAPIKeys, counter),
+		subscription.WithCounter(subscription.ResourceTeamMembers, counter),
+	)
+
+	svc, err := subscription.NewService(context.Background(), subscription.NewInMemSource(plan),
+		&mockProvider{}, &mockStore{}, opts...)
+	require.NoError(t, err)
+	return svc
+}
+
+func TestService_CheckSoftLimit(t *testing.T) {
+	t.Parallel()
+	ctx := subscription.SetPlanIDToContext(context.Background(), "basic")
+
+	t.Run("below threshold", func(t *testing.T) {
+		t.Parallel()
+		svc := newSoftLimitService(t, 7)
+
+		status, err := svc.CheckSoftLimit(ctx, uuid.New(), subscription.ResourceProjects)
+		require.NoError(t, err)
+		assert.False(t, status.Exceeded)
+		assert.Equal(t, 70, status.Percentage)
+		assert.Equal(t, 80, status.Threshold)
+		assert.Equal(t, int64(7), status.Current)
+		assert.Equal(t, int64(10), status.Limit)
+	})
+
+	t.Run("at threshold", func(t *testing.T) {
+		t.Parallel()
+		svc := newSoftLimitService(t, 8)
+
+		status, err := svc.CheckSoftLimit(ctx, uuid.New(), subscription.ResourceProjects)
+		require.NoError(t, err)
+		assert.True(t, status.Exceeded)
+		assert.Equal(t, 80, status.Percentage)
+	})
+
+	t.Run("resource without threshold", func(t *testing.T) {
+		t.Parallel()
+		svc := newSoftLimitService(t, 5)
+
+		status, err := svc.CheckSoftLimit(ctx, uuid.New(), subscription.ResourceAPIKeys)
+		require.NoError(t, err)
+		assert.False(t, status.Exceeded)
+		assert.Equal(t, 100, status.Percentage)
+		assert.Zero(t, status.Threshold)
+	})
+
+	t.Run("unlimited resource", func(t *testing.T) {
+		t.Parallel()
+		svc := newSoftLimitService(t, 1000)
+
+		status, err := svc.CheckSoftLimit(ctx, uuid.New(), subscription.ResourceTeamMembers)
+		require.NoError(t, err)
+		assert.False(t, status.Exceeded)
+		assert.Equal(t, -1, status.Percentage)
+	})
+
+	t.Run("unknown resource", func(t *testing.T) {
+		t.Parallel()
+		svc := newSoftLimitService(t, 0)
+
+		_, err := svc.CheckSoftLimit(ctx, uuid.New(), subscription.ResourceDomains)
+		assert.ErrorIs(t, err, subscription.ErrInvalidResource)
+	})
+}
+
+func TestService_CanCreate_SoftLimitHook(t *testing.T) {
+	t.Parallel()
+	ctx := subscription.SetPlanIDToContext(context.Background(), "basic")
+
+	newHook := func(calls *atomic.Int32, pct *atomic.Int32) subscription.ServiceOption {
+		return subscription.WithOnSoftLimit(func(_ context.Context, _ uuid.UUID, res subscription.Resource, percentage int) {
+			assert.Equal(t, subscription.ResourceProjects, res)
+			calls.Add(1)
+			pct.Store(int32(percentage))
+		})
+	}
+
+	t.Run("fires when threshold crossed", func(t *testing.T) {
+		t.Parallel()
+		var calls, pct atomic.Int32
+		svc := newSoftLimitService(t, 9, newHook(&calls, &pct))
+
+		require.NoError(t, svc.CanCreate(ctx, uuid.New(), subscription.ResourceProjects))
+		assert.Equal(t, int32(1), calls.Load())
+		assert.Equal(t, int32(90), pct.Load())
+	})
+
+	t.Run("silent below threshold", func(t *testing.T) {
+		t.Parallel()
+		var calls, pct atomic.Int32
+		svc := newSoftLimitService(t, 3, newHook(&calls, &pct))
+
+		require.NoError(t, svc.CanCreate(ctx, uuid.New(), subscription.ResourceProjects))
+		assert.Zero(t, calls.Load())
+	})
+
+	t.Run("silent when hard limit blocks creation", func(t *testing.T) {
+		t.Parallel()
+		var calls, pct atomic.Int32
+		svc := newSoftLimitService(t, 10, newHook(&calls, &pct))
+
+		assert.ErrorIs(t, svc.CanCreate(ctx, uuid.New(), subscription.ResourceProjects), subscription.ErrLimitExceeded)
+		assert.Zero(t, calls.Load())
+	})
+}
+
+func TestNewService_InvalidSoftLimitThreshold(t *testing.T) {
+	t.Parallel()
+
+	for _, threshold := range []int{0, -10, 101} {
+		plan := subscription.Plan{
+			ID:                 "basic",
+			Limits:             map[subscription.Resource]int64{subscription.ResourceProjects: 10},
+			SoftLimitThreshold: map[subscription.Resource]int{subscription.ResourceProjects: threshold},
+		}
+
+		_, err := subscription.NewService(context.Background(), subscription.NewInMemSource(plan), &mockProvider{}, &mockStore{})
+		assert.ErrorIs(t, err, subscription.ErrInvalidSoftLimit, "threshold %d", threshold)
+	}
+}
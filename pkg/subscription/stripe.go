@@ -0,0 +1,553 @@
+package subscription
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// DefaultStripeAPIURL is the Stripe REST API base URL.
+	DefaultStripeAPIURL = "https://api.stripe.com"
+	// DefaultStripeWebhookTolerance is the maximum age of a signed webhook, matching Stripe's SDKs.
+	DefaultStripeWebhookTolerance = 5 * time.Minute
+
+	// stripeAPIVersion pins request and response shapes regardless of the account default.
+	stripeAPIVersion = "2024-06-20"
+	// Stripe portal session URLs are short-lived and single-use
+	stripePortalExpiry = 5 * time.Minute
+)
+
+// StripeConfig holds configuration for Stripe billing provider.
+type StripeConfig struct {
+	SecretKey        string        `env:"STRIPE_SECRET_KEY,required"`
+	WebhookSecret    string        `env:"STRIPE_WEBHOOK_SECRET,required"`
+	PortalReturnURL  string        `env:"STRIPE_PORTAL_RETURN_URL"` // where the customer portal links back to
+	WebhookTolerance time.Duration `env:"STRIPE_WEBHOOK_TOLERANCE" envDefault:"5m"`
+	APIURL           string        `env:"STRIPE_API_URL" envDefault:"https://api.stripe.com"`
+
+	// MeterEventNames maps metered resources to Stripe billing meter event names.
+	// Resources without an entry use the resource name, e.g. "api_calls".
+	MeterEventNames map[Resource]string
+}
+
+// Validate checks if the configuration is valid.
+func (c StripeConfig) Validate() error {
+	if c.SecretKey == "" {
+		return ErrMissingAPIKey
+	}
+	if c.WebhookSecret == "" {
+		return ErrMissingWebhookSecret
+	}
+	return nil
+}
+
+// StripeProvider implements BillingProvider for Stripe using Checkout Sessions,
+// the Billing customer portal and billing meters. Proration previews aren't supported.
+type StripeProvider struct {
+	client *http.Client
+	config StripeConfig
+}
+
+// NewStripeProvider creates a new Stripe billing provider.
+func NewStripeProvider(config StripeConfig) (*StripeProvider, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	if config.APIURL == "" {
+		config.APIURL = DefaultStripeAPIURL
+	}
+	config.APIURL = strings.TrimSuffix(config.APIURL, "/")
+	if config.WebhookTolerance <= 0 {
+		config.WebhookTolerance = DefaultStripeWebhookTolerance
+	}
+
+	return &StripeProvider{
+		client: &http.Client{Timeout: 30 * time.Second},
+		config: config,
+	}, nil
+}
+
+// CreateCheckoutLink creates a hosted subscription checkout session in Stripe.
+// The tenant ID is stored in the subscription metadata so webhooks can be matched to it.
+func (p *StripeProvider) CreateCheckoutLink(ctx context.Context, req CheckoutRequest) (*CheckoutLink, error) {
+	if req.PriceID == "" {
+		return nil, ErrMissingPriceID
+	}
+	if req.TenantID == uuid.Nil {
+		return nil, ErrMissingTenantID
+	}
+
+	form := url.Values{
+		"mode":                                   {"subscription"},
+		"line_items[0][price]":                   {req.PriceID},
+		"line_items[0][quantity]":                {"1"},
+		"client_reference_id":                    {req.TenantID.String()},
+		"metadata[tenant_id]":                    {req.TenantID.String()},
+		"subscription_data[metadata][tenant_id]": {req.TenantID.String()},
+	}
+	if req.Email != "" {
+		form.Set("customer_email", req.Email)
+	}
+	if req.SuccessURL != "" {
+		form.Set("success_url", req.SuccessURL)
+	}
+	if req.CancelURL != "" {
+		form.Set("cancel_url", req.CancelURL)
+	}
+
+	// Sessions take a promotion code ID, so the code is resolved and validated first
+	if req.DiscountCode != "" {
+		coupon, err := p.ValidateCoupon(ctx, req.DiscountCode)
+		if err != nil {
+			return nil, err
+		}
+		form.Set("discounts[0][promotion_code]", coupon.ID)
+	}
+
+	var session struct {
+		ID        string `json:"id"`
+		URL       string `json:"url"`
+		ExpiresAt int64  `json:"expires_at"`
+	}
+	if err := p.do(ctx, http.MethodPost, "/v1/checkout/sessions", form, &session); err != nil {
+		var apiErr *stripeError
+		if errors.As(err, &apiErr) && strings.HasPrefix(apiErr.Param, "discounts") {
+			return nil, errors.Join(ErrCouponNotApplicable, err)
+		}
+		return nil, errors.Join(ErrFailedToCreateCheckoutSession, err)
+	}
+
+	if session.URL == "" {
+		return nil, ErrNoCheckoutURL
+	}
+
+	expiresAt := time.Now().Add(DefaultCheckoutExpiry)
+	if session.ExpiresAt > 0 {
+		expiresAt = time.Unix(session.ExpiresAt, 0)
+	}
+
+	return &CheckoutLink{
+		URL:       session.URL,
+		SessionID: session.ID,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// GetCustomerPortalLink returns a link to Stripe's customer portal.
+func (p *StripeProvider) GetCustomerPortalLink(ctx context.Context, subscription *Subscription) (*PortalLink, error) {
+	if subscription == nil {
+		return nil, ErrSubscriptionNotFound
+	}
+	if subscription.ProviderSubID == "" {
+		return nil, ErrMissingProviderSubID
+	}
+	if subscription.ProviderCustomerID == "" {
+		return nil, ErrMissingProviderCustomerID
+	}
+
+	form := url.Values{"customer": {subscription.ProviderCustomerID}}
+	if p.config.PortalReturnURL != "" {
+		form.Set("return_url", p.config.PortalReturnURL)
+	}
+
+	var session struct {
+		URL string `json:"url"`
+	}
+	if err := p.do(ctx, http.MethodPost, "/v1/billing_portal/sessions", form, &session); err != nil {
+		return nil, errors.Join(ErrFailedToCreateBillingPortalSession, err)
+	}
+
+	if session.URL == "" {
+		return nil, ErrNoPortalURL
+	}
+
+	return &PortalLink{
+		URL:       session.URL,
+		ExpiresAt: time.Now().Add(stripePortalExpiry),
+	}, nil
+}
+
+// ValidateCoupon finds an active Stripe promotion code by its customer-facing code
+// whose coupon is still valid.
+func (p *StripeProvider) ValidateCoupon(ctx context.Context, code string) (*CouponInfo, error) {
+	if code == "" {
+		return nil, ErrInvalidCoupon
+	}
+
+	query := url.Values{
+		"code":     {code},
+		"active":   {"true"},
+		"limit":    {"1"},
+		"expand[]": {"data.coupon.applies_to"},
+	}
+
+	var list struct {
+		Data []stripePromotionCode `json:"data"`
+	}
+	if err := p.do(ctx, http.MethodGet, "/v1/promotion_codes?"+query.Encode(), nil, &list); err != nil {
+		return nil, errors.Join(ErrFailedToValidateCoupon, err)
+	}
+	if len(list.Data) == 0 {
+		return nil, ErrInvalidCoupon
+	}
+
+	return couponFromStripe(list.Data[0], time.Now())
+}
+
+// stripePromotionCode is the subset of a Stripe promotion code used for validation.
+type stripePromotionCode struct {
+	ID             string `json:"id"`
+	Code           string `json:"code"`
+	Active         bool   `json:"active"`
+	ExpiresAt      int64  `json:"expires_at"`
+	MaxRedemptions int64  `json:"max_redemptions"`
+	TimesRedeemed  int64  `json:"times_redeemed"`
+	Coupon         struct {
+		ID               string  `json:"id"`
+		Valid            bool    `json:"valid"`
+		PercentOff       float64 `json:"percent_off"`
+		AmountOff        int64   `json:"amount_off"`
+		Currency         string  `json:"currency"`
+		Duration         string  `json:"duration"`
+		DurationInMonths int     `json:"duration_in_months"`
+		RedeemBy         int64   `json:"redeem_by"`
+		AppliesTo        *struct {
+			Products []string `json:"products"`
+		} `json:"applies_to"`
+	} `json:"coupon"`
+}
+
+// couponFromStripe validates a Stripe promotion code and converts it to CouponInfo.
+func couponFromStripe(promo stripePromotionCode, now time.Time) (*CouponInfo, error) {
+	if !promo.Active || !promo.Coupon.Valid {
+		return nil, ErrInvalidCoupon
+	}
+	if promo.MaxRedemptions > 0 && promo.TimesRedeemed >= promo.MaxRedemptions {
+		return nil, ErrInvalidCoupon
+	}
+
+	coupon := &CouponInfo{
+		Code:      promo.Code,
+		ID:        promo.ID,
+		Recurring: promo.Coupon.Duration != "once",
+	}
+	if promo.Coupon.Duration == "repeating" {
+		coupon.RecurringIntervals = promo.Coupon.DurationInMonths
+	}
+	if promo.Coupon.AppliesTo != nil {
+		coupon.RestrictTo = promo.Coupon.AppliesTo.Products
+	}
+
+	// The earlier of the promotion code and coupon deadlines wins
+	for _, deadline := range []int64{promo.ExpiresAt, promo.Coupon.RedeemBy} {
+		if deadline == 0 {
+			continue
+		}
+		expiresAt := time.Unix(deadline, 0)
+		if coupon.ExpiresAt.IsZero() || expiresAt.Before(coupon.ExpiresAt) {
+			coupon.ExpiresAt = expiresAt
+		}
+	}
+	if !coupon.ExpiresAt.IsZero() && !now.Before(coupon.ExpiresAt) {
+		return nil, ErrCouponExpired
+	}
+
+	if promo.Coupon.PercentOff > 0 {
+		coupon.Type = CouponPercentage
+		coupon.Percentage = promo.Coupon.PercentOff
+	} else {
+		coupon.Type = CouponFlat
+		coupon.Amount = Money{
+			Amount:   promo.Coupon.AmountOff,
+			Currency: strings.ToUpper(promo.Coupon.Currency),
+		}
+	}
+
+	return coupon, nil
+}
+
+// ParseWebhook validates the Stripe-Signature header and parses the event.
+// Events older than the configured tolerance are rejected to prevent replays.
+func (p *StripeProvider) ParseWebhook(req *http.Request) (*WebhookEvent, error) {
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, errors.Join(ErrFailedToReadRequestBody, err)
+	}
+
+	if err := p.verifySignature(req.Header.Get("Stripe-Signature"), body, time.Now()); err != nil {
+		return nil, err
+	}
+
+	var stripeEvent stripeWebhookEvent
+	if err := json.Unmarshal(body, &stripeEvent); err != nil {
+		return nil, errors.Join(ErrFailedToParseWebhook, err)
+	}
+
+	return extractStripeWebhookData(stripeEvent), nil
+}
+
+// verifySignature checks a "t=<unix>,v1=<hex>" header against an HMAC-SHA256 of "<t>.<body>".
+// Stripe may send several v1 signatures while a secret is being rolled; any match is accepted.
+func (p *StripeProvider) verifySignature(header string, body []byte, now time.Time) error {
+	if header == "" {
+		return ErrWebhookVerificationFailed
+	}
+
+	var timestamp string
+	var signatures [][]byte
+	for part := range strings.SplitSeq(header, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			if sig, err := hex.DecodeString(value); err == nil {
+				signatures = append(signatures, sig)
+			}
+		}
+	}
+
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || len(signatures) == 0 {
+		return ErrWebhookVerificationFailed
+	}
+
+	mac := hmac.New(sha256.New, []byte(p.config.WebhookSecret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	expected := mac.Sum(nil)
+
+	valid := false
+	for _, sig := range signatures {
+		if hmac.Equal(sig, expected) {
+			valid = true
+			break
+		}
+	}
+	if !valid {
+		return ErrWebhookVerificationFailed
+	}
+
+	if age := now.Sub(time.Unix(ts, 0)); age > p.config.WebhookTolerance || age < -p.config.WebhookTolerance {
+		return fmt.Errorf("%w: timestamp outside tolerance", ErrWebhookVerificationFailed)
+	}
+
+	return nil
+}
+
+// stripeWebhookEvent represents the structure of a Stripe webhook event.
+type stripeWebhookEvent struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+	Data struct {
+		Object map[string]any `json:"object"`
+	} `json:"data"`
+}
+
+// extractStripeWebhookData extracts relevant data from a Stripe webhook event.
+func extractStripeWebhookData(stripeEvent stripeWebhookEvent) *WebhookEvent {
+	obj := stripeEvent.Data.Object
+	event := &WebhookEvent{
+		EventID:       stripeEvent.ID,
+		Type:          mapStripeEventType(stripeEvent.Type),
+		ProviderEvent: stripeEvent.Type,
+		Raw:           obj,
+	}
+
+	if custID, ok := obj["customer"].(string); ok {
+		event.CustomerID = custID
+	}
+
+	switch {
+	case strings.HasPrefix(stripeEvent.Type, "customer.subscription."):
+		if subID, ok := obj["id"].(string); ok {
+			event.SubscriptionID = subID
+		}
+		if status, ok := obj["status"].(string); ok {
+			event.Status = string(mapStripeStatus(status))
+		}
+		event.TenantID = tenantIDFromMetadata(obj["metadata"])
+		if items, ok := obj["items"].(map[string]any); ok {
+			event.PlanID = extractPriceIDFromItems(items["data"])
+		}
+
+	case strings.HasPrefix(stripeEvent.Type, "invoice."):
+		if status, ok := obj["status"].(string); ok {
+			event.Status = status
+		}
+
+		// Subscription details moved under "parent" in newer API versions
+		details, _ := obj["subscription_details"].(map[string]any)
+		if parent, ok := obj["parent"].(map[string]any); ok && details == nil {
+			details, _ = parent["subscription_details"].(map[string]any)
+		}
+		if subID, ok := obj["subscription"].(string); ok {
+			event.SubscriptionID = subID
+		} else if subID, ok := details["subscription"].(string); ok {
+			event.SubscriptionID = subID
+		}
+		event.TenantID = tenantIDFromMetadata(details["metadata"])
+
+		if lines, ok := obj["lines"].(map[string]any); ok {
+			event.PlanID = extractPriceIDFromItems(lines["data"])
+		}
+	}
+
+	return event
+}
+
+// tenantIDFromMetadata reads the tenant_id key set during checkout.
+func tenantIDFromMetadata(metadata any) uuid.UUID {
+	m, ok := metadata.(map[string]any)
+	if !ok {
+		return uuid.Nil
+	}
+	tenantIDStr, ok := m["tenant_id"].(string)
+	if !ok {
+		return uuid.Nil
+	}
+	tenantID, err := uuid.Parse(tenantIDStr)
+	if err != nil {
+		return uuid.Nil
+	}
+	return tenantID
+}
+
+// mapStripeEventType maps Stripe event types to internal EventType.
+func mapStripeEventType(stripeEvent string) EventType {
+	switch stripeEvent {
+	case "customer.subscription.created":
+		return EventSubscriptionCreated
+	case "customer.subscription.updated":
+		return EventSubscriptionUpdated
+	case "customer.subscription.deleted":
+		return EventSubscriptionCancelled
+	case "customer.subscription.resumed":
+		return EventSubscriptionResumed
+	case "invoice.paid", "invoice.payment_succeeded":
+		return EventPaymentSucceeded
+	case "invoice.payment_failed":
+		return EventPaymentFailed
+	default:
+		// Return the original event as EventType for unmapped events
+		return EventType(stripeEvent)
+	}
+}
+
+// mapStripeStatus maps Stripe subscription status to internal SubscriptionStatus.
+func mapStripeStatus(stripeStatus string) SubscriptionStatus {
+	switch strings.ToLower(stripeStatus) {
+	case "trialing":
+		return StatusTrialing
+	case "active":
+		return StatusActive
+	case "past_due", "unpaid":
+		return StatusPastDue
+	case "canceled", "cancelled":
+		return StatusCancelled
+	case "incomplete_expired":
+		return StatusExpired
+	default:
+		// Return as-is for unknown statuses
+		return SubscriptionStatus(stripeStatus)
+	}
+}
+
+// ReportUsage sends the period's usage as a single billing meter event for the customer.
+// The idempotency key becomes the event identifier, so Stripe drops duplicate reports.
+// Stripe only accepts events from the last 35 days.
+func (p *StripeProvider) ReportUsage(ctx context.Context, report UsageReport) error {
+	if report.Subscription == nil || report.Subscription.ProviderCustomerID == "" {
+		return ErrMissingProviderCustomerID
+	}
+
+	eventName := string(report.Resource)
+	if name, ok := p.config.MeterEventNames[report.Resource]; ok {
+		eventName = name
+	}
+
+	// Attribute the usage to the last second of the period it was recorded in
+	timestamp := report.PeriodEnd.Add(-time.Second)
+	if now := time.Now(); timestamp.After(now) {
+		timestamp = now
+	}
+
+	form := url.Values{
+		"event_name":                  {eventName},
+		"identifier":                  {report.IdempotencyKey},
+		"timestamp":                   {strconv.FormatInt(timestamp.Unix(), 10)},
+		"payload[stripe_customer_id]": {report.Subscription.ProviderCustomerID},
+		"payload[value]":              {strconv.FormatInt(report.Quantity, 10)},
+	}
+
+	var event struct {
+		Identifier string `json:"identifier"`
+	}
+	return p.do(ctx, http.MethodPost, "/v1/billing/meter_events", form, &event)
+}
+
+// stripeError is the error object returned by the Stripe API.
+type stripeError struct {
+	StatusCode int    `json:"-"`
+	Type       string `json:"type"`
+	Code       string `json:"code"`
+	Param      string `json:"param"`
+	Message    string `json:"message"`
+}
+
+func (e *stripeError) Error() string {
+	return fmt.Sprintf("stripe: %s (status %d, type %s, code %s)", e.Message, e.StatusCode, e.Type, e.Code)
+}
+
+// do sends a form-encoded request to the Stripe API and decodes the JSON response into out.
+func (p *StripeProvider) do(ctx context.Context, method, path string, form url.Values, out any) error {
+	var body io.Reader
+	if form != nil {
+		body = strings.NewReader(form.Encode())
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, p.config.APIURL+path, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+p.config.SecretKey)
+	req.Header.Set("Stripe-Version", stripeAPIVersion)
+	if form != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		var errResp struct {
+			Error stripeError `json:"error"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&errResp)
+		errResp.Error.StatusCode = resp.StatusCode
+		return &errResp.Error
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
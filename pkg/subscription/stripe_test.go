@@ -0,0 +1,316 @@
+package subscription_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dmitrymomot/saaskit/pkg/subscription"
+)
+
+const stripeWebhookSecret = "whsec_test"
+
+func newStripeProvider(t *testing.T, handler http.HandlerFunc) *subscription.StripeProvider {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	provider, err := subscription.NewStripeProvider(subscription.StripeConfig{
+		SecretKey:       "sk_test_123",
+		WebhookSecret:   stripeWebhookSecret,
+		PortalReturnURL: "https://app.example.com/billing",
+		APIURL:          server.URL,
+	})
+	require.NoError(t, err)
+	return provider
+}
+
+func stripeWebhookRequest(t *testing.T, payload string, at time.Time) *http.Request {
+	t.Helper()
+
+	ts := strconv.FormatInt(at.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(stripeWebhookSecret))
+	mac.Write([]byte(ts + "." + payload))
+
+	req := httptest.NewRequest(http.MethodPost, "/webhook", bytes.NewReader([]byte(payload)))
+	req.Header.Set("Stripe-Signature", fmt.Sprintf("t=%s,v1=%s,v0=ignored", ts, hex.EncodeToString(mac.Sum(nil))))
+	return req
+}
+
+func TestNewStripeProvider_Validation(t *testing.T) {
+	t.Parallel()
+
+	_, err := subscription.NewStripeProvider(subscription.StripeConfig{WebhookSecret: "whsec"})
+	assert.ErrorIs(t, err, subscription.ErrMissingAPIKey)
+
+	_, err = subscription.NewStripeProvider(subscription.StripeConfig{SecretKey: "sk"})
+	assert.ErrorIs(t, err, subscription.ErrMissingWebhookSecret)
+}
+
+func TestStripeProvider_CreateCheckoutLink(t *testing.T) {
+	t.Parallel()
+
+	tenantID := uuid.New()
+	expiresAt := time.Now().Add(time.Hour).Unix()
+
+	provider := newStripeProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/promotion_codes":
+			assert.Equal(t, "LAUNCH20", r.URL.Query().Get("code"))
+			fmt.Fprint(w, `{"data":[{"id":"promo_1","code":"LAUNCH20","active":true,"coupon":{"id":"co_1","valid":true,"percent_off":20,"duration":"once"}}]}`)
+		case "/v1/checkout/sessions":
+			assert.Equal(t, "Bearer sk_test_123", r.Header.Get("Authorization"))
+			assert.NoError(t, r.ParseForm())
+			assert.Equal(t, "subscription", r.PostForm.Get("mode"))
+			assert.Equal(t, "price_pro", r.PostForm.Get("line_items[0][price]"))
+			assert.Equal(t, tenantID.String(), r.PostForm.Get("subscription_data[metadata][tenant_id]"))
+			assert.Equal(t, "user@example.com", r.PostForm.Get("customer_email"))
+			assert.Equal(t, "promo_1", r.PostForm.Get("discounts[0][promotion_code]"))
+			fmt.Fprintf(w, `{"id":"cs_123","url":"https://checkout.stripe.com/c/cs_123","expires_at":%d}`, expiresAt)
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+	})
+
+	link, err := provider.CreateCheckoutLink(context.Background(), subscription.CheckoutRequest{
+		PriceID:      "price_pro",
+		TenantID:     tenantID,
+		Email:        "user@example.com",
+		SuccessURL:   "https://app.example.com/success",
+		CancelURL:    "https://app.example.com/cancel",
+		DiscountCode: "LAUNCH20",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "https://checkout.stripe.com/c/cs_123", link.URL)
+	assert.Equal(t, "cs_123", link.SessionID)
+	assert.Equal(t, expiresAt, link.ExpiresAt.Unix())
+
+	t.Run("api errors", func(t *testing.T) {
+		t.Parallel()
+
+		provider := newStripeProvider(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"error":{"type":"invalid_request_error","message":"No such price","param":"line_items[0][price]"}}`)
+		})
+		_, err := provider.CreateCheckoutLink(context.Background(), subscription.CheckoutRequest{PriceID: "price_x", TenantID: tenantID})
+		assert.ErrorIs(t, err, subscription.ErrFailedToCreateCheckoutSession)
+		assert.ErrorContains(t, err, "No such price")
+	})
+}
+
+func TestStripeProvider_GetCustomerPortalLink(t *testing.T) {
+	t.Parallel()
+
+	provider := newStripeProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/billing_portal/sessions", r.URL.Path)
+		assert.NoError(t, r.ParseForm())
+		assert.Equal(t, "cus_123", r.PostForm.Get("customer"))
+		assert.Equal(t, "https://app.example.com/billing", r.PostForm.Get("return_url"))
+		fmt.Fprint(w, `{"id":"bps_1","url":"https://billing.stripe.com/p/session/bps_1"}`)
+	})
+
+	link, err := provider.GetCustomerPortalLink(context.Background(), &subscription.Subscription{
+		ProviderSubID:      "sub_123",
+		ProviderCustomerID: "cus_123",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "https://billing.stripe.com/p/session/bps_1", link.URL)
+
+	_, err = provider.GetCustomerPortalLink(context.Background(), &subscription.Subscription{ProviderSubID: "sub_123"})
+	assert.ErrorIs(t, err, subscription.ErrMissingProviderCustomerID)
+}
+
+func TestStripeProvider_ValidateCoupon(t *testing.T) {
+	t.Parallel()
+
+	promos := map[string]string{
+		"FLAT5":   `{"id":"promo_flat","code":"FLAT5","active":true,"coupon":{"valid":true,"amount_off":500,"currency":"usd","duration":"repeating","duration_in_months":3,"applies_to":{"products":["prod_1"]}}}`,
+		"OLD":     fmt.Sprintf(`{"id":"promo_old","code":"OLD","active":true,"expires_at":%d,"coupon":{"valid":true,"percent_off":10,"duration":"forever"}}`, time.Now().Add(-time.Hour).Unix()),
+		"USEDUP":  `{"id":"promo_used","code":"USEDUP","active":true,"max_redemptions":5,"times_redeemed":5,"coupon":{"valid":true,"percent_off":10,"duration":"once"}}`,
+		"INVALID": `{"id":"promo_inv","code":"INVALID","active":true,"coupon":{"valid":false,"percent_off":10,"duration":"once"}}`,
+	}
+	provider := newStripeProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		if promo, ok := promos[r.URL.Query().Get("code")]; ok {
+			fmt.Fprintf(w, `{"data":[%s]}`, promo)
+			return
+		}
+		fmt.Fprint(w, `{"data":[]}`)
+	})
+	ctx := context.Background()
+
+	coupon, err := provider.ValidateCoupon(ctx, "FLAT5")
+	require.NoError(t, err)
+	assert.Equal(t, subscription.CouponFlat, coupon.Type)
+	assert.Equal(t, subscription.Money{Amount: 500, Currency: "USD"}, coupon.Amount)
+	assert.True(t, coupon.Recurring)
+	assert.Equal(t, 3, coupon.RecurringIntervals)
+	assert.True(t, coupon.AppliesTo("prod_1"))
+	assert.False(t, coupon.AppliesTo("prod_2"))
+
+	_, err = provider.ValidateCoupon(ctx, "OLD")
+	assert.ErrorIs(t, err, subscription.ErrCouponExpired)
+
+	for _, code := range []string{"USEDUP", "INVALID", "UNKNOWN"} {
+		_, err = provider.ValidateCoupon(ctx, code)
+		assert.ErrorIs(t, err, subscription.ErrInvalidCoupon, code)
+	}
+}
+
+func TestStripeProvider_ParseWebhook(t *testing.T) {
+	t.Parallel()
+
+	tenantID := uuid.New()
+	provider := newStripeProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request %s", r.URL.Path)
+	})
+
+	t.Run("subscription updated", func(t *testing.T) {
+		t.Parallel()
+
+		payload := fmt.Sprintf(`{"id":"evt_1","type":"customer.subscription.updated","data":{"object":{
+			"id":"sub_123","customer":"cus_123","status":"past_due","metadata":{"tenant_id":%q},
+			"items":{"data":[{"id":"si_1","price":{"id":"price_pro"}}]}}}}`, tenantID)
+
+		event, err := provider.ParseWebhook(stripeWebhookRequest(t, payload, time.Now()))
+		require.NoError(t, err)
+		assert.Equal(t, "evt_1", event.EventID)
+		assert.Equal(t, subscription.EventSubscriptionUpdated, event.Type)
+		assert.Equal(t, "customer.subscription.updated", event.ProviderEvent)
+		assert.Equal(t, tenantID, event.TenantID)
+		assert.Equal(t, "sub_123", event.SubscriptionID)
+		assert.Equal(t, "cus_123", event.CustomerID)
+		assert.Equal(t, string(subscription.StatusPastDue), event.Status)
+		assert.Equal(t, "price_pro", event.PlanID)
+	})
+
+	t.Run("subscription deleted", func(t *testing.T) {
+		t.Parallel()
+
+		payload := fmt.Sprintf(`{"type":"customer.subscription.deleted","data":{"object":{"id":"sub_123","status":"canceled","metadata":{"tenant_id":%q}}}}`, tenantID)
+
+		event, err := provider.ParseWebhook(stripeWebhookRequest(t, payload, time.Now()))
+		require.NoError(t, err)
+		assert.Equal(t, subscription.EventSubscriptionCancelled, event.Type)
+		assert.Equal(t, string(subscription.StatusCancelled), event.Status)
+	})
+
+	t.Run("invoice payment failed", func(t *testing.T) {
+		t.Parallel()
+
+		for name, payload := range map[string]string{
+			"subscription_details": fmt.Sprintf(`{"type":"invoice.payment_failed","data":{"object":{
+				"customer":"cus_123","subscription":"sub_123","status":"open",
+				"subscription_details":{"metadata":{"tenant_id":%q}},
+				"lines":{"data":[{"price":{"id":"price_pro"}}]}}}}`, tenantID),
+			"parent": fmt.Sprintf(`{"type":"invoice.payment_failed","data":{"object":{
+				"customer":"cus_123","status":"open",
+				"parent":{"subscription_details":{"subscription":"sub_123","metadata":{"tenant_id":%q}}}}}}`, tenantID),
+		} {
+			event, err := provider.ParseWebhook(stripeWebhookRequest(t, payload, time.Now()))
+			require.NoError(t, err, name)
+			assert.Equal(t, subscription.EventPaymentFailed, event.Type, name)
+			assert.Equal(t, tenantID, event.TenantID, name)
+			assert.Equal(t, "sub_123", event.SubscriptionID, name)
+		}
+	})
+
+	t.Run("rejects invalid signatures", func(t *testing.T) {
+		t.Parallel()
+
+		payload := `{"type":"customer.subscription.updated","data":{"object":{}}}`
+
+		stale := stripeWebhookRequest(t, payload, time.Now().Add(-10*time.Minute))
+		_, err := provider.ParseWebhook(stale)
+		assert.ErrorIs(t, err, subscription.ErrWebhookVerificationFailed)
+
+		tampered := stripeWebhookRequest(t, payload, time.Now())
+		tampered.Body = http.NoBody
+		_, err = provider.ParseWebhook(tampered)
+		assert.ErrorIs(t, err, subscription.ErrWebhookVerificationFailed)
+
+		missing := httptest.NewRequest(http.MethodPost, "/webhook", bytes.NewReader([]byte(payload)))
+		_, err = provider.ParseWebhook(missing)
+		assert.ErrorIs(t, err, subscription.ErrWebhookVerificationFailed)
+	})
+}
+
+func TestService_HandleWebhook_Stripe(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	tenantID := uuid.New()
+	provider := newStripeProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request %s", r.URL.Path)
+	})
+
+	src := &mockPlansSource{}
+	src.On("Load", mock.Anything).Return(createTestPlans(), nil)
+	store := &mockStore{}
+	store.On("Get", mock.Anything, tenantID).Return(&subscription.Subscription{
+		TenantID:      tenantID,
+		PlanID:        "pro",
+		Status:        subscription.StatusActive,
+		ProviderSubID: "sub_123",
+	}, nil)
+	store.On("Save", mock.Anything, mock.MatchedBy(func(sub *subscription.Subscription) bool {
+		return sub.Status == subscription.StatusPastDue
+	})).Return(nil).Once()
+
+	svc, err := subscription.NewService(ctx, src, provider, store)
+	require.NoError(t, err)
+
+	payload := fmt.Sprintf(`{"type":"invoice.payment_failed","data":{"object":{"subscription":"sub_123","subscription_details":{"metadata":{"tenant_id":%q}}}}}`, tenantID)
+	require.NoError(t, svc.HandleWebhook(stripeWebhookRequest(t, payload, time.Now())))
+	store.AssertExpectations(t)
+}
+
+func TestStripeProvider_ReportUsage(t *testing.T) {
+	t.Parallel()
+
+	periodEnd := time.Now().Add(-time.Hour).Truncate(time.Second)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/billing/meter_events", r.URL.Path)
+		assert.NoError(t, r.ParseForm())
+		assert.Equal(t, "api_requests", r.PostForm.Get("event_name"))
+		assert.Equal(t, "tenant:api_calls:1", r.PostForm.Get("identifier"))
+		assert.Equal(t, "cus_123", r.PostForm.Get("payload[stripe_customer_id]"))
+		assert.Equal(t, "1200", r.PostForm.Get("payload[value]"))
+		assert.Equal(t, strconv.FormatInt(periodEnd.Add(-time.Second).Unix(), 10), r.PostForm.Get("timestamp"))
+		fmt.Fprint(w, `{"object":"billing.meter_event","identifier":"tenant:api_calls:1"}`)
+	}))
+	t.Cleanup(server.Close)
+
+	provider, err := subscription.NewStripeProvider(subscription.StripeConfig{
+		SecretKey:       "sk_test_123",
+		WebhookSecret:   stripeWebhookSecret,
+		MeterEventNames: map[subscription.Resource]string{"api_calls": "api_requests"},
+		APIURL:          server.URL,
+	})
+	require.NoError(t, err)
+
+	report := subscription.UsageReport{
+		Subscription:   &subscription.Subscription{ProviderCustomerID: "cus_123"},
+		Resource:       "api_calls",
+		Quantity:       1200,
+		PeriodEnd:      periodEnd,
+		IdempotencyKey: "tenant:api_calls:1",
+	}
+	require.NoError(t, provider.ReportUsage(context.Background(), report))
+
+	report.Subscription = &subscription.Subscription{}
+	assert.ErrorIs(t, provider.ReportUsage(context.Background(), report), subscription.ErrMissingProviderCustomerID)
+}
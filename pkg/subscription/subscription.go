@@ -18,6 +18,7 @@ type Subscription struct {
 	TrialEndsAt        *time.Time // set only for plans with trials
 	UpdatedAt          time.Time
 	CancelledAt        *time.Time // set when subscription is cancelled
+	GracePeriodEndsAt  *time.Time // set when a payment fails and a grace period is configured
 }
 
 func (s *Subscription) IsTrialing() bool {
@@ -28,6 +29,11 @@ func (s *Subscription) IsActive() bool {
 	return s.Status == StatusActive
 }
 
+// IsPastDue reports whether the latest payment failed and the subscription is in dunning.
+func (s *Subscription) IsPastDue() bool {
+	return s.Status == StatusPastDue
+}
+
 func (s *Subscription) IsCancelled() bool {
 	return s.Status == StatusCancelled
 }
@@ -62,3 +68,30 @@ func (s *Subscription) TrialDaysRemainingAt(now time.Time) int {
 func (s *Subscription) TrialDaysRemaining() int {
 	return s.TrialDaysRemainingAt(time.Now().UTC())
 }
+
+// DaysUntilCancellationAt returns the number of days left in the grace period at a given time.
+// Returns 0 if not past due, no grace period is set or it has ended.
+func (s *Subscription) DaysUntilCancellationAt(now time.Time) int {
+	if !s.IsPastDue() || s.GracePeriodEndsAt == nil {
+		return 0
+	}
+
+	remaining := s.GracePeriodEndsAt.Sub(now)
+	if remaining <= 0 {
+		return 0
+	}
+
+	days := remaining.Hours() / 24
+	return int(days + 0.5)
+}
+
+// DaysUntilCancellation returns the number of days left before a past-due subscription is cancelled.
+// Returns 0 if not past due, no grace period is set or it has ended.
+func (s *Subscription) DaysUntilCancellation() int {
+	return s.DaysUntilCancellationAt(time.Now().UTC())
+}
+
+// gracePeriodEnded reports whether a past-due subscription has run out of grace.
+func (s *Subscription) gracePeriodEnded(now time.Time) bool {
+	return s.IsPastDue() && s.GracePeriodEndsAt != nil && !now.Before(*s.GracePeriodEndsAt)
+}
@@ -151,3 +151,22 @@ func TestSubscription_TrialDaysRemaining(t *testing.T) {
 		assert.Equal(t, 0, days)
 	})
 }
+
+func TestSubscription_DaysUntilCancellation(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	endsAt := now.Add(3*24*time.Hour + 2*time.Hour)
+	pastDue := &subscription.Subscription{Status: subscription.StatusPastDue, GracePeriodEndsAt: &endsAt}
+
+	assert.True(t, pastDue.IsPastDue())
+	assert.Equal(t, 3, pastDue.DaysUntilCancellationAt(now))
+	assert.Equal(t, 0, pastDue.DaysUntilCancellationAt(endsAt.Add(time.Second)))
+
+	active := &subscription.Subscription{Status: subscription.StatusActive, GracePeriodEndsAt: &endsAt}
+	assert.False(t, active.IsPastDue())
+	assert.Equal(t, 0, active.DaysUntilCancellationAt(now))
+
+	noGrace := &subscription.Subscription{Status: subscription.StatusPastDue}
+	assert.Equal(t, 0, noGrace.DaysUntilCancellation())
+}
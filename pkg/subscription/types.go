@@ -77,7 +77,8 @@ const (
 
 // CheckoutOptions contains options for creating a checkout session.
 type CheckoutOptions struct {
-	Email      string // pre-fill billing email
-	SuccessURL string // redirect after successful payment
-	CancelURL  string // redirect if customer cancels
+	Email        string // pre-fill billing email
+	SuccessURL   string // redirect after successful payment
+	CancelURL    string // redirect if customer cancels
+	DiscountCode string // optional coupon pre-applied to the hosted checkout
 }
@@ -0,0 +1,70 @@
+package subscription
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// DefaultWebhookDedupTTL covers the retry windows of Paddle (3 days) and Stripe (3 days).
+const DefaultWebhookDedupTTL = 72 * time.Hour
+
+// WebhookDedupStore remembers processed provider event IDs.
+// Register it with WithWebhookDedup so redelivered webhooks are applied only once.
+type WebhookDedupStore interface {
+	// Seen reports whether eventID was already recorded and records it otherwise.
+	// The check and the write must be atomic so concurrent deliveries of the same
+	// event can't both return false. Recorded IDs may expire after a TTL.
+	Seen(ctx context.Context, eventID string) (bool, error)
+
+	// Forget removes eventID so a redelivery is processed again.
+	// HandleWebhook calls it when processing a recorded event fails.
+	Forget(ctx context.Context, eventID string) error
+}
+
+type inMemWebhookDedupStore struct {
+	mu     sync.Mutex
+	ttl    time.Duration
+	events map[string]time.Time // event ID -> expiration
+}
+
+// NewInMemWebhookDedupStore returns an in-memory WebhookDedupStore that forgets event IDs after ttl.
+// A non-positive ttl uses DefaultWebhookDedupTTL. Use a shared store (e.g. Redis SET NX EX)
+// when webhooks are handled by more than one instance.
+func NewInMemWebhookDedupStore(ttl time.Duration) WebhookDedupStore {
+	if ttl <= 0 {
+		ttl = DefaultWebhookDedupTTL
+	}
+	return &inMemWebhookDedupStore{
+		ttl:    ttl,
+		events: make(map[string]time.Time),
+	}
+}
+
+func (s *inMemWebhookDedupStore) Seen(_ context.Context, eventID string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if expiresAt, ok := s.events[eventID]; ok && now.Before(expiresAt) {
+		return true, nil
+	}
+
+	// Drop expired IDs on write so the map doesn't grow without bound
+	for id, expiresAt := range s.events {
+		if !now.Before(expiresAt) {
+			delete(s.events, id)
+		}
+	}
+
+	s.events[eventID] = now.Add(s.ttl)
+	return false, nil
+}
+
+func (s *inMemWebhookDedupStore) Forget(_ context.Context, eventID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.events, eventID)
+	return nil
+}
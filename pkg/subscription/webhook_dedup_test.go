@@ -0,0 +1,204 @@
+package subscription_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dmitrymomot/saaskit/pkg/subscription"
+)
+
+type failingDedupStore struct{}
+
+func (failingDedupStore) Seen(context.Context, string) (bool, error) {
+	return false, errors.New("redis unavailable")
+}
+
+func (failingDedupStore) Forget(context.Context, string) error {
+	return errors.New("redis unavailable")
+}
+
+func TestService_HandleWebhook_Dedup(t *testing.T) {
+	t.Parallel()
+
+	newService := func(t *testing.T, provider *mockProvider, store *mockStore, dedup subscription.WebhookDedupStore) subscription.Service {
+		t.Helper()
+		src := &mockPlansSource{}
+		src.On("Load", mock.Anything).Return(createTestPlans(), nil)
+		svc, err := subscription.NewService(context.Background(), src, provider, store, subscription.WithWebhookDedup(dedup))
+		require.NoError(t, err)
+		return svc
+	}
+
+	webhook := func(provider *mockProvider, event *subscription.WebhookEvent) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/webhook", bytes.NewReader([]byte(`{}`)))
+		provider.On("ParseWebhook", req).Return(event, nil).Once()
+		return req
+	}
+
+	createdEvent := func(eventID string, tenantID uuid.UUID) *subscription.WebhookEvent {
+		return &subscription.WebhookEvent{
+			EventID:        eventID,
+			Type:           subscription.EventSubscriptionCreated,
+			TenantID:       tenantID,
+			SubscriptionID: "sub_123",
+			PlanID:         "basic",
+			Status:         string(subscription.StatusActive),
+		}
+	}
+
+	t.Run("redelivered event is processed once", func(t *testing.T) {
+		t.Parallel()
+		tenantID := uuid.New()
+
+		provider := &mockProvider{}
+		store := &mockStore{}
+		store.On("Save", mock.Anything, mock.Anything).Return(nil).Once()
+		svc := newService(t, provider, store, subscription.NewInMemWebhookDedupStore(time.Hour))
+
+		require.NoError(t, svc.HandleWebhook(webhook(provider, createdEvent("evt_1", tenantID))))
+		require.NoError(t, svc.HandleWebhook(webhook(provider, createdEvent("evt_1", tenantID))))
+
+		store.AssertNumberOfCalls(t, "Save", 1)
+	})
+
+	t.Run("distinct events are all processed", func(t *testing.T) {
+		t.Parallel()
+		tenantID := uuid.New()
+
+		provider := &mockProvider{}
+		store := &mockStore{}
+		store.On("Save", mock.Anything, mock.Anything).Return(nil).Twice()
+		svc := newService(t, provider, store, subscription.NewInMemWebhookDedupStore(time.Hour))
+
+		require.NoError(t, svc.HandleWebhook(webhook(provider, createdEvent("evt_1", tenantID))))
+		require.NoError(t, svc.HandleWebhook(webhook(provider, createdEvent("evt_2", tenantID))))
+
+		store.AssertNumberOfCalls(t, "Save", 2)
+	})
+
+	t.Run("events without ID are not deduplicated", func(t *testing.T) {
+		t.Parallel()
+		tenantID := uuid.New()
+
+		provider := &mockProvider{}
+		store := &mockStore{}
+		store.On("Save", mock.Anything, mock.Anything).Return(nil).Twice()
+		svc := newService(t, provider, store, subscription.NewInMemWebhookDedupStore(time.Hour))
+
+		require.NoError(t, svc.HandleWebhook(webhook(provider, createdEvent("", tenantID))))
+		require.NoError(t, svc.HandleWebhook(webhook(provider, createdEvent("", tenantID))))
+
+		store.AssertNumberOfCalls(t, "Save", 2)
+	})
+
+	t.Run("failed event is applied on retry", func(t *testing.T) {
+		t.Parallel()
+		tenantID := uuid.New()
+
+		provider := &mockProvider{}
+		store := &mockStore{}
+		store.On("Save", mock.Anything, mock.Anything).Return(errors.New("db unavailable")).Once()
+		store.On("Save", mock.Anything, mock.Anything).Return(nil).Once()
+		svc := newService(t, provider, store, subscription.NewInMemWebhookDedupStore(time.Hour))
+
+		err := svc.HandleWebhook(webhook(provider, createdEvent("evt_1", tenantID)))
+		require.ErrorIs(t, err, subscription.ErrFailedToSaveSubscription)
+
+		require.NoError(t, svc.HandleWebhook(webhook(provider, createdEvent("evt_1", tenantID))))
+		require.NoError(t, svc.HandleWebhook(webhook(provider, createdEvent("evt_1", tenantID))))
+
+		store.AssertNumberOfCalls(t, "Save", 2)
+	})
+
+	t.Run("store error stops processing", func(t *testing.T) {
+		t.Parallel()
+
+		provider := &mockProvider{}
+		store := &mockStore{}
+		svc := newService(t, provider, store, failingDedupStore{})
+
+		err := svc.HandleWebhook(webhook(provider, createdEvent("evt_1", uuid.New())))
+		require.ErrorIs(t, err, subscription.ErrFailedToCheckWebhookDup)
+
+		store.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+	})
+}
+
+func TestInMemWebhookDedupStore(t *testing.T) {
+	t.Parallel()
+
+	t.Run("reports recorded IDs", func(t *testing.T) {
+		t.Parallel()
+		store := subscription.NewInMemWebhookDedupStore(time.Hour)
+
+		seen, err := store.Seen(context.Background(), "evt_1")
+		require.NoError(t, err)
+		assert.False(t, seen)
+
+		seen, err = store.Seen(context.Background(), "evt_1")
+		require.NoError(t, err)
+		assert.True(t, seen)
+	})
+
+	t.Run("forgets IDs after ttl", func(t *testing.T) {
+		t.Parallel()
+		store := subscription.NewInMemWebhookDedupStore(10 * time.Millisecond)
+
+		seen, err := store.Seen(context.Background(), "evt_1")
+		require.NoError(t, err)
+		assert.False(t, seen)
+
+		time.Sleep(20 * time.Millisecond)
+
+		seen, err = store.Seen(context.Background(), "evt_1")
+		require.NoError(t, err)
+		assert.False(t, seen)
+	})
+
+	t.Run("forgotten IDs are processed again", func(t *testing.T) {
+		t.Parallel()
+		store := subscription.NewInMemWebhookDedupStore(time.Hour)
+
+		_, err := store.Seen(context.Background(), "evt_1")
+		require.NoError(t, err)
+		require.NoError(t, store.Forget(context.Background(), "evt_1"))
+
+		seen, err := store.Seen(context.Background(), "evt_1")
+		require.NoError(t, err)
+		assert.False(t, seen)
+	})
+
+	t.Run("concurrent deliveries see one first", func(t *testing.T) {
+		t.Parallel()
+		store := subscription.NewInMemWebhookDedupStore(time.Hour)
+
+		var first atomic.Int32
+		var wg sync.WaitGroup
+		for range 20 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				seen, err := store.Seen(context.Background(), "evt_1")
+				assert.NoError(t, err)
+				if !seen {
+					first.Add(1)
+				}
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, int32(1), first.Load())
+	})
+}
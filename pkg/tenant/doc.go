@@ -71,9 +71,23 @@
 //   - ErrInactiveTenant: Tenant exists but is not active
 //   - ErrNoTenantInContext: Required tenant is missing from context
 //   - ErrInvalidIdentifier: Malformed tenant identifier
+//   - ErrImpersonationDenied: Impersonation credentials were rejected
 //
 // Custom error handlers can be configured to return appropriate HTTP responses.
 //
+// # Impersonation
+//
+// WithImpersonation lets support staff act as another tenant. It is disabled
+// by default; the authorizer verifies the caller's credentials and the hook
+// records every impersonated request for audit. FromContext returns the
+// impersonated tenant, while ImpersonationInfo reveals the real actor:
+//
+//	if info, ok := tenant.ImpersonationInfo(ctx); ok {
+//		log.Printf("%s acting as %s", info.Actor, info.Target.Name)
+//	}
+//
+// Impersonate creates such a context directly for non-HTTP code.
+//
 // # Security Considerations
 //
 // - Always validate tenant access in handlers for sensitive operations
@@ -14,4 +14,7 @@ var (
 
 	// ErrInactiveTenant is returned when trying to use an inactive tenant.
 	ErrInactiveTenant = errors.New("tenant is inactive")
+
+	// ErrImpersonationDenied is returned when impersonation credentials are rejected.
+	ErrImpersonationDenied = errors.New("tenant impersonation denied")
 )
@@ -0,0 +1,59 @@
+package tenant
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// Impersonation describes a support or admin session acting as another tenant.
+type Impersonation struct {
+	Actor      string    // real user behind the request, e.g. admin user ID or email
+	Target     *Tenant   // tenant being impersonated, also returned by FromContext
+	RealTenant *Tenant   // tenant resolved before impersonation, nil if none
+	StartedAt  time.Time // when the impersonated context was created
+}
+
+// ImpersonationAuthorizer inspects a request for impersonation credentials.
+// It returns an empty target when the request doesn't ask to impersonate,
+// and an error when credentials are present but not allowed; the error is
+// passed to the middleware error handler wrapped with ErrImpersonationDenied.
+type ImpersonationAuthorizer func(r *http.Request) (actor, targetIdentifier string, err error)
+
+// ImpersonationHook is called for every impersonated request, typically to write an audit record.
+type ImpersonationHook func(ctx context.Context, info Impersonation)
+
+type impersonationContextKey struct{}
+
+// Impersonate returns a context where FromContext yields target, while
+// ImpersonationInfo reveals the real actor. Use it for background jobs and
+// tooling; HTTP requests should go through WithImpersonation instead.
+func Impersonate(ctx context.Context, target *Tenant, actor string) context.Context {
+	info := Impersonation{
+		Actor:     actor,
+		Target:    target,
+		StartedAt: time.Now(),
+	}
+
+	// Nested impersonation keeps pointing at the original tenant
+	if current, ok := ImpersonationInfo(ctx); ok {
+		info.RealTenant = current.RealTenant
+	} else if real, ok := FromContext(ctx); ok {
+		info.RealTenant = real
+	}
+
+	ctx = context.WithValue(ctx, impersonationContextKey{}, info)
+	return WithTenant(ctx, target)
+}
+
+// ImpersonationInfo reports whether the context tenant is impersonated and by whom.
+func ImpersonationInfo(ctx context.Context) (Impersonation, bool) {
+	info, ok := ctx.Value(impersonationContextKey{}).(Impersonation)
+	return info, ok
+}
+
+// IsImpersonated reports whether the context tenant is impersonated.
+func IsImpersonated(ctx context.Context) bool {
+	_, ok := ImpersonationInfo(ctx)
+	return ok
+}
@@ -0,0 +1,175 @@
+package tenant_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dmitrymomot/saaskit/pkg/tenant"
+)
+
+func TestImpersonate(t *testing.T) {
+	t.Parallel()
+
+	real := createTestTenant("support", true)
+	target := createTestTenant("acme", true)
+
+	ctx := tenant.WithTenant(context.Background(), real)
+	assert.False(t, tenant.IsImpersonated(ctx))
+
+	ctx = tenant.Impersonate(ctx, target, "admin@example.com")
+
+	current, ok := tenant.FromContext(ctx)
+	require.True(t, ok)
+	assert.Equal(t, target, current)
+
+	info, ok := tenant.ImpersonationInfo(ctx)
+	require.True(t, ok)
+	assert.Equal(t, "admin@example.com", info.Actor)
+	assert.Equal(t, target, info.Target)
+	assert.Equal(t, real, info.RealTenant)
+	assert.False(t, info.StartedAt.IsZero())
+
+	t.Run("nested impersonation keeps the real tenant", func(t *testing.T) {
+		t.Parallel()
+		other := createTestTenant("globex", true)
+
+		nested := tenant.Impersonate(ctx, other, "admin@example.com")
+		info, ok := tenant.ImpersonationInfo(nested)
+		require.True(t, ok)
+		assert.Equal(t, other, info.Target)
+		assert.Equal(t, real, info.RealTenant)
+	})
+}
+
+func TestMiddleware_Impersonation(t *testing.T) {
+	t.Parallel()
+
+	// Admin requests carry X-Impersonate; only the "admin-token" bearer may use it
+	authorizer := func(r *http.Request) (string, string, error) {
+		target := r.Header.Get("X-Impersonate")
+		if target == "" {
+			return "", "", nil
+		}
+		if r.Header.Get("Authorization") != "Bearer admin-token" {
+			return "", "", errors.New("not an admin")
+		}
+		return "admin@example.com", target, nil
+	}
+
+	newRequest := func(headers map[string]string) *http.Request {
+		req := httptest.NewRequest(http.MethodGet, "/dashboard", nil)
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		return req
+	}
+
+	t.Run("swaps tenant and records actor", func(t *testing.T) {
+		t.Parallel()
+		own := createTestTenant("support", true)
+		target := createTestTenant("acme", false) // inactive tenants can still be inspected
+
+		provider := new(mockProvider)
+		provider.On("GetByIdentifier", mock.Anything, "support").Return(own, nil).Once()
+		provider.On("GetByIdentifier", mock.Anything, "acme").Return(target, nil).Once()
+
+		var audited []tenant.Impersonation
+		middleware := tenant.Middleware(tenant.NewHeaderResolver("X-Tenant-ID"), provider,
+			tenant.WithImpersonation(authorizer, func(_ context.Context, info tenant.Impersonation) {
+				audited = append(audited, info)
+			}),
+		)
+
+		handler := middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			current := tenant.MustFromContext(r.Context())
+			assert.Equal(t, target, current)
+
+			info, ok := tenant.ImpersonationInfo(r.Context())
+			require.True(t, ok)
+			assert.Equal(t, "admin@example.com", info.Actor)
+			assert.Equal(t, own, info.RealTenant)
+			w.WriteHeader(http.StatusOK)
+		}))
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, newRequest(map[string]string{
+			"X-Tenant-ID":   "support",
+			"X-Impersonate": "acme",
+			"Authorization": "Bearer admin-token",
+		}))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		require.Len(t, audited, 1)
+		assert.Equal(t, target, audited[0].Target)
+		provider.AssertExpectations(t)
+	})
+
+	t.Run("rejects unauthorized impersonation", func(t *testing.T) {
+		t.Parallel()
+		provider := new(mockProvider)
+
+		middleware := tenant.Middleware(tenant.NewHeaderResolver("X-Tenant-ID"), provider,
+			tenant.WithImpersonation(authorizer, nil),
+		)
+		handler := middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			t.Fatal("handler must not be called")
+		}))
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, newRequest(map[string]string{
+			"X-Impersonate": "acme",
+			"Authorization": "Bearer user-token",
+		}))
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		provider.AssertNotCalled(t, "GetByIdentifier", mock.Anything, "acme")
+	})
+
+	t.Run("ignores impersonation headers without opt-in", func(t *testing.T) {
+		t.Parallel()
+		own := createTestTenant("support", true)
+		provider := new(mockProvider)
+		provider.On("GetByIdentifier", mock.Anything, "support").Return(own, nil).Once()
+
+		middleware := tenant.Middleware(tenant.NewHeaderResolver("X-Tenant-ID"), provider)
+		handler := middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, own, tenant.MustFromContext(r.Context()))
+			assert.False(t, tenant.IsImpersonated(r.Context()))
+		}))
+
+		handler.ServeHTTP(httptest.NewRecorder(), newRequest(map[string]string{
+			"X-Tenant-ID":   "support",
+			"X-Impersonate": "acme",
+			"Authorization": "Bearer admin-token",
+		}))
+		provider.AssertExpectations(t)
+	})
+
+	t.Run("unknown target tenant", func(t *testing.T) {
+		t.Parallel()
+		provider := new(mockProvider)
+		provider.On("GetByIdentifier", mock.Anything, "missing").Return(nil, tenant.ErrTenantNotFound).Once()
+
+		middleware := tenant.Middleware(tenant.NewHeaderResolver("X-Tenant-ID"), provider,
+			tenant.WithImpersonation(authorizer, nil),
+		)
+		handler := middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			t.Fatal("handler must not be called")
+		}))
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, newRequest(map[string]string{
+			"X-Impersonate": "missing",
+			"Authorization": "Bearer admin-token",
+		}))
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
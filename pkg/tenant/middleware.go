@@ -1,13 +1,15 @@
 package tenant
 
 import (
+	"context"
+	"errors"
 	"log/slog"
 	"net/http"
 	"strings"
 )
 
 // Middleware extracts tenant information from requests and adds it to context.
-// Supports caching, path skipping, and configurable error handling.
+// Supports caching, path skipping, impersonation, and configurable error handling.
 func Middleware(resolver Resolver, provider Provider, opts ...Option) func(http.Handler) http.Handler {
 	cfg := &config{
 		cache:         &NoOpCache{},
@@ -36,48 +38,86 @@ func Middleware(resolver Resolver, provider Provider, opts ...Option) func(http.
 				return
 			}
 
-			// Allow requests without tenant identification
-			if identifier == "" {
-				next.ServeHTTP(w, r)
-				return
-			}
+			ctx := r.Context()
 
-			// Check cache to avoid database lookup
-			if cached, ok := cfg.cache.Get(r.Context(), identifier); ok {
-				if cfg.requireActive && !cached.Active {
-					cfg.errorHandler(w, r, ErrInactiveTenant)
+			// Requests without tenant identification are allowed through
+			if identifier != "" {
+				tenant, err := cfg.load(ctx, provider, identifier)
+				if err != nil {
+					cfg.errorHandler(w, r, err)
 					return
 				}
 
-				ctx := WithTenant(r.Context(), cached)
-				next.ServeHTTP(w, r.WithContext(ctx))
-				return
-			}
-
-			tenant, err := provider.GetByIdentifier(r.Context(), identifier)
-			if err != nil {
-				cfg.errorHandler(w, r, err)
-				return
-			}
+				if cfg.requireActive && !tenant.Active {
+					cfg.errorHandler(w, r, ErrInactiveTenant)
+					return
+				}
 
-			if cfg.requireActive && !tenant.Active {
-				cfg.errorHandler(w, r, ErrInactiveTenant)
-				return
+				ctx = WithTenant(ctx, tenant)
 			}
 
-			// Cache failures are logged but don't block requests
-			if err := cfg.cache.Set(r.Context(), identifier, tenant); err != nil {
-				cfg.logger.WarnContext(r.Context(), "failed to cache tenant",
-					"tenant_id", identifier,
-					"error", err)
+			if cfg.impersonationAuthorizer != nil {
+				ctx, err = cfg.impersonate(ctx, provider, r)
+				if err != nil {
+					cfg.errorHandler(w, r, err)
+					return
+				}
 			}
 
-			ctx := WithTenant(r.Context(), tenant)
 			next.ServeHTTP(w, r.WithContext(ctx))
 		})
 	}
 }
 
+// load returns the tenant from cache, falling back to the provider
+func (cfg *config) load(ctx context.Context, provider Provider, identifier string) (*Tenant, error) {
+	// Check cache to avoid database lookup
+	if cached, ok := cfg.cache.Get(ctx, identifier); ok {
+		return cached, nil
+	}
+
+	tenant, err := provider.GetByIdentifier(ctx, identifier)
+	if err != nil {
+		return nil, err
+	}
+
+	// Cache failures are logged but don't block requests
+	if err := cfg.cache.Set(ctx, identifier, tenant); err != nil {
+		cfg.logger.WarnContext(ctx, "failed to cache tenant",
+			"tenant_id", identifier,
+			"error", err)
+	}
+
+	return tenant, nil
+}
+
+// impersonate swaps the context tenant when the request carries authorized impersonation credentials
+func (cfg *config) impersonate(ctx context.Context, provider Provider, r *http.Request) (context.Context, error) {
+	actor, target, err := cfg.impersonationAuthorizer(r)
+	if err != nil {
+		return ctx, errors.Join(ErrImpersonationDenied, err)
+	}
+	if target == "" {
+		return ctx, nil
+	}
+	if actor == "" {
+		return ctx, errors.Join(ErrImpersonationDenied, errors.New("impersonation actor is required for audit"))
+	}
+
+	tenant, err := cfg.load(ctx, provider, target)
+	if err != nil {
+		return ctx, err
+	}
+
+	ctx = Impersonate(ctx, tenant, actor)
+	if cfg.impersonationHook != nil {
+		info, _ := ImpersonationInfo(ctx)
+		cfg.impersonationHook(ctx, info)
+	}
+
+	return ctx, nil
+}
+
 // RequireTenant ensures a tenant is present in context, useful for protecting tenant-only routes.
 func RequireTenant(errorHandler ErrorHandler) func(http.Handler) http.Handler {
 	if errorHandler == nil {
@@ -16,6 +16,9 @@ type config struct {
 	skipPaths     []string
 	requireActive bool
 	logger        *slog.Logger
+
+	impersonationAuthorizer ImpersonationAuthorizer
+	impersonationHook       ImpersonationHook
 }
 
 // Option configures the middleware.
@@ -56,6 +59,18 @@ func WithLogger(logger *slog.Logger) Option {
 	}
 }
 
+// WithImpersonation enables tenant impersonation; without it impersonation
+// credentials are ignored. The authorizer must verify that the caller is allowed
+// to impersonate, e.g. by checking an admin session. The hook receives every
+// impersonated request and should record it for audit. Impersonated tenants
+// are not required to be active, so support can inspect suspended accounts.
+func WithImpersonation(authorizer ImpersonationAuthorizer, hook ImpersonationHook) Option {
+	return func(c *config) {
+		c.impersonationAuthorizer = authorizer
+		c.impersonationHook = hook
+	}
+}
+
 func defaultErrorHandler(w http.ResponseWriter, r *http.Request, err error) {
 	switch {
 	case errors.Is(err, ErrTenantNotFound):
@@ -64,6 +79,8 @@ func defaultErrorHandler(w http.ResponseWriter, r *http.Request, err error) {
 		http.Error(w, "Tenant is inactive", http.StatusForbidden)
 	case errors.Is(err, ErrInvalidIdentifier):
 		http.Error(w, "Invalid tenant identifier", http.StatusBadRequest)
+	case errors.Is(err, ErrImpersonationDenied):
+		http.Error(w, "Impersonation denied", http.StatusForbidden)
 	default:
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
 	}
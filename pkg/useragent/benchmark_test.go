@@ -236,3 +236,38 @@ func BenchmarkGetShortIdentifier(b *testing.B) {
 		i++
 	}
 }
+
+// Benchmark Parser on distinct UAs, so every lookup misses and parses
+func BenchmarkParser_Miss(b *testing.B) {
+	p := useragent.NewParser(useragent.WithCacheSize(1))
+	uas := []string{chromeDesktopUA, safariMobileUA}
+	b.ReportAllocs()
+	b.ResetTimer()
+	i := 0
+	for b.Loop() {
+		result, err = p.Parse(uas[i%len(uas)])
+		i++
+	}
+}
+
+// Benchmark Parser with a warm cache, compare with BenchmarkParser_Miss
+func BenchmarkParser_Hit(b *testing.B) {
+	p := useragent.NewParser()
+	_, _ = p.Parse(chromeDesktopUA)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for b.Loop() {
+		result, err = p.Parse(chromeDesktopUA)
+	}
+}
+
+// Benchmark CachedParser with a warm cache
+func BenchmarkCachedParser_Hit(b *testing.B) {
+	p := useragent.NewCachedParser(16)
+	_, _ = p.Parse(chromeDesktopUA)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for b.Loop() {
+		result, err = p.Parse(chromeDesktopUA)
+	}
+}
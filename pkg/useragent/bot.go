@@ -0,0 +1,55 @@
+package useragent
+
+import "strings"
+
+// Bot category keyword sets, checked in botCategories order
+var (
+	aiBotKeywords         = newKeywordSet("gptbot", "chatgpt-user", "oai-searchbot", "claudebot", "claude-web", "anthropic-ai", "ccbot", "perplexitybot", "bytespider", "cohere-ai", "meta-externalagent", "amazonbot", "applebot-extended", "diffbot", "youbot")
+	monitoringBotKeywords = newKeywordSet("uptimerobot", "pingdom", "statuscake", "site24x7", "newrelicpinger", "datadog", "betteruptime", "uptime-kuma", "freshping", "hetrixtools", "checkly")
+	socialBotKeywords     = newKeywordSet("facebookexternalhit", "facebookcatalog", "facebot", "twitterbot", "linkedinbot", "slackbot", "slack-imgproxy", "discordbot", "telegrambot", "whatsapp", "pinterest", "redditbot", "skypeuripreview", "embedly", "mastodon")
+	searchBotKeywords     = newKeywordSet("googlebot", "bingbot", "yandexbot", "baiduspider", "duckduckbot", "applebot", "slurp", "sogou", "yeti", "seznambot", "petalbot", "qwantify", "exabot", "mojeekbot")
+)
+
+// AI crawlers are checked first since some reuse search engine names (Applebot-Extended)
+var botCategories = []struct {
+	category string
+	keywords keywordSet
+}{
+	{BotAI, aiBotKeywords},
+	{BotMonitoring, monitoringBotKeywords},
+	{BotSocial, socialBotKeywords},
+	{BotSearch, searchBotKeywords},
+}
+
+// ParseBotCategory classifies a lower-cased bot UA string as search, social,
+// monitoring or AI. Bots that match no curated keyword set return BotGeneric.
+// The UA is assumed to be a bot, see ParseDeviceType.
+func ParseBotCategory(lowerUA string) string {
+	for _, c := range botCategories {
+		if c.keywords.contains(lowerUA) {
+			return c.category
+		}
+	}
+	return BotGeneric
+}
+
+// BotCategory returns the bot category (search, social, monitoring, ai or generic)
+// for rate-limiting bots per category. Returns an empty string for non-bots.
+func (ua UserAgent) BotCategory() string {
+	if !ua.IsBot() {
+		return ""
+	}
+	return ParseBotCategory(strings.ToLower(ua.userAgent))
+}
+
+// BotName returns the crawler name, e.g. "Googlebot" or "GPTBot", or an empty
+// string for non-bots and bots that can't be identified.
+func (ua UserAgent) BotName() string {
+	if !ua.IsBot() {
+		return ""
+	}
+	if name := extractBotName(ua.userAgent); name != "Unknown Bot" {
+		return name
+	}
+	return ""
+}
@@ -0,0 +1,107 @@
+package useragent_test
+
+import (
+	"testing"
+
+	"github.com/dmitrymomot/saaskit/pkg/useragent"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestBotCategory tests bot classification and name extraction with real UA samples
+func TestBotCategory(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		ua       string
+		category string
+		botName  string
+	}{
+		{
+			name:     "Googlebot",
+			ua:       "Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)",
+			category: useragent.BotSearch,
+			botName:  "Googlebot",
+		},
+		{
+			name:     "Bingbot",
+			ua:       "Mozilla/5.0 (compatible; bingbot/2.0; +http://www.bing.com/bingbot.htm)",
+			category: useragent.BotSearch,
+			botName:  "Bingbot",
+		},
+		{
+			name:     "Facebook link preview",
+			ua:       "facebookexternalhit/1.1 (+http://www.facebook.com/externalhit_uatext.php)",
+			category: useragent.BotSocial,
+			botName:  "Facebook",
+		},
+		{
+			name:     "Twitterbot",
+			ua:       "Twitterbot/1.0",
+			category: useragent.BotSocial,
+			botName:  "Twitterbot",
+		},
+		{
+			name:     "UptimeRobot",
+			ua:       "Mozilla/5.0+(compatible; UptimeRobot/2.0; http://www.uptimerobot.com/)",
+			category: useragent.BotMonitoring,
+			botName:  "UptimeRobot",
+		},
+		{
+			name:     "Pingdom",
+			ua:       "Pingdom.com_bot_version_1.4_(http://www.pingdom.com/)",
+			category: useragent.BotMonitoring,
+			botName:  "Pingdom",
+		},
+		{
+			name:     "GPTBot",
+			ua:       "Mozilla/5.0 AppleWebKit/537.36 (KHTML, like Gecko; compatible; GPTBot/1.0; +https://openai.com/gptbot)",
+			category: useragent.BotAI,
+			botName:  "GPTBot",
+		},
+		{
+			name:     "ChatGPT-User",
+			ua:       "Mozilla/5.0 AppleWebKit/537.36 (KHTML, like Gecko); compatible; ChatGPT-User/1.0; +https://openai.com/bot",
+			category: useragent.BotAI,
+			botName:  "ChatGPT-User",
+		},
+		{
+			name:     "ClaudeBot",
+			ua:       "Mozilla/5.0 AppleWebKit/537.36 (KHTML, like Gecko; compatible; ClaudeBot/1.0; +claudebot@anthropic.com)",
+			category: useragent.BotAI,
+			botName:  "ClaudeBot",
+		},
+		{
+			name:     "CCBot",
+			ua:       "CCBot/2.0 (https://commoncrawl.org/faq/)",
+			category: useragent.BotAI,
+			botName:  "CCBot",
+		},
+		{
+			name:     "Generic bot",
+			ua:       "Mozilla/5.0 (compatible; SemrushBot/7~bl; +http://www.semrush.com/bot.html)",
+			category: useragent.BotGeneric,
+			botName:  "Semrushbot",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			ua, err := useragent.Parse(tc.ua)
+			require.NoError(t, err)
+			assert.True(t, ua.IsBot())
+			assert.Equal(t, tc.category, ua.BotCategory())
+			assert.Equal(t, tc.botName, ua.BotName())
+		})
+	}
+
+	t.Run("non-bot", func(t *testing.T) {
+		t.Parallel()
+		ua, err := useragent.Parse("Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36")
+		require.NoError(t, err)
+		assert.Empty(t, ua.BotCategory())
+		assert.Empty(t, ua.BotName())
+	})
+}
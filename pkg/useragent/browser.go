@@ -207,3 +207,48 @@ func ParseBrowser(lowerUA string) Browser {
 		Version: "",
 	}
 }
+
+// ParseEngine identifies the layout engine and its version from a lower-cased UA string.
+// Chromium-based browsers report Blink with the Chrome version, since Blink versions
+// track Chromium. Every iOS browser must use WebKit, so iOS always reports WebKit.
+// Legacy Edge reports EdgeHTML and Firefox reports Gecko with its rv: version, as the
+// Gecko/ token is frozen. Scans tokens without regexes, so it doesn't allocate.
+func ParseEngine(lowerUA string) (engine, version string) {
+	switch {
+	case strings.Contains(lowerUA, "trident/"):
+		return EngineTrident, tokenVersion(lowerUA, "trident/")
+	case strings.Contains(lowerUA, "edge/"):
+		return EngineEdgeHTML, tokenVersion(lowerUA, "edge/")
+	case iOSKeywords.contains(lowerUA) && strings.Contains(lowerUA, "applewebkit/"):
+		return EngineWebKit, tokenVersion(lowerUA, "applewebkit/")
+	case strings.Contains(lowerUA, "chrome/"):
+		version = tokenVersion(lowerUA, "chrome/")
+		// Chrome forked Blink from WebKit in version 28
+		if leadingInt(version) < 28 {
+			return EngineWebKit, tokenVersion(lowerUA, "applewebkit/")
+		}
+		return EngineBlink, version
+	case strings.Contains(lowerUA, "applewebkit/"):
+		return EngineWebKit, tokenVersion(lowerUA, "applewebkit/")
+	case strings.Contains(lowerUA, "presto/"):
+		return EnginePresto, tokenVersion(lowerUA, "presto/")
+	case strings.Contains(lowerUA, "gecko/"):
+		return EngineGecko, tokenVersion(lowerUA, "rv:")
+	}
+	return EngineUnknown, ""
+}
+
+// tokenVersion returns the dotted version following token, e.g. "605.1.15" for
+// "applewebkit/" in "applewebkit/605.1.15 (khtml". The result is a substring of ua.
+func tokenVersion(ua, token string) string {
+	i := strings.Index(ua, token)
+	if i < 0 {
+		return ""
+	}
+	rest := ua[i+len(token):]
+	end := 0
+	for end < len(rest) && (rest[end] == '.' || (rest[end] >= '0' && rest[end] <= '9')) {
+		end++
+	}
+	return strings.TrimRight(rest[:end], ".")
+}
@@ -1,11 +1,13 @@
 package useragent_test
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/dmitrymomot/saaskit/pkg/useragent"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // TestBrowserInfo tests the BrowserInfo method
@@ -74,3 +76,100 @@ func TestBrowserInfo(t *testing.T) {
 }
 
 // Additional tests for Browser parsing are already in useragent_test.go (TestParseBrowser)
+
+// TestParseEngine tests layout engine detection
+func TestParseEngine(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		ua      string
+		engine  string
+		version string
+	}{
+		{
+			name:    "Chrome",
+			ua:      "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.6099.130 Safari/537.36",
+			engine:  useragent.EngineBlink,
+			version: "120.0.6099.130",
+		},
+		{
+			name:    "Chromium-based Edge",
+			ua:      "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 Edg/120.0.2210.91",
+			engine:  useragent.EngineBlink,
+			version: "120.0.0.0",
+		},
+		{
+			name:    "Legacy Edge",
+			ua:      "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/70.0.3538.102 Safari/537.36 Edge/18.19041",
+			engine:  useragent.EngineEdgeHTML,
+			version: "18.19041",
+		},
+		{
+			name:    "Safari",
+			ua:      "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.0 Safari/605.1.15",
+			engine:  useragent.EngineWebKit,
+			version: "605.1.15",
+		},
+		{
+			name:    "Chrome on iOS",
+			ua:      "Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) CriOS/120.0.6099.119 Mobile/15E148 Safari/604.1",
+			engine:  useragent.EngineWebKit,
+			version: "605.1.15",
+		},
+		{
+			name:    "Old Chrome before Blink",
+			ua:      "Mozilla/5.0 (Windows NT 6.1) AppleWebKit/537.11 (KHTML, like Gecko) Chrome/23.0.1271.97 Safari/537.11",
+			engine:  useragent.EngineWebKit,
+			version: "537.11",
+		},
+		{
+			name:    "Firefox",
+			ua:      "Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:121.0) Gecko/20100101 Firefox/121.0",
+			engine:  useragent.EngineGecko,
+			version: "121.0",
+		},
+		{
+			name:    "Internet Explorer 11",
+			ua:      "Mozilla/5.0 (Windows NT 10.0; WOW64; Trident/7.0; rv:11.0) like Gecko",
+			engine:  useragent.EngineTrident,
+			version: "7.0",
+		},
+		{
+			name:    "Opera Presto",
+			ua:      "Opera/9.80 (Windows NT 6.1; WOW64) Presto/2.12.388 Version/12.18",
+			engine:  useragent.EnginePresto,
+			version: "2.12.388",
+		},
+		{
+			name:   "Unknown",
+			ua:     "curl/8.4.0",
+			engine: useragent.EngineUnknown,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			engine, version := useragent.ParseEngine(strings.ToLower(tc.ua))
+			assert.Equal(t, tc.engine, engine)
+			assert.Equal(t, tc.version, version)
+		})
+	}
+
+	t.Run("populated by Parse", func(t *testing.T) {
+		t.Parallel()
+		ua, err := useragent.Parse(tests[0].ua)
+		require.NoError(t, err)
+		assert.Equal(t, useragent.EngineBlink, ua.Engine())
+		assert.Equal(t, "120.0.6099.130", ua.EngineVersion())
+	})
+}
+
+// TestParseEngine_NoAllocs guards the zero-allocation path for lower-cased input
+func TestParseEngine_NoAllocs(t *testing.T) {
+	lowerUA := "mozilla/5.0 (macintosh; intel mac os x 10_15_7) applewebkit/605.1.15 (khtml, like gecko) version/17.0 safari/605.1.15"
+	allocs := testing.AllocsPerRun(100, func() {
+		_, _ = useragent.ParseEngine(lowerUA)
+	})
+	assert.Zero(t, allocs)
+}
@@ -0,0 +1,110 @@
+package useragent
+
+import (
+	"sync/atomic"
+
+	"github.com/dmitrymomot/saaskit/pkg/cache"
+)
+
+// DefaultCacheCapacity fits the few hundred distinct UA strings a typical site sees.
+const DefaultCacheCapacity = 1024
+
+// CacheStats reports cache effectiveness of a CachedParser.
+type CacheStats struct {
+	Hits   uint64
+	Misses uint64
+	Size   int
+}
+
+// HitRate returns the share of lookups served from cache in the range [0, 1].
+func (s CacheStats) HitRate() float64 {
+	total := s.Hits + s.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(total)
+}
+
+type parseResult struct {
+	ua  UserAgent
+	err error
+}
+
+// CachedParser memoizes Parse results in an LRU keyed by the raw UA string.
+// Real traffic has low UA cardinality, so most lookups skip parsing entirely.
+// Parse errors are cached too, so repeated junk UAs stay cheap.
+// Safe for concurrent use.
+type CachedParser struct {
+	cache  *cache.LRUCache[string, parseResult]
+	hits   atomic.Uint64
+	misses atomic.Uint64
+}
+
+// NewCachedParser creates a parser caching up to capacity distinct UA strings.
+// Non-positive capacity falls back to DefaultCacheCapacity.
+func NewCachedParser(capacity int) *CachedParser {
+	if capacity <= 0 {
+		capacity = DefaultCacheCapacity
+	}
+	return &CachedParser{
+		cache: cache.NewLRUCache[string, parseResult](capacity),
+	}
+}
+
+// Parser is a reusable, concurrency-safe parser with an LRU of parse results.
+// It's the same type as CachedParser, constructed with options.
+type Parser = CachedParser
+
+// ParserOption configures a Parser.
+type ParserOption func(*parserConfig)
+
+type parserConfig struct {
+	cacheSize int
+}
+
+// WithCacheSize sets how many distinct UA strings the Parser caches.
+// Non-positive sizes fall back to DefaultCacheCapacity.
+func WithCacheSize(size int) ParserOption {
+	return func(c *parserConfig) {
+		c.cacheSize = size
+	}
+}
+
+// NewParser creates a Parser caching up to DefaultCacheCapacity UA strings
+// unless configured otherwise. The package-level Parse stays uncached.
+func NewParser(opts ...ParserOption) *Parser {
+	cfg := parserConfig{cacheSize: DefaultCacheCapacity}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return NewCachedParser(cfg.cacheSize)
+}
+
+// Parse returns the cached result for ua or parses and caches it.
+func (p *CachedParser) Parse(ua string) (UserAgent, error) {
+	if res, ok := p.cache.Get(ua); ok {
+		p.hits.Add(1)
+		return res.ua, res.err
+	}
+
+	p.misses.Add(1)
+	parsed, err := Parse(ua)
+	p.cache.Put(ua, parseResult{ua: parsed, err: err})
+	return parsed, err
+}
+
+// Stats returns hit/miss counters and the current number of cached entries.
+func (p *CachedParser) Stats() CacheStats {
+	return CacheStats{
+		Hits:   p.hits.Load(),
+		Misses: p.misses.Load(),
+		Size:   p.cache.Len(),
+	}
+}
+
+// Reset drops all cached entries and zeroes the counters.
+func (p *CachedParser) Reset() {
+	p.cache.Clear()
+	p.hits.Store(0)
+	p.misses.Store(0)
+}
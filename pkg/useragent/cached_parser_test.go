@@ -0,0 +1,128 @@
+package useragent_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/dmitrymomot/saaskit/pkg/useragent"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	chromeUA  = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"
+	iphoneUA  = "Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.0 Mobile/15E148 Safari/604.1"
+	firefoxUA = "Mozilla/5.0 (X11; Linux x86_64; rv:121.0) Gecko/20100101 Firefox/121.0"
+)
+
+func TestCachedParser(t *testing.T) {
+	t.Parallel()
+
+	t.Run("returns same result as Parse", func(t *testing.T) {
+		t.Parallel()
+		p := useragent.NewCachedParser(10)
+
+		want, err := useragent.Parse(chromeUA)
+		require.NoError(t, err)
+
+		for range 3 {
+			got, err := p.Parse(chromeUA)
+			require.NoError(t, err)
+			assert.Equal(t, want, got)
+		}
+
+		stats := p.Stats()
+		assert.Equal(t, uint64(2), stats.Hits)
+		assert.Equal(t, uint64(1), stats.Misses)
+		assert.Equal(t, 1, stats.Size)
+		assert.InDelta(t, 2.0/3.0, stats.HitRate(), 0.0001)
+	})
+
+	t.Run("caches errors", func(t *testing.T) {
+		t.Parallel()
+		p := useragent.NewCachedParser(10)
+
+		_, err := p.Parse("")
+		require.ErrorIs(t, err, useragent.ErrEmptyUserAgent)
+		_, err = p.Parse("")
+		require.ErrorIs(t, err, useragent.ErrEmptyUserAgent)
+
+		assert.Equal(t, uint64(1), p.Stats().Hits)
+	})
+
+	t.Run("evicts least recently used", func(t *testing.T) {
+		t.Parallel()
+		p := useragent.NewCachedParser(2)
+
+		_, _ = p.Parse(chromeUA)
+		_, _ = p.Parse(iphoneUA)
+		_, _ = p.Parse(chromeUA)
+		_, _ = p.Parse(firefoxUA) // evicts iphoneUA
+		_, _ = p.Parse(iphoneUA)
+
+		stats := p.Stats()
+		assert.Equal(t, 2, stats.Size)
+		assert.Equal(t, uint64(1), stats.Hits)
+		assert.Equal(t, uint64(4), stats.Misses)
+	})
+
+	t.Run("reset clears cache and stats", func(t *testing.T) {
+		t.Parallel()
+		p := useragent.NewCachedParser(0)
+		_, _ = p.Parse(chromeUA)
+		p.Reset()
+
+		assert.Equal(t, useragent.CacheStats{}, p.Stats())
+		assert.Zero(t, p.Stats().HitRate())
+	})
+
+	t.Run("concurrent use", func(t *testing.T) {
+		t.Parallel()
+		p := useragent.NewCachedParser(10)
+		uas := []string{chromeUA, iphoneUA, firefoxUA}
+
+		var wg sync.WaitGroup
+		for i := range 50 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := p.Parse(uas[i%len(uas)])
+				assert.NoError(t, err)
+			}()
+		}
+		wg.Wait()
+
+		stats := p.Stats()
+		assert.Equal(t, uint64(50), stats.Hits+stats.Misses)
+		assert.Equal(t, 3, stats.Size)
+	})
+}
+
+func TestNewParser(t *testing.T) {
+	t.Parallel()
+
+	t.Run("default cache size", func(t *testing.T) {
+		t.Parallel()
+		p := useragent.NewParser()
+
+		want, err := useragent.Parse(iphoneUA)
+		require.NoError(t, err)
+		got, err := p.Parse(iphoneUA)
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+	})
+
+	t.Run("configured cache size", func(t *testing.T) {
+		t.Parallel()
+		p := useragent.NewParser(useragent.WithCacheSize(1))
+
+		_, _ = p.Parse(chromeUA)
+		_, _ = p.Parse(iphoneUA) // evicts chromeUA
+		_, _ = p.Parse(chromeUA)
+
+		stats := p.Stats()
+		assert.Equal(t, 1, stats.Size)
+		assert.Equal(t, uint64(3), stats.Misses)
+	})
+}
@@ -24,6 +24,54 @@ const (
 	DeviceTypeUnknown = "unknown"
 )
 
+// Bot categories distinguish crawlers for per-category policies, see UserAgent.BotCategory
+const (
+	// BotSearch identifies search engine crawlers (Googlebot, Bingbot, etc.)
+	BotSearch = "search"
+
+	// BotSocial identifies link preview fetchers of social networks and messengers
+	BotSocial = "social"
+
+	// BotMonitoring identifies uptime and synthetic monitoring tools
+	BotMonitoring = "monitoring"
+
+	// BotAI identifies AI training crawlers and assistant fetchers (GPTBot, ClaudeBot, etc.)
+	BotAI = "ai"
+
+	// BotGeneric is used for bots that don't belong to a known category
+	BotGeneric = "generic"
+)
+
+// Form factors refine device types for responsive decisions, see UserAgent.FormFactor
+const (
+	// FormFactorPhone identifies regular smartphones
+	FormFactorPhone = "phone"
+
+	// FormFactorPhablet identifies large-screen phones (Galaxy Note, Redmi Note, etc.)
+	FormFactorPhablet = "phablet"
+
+	// FormFactorTablet identifies tablets
+	FormFactorTablet = "tablet"
+
+	// FormFactorLaptop identifies laptops, reported only when the UA implies one (Chromebooks)
+	FormFactorLaptop = "laptop"
+
+	// FormFactorDesktop identifies desktop-class computers, including laptops the UA can't tell apart
+	FormFactorDesktop = "desktop"
+
+	// FormFactorTV identifies smart TVs and streaming devices
+	FormFactorTV = "tv"
+
+	// FormFactorWatch identifies smartwatches
+	FormFactorWatch = "watch"
+
+	// FormFactorConsole identifies gaming consoles
+	FormFactorConsole = "console"
+
+	// FormFactorUnknown is used when there are no reliable signals, including for bots
+	FormFactorUnknown = "unknown"
+)
+
 // Mobile device model identifiers
 const (
 	// MobileDeviceIPhone identifies Apple iPhone devices
@@ -75,6 +123,51 @@ const (
 	TabletDeviceUnknown = "unknown"
 )
 
+// Device vendors, see UserAgent.Vendor
+const (
+	// VendorApple identifies Apple (iPhone, iPad) devices
+	VendorApple = "apple"
+
+	// VendorSamsung identifies Samsung devices
+	VendorSamsung = "samsung"
+
+	// VendorGoogle identifies Google (Pixel, Nexus) devices
+	VendorGoogle = "google"
+
+	// VendorXiaomi identifies Xiaomi (Redmi, POCO) devices
+	VendorXiaomi = "xiaomi"
+
+	// VendorHuawei identifies Huawei and Honor devices
+	VendorHuawei = "huawei"
+
+	// VendorOppo identifies Oppo devices
+	VendorOppo = "oppo"
+
+	// VendorRealme identifies Realme devices
+	VendorRealme = "realme"
+
+	// VendorVivo identifies Vivo devices
+	VendorVivo = "vivo"
+
+	// VendorOnePlus identifies OnePlus devices
+	VendorOnePlus = "oneplus"
+
+	// VendorMotorola identifies Motorola devices
+	VendorMotorola = "motorola"
+
+	// VendorNokia identifies Nokia devices
+	VendorNokia = "nokia"
+
+	// VendorLG identifies LG devices
+	VendorLG = "lg"
+
+	// VendorSony identifies Sony devices
+	VendorSony = "sony"
+
+	// VendorAmazon identifies Amazon (Fire tablets and Fire TV) devices
+	VendorAmazon = "amazon"
+)
+
 // Browser name identifiers
 const (
 	// BrowserChrome identifies Google Chrome browser
@@ -126,6 +219,30 @@ const (
 	BrowserUnknown = "unknown"
 )
 
+// Layout engine identifiers
+const (
+	// EngineBlink identifies the Blink engine (Chrome, Chromium-based Edge, Opera, etc.)
+	EngineBlink = "blink"
+
+	// EngineWebKit identifies the WebKit engine (Safari and every iOS browser)
+	EngineWebKit = "webkit"
+
+	// EngineGecko identifies the Gecko engine (Firefox)
+	EngineGecko = "gecko"
+
+	// EngineTrident identifies the Trident engine (Internet Explorer)
+	EngineTrident = "trident"
+
+	// EngineEdgeHTML identifies the EdgeHTML engine (legacy Edge)
+	EngineEdgeHTML = "edgehtml"
+
+	// EnginePresto identifies the Presto engine (legacy Opera)
+	EnginePresto = "presto"
+
+	// EngineUnknown is used when the engine cannot be determined
+	EngineUnknown = "unknown"
+)
+
 // Operating system identifiers
 const (
 	// OSWindows identifies Microsoft Windows operating system
@@ -155,6 +272,18 @@ const (
 	// OSFireOS identifies Amazon Fire OS operating system
 	OSFireOS = "fireos"
 
+	// OSTvOS identifies Apple tvOS operating system
+	OSTvOS = "tvos"
+
+	// OSTizen identifies Samsung Tizen operating system
+	OSTizen = "tizen"
+
+	// OSWebOS identifies LG webOS operating system
+	OSWebOS = "webos"
+
+	// OSRoku identifies Roku OS operating system
+	OSRoku = "roku"
+
 	// OSUnknown is used when the operating system cannot be determined
 	OSUnknown = "unknown"
 )
@@ -1,6 +1,7 @@
 package useragent
 
 import (
+	"regexp"
 	"strings"
 )
 
@@ -27,13 +28,18 @@ func (k keywordSet) contains(s string) bool {
 // Keyword sets organized by device type for efficient classification.
 // Bot detection includes social media crawlers and monitoring tools.
 var (
-	botKeywords     = newKeywordSet("bot", "spider", "crawler", "archiver", "ping", "lighthouse", "slurp", "daum", "sogou", "yeti", "facebook", "twitter", "slack", "linkedin", "whatsapp", "telegram", "discord", "camo asset", "generator", "monitor", "analyzer", "validator", "fetcher", "scraper", "check")
+	botKeywords     = newKeywordSet("bot", "spider", "crawler", "archiver", "ping", "lighthouse", "slurp", "daum", "sogou", "yeti", "facebook", "twitter", "slack", "linkedin", "whatsapp", "telegram", "discord", "camo asset", "generator", "monitor", "analyzer", "validator", "fetcher", "scraper", "check", "chatgpt-user", "anthropic-ai", "claude-web", "cohere-ai", "statuscake", "site24x7", "uptime-kuma", "synthetics")
 	tvKeywords      = newKeywordSet("tv", "appletv", "smarttv", "googletv", "android tv", "webos", "tizen")
 	consoleKeywords = newKeywordSet("playstation", "xbox", "nintendo", "wiiu", "switch")
 	tabletKeywords  = newKeywordSet("tablet", "kindle", "silk")
 	mobileKeywords  = newKeywordSet("mobile", "iphone", "android", "windows phone", "iemobile", "blackberry", "nokia")
 	desktopKeywords = newKeywordSet("windows", "macintosh", "mac os x", "linux", "x11", "ubuntu", "fedora", "debian", "chromeos", "cros")
 
+	// Connected TV and streaming device markers, specific enough to be checked before
+	// Android and mobile detection: Fire TV (AFT* models) and Android TV boxes report
+	// Android and often "Mobile Safari". Tizen and webOS phones are left to tvKeywords.
+	ctvKeywords = newKeywordSet("roku", "appletv", "apple tv", "tvos", "; aft", "crkey", "androidtv", "android tv", "googletv", "google tv", "bravia", "smart-tv", "smarttv", "web0s", "netcast", "hbbtv")
+
 	// Mobile device brand detection based on common UA patterns
 	samsungMobileWords = newKeywordSet("samsung", "sm-g", "sm-a", "sm-n", "samsungbrowser")
 	huaweiMobileWords  = newKeywordSet("huawei", "hwa-", "honor", "h60-", "h30-")
@@ -67,6 +73,11 @@ func ParseDeviceType(lowerUA string) string {
 		return DeviceTypeBot
 	}
 
+	// Consoles may mention TV apps, so they keep their own class
+	if ctvKeywords.contains(lowerUA) && !consoleKeywords.contains(lowerUA) {
+		return DeviceTypeTV
+	}
+
 	// Android tablets omit 'Mobile' keyword, unlike phones
 	if strings.Contains(lowerUA, "android") {
 		if !strings.Contains(lowerUA, "mobile") {
@@ -181,3 +192,116 @@ func GetDeviceModel(lowerUA, deviceType string) string {
 
 	return ""
 }
+
+// Android UAs carry the model before "Build/", or as the last token after the
+// Android version in reduced and WebView-less UAs: "(Linux; Android 14; Pixel 8)"
+var (
+	androidBuildModelRegex = regexp.MustCompile(`(?i);\s*([^;()]+?)\s+build/`)
+	androidModelRegex      = regexp.MustCompile(`(?i)android[^;)]*;\s*([^;)]+?)\s*\)`)
+)
+
+// placeholderModels are tokens found in the model position that aren't models.
+// Chrome's reduced UA always reports "K".
+var placeholderModels = newKeywordSet("k", "u", "wv", "mobile", "tablet")
+
+// vendorPrefixes map lower-cased model prefixes to vendors, checked in order
+var vendorPrefixes = []struct {
+	prefix string
+	vendor string
+}{
+	{"samsung", VendorSamsung},
+	{"sm-", VendorSamsung},
+	{"gt-", VendorSamsung},
+	{"sgh-", VendorSamsung},
+	{"sch-", VendorSamsung},
+	{"pixel", VendorGoogle},
+	{"nexus", VendorGoogle},
+	{"redmi", VendorXiaomi},
+	{"poco", VendorXiaomi},
+	{"mi ", VendorXiaomi},
+	{"xiaomi", VendorXiaomi},
+	{"huawei", VendorHuawei},
+	{"honor", VendorHuawei},
+	{"cph", VendorOppo},
+	{"oppo", VendorOppo},
+	{"rmx", VendorRealme},
+	{"vivo", VendorVivo},
+	{"oneplus", VendorOnePlus},
+	{"moto", VendorMotorola},
+	{"xt1", VendorMotorola},
+	{"nokia", VendorNokia},
+	{"lm-", VendorLG},
+	{"lg-", VendorLG},
+	{"sony", VendorSony},
+	{"xq-", VendorSony},
+	{"kf", VendorAmazon},
+	{"aft", VendorAmazon},
+}
+
+// ParseModel extracts the device model and vendor from a UA string in its original
+// case, e.g. "SM-G991B" and "samsung" for Android, or "iPhone" and "apple" for iOS.
+// Vendors of unknown model prefixes fall back to brand keywords in the UA.
+// Desktop UAs carry no model, so both are empty rather than guessed.
+func ParseModel(ua string) (model, vendor string) {
+	lowerUA := strings.ToLower(ua)
+
+	switch {
+	case strings.Contains(lowerUA, "ipad"):
+		return "iPad", VendorApple
+	case strings.Contains(lowerUA, "iphone"):
+		return "iPhone", VendorApple
+	case strings.Contains(lowerUA, "ipod"):
+		return "iPod", VendorApple
+	case !strings.Contains(lowerUA, "android"):
+		return "", ""
+	}
+
+	model = extractModel(ua, androidBuildModelRegex)
+	if model == "" {
+		model = extractModel(ua, androidModelRegex)
+	}
+	if _, ok := placeholderModels[strings.ToLower(model)]; ok {
+		model = ""
+	}
+
+	return model, parseVendor(strings.ToLower(model), lowerUA)
+}
+
+// maxModelLength bounds models taken from untrusted UA strings
+const maxModelLength = 64
+
+// extractModel returns the model captured by regex, trimmed and length-capped
+func extractModel(ua string, regex *regexp.Regexp) string {
+	matches := regex.FindStringSubmatch(ua)
+	if len(matches) < 2 {
+		return ""
+	}
+	model := strings.TrimSpace(matches[1])
+	if len(model) > maxModelLength {
+		model = model[:maxModelLength]
+	}
+	return model
+}
+
+// parseVendor maps a lower-cased model to its vendor, falling back to brand keywords
+func parseVendor(lowerModel, lowerUA string) string {
+	if lowerModel != "" {
+		for _, p := range vendorPrefixes {
+			if strings.HasPrefix(lowerModel, p.prefix) {
+				return p.vendor
+			}
+		}
+	}
+
+	switch {
+	case samsungMobileWords.contains(lowerUA) || samsungTabletWords.contains(lowerUA):
+		return VendorSamsung
+	case huaweiMobileWords.contains(lowerUA) || huaweiTabletWords.contains(lowerUA):
+		return VendorHuawei
+	case strings.Contains(lowerUA, "xiaomi") || strings.Contains(lowerUA, "miui"):
+		return VendorXiaomi
+	case kindleWords.contains(lowerUA):
+		return VendorAmazon
+	}
+	return ""
+}
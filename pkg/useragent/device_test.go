@@ -6,6 +6,7 @@ import (
 	"github.com/dmitrymomot/saaskit/pkg/useragent"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // TestGetDeviceModel tests the GetDeviceModel function with various devices
@@ -165,7 +166,7 @@ func TestParseDeviceType(t *testing.T) {
 		{
 			name:     "Smart TV",
 			ua:       "mozilla/5.0 (linux; android tv; sm-t970) applewebkit/537.36 (khtml, like gecko) chrome/91.0.4472.120 safari/537.36",
-			expected: useragent.DeviceTypeTablet, // It's being detected as a tablet based on current implementation
+			expected: useragent.DeviceTypeTV,
 		},
 		{
 			name:     "Game Console",
@@ -192,3 +193,224 @@ func TestParseDeviceType(t *testing.T) {
 		})
 	}
 }
+
+// TestParse_ConnectedTV tests smart TV and streaming device detection with real UA samples
+func TestParse_ConnectedTV(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name       string
+		ua         string
+		deviceType string
+		os         string
+		osVersion  string
+		identifier string
+	}{
+		{
+			name:       "Roku",
+			ua:         "Roku/DVP-9.10 (519.10E04111A)",
+			deviceType: useragent.DeviceTypeTV,
+			os:         useragent.OSRoku,
+			identifier: "Roku tv",
+		},
+		{
+			name:       "Roku with model",
+			ua:         "Roku4640X/DVP-7.70 (297.70E04154A)",
+			deviceType: useragent.DeviceTypeTV,
+			os:         useragent.OSRoku,
+			identifier: "Roku tv",
+		},
+		{
+			name:       "Apple TV media player",
+			ua:         "AppleCoreMedia/1.0.0.20K71 (Apple TV; U; CPU OS 16_1 like Mac OS X; en_us)",
+			deviceType: useragent.DeviceTypeTV,
+			os:         useragent.OSTvOS,
+			osVersion:  "16.1",
+			identifier: "tvOS tv",
+		},
+		{
+			name:       "Apple TV model",
+			ua:         "AppleTV11,1/16.1",
+			deviceType: useragent.DeviceTypeTV,
+			os:         useragent.OSTvOS,
+			osVersion:  "16.1",
+			identifier: "tvOS tv",
+		},
+		{
+			name:       "tvOS app",
+			ua:         "Plex/8.28 (com.plexapp.plex; build:1; tvOS 17.2.0) Alamofire/5.6.4",
+			deviceType: useragent.DeviceTypeTV,
+			os:         useragent.OSTvOS,
+			osVersion:  "17.2.0",
+			identifier: "tvOS tv",
+		},
+		{
+			name:       "Fire TV Stick 4K",
+			ua:         "Mozilla/5.0 (Linux; Android 7.1.2; AFTMM Build/NS6265; wv) AppleWebKit/537.36 (KHTML, like Gecko) Version/4.0 Chrome/70.0.3538.110 Mobile Safari/537.36",
+			deviceType: useragent.DeviceTypeTV,
+			os:         useragent.OSFireOS,
+			identifier: "Chrome/70.0.35381 (Fire OS tv)",
+		},
+		{
+			name:       "Fire TV Cube",
+			ua:         "Mozilla/5.0 (Linux; Android 9; AFTKA Build/PS7633.3445N; wv) AppleWebKit/537.36 (KHTML, like Gecko) Version/4.0 Chrome/120.0.6099.230 Mobile Safari/537.36",
+			deviceType: useragent.DeviceTypeTV,
+			os:         useragent.OSFireOS,
+			identifier: "Chrome/120.0.6099 (Fire OS tv)",
+		},
+		{
+			name:       "Samsung Tizen",
+			ua:         "Mozilla/5.0 (SMART-TV; Linux; Tizen 6.0) AppleWebKit/537.36 (KHTML, like Gecko) SamsungBrowser/4.0 Chrome/76.0.3809.146 TV Safari/537.36",
+			deviceType: useragent.DeviceTypeTV,
+			os:         useragent.OSTizen,
+			osVersion:  "6.0",
+			identifier: "Samsung/4.0 (Tizen tv)",
+		},
+		{
+			name:       "LG webOS",
+			ua:         "Mozilla/5.0 (Web0S; Linux/SmartTV) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/79.0.3945.79 Safari/537.36 WebAppManager",
+			deviceType: useragent.DeviceTypeTV,
+			os:         useragent.OSWebOS,
+			identifier: "Chrome/79.0.39451 (webOS tv)",
+		},
+		{
+			name:       "LG NetCast",
+			ua:         "Mozilla/5.0 (DirectFB; Linux armv7l) AppleWebKit/534.26+ (KHTML, like Gecko) Version/5.0 Safari/534.26+ LG Browser/5.00.00(+mouse+3D+SCREEN+TUNER; LGE; 42LM7600-ZA; 04.41.03; 0x00000001;); LG NetCast.TV-2012 0",
+			deviceType: useragent.DeviceTypeTV,
+			os:         useragent.OSLinux,
+			identifier: "Safari/5.0 (Linux tv)",
+		},
+		{
+			name:       "Chromecast with Google TV",
+			ua:         "Mozilla/5.0 (Linux; Android 12.0; Build/STTL.240206.002) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.6099.230 Safari/537.36 CrKey/1.56.500000 DeviceType/AndroidTV",
+			deviceType: useragent.DeviceTypeTV,
+			os:         useragent.OSAndroid,
+			osVersion:  "12.0",
+			identifier: "Chrome/120.0.6099 (Android tv)",
+		},
+		{
+			name:       "PlayStation 5 stays console",
+			ua:         "Mozilla/5.0 (PlayStation; PlayStation 5/2.26) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/13.0 Safari/605.1.15",
+			deviceType: useragent.DeviceTypeConsole,
+			os:         useragent.OSUnknown,
+			identifier: "Safari/13.0 (Unknown OS console)",
+		},
+		{
+			name:       "Xbox One stays console",
+			ua:         "Mozilla/5.0 (Windows NT 10.0; Win64; x64; Xbox; Xbox One) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/70.0.3538.102 Safari/537.36 Edge/18.19041",
+			deviceType: useragent.DeviceTypeConsole,
+			os:         useragent.OSWindows,
+			osVersion:  "10/11",
+			identifier: "Edge/18.19041 (Windows console)",
+		},
+		{
+			name:       "Tizen phone stays mobile",
+			ua:         "Mozilla/5.0 (Linux; Tizen 2.3; SAMSUNG SM-Z130H) AppleWebKit/537.3 (KHTML, like Gecko) SamsungBrowser/1.0 Mobile Safari/537.3",
+			deviceType: useragent.DeviceTypeMobile,
+			os:         useragent.OSTizen,
+			osVersion:  "2.3",
+			identifier: "Samsung/1.0 (Tizen mobile)",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			ua, err := useragent.Parse(tc.ua)
+			require.NoError(t, err)
+			assert.Equal(t, tc.deviceType, ua.DeviceType())
+			assert.Equal(t, tc.os, ua.OS())
+			assert.Equal(t, tc.osVersion, ua.OSVersion())
+			assert.Equal(t, tc.identifier, ua.GetShortIdentifier())
+		})
+	}
+}
+
+// TestParseModel tests model and vendor extraction with real UA samples
+func TestParseModel(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name   string
+		ua     string
+		model  string
+		vendor string
+	}{
+		{
+			name:   "Samsung Galaxy S21 WebView",
+			ua:     "Mozilla/5.0 (Linux; Android 13; SM-G991B Build/TP1A.220624.014; wv) AppleWebKit/537.36 (KHTML, like Gecko) Version/4.0 Chrome/119.0.6045.163 Mobile Safari/537.36",
+			model:  "SM-G991B",
+			vendor: useragent.VendorSamsung,
+		},
+		{
+			name:   "Samsung Internet",
+			ua:     "Mozilla/5.0 (Linux; Android 13; SAMSUNG SM-S918B) AppleWebKit/537.36 (KHTML, like Gecko) SamsungBrowser/23.0 Chrome/115.0.0.0 Mobile Safari/537.36",
+			model:  "SAMSUNG SM-S918B",
+			vendor: useragent.VendorSamsung,
+		},
+		{
+			name:   "Samsung Galaxy Tab",
+			ua:     "Mozilla/5.0 (Linux; Android 13; SM-X700) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+			model:  "SM-X700",
+			vendor: useragent.VendorSamsung,
+		},
+		{
+			name:   "Xiaomi Redmi",
+			ua:     "Mozilla/5.0 (Linux; Android 12; Redmi Note 11 Build/SKQ1.211103.001) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/119.0.6045.163 Mobile Safari/537.36",
+			model:  "Redmi Note 11",
+			vendor: useragent.VendorXiaomi,
+		},
+		{
+			name:   "Xiaomi numeric model with MIUI browser",
+			ua:     "Mozilla/5.0 (Linux; U; Android 13; en-us; 2201116SG Build/TKQ1.221114.001) AppleWebKit/537.36 (KHTML, like Gecko) Version/4.0 Chrome/112.0.5615.136 Mobile Safari/537.36 XiaoMi/MiuiBrowser/14.0.8-gn",
+			model:  "2201116SG",
+			vendor: useragent.VendorXiaomi,
+		},
+		{
+			name:   "Pixel",
+			ua:     "Mozilla/5.0 (Linux; Android 14; Pixel 8 Pro) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.6099.144 Mobile Safari/537.36",
+			model:  "Pixel 8 Pro",
+			vendor: useragent.VendorGoogle,
+		},
+		{
+			name:   "Fire TV",
+			ua:     "Mozilla/5.0 (Linux; Android 9; AFTKA Build/PS7633.3445N; wv) AppleWebKit/537.36 (KHTML, like Gecko) Version/4.0 Chrome/120.0.6099.230 Mobile Safari/537.36",
+			model:  "AFTKA",
+			vendor: useragent.VendorAmazon,
+		},
+		{
+			name: "Reduced Chrome UA",
+			ua:   "Mozilla/5.0 (Linux; Android 10; K) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Mobile Safari/537.36",
+		},
+		{
+			name:   "iPhone",
+			ua:     "Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.0 Mobile/15E148 Safari/604.1",
+			model:  "iPhone",
+			vendor: useragent.VendorApple,
+		},
+		{
+			name: "Windows desktop",
+			ua:   "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+		},
+		{
+			name: "Mac desktop",
+			ua:   "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.0 Safari/605.1.15",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			ua, err := useragent.Parse(tc.ua)
+			require.NoError(t, err)
+			assert.Equal(t, tc.model, ua.Model())
+			assert.Equal(t, tc.vendor, ua.Vendor())
+		})
+	}
+
+	t.Run("bots have no model", func(t *testing.T) {
+		t.Parallel()
+		ua, err := useragent.Parse("Mozilla/5.0 (Linux; Android 6.0.1; Nexus 5X Build/MMB29P) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.6099.71 Mobile Safari/537.36 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)")
+		require.NoError(t, err)
+		assert.Empty(t, ua.Model())
+		assert.Empty(t, ua.Vendor())
+	})
+}
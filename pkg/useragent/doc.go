@@ -3,9 +3,12 @@
 //
 // It identifies:
 //   - Device type – desktop, mobile, tablet, TV, console, bot or unknown
-//   - Device model – iPhone, Samsung, Huawei, etc. (when available)
-//   - Operating system – Windows, macOS, iOS, Android, Linux, ChromeOS, etc.
+//   - Device model – iPhone, Samsung, Huawei, etc. (when available), plus the
+//     exact Android model ("SM-G991B", "Pixel 8") and its vendor
+//   - Operating system – Windows, macOS, iOS, Android, Linux, ChromeOS, etc., with versions
 //   - Browser name and version – Chrome, Safari, Firefox, …
+//   - Layout engine and version – Blink, WebKit, Gecko, Trident, EdgeHTML
+//   - Bot category and name – search, social, monitoring, AI or generic crawlers
 //
 // In addition, helper methods make it trivial to test whether a UA belongs to a
 // particular class (IsBot, IsMobile, IsDesktop, …) and to build short human-readable
@@ -60,6 +63,30 @@
 //	    // serve mobile-optimised assets
 //	}
 //
+// For responsive decisions FormFactor refines the device type into phone,
+// phablet, tablet, laptop, desktop, tv, watch or console, and IsTouchLikely
+// gives a best-effort touch hint. WithClientHints applies Sec-CH-UA-Form-Factors,
+// Sec-CH-UA-Mobile and Sec-CH-DPR request headers on top of the UA string:
+//
+//	ua = ua.WithClientHints(r.Header)
+//	if ua.FormFactor() == useragent.FormFactorPhablet && ua.DevicePixelRatio() >= 2 {
+//	    // serve large @2x images
+//	}
+//
+// ParseHeaders goes further for Chromium's reduced UA strings: the Sec-CH-UA brand
+// lists, platform and mobile hints take precedence over the User-Agent header for
+// the browser version, OS version and device type, then WithClientHints is applied.
+//
+// OSVersion reports the OS version for Windows, macOS, iOS and Android, with
+// Windows NT versions mapped to marketing names ("10/11" for NT 10.0), and
+// CompareOSVersion compares it numerically. UA versions are ambiguous – Windows
+// 10 and 11 share NT 10.0 and browsers freeze macOS at 10.15.7 – so use them
+// as hints:
+//
+//	if ua.OS() == useragent.OSiOS && ua.CompareOSVersion("15") < 0 {
+//	    // serve the fallback
+//	}
+//
 // # Error Handling
 //
 // Parse may return the following sentinel errors, all export-visible via
@@ -72,6 +99,10 @@
 // • Single pass over the input for most common paths.
 // • Hot keyword sets implemented by map[string]struct{} look-ups.
 //
+// • CachedParser (also built by NewParser) memoizes results in an LRU keyed by the
+//   raw UA string, turning repeat lookups into a map hit; Stats exposes the hit
+//   rate for tuning capacity.
+//
 // Benchmarks live next to the implementation (benchmark_test.go) and show sub-µs
 // parsing times on 2024-class CPUs.
 //
@@ -0,0 +1,30 @@
+package useragent_test
+
+import (
+	"fmt"
+
+	"github.com/dmitrymomot/saaskit/pkg/useragent"
+)
+
+// Example_engine shows that Chromium-based Edge reports Blink while legacy Edge reports EdgeHTML
+func Example_engine() {
+	for _, s := range []string{
+		// Chromium-based Edge
+		"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 Edg/120.0.2210.91",
+		// Legacy Edge
+		"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/70.0.3538.102 Safari/537.36 Edge/18.19041",
+		// Safari
+		"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.0 Safari/605.1.15",
+	} {
+		ua, err := useragent.Parse(s)
+		if err != nil {
+			panic(err)
+		}
+		fmt.Println(ua.BrowserName(), ua.Engine(), ua.EngineVersion())
+	}
+
+	// Output:
+	// edge blink 120.0.0.0
+	// edge edgehtml 18.19041
+	// safari webkit 605.1.15
+}
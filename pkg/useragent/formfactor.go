@@ -0,0 +1,126 @@
+package useragent
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Keyword sets for form factors that the coarse device types don't distinguish
+var (
+	watchKeywords   = newKeywordSet("watch", "wear os", "wearos", "sm-r8", "sm-r9")
+	phabletKeywords = newKeywordSet("phablet", "galaxy note", "sm-n9", "sm-n7", "redmi note", "mi max")
+	laptopKeywords  = newKeywordSet("cros", "chromeos")
+)
+
+// ParseFormFactor derives a form factor from a lower-cased UA string and its device type.
+// Laptops can't be told from desktops by the UA alone, so only Chromebooks are
+// reported as laptops. Bots and unknown devices return FormFactorUnknown.
+func ParseFormFactor(lowerUA, deviceType string) string {
+	switch deviceType {
+	case DeviceTypeMobile:
+		if watchKeywords.contains(lowerUA) {
+			return FormFactorWatch
+		}
+		if phabletKeywords.contains(lowerUA) {
+			return FormFactorPhablet
+		}
+		return FormFactorPhone
+	case DeviceTypeTablet:
+		return FormFactorTablet
+	case DeviceTypeDesktop:
+		if laptopKeywords.contains(lowerUA) {
+			return FormFactorLaptop
+		}
+		return FormFactorDesktop
+	case DeviceTypeTV:
+		return FormFactorTV
+	case DeviceTypeConsole:
+		return FormFactorConsole
+	default:
+		return FormFactorUnknown
+	}
+}
+
+// FormFactor returns a finer classification than DeviceType: phone, phablet,
+// tablet, laptop, desktop, tv, watch or console. Client hints applied with
+// WithClientHints take precedence over the UA string. Returns FormFactorUnknown
+// when there are no reliable signals rather than guessing.
+//
+// Note that iPads on iPadOS 13+ request desktop sites with a Macintosh UA and
+// are reported as desktops unless client hints say otherwise.
+func (ua UserAgent) FormFactor() string {
+	if ua.formFactor != "" {
+		return ua.formFactor
+	}
+	return ParseFormFactor(strings.ToLower(ua.userAgent), ua.deviceType)
+}
+
+// IsTouchLikely reports whether the device most likely has a touch screen.
+// It's a best-effort hint: phones, tablets and watches are assumed to be touch
+// devices, as are Windows UAs with a Touch token. Everything else returns false.
+func (ua UserAgent) IsTouchLikely() bool {
+	switch ua.FormFactor() {
+	case FormFactorPhone, FormFactorPhablet, FormFactorTablet, FormFactorWatch:
+		return true
+	case FormFactorDesktop, FormFactorLaptop:
+		return strings.Contains(strings.ToLower(ua.userAgent), "touch")
+	default:
+		return false
+	}
+}
+
+// DevicePixelRatio returns the Sec-CH-DPR client hint set by WithClientHints,
+// or 0 when the browser didn't send it.
+func (ua UserAgent) DevicePixelRatio() float64 { return ua.dpr }
+
+// WithClientHints returns a copy of ua refined by User-Agent Client Hints.
+// Precedence for the form factor is Sec-CH-UA-Form-Factors, then Sec-CH-UA-Mobile,
+// then the UA string. Sec-CH-DPR (or the legacy DPR header) sets DevicePixelRatio.
+// Browsers only send these hints after opting in with an Accept-CH response header:
+//
+//	Accept-CH: Sec-CH-UA-Mobile, Sec-CH-UA-Form-Factors, Sec-CH-DPR
+func (ua UserAgent) WithClientHints(h http.Header) UserAgent {
+	fromUA := ParseFormFactor(strings.ToLower(ua.userAgent), ua.deviceType)
+
+	if ff := formFactorFromHint(h.Get("Sec-CH-UA-Form-Factors"), fromUA); ff != "" {
+		ua.formFactor = ff
+	} else if h.Get("Sec-CH-UA-Mobile") == "?1" && fromUA != FormFactorPhone && fromUA != FormFactorPhablet {
+		// The mobile hint doesn't tell phones from phablets, the UA might
+		ua.formFactor = FormFactorPhone
+	}
+
+	dpr := h.Get("Sec-CH-DPR")
+	if dpr == "" {
+		dpr = h.Get("DPR")
+	}
+	if v, err := strconv.ParseFloat(dpr, 64); err == nil && v > 0 {
+		ua.dpr = v
+	}
+
+	return ua
+}
+
+// formFactorFromHint maps the first recognized value of a Sec-CH-UA-Form-Factors
+// list, e.g. `"Mobile", "EInk"`. The UA-derived form factor refines broad hints.
+func formFactorFromHint(hint, fromUA string) string {
+	for item := range strings.SplitSeq(hint, ",") {
+		switch strings.ToLower(strings.Trim(strings.TrimSpace(item), `"`)) {
+		case "mobile":
+			if fromUA == FormFactorPhablet {
+				return FormFactorPhablet
+			}
+			return FormFactorPhone
+		case "tablet":
+			return FormFactorTablet
+		case "desktop":
+			if fromUA == FormFactorLaptop {
+				return FormFactorLaptop
+			}
+			return FormFactorDesktop
+		case "watch":
+			return FormFactorWatch
+		}
+	}
+	return ""
+}
@@ -0,0 +1,189 @@
+package useragent_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dmitrymomot/saaskit/pkg/useragent"
+)
+
+func TestFormFactor(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		ua         string
+		formFactor string
+		touch      bool
+	}{
+		{
+			name:       "iPhone",
+			ua:         "Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.0 Mobile/15E148 Safari/604.1",
+			formFactor: useragent.FormFactorPhone,
+			touch:      true,
+		},
+		{
+			name:       "Galaxy Note",
+			ua:         "Mozilla/5.0 (Linux; Android 12; SM-N986B) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/112.0.0.0 Mobile Safari/537.36",
+			formFactor: useragent.FormFactorPhablet,
+			touch:      true,
+		},
+		{
+			name:       "Galaxy Watch",
+			ua:         "Mozilla/5.0 (Linux; Android 11; SM-R860 Build/RWD1.220613.001; wv) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/112.0.0.0 Mobile Safari/537.36",
+			formFactor: useragent.FormFactorWatch,
+			touch:      true,
+		},
+		{
+			name:       "iPad",
+			ua:         "Mozilla/5.0 (iPad; CPU OS 16_0 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/16.0 Mobile/15E148 Safari/604.1",
+			formFactor: useragent.FormFactorTablet,
+			touch:      true,
+		},
+		{
+			name:       "Chromebook",
+			ua:         "Mozilla/5.0 (X11; CrOS x86_64 15359.58.0) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/112.0.0.0 Safari/537.36",
+			formFactor: useragent.FormFactorLaptop,
+		},
+		{
+			name:       "Windows desktop",
+			ua:         "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/112.0.0.0 Safari/537.36",
+			formFactor: useragent.FormFactorDesktop,
+		},
+		{
+			name:       "Smart TV",
+			ua:         "Mozilla/5.0 (SMART-TV; Linux; Tizen 6.0) AppleWebKit/537.36 (KHTML, like Gecko) SamsungBrowser/4.0 Chrome/76.0.3809.146 TV Safari/537.36",
+			formFactor: useragent.FormFactorTV,
+		},
+		{
+			name:       "Bot",
+			ua:         "Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)",
+			formFactor: useragent.FormFactorUnknown,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ua, err := useragent.Parse(tt.ua)
+			require.NoError(t, err)
+			assert.Equal(t, tt.formFactor, ua.FormFactor())
+			assert.Equal(t, tt.touch, ua.IsTouchLikely())
+		})
+	}
+
+	t.Run("unknown device", func(t *testing.T) {
+		t.Parallel()
+
+		ua := useragent.New("custom", useragent.DeviceTypeUnknown, "", useragent.OSUnknown, useragent.BrowserUnknown, "")
+		assert.Equal(t, useragent.FormFactorUnknown, ua.FormFactor())
+		assert.False(t, ua.IsTouchLikely())
+	})
+
+	t.Run("keeps device type intact", func(t *testing.T) {
+		t.Parallel()
+
+		ua, err := useragent.Parse("Mozilla/5.0 (Linux; Android 12; SM-N986B) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/112.0.0.0 Mobile Safari/537.36")
+		require.NoError(t, err)
+		assert.Equal(t, useragent.DeviceTypeMobile, ua.DeviceType())
+	})
+}
+
+func TestUserAgent_WithClientHints(t *testing.T) {
+	t.Parallel()
+
+	const (
+		macUA     = "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/112.0.0.0 Safari/537.36"
+		noteUA    = "Mozilla/5.0 (Linux; Android 12; SM-N986B) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/112.0.0.0 Mobile Safari/537.36"
+		crosUA    = "Mozilla/5.0 (X11; CrOS x86_64 15359.58.0) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/112.0.0.0 Safari/537.36"
+		androidUA = "Mozilla/5.0 (Linux; Android 10; K) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/112.0.0.0 Safari/537.36"
+	)
+
+	tests := []struct {
+		name       string
+		ua         string
+		headers    map[string]string
+		formFactor string
+		touch      bool
+		dpr        float64
+	}{
+		{
+			name:       "form factors hint wins over UA",
+			ua:         macUA,
+			headers:    map[string]string{"Sec-CH-UA-Form-Factors": `"Tablet"`},
+			formFactor: useragent.FormFactorTablet,
+			touch:      true,
+		},
+		{
+			name:       "first recognized form factor",
+			ua:         androidUA,
+			headers:    map[string]string{"Sec-CH-UA-Form-Factors": `"EInk", "Mobile"`},
+			formFactor: useragent.FormFactorPhone,
+			touch:      true,
+		},
+		{
+			name:       "mobile hint keeps phablet from UA",
+			ua:         noteUA,
+			headers:    map[string]string{"Sec-CH-UA-Form-Factors": `"Mobile"`, "Sec-CH-UA-Mobile": "?1"},
+			formFactor: useragent.FormFactorPhablet,
+			touch:      true,
+		},
+		{
+			name:       "desktop hint keeps laptop from UA",
+			ua:         crosUA,
+			headers:    map[string]string{"Sec-CH-UA-Form-Factors": `"Desktop"`},
+			formFactor: useragent.FormFactorLaptop,
+		},
+		{
+			name:       "mobile hint overrides reduced UA",
+			ua:         androidUA,
+			headers:    map[string]string{"Sec-CH-UA-Mobile": "?1"},
+			formFactor: useragent.FormFactorPhone,
+			touch:      true,
+		},
+		{
+			name:       "device pixel ratio",
+			ua:         macUA,
+			headers:    map[string]string{"Sec-CH-DPR": "2"},
+			formFactor: useragent.FormFactorDesktop,
+			dpr:        2,
+		},
+		{
+			name:       "legacy DPR header",
+			ua:         macUA,
+			headers:    map[string]string{"DPR": "1.5"},
+			formFactor: useragent.FormFactorDesktop,
+			dpr:        1.5,
+		},
+		{
+			name:       "invalid hints are ignored",
+			ua:         macUA,
+			headers:    map[string]string{"Sec-CH-UA-Form-Factors": `"XR"`, "Sec-CH-DPR": "dense"},
+			formFactor: useragent.FormFactorDesktop,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ua, err := useragent.Parse(tt.ua)
+			require.NoError(t, err)
+
+			h := http.Header{}
+			for k, v := range tt.headers {
+				h.Set(k, v)
+			}
+
+			hinted := ua.WithClientHints(h)
+			assert.Equal(t, tt.formFactor, hinted.FormFactor())
+			assert.Equal(t, tt.touch, hinted.IsTouchLikely())
+			assert.Equal(t, tt.dpr, hinted.DevicePixelRatio())
+			assert.Equal(t, ua.DeviceType(), hinted.DeviceType())
+		})
+	}
+}
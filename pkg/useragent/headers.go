@@ -0,0 +1,180 @@
+package useragent
+
+import (
+	"net/http"
+	"strings"
+)
+
+// brandBrowsers maps lower-cased Sec-CH-UA brands to browser names.
+// "Chromium" is only used when no more specific brand is listed.
+var brandBrowsers = map[string]string{
+	"google chrome":    BrowserChrome,
+	"microsoft edge":   BrowserEdge,
+	"opera":            BrowserOpera,
+	"opera gx":         BrowserOpera,
+	"brave":            BrowserBrave,
+	"vivaldi":          BrowserVivaldi,
+	"yandex":           BrowserYandex,
+	"yabrowser":        BrowserYandex,
+	"samsung internet": BrowserSamsung,
+}
+
+// platformOSes maps lower-cased Sec-CH-UA-Platform values to OS names
+var platformOSes = map[string]string{
+	"windows":     OSWindows,
+	"macos":       OSMacOS,
+	"ios":         OSiOS,
+	"android":     OSAndroid,
+	"linux":       OSLinux,
+	"chrome os":   OSChromeOS,
+	"chromium os": OSChromeOS,
+}
+
+// ParseHeaders parses the User-Agent header refined by User-Agent Client Hints.
+// Chromium browsers send a reduced UA ("Chrome/124.0.0.0", "Android 10; K") and
+// carry the real values in hints, which take precedence when present:
+//
+//   - Browser: Sec-CH-UA-Full-Version-List, then Sec-CH-UA (major version only,
+//     used when it disagrees with the UA), then the UA string
+//   - OS: Sec-CH-UA-Platform and Sec-CH-UA-Platform-Version, then the UA string.
+//     Windows platform versions 13+ map to "11" and 1-10 to "10"
+//   - Device type: Sec-CH-UA-Mobile ("?1" is mobile; "?0" turns a mobile guess into
+//     a tablet on Android, desktop elsewhere). Bots, TVs and consoles keep their type
+//   - Model and vendor: Sec-CH-UA-Model, then the UA string
+//   - Form factor and pixel ratio: see WithClientHints
+//
+// Without hints it's equivalent to Parse(h.Get("User-Agent")), including its errors.
+// Browsers send the high-entropy hints only after an Accept-CH response header:
+//
+//	Accept-CH: Sec-CH-UA-Full-Version-List, Sec-CH-UA-Platform-Version, Sec-CH-UA-Model
+func ParseHeaders(h http.Header) (UserAgent, error) {
+	ua, err := Parse(h.Get("User-Agent"))
+	if err != nil {
+		return ua, err
+	}
+
+	ua.applyBrandHints(h)
+	ua.applyPlatformHints(h)
+	ua.applyMobileHint(h.Get("Sec-CH-UA-Mobile"))
+	ua.applyModelHint(unquote(h.Get("Sec-CH-UA-Model")))
+
+	return ua.WithClientHints(h), nil
+}
+
+// applyBrandHints sets the browser and Blink version from the brand lists
+func (ua *UserAgent) applyBrandHints(h http.Header) {
+	fullList := h.Get("Sec-CH-UA-Full-Version-List")
+	list := fullList
+	if list == "" {
+		list = h.Get("Sec-CH-UA")
+	}
+	if list == "" {
+		return
+	}
+
+	var name, version, chromiumVersion string
+	for item := range strings.SplitSeq(list, ",") {
+		brand, v := parseBrand(item)
+		lower := strings.ToLower(brand)
+		if lower == "chromium" {
+			chromiumVersion = v
+		} else if browser, ok := brandBrowsers[lower]; ok && name == "" {
+			name, version = browser, v
+		}
+	}
+	if name == "" && chromiumVersion != "" {
+		name, version = BrowserChrome, chromiumVersion
+	}
+	if name == "" {
+		return
+	}
+
+	// A major-only version is less precise than the UA's unless they disagree
+	if fullList != "" || name != ua.browserName || leadingInt(version) != leadingInt(ua.browserVer) {
+		ua.browserName, ua.browserVer = name, version
+	}
+	if chromiumVersion != "" && (fullList != "" || ua.engine != EngineBlink) {
+		ua.engine, ua.engineVer = EngineBlink, chromiumVersion
+	}
+}
+
+// applyPlatformHints sets the OS and its version from the platform hints
+func (ua *UserAgent) applyPlatformHints(h http.Header) {
+	os, ok := platformOSes[strings.ToLower(unquote(h.Get("Sec-CH-UA-Platform")))]
+	if !ok {
+		return
+	}
+	if os != ua.os {
+		ua.os, ua.osVersion = os, ""
+	}
+
+	version := unquote(h.Get("Sec-CH-UA-Platform-Version"))
+	if version == "" {
+		return
+	}
+	if os == OSWindows {
+		// Windows 7 to 8.1 all report 0.x, so the UA's NT version is more precise
+		switch major := leadingInt(version); {
+		case major >= 13:
+			version = "11"
+		case major >= 1:
+			version = "10"
+		default:
+			return
+		}
+	}
+	ua.osVersion = version
+}
+
+// applyMobileHint overrides the device type heuristics with Sec-CH-UA-Mobile
+func (ua *UserAgent) applyMobileHint(hint string) {
+	switch ua.deviceType {
+	case DeviceTypeBot, DeviceTypeTV, DeviceTypeConsole:
+		return
+	}
+
+	deviceType := ua.deviceType
+	switch hint {
+	case "?1":
+		deviceType = DeviceTypeMobile
+	case "?0":
+		if deviceType == DeviceTypeMobile || deviceType == DeviceTypeUnknown {
+			deviceType = DeviceTypeDesktop
+			if ua.os == OSAndroid {
+				deviceType = DeviceTypeTablet
+			}
+		}
+	}
+	if deviceType != ua.deviceType {
+		ua.deviceType = deviceType
+		ua.deviceModel = GetDeviceModel(strings.ToLower(ua.userAgent), deviceType)
+	}
+}
+
+// applyModelHint sets the model from Sec-CH-UA-Model, which reduced UAs replace with "K"
+func (ua *UserAgent) applyModelHint(model string) {
+	if model == "" || ua.deviceType == DeviceTypeBot {
+		return
+	}
+	if len(model) > maxModelLength {
+		model = model[:maxModelLength]
+	}
+	ua.model = model
+	ua.vendor = parseVendor(strings.ToLower(model), strings.ToLower(ua.userAgent))
+}
+
+// parseBrand parses a brand list item such as `"Google Chrome";v="124.0.6367.91"`
+func parseBrand(item string) (brand, version string) {
+	brand, params, _ := strings.Cut(item, ";")
+	for param := range strings.SplitSeq(params, ";") {
+		if key, value, ok := strings.Cut(strings.TrimSpace(param), "="); ok && key == "v" {
+			version = unquote(value)
+		}
+	}
+	return unquote(brand), version
+}
+
+// unquote trims whitespace and the quotes of a structured header string
+func unquote(s string) string {
+	return strings.Trim(strings.TrimSpace(s), `"`)
+}
@@ -0,0 +1,200 @@
+package useragent_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/dmitrymomot/saaskit/pkg/useragent"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseHeaders(t *testing.T) {
+	t.Parallel()
+
+	const (
+		windowsUA = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36"
+		macUA     = "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36"
+		edgeUA    = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36 Edg/124.0.0.0"
+		androidUA = "Mozilla/5.0 (Linux; Android 10; K) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Mobile Safari/537.36"
+		botUA     = "Mozilla/5.0 (Linux; Android 6.0.1; Nexus 5X Build/MMB29P) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.6367.91 Mobile Safari/537.36 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)"
+	)
+
+	tests := []struct {
+		name        string
+		headers     map[string]string
+		browser     string
+		browserVer  string
+		engineVer   string
+		os          string
+		osVersion   string
+		deviceType  string
+		deviceModel string
+	}{
+		{
+			name: "frozen UA Chrome gets full version from hints",
+			headers: map[string]string{
+				"User-Agent":                  windowsUA,
+				"Sec-CH-UA-Full-Version-List": `"Chromium";v="124.0.6367.91", "Google Chrome";v="124.0.6367.91", "Not-A.Brand";v="99.0.0.0"`,
+				"Sec-CH-UA-Platform":          `"Windows"`,
+				"Sec-CH-UA-Platform-Version":  `"15.0.0"`,
+				"Sec-CH-UA-Mobile":            "?0",
+			},
+			browser:    useragent.BrowserChrome,
+			browserVer: "124.0.6367.91",
+			engineVer:  "124.0.6367.91",
+			os:         useragent.OSWindows,
+			osVersion:  "11",
+			deviceType: useragent.DeviceTypeDesktop,
+		},
+		{
+			name: "Windows 10 platform version",
+			headers: map[string]string{
+				"User-Agent":                 windowsUA,
+				"Sec-CH-UA-Platform":         `"Windows"`,
+				"Sec-CH-UA-Platform-Version": `"10.0.0"`,
+			},
+			browser:    useragent.BrowserChrome,
+			browserVer: "124.0.0.0",
+			engineVer:  "124.0.0.0",
+			os:         useragent.OSWindows,
+			osVersion:  "10",
+			deviceType: useragent.DeviceTypeDesktop,
+		},
+		{
+			name: "frozen macOS version",
+			headers: map[string]string{
+				"User-Agent":                 macUA,
+				"Sec-CH-UA-Platform":         `"macOS"`,
+				"Sec-CH-UA-Platform-Version": `"14.4.1"`,
+			},
+			browser:    useragent.BrowserChrome,
+			browserVer: "124.0.0.0",
+			engineVer:  "124.0.0.0",
+			os:         useragent.OSMacOS,
+			osVersion:  "14.4.1",
+			deviceType: useragent.DeviceTypeDesktop,
+		},
+		{
+			name: "major-only brand keeps UA version",
+			headers: map[string]string{
+				"User-Agent": edgeUA,
+				"Sec-CH-UA":  `"Chromium";v="124", "Microsoft Edge";v="124", "Not-A.Brand";v="99"`,
+			},
+			browser:    useragent.BrowserEdge,
+			browserVer: "124.0.0.0",
+			engineVer:  "124.0.0.0",
+			os:         useragent.OSWindows,
+			osVersion:  "10/11",
+			deviceType: useragent.DeviceTypeDesktop,
+		},
+		{
+			name: "reduced Android UA with mobile hint",
+			headers: map[string]string{
+				"User-Agent":                 androidUA,
+				"Sec-CH-UA-Mobile":           "?1",
+				"Sec-CH-UA-Platform":         `"Android"`,
+				"Sec-CH-UA-Platform-Version": `"14.0.0"`,
+			},
+			browser:     useragent.BrowserChrome,
+			browserVer:  "124.0.0.0",
+			engineVer:   "124.0.0.0",
+			os:          useragent.OSAndroid,
+			osVersion:   "14.0.0",
+			deviceType:  useragent.DeviceTypeMobile,
+			deviceModel: useragent.MobileDeviceAndroid,
+		},
+		{
+			name: "non-mobile hint on Android is a tablet",
+			headers: map[string]string{
+				"User-Agent":         androidUA,
+				"Sec-CH-UA-Mobile":   "?0",
+				"Sec-CH-UA-Platform": `"Android"`,
+			},
+			browser:     useragent.BrowserChrome,
+			browserVer:  "124.0.0.0",
+			engineVer:   "124.0.0.0",
+			os:          useragent.OSAndroid,
+			osVersion:   "10",
+			deviceType:  useragent.DeviceTypeTablet,
+			deviceModel: useragent.TabletDeviceAndroid,
+		},
+		{
+			name: "bots keep their type",
+			headers: map[string]string{
+				"User-Agent":       botUA,
+				"Sec-CH-UA-Mobile": "?0",
+			},
+			browser:    useragent.BrowserChrome,
+			browserVer: "124.0.6367.91",
+			engineVer:  "124.0.6367.91",
+			os:         useragent.OSAndroid,
+			osVersion:  "6.0.1",
+			deviceType: useragent.DeviceTypeBot,
+		},
+		{
+			name: "without hints",
+			headers: map[string]string{
+				"User-Agent": windowsUA,
+			},
+			browser:    useragent.BrowserChrome,
+			browserVer: "124.0.0.0",
+			engineVer:  "124.0.0.0",
+			os:         useragent.OSWindows,
+			osVersion:  "10/11",
+			deviceType: useragent.DeviceTypeDesktop,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			h := http.Header{}
+			for k, v := range tt.headers {
+				h.Set(k, v)
+			}
+
+			ua, err := useragent.ParseHeaders(h)
+			require.NoError(t, err)
+			assert.Equal(t, tt.browser, ua.BrowserName())
+			assert.Equal(t, tt.browserVer, ua.BrowserVer())
+			assert.Equal(t, useragent.EngineBlink, ua.Engine())
+			assert.Equal(t, tt.engineVer, ua.EngineVersion())
+			assert.Equal(t, tt.os, ua.OS())
+			assert.Equal(t, tt.osVersion, ua.OSVersion())
+			assert.Equal(t, tt.deviceType, ua.DeviceType())
+			assert.Equal(t, tt.deviceModel, ua.DeviceModel())
+		})
+	}
+
+	t.Run("without hints matches Parse", func(t *testing.T) {
+		t.Parallel()
+		h := http.Header{"User-Agent": []string{androidUA}}
+
+		fromHeaders, err := useragent.ParseHeaders(h)
+		require.NoError(t, err)
+		parsed, err := useragent.Parse(androidUA)
+		require.NoError(t, err)
+		assert.Equal(t, parsed, fromHeaders)
+	})
+
+	t.Run("model hint replaces reduced UA placeholder", func(t *testing.T) {
+		t.Parallel()
+		h := http.Header{}
+		h.Set("User-Agent", androidUA)
+		h.Set("Sec-CH-UA-Model", `"Pixel 8"`)
+
+		ua, err := useragent.ParseHeaders(h)
+		require.NoError(t, err)
+		assert.Equal(t, "Pixel 8", ua.Model())
+		assert.Equal(t, useragent.VendorGoogle, ua.Vendor())
+	})
+
+	t.Run("empty user agent", func(t *testing.T) {
+		t.Parallel()
+		_, err := useragent.ParseHeaders(http.Header{"Sec-CH-UA-Mobile": []string{"?1"}})
+		assert.ErrorIs(t, err, useragent.ErrEmptyUserAgent)
+	})
+}
@@ -1,6 +1,8 @@
 package useragent
 
 import (
+	"cmp"
+	"regexp"
 	"strings"
 )
 
@@ -8,6 +10,11 @@ import (
 var (
 	windowsPhoneKeywords = newKeywordSet("windows phone")
 	windowsKeywords      = newKeywordSet("windows")
+	tvOSKeywords         = newKeywordSet("tvos", "appletv", "apple tv")
+	tizenKeywords        = newKeywordSet("tizen")
+	webOSKeywords        = newKeywordSet("web0s", "webos")
+	rokuKeywords         = newKeywordSet("roku")
+	fireTVKeywords       = newKeywordSet("; aft")
 	iOSKeywords          = newKeywordSet("iphone", "ipad", "ipod")
 	macOSKeywords        = newKeywordSet("macintosh", "mac os x")
 	harmonyOSKeywords    = newKeywordSet("harmonyos")
@@ -32,6 +39,11 @@ func ParseOS(lowerUA string) string {
 		return OSWindows
 	}
 
+	// Apple TV UAs mimic iOS ("CPU OS 17_0 like Mac OS X")
+	if tvOSKeywords.contains(lowerUA) {
+		return OSTvOS
+	}
+
 	if iOSKeywords.contains(lowerUA) {
 		return OSiOS
 	}
@@ -40,6 +52,11 @@ func ParseOS(lowerUA string) string {
 		return OSMacOS
 	}
 
+	// Fire TV runs Fire OS but reports Android
+	if fireTVKeywords.contains(lowerUA) {
+		return OSFireOS
+	}
+
 	// Android check includes fallback for edge cases where keyword detection fails
 	if androidKeywords.contains(lowerUA) || strings.Contains(lowerUA, "android") {
 		return OSAndroid
@@ -58,9 +75,154 @@ func ParseOS(lowerUA string) string {
 		return OSChromeOS
 	}
 
+	// TV platforms are Linux-based and report it, so they're checked before Linux
+	if tizenKeywords.contains(lowerUA) {
+		return OSTizen
+	}
+
+	if webOSKeywords.contains(lowerUA) {
+		return OSWebOS
+	}
+
+	if rokuKeywords.contains(lowerUA) {
+		return OSRoku
+	}
+
 	if linuxKeywords.contains(lowerUA) {
 		return OSLinux
 	}
 
 	return OSUnknown
 }
+
+// OS version patterns, matched against the lower-cased UA
+var (
+	windowsVersionRegex = regexp.MustCompile(`windows nt (\d+\.\d+)`)
+	iOSVersionRegex     = regexp.MustCompile(`(?:iphone|cpu) os (\d+(?:_\d+)*)`)
+	macOSVersionRegex   = regexp.MustCompile(`mac os x (\d+(?:[_.]\d+)*)`)
+	androidVersionRegex = regexp.MustCompile(`android (\d+(?:\.\d+)*)`)
+	tvOSVersionRegex    = regexp.MustCompile(`(?:tvos[ /]|cpu os |appletv\d+,\d+/)(\d+(?:[_.]\d+)*)`)
+	tizenVersionRegex   = regexp.MustCompile(`tizen (\d+(?:\.\d+)*)`)
+)
+
+// windowsVersions maps Windows NT kernel versions to marketing names.
+// NT 10.0 is reported by both Windows 10 and 11, so it maps to "10/11".
+var windowsVersions = map[string]string{
+	"10.0": "10/11",
+	"6.3":  "8.1",
+	"6.2":  "8",
+	"6.1":  "7",
+	"6.0":  "Vista",
+	"5.2":  "XP",
+	"5.1":  "XP",
+	"5.0":  "2000",
+}
+
+// ParseOSVersion extracts the version of the given OS from a lower-cased UA string.
+// Underscores are normalized to dots ("iPhone OS 16_4" becomes "16.4"), and Windows
+// NT versions are mapped to marketing names ("Windows NT 6.1" becomes "7").
+// Returns an empty string when the UA carries no version for the OS.
+func ParseOSVersion(lowerUA, os string) string {
+	var regex *regexp.Regexp
+	switch os {
+	case OSWindows:
+		regex = windowsVersionRegex
+	case OSiOS:
+		regex = iOSVersionRegex
+	case OSMacOS:
+		regex = macOSVersionRegex
+	case OSAndroid:
+		regex = androidVersionRegex
+	case OSTvOS:
+		regex = tvOSVersionRegex
+	case OSTizen:
+		regex = tizenVersionRegex
+	default:
+		return ""
+	}
+
+	version := strings.ReplaceAll(extractVersion(lowerUA, regex), "_", ".")
+	if os == OSWindows {
+		// Unknown NT versions are kept as-is rather than guessed
+		if name, ok := windowsVersions[version]; ok {
+			return name
+		}
+	}
+	return version
+}
+
+// OSVersion returns the OS version, e.g. "16.4" for iOS, "13" for Android or
+// "10/11" for Windows, or an empty string when the UA doesn't carry one.
+//
+// UA versions are inherently ambiguous: Windows 10 and 11 both report NT 10.0,
+// Safari and Chrome freeze macOS at 10_15_7, and Chrome on Android 10+ may report
+// a reduced "Android 10". Treat the version as a lower bound hint, not a fact.
+func (ua UserAgent) OSVersion() string { return ua.osVersion }
+
+// CompareOSVersion compares the OS version with target component by component,
+// returning -1, 0 or +1 like cmp.Compare. Missing components count as zero,
+// so "16" equals "16.0". For Windows, target may be a marketing name ("7", "8.1",
+// "10/11") or an NT version ("6.1"); "10" and "11" both match "10/11".
+// An unknown OS version compares lower than any target, so a check such as
+// CompareOSVersion("15") >= 0 fails closed.
+func (ua UserAgent) CompareOSVersion(target string) int {
+	if ua.osVersion == "" {
+		return -1
+	}
+
+	version := ua.osVersion
+	if ua.os == OSWindows {
+		version = windowsNTVersion(version)
+		target = windowsNTVersion(target)
+	}
+
+	return compareVersions(version, target)
+}
+
+// windowsNTVersion converts a Windows marketing name back to its NT version.
+// Anything else is returned unchanged.
+func windowsNTVersion(version string) string {
+	switch version {
+	case "10", "11":
+		return "10.0"
+	}
+	for nt, name := range windowsVersions {
+		// XP has two NT versions, the lower one is its canonical release
+		if name == version && nt != "5.2" {
+			return nt
+		}
+	}
+	return version
+}
+
+// compareVersions compares dotted versions numerically, ignoring anything after
+// the leading digits of each component.
+func compareVersions(a, b string) int {
+	as := strings.Split(strings.ReplaceAll(a, "_", "."), ".")
+	bs := strings.Split(strings.ReplaceAll(b, "_", "."), ".")
+	for i := range max(len(as), len(bs)) {
+		var x, y int
+		if i < len(as) {
+			x = leadingInt(as[i])
+		}
+		if i < len(bs) {
+			y = leadingInt(bs[i])
+		}
+		if c := cmp.Compare(x, y); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+// leadingInt parses the digits at the start of s, returning 0 when there are none.
+func leadingInt(s string) int {
+	n := 0
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			break
+		}
+		n = n*10 + int(r-'0')
+	}
+	return n
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/dmitrymomot/saaskit/pkg/useragent"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // TestParseOSDetection tests the OS detection with various edge cases
@@ -81,3 +82,120 @@ func TestOSAccessors(t *testing.T) {
 
 	assert.Equal(t, useragent.OSWindows, ua.OS())
 }
+
+func TestParseOSVersion(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		ua       string
+		expected string
+	}{
+		{
+			name:     "Windows 10/11",
+			ua:       "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+			expected: "10/11",
+		},
+		{
+			name:     "Windows 7",
+			ua:       "Mozilla/5.0 (Windows NT 6.1; WOW64; rv:52.0) Gecko/20100101 Firefox/52.0",
+			expected: "7",
+		},
+		{
+			name:     "Windows XP",
+			ua:       "Mozilla/4.0 (compatible; MSIE 8.0; Windows NT 5.1; Trident/4.0)",
+			expected: "XP",
+		},
+		{
+			name:     "iPhone",
+			ua:       "Mozilla/5.0 (iPhone; CPU iPhone OS 16_4 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/16.4 Mobile/15E148 Safari/604.1",
+			expected: "16.4",
+		},
+		{
+			name:     "iPad",
+			ua:       "Mozilla/5.0 (iPad; CPU OS 15_6_1 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/15.6 Mobile/15E148 Safari/604.1",
+			expected: "15.6.1",
+		},
+		{
+			name:     "macOS",
+			ua:       "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.0 Safari/605.1.15",
+			expected: "10.15.7",
+		},
+		{
+			name:     "macOS Firefox",
+			ua:       "Mozilla/5.0 (Macintosh; Intel Mac OS X 10.15; rv:109.0) Gecko/20100101 Firefox/119.0",
+			expected: "10.15",
+		},
+		{
+			name:     "Android",
+			ua:       "Mozilla/5.0 (Linux; Android 13; Pixel 7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/116.0.0.0 Mobile Safari/537.36",
+			expected: "13",
+		},
+		{
+			name:     "Android point release",
+			ua:       "Mozilla/5.0 (Linux; Android 8.1.0; SM-T580) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/90.0.4430.210 Safari/537.36",
+			expected: "8.1.0",
+		},
+		{
+			name:     "Android without version",
+			ua:       "Mozilla/5.0 (Linux; Android; K) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Mobile Safari/537.36",
+			expected: "",
+		},
+		{
+			name:     "Linux",
+			ua:       "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/91.0.4472.124 Safari/537.36",
+			expected: "",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			ua, err := useragent.Parse(tc.ua)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, ua.OSVersion())
+		})
+	}
+}
+
+func TestCompareOSVersion(t *testing.T) {
+	t.Parallel()
+
+	parse := func(t *testing.T, s string) useragent.UserAgent {
+		t.Helper()
+		ua, err := useragent.Parse(s)
+		require.NoError(t, err)
+		return ua
+	}
+
+	t.Run("iOS", func(t *testing.T) {
+		t.Parallel()
+		ua := parse(t, "Mozilla/5.0 (iPhone; CPU iPhone OS 14_8 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Mobile/15E148")
+
+		assert.Equal(t, -1, ua.CompareOSVersion("15"))
+		assert.Equal(t, 1, ua.CompareOSVersion("14.7.1"))
+		assert.Equal(t, 0, ua.CompareOSVersion("14.8.0"))
+		assert.Equal(t, 0, ua.CompareOSVersion("14_8"))
+	})
+
+	t.Run("Windows marketing names", func(t *testing.T) {
+		t.Parallel()
+		win10 := parse(t, "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36")
+		win81 := parse(t, "Mozilla/5.0 (Windows NT 6.3; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/109.0.0.0 Safari/537.36")
+
+		assert.Equal(t, 0, win10.CompareOSVersion("10"))
+		assert.Equal(t, 0, win10.CompareOSVersion("11"))
+		assert.Equal(t, 0, win10.CompareOSVersion("10/11"))
+		assert.Equal(t, 1, win10.CompareOSVersion("8.1"))
+		assert.Equal(t, -1, win81.CompareOSVersion("10"))
+		assert.Equal(t, 1, win81.CompareOSVersion("Vista"))
+		assert.Equal(t, 0, win81.CompareOSVersion("6.3"))
+	})
+
+	t.Run("unknown version", func(t *testing.T) {
+		t.Parallel()
+		ua := parse(t, "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/91.0.4472.124 Safari/537.36")
+
+		assert.Empty(t, ua.OSVersion())
+		assert.Equal(t, -1, ua.CompareOSVersion("1"))
+	})
+}
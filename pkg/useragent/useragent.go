@@ -15,10 +15,19 @@ type UserAgent struct {
 
 	deviceType  string
 	deviceModel string
+	model       string
+	vendor      string
 
 	os          string
+	osVersion   string
 	browserName string
 	browserVer  string
+	engine      string
+	engineVer   string
+
+	// Set from client hints by WithClientHints
+	formFactor string
+	dpr        float64
 }
 
 func (ua UserAgent) String() string { return ua.userAgent }
@@ -31,12 +40,25 @@ func (ua UserAgent) DeviceType() string { return ua.deviceType }
 // DeviceModel returns the specific device model if available
 func (ua UserAgent) DeviceModel() string { return ua.deviceModel }
 
+// Model returns the device model as reported by the UA, e.g. "SM-G991B", "Pixel 8" or
+// "iPhone", or an empty string when the UA carries none (desktops, reduced UAs)
+func (ua UserAgent) Model() string { return ua.model }
+
+// Vendor returns the device vendor (samsung, google, xiaomi, apple, etc.), or an empty string if unknown
+func (ua UserAgent) Vendor() string { return ua.vendor }
+
 func (ua UserAgent) OS() string { return ua.os }
 
 func (ua UserAgent) BrowserName() string { return ua.browserName }
 
 func (ua UserAgent) BrowserVer() string { return ua.browserVer }
 
+// Engine returns the layout engine (blink, webkit, gecko, trident, edgehtml, presto, unknown)
+func (ua UserAgent) Engine() string { return ua.engine }
+
+// EngineVersion returns the layout engine version, or an empty string if unknown
+func (ua UserAgent) EngineVersion() string { return ua.engineVer }
+
 func (ua UserAgent) BrowserInfo() Browser {
 	return Browser{Name: ua.browserName, Version: ua.browserVer}
 }
@@ -70,6 +92,23 @@ var botNameMap = map[string]string{
 	"slackbot":            "Slackbot",
 	"telegrambot":         "Telegrambot",
 	"adsbot":              "AdsBot",
+	"duckduckbot":         "DuckDuckBot",
+	"applebot":            "Applebot",
+	"baiduspider":         "Baiduspider",
+	"discordbot":          "Discordbot",
+	"pinterestbot":        "Pinterestbot",
+	"gptbot":              "GPTBot",
+	"chatgpt-user":        "ChatGPT-User",
+	"oai-searchbot":       "OAI-SearchBot",
+	"claudebot":           "ClaudeBot",
+	"claude-web":          "Claude-Web",
+	"anthropic-ai":        "anthropic-ai",
+	"ccbot":               "CCBot",
+	"perplexitybot":       "PerplexityBot",
+	"bytespider":          "Bytespider",
+	"uptimerobot":         "UptimeRobot",
+	"pingdom":             "Pingdom",
+	"statuscake":          "StatusCake",
 }
 
 // Fallback patterns for dynamic bot name extraction when fast-path fails
@@ -118,9 +157,16 @@ func formatOSName(osName string) string {
 		return "Unknown OS"
 	}
 
-	// iOS requires special casing due to brand guidelines
-	if strings.ToLower(osName) == "ios" {
+	// Some platforms require special casing due to brand guidelines
+	switch strings.ToLower(osName) {
+	case OSiOS:
 		return "iOS"
+	case OSTvOS:
+		return "tvOS"
+	case OSWebOS:
+		return "webOS"
+	case OSFireOS:
+		return "Fire OS"
 	}
 
 	if len(osName) > 0 {
@@ -247,6 +293,7 @@ func Parse(ua string) (UserAgent, error) {
 	deviceModel := GetDeviceModel(lowerUA, deviceType)
 
 	os := ParseOS(lowerUA)
+	osVersion := ParseOSVersion(lowerUA, os)
 
 	browser := ParseBrowser(lowerUA)
 
@@ -255,7 +302,14 @@ func Parse(ua string) (UserAgent, error) {
 		return zero, ErrMalformedUserAgent
 	}
 
-	return New(ua, deviceType, deviceModel, os, browser.Name, browser.Version), nil
+	parsed := New(ua, deviceType, deviceModel, os, browser.Name, browser.Version)
+	parsed.osVersion = osVersion
+	parsed.engine, parsed.engineVer = ParseEngine(lowerUA)
+	if deviceType != DeviceTypeBot {
+		// Crawlers impersonate devices, their models would be misleading
+		parsed.model, parsed.vendor = ParseModel(ua)
+	}
+	return parsed, nil
 }
 
 // New creates a UserAgent struct with the provided parameters
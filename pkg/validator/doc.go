@@ -36,6 +36,15 @@
 //	    }
 //	}
 //
+// # Rule Sets
+//
+// RuleSet groups rule functions for reuse across handlers. Param binds the
+// parameter of rules such as MaxLen, and Merge composes sets:
+//
+//	emailRules := validator.NewRuleSet(validator.Required, validator.ValidEmail,
+//	    validator.Param(validator.MaxLen, 254))
+//	err := validator.ApplyRuleSet("email", email, emailRules)
+//
 // # Error Handling
 //
 // ValidationErrors implements `Is`, `As`, and `Error`, so you can use
@@ -0,0 +1,76 @@
+package validator
+
+// RuleFunc builds a Rule for a field and value. Most validation functions in
+// this package, such as Required or ValidEmail, already have this shape.
+type RuleFunc[T any] func(field string, value T) Rule
+
+// RuleSet groups rules for a kind of value so the same validation can be
+// defined once and reused across handlers:
+//
+//	var EmailRules = validator.NewRuleSet(
+//	    validator.Required,
+//	    validator.ValidEmail,
+//	    validator.Param(validator.MaxLen, 254),
+//	)
+//
+//	err := validator.ApplyRuleSet("email", req.Email, EmailRules)
+type RuleSet[T any] []RuleFunc[T]
+
+// NewRuleSet creates a rule set from rule functions, skipping nil entries.
+func NewRuleSet[T any](rules ...RuleFunc[T]) RuleSet[T] {
+	rs := make(RuleSet[T], 0, len(rules))
+	for _, r := range rules {
+		if r != nil {
+			rs = append(rs, r)
+		}
+	}
+	return rs
+}
+
+// Param binds the parameter of a parameterized rule, e.g. Param(MaxLen, 254).
+func Param[T, P any](fn func(field string, value T, param P) Rule, param P) RuleFunc[T] {
+	return func(field string, value T) Rule {
+		return fn(field, value, param)
+	}
+}
+
+// Param2 binds both parameters of a rule, e.g. Param2(ValidUsername, 3, 32).
+func Param2[T, P1, P2 any](fn func(field string, value T, p1 P1, p2 P2) Rule, p1 P1, p2 P2) RuleFunc[T] {
+	return func(field string, value T) Rule {
+		return fn(field, value, p1, p2)
+	}
+}
+
+// Merge returns a new rule set containing the rules of rs followed by others.
+// The receiver is not modified, so shared base sets stay intact.
+func (rs RuleSet[T]) Merge(others ...RuleSet[T]) RuleSet[T] {
+	size := len(rs)
+	for _, o := range others {
+		size += len(o)
+	}
+
+	merged := make(RuleSet[T], 0, size)
+	merged = append(merged, rs...)
+	for _, o := range others {
+		merged = append(merged, o...)
+	}
+	return merged
+}
+
+// Rules builds the rules for a field, for combining with other rules in Apply:
+//
+//	err := validator.Apply(append(EmailRules.Rules("email", req.Email),
+//	    validator.Required("name", req.Name),
+//	)...)
+func (rs RuleSet[T]) Rules(field string, value T) []Rule {
+	rules := make([]Rule, len(rs))
+	for i, fn := range rs {
+		rules[i] = fn(field, value)
+	}
+	return rules
+}
+
+// ApplyRuleSet validates a single field against a rule set.
+func ApplyRuleSet[T any](field string, value T, rs RuleSet[T]) error {
+	return Apply(rs.Rules(field, value)...)
+}
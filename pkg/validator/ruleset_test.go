@@ -0,0 +1,93 @@
+package validator_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dmitrymomot/saaskit/pkg/validator"
+)
+
+func TestRuleSet(t *testing.T) {
+	t.Parallel()
+
+	emailRules := validator.NewRuleSet(
+		validator.Required,
+		validator.ValidEmail,
+		validator.Param(validator.MaxLen, 254),
+	)
+
+	t.Run("valid value passes", func(t *testing.T) {
+		t.Parallel()
+		assert.NoError(t, validator.ApplyRuleSet("email", "user@example.com", emailRules))
+	})
+
+	t.Run("errors are attributed to the field", func(t *testing.T) {
+		t.Parallel()
+		err := validator.ApplyRuleSet("contact_email", "not-an-email", emailRules)
+		require.Error(t, err)
+
+		verrs := validator.ExtractValidationErrors(err)
+		assert.True(t, verrs.Has("contact_email"))
+		assert.Equal(t, "validation.email", verrs.GetErrors("contact_email")[0].TranslationKey)
+	})
+
+	t.Run("parameterized rule", func(t *testing.T) {
+		t.Parallel()
+		short := validator.NewRuleSet(validator.Param(validator.MaxLen, 3))
+		err := validator.ApplyRuleSet("code", "abcd", short)
+		require.Error(t, err)
+		assert.Equal(t, 3, validator.ExtractValidationErrors(err)[0].TranslationValues["max"])
+
+		username := validator.NewRuleSet(validator.Param2(validator.ValidUsername, 3, 8))
+		assert.NoError(t, validator.ApplyRuleSet("username", "john_doe", username))
+		assert.Error(t, validator.ApplyRuleSet("username", "jo", username))
+	})
+
+	t.Run("merge composes without mutating base", func(t *testing.T) {
+		t.Parallel()
+		corporate := emailRules.Merge(validator.NewRuleSet(
+			func(field, value string) validator.Rule {
+				return validator.Rule{
+					Check: func() bool { return strings.HasSuffix(value, "@example.com") },
+					Error: validator.ValidationError{Field: field, Message: "must be a corporate email"},
+				}
+			},
+		))
+
+		assert.Len(t, emailRules, 3)
+		assert.Len(t, corporate, 4)
+		assert.NoError(t, validator.ApplyRuleSet("email", "user@example.com", corporate))
+		assert.Error(t, validator.ApplyRuleSet("email", "user@other.com", corporate))
+		assert.NoError(t, validator.ApplyRuleSet("email", "user@other.com", emailRules))
+	})
+
+	t.Run("rules combine with other rules in Apply", func(t *testing.T) {
+		t.Parallel()
+		err := validator.Apply(append(emailRules.Rules("email", ""),
+			validator.Required("name", ""),
+		)...)
+
+		verrs := validator.ExtractValidationErrors(err)
+		assert.True(t, verrs.Has("email"))
+		assert.True(t, verrs.Has("name"))
+	})
+
+	t.Run("generic value types", func(t *testing.T) {
+		t.Parallel()
+		ageRules := validator.NewRuleSet(
+			validator.Param(validator.MinNum[int], 18),
+			validator.Param(validator.MaxNum[int], 120),
+		)
+		assert.NoError(t, validator.ApplyRuleSet("age", 30, ageRules))
+		assert.Error(t, validator.ApplyRuleSet("age", 12, ageRules))
+	})
+
+	t.Run("nil rules are skipped", func(t *testing.T) {
+		t.Parallel()
+		rs := validator.NewRuleSet[string](nil, validator.Required)
+		assert.Len(t, rs, 1)
+	})
+}
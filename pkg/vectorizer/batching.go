@@ -0,0 +1,166 @@
+package vectorizer
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DefaultBatchMaxWait is how long BatchingProvider waits for more texts when maxWait is not set.
+const DefaultBatchMaxWait = 10 * time.Millisecond
+
+// BatchingProvider coalesces concurrent Vectorize calls into VectorizeBatch requests.
+// A batch is sent when it reaches maxBatch texts or when maxWait has passed since
+// its first text arrived, whichever comes first. Each caller still gets its own
+// vector back, so it can wrap any Provider transparently.
+type BatchingProvider struct {
+	provider Provider
+	maxBatch int
+	maxWait  time.Duration
+
+	mu      sync.Mutex
+	pending []*batchRequest
+	timer   *time.Timer
+}
+
+type batchRequest struct {
+	ctx    context.Context
+	text   string
+	result chan batchResult
+}
+
+type batchResult struct {
+	vector Vector
+	err    error
+}
+
+// NewBatchingProvider wraps provider with request coalescing.
+// maxBatch defaults to 100 texts and maxWait to DefaultBatchMaxWait when not positive.
+func NewBatchingProvider(provider Provider, maxBatch int, maxWait time.Duration) *BatchingProvider {
+	if provider == nil {
+		panic("vectorizer: provider cannot be nil")
+	}
+	if maxBatch <= 0 {
+		maxBatch = DefaultOpenAIMaxBatchSize
+	}
+	if maxWait <= 0 {
+		maxWait = DefaultBatchMaxWait
+	}
+
+	return &BatchingProvider{
+		provider: provider,
+		maxBatch: maxBatch,
+		maxWait:  maxWait,
+	}
+}
+
+// NewBatchingVectorizer creates a Vectorizer with a default SimpleChunker whose
+// ToVector calls are coalesced by a BatchingProvider. Useful for high-QPS
+// embedding workloads where many single texts arrive at the same time.
+func NewBatchingVectorizer(provider Provider, maxBatch int, maxWait time.Duration) (*Vectorizer, error) {
+	if provider == nil {
+		return nil, ErrProviderNotSet
+	}
+	return NewWithDefaults(NewBatchingProvider(provider, maxBatch, maxWait))
+}
+
+// Vectorize queues text for the next batch and waits for its vector.
+// If ctx is canceled while waiting, the call returns ctx.Err() and the
+// text is dropped from the batch if it hasn't been sent yet.
+func (b *BatchingProvider) Vectorize(ctx context.Context, text string) (Vector, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	req := &batchRequest{
+		ctx:    ctx,
+		text:   text,
+		result: make(chan batchResult, 1),
+	}
+
+	b.mu.Lock()
+	b.pending = append(b.pending, req)
+	switch {
+	case len(b.pending) >= b.maxBatch:
+		batch := b.takeLocked()
+		b.mu.Unlock()
+		go b.send(batch)
+	case len(b.pending) == 1:
+		b.timer = time.AfterFunc(b.maxWait, b.flush)
+		b.mu.Unlock()
+	default:
+		b.mu.Unlock()
+	}
+
+	select {
+	case res := <-req.result:
+		return res.vector, res.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// VectorizeBatch passes already batched texts straight to the underlying provider.
+func (b *BatchingProvider) VectorizeBatch(ctx context.Context, texts []string) ([]Vector, error) {
+	return b.provider.VectorizeBatch(ctx, texts)
+}
+
+// Dimensions returns the vector dimensions of the underlying provider.
+func (b *BatchingProvider) Dimensions() int {
+	return b.provider.Dimensions()
+}
+
+// flush sends whatever is pending once maxWait has elapsed.
+func (b *BatchingProvider) flush() {
+	b.mu.Lock()
+	batch := b.takeLocked()
+	b.mu.Unlock()
+
+	b.send(batch)
+}
+
+func (b *BatchingProvider) takeLocked() []*batchRequest {
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+	batch := b.pending
+	b.pending = nil
+	return batch
+}
+
+// send issues one VectorizeBatch for the requests whose callers are still waiting
+// and fans the results back out.
+func (b *BatchingProvider) send(batch []*batchRequest) {
+	live := batch[:0]
+	for _, req := range batch {
+		if req.ctx.Err() == nil {
+			live = append(live, req)
+		}
+	}
+	if len(live) == 0 {
+		return
+	}
+
+	texts := make([]string, len(live))
+	for i, req := range live {
+		texts[i] = req.text
+	}
+
+	// The batch outlives any single caller; keep context values but not cancellation
+	ctx := context.WithoutCancel(live[0].ctx)
+
+	vectors, err := b.provider.VectorizeBatch(ctx, texts)
+	if err == nil && len(vectors) != len(texts) {
+		err = fmt.Errorf("%w: provider returned %d vectors for %d texts", ErrVectorizationFailed, len(vectors), len(texts))
+	}
+
+	for i, req := range live {
+		if err != nil {
+			req.result <- batchResult{err: err}
+			continue
+		}
+		req.result <- batchResult{vector: vectors[i]}
+	}
+}
@@ -0,0 +1,165 @@
+package vectorizer
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingProvider returns the text length as a one-dimensional vector
+type countingProvider struct {
+	calls   atomic.Int32
+	mu      sync.Mutex
+	batches [][]string
+	err     error
+	delay   time.Duration
+}
+
+func (p *countingProvider) Vectorize(ctx context.Context, text string) (Vector, error) {
+	vectors, err := p.VectorizeBatch(ctx, []string{text})
+	if err != nil {
+		return nil, err
+	}
+	return vectors[0], nil
+}
+
+func (p *countingProvider) VectorizeBatch(_ context.Context, texts []string) ([]Vector, error) {
+	p.calls.Add(1)
+	p.mu.Lock()
+	p.batches = append(p.batches, texts)
+	p.mu.Unlock()
+
+	time.Sleep(p.delay)
+	if p.err != nil {
+		return nil, p.err
+	}
+
+	vectors := make([]Vector, len(texts))
+	for i, text := range texts {
+		vectors[i] = Vector{float64(len(text))}
+	}
+	return vectors, nil
+}
+
+func (p *countingProvider) Dimensions() int { return 1 }
+
+func TestBatchingProvider(t *testing.T) {
+	t.Parallel()
+
+	t.Run("coalesces concurrent calls", func(t *testing.T) {
+		t.Parallel()
+
+		provider := &countingProvider{}
+		v, err := NewBatchingVectorizer(provider, 100, 50*time.Millisecond)
+		require.NoError(t, err)
+
+		texts := []string{"a", "bb", "ccc", "dddd", "eeeee", "ffffff", "ggggggg", "hhhhhhhh"}
+		results := make([]Vector, len(texts))
+
+		var wg sync.WaitGroup
+		for i, text := range texts {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				vec, err := v.ToVector(context.Background(), text)
+				assert.NoError(t, err)
+				results[i] = vec
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, int32(1), provider.calls.Load())
+		for i, text := range texts {
+			assert.Equal(t, Vector{float64(len(text))}, results[i])
+		}
+	})
+
+	t.Run("flushes when batch is full", func(t *testing.T) {
+		t.Parallel()
+
+		provider := &countingProvider{}
+		b := NewBatchingProvider(provider, 2, time.Hour)
+
+		var wg sync.WaitGroup
+		for range 4 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := b.Vectorize(context.Background(), "text")
+				assert.NoError(t, err)
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, int32(2), provider.calls.Load())
+		for _, batch := range provider.batches {
+			assert.Len(t, batch, 2)
+		}
+	})
+
+	t.Run("fans out provider error", func(t *testing.T) {
+		t.Parallel()
+
+		providerErr := errors.New("api down")
+		b := NewBatchingProvider(&countingProvider{err: providerErr}, 10, time.Millisecond)
+
+		_, err := b.Vectorize(context.Background(), "text")
+		assert.ErrorIs(t, err, providerErr)
+	})
+
+	t.Run("canceled waiter", func(t *testing.T) {
+		t.Parallel()
+
+		provider := &countingProvider{}
+		b := NewBatchingProvider(provider, 10, 30*time.Millisecond)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error, 1)
+		go func() {
+			_, err := b.Vectorize(ctx, "canceled")
+			done <- err
+		}()
+
+		time.Sleep(5 * time.Millisecond)
+		cancel()
+		assert.ErrorIs(t, <-done, context.Canceled)
+
+		vec, err := b.Vectorize(context.Background(), "kept")
+		require.NoError(t, err)
+		assert.Equal(t, Vector{4}, vec)
+
+		provider.mu.Lock()
+		defer provider.mu.Unlock()
+		for _, batch := range provider.batches {
+			assert.NotContains(t, batch, "canceled")
+		}
+	})
+
+	t.Run("canceled context before queueing", func(t *testing.T) {
+		t.Parallel()
+
+		provider := &countingProvider{}
+		b := NewBatchingProvider(provider, 10, time.Millisecond)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := b.Vectorize(ctx, "text")
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, int32(0), provider.calls.Load())
+	})
+
+	t.Run("nil provider", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := NewBatchingVectorizer(nil, 10, time.Millisecond)
+		assert.ErrorIs(t, err, ErrProviderNotSet)
+		assert.Panics(t, func() { NewBatchingProvider(nil, 10, time.Millisecond) })
+	})
+}
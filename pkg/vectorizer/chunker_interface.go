@@ -42,31 +42,7 @@ func DefaultChunkOptions() ChunkOptions {
 
 // EstimateTokens provides a rough estimate of token count for a text.
 // Uses the approximation that 1 word ≈ 1.3 tokens for English text.
-// This is a utility function available to all chunker implementations.
+// It's a shortcut for HeuristicTokenizer; use a Tokenizer for accurate counts.
 func EstimateTokens(text string) int {
-	if text == "" {
-		return 0
-	}
-
-	// Simple word counting
-	wordCount := 0
-	inWord := false
-
-	for _, r := range text {
-		if r == ' ' || r == '\t' || r == '\n' || r == '\r' {
-			if inWord {
-				wordCount++
-				inWord = false
-			}
-		} else {
-			inWord = true
-		}
-	}
-
-	if inWord {
-		wordCount++
-	}
-
-	// Approximation: 1 word ≈ 1.3 tokens
-	return int(float64(wordCount) * 1.3)
+	return HeuristicTokenizer{}.CountTokens(text)
 }
@@ -0,0 +1,539 @@
+package vectorizer
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// MarkdownChunker splits markdown documents into sections at ATX headings (# to ######).
+// Each heading stays attached to the content that follows it, fenced code blocks
+// (``` and ~~~) are never broken, tables are split only between rows with the
+// header repeated, and lists only between items. Sections that exceed MaxTokens are
+// packed block by block, and paragraphs that alone exceed it fall back to
+// SimpleChunker's sentence splitting.
+//
+// A code block, table row or list item larger than MaxTokens is kept whole,
+// so such chunks may exceed the limit.
+type MarkdownChunker struct {
+	fallback    *SimpleChunker
+	tokenizer   Tokenizer
+	breadcrumbs bool
+}
+
+// MarkdownOption configures a MarkdownChunker.
+type MarkdownOption func(*MarkdownChunker)
+
+// WithBreadcrumbs prefixes every chunk with the path of headings it belongs to,
+// e.g. "Guide > Installation > Linux", so each chunk carries its context on retrieval.
+// Headings then appear only in the breadcrumbs, and sections without content are skipped.
+func WithBreadcrumbs() MarkdownOption {
+	return func(c *MarkdownChunker) {
+		c.breadcrumbs = true
+	}
+}
+
+// WithMarkdownTokenizer sets the Tokenizer used for all chunking decisions,
+// including the sentence fallback (default HeuristicTokenizer).
+func WithMarkdownTokenizer(tokenizer Tokenizer) MarkdownOption {
+	return func(c *MarkdownChunker) {
+		if tokenizer != nil {
+			c.tokenizer = tokenizer
+		}
+	}
+}
+
+// NewMarkdownChunker creates a chunker for markdown documents.
+func NewMarkdownChunker(opts ...MarkdownOption) *MarkdownChunker {
+	c := &MarkdownChunker{tokenizer: HeuristicTokenizer{}}
+	for _, opt := range opts {
+		opt(c)
+	}
+	c.fallback = NewSimpleChunker(WithTokenizer(c.tokenizer))
+	return c
+}
+
+// breadcrumbSeparator joins heading titles in breadcrumbs.
+const breadcrumbSeparator = " > "
+
+// Split divides markdown text into chunks of at most MaxTokens where possible.
+// Overlap only applies to sentences too long for a chunk, which are split by words.
+// MinChunkSize is not used: small sections are not merged, so every chunk belongs
+// to a single heading.
+func (c *MarkdownChunker) Split(text string, options ChunkOptions) []string {
+	spans := c.SplitWithOffsets(text, options)
+	chunks := make([]string, len(spans))
+	for i, span := range spans {
+		chunks[i] = span.Text
+	}
+	return chunks
+}
+
+// SplitWithOffsets splits text like Split and returns the byte range of each chunk
+// in text. Ranges cover the source of the chunk's content and its heading lines;
+// breadcrumbs and repeated table headers are not part of the range.
+func (c *MarkdownChunker) SplitWithOffsets(text string, options ChunkOptions) []TextSpan {
+	if options.MaxTokens <= 0 {
+		options.MaxTokens = 500
+	}
+
+	blocks := parseMarkdownBlocks(text)
+	chunks := []TextSpan{}
+
+	var (
+		path     []markdownHeading // headings enclosing the current section
+		headings []markdownBlock   // heading lines waiting for content
+		body     []markdownBlock
+	)
+
+	flush := func() {
+		if len(body) == 0 {
+			return
+		}
+		var prefix TextSpan
+		if len(headings) > 0 {
+			prefix = joinSpans(blockSpans(headings), "\n\n")
+		}
+		if c.breadcrumbs {
+			titles := make([]string, len(path))
+			for i, h := range path {
+				titles[i] = h.title
+			}
+			prefix = TextSpan{Text: strings.Join(titles, breadcrumbSeparator), Start: -1, End: -1}
+		}
+		chunks = append(chunks, c.splitSection(prefix, body, options)...)
+		headings, body = nil, nil
+	}
+
+	for _, block := range blocks {
+		if block.kind != markdownHeadingBlock {
+			body = append(body, block)
+			continue
+		}
+
+		flush()
+		for len(path) > 0 && path[len(path)-1].level >= block.level {
+			path = path[:len(path)-1]
+		}
+		path = append(path, markdownHeading{level: block.level, title: block.title})
+		headings = append(headings, block)
+	}
+	flush()
+
+	// A document of headings only is still worth indexing
+	if len(chunks) == 0 && len(headings) > 0 {
+		chunks = append(chunks, joinSpans(blockSpans(headings), "\n\n"))
+	}
+
+	return chunks
+}
+
+// splitSection packs the blocks of a section into chunks. The prefix (heading lines,
+// or breadcrumbs without a source range) starts the first chunk, or every chunk with
+// breadcrumbs, and its tokens are reserved in every chunk so the limit holds either way.
+func (c *MarkdownChunker) splitSection(prefix TextSpan, blocks []markdownBlock, options ChunkOptions) []TextSpan {
+	budget := options.MaxTokens
+	if prefix.Text != "" {
+		budget = max(budget-c.tokenizer.CountTokens(prefix.Text+"\n\n"), 1)
+	}
+
+	var pieces []TextSpan
+	for _, block := range blocks {
+		if c.tokenizer.CountTokens(block.text) <= budget {
+			pieces = append(pieces, block.span())
+			continue
+		}
+		pieces = append(pieces, c.splitBlock(block, budget, options)...)
+	}
+
+	chunks := c.join(pieces, "\n\n", budget)
+
+	for i := range chunks {
+		if prefix.Text == "" || (i > 0 && !c.breadcrumbs) {
+			continue
+		}
+		chunks[i].Text = prefix.Text + "\n\n" + chunks[i].Text
+		if prefix.Start >= 0 {
+			chunks[i].Start = prefix.Start
+		}
+	}
+
+	return chunks
+}
+
+// splitBlock splits a block that exceeds the budget at its safe boundaries.
+func (c *MarkdownChunker) splitBlock(block markdownBlock, budget int, options ChunkOptions) []TextSpan {
+	switch block.kind {
+	case markdownCodeBlock:
+		return []TextSpan{block.span()}
+	case markdownTableBlock:
+		// Rows after the header and delimiter lines, each part repeats the header
+		header := block.parts[0].Text + "\n" + block.parts[1].Text
+		return c.pack(header, block.parts[2:], budget)
+	case markdownListBlock:
+		return c.pack("", block.parts, budget)
+	default:
+		// Sentences that alone exceed the budget are split by words, with overlap
+		var sentences []string
+		for _, sentence := range c.fallback.splitIntoSentences(block.text) {
+			if c.tokenizer.CountTokens(sentence) <= budget {
+				sentences = append(sentences, sentence)
+				continue
+			}
+			sentences = append(sentences, c.fallback.splitByTokens(sentence, ChunkOptions{
+				MaxTokens: budget,
+				Overlap:   options.Overlap,
+			})...)
+		}
+		spans := LocateChunks(block.raw, sentences)
+		for i := range spans {
+			if spans[i].Start >= 0 {
+				spans[i].Start += block.start
+				spans[i].End += block.start
+			}
+		}
+		return c.join(spans, " ", budget)
+	}
+}
+
+// join greedily concatenates pieces with sep into chunks within budget.
+func (c *MarkdownChunker) join(pieces []TextSpan, sep string, budget int) []TextSpan {
+	var chunks []TextSpan
+	var current []TextSpan
+	currentText := ""
+	for _, piece := range pieces {
+		if len(current) > 0 {
+			candidate := currentText + sep + piece.Text
+			if c.tokenizer.CountTokens(candidate) <= budget {
+				current, currentText = append(current, piece), candidate
+				continue
+			}
+			chunks = append(chunks, joinSpans(current, sep))
+		}
+		current, currentText = []TextSpan{piece}, piece.Text
+	}
+	if len(current) > 0 {
+		chunks = append(chunks, joinSpans(current, sep))
+	}
+	return chunks
+}
+
+// pack joins parts line by line into pieces within budget, starting each piece with
+// header if set. A part that doesn't fit on its own gets a piece of its own.
+// The range of a piece covers its parts, not the repeated header.
+func (c *MarkdownChunker) pack(header string, parts []TextSpan, budget int) []TextSpan {
+	var pieces []TextSpan
+	var current []TextSpan
+
+	for _, part := range parts {
+		if len(current) > 0 {
+			candidate := append(slices.Clip(current), part)
+			if c.tokenizer.CountTokens(withHeader(header, joinSpans(candidate, "\n")).Text) <= budget {
+				current = candidate
+				continue
+			}
+			pieces = append(pieces, withHeader(header, joinSpans(current, "\n")))
+		}
+		current = []TextSpan{part}
+	}
+	if len(current) > 0 {
+		pieces = append(pieces, withHeader(header, joinSpans(current, "\n")))
+	}
+
+	return pieces
+}
+
+// withHeader prepends a header line to the text of span, keeping its range.
+func withHeader(header string, span TextSpan) TextSpan {
+	if header != "" {
+		span.Text = header + "\n" + span.Text
+	}
+	return span
+}
+
+// joinSpans joins the text of consecutive spans, with a range from the first
+// located span to the last one.
+func joinSpans(spans []TextSpan, sep string) TextSpan {
+	texts := make([]string, len(spans))
+	joined := TextSpan{Start: -1, End: -1}
+	for i, span := range spans {
+		texts[i] = span.Text
+		if span.Start < 0 {
+			continue
+		}
+		if joined.Start < 0 {
+			joined.Start = span.Start
+		}
+		joined.End = max(joined.End, span.End)
+	}
+	joined.Text = strings.Join(texts, sep)
+	return joined
+}
+
+func blockSpans(blocks []markdownBlock) []TextSpan {
+	spans := make([]TextSpan, len(blocks))
+	for i, block := range blocks {
+		spans[i] = block.span()
+	}
+	return spans
+}
+
+type markdownBlockKind int
+
+const (
+	markdownParagraphBlock markdownBlockKind = iota
+	markdownHeadingBlock
+	markdownCodeBlock
+	markdownTableBlock
+	markdownListBlock
+)
+
+// markdownBlock is a unit of a markdown document that is split only at its own boundaries.
+type markdownBlock struct {
+	kind  markdownBlockKind
+	text  string
+	raw   string     // source of the block, text may differ in line endings
+	start int        // byte offset of the block in the document
+	end   int        // byte offset after the block
+	level int        // heading level, 1-6
+	title string     // heading text without markers
+	parts []TextSpan // table rows or list items
+}
+
+func (b markdownBlock) span() TextSpan {
+	return TextSpan{Text: b.text, Start: b.start, End: b.end}
+}
+
+type markdownHeading struct {
+	level int
+	title string
+}
+
+// parseMarkdownBlocks splits a document into headings, fenced code blocks, tables,
+// lists and paragraphs. Blocks are separated by blank lines or by a change of kind.
+func parseMarkdownBlocks(text string) []markdownBlock {
+	var lines []string // without line endings
+	var starts []int   // byte offset of each line
+	for offset := 0; ; {
+		n := strings.IndexByte(text[offset:], '\n')
+		if n < 0 {
+			lines, starts = append(lines, text[offset:]), append(starts, offset)
+			break
+		}
+		lines = append(lines, strings.TrimSuffix(text[offset:offset+n], "\r"))
+		starts = append(starts, offset)
+		offset += n + 1
+	}
+
+	// block builds a block from lines [from, to), with its range trimmed of outer whitespace
+	block := func(kind markdownBlockKind, from, to int) markdownBlock {
+		last := lines[to-1]
+		b := markdownBlock{
+			kind:  kind,
+			text:  strings.TrimSpace(strings.Join(lines[from:to], "\n")),
+			start: starts[from] + len(lines[from]) - len(strings.TrimLeftFunc(lines[from], unicode.IsSpace)),
+			end:   starts[to-1] + len(strings.TrimRightFunc(last, unicode.IsSpace)),
+		}
+		b.raw = text[b.start:b.end]
+		return b
+	}
+
+	var blocks []markdownBlock
+	paragraph := -1 // first line of the current paragraph
+
+	flushParagraph := func(to int) {
+		if paragraph >= 0 {
+			blocks = append(blocks, block(markdownParagraphBlock, paragraph, to))
+			paragraph = -1
+		}
+	}
+
+	for i := 0; i < len(lines); {
+		line := lines[i]
+
+		if strings.TrimSpace(line) == "" {
+			flushParagraph(i)
+			i++
+			continue
+		}
+
+		if fence, ok := openingFence(line); ok {
+			flushParagraph(i)
+			end := i + 1
+			for end < len(lines) && !isClosingFence(lines[end], fence) {
+				end++
+			}
+			end = min(end+1, len(lines)) // include the closing fence, unclosed fences run to the end
+			b := block(markdownCodeBlock, i, end)
+			b.text = strings.Join(lines[i:end], "\n") // keep indentation of code
+			blocks = append(blocks, b)
+			i = end
+			continue
+		}
+
+		if level, title, ok := parseHeading(line); ok {
+			flushParagraph(i)
+			b := block(markdownHeadingBlock, i, i+1)
+			b.level, b.title = level, title
+			blocks = append(blocks, b)
+			i++
+			continue
+		}
+
+		if i+1 < len(lines) && strings.Contains(line, "|") && isTableDelimiter(lines[i+1]) {
+			flushParagraph(i)
+			end := i + 2
+			for end < len(lines) && strings.TrimSpace(lines[end]) != "" && strings.Contains(lines[end], "|") {
+				end++
+			}
+			b := block(markdownTableBlock, i, end)
+			for row := i; row < end; row++ {
+				b.parts = append(b.parts, block(markdownTableBlock, row, row+1).span())
+			}
+			blocks = append(blocks, b)
+			i = end
+			continue
+		}
+
+		if indent, ok := listItemIndent(line); ok {
+			flushParagraph(i)
+			b := markdownBlock{kind: markdownListBlock}
+			item := i
+			end := i + 1
+			for end < len(lines) {
+				next := lines[end]
+				if strings.TrimSpace(next) == "" {
+					break
+				}
+				if _, ok := openingFence(next); ok && leadingSpaces(next) <= indent {
+					break
+				}
+				if _, _, ok := parseHeading(next); ok {
+					break
+				}
+				// Items at the first item's indentation start a new item, deeper lines continue it
+				if nextIndent, ok := listItemIndent(next); ok && nextIndent <= indent {
+					b.parts = append(b.parts, block(markdownListBlock, item, end).span())
+					item = end
+				}
+				end++
+			}
+			b.parts = append(b.parts, block(markdownListBlock, item, end).span())
+			list := block(markdownListBlock, i, end)
+			list.parts = b.parts
+			blocks = append(blocks, list)
+			i = end
+			continue
+		}
+
+		if paragraph < 0 {
+			paragraph = i
+		}
+		i++
+	}
+	flushParagraph(len(lines))
+
+	return blocks
+}
+
+// openingFence returns the fence marker (``` or ~~~, possibly longer) that opens a code block.
+func openingFence(line string) (string, bool) {
+	if leadingSpaces(line) > 3 {
+		return "", false
+	}
+	trimmed := strings.TrimLeft(line, " ")
+	if len(trimmed) < 3 || (trimmed[0] != '`' && trimmed[0] != '~') {
+		return "", false
+	}
+	n := 0
+	for n < len(trimmed) && trimmed[n] == trimmed[0] {
+		n++
+	}
+	if n < 3 {
+		return "", false
+	}
+	// Backtick fences can't have backticks in their info string
+	if trimmed[0] == '`' && strings.Contains(trimmed[n:], "`") {
+		return "", false
+	}
+	return trimmed[:n], true
+}
+
+// isClosingFence reports whether line closes a code block opened with fence.
+func isClosingFence(line, fence string) bool {
+	if leadingSpaces(line) > 3 {
+		return false
+	}
+	trimmed := strings.TrimSpace(line)
+	return len(trimmed) >= len(fence) && strings.Trim(trimmed, fence[:1]) == ""
+}
+
+// parseHeading parses an ATX heading, returning its level and title.
+func parseHeading(line string) (int, string, bool) {
+	if leadingSpaces(line) > 3 {
+		return 0, "", false
+	}
+	trimmed := strings.TrimSpace(line)
+	level := 0
+	for level < len(trimmed) && trimmed[level] == '#' {
+		level++
+	}
+	if level == 0 || level > 6 || (level < len(trimmed) && trimmed[level] != ' ' && trimmed[level] != '\t') {
+		return 0, "", false
+	}
+
+	title := strings.TrimSpace(trimmed[level:])
+	// Drop an optional closing sequence, e.g. "## Title ##"
+	if stripped := strings.TrimRight(title, "#"); stripped == "" || strings.HasSuffix(stripped, " ") {
+		title = strings.TrimSpace(stripped)
+	}
+	return level, title, true
+}
+
+// isTableDelimiter reports whether line is a table delimiter row, e.g. "|---|:--:|".
+func isTableDelimiter(line string) bool {
+	trimmed := strings.Trim(strings.TrimSpace(line), "|")
+	if trimmed == "" {
+		return false
+	}
+	for cell := range strings.SplitSeq(trimmed, "|") {
+		cell = strings.Trim(strings.TrimSpace(cell), ":")
+		if cell == "" || strings.Trim(cell, "-") != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// listItemIndent returns the indentation of a bullet or ordered list item.
+func listItemIndent(line string) (int, bool) {
+	indent := leadingSpaces(line)
+	rest := strings.TrimLeft(line, " \t")
+	if len(rest) >= 2 && strings.ContainsRune("-*+", rune(rest[0])) && (rest[1] == ' ' || rest[1] == '\t') {
+		return indent, true
+	}
+	digits := 0
+	for digits < len(rest) && digits < 9 && rest[digits] >= '0' && rest[digits] <= '9' {
+		digits++
+	}
+	if digits > 0 && digits+1 < len(rest) && (rest[digits] == '.' || rest[digits] == ')') && rest[digits+1] == ' ' {
+		return indent, true
+	}
+	return 0, false
+}
+
+// leadingSpaces counts leading spaces, with tabs counted as four.
+func leadingSpaces(line string) int {
+	n := 0
+	for _, r := range line {
+		switch r {
+		case ' ':
+			n++
+		case '\t':
+			n += 4
+		default:
+			return n
+		}
+	}
+	return n
+}
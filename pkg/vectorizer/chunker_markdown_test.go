@@ -0,0 +1,176 @@
+package vectorizer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarkdownChunker_Split(t *testing.T) {
+	doc := strings.Join([]string{
+		"Intro paragraph.",
+		"",
+		"# Guide",
+		"",
+		"## Install",
+		"",
+		"Run the installer.",
+		"",
+		"```sh",
+		"# not a heading",
+		"go get example.com/pkg",
+		"```",
+		"",
+		"## Usage ##",
+		"",
+		"Call the function.",
+	}, "\n")
+
+	t.Run("splits at headings keeping them with their content", func(t *testing.T) {
+		chunks := NewMarkdownChunker().Split(doc, ChunkOptions{MaxTokens: 100})
+
+		require.Len(t, chunks, 3)
+		assert.Equal(t, "Intro paragraph.", chunks[0])
+		assert.Equal(t, "# Guide\n\n## Install\n\nRun the installer.\n\n```sh\n# not a heading\ngo get example.com/pkg\n```", chunks[1])
+		assert.Equal(t, "## Usage ##\n\nCall the function.", chunks[2])
+	})
+
+	t.Run("prefixes breadcrumbs", func(t *testing.T) {
+		chunks := NewMarkdownChunker(WithBreadcrumbs()).Split(doc, ChunkOptions{MaxTokens: 100})
+
+		require.Len(t, chunks, 3)
+		assert.Equal(t, "Intro paragraph.", chunks[0])
+		assert.True(t, strings.HasPrefix(chunks[1], "Guide > Install\n\nRun the installer."), chunks[1])
+		assert.Equal(t, "Guide > Usage\n\nCall the function.", chunks[2])
+	})
+
+	t.Run("breadcrumbs repeat on every chunk of a long section", func(t *testing.T) {
+		text := "# API\n\n## Errors\n\n" + strings.Repeat("First sentence here. ", 10) + "\n\n" + strings.Repeat("Second block sentence. ", 10)
+		chunks := NewMarkdownChunker(WithBreadcrumbs()).Split(text, ChunkOptions{MaxTokens: 40})
+
+		require.Greater(t, len(chunks), 1)
+		for _, chunk := range chunks {
+			assert.True(t, strings.HasPrefix(chunk, "API > Errors\n\n"), chunk)
+			assert.LessOrEqual(t, EstimateTokens(chunk), 40, chunk)
+		}
+	})
+
+	t.Run("never breaks code blocks", func(t *testing.T) {
+		code := "~~~go\n" + strings.Repeat("fmt.Println(\"a b c d e\")\n\n", 20) + "~~~"
+		text := "## Example\n\nSome text.\n\n" + code + "\n\nAfter."
+		chunks := NewMarkdownChunker().Split(text, ChunkOptions{MaxTokens: 20})
+
+		assert.Contains(t, chunks, code)
+		assert.Equal(t, "## Example\n\nSome text.", chunks[0])
+		assert.Equal(t, "After.", chunks[len(chunks)-1])
+	})
+
+	t.Run("splits tables between rows with the header repeated", func(t *testing.T) {
+		header := "| Name | Description |\n|------|:-----------:|"
+		var rows []string
+		for range 8 {
+			rows = append(rows, "| item | some longer description text |")
+		}
+		text := header + "\n" + strings.Join(rows, "\n")
+
+		chunks := NewMarkdownChunker().Split(text, ChunkOptions{MaxTokens: 40})
+
+		require.Greater(t, len(chunks), 1)
+		total := 0
+		for _, chunk := range chunks {
+			require.True(t, strings.HasPrefix(chunk, header+"\n"), chunk)
+			for _, row := range strings.Split(chunk, "\n")[2:] {
+				assert.Equal(t, rows[0], row)
+				total++
+			}
+		}
+		assert.Equal(t, len(rows), total)
+	})
+
+	t.Run("splits lists between items", func(t *testing.T) {
+		item := "- first line of the item with several words\n  continuation line of the same item"
+		text := strings.TrimSuffix(strings.Repeat(item+"\n", 6), "\n")
+
+		chunks := NewMarkdownChunker().Split(text, ChunkOptions{MaxTokens: 40})
+
+		require.Greater(t, len(chunks), 1)
+		for _, chunk := range chunks {
+			for _, line := range strings.Split(chunk, "\n") {
+				if strings.HasPrefix(line, "  ") {
+					continue
+				}
+				assert.True(t, strings.HasPrefix(line, "- "), line)
+			}
+			assert.True(t, strings.HasSuffix(chunk, "continuation line of the same item"), chunk)
+		}
+	})
+
+	t.Run("falls back to sentence splitting for long paragraphs", func(t *testing.T) {
+		paragraph := strings.Repeat("This sentence has exactly seven words. ", 12)
+		chunks := NewMarkdownChunker().Split("# Long\n\n"+paragraph, ChunkOptions{MaxTokens: 30})
+
+		require.Greater(t, len(chunks), 1)
+		assert.True(t, strings.HasPrefix(chunks[0], "# Long\n\nThis sentence"))
+		for _, chunk := range chunks {
+			assert.LessOrEqual(t, EstimateTokens(chunk), 30, chunk)
+			assert.True(t, strings.HasSuffix(chunk, "words."), chunk)
+		}
+	})
+
+	t.Run("uses the injected tokenizer", func(t *testing.T) {
+		text := "# A\n\n自然言語処理は人工知能の一分野です。\n\n計算機で人間の言語を扱います。"
+
+		assert.Len(t, NewMarkdownChunker().Split(text, ChunkOptions{MaxTokens: 20}), 1)
+		chunks := NewMarkdownChunker(WithMarkdownTokenizer(runeTokenizer)).Split(text, ChunkOptions{MaxTokens: 20})
+		assert.Equal(t, []string{"# A\n\n自然言語処理は人工知能の一分野です。", "計算機で人間の言語を扱います。"}, chunks)
+	})
+
+	t.Run("empty input", func(t *testing.T) {
+		assert.Empty(t, NewMarkdownChunker().Split("  \n\n", DefaultChunkOptions()))
+	})
+}
+
+func TestParseMarkdownBlocks(t *testing.T) {
+	t.Run("headings", func(t *testing.T) {
+		for line, want := range map[string]string{
+			"# Title":         "Title",
+			"###### Deep":     "Deep",
+			"## Closed ##":    "Closed",
+			"# C#":            "C#",
+			"   ### Indented": "Indented",
+		} {
+			level, title, ok := parseHeading(line)
+			assert.True(t, ok, line)
+			assert.Positive(t, level)
+			assert.Equal(t, want, title, line)
+		}
+		for _, line := range []string{"#hashtag", "####### seven", "    # code"} {
+			_, _, ok := parseHeading(line)
+			assert.False(t, ok, line)
+		}
+	})
+
+	t.Run("unclosed fence runs to the end", func(t *testing.T) {
+		blocks := parseMarkdownBlocks("```\n# inside\n\ntext")
+		require.Len(t, blocks, 1)
+		assert.Equal(t, markdownCodeBlock, blocks[0].kind)
+	})
+
+	t.Run("fence closes only with the same marker", func(t *testing.T) {
+		blocks := parseMarkdownBlocks("````\n```\n~~~\n````\n# After")
+		require.Len(t, blocks, 2)
+		assert.Equal(t, "````\n```\n~~~\n````", blocks[0].text)
+		assert.Equal(t, markdownHeadingBlock, blocks[1].kind)
+	})
+
+	t.Run("nested list items stay with their parent", func(t *testing.T) {
+		blocks := parseMarkdownBlocks("1. one\n   - nested\n2. two")
+		require.Len(t, blocks, 1)
+		assert.Equal(t, []TextSpan{
+			{Text: "1. one\n   - nested", Start: 0, End: 18},
+			{Text: "2. two", Start: 19, End: 25},
+		}, blocks[0].parts)
+	})
+}
@@ -1,6 +1,7 @@
 package vectorizer
 
 import (
+	"sort"
 	"strings"
 	"unicode"
 )
@@ -15,22 +16,25 @@ type SimpleChunker struct {
 
 	// commonAbbreviations contains known abbreviations that don't end sentences
 	commonAbbreviations map[string]bool
+
+	// tokenizer counts tokens for all chunking decisions
+	tokenizer Tokenizer
 }
 
 // NewSimpleChunker creates a chunker that splits text intelligently.
-// By default, it attempts to maintain sentence boundaries.
-func NewSimpleChunker() *SimpleChunker {
-	return &SimpleChunker{
-		splitBySentence:     true,
-		commonAbbreviations: initCommonAbbreviations(),
-	}
+// By default, it attempts to maintain sentence boundaries and counts tokens
+// with HeuristicTokenizer; use WithTokenizer to plug in an exact counter.
+func NewSimpleChunker(opts ...Option) *SimpleChunker {
+	return NewSimpleChunkerWithOptions(true, opts...)
 }
 
 // NewSimpleChunkerWithOptions creates a chunker with specific behavior.
-func NewSimpleChunkerWithOptions(splitBySentence bool) *SimpleChunker {
+func NewSimpleChunkerWithOptions(splitBySentence bool, opts ...Option) *SimpleChunker {
+	o := applyOptions(opts)
 	return &SimpleChunker{
 		splitBySentence:     splitBySentence,
 		commonAbbreviations: initCommonAbbreviations(),
+		tokenizer:           o.tokenizer,
 	}
 }
 
@@ -98,7 +102,7 @@ func (c *SimpleChunker) Split(text string, options ChunkOptions) []string {
 	}
 
 	// If text is small enough, return as single chunk
-	tokenCount := EstimateTokens(text)
+	tokenCount := c.tokenizer.CountTokens(text)
 	if tokenCount <= options.MaxTokens {
 		return []string{text}
 	}
@@ -129,7 +133,7 @@ func (c *SimpleChunker) splitBySentences(text string, options ChunkOptions) []st
 	currentTokens := 0
 
 	for _, sentence := range sentences {
-		sentenceTokens := EstimateTokens(sentence)
+		sentenceTokens := c.tokenizer.CountTokens(sentence)
 
 		// If single sentence exceeds max tokens, split it by words
 		if sentenceTokens > options.MaxTokens {
@@ -156,7 +160,7 @@ func (c *SimpleChunker) splitBySentences(text string, options ChunkOptions) []st
 				// Calculate overlap from the end of current chunk
 				overlapText := c.getOverlapText(currentChunk, options.Overlap)
 				currentChunk = []string{overlapText}
-				currentTokens = EstimateTokens(overlapText)
+				currentTokens = c.tokenizer.CountTokens(overlapText)
 			} else {
 				currentChunk = []string{}
 				currentTokens = 0
@@ -175,15 +179,56 @@ func (c *SimpleChunker) splitBySentences(text string, options ChunkOptions) []st
 	return chunks
 }
 
-// splitByTokens splits text by approximate token count without considering sentences.
+// splitByTokens splits text at word boundaries without considering sentences.
+// Each chunk takes as many words as fit into MaxTokens, and starts with the
+// trailing words of the previous chunk that fit into Overlap.
+// Words that alone exceed MaxTokens, e.g. long runs of CJK text, are split by characters.
 func (c *SimpleChunker) splitByTokens(text string, options ChunkOptions) []string {
 	words := strings.Fields(text)
 	if len(words) == 0 {
 		return []string{}
 	}
 
+	// The heuristic is a fixed ratio per word, so chunk sizes follow from word counts
+	if _, ok := c.tokenizer.(HeuristicTokenizer); ok {
+		return splitByWordRatio(words, options)
+	}
+
+	var chunks []string
+
+	for start := 0; start < len(words); {
+		n := longestFit(len(words)-start, func(n int) bool {
+			return c.tokenizer.CountTokens(strings.Join(words[start:start+n], " ")) <= options.MaxTokens
+		})
+		end := start + n
+
+		if n == 1 && c.tokenizer.CountTokens(words[start]) > options.MaxTokens {
+			chunks = append(chunks, c.splitWord(words[start], options.MaxTokens)...)
+		} else {
+			chunks = append(chunks, strings.Join(words[start:end], " "))
+		}
+
+		if end >= len(words) {
+			break
+		}
+
+		// Move forward with overlap, always keeping at least one new word per chunk
+		overlap := 0
+		if options.Overlap > 0 {
+			overlap = sort.Search(n-1, func(m int) bool {
+				return c.tokenizer.CountTokens(strings.Join(words[end-m-1:end], " ")) > options.Overlap
+			})
+		}
+		start = end - overlap
+	}
+
+	return chunks
+}
+
+// splitByWordRatio splits words into chunks sized with the HeuristicTokenizer ratio.
+func splitByWordRatio(words []string, options ChunkOptions) []string {
 	// Use 1.3 tokens per word based on average English word length (4-5 chars + space)
-	wordsPerChunk := int(float64(options.MaxTokens) / 1.3)
+	wordsPerChunk := max(int(float64(options.MaxTokens)/1.3), 1)
 	overlapWords := int(float64(options.Overlap) / 1.3)
 
 	var chunks []string
@@ -207,6 +252,29 @@ func (c *SimpleChunker) splitByTokens(text string, options ChunkOptions) []strin
 	return chunks
 }
 
+// splitWord splits a single word that exceeds maxTokens into pieces by characters.
+func (c *SimpleChunker) splitWord(word string, maxTokens int) []string {
+	runes := []rune(word)
+	var pieces []string
+
+	for start := 0; start < len(runes); {
+		n := longestFit(len(runes)-start, func(n int) bool {
+			return c.tokenizer.CountTokens(string(runes[start:start+n])) <= maxTokens
+		})
+		pieces = append(pieces, string(runes[start:start+n]))
+		start += n
+	}
+
+	return pieces
+}
+
+// longestFit returns the largest n in [1, limit] for which fits(n) holds, assuming
+// that fits is monotonic. Returns 1 when nothing fits, so callers always make progress.
+func longestFit(limit int, fits func(n int) bool) int {
+	n := sort.Search(limit, func(i int) bool { return !fits(i + 1) })
+	return max(n, 1)
+}
+
 // getWordBefore extracts the word immediately before the given position
 func (c *SimpleChunker) getWordBefore(text []rune, pos int) string {
 	if pos <= 0 || pos > len(text) {
@@ -322,7 +390,7 @@ func (c *SimpleChunker) getOverlapText(sentences []string, overlapTokens int) st
 	currentTokens := 0
 
 	for i := len(sentences) - 1; i >= 0; i-- {
-		sentenceTokens := EstimateTokens(sentences[i])
+		sentenceTokens := c.tokenizer.CountTokens(sentences[i])
 		if currentTokens+sentenceTokens > overlapTokens && sentenceCount > 0 {
 			break
 		}
@@ -349,7 +417,7 @@ func (c *SimpleChunker) mergeSmallChunks(chunks []string, minSize int) []string
 	bufferTokens := 0
 
 	for _, chunk := range chunks {
-		chunkTokens := EstimateTokens(chunk)
+		chunkTokens := c.tokenizer.CountTokens(chunk)
 
 		if bufferTokens == 0 {
 			// Start new buffer
@@ -376,3 +444,10 @@ func (c *SimpleChunker) mergeSmallChunks(chunks []string, minSize int) []string
 
 	return merged
 }
+
+// SplitWithOffsets splits text like Split and returns the byte range of each chunk
+// in text. Chunks are rebuilt from words and sentences, so they are located by
+// their words; overlapping chunks report overlapping ranges.
+func (c *SimpleChunker) SplitWithOffsets(text string, options ChunkOptions) []TextSpan {
+	return LocateChunks(text, c.Split(text, options))
+}
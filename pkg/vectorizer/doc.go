@@ -83,6 +83,16 @@
 //	customChunker := vectorizer.NewSimpleChunkerWithOptions(false) // Disable sentence splitting
 //	chunks, err = v.ProcessWithChunker(ctx, document, customChunker, options)
 //
+// # Token Counting
+//
+// Chunking decisions count tokens with a Tokenizer. HeuristicTokenizer, the default,
+// estimates 1.3 tokens per word and underestimates code and CJK text. Inject an exact
+// counter with WithTokenizer:
+//
+//	tok := vectorizer.TokenizerFunc(countWithTiktoken)
+//	chunker := vectorizer.NewSimpleChunker(vectorizer.WithTokenizer(tok))
+//	v, err := vectorizer.NewWithDefaults(provider, vectorizer.WithTokenizer(tok))
+//
 // # Implementing Custom Providers
 //
 // Create your own embedding provider by implementing the Provider interface:
@@ -119,44 +129,35 @@
 //	customProvider := &MyCustomProvider{apiKey: "your-key"}
 //	v, err := vectorizer.NewWithDefaults(customProvider)
 //
-// # Implementing Custom Chunkers
+// For tests, FakeProvider returns deterministic vectors derived from a hash of
+// each text, and WithFailOnCall makes a given call fail to exercise retry paths:
 //
-// Create specialized text splitting strategies by implementing the Chunker interface:
-//
-//	type MarkdownChunker struct {
-//	    preserveHeaders bool
-//	}
+//	provider := vectorizer.NewFakeProvider(8, vectorizer.WithFailOnCall(1, vectorizer.ErrRateLimitExceeded))
 //
-//	func (c *MarkdownChunker) Split(text string, options vectorizer.ChunkOptions) []string {
-//	    // Custom logic for markdown-aware chunking
-//	    // Split by headers, preserve code blocks, etc.
-//
-//	    if c.preserveHeaders {
-//	        // Split at ## headers but keep header with content
-//	        return c.splitByHeaders(text, options)
-//	    }
+// # Implementing Custom Chunkers
 //
-//	    // Fallback to simple splitting
-//	    return c.splitSimple(text, options)
-//	}
+// Create specialized text splitting strategies by implementing the Chunker interface:
 //
-//	func (c *MarkdownChunker) splitByHeaders(text string, options vectorizer.ChunkOptions) []string {
-//	    // Your markdown-specific splitting logic
-//	    return []string{text} // Simplified
-//	}
+//	type ParagraphChunker struct{}
 //
-//	func (c *MarkdownChunker) splitSimple(text string, options vectorizer.ChunkOptions) []string {
-//	    // Use utility function for token estimation
-//	    if vectorizer.EstimateTokens(text) <= options.MaxTokens {
-//	        return []string{text}
+//	func (c *ParagraphChunker) Split(text string, options vectorizer.ChunkOptions) []string {
+//	    // Custom logic, e.g. one chunk per blank-line separated paragraph
+//	    var chunks []string
+//	    for _, p := range strings.Split(text, "\n\n") {
+//	        if p = strings.TrimSpace(p); p != "" {
+//	            chunks = append(chunks, p)
+//	        }
 //	    }
-//	    // Implement your splitting logic
-//	    return []string{text}
+//	    return chunks
 //	}
 //
 //	// Usage
-//	markdownChunker := &MarkdownChunker{preserveHeaders: true}
-//	v, err := vectorizer.New(provider, markdownChunker)
+//	v, err := vectorizer.New(provider, &ParagraphChunker{})
+//
+// Chunk.StartOffset and Chunk.EndOffset hold the byte range of each chunk in the
+// source text. Chunkers that know their positions implement OffsetChunker; the
+// chunks of any other Chunker are found with LocateChunks, and get -1 offsets
+// when their text doesn't appear in the source.
 //
 // # Built-in Components
 //
@@ -171,6 +172,15 @@
 //   - Automatic merging prevents overly small fragments
 //   - Word-level fallback for sentences exceeding token limits
 //
+// MarkdownChunker splits technical docs at ATX headings, keeping each heading
+// with its section, fenced code blocks whole, and tables and lists split only
+// between rows and items. Long sections fall back to sentence splitting, and
+// WithBreadcrumbs prefixes each chunk with its heading path:
+//
+//	chunker := vectorizer.NewMarkdownChunker(vectorizer.WithBreadcrumbs())
+//	chunks, err := v.ProcessWithChunker(ctx, readme, chunker, vectorizer.DefaultChunkOptions())
+//	// chunks[i].Text: "Guide > Installation\n\nRun go get ..."
+//
 // # Advanced Usage Patterns
 //
 // Batch processing for efficiency:
@@ -178,6 +188,12 @@
 //	texts := []string{"text1", "text2", "text3"}
 //	vectors, err := v.ChunksToVectors(ctx, texts)
 //
+// Coalescing concurrent single-text requests under high QPS:
+//
+//	// Up to 64 texts per API call, waiting at most 10ms for a batch to fill
+//	v, err := vectorizer.NewBatchingVectorizer(provider, 64, 10*time.Millisecond)
+//	vector, err := v.ToVector(ctx, text) // same API, far fewer requests
+//
 // Runtime chunker switching:
 //
 //	// Start with sentence-aware chunking
@@ -203,11 +219,22 @@
 //	    HTTPClient: client,
 //	})
 //
+// Rate limits are handled per sub-batch: VectorizeBatch sends at most MaxBatchSize
+// texts per request and retries a sub-batch answered with HTTP 429 up to MaxRetries
+// times with exponential backoff starting at RetryDelay, keeping output order:
+//
+//	provider, err := vectorizer.NewOpenAIProvider(vectorizer.OpenAIConfig{
+//	    APIKey:       os.Getenv("OPENAI_API_KEY"),
+//	    MaxBatchSize: 50,
+//	    MaxRetries:   5,
+//	})
+//
 // # Performance Considerations
 //
 // Optimize for your specific use case:
 //
 // - Batch processing: Use ChunksToVectors for multiple texts (reduces API calls)
+// - Concurrent callers: Wrap the provider with NewBatchingProvider to coalesce ToVector calls
 // - Chunk size: Balance between context (larger chunks) and precision (smaller chunks)
 // - Overlap: Higher overlap maintains context but increases processing time and cost
 // - HTTP client: Configure timeout and connection pooling for large batches
@@ -226,9 +253,8 @@
 //	vector, err := v.ToVector(ctx, text)
 //	if err != nil {
 //	    if errors.Is(err, vectorizer.ErrRateLimitExceeded) {
-//	        // Implement exponential backoff
-//	        time.Sleep(time.Second * 5)
-//	        return v.ToVector(ctx, text)
+//	        // Retries with backoff were exhausted, try again later
+//	        return nil, err
 //	    }
 //	    if errors.Is(err, vectorizer.ErrContextLengthExceeded) {
 //	        // Text too long, chunk it first
@@ -246,6 +272,8 @@
 //   - ErrContextLengthExceeded – text too long for model
 //   - ErrAPIKeyRequired        – missing API key in provider configuration
 //   - ErrInvalidModel          – unsupported model name
+//   - ErrDimensionMismatch     – comparing vectors of different lengths
+//   - ErrInvalidK              – TopK called with a non-positive k
 //
 // # Integration Examples
 //
@@ -253,18 +281,20 @@
 //
 //	// Index documents
 //	documents := []string{"doc1", "doc2", "doc3"}
-//	var index []vectorizer.Vector
+//	var index []vectorizer.Chunk
 //	for _, doc := range documents {
 //	    chunks, _ := v.Process(ctx, doc, vectorizer.DefaultChunkOptions())
-//	    for _, chunk := range chunks {
-//	        index = append(index, chunk.Vector)
-//	    }
+//	    index = append(index, chunks...)
 //	}
 //
 //	// Search
 //	query := "user's search query"
 //	queryVector, _ := v.ToVector(ctx, query)
-//	// Implement cosine similarity comparison with index
+//	results, err := vectorizer.TopK(queryVector, index, 5)
+//	// results are sorted by descending Score (cosine similarity)
+//
+// CosineSimilarity compares two vectors directly. Both helpers return 0 similarity
+// for zero vectors and ErrDimensionMismatch for vectors of different lengths.
 //
 // See the accompanying README.md for complete implementation examples.
 package vectorizer
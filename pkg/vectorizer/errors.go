@@ -16,4 +16,6 @@ var (
 	ErrInvalidModel          = errors.New("invalid model name")
 	ErrRateLimitExceeded     = errors.New("rate limit exceeded")
 	ErrContextLengthExceeded = errors.New("text exceeds maximum context length")
+	ErrDimensionMismatch     = errors.New("vector dimensions do not match")
+	ErrInvalidK              = errors.New("k must be positive")
 )
@@ -0,0 +1,120 @@
+package vectorizer
+
+import (
+	"context"
+	"crypto/sha256"
+	"math"
+	"math/rand/v2"
+	"sync"
+)
+
+// FakeProvider is an in-memory Provider for tests. It needs no API key or network,
+// and returns deterministic unit vectors derived from a SHA-256 hash of each text,
+// so identical texts always get identical vectors and a cosine similarity of 1,
+// while different texts are nearly orthogonal. Vectors carry no semantic meaning.
+// Safe for concurrent use.
+type FakeProvider struct {
+	dimensions int
+
+	mu       sync.Mutex
+	calls    int
+	failures map[int]error
+}
+
+// FakeOption configures a FakeProvider.
+type FakeOption func(*FakeProvider)
+
+// WithFailOnCall makes the nth call (1-based) to Vectorize or VectorizeBatch return err,
+// e.g. to test retry paths. Calls to both methods share one counter.
+// Can be given several times to fail several calls.
+func WithFailOnCall(n int, err error) FakeOption {
+	return func(p *FakeProvider) {
+		if n > 0 && err != nil {
+			p.failures[n] = err
+		}
+	}
+}
+
+// NewFakeProvider creates a FakeProvider returning vectors of dims dimensions.
+// Panics if dims isn't positive.
+func NewFakeProvider(dims int, opts ...FakeOption) *FakeProvider {
+	if dims <= 0 {
+		panic("vectorizer: fake provider dimensions must be positive")
+	}
+
+	p := &FakeProvider{
+		dimensions: dims,
+		failures:   make(map[int]error),
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+// Vectorize returns the vector for text.
+func (p *FakeProvider) Vectorize(ctx context.Context, text string) (Vector, error) {
+	if err := p.call(ctx); err != nil {
+		return nil, err
+	}
+	return p.vector(text), nil
+}
+
+// VectorizeBatch returns the vectors for texts, in the order of texts.
+func (p *FakeProvider) VectorizeBatch(ctx context.Context, texts []string) ([]Vector, error) {
+	if err := p.call(ctx); err != nil {
+		return nil, err
+	}
+
+	vectors := make([]Vector, len(texts))
+	for i, text := range texts {
+		vectors[i] = p.vector(text)
+	}
+	return vectors, nil
+}
+
+// Dimensions returns the configured vector dimensions.
+func (p *FakeProvider) Dimensions() int {
+	return p.dimensions
+}
+
+// Calls returns how many times Vectorize and VectorizeBatch have been called,
+// including failed calls.
+func (p *FakeProvider) Calls() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.calls
+}
+
+// call counts a call and returns the error configured for it, if any.
+// A canceled context fails the call like a real provider would.
+func (p *FakeProvider) call(ctx context.Context) error {
+	p.mu.Lock()
+	p.calls++
+	err := p.failures[p.calls]
+	p.mu.Unlock()
+
+	if err != nil {
+		return err
+	}
+	return ctx.Err()
+}
+
+// vector derives a unit vector from the SHA-256 hash of text.
+func (p *FakeProvider) vector(text string) Vector {
+	rng := rand.New(rand.NewChaCha8(sha256.Sum256([]byte(text))))
+
+	vec := make(Vector, p.dimensions)
+	var norm float64
+	for i := range vec {
+		vec[i] = rng.NormFloat64()
+		norm += vec[i] * vec[i]
+	}
+
+	norm = math.Sqrt(norm)
+	for i := range vec {
+		vec[i] /= norm
+	}
+	return vec
+}
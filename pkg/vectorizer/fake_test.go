@@ -0,0 +1,92 @@
+package vectorizer
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var _ Provider = (*FakeProvider)(nil)
+
+func TestFakeProvider_Vectors(t *testing.T) {
+	ctx := context.Background()
+	p := NewFakeProvider(64)
+
+	a, err := p.Vectorize(ctx, "hello world")
+	require.NoError(t, err)
+	require.Len(t, a, 64)
+
+	var norm float64
+	for _, v := range a {
+		norm += v * v
+	}
+	assert.InDelta(t, 1, math.Sqrt(norm), 1e-9)
+
+	t.Run("deterministic across calls and instances", func(t *testing.T) {
+		batch, err := NewFakeProvider(64).VectorizeBatch(ctx, []string{"other", "hello world"})
+		require.NoError(t, err)
+		require.Len(t, batch, 2)
+		assert.Equal(t, a, batch[1])
+
+		sim, err := CosineSimilarity(a, batch[0])
+		require.NoError(t, err)
+		assert.Less(t, sim, 0.9)
+	})
+
+	t.Run("semantic search ranks the identical text first", func(t *testing.T) {
+		v, err := New(p, NewSimpleChunker())
+		require.NoError(t, err)
+
+		docs := []string{"billing and invoices", "hello world", "password reset"}
+		vectors, err := v.ChunksToVectors(ctx, docs)
+		require.NoError(t, err)
+
+		candidates := make([]Chunk, len(docs))
+		for i := range docs {
+			candidates[i] = Chunk{Text: docs[i], Vector: vectors[i]}
+		}
+
+		top, err := TopK(a, candidates, 1)
+		require.NoError(t, err)
+		assert.Equal(t, "hello world", top[0].Text)
+		assert.InDelta(t, 1, top[0].Score, 1e-9)
+	})
+}
+
+func TestFakeProvider_FailOnCall(t *testing.T) {
+	ctx := context.Background()
+	p := NewFakeProvider(8,
+		WithFailOnCall(2, ErrRateLimitExceeded),
+		WithFailOnCall(3, ErrVectorizationFailed),
+	)
+
+	_, err := p.Vectorize(ctx, "one")
+	require.NoError(t, err)
+
+	_, err = p.VectorizeBatch(ctx, []string{"two"})
+	assert.ErrorIs(t, err, ErrRateLimitExceeded)
+
+	_, err = p.Vectorize(ctx, "three")
+	assert.ErrorIs(t, err, ErrVectorizationFailed)
+
+	_, err = p.Vectorize(ctx, "four")
+	require.NoError(t, err)
+	assert.Equal(t, 4, p.Calls())
+}
+
+func TestFakeProvider_ContextCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := NewFakeProvider(8).Vectorize(ctx, "text")
+	assert.True(t, errors.Is(err, context.Canceled))
+}
+
+func TestNewFakeProvider_InvalidDimensions(t *testing.T) {
+	assert.Panics(t, func() { NewFakeProvider(0) })
+	assert.Equal(t, 3, NewFakeProvider(3).Dimensions())
+}
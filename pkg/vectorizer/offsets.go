@@ -0,0 +1,123 @@
+package vectorizer
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// TextSpan is a chunk of text with the byte offsets of its source range in the
+// original input, so that input[Start:End] is the text the chunk was built from.
+// Offsets are -1 when the chunk can't be located in the input.
+type TextSpan struct {
+	Text  string
+	Start int
+	End   int
+}
+
+// OffsetChunker is implemented by chunkers that report where each chunk comes from.
+// Vectorizer.Process uses it to fill Chunk offsets, and falls back to LocateChunks
+// for chunkers that only implement Chunker.
+type OffsetChunker interface {
+	Chunker
+
+	// SplitWithOffsets splits text like Split and returns the source range of each chunk.
+	SplitWithOffsets(text string, options ChunkOptions) []TextSpan
+}
+
+// LocateChunks finds the source range of each chunk in source, for chunkers that
+// rebuild chunk text from words or sentences. Chunks must be in source order and
+// may overlap; whitespace between words may differ from the source.
+// A chunk that can't be found gets offsets of -1.
+func LocateChunks(source string, chunks []string) []TextSpan {
+	spans := make([]TextSpan, len(chunks))
+	from := 0
+	for i, chunk := range chunks {
+		start, end, ok := locate(source, chunk, from)
+		if !ok {
+			spans[i] = TextSpan{Text: chunk, Start: -1, End: -1}
+			continue
+		}
+		spans[i] = TextSpan{Text: chunk, Start: start, End: end}
+		// The next chunk starts after this one's start, even if they overlap
+		_, size := utf8.DecodeRuneInString(source[start:])
+		from = start + size
+	}
+	return spans
+}
+
+// locate finds the range of chunk in source at or after from. Words are matched in
+// runs separated by any whitespace; when a run breaks, e.g. where a chunker merged
+// overlapping pieces, the remaining words are searched again from the chunk start.
+func locate(source, chunk string, from int) (int, int, bool) {
+	words := strings.Fields(chunk)
+	start, end := -1, -1
+
+	for i := 0; i < len(words); {
+		pos := from
+		if start >= 0 {
+			pos = start
+		}
+		runStart, runEnd, n := matchRun(source, words[i:], pos)
+		if n == 0 {
+			i++ // word not in the source, e.g. a separator added by the chunker
+			continue
+		}
+		if start < 0 {
+			start = runStart
+		}
+		end = max(end, runEnd)
+		i += n
+	}
+
+	return start, end, start >= 0
+}
+
+// matchRun finds the first occurrence at or after pos where all words follow each
+// other, separated only by whitespace, or else the occurrence matching the most words.
+// Returns the range of the matched words and how many matched.
+func matchRun(source string, words []string, pos int) (int, int, int) {
+	bestStart, bestEnd, best := 0, 0, 0
+
+	for pos <= len(source) {
+		idx := strings.Index(source[pos:], words[0])
+		if idx < 0 {
+			break
+		}
+		start := pos + idx
+		end := start + len(words[0])
+		n := 1
+		for n < len(words) {
+			next := end
+			for next < len(source) {
+				r, size := utf8.DecodeRuneInString(source[next:])
+				if !unicode.IsSpace(r) {
+					break
+				}
+				next += size
+			}
+			if !strings.HasPrefix(source[next:], words[n]) {
+				break
+			}
+			end = next + len(words[n])
+			n++
+		}
+		if n > best {
+			bestStart, bestEnd, best = start, end, n
+		}
+		if n == len(words) {
+			break
+		}
+		pos = start + len(words[0])
+	}
+
+	return bestStart, bestEnd, best
+}
+
+// splitWithOffsets splits text with chunker and locates each chunk in text.
+func splitWithOffsets(chunker Chunker, text string, options ChunkOptions) []TextSpan {
+	if oc, ok := chunker.(OffsetChunker); ok {
+		return oc.SplitWithOffsets(text, options)
+	}
+	return LocateChunks(text, chunker.Split(text, options))
+}
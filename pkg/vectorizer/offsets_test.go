@@ -0,0 +1,131 @@
+package vectorizer
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// assertSpansMatch checks that every span points at source text with the chunk's words.
+func assertSpansMatch(t *testing.T, source string, spans []TextSpan) {
+	t.Helper()
+	for _, span := range spans {
+		require.GreaterOrEqual(t, span.Start, 0, span.Text)
+		require.LessOrEqual(t, span.End, len(source), span.Text)
+		assert.Equal(t, strings.Fields(span.Text), strings.Fields(source[span.Start:span.End]), span.Text)
+	}
+}
+
+func TestLocateChunks(t *testing.T) {
+	t.Run("multi-byte text with normalized whitespace", func(t *testing.T) {
+		source := "  Привет,   мир!\nЭто тест. Ещё одно  предложение."
+		spans := LocateChunks(source, []string{"Привет, мир!", "Это тест.", "Ещё одно предложение."})
+
+		require.Len(t, spans, 3)
+		assert.Equal(t, strings.Index(source, "Привет"), spans[0].Start)
+		assert.Equal(t, "Привет,   мир!", source[spans[0].Start:spans[0].End])
+		assert.Equal(t, "Это тест.", source[spans[1].Start:spans[1].End])
+		assert.Equal(t, "Ещё одно  предложение.", source[spans[2].Start:spans[2].End])
+	})
+
+	t.Run("repeated text maps to successive occurrences", func(t *testing.T) {
+		source := "Hi there. Hi there. Hi there."
+		spans := LocateChunks(source, []string{"Hi there.", "Hi there.", "Hi there."})
+
+		assert.Equal(t, []int{0, 10, 20}, []int{spans[0].Start, spans[1].Start, spans[2].Start})
+	})
+
+	t.Run("overlapping chunks keep their true ranges", func(t *testing.T) {
+		source := "один два три четыре пять"
+		spans := LocateChunks(source, []string{"один два три", "три четыре пять"})
+
+		assert.Equal(t, "один два три", source[spans[0].Start:spans[0].End])
+		assert.Equal(t, "три четыре пять", source[spans[1].Start:spans[1].End])
+		assert.Less(t, spans[1].Start, spans[0].End)
+	})
+
+	t.Run("merged overlapping pieces span both", func(t *testing.T) {
+		source := "a b c d"
+		spans := LocateChunks(source, []string{"a b b c"})
+
+		assert.Equal(t, TextSpan{Text: "a b b c", Start: 0, End: 5}, spans[0])
+	})
+
+	t.Run("unknown text", func(t *testing.T) {
+		spans := LocateChunks("abc", []string{"xyz"})
+		assert.Equal(t, TextSpan{Text: "xyz", Start: -1, End: -1}, spans[0])
+	})
+}
+
+func TestSimpleChunker_SplitWithOffsets(t *testing.T) {
+	source := strings.Repeat("Café crème coûte cher. Ça va très bien!  Naïve façade.\n", 6)
+
+	for _, splitBySentence := range []bool{true, false} {
+		chunker := NewSimpleChunkerWithOptions(splitBySentence)
+		spans := chunker.SplitWithOffsets(source, ChunkOptions{MaxTokens: 12, Overlap: 4, MinChunkSize: 1})
+
+		require.Greater(t, len(spans), 1)
+		assertSpansMatch(t, source, spans)
+		for i := 1; i < len(spans); i++ {
+			assert.Greater(t, spans[i].Start, spans[i-1].Start)
+		}
+	}
+}
+
+func TestMarkdownChunker_SplitWithOffsets(t *testing.T) {
+	t.Run("heading chunks start at the heading", func(t *testing.T) {
+		source := "# Título\r\n\r\nPárrafo con acentos.\r\n\r\n## Sección\r\n\r\nMás texto aquí."
+		spans := NewMarkdownChunker().SplitWithOffsets(source, ChunkOptions{MaxTokens: 100})
+
+		require.Len(t, spans, 2)
+		assert.Equal(t, "# Título\r\n\r\nPárrafo con acentos.", source[spans[0].Start:spans[0].End])
+		assert.Equal(t, "## Sección\r\n\r\nMás texto aquí.", source[spans[1].Start:spans[1].End])
+	})
+
+	t.Run("breadcrumb chunks cover only the content", func(t *testing.T) {
+		source := "# Guide\n\n## Install\n\n" + strings.Repeat("Run the installer now. ", 8)
+		spans := NewMarkdownChunker(WithBreadcrumbs()).SplitWithOffsets(source, ChunkOptions{MaxTokens: 20})
+
+		require.Greater(t, len(spans), 1)
+		for _, span := range spans {
+			require.True(t, strings.HasPrefix(span.Text, "Guide > Install\n\n"))
+			assert.Equal(t, strings.Fields(strings.TrimPrefix(span.Text, "Guide > Install\n\n")),
+				strings.Fields(source[span.Start:span.End]))
+		}
+	})
+
+	t.Run("table parts cover their rows", func(t *testing.T) {
+		rows := []string{"| a | ünïcödé row one |", "| b | ünïcödé row two |", "| c | ünïcödé row three |"}
+		source := "| k | v |\n|---|---|\n" + strings.Join(rows, "\n")
+		spans := NewMarkdownChunker().SplitWithOffsets(source, ChunkOptions{MaxTokens: 14})
+
+		require.Len(t, spans, 3)
+		for i, span := range spans {
+			assert.Equal(t, rows[i], source[span.Start:span.End])
+		}
+	})
+}
+
+func TestVectorizer_Process_Offsets(t *testing.T) {
+	ctx := context.Background()
+	source := "Première phrase ici. Deuxième phrase là. Troisième phrase enfin."
+
+	provider := &MockProvider{}
+	provider.On("VectorizeBatch", ctx, mock.Anything).Return([]Vector{{1}, {2}, {3}}, nil)
+
+	v, err := NewWithDefaults(provider)
+	require.NoError(t, err)
+
+	chunks, err := v.Process(ctx, source, ChunkOptions{MaxTokens: 4, MinChunkSize: 1})
+	require.NoError(t, err)
+	require.Len(t, chunks, 3)
+
+	for _, chunk := range chunks {
+		assert.Equal(t, chunk.Text, source[chunk.StartOffset:chunk.EndOffset])
+	}
+	assert.Equal(t, "Deuxième phrase là.", source[chunks[1].StartOffset:chunks[1].EndOffset])
+}
@@ -4,9 +4,11 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -18,8 +20,18 @@ const (
 	// OpenAI API endpoint for embeddings
 	openAIEmbeddingsURL = "https://api.openai.com/v1/embeddings"
 
-	// Maximum texts per batch request
-	maxBatchSize = 100
+	// DefaultOpenAIMaxBatchSize is the default number of texts per API request
+	DefaultOpenAIMaxBatchSize = 100
+
+	// DefaultOpenAIMaxRetries is the default number of retries for a rate-limited request
+	DefaultOpenAIMaxRetries = 3
+
+	// DefaultOpenAIRetryDelay is the default delay before the first retry,
+	// doubled on every following retry
+	DefaultOpenAIRetryDelay = time.Second
+
+	// Upper bound for a single backoff delay
+	maxRetryDelay = 30 * time.Second
 
 	// Default timeout for API requests
 	defaultTimeout = 30 * time.Second
@@ -27,10 +39,14 @@ const (
 
 // OpenAIProvider implements the Provider interface using OpenAI's API.
 type OpenAIProvider struct {
-	apiKey     string
-	model      string
-	dimensions int
-	client     *http.Client
+	apiKey       string
+	model        string
+	dimensions   int
+	client       *http.Client
+	url          string
+	maxBatchSize int
+	maxRetries   int
+	retryDelay   time.Duration
 }
 
 // OpenAIConfig configures the OpenAI provider.
@@ -45,6 +61,21 @@ type OpenAIConfig struct {
 	// HTTPClient allows custom HTTP client configuration
 	// Default: http.Client with 30s timeout
 	HTTPClient *http.Client
+
+	// MaxBatchSize is the maximum number of texts sent in a single API request.
+	// Larger batches are split into sub-batches.
+	// Default: 100
+	MaxBatchSize int
+
+	// MaxRetries is how many times a rate-limited (HTTP 429) sub-batch is retried
+	// with exponential backoff. Set to a negative value to disable retries.
+	// Default: 3
+	MaxRetries int
+
+	// RetryDelay is the delay before the first retry, doubled on each following one.
+	// A Retry-After header from the API takes precedence.
+	// Default: 1s
+	RetryDelay time.Duration
 }
 
 // NewOpenAIProvider creates a new OpenAI embedding provider.
@@ -71,11 +102,33 @@ func NewOpenAIProvider(config OpenAIConfig) (*OpenAIProvider, error) {
 		}
 	}
 
+	maxBatchSize := config.MaxBatchSize
+	if maxBatchSize <= 0 {
+		maxBatchSize = DefaultOpenAIMaxBatchSize
+	}
+
+	maxRetries := config.MaxRetries
+	switch {
+	case maxRetries == 0:
+		maxRetries = DefaultOpenAIMaxRetries
+	case maxRetries < 0:
+		maxRetries = 0
+	}
+
+	retryDelay := config.RetryDelay
+	if retryDelay <= 0 {
+		retryDelay = DefaultOpenAIRetryDelay
+	}
+
 	return &OpenAIProvider{
-		apiKey:     config.APIKey,
-		model:      model,
-		dimensions: dimensions,
-		client:     client,
+		apiKey:       config.APIKey,
+		model:        model,
+		dimensions:   dimensions,
+		client:       client,
+		url:          openAIEmbeddingsURL,
+		maxBatchSize: maxBatchSize,
+		maxRetries:   maxRetries,
+		retryDelay:   retryDelay,
 	}, nil
 }
 
@@ -93,19 +146,20 @@ func (p *OpenAIProvider) Vectorize(ctx context.Context, text string) (Vector, er
 	return vectors[0], nil
 }
 
-// VectorizeBatch converts multiple texts into vectors in a single request.
+// VectorizeBatch converts multiple texts into vectors, in the order of texts.
+// Texts are sent in sub-batches of MaxBatchSize. A rate-limited sub-batch is retried
+// with exponential backoff, so earlier progress is kept; ErrRateLimitExceeded is
+// returned only after MaxRetries retries. Context cancellation stops waiting between retries.
 func (p *OpenAIProvider) VectorizeBatch(ctx context.Context, texts []string) ([]Vector, error) {
 	if len(texts) == 0 {
 		return []Vector{}, nil
 	}
 
-	// Split into batches if necessary
-	var allVectors []Vector
+	allVectors := make([]Vector, 0, len(texts))
 
-	for i := 0; i < len(texts); i += maxBatchSize {
-		end := min(i+maxBatchSize, len(texts))
-		batch := texts[i:end]
-		vectors, err := p.callAPI(ctx, batch)
+	for i := 0; i < len(texts); i += p.maxBatchSize {
+		end := min(i+p.maxBatchSize, len(texts))
+		vectors, err := p.callAPIWithRetry(ctx, texts[i:end])
 		if err != nil {
 			return nil, err
 		}
@@ -116,6 +170,33 @@ func (p *OpenAIProvider) VectorizeBatch(ctx context.Context, texts []string) ([]
 	return allVectors, nil
 }
 
+// callAPIWithRetry calls the API, retrying rate-limited requests with exponential backoff.
+func (p *OpenAIProvider) callAPIWithRetry(ctx context.Context, texts []string) ([]Vector, error) {
+	delay := p.retryDelay
+	for attempt := 0; ; attempt++ {
+		vectors, err := p.callAPI(ctx, texts)
+		if err == nil || !errors.Is(err, ErrRateLimitExceeded) || attempt >= p.maxRetries {
+			return vectors, err
+		}
+
+		wait := delay
+		var rl *rateLimitError
+		if errors.As(err, &rl) && rl.retryAfter > 0 {
+			wait = rl.retryAfter
+		}
+
+		timer := time.NewTimer(min(wait, maxRetryDelay))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, errors.Join(ctx.Err(), err)
+		case <-timer.C:
+		}
+
+		delay = min(delay*2, maxRetryDelay)
+	}
+}
+
 // Dimensions returns the vector dimensions for the current model.
 func (p *OpenAIProvider) Dimensions() int {
 	return p.dimensions
@@ -135,7 +216,7 @@ func (p *OpenAIProvider) callAPI(ctx context.Context, texts []string) ([]Vector,
 	}
 
 	// Create request
-	req, err := http.NewRequestWithContext(ctx, "POST", openAIEmbeddingsURL, bytes.NewReader(jsonData))
+	req, err := http.NewRequestWithContext(ctx, "POST", p.url, bytes.NewReader(jsonData))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -159,11 +240,17 @@ func (p *OpenAIProvider) callAPI(ctx context.Context, texts []string) ([]Vector,
 	// Check for errors
 	if resp.StatusCode != http.StatusOK {
 		var errorResp openAIErrorResponse
-		if err := json.Unmarshal(body, &errorResp); err == nil && errorResp.Error.Message != "" {
-			// Check for specific error types
-			if strings.Contains(errorResp.Error.Message, "rate limit") {
-				return nil, fmt.Errorf("%w: %s", ErrRateLimitExceeded, errorResp.Error.Message)
+		_ = json.Unmarshal(body, &errorResp)
+
+		if resp.StatusCode == http.StatusTooManyRequests || strings.Contains(errorResp.Error.Message, "rate limit") {
+			return nil, &rateLimitError{
+				err:        fmt.Errorf("%w: %s", ErrRateLimitExceeded, errorResp.Error.Message),
+				retryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
 			}
+		}
+
+		if errorResp.Error.Message != "" {
+			// Check for specific error types
 			if strings.Contains(errorResp.Error.Message, "context length") {
 				return nil, fmt.Errorf("%w: %s", ErrContextLengthExceeded, errorResp.Error.Message)
 			}
@@ -178,15 +265,39 @@ func (p *OpenAIProvider) callAPI(ctx context.Context, texts []string) ([]Vector,
 		return nil, fmt.Errorf("failed to parse response: %w", err)
 	}
 
-	// Extract vectors
-	vectors := make([]Vector, len(response.Data))
-	for i, item := range response.Data {
-		vectors[i] = Vector(item.Embedding)
+	// Place vectors by their input index, the API doesn't guarantee response order
+	if len(response.Data) != len(texts) {
+		return nil, fmt.Errorf("%w: expected %d embeddings, got %d", ErrVectorizationFailed, len(texts), len(response.Data))
+	}
+	vectors := make([]Vector, len(texts))
+	for _, item := range response.Data {
+		if item.Index < 0 || item.Index >= len(texts) || vectors[item.Index] != nil {
+			return nil, fmt.Errorf("%w: unexpected embedding index %d", ErrVectorizationFailed, item.Index)
+		}
+		vectors[item.Index] = Vector(item.Embedding)
 	}
 
 	return vectors, nil
 }
 
+// rateLimitError is a rate-limit response, with the delay requested by the API if any.
+type rateLimitError struct {
+	err        error
+	retryAfter time.Duration
+}
+
+func (e *rateLimitError) Error() string { return e.err.Error() }
+func (e *rateLimitError) Unwrap() error { return e.err }
+
+// parseRetryAfter parses a Retry-After header given in seconds.
+func parseRetryAfter(value string) time.Duration {
+	seconds, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || seconds <= 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
+
 // getModelDimensions returns the vector dimensions for a given model.
 func getModelDimensions(model string) int {
 	switch model {
@@ -0,0 +1,151 @@
+package vectorizer
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestOpenAIServer serves embeddings where each vector holds the numeric input text.
+// Data items are returned in reverse order to verify that output follows input order.
+// rateLimited decides whether a request, numbered from 1, is answered with HTTP 429.
+func newTestOpenAIServer(t *testing.T, rateLimited func(request int64) bool) (*httptest.Server, *atomic.Int64, *[]int) {
+	t.Helper()
+
+	var (
+		requests   atomic.Int64
+		mu         sync.Mutex
+		batchSizes []int
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := requests.Add(1)
+
+		var req openAIRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		if rateLimited(n) {
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = w.Write([]byte(`{"error":{"message":"Rate limit reached","type":"requests"}}`))
+			return
+		}
+
+		mu.Lock()
+		batchSizes = append(batchSizes, len(req.Input))
+		mu.Unlock()
+
+		var resp openAIResponse
+		for i := len(req.Input) - 1; i >= 0; i-- {
+			v, _ := strconv.ParseFloat(req.Input[i], 64)
+			resp.Data = append(resp.Data, struct {
+				Embedding []float64 `json:"embedding"`
+				Index     int       `json:"index"`
+			}{Embedding: []float64{v}, Index: i})
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv, &requests, &batchSizes
+}
+
+func newTestOpenAIProvider(t *testing.T, url string, config OpenAIConfig) *OpenAIProvider {
+	t.Helper()
+
+	config.APIKey = "test-key"
+	provider, err := NewOpenAIProvider(config)
+	require.NoError(t, err)
+	provider.url = url
+	return provider
+}
+
+func TestOpenAIProvider_VectorizeBatch(t *testing.T) {
+	texts := make([]string, 250)
+	for i := range texts {
+		texts[i] = strconv.Itoa(i)
+	}
+
+	t.Run("defaults", func(t *testing.T) {
+		provider := newTestOpenAIProvider(t, "", OpenAIConfig{})
+		assert.Equal(t, DefaultOpenAIMaxBatchSize, provider.maxBatchSize)
+		assert.Equal(t, DefaultOpenAIMaxRetries, provider.maxRetries)
+		assert.Equal(t, DefaultOpenAIRetryDelay, provider.retryDelay)
+
+		provider = newTestOpenAIProvider(t, "", OpenAIConfig{MaxRetries: -1})
+		assert.Zero(t, provider.maxRetries)
+	})
+
+	t.Run("splits into sub-batches and retries rate-limited ones in order", func(t *testing.T) {
+		// The second sub-batch is rate limited twice
+		srv, requests, batchSizes := newTestOpenAIServer(t, func(n int64) bool { return n == 2 || n == 3 })
+		provider := newTestOpenAIProvider(t, srv.URL, OpenAIConfig{
+			MaxBatchSize: 100,
+			RetryDelay:   time.Millisecond,
+		})
+
+		vectors, err := provider.VectorizeBatch(context.Background(), texts)
+		require.NoError(t, err)
+		require.Len(t, vectors, len(texts))
+		for i, v := range vectors {
+			assert.Equal(t, Vector{float64(i)}, v)
+		}
+
+		assert.Equal(t, int64(5), requests.Load())
+		assert.Equal(t, []int{100, 100, 50}, *batchSizes)
+	})
+
+	t.Run("returns rate limit error after exhausting retries", func(t *testing.T) {
+		srv, requests, _ := newTestOpenAIServer(t, func(int64) bool { return true })
+		provider := newTestOpenAIProvider(t, srv.URL, OpenAIConfig{
+			MaxRetries: 2,
+			RetryDelay: time.Millisecond,
+		})
+
+		_, err := provider.VectorizeBatch(context.Background(), texts[:10])
+		assert.ErrorIs(t, err, ErrRateLimitExceeded)
+		assert.Equal(t, int64(3), requests.Load())
+	})
+
+	t.Run("retries can be disabled", func(t *testing.T) {
+		srv, requests, _ := newTestOpenAIServer(t, func(int64) bool { return true })
+		provider := newTestOpenAIProvider(t, srv.URL, OpenAIConfig{MaxRetries: -1})
+
+		_, err := provider.VectorizeBatch(context.Background(), texts[:10])
+		assert.ErrorIs(t, err, ErrRateLimitExceeded)
+		assert.Equal(t, int64(1), requests.Load())
+	})
+
+	t.Run("stops waiting when context is cancelled", func(t *testing.T) {
+		srv, requests, _ := newTestOpenAIServer(t, func(int64) bool { return true })
+		provider := newTestOpenAIProvider(t, srv.URL, OpenAIConfig{RetryDelay: time.Minute})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		_, err := provider.VectorizeBatch(ctx, texts[:10])
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.ErrorIs(t, err, ErrRateLimitExceeded)
+		assert.Less(t, time.Since(start), 5*time.Second)
+		assert.Equal(t, int64(1), requests.Load())
+	})
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	assert.Equal(t, 3*time.Second, parseRetryAfter("3"))
+	assert.Zero(t, parseRetryAfter(""))
+	assert.Zero(t, parseRetryAfter("Wed, 21 Oct 2015 07:28:00 GMT"))
+	assert.Zero(t, parseRetryAfter("-1"))
+}
@@ -0,0 +1,137 @@
+package vectorizer
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+)
+
+// CosineSimilarity returns the cosine of the angle between a and b, from -1 to 1.
+// Returns ErrDimensionMismatch when the vectors have different lengths, and 0 when
+// either vector has zero magnitude, since the angle is undefined.
+func CosineSimilarity(a, b Vector) (float64, error) {
+	if len(a) != len(b) {
+		return 0, errors.Join(ErrDimensionMismatch, fmt.Errorf("got %d and %d", len(a), len(b)))
+	}
+
+	var dot, normA, normB float64
+	for i := range a {
+		dot += a[i] * b[i]
+		normA += a[i] * a[i]
+		normB += b[i] * b[i]
+	}
+
+	return cosine(dot, normA, normB), nil
+}
+
+// TopK returns the k candidates most similar to query, sorted by descending Score,
+// which holds the cosine similarity. Candidates with equal scores keep their input order.
+// Returns all candidates when k exceeds their number, ErrInvalidK when k isn't positive,
+// and ErrDimensionMismatch when a candidate vector doesn't match the query.
+// Candidates are not modified; the returned chunks share their vectors.
+func TopK(query Vector, candidates []Chunk, k int) ([]Chunk, error) {
+	if k <= 0 {
+		return nil, ErrInvalidK
+	}
+
+	var queryNorm float64
+	for _, v := range query {
+		queryNorm += v * v
+	}
+
+	// Min-heap of the best k candidates seen so far, the worst at the root,
+	// so most candidates are rejected with a single comparison
+	best := make([]scoredChunk, 0, min(k, len(candidates)))
+	for i := range candidates {
+		vec := candidates[i].Vector
+		if len(vec) != len(query) {
+			return nil, errors.Join(ErrDimensionMismatch,
+				fmt.Errorf("candidate %d has %d dimensions, query has %d", i, len(vec), len(query)))
+		}
+
+		var dot, norm float64
+		for j, v := range vec {
+			dot += query[j] * v
+			norm += v * v
+		}
+		s := scoredChunk{index: i, score: cosine(dot, queryNorm, norm)}
+
+		if len(best) < k {
+			best = append(best, s)
+			siftUp(best, len(best)-1)
+			continue
+		}
+		if worse(best[0], s) {
+			best[0] = s
+			siftDown(best, 0)
+		}
+	}
+
+	slices.SortFunc(best, func(a, b scoredChunk) int {
+		switch {
+		case worse(b, a):
+			return -1
+		case worse(a, b):
+			return 1
+		}
+		return 0
+	})
+
+	result := make([]Chunk, len(best))
+	for i, s := range best {
+		result[i] = candidates[s.index]
+		result[i].Score = s.score
+	}
+
+	return result, nil
+}
+
+// cosine computes the similarity from a dot product and squared norms.
+func cosine(dot, normA, normB float64) float64 {
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
+
+type scoredChunk struct {
+	index int
+	score float64
+}
+
+// worse reports whether a ranks below b: a lower score, or an equal score later in the input.
+func worse(a, b scoredChunk) bool {
+	if a.score != b.score {
+		return a.score < b.score
+	}
+	return a.index > b.index
+}
+
+func siftUp(h []scoredChunk, i int) {
+	for i > 0 {
+		parent := (i - 1) / 2
+		if !worse(h[i], h[parent]) {
+			return
+		}
+		h[i], h[parent] = h[parent], h[i]
+		i = parent
+	}
+}
+
+func siftDown(h []scoredChunk, i int) {
+	for {
+		smallest := i
+		if l := 2*i + 1; l < len(h) && worse(h[l], h[smallest]) {
+			smallest = l
+		}
+		if r := 2*i + 2; r < len(h) && worse(h[r], h[smallest]) {
+			smallest = r
+		}
+		if smallest == i {
+			return
+		}
+		h[i], h[smallest] = h[smallest], h[i]
+		i = smallest
+	}
+}
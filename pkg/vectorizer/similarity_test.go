@@ -0,0 +1,93 @@
+package vectorizer
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCosineSimilarity(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b Vector
+		want float64
+	}{
+		{name: "identical", a: Vector{1, 2, 3}, b: Vector{1, 2, 3}, want: 1},
+		{name: "scaled", a: Vector{1, 2, 3}, b: Vector{2, 4, 6}, want: 1},
+		{name: "orthogonal", a: Vector{1, 0}, b: Vector{0, 1}, want: 0},
+		{name: "opposite", a: Vector{1, -1}, b: Vector{-1, 1}, want: -1},
+		{name: "zero vector", a: Vector{0, 0, 0}, b: Vector{1, 2, 3}, want: 0},
+		{name: "both zero", a: Vector{0, 0}, b: Vector{0, 0}, want: 0},
+		{name: "empty", a: Vector{}, b: Vector{}, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CosineSimilarity(tt.a, tt.b)
+			require.NoError(t, err)
+			assert.False(t, math.IsNaN(got))
+			assert.InDelta(t, tt.want, got, 1e-9)
+		})
+	}
+
+	t.Run("dimension mismatch", func(t *testing.T) {
+		_, err := CosineSimilarity(Vector{1, 2}, Vector{1, 2, 3})
+		assert.ErrorIs(t, err, ErrDimensionMismatch)
+	})
+}
+
+func TestTopK(t *testing.T) {
+	query := Vector{1, 0}
+	candidates := []Chunk{
+		{Text: "orthogonal", Vector: Vector{0, 1}, Index: 0},
+		{Text: "exact", Vector: Vector{2, 0}, Index: 1},
+		{Text: "close", Vector: Vector{1, 0.2}, Index: 2},
+		{Text: "opposite", Vector: Vector{-1, 0}, Index: 3},
+		{Text: "zero", Vector: Vector{0, 0}, Index: 4},
+		{Text: "exact again", Vector: Vector{5, 0}, Index: 5},
+	}
+
+	t.Run("returns best matches in descending order", func(t *testing.T) {
+		result, err := TopK(query, candidates, 3)
+		require.NoError(t, err)
+		require.Len(t, result, 3)
+
+		// Equal scores keep input order
+		assert.Equal(t, "exact", result[0].Text)
+		assert.Equal(t, "exact again", result[1].Text)
+		assert.Equal(t, "close", result[2].Text)
+		assert.InDelta(t, 1, result[0].Score, 1e-9)
+		assert.Greater(t, result[1].Score, result[2].Score)
+
+		// Candidates are not modified
+		assert.Zero(t, candidates[1].Score)
+	})
+
+	t.Run("k larger than candidates returns all sorted", func(t *testing.T) {
+		result, err := TopK(query, candidates, 100)
+		require.NoError(t, err)
+		require.Len(t, result, len(candidates))
+		for i := 1; i < len(result); i++ {
+			assert.GreaterOrEqual(t, result[i-1].Score, result[i].Score)
+		}
+		assert.Equal(t, "opposite", result[len(result)-1].Text)
+	})
+
+	t.Run("no candidates", func(t *testing.T) {
+		result, err := TopK(query, nil, 3)
+		require.NoError(t, err)
+		assert.Empty(t, result)
+	})
+
+	t.Run("invalid k", func(t *testing.T) {
+		_, err := TopK(query, candidates, 0)
+		assert.ErrorIs(t, err, ErrInvalidK)
+	})
+
+	t.Run("dimension mismatch", func(t *testing.T) {
+		_, err := TopK(Vector{1, 0, 0}, candidates, 3)
+		assert.ErrorIs(t, err, ErrDimensionMismatch)
+	})
+}
@@ -0,0 +1,75 @@
+package vectorizer
+
+// Tokenizer counts tokens in text, used for chunking decisions.
+// Implement it with a model-specific tokenizer, e.g. a tiktoken-compatible counter,
+// when the heuristic is too inaccurate for your content.
+type Tokenizer interface {
+	CountTokens(text string) int
+}
+
+// TokenizerFunc adapts a plain function to the Tokenizer interface.
+type TokenizerFunc func(text string) int
+
+// CountTokens calls f(text).
+func (f TokenizerFunc) CountTokens(text string) int {
+	return f(text)
+}
+
+// HeuristicTokenizer estimates tokens as 1 word ≈ 1.3 tokens, splitting words on whitespace.
+// It's fast and good enough for English prose, but underestimates code and languages
+// written without spaces such as Chinese or Japanese. It's the default Tokenizer.
+type HeuristicTokenizer struct{}
+
+// CountTokens returns the estimated token count of text.
+func (HeuristicTokenizer) CountTokens(text string) int {
+	if text == "" {
+		return 0
+	}
+
+	// Simple word counting
+	wordCount := 0
+	inWord := false
+
+	for _, r := range text {
+		if r == ' ' || r == '\t' || r == '\n' || r == '\r' {
+			if inWord {
+				wordCount++
+				inWord = false
+			}
+		} else {
+			inWord = true
+		}
+	}
+
+	if inWord {
+		wordCount++
+	}
+
+	// Approximation: 1 word ≈ 1.3 tokens
+	return int(float64(wordCount) * 1.3)
+}
+
+// Option configures a Vectorizer or a SimpleChunker.
+type Option func(*options)
+
+type options struct {
+	tokenizer Tokenizer
+}
+
+// WithTokenizer sets the Tokenizer used to count tokens (default HeuristicTokenizer).
+// A nil tokenizer is ignored.
+func WithTokenizer(tokenizer Tokenizer) Option {
+	return func(o *options) {
+		if tokenizer != nil {
+			o.tokenizer = tokenizer
+		}
+	}
+}
+
+func applyOptions(opts []Option) options {
+	o := options{tokenizer: HeuristicTokenizer{}}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
@@ -0,0 +1,78 @@
+package vectorizer
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runeTokenizer counts every non-space character as a token, like tokenizers do for CJK text.
+var runeTokenizer = TokenizerFunc(func(text string) int {
+	return utf8.RuneCountInString(strings.Join(strings.Fields(text), ""))
+})
+
+func TestHeuristicTokenizer(t *testing.T) {
+	for _, text := range []string{"", "hello world", "one two three four five"} {
+		assert.Equal(t, EstimateTokens(text), HeuristicTokenizer{}.CountTokens(text))
+	}
+}
+
+func TestSimpleChunker_WithTokenizer(t *testing.T) {
+	t.Run("chunks fit the injected tokenizer", func(t *testing.T) {
+		chunker := NewSimpleChunkerWithOptions(false, WithTokenizer(runeTokenizer))
+		text := "alpha beta gamma delta epsilon zeta eta theta iota kappa lambda"
+
+		chunks := chunker.Split(text, ChunkOptions{MaxTokens: 12, MinChunkSize: 1})
+
+		require.Greater(t, len(chunks), 1)
+		for _, chunk := range chunks {
+			assert.LessOrEqual(t, runeTokenizer.CountTokens(chunk), 12, chunk)
+		}
+		assert.Equal(t, strings.Fields(text), strings.Fields(strings.Join(chunks, " ")))
+	})
+
+	t.Run("overlap is measured with the injected tokenizer", func(t *testing.T) {
+		chunker := NewSimpleChunkerWithOptions(false, WithTokenizer(runeTokenizer))
+
+		chunks := chunker.Split("aa bb cc dd ee ff", ChunkOptions{MaxTokens: 6, Overlap: 2, MinChunkSize: 1})
+
+		assert.Equal(t, []string{"aa bb cc", "cc dd ee", "ee ff"}, chunks)
+	})
+
+	t.Run("splits text without spaces by characters", func(t *testing.T) {
+		chunker := NewSimpleChunker(WithTokenizer(runeTokenizer))
+		text := "自然言語処理は人工知能の一分野であり計算機で人間の言語を扱う技術です"
+
+		// The heuristic sees a single word and would keep it whole
+		assert.Equal(t, []string{text}, NewSimpleChunker().Split(text, ChunkOptions{MaxTokens: 10}))
+
+		chunks := chunker.Split(text, ChunkOptions{MaxTokens: 10, MinChunkSize: 1})
+		require.Len(t, chunks, 4)
+		for _, chunk := range chunks {
+			assert.LessOrEqual(t, runeTokenizer.CountTokens(chunk), 10)
+		}
+		assert.Equal(t, text, strings.Join(chunks, ""))
+	})
+
+	t.Run("nil tokenizer keeps the default", func(t *testing.T) {
+		chunker := NewSimpleChunker(WithTokenizer(nil))
+		assert.Equal(t, HeuristicTokenizer{}, chunker.tokenizer)
+	})
+}
+
+func TestVectorizer_WithTokenizer(t *testing.T) {
+	v, err := NewWithDefaults(&MockProvider{}, WithTokenizer(runeTokenizer))
+	require.NoError(t, err)
+
+	assert.Equal(t, 8, v.CountTokens("hello 世界!"))
+	chunker, ok := v.chunker.(*SimpleChunker)
+	require.True(t, ok)
+	assert.Equal(t, 8, chunker.tokenizer.CountTokens("hello 世界!"))
+
+	v, err = New(&MockProvider{}, NewSimpleChunker())
+	require.NoError(t, err)
+	assert.Equal(t, EstimateTokens("hello world"), v.CountTokens("hello world"))
+}
@@ -13,9 +13,12 @@ type Vector []float64
 // Chunk represents a piece of text with its corresponding vector embedding.
 // Used when processing long texts that need to be split into smaller parts.
 type Chunk struct {
-	Text   string `json:"text"`
-	Vector Vector `json:"vector"`
-	Index  int    `json:"index"` // Position in the original text
+	Text        string  `json:"text"`
+	Vector      Vector  `json:"vector"`
+	Index       int     `json:"index"`           // Position in the original text
+	Score       float64 `json:"score,omitempty"` // Similarity to the query, set by TopK
+	StartOffset int     `json:"start_offset"`    // Byte offset of the chunk's source in the original text, -1 if unknown
+	EndOffset   int     `json:"end_offset"`      // Byte offset after the chunk's source, -1 if unknown
 }
 
 // Provider defines the interface for vectorization backends.
@@ -36,29 +39,35 @@ type Provider interface {
 // It uses a Provider for the actual embedding generation and a Chunker
 // for text splitting, adding convenience methods for batch processing.
 type Vectorizer struct {
-	provider Provider
-	chunker  Chunker
+	provider  Provider
+	chunker   Chunker
+	tokenizer Tokenizer
 }
 
 // New creates a new Vectorizer with the specified provider and chunker.
 // Returns an error if provider or chunker is nil.
-func New(provider Provider, chunker Chunker) (*Vectorizer, error) {
+// WithTokenizer sets the tokenizer used by CountTokens; it doesn't change
+// the chunker, which is configured on its own.
+func New(provider Provider, chunker Chunker, opts ...Option) (*Vectorizer, error) {
 	if provider == nil {
 		return nil, ErrProviderNotSet
 	}
 	if chunker == nil {
 		return nil, ErrChunkerNotSet
 	}
+	o := applyOptions(opts)
 	return &Vectorizer{
-		provider: provider,
-		chunker:  chunker,
+		provider:  provider,
+		chunker:   chunker,
+		tokenizer: o.tokenizer,
 	}, nil
 }
 
 // NewWithDefaults creates a new Vectorizer with the specified provider
 // and a default SimpleChunker for convenience.
-func NewWithDefaults(provider Provider) (*Vectorizer, error) {
-	return New(provider, NewSimpleChunker())
+// The tokenizer set with WithTokenizer is shared with the chunker.
+func NewWithDefaults(provider Provider, opts ...Option) (*Vectorizer, error) {
+	return New(provider, NewSimpleChunker(opts...), opts...)
 }
 
 // ToVector converts a single text string into a vector embedding.
@@ -116,35 +125,14 @@ func (v *Vectorizer) ChunksToVectors(ctx context.Context, chunks []string) ([]Ve
 
 // Process splits a long text into chunks and vectorizes each chunk.
 // This is the main entry point for processing documents or articles.
-// Returns a slice of Chunks, each containing text and its vector.
+// Returns a slice of Chunks, each containing text, its vector and the byte
+// offsets of its source in text, e.g. for highlighting search hits.
 func (v *Vectorizer) Process(ctx context.Context, text string, options ChunkOptions) ([]Chunk, error) {
 	if v.provider == nil {
 		return nil, ErrProviderNotSet
 	}
 
-	// Split text into chunks using the configured chunker
-	textChunks := v.chunker.Split(text, options)
-	if len(textChunks) == 0 {
-		return []Chunk{}, nil
-	}
-
-	// Vectorize all chunks
-	vectors, err := v.ChunksToVectors(ctx, textChunks)
-	if err != nil {
-		return nil, err
-	}
-
-	// Combine texts and vectors into Chunk structs
-	chunks := make([]Chunk, len(textChunks))
-	for i := range len(textChunks) {
-		chunks[i] = Chunk{
-			Text:   textChunks[i],
-			Vector: vectors[i],
-			Index:  i,
-		}
-	}
-
-	return chunks, nil
+	return v.process(ctx, text, v.chunker, options)
 }
 
 // Dimensions returns the vector dimensions for the current provider's model.
@@ -155,6 +143,15 @@ func (v *Vectorizer) Dimensions() int {
 	return v.provider.Dimensions()
 }
 
+// CountTokens counts tokens in text with the configured Tokenizer.
+// Useful to check texts against the model's context length before vectorizing.
+func (v *Vectorizer) CountTokens(text string) int {
+	if v.tokenizer == nil {
+		return EstimateTokens(text)
+	}
+	return v.tokenizer.CountTokens(text)
+}
+
 // Chunk splits text into chunks using the configured chunker.
 // This is useful when you want to chunk text without vectorizing it.
 func (v *Vectorizer) Chunk(text string, options ChunkOptions) []string {
@@ -184,12 +181,22 @@ func (v *Vectorizer) ProcessWithChunker(ctx context.Context, text string, chunke
 		return nil, ErrChunkerNotSet
 	}
 
-	// Split text into chunks using the provided chunker
-	textChunks := chunker.Split(text, options)
-	if len(textChunks) == 0 {
+	return v.process(ctx, text, chunker, options)
+}
+
+// process splits text with chunker, locates the chunks in text and vectorizes them.
+func (v *Vectorizer) process(ctx context.Context, text string, chunker Chunker, options ChunkOptions) ([]Chunk, error) {
+	// Split text into chunks, with their byte ranges in text
+	spans := splitWithOffsets(chunker, text, options)
+	if len(spans) == 0 {
 		return []Chunk{}, nil
 	}
 
+	textChunks := make([]string, len(spans))
+	for i, span := range spans {
+		textChunks[i] = span.Text
+	}
+
 	// Vectorize all chunks
 	vectors, err := v.ChunksToVectors(ctx, textChunks)
 	if err != nil {
@@ -197,12 +204,14 @@ func (v *Vectorizer) ProcessWithChunker(ctx context.Context, text string, chunke
 	}
 
 	// Combine texts and vectors into Chunk structs
-	chunks := make([]Chunk, len(textChunks))
-	for i := range len(textChunks) {
+	chunks := make([]Chunk, len(spans))
+	for i, span := range spans {
 		chunks[i] = Chunk{
-			Text:   textChunks[i],
-			Vector: vectors[i],
-			Index:  i,
+			Text:        span.Text,
+			Vector:      vectors[i],
+			Index:       i,
+			StartOffset: span.Start,
+			EndOffset:   span.End,
 		}
 	}
 
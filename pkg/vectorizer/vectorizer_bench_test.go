@@ -289,3 +289,23 @@ func generateChunks(n int) []string {
 	}
 	return chunks
 }
+
+// BenchmarkTopK tests similarity search over a few thousand candidates
+func BenchmarkTopK(b *testing.B) {
+	const dimensions = 1536
+	candidates := make([]Chunk, 5000)
+	for i := range candidates {
+		vec := make(Vector, dimensions)
+		for j := range vec {
+			vec[j] = float64((i*31+j*17)%100) / 100
+		}
+		candidates[i] = Chunk{Text: strconv.Itoa(i), Vector: vec, Index: i}
+	}
+	query := candidates[42].Vector
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = TopK(query, candidates, 10)
+	}
+}
@@ -0,0 +1,214 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// AsyncSender defaults
+const (
+	DefaultAsyncWorkers   = 4
+	DefaultAsyncQueueSize = 100
+)
+
+// AsyncErrorHandler is called from a worker goroutine when an async delivery
+// finally fails, after all retries.
+type AsyncErrorHandler func(webhookURL string, err error)
+
+type asyncOptions struct {
+	workers      int
+	queueSize    int
+	errorHandler AsyncErrorHandler
+}
+
+// AsyncOption configures an AsyncSender
+type AsyncOption func(*asyncOptions)
+
+// WithAsyncWorkers sets the number of concurrent delivery goroutines.
+// Default is 4.
+func WithAsyncWorkers(n int) AsyncOption {
+	return func(o *asyncOptions) {
+		if n > 0 {
+			o.workers = n
+		}
+	}
+}
+
+// WithAsyncQueueSize sets how many deliveries can wait for a worker before
+// SendAsync blocks. Default is 100.
+func WithAsyncQueueSize(n int) AsyncOption {
+	return func(o *asyncOptions) {
+		if n > 0 {
+			o.queueSize = n
+		}
+	}
+}
+
+// WithAsyncErrorHandler sets a callback for deliveries that failed after all retries.
+// Per-attempt results are reported through WithOnDelivery as with Send.
+func WithAsyncErrorHandler(handler AsyncErrorHandler) AsyncOption {
+	return func(o *asyncOptions) {
+		o.errorHandler = handler
+	}
+}
+
+// asyncJob is a marshaled payload waiting for a worker
+type asyncJob struct {
+	ctx     context.Context
+	url     string
+	payload json.RawMessage
+	opts    []SendOption
+}
+
+// AsyncSender delivers webhooks in the background through a bounded worker pool.
+// Deliveries live in memory only; use modules/webhooks when they must survive restarts.
+// Safe for concurrent use.
+type AsyncSender struct {
+	sender *Sender
+	opts   asyncOptions
+
+	queue chan asyncJob
+	done  chan struct{} // Closed by Close to stop accepting deliveries
+
+	// Cancels in-flight deliveries when Close gives up draining
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu        sync.RWMutex
+	closed    bool
+	enqueuers sync.WaitGroup // SendAsync calls that may still write to queue
+	workers   sync.WaitGroup
+	closeOnce sync.Once
+}
+
+// NewAsyncSender starts the worker pool on top of sender.
+// A nil sender is replaced with NewSender(). Call Close to stop the workers.
+func NewAsyncSender(sender *Sender, opts ...AsyncOption) *AsyncSender {
+	if sender == nil {
+		sender = NewSender()
+	}
+
+	o := asyncOptions{
+		workers:   DefaultAsyncWorkers,
+		queueSize: DefaultAsyncQueueSize,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	a := &AsyncSender{
+		sender: sender,
+		opts:   o,
+		queue:  make(chan asyncJob, o.queueSize),
+		done:   make(chan struct{}),
+		ctx:    ctx,
+		cancel: cancel,
+	}
+
+	a.workers.Add(o.workers)
+	for range o.workers {
+		go a.work()
+	}
+
+	return a
+}
+
+// SendAsync validates and marshals data, then enqueues the delivery and returns.
+// When the queue is full it blocks until a worker frees a slot or ctx is done,
+// so callers get backpressure instead of silently dropped webhooks.
+//
+// The delivery keeps ctx values but not its cancellation, so enqueueing from an
+// HTTP handler is safe. Results are reported from worker goroutines through
+// WithOnDelivery, WithInstrumentation and WithAsyncErrorHandler.
+func (a *AsyncSender) SendAsync(ctx context.Context, webhookURL string, data any, opts ...SendOption) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload to JSON: %w", err)
+	}
+	if err := a.sender.validateInputs(webhookURL, payload); err != nil {
+		return err
+	}
+
+	a.mu.RLock()
+	if a.closed {
+		a.mu.RUnlock()
+		return ErrSenderClosed
+	}
+	a.enqueuers.Add(1)
+	a.mu.RUnlock()
+	defer a.enqueuers.Done()
+
+	job := asyncJob{
+		ctx:     context.WithoutCancel(ctx),
+		url:     webhookURL,
+		payload: payload,
+		opts:    opts,
+	}
+
+	select {
+	case a.queue <- job:
+		return nil
+	case <-a.done:
+		return ErrSenderClosed
+	case <-ctx.Done():
+		return fmt.Errorf("%w: %w", ErrQueueFull, ctx.Err())
+	}
+}
+
+// Close stops accepting deliveries and waits for queued and in-flight ones to finish.
+// If ctx is done first, remaining deliveries are cancelled and ctx.Err() is returned.
+// SendAsync calls blocked on a full queue return ErrSenderClosed.
+func (a *AsyncSender) Close(ctx context.Context) error {
+	a.closeOnce.Do(func() {
+		a.mu.Lock()
+		a.closed = true
+		close(a.done)
+		a.mu.Unlock()
+
+		// Workers drain the queue and exit once no SendAsync can write to it
+		go func() {
+			a.enqueuers.Wait()
+			close(a.queue)
+		}()
+	})
+
+	drained := make(chan struct{})
+	go func() {
+		a.workers.Wait()
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+		a.cancel()
+		return nil
+	case <-ctx.Done():
+		a.cancel()
+		return ctx.Err()
+	}
+}
+
+// work delivers queued webhooks until the queue is closed
+func (a *AsyncSender) work() {
+	defer a.workers.Done()
+
+	for job := range a.queue {
+		a.deliver(job)
+	}
+}
+
+// deliver sends one job, aborting when Close cancels the pool
+func (a *AsyncSender) deliver(job asyncJob) {
+	ctx, cancel := context.WithCancel(job.ctx)
+	defer cancel()
+	stop := context.AfterFunc(a.ctx, cancel)
+	defer stop()
+
+	err := a.sender.Send(ctx, job.url, job.payload, job.opts...)
+	if err != nil && a.opts.errorHandler != nil {
+		a.opts.errorHandler(job.url, err)
+	}
+}
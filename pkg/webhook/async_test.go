@@ -0,0 +1,172 @@
+package webhook_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dmitrymomot/saaskit/pkg/webhook"
+)
+
+func TestAsyncSender_SendAsync(t *testing.T) {
+	t.Parallel()
+
+	var received atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received.Add(1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	var mu sync.Mutex
+	var results []webhook.DeliveryResult
+	onDelivery := webhook.WithOnDelivery(func(result webhook.DeliveryResult) {
+		mu.Lock()
+		defer mu.Unlock()
+		results = append(results, result)
+	})
+
+	async := webhook.NewAsyncSender(webhook.NewSender(), webhook.WithAsyncWorkers(3))
+
+	for i := range 10 {
+		err := async.SendAsync(context.Background(), server.URL, map[string]int{"n": i}, onDelivery)
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, async.Close(context.Background()))
+	assert.Equal(t, int32(10), received.Load())
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, results, 10)
+	for _, r := range results {
+		assert.True(t, r.Success)
+	}
+}
+
+func TestAsyncSender_ValidatesBeforeEnqueue(t *testing.T) {
+	t.Parallel()
+
+	async := webhook.NewAsyncSender(nil)
+	defer async.Close(context.Background())
+
+	err := async.SendAsync(context.Background(), "ftp://example.com", map[string]string{"a": "b"})
+	assert.ErrorIs(t, err, webhook.ErrInvalidURL)
+
+	err = async.SendAsync(context.Background(), "https://example.com", make(chan int))
+	assert.Error(t, err)
+}
+
+func TestAsyncSender_Backpressure(t *testing.T) {
+	t.Parallel()
+
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	defer close(release)
+
+	async := webhook.NewAsyncSender(webhook.NewSender(),
+		webhook.WithAsyncWorkers(1),
+		webhook.WithAsyncQueueSize(1),
+	)
+
+	// One delivery occupies the worker, the next fills the queue
+	require.NoError(t, async.SendAsync(context.Background(), server.URL, "first"))
+	require.NoError(t, async.SendAsync(context.Background(), server.URL, "second"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := async.SendAsync(ctx, server.URL, "third")
+	assert.ErrorIs(t, err, webhook.ErrQueueFull)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond, "should block until the deadline")
+
+	closeCtx, closeCancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer closeCancel()
+	assert.ErrorIs(t, async.Close(closeCtx), context.DeadlineExceeded)
+}
+
+func TestAsyncSender_Close(t *testing.T) {
+	t.Parallel()
+
+	var received atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(20 * time.Millisecond)
+		received.Add(1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	async := webhook.NewAsyncSender(webhook.NewSender(), webhook.WithAsyncWorkers(2))
+	for range 5 {
+		require.NoError(t, async.SendAsync(context.Background(), server.URL, "payload"))
+	}
+
+	// Close drains queued deliveries before returning
+	require.NoError(t, async.Close(context.Background()))
+	assert.Equal(t, int32(5), received.Load())
+
+	err := async.SendAsync(context.Background(), server.URL, "late")
+	assert.ErrorIs(t, err, webhook.ErrSenderClosed)
+
+	// Closing twice is safe
+	assert.NoError(t, async.Close(context.Background()))
+}
+
+func TestAsyncSender_DetachedFromCallerContext(t *testing.T) {
+	t.Parallel()
+
+	var received atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received.Add(1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	async := webhook.NewAsyncSender(webhook.NewSender())
+
+	// Enqueueing from a request that finishes right away must still deliver
+	ctx, cancel := context.WithCancel(context.Background())
+	require.NoError(t, async.SendAsync(ctx, server.URL, "payload"))
+	cancel()
+
+	require.NoError(t, async.Close(context.Background()))
+	assert.Equal(t, int32(1), received.Load())
+}
+
+func TestAsyncSender_ErrorHandler(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	var failedURL atomic.Value
+	var failure atomic.Value
+	async := webhook.NewAsyncSender(webhook.NewSender(),
+		webhook.WithAsyncErrorHandler(func(webhookURL string, err error) {
+			failedURL.Store(webhookURL)
+			failure.Store(err)
+		}),
+	)
+
+	require.NoError(t, async.SendAsync(context.Background(), server.URL, "payload", webhook.WithNoRetry()))
+	require.NoError(t, async.Close(context.Background()))
+
+	assert.Equal(t, server.URL, failedURL.Load())
+	err, _ := failure.Load().(error)
+	assert.ErrorIs(t, err, webhook.ErrPermanentFailure)
+}
@@ -0,0 +1,98 @@
+package webhook
+
+import (
+	"net/url"
+	"sync"
+	"time"
+)
+
+// BreakerRegistry hands out one CircuitBreaker per endpoint, created lazily
+// with shared configuration. Safe for concurrent use.
+type BreakerRegistry struct {
+	mu       sync.RWMutex
+	breakers map[string]*CircuitBreaker
+
+	failureThreshold int
+	successThreshold int
+	recoveryTimeout  time.Duration
+}
+
+// NewBreakerRegistry creates a registry whose breakers are built with
+// NewCircuitBreaker(failureThreshold, successThreshold, recoveryTimeout),
+// so non-positive values fall back to the same defaults.
+func NewBreakerRegistry(failureThreshold, successThreshold int, recoveryTimeout time.Duration) *BreakerRegistry {
+	return &BreakerRegistry{
+		breakers:         make(map[string]*CircuitBreaker),
+		failureThreshold: failureThreshold,
+		successThreshold: successThreshold,
+		recoveryTimeout:  recoveryTimeout,
+	}
+}
+
+// Get returns the breaker for endpoint, creating it on first use.
+// WithBreakerRegistry keys endpoints with EndpointKey.
+func (r *BreakerRegistry) Get(endpoint string) *CircuitBreaker {
+	r.mu.RLock()
+	cb, ok := r.breakers[endpoint]
+	r.mu.RUnlock()
+	if ok {
+		return cb
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Another goroutine may have created it while we waited for the write lock
+	if cb, ok := r.breakers[endpoint]; ok {
+		return cb
+	}
+	cb = NewCircuitBreaker(r.failureThreshold, r.successThreshold, r.recoveryTimeout)
+	r.breakers[endpoint] = cb
+	return cb
+}
+
+// Remove forgets the breaker for endpoint, e.g. when a subscription is deleted.
+// The next Get starts with a closed breaker.
+func (r *BreakerRegistry) Remove(endpoint string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.breakers, endpoint)
+}
+
+// Stats returns a snapshot of every breaker's statistics keyed by endpoint, for dashboards.
+func (r *BreakerRegistry) Stats() map[string]CircuitStats {
+	r.mu.RLock()
+	breakers := make(map[string]*CircuitBreaker, len(r.breakers))
+	for endpoint, cb := range r.breakers {
+		breakers[endpoint] = cb
+	}
+	r.mu.RUnlock()
+
+	// Breakers lock themselves, don't hold the registry lock meanwhile
+	stats := make(map[string]CircuitStats, len(breakers))
+	for endpoint, cb := range breakers {
+		s := cb.Stats()
+		s.State = cb.State().String() // Reflect a pending open -> half-open transition
+		stats[endpoint] = s
+	}
+	return stats
+}
+
+// EndpointKey returns the registry key for a webhook URL: its host[:port] and path.
+// Query strings and fragments are ignored so per-delivery parameters share a breaker.
+func EndpointKey(webhookURL string) string {
+	u, err := url.Parse(webhookURL)
+	if err != nil {
+		return webhookURL
+	}
+	return u.Host + u.Path
+}
+
+// WithBreakerRegistry selects the circuit breaker from registry by the request
+// URL's EndpointKey. A breaker passed with WithCircuitBreaker takes precedence.
+func WithBreakerRegistry(registry *BreakerRegistry) SendOption {
+	return func(o *sendOptions) {
+		o.breakerRegistry = registry
+	}
+}
@@ -0,0 +1,135 @@
+package webhook_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dmitrymomot/saaskit/pkg/webhook"
+)
+
+func TestBreakerRegistry_Get(t *testing.T) {
+	t.Parallel()
+
+	registry := webhook.NewBreakerRegistry(2, 1, time.Minute)
+
+	a := registry.Get("example.com/hooks")
+	assert.Same(t, a, registry.Get("example.com/hooks"), "should reuse the breaker per endpoint")
+	assert.NotSame(t, a, registry.Get("example.com/other"))
+
+	// Shared config: two failures open the breaker
+	a.RecordFailure()
+	a.RecordFailure()
+	assert.Equal(t, webhook.CircuitOpen, a.State())
+	assert.Equal(t, webhook.CircuitClosed, registry.Get("example.com/other").State())
+
+	registry.Remove("example.com/hooks")
+	assert.NotSame(t, a, registry.Get("example.com/hooks"))
+	assert.Equal(t, webhook.CircuitClosed, registry.Get("example.com/hooks").State())
+}
+
+func TestBreakerRegistry_ConcurrentGet(t *testing.T) {
+	t.Parallel()
+
+	registry := webhook.NewBreakerRegistry(0, 0, 0)
+
+	var wg sync.WaitGroup
+	breakers := make([]*webhook.CircuitBreaker, 50)
+	for i := range breakers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			breakers[i] = registry.Get("example.com/hooks")
+		}()
+	}
+	wg.Wait()
+
+	for _, cb := range breakers {
+		assert.Same(t, breakers[0], cb)
+	}
+	assert.Len(t, registry.Stats(), 1)
+}
+
+func TestBreakerRegistry_Stats(t *testing.T) {
+	t.Parallel()
+
+	registry := webhook.NewBreakerRegistry(1, 1, time.Hour)
+	registry.Get("a.example.com/hooks").RecordFailure()
+	registry.Get("b.example.com/hooks").RecordSuccess()
+
+	stats := registry.Stats()
+	require.Len(t, stats, 2)
+	assert.Equal(t, "open", stats["a.example.com/hooks"].State)
+	assert.Equal(t, 1, stats["a.example.com/hooks"].Failures)
+	assert.Equal(t, "closed", stats["b.example.com/hooks"].State)
+}
+
+func TestEndpointKey(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{url: "https://example.com/hooks", want: "example.com/hooks"},
+		{url: "https://example.com:8443/hooks?token=abc#frag", want: "example.com:8443/hooks"},
+		{url: "http://example.com", want: "example.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, webhook.EndpointKey(tt.url))
+		})
+	}
+}
+
+func TestSender_Send_WithBreakerRegistry(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/failing" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	sender := webhook.NewSender()
+	registry := webhook.NewBreakerRegistry(2, 1, time.Hour)
+	send := func(path string) error {
+		return sender.Send(context.Background(), server.URL+path, map[string]string{"a": "b"},
+			webhook.WithBreakerRegistry(registry),
+			webhook.WithNoRetry(),
+		)
+	}
+
+	require.Error(t, send("/failing"))
+	require.Error(t, send("/failing?attempt=2"))
+	assert.ErrorIs(t, send("/failing"), webhook.ErrCircuitOpen)
+
+	// Other paths on the same host have their own breaker
+	assert.NoError(t, send("/healthy"))
+
+	stats := registry.Stats()
+	host := webhook.EndpointKey(server.URL)
+	assert.Equal(t, "open", stats[host+"/failing"].State)
+	assert.Equal(t, "closed", stats[host+"/healthy"].State)
+
+	// An explicit breaker wins over the registry
+	explicit := webhook.NewCircuitBreaker(5, 1, time.Hour)
+	err := sender.Send(context.Background(), server.URL+"/failing", map[string]string{"a": "b"},
+		webhook.WithBreakerRegistry(registry),
+		webhook.WithCircuitBreaker(explicit),
+		webhook.WithNoRetry(),
+	)
+	assert.NotErrorIs(t, err, webhook.ErrCircuitOpen)
+	assert.Equal(t, 1, explicit.Stats().Failures)
+}
@@ -72,36 +72,50 @@ func NewCircuitBreaker(failureThreshold, successThreshold int, recoveryTimeout t
 // Allow checks if a request should be allowed through the circuit breaker.
 // Uses a write lock since it may transition from open to half-open state.
 func (cb *CircuitBreaker) Allow() bool {
+	allowed, _, _ := cb.allow()
+	return allowed
+}
+
+// allow implements Allow, also returning the states before and after the call
+// so that transitions can be reported.
+func (cb *CircuitBreaker) allow() (allowed bool, from, to CircuitState) {
 	cb.mu.Lock()
 	defer cb.mu.Unlock()
 
+	from = cb.state
+
 	switch cb.state {
 	case CircuitClosed:
-		return true
+		allowed = true
 
 	case CircuitOpen:
 		// Automatically transition to half-open after recovery timeout
 		if time.Since(cb.lastFailureTime) > cb.recoveryTimeout {
 			cb.state = CircuitHalfOpen
 			cb.successCount = 0
-			return true
+			allowed = true
 		}
-		return false
 
 	case CircuitHalfOpen:
 		// Allow request to test if service has recovered
-		return true
-
-	default:
-		return false
+		allowed = true
 	}
+
+	return allowed, from, cb.state
 }
 
 // RecordSuccess records a successful request and may close the circuit
 func (cb *CircuitBreaker) RecordSuccess() {
+	cb.recordSuccess()
+}
+
+// recordSuccess implements RecordSuccess, returning the states before and after.
+func (cb *CircuitBreaker) recordSuccess() (from, to CircuitState) {
 	cb.mu.Lock()
 	defer cb.mu.Unlock()
 
+	from = cb.state
+
 	switch cb.state {
 	case CircuitClosed:
 		// Reset failure counter to prevent gradual degradation
@@ -116,13 +130,21 @@ func (cb *CircuitBreaker) RecordSuccess() {
 			cb.successCount = 0
 		}
 	}
+
+	return from, cb.state
 }
 
 // RecordFailure records a failed request and may open the circuit
 func (cb *CircuitBreaker) RecordFailure() {
+	cb.recordFailure()
+}
+
+// recordFailure implements RecordFailure, returning the states before and after.
+func (cb *CircuitBreaker) recordFailure() (from, to CircuitState) {
 	cb.mu.Lock()
 	defer cb.mu.Unlock()
 
+	from = cb.state
 	cb.lastFailureTime = time.Now()
 
 	switch cb.state {
@@ -139,6 +161,8 @@ func (cb *CircuitBreaker) RecordFailure() {
 		cb.failures = cb.failureThreshold
 		cb.successCount = 0
 	}
+
+	return from, cb.state
 }
 
 // State returns the current state, accounting for automatic transitions
@@ -10,11 +10,12 @@
 //
 // - Automatic JSON marshaling for any Go value
 // - Synchronous HTTP POST delivery with configurable timeouts
+// - In-process async delivery through a bounded worker pool (AsyncSender)
 // - Automatic retry logic with exponential backoff and jitter
-// - HMAC-SHA256 request signing for payload authentication
+// - HMAC-SHA256 request signing for payload authentication, with HMAC-SHA512 and Ed25519 via WithSigner
 // - Circuit breaker to prevent hammering failed endpoints
 // - Flexible error classification (permanent vs temporary failures)
-// - Delivery hooks for metrics, logging, and custom handling
+// - Pluggable instrumentation and delivery hooks for metrics and logging
 //
 // # Basic Usage
 //
@@ -79,6 +80,10 @@
 //	    }),
 //	)
 //
+// Payloads above WithMaxPayloadBytes (10MB by default) fail with ErrPayloadTooLarge
+// before sending. At most WithMaxResponseBytes (64KB by default) of the response is
+// read and reported to hooks as DeliveryResult.ResponseBody.
+//
 // # Request Signing
 //
 // When WithSignature is used, the package adds standard webhook headers:
@@ -86,14 +91,44 @@
 //	X-Webhook-Signature: HMAC-SHA256 hex-encoded signature
 //	X-Webhook-Timestamp: Unix timestamp when signature was created
 //	X-Webhook-ID: Unique identifier for this webhook event
+//	X-Webhook-Signature-Algorithm: hmac-sha256
 //
 // The signature is calculated as: HMAC-SHA256(secret, timestamp + "." + payload)
 //
-// Receivers can verify signatures using the VerifySignature function:
+// WithSigner swaps the algorithm for receivers that require another one, e.g.
+// NewHMACSHA512Signer or NewEd25519Signer; custom schemes implement Signer.
+//
+// Receivers can verify signatures using the VerifySignature function, which picks
+// the HMAC hash from the algorithm header and treats a missing header as HMAC-SHA256:
 //
 //	headers := webhook.ExtractSignatureHeaders(httpHeaders)
 //	err := webhook.VerifySignature(secret, payload, headers, 5*time.Minute)
 //
+// Ed25519 signatures are verified with the sender's public key via VerifyEd25519Signature.
+//
+// # Receiving Webhooks
+//
+// VerifyMiddleware does the same for an http.Handler. It rejects requests with
+// missing or malformed signature headers with 400, and an invalid signature or a
+// timestamp outside the tolerance with 401. ReceiverMiddleware uses 401 for both.
+// The verified body stays readable from r.Body and is also available from context:
+//
+//	mux.Handle("POST /webhooks", webhook.VerifyMiddleware(secret, 5*time.Minute)(handler))
+//
+//	body, _ := webhook.RawBodyFromContext(r.Context())
+//	id, _ := webhook.IDFromContext(r.Context()) // X-Webhook-ID for deduplication
+//
+// # Async Delivery
+//
+// AsyncSender runs Send on a fixed pool of workers without persistence. SendAsync
+// validates and enqueues, blocking while the queue is full until its context is done;
+// Close drains queued deliveries on shutdown:
+//
+//	async := webhook.NewAsyncSender(sender, webhook.WithAsyncWorkers(8))
+//	defer async.Close(shutdownCtx)
+//
+//	err := async.SendAsync(ctx, url, event, webhook.WithOnDelivery(logResult))
+//
 // # Retry Logic
 //
 // The package distinguishes between permanent and temporary failures:
@@ -139,17 +174,54 @@
 //	// Reuse the same circuit breaker for the same endpoint
 //	err := sender.Send(ctx, url, payload, webhook.WithCircuitBreaker(cb))
 //
+// When sending to many endpoints, a BreakerRegistry creates breakers lazily with
+// shared config and picks one per request by host and path (EndpointKey):
+//
+//	breakers := webhook.NewBreakerRegistry(5, 2, 30*time.Second)
+//	err := sender.Send(ctx, url, payload, webhook.WithBreakerRegistry(breakers))
+//
+//	for endpoint, stats := range breakers.Stats() { ... } // dashboard
+//
 // States:
 // - Closed: Normal operation, requests pass through
 // - Open: Too many failures, requests blocked
 // - Half-Open: Testing if service recovered
 //
+// # Instrumentation
+//
+// WithOnDelivery reports each attempt to a single callback. For production
+// metrics, WithInstrumentation takes an Instrumentation whose RecordAttempt,
+// RecordResult and RecordCircuitTransition methods receive attempts, latencies
+// and circuit breaker state changes keyed by the endpoint host, and can be
+// backed by Prometheus or OpenTelemetry. NoopInstrumentation is the default.
+//
+//	err := sender.Send(ctx, url, payload,
+//	    webhook.WithInstrumentation(metrics),
+//	    webhook.WithCircuitBreaker(cb),
+//	)
+//
+// # Large Payloads
+//
+// Receivers often limit request size. Payloads above a threshold can be
+// uploaded elsewhere and replaced with a small signed PayloadEnvelope
+// containing the URL, size and SHA-256 of the original payload:
+//
+//	err := sender.Send(ctx, url, event,
+//	    webhook.WithSignature(secret),
+//	    webhook.WithLargePayloadThreshold(256*1024, func(ctx context.Context, p []byte) (string, error) {
+//	        return uploadToS3AndPresign(ctx, p)
+//	    }),
+//	)
+//
+// Receivers detect envelopes with ParseEnvelope, fetch the URL and call
+// PayloadEnvelope.Verify on the downloaded content.
+//
 // # Performance Considerations
 //
 // - The default HTTP client reuses connections with proper pooling
 // - Payload signing uses HMAC-SHA256 which is fast and secure
 // - Circuit breakers should be reused per endpoint, not created per request
-// - For high-volume webhooks, consider using the async modules/webhooks package
+// - For high-volume webhooks, use AsyncSender, or modules/webhooks when deliveries must be persisted
 //
 // # Integration Points
 //
@@ -1,6 +1,9 @@
 package webhook
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 // Domain errors for webhook operations, designed for error wrapping and classification.
 // These provide stable error identities for error handling while allowing detailed
@@ -19,6 +22,13 @@ var (
 	ErrInvalidPayload        = errors.New("invalid webhook payload")
 	ErrInvalidURL            = errors.New("invalid webhook URL")
 	ErrTimeout               = errors.New("webhook request timeout")
+	ErrPayloadUploadFailed   = errors.New("failed to upload large webhook payload")
+	ErrPayloadHashMismatch   = errors.New("webhook payload does not match envelope hash")
+	ErrSenderClosed          = errors.New("webhook async sender is closed")
+	ErrQueueFull             = errors.New("webhook async queue is full")
+
+	// ErrPayloadTooLarge also matches ErrInvalidPayload for callers that predate it
+	ErrPayloadTooLarge = fmt.Errorf("%w: payload too large", ErrInvalidPayload)
 )
 
 // IsCircuitOpen checks if an error indicates the circuit breaker is open
@@ -0,0 +1,48 @@
+package webhook
+
+import (
+	"context"
+)
+
+// Instrumentation receives structured delivery events for metrics and tracing,
+// e.g. to back Prometheus counters and histograms or OpenTelemetry spans.
+// Host is the host[:port] of the webhook URL, for per-host breakdowns.
+// Methods are called synchronously from Send and must be safe for concurrent use.
+type Instrumentation interface {
+	// RecordAttempt is called before each HTTP request. Attempt is 1-based,
+	// so attempts above 1 are retries.
+	RecordAttempt(ctx context.Context, host string, attempt int)
+
+	// RecordResult is called after each HTTP request with its outcome and latency.
+	// It's also called with Attempt 0 and ErrCircuitOpen when the circuit breaker
+	// rejects a delivery without making a request.
+	RecordResult(ctx context.Context, host string, result DeliveryResult)
+
+	// RecordCircuitTransition is called when the circuit breaker passed to
+	// WithCircuitBreaker changes state during a send.
+	RecordCircuitTransition(ctx context.Context, host string, from, to CircuitState)
+}
+
+// NoopInstrumentation discards all events. It's the default Instrumentation.
+type NoopInstrumentation struct{}
+
+// RecordAttempt does nothing.
+func (NoopInstrumentation) RecordAttempt(context.Context, string, int) {}
+
+// RecordResult does nothing.
+func (NoopInstrumentation) RecordResult(context.Context, string, DeliveryResult) {}
+
+// RecordCircuitTransition does nothing.
+func (NoopInstrumentation) RecordCircuitTransition(context.Context, string, CircuitState, CircuitState) {
+}
+
+// WithInstrumentation sets the Instrumentation notified of attempts, results
+// and circuit breaker transitions. Unlike WithOnDelivery it can be shared by
+// all sends to feed metrics. Nil is ignored.
+func WithInstrumentation(i Instrumentation) SendOption {
+	return func(o *sendOptions) {
+		if i != nil {
+			o.instrumentation = i
+		}
+	}
+}
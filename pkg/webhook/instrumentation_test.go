@@ -0,0 +1,144 @@
+package webhook_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dmitrymomot/saaskit/pkg/webhook"
+)
+
+type transition struct {
+	host     string
+	from, to webhook.CircuitState
+}
+
+// recordingInstrumentation collects all events for assertions.
+type recordingInstrumentation struct {
+	mu          sync.Mutex
+	attempts    []int
+	results     []webhook.DeliveryResult
+	hosts       []string
+	transitions []transition
+}
+
+func (r *recordingInstrumentation) RecordAttempt(_ context.Context, host string, attempt int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.attempts = append(r.attempts, attempt)
+	r.hosts = append(r.hosts, host)
+}
+
+func (r *recordingInstrumentation) RecordResult(_ context.Context, _ string, result webhook.DeliveryResult) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.results = append(r.results, result)
+}
+
+func (r *recordingInstrumentation) RecordCircuitTransition(_ context.Context, host string, from, to webhook.CircuitState) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.transitions = append(r.transitions, transition{host: host, from: from, to: to})
+}
+
+func TestSender_Send_Instrumentation(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	u, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	instr := &recordingInstrumentation{}
+	err = webhook.NewSender().Send(context.Background(), server.URL, map[string]string{"a": "b"},
+		webhook.WithInstrumentation(instr),
+		webhook.WithBasicRetry(3, time.Millisecond),
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, []int{1, 2, 3}, instr.attempts)
+	assert.Equal(t, []string{u.Host, u.Host, u.Host}, instr.hosts)
+	require.Len(t, instr.results, 3)
+	for i, result := range instr.results {
+		assert.Equal(t, i+1, result.Attempt)
+		assert.Positive(t, result.Duration)
+	}
+	assert.Equal(t, http.StatusServiceUnavailable, instr.results[0].StatusCode)
+	assert.False(t, instr.results[1].Success)
+	assert.True(t, instr.results[2].Success)
+	assert.Empty(t, instr.transitions)
+}
+
+func TestSender_Send_InstrumentationCircuitTransitions(t *testing.T) {
+	t.Parallel()
+
+	var fail atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fail.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	u, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	instr := &recordingInstrumentation{}
+	cb := webhook.NewCircuitBreaker(1, 1, 50*time.Millisecond)
+	send := func() error {
+		return webhook.NewSender().Send(context.Background(), server.URL, map[string]string{"a": "b"},
+			webhook.WithInstrumentation(instr),
+			webhook.WithCircuitBreaker(cb),
+			webhook.WithNoRetry(),
+		)
+	}
+
+	fail.Store(true)
+	require.Error(t, send())
+
+	// Rejected without a request
+	require.ErrorIs(t, send(), webhook.ErrCircuitOpen)
+	last := instr.results[len(instr.results)-1]
+	assert.Equal(t, 0, last.Attempt)
+	assert.ErrorIs(t, last.Error, webhook.ErrCircuitOpen)
+	assert.Equal(t, []int{1}, instr.attempts)
+
+	time.Sleep(60 * time.Millisecond)
+	fail.Store(false)
+	require.NoError(t, send())
+
+	assert.Equal(t, []transition{
+		{host: u.Host, from: webhook.CircuitClosed, to: webhook.CircuitOpen},
+		{host: u.Host, from: webhook.CircuitOpen, to: webhook.CircuitHalfOpen},
+		{host: u.Host, from: webhook.CircuitHalfOpen, to: webhook.CircuitClosed},
+	}, instr.transitions)
+}
+
+func TestNoopInstrumentation(t *testing.T) {
+	t.Parallel()
+
+	var i webhook.Instrumentation = webhook.NoopInstrumentation{}
+	assert.NotPanics(t, func() {
+		i.RecordAttempt(context.Background(), "example.com", 1)
+		i.RecordResult(context.Background(), "example.com", webhook.DeliveryResult{})
+		i.RecordCircuitTransition(context.Background(), "example.com", webhook.CircuitClosed, webhook.CircuitOpen)
+	})
+}
@@ -0,0 +1,73 @@
+package webhook
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+)
+
+// EnvelopeType identifies webhook bodies that reference an offloaded payload.
+const EnvelopeType = "webhook.payload_reference"
+
+// PayloadUploader stores an oversized payload elsewhere (e.g. S3) and returns
+// a URL the receiver can fetch it from. Presigned URLs are recommended.
+type PayloadUploader func(ctx context.Context, payload []byte) (url string, err error)
+
+// PayloadEnvelope is delivered instead of the original payload when it exceeds
+// the threshold configured with WithLargePayloadThreshold.
+// The signature covers the envelope; SHA256 lets the receiver verify the fetched content.
+type PayloadEnvelope struct {
+	Type   string `json:"type"`
+	URL    string `json:"url"`
+	SHA256 string `json:"sha256"`
+	Size   int64  `json:"size"`
+}
+
+// ParseEnvelope decodes a webhook body as a PayloadEnvelope.
+// The boolean is false when the body is a regular inline payload.
+func ParseEnvelope(body []byte) (PayloadEnvelope, bool) {
+	var env PayloadEnvelope
+	if err := json.Unmarshal(body, &env); err != nil || env.Type != EnvelopeType || env.URL == "" {
+		return PayloadEnvelope{}, false
+	}
+	return env, true
+}
+
+// Verify checks that content fetched from the envelope URL matches the announced hash and size.
+func (e PayloadEnvelope) Verify(content []byte) error {
+	if int64(len(content)) != e.Size {
+		return fmt.Errorf("%w: size mismatch", ErrPayloadHashMismatch)
+	}
+	sum := sha256.Sum256(content)
+	if subtle.ConstantTimeCompare([]byte(hex.EncodeToString(sum[:])), []byte(e.SHA256)) != 1 {
+		return ErrPayloadHashMismatch
+	}
+	return nil
+}
+
+// offloadPayload uploads the payload and returns the marshaled envelope to deliver instead.
+func offloadPayload(ctx context.Context, uploader PayloadUploader, payload []byte) ([]byte, error) {
+	url, err := uploader(ctx, payload)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrPayloadUploadFailed, err)
+	}
+	if url == "" {
+		return nil, fmt.Errorf("%w: uploader returned empty URL", ErrPayloadUploadFailed)
+	}
+
+	sum := sha256.Sum256(payload)
+	envelope, err := json.Marshal(PayloadEnvelope{
+		Type:   EnvelopeType,
+		URL:    url,
+		SHA256: hex.EncodeToString(sum[:]),
+		Size:   int64(len(payload)),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal payload envelope: %w", err)
+	}
+
+	return envelope, nil
+}
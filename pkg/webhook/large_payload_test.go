@@ -0,0 +1,142 @@
+package webhook_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dmitrymomot/saaskit/pkg/webhook"
+)
+
+func TestSender_Send_LargePayloadOffload(t *testing.T) {
+	t.Parallel()
+
+	const secret = "test-secret"
+	largeData := map[string]string{"blob": strings.Repeat("x", 4096)}
+
+	t.Run("offloads payload above threshold and signs envelope", func(t *testing.T) {
+		t.Parallel()
+
+		var uploaded []byte
+		var uploads atomic.Int32
+		uploader := func(ctx context.Context, payload []byte) (string, error) {
+			uploads.Add(1)
+			uploaded = payload
+			return "https://storage.example.com/payloads/evt_1.json", nil
+		}
+
+		var received []byte
+		var calls atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			received = body
+
+			sig, err := webhook.ExtractSignatureHeaders(map[string]string{
+				"X-Webhook-Signature": r.Header.Get("X-Webhook-Signature"),
+				"X-Webhook-Timestamp": r.Header.Get("X-Webhook-Timestamp"),
+			})
+			require.NoError(t, err)
+			assert.NoError(t, webhook.VerifySignature(secret, body, sig, time.Minute))
+
+			// Fail the first attempt to verify the upload isn't repeated on retry
+			if calls.Add(1) == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		sender := webhook.NewSender()
+		err := sender.Send(context.Background(), server.URL, largeData,
+			webhook.WithSignature(secret),
+			webhook.WithBasicRetry(1, time.Millisecond),
+			webhook.WithLargePayloadThreshold(1024, uploader),
+		)
+		require.NoError(t, err)
+		assert.Equal(t, int32(1), uploads.Load())
+
+		env, ok := webhook.ParseEnvelope(received)
+		require.True(t, ok)
+		assert.Equal(t, webhook.EnvelopeType, env.Type)
+		assert.Equal(t, "https://storage.example.com/payloads/evt_1.json", env.URL)
+		assert.Equal(t, int64(len(uploaded)), env.Size)
+		assert.NoError(t, env.Verify(uploaded))
+		assert.ErrorIs(t, env.Verify([]byte("tampered")), webhook.ErrPayloadHashMismatch)
+	})
+
+	t.Run("small payload is delivered inline", func(t *testing.T) {
+		t.Parallel()
+
+		uploader := func(ctx context.Context, payload []byte) (string, error) {
+			t.Fatal("uploader must not be called")
+			return "", nil
+		}
+
+		var received []byte
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			received, _ = io.ReadAll(r.Body)
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		err := webhook.NewSender().Send(context.Background(), server.URL, map[string]string{"event": "small"},
+			webhook.WithLargePayloadThreshold(1024, uploader),
+		)
+		require.NoError(t, err)
+
+		_, ok := webhook.ParseEnvelope(received)
+		assert.False(t, ok)
+		assert.JSONEq(t, `{"event":"small"}`, string(received))
+	})
+
+	t.Run("envelope satisfies max payload size", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		uploader := func(ctx context.Context, payload []byte) (string, error) {
+			return "https://storage.example.com/p", nil
+		}
+
+		err := webhook.NewSender().Send(context.Background(), server.URL, largeData,
+			webhook.WithMaxPayloadSize(2048),
+			webhook.WithLargePayloadThreshold(1024, uploader),
+		)
+		assert.NoError(t, err)
+	})
+
+	t.Run("upload failure aborts delivery", func(t *testing.T) {
+		t.Parallel()
+
+		var calls atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		uploader := func(ctx context.Context, payload []byte) (string, error) {
+			return "", errors.New("s3 unavailable")
+		}
+
+		err := webhook.NewSender().Send(context.Background(), server.URL, largeData,
+			webhook.WithLargePayloadThreshold(1024, uploader),
+		)
+		assert.ErrorIs(t, err, webhook.ErrPayloadUploadFailed)
+		assert.Equal(t, int32(0), calls.Load())
+	})
+}
@@ -1,6 +1,7 @@
 package webhook
 
 import (
+	"crypto/sha256"
 	"net/http"
 	"time"
 )
@@ -12,6 +13,9 @@ type DeliveryResult struct {
 	Attempt    int
 	Duration   time.Duration
 	Error      error
+
+	// ResponseBody holds up to WithMaxResponseBytes of the response body, nil when empty
+	ResponseBody []byte
 }
 
 // DeliveryHook is called after each delivery attempt
@@ -26,14 +30,19 @@ type sendOptions struct {
 	maxRetries      int
 	backoffStrategy BackoffStrategy
 
-	signatureSecret string
+	signer Signer
 
-	circuitBreaker *CircuitBreaker
+	circuitBreaker  *CircuitBreaker
+	breakerRegistry *BreakerRegistry
 
-	onDelivery DeliveryHook
+	onDelivery      DeliveryHook
+	instrumentation Instrumentation
 
 	maxPayloadSize  int64 // Maximum allowed payload size in bytes
 	maxResponseSize int64 // Maximum response body size to read
+
+	largePayloadThreshold int64 // Payloads above this size are offloaded via largePayloadUploader
+	largePayloadUploader  PayloadUploader
 }
 
 // defaultSendOptions returns options with sensible defaults
@@ -45,6 +54,7 @@ func defaultSendOptions() *sendOptions {
 		backoffStrategy: DefaultBackoffStrategy(),
 		maxPayloadSize:  10 * 1024 * 1024, // 10MB default
 		maxResponseSize: 64 * 1024,        // 64KB default
+		instrumentation: NoopInstrumentation{},
 	}
 }
 
@@ -103,10 +113,22 @@ func WithBackoff(strategy BackoffStrategy) SendOption {
 }
 
 // WithSignature enables HMAC-SHA256 request signing with the given secret.
-// Adds X-Webhook-Signature, X-Webhook-Timestamp, and X-Webhook-ID headers.
+// Adds X-Webhook-Signature, X-Webhook-Timestamp, X-Webhook-ID, and
+// X-Webhook-Signature-Algorithm headers. An empty secret disables signing.
 func WithSignature(secret string) SendOption {
 	return func(o *sendOptions) {
-		o.signatureSecret = secret
+		o.signer = nil
+		if secret != "" {
+			o.signer = hmacSigner{algorithm: AlgorithmHMACSHA256, hash: sha256.New, secret: []byte(secret)}
+		}
+	}
+}
+
+// WithSigner signs requests with a custom Signer, e.g. NewHMACSHA512Signer or
+// NewEd25519Signer. It replaces WithSignature; the last one applied wins.
+func WithSigner(signer Signer) SendOption {
+	return func(o *sendOptions) {
+		o.signer = signer
 	}
 }
 
@@ -166,9 +188,10 @@ func WithNoRetry() SendOption {
 	}
 }
 
-// WithMaxPayloadSize sets the maximum allowed payload size in bytes.
+// WithMaxPayloadBytes sets the maximum allowed payload size in bytes.
+// Larger payloads are rejected with ErrPayloadTooLarge before any request is made.
 // Default is 10MB if not specified. Set to 0 to disable limit.
-func WithMaxPayloadSize(size int64) SendOption {
+func WithMaxPayloadBytes(size int64) SendOption {
 	return func(o *sendOptions) {
 		if size >= 0 {
 			o.maxPayloadSize = size
@@ -176,12 +199,40 @@ func WithMaxPayloadSize(size int64) SendOption {
 	}
 }
 
-// WithMaxResponseSize sets the maximum response body size to read in bytes.
-// Default is 64KB if not specified. This limits memory usage when reading error responses.
-func WithMaxResponseSize(size int64) SendOption {
+// WithMaxPayloadSize sets the maximum allowed payload size in bytes.
+//
+// Deprecated: Use WithMaxPayloadBytes.
+func WithMaxPayloadSize(size int64) SendOption {
+	return WithMaxPayloadBytes(size)
+}
+
+// WithMaxResponseBytes caps how much of the response body is read, in bytes.
+// Default is 64KB if not specified. The captured body is reported in
+// DeliveryResult.ResponseBody and the rest is never read into memory.
+func WithMaxResponseBytes(size int64) SendOption {
 	return func(o *sendOptions) {
 		if size > 0 {
 			o.maxResponseSize = size
 		}
 	}
 }
+
+// WithMaxResponseSize sets the maximum response body size to read in bytes.
+//
+// Deprecated: Use WithMaxResponseBytes.
+func WithMaxResponseSize(size int64) SendOption {
+	return WithMaxResponseBytes(size)
+}
+
+// WithLargePayloadThreshold offloads payloads larger than threshold bytes.
+// The payload is uploaded once via uploader and the webhook POSTs a small
+// PayloadEnvelope with the URL and SHA-256 hash instead. The signature covers
+// the envelope, and the size limit applies to the delivered envelope.
+func WithLargePayloadThreshold(threshold int64, uploader PayloadUploader) SendOption {
+	return func(o *sendOptions) {
+		if threshold > 0 && uploader != nil {
+			o.largePayloadThreshold = threshold
+			o.largePayloadUploader = uploader
+		}
+	}
+}
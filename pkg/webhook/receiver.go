@@ -0,0 +1,173 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Receiver defaults
+const (
+	// DefaultReceiverTolerance is the maximum accepted signature age when tolerance is not set
+	DefaultReceiverTolerance = 5 * time.Minute
+
+	// DefaultReceiverMaxBodySize limits how much of the request body is read for verification
+	DefaultReceiverMaxBodySize int64 = 1 << 20 // 1MB
+)
+
+type receiverContextKey struct{}
+
+// receivedWebhook is stored in the request context after successful verification
+type receivedWebhook struct {
+	body []byte
+	id   string
+}
+
+// ReceiverErrorHandler writes the response for a request that failed verification.
+// status is http.StatusUnauthorized for signature errors,
+// http.StatusBadRequest for unreadable bodies (and, with VerifyMiddleware, missing
+// or malformed signature headers) and http.StatusRequestEntityTooLarge when the
+// body exceeds the size limit.
+type ReceiverErrorHandler func(w http.ResponseWriter, r *http.Request, status int, err error)
+
+type receiverOptions struct {
+	maxBodySize  int64
+	errorHandler ReceiverErrorHandler
+}
+
+// ReceiverOption configures ReceiverMiddleware and VerifyMiddleware
+type ReceiverOption func(*receiverOptions)
+
+// WithReceiverMaxBodySize sets the maximum request body size in bytes.
+// Default is 1MB. Larger requests are rejected with 413 before verification.
+func WithReceiverMaxBodySize(size int64) ReceiverOption {
+	return func(o *receiverOptions) {
+		if size > 0 {
+			o.maxBodySize = size
+		}
+	}
+}
+
+// WithReceiverErrorHandler replaces the default plain-text error response.
+func WithReceiverErrorHandler(handler ReceiverErrorHandler) ReceiverOption {
+	return func(o *receiverOptions) {
+		if handler != nil {
+			o.errorHandler = handler
+		}
+	}
+}
+
+func defaultReceiverErrorHandler(w http.ResponseWriter, _ *http.Request, status int, _ error) {
+	// Verification details are not exposed to the sender
+	http.Error(w, http.StatusText(status), status)
+}
+
+// ReceiverMiddleware verifies signed webhooks on the receiving side.
+// It checks X-Webhook-Signature and X-Webhook-Timestamp against the raw body
+// with the HMAC algorithm named in X-Webhook-Signature-Algorithm (SHA-256 when absent),
+// rejects timestamps older than tolerance (DefaultReceiverTolerance when <= 0)
+// and responds with 401 on failure. On success the verified body is available
+// through RawBodyFromContext and is also restored on r.Body for decoding.
+//
+// Prefer VerifyMiddleware, which tells malformed requests apart from forged ones.
+func ReceiverMiddleware(secret string, tolerance time.Duration, opts ...ReceiverOption) func(http.Handler) http.Handler {
+	return newReceiverMiddleware(secret, tolerance, http.StatusUnauthorized, opts)
+}
+
+// VerifyMiddleware works like ReceiverMiddleware but responds with 400 when the
+// signature or timestamp headers are missing or malformed, keeping 401 for
+// signature mismatches and timestamps outside the tolerance window (replays).
+// The verified X-Webhook-ID is available through IDFromContext for idempotency.
+func VerifyMiddleware(secret string, tolerance time.Duration, opts ...ReceiverOption) func(http.Handler) http.Handler {
+	return newReceiverMiddleware(secret, tolerance, http.StatusBadRequest, opts)
+}
+
+// newReceiverMiddleware implements both middlewares; headerErrorStatus is used
+// when the signature headers can't be read.
+func newReceiverMiddleware(secret string, tolerance time.Duration, headerErrorStatus int, opts []ReceiverOption) func(http.Handler) http.Handler {
+	if secret == "" {
+		panic("webhook: receiver secret cannot be empty")
+	}
+	if tolerance <= 0 {
+		tolerance = DefaultReceiverTolerance
+	}
+
+	o := &receiverOptions{
+		maxBodySize:  DefaultReceiverMaxBodySize,
+		errorHandler: defaultReceiverErrorHandler,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, err := io.ReadAll(io.LimitReader(r.Body, o.maxBodySize+1))
+			_ = r.Body.Close()
+			if err != nil {
+				o.errorHandler(w, r, http.StatusBadRequest, errors.Join(ErrInvalidPayload, err))
+				return
+			}
+			if int64(len(body)) > o.maxBodySize {
+				o.errorHandler(w, r, http.StatusRequestEntityTooLarge,
+					fmt.Errorf("%w: body exceeds %d bytes", ErrInvalidPayload, o.maxBodySize))
+				return
+			}
+
+			headers, err := signatureHeadersFromRequest(r)
+			if err != nil {
+				o.errorHandler(w, r, headerErrorStatus, err)
+				return
+			}
+			if err := VerifySignature(secret, body, headers, tolerance); err != nil {
+				o.errorHandler(w, r, http.StatusUnauthorized, err)
+				return
+			}
+
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			ctx := context.WithValue(r.Context(), receiverContextKey{}, receivedWebhook{body: body, id: headers.ID})
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// signatureHeadersFromRequest reads the signature headers; http.Header lookups are case-insensitive.
+func signatureHeadersFromRequest(r *http.Request) (SignatureHeaders, error) {
+	sig := SignatureHeaders{
+		Signature: r.Header.Get("X-Webhook-Signature"),
+		ID:        r.Header.Get("X-Webhook-ID"),
+		Algorithm: strings.ToLower(r.Header.Get("X-Webhook-Signature-Algorithm")),
+	}
+
+	ts := r.Header.Get("X-Webhook-Timestamp")
+	if sig.Signature == "" || ts == "" {
+		return SignatureHeaders{}, fmt.Errorf("%w: missing required signature headers", ErrInvalidConfiguration)
+	}
+
+	var err error
+	sig.Timestamp, err = strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return SignatureHeaders{}, fmt.Errorf("%w: invalid timestamp format", ErrInvalidConfiguration)
+	}
+
+	return sig, nil
+}
+
+// RawBodyFromContext returns the verified request body stored by ReceiverMiddleware or VerifyMiddleware.
+func RawBodyFromContext(ctx context.Context) ([]byte, bool) {
+	v, ok := ctx.Value(receiverContextKey{}).(receivedWebhook)
+	return v.body, ok
+}
+
+// IDFromContext returns the X-Webhook-ID of a verified request, useful for deduplication.
+// It is empty when the sender didn't set the header.
+func IDFromContext(ctx context.Context) (string, bool) {
+	v, ok := ctx.Value(receiverContextKey{}).(receivedWebhook)
+	return v.id, ok
+}
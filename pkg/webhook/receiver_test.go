@@ -0,0 +1,262 @@
+package webhook_test
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dmitrymomot/saaskit/pkg/webhook"
+)
+
+const receiverSecret = "receiver_secret"
+
+func signedRequest(t *testing.T, body string, timestamp int64) *http.Request {
+	t.Helper()
+
+	h := hmac.New(sha256.New, []byte(receiverSecret))
+	h.Write(fmt.Appendf(nil, "%d.%s", timestamp, body))
+
+	req := httptest.NewRequest(http.MethodPost, "/webhooks", strings.NewReader(body))
+	req.Header.Set("X-Webhook-Signature", hex.EncodeToString(h.Sum(nil)))
+	req.Header.Set("X-Webhook-Timestamp", strconv.FormatInt(timestamp, 10))
+	req.Header.Set("X-Webhook-ID", "evt_123")
+	return req
+}
+
+func TestReceiverMiddleware(t *testing.T) {
+	t.Parallel()
+
+	var (
+		gotBody    []byte
+		gotRawBody []byte
+		gotID      string
+	)
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var ok bool
+		gotRawBody, ok = webhook.RawBodyFromContext(r.Context())
+		require.True(t, ok)
+		gotID, _ = webhook.IDFromContext(r.Context())
+		gotBody, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	t.Run("valid signature reaches handler", func(t *testing.T) {
+		t.Parallel()
+
+		mw := webhook.ReceiverMiddleware(receiverSecret, time.Minute)
+		body := `{"event":"user.created"}`
+
+		rec := httptest.NewRecorder()
+		mw(next).ServeHTTP(rec, signedRequest(t, body, time.Now().Unix()))
+
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+		assert.Equal(t, body, string(gotRawBody))
+		assert.Equal(t, body, string(gotBody))
+		assert.Equal(t, "evt_123", gotID)
+	})
+
+	t.Run("rejects", func(t *testing.T) {
+		t.Parallel()
+
+		called := false
+		handler := webhook.ReceiverMiddleware(receiverSecret, time.Minute)(
+			http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { called = true }),
+		)
+
+		tests := []struct {
+			name   string
+			req    func() *http.Request
+			status int
+		}{
+			{
+				name: "tampered body",
+				req: func() *http.Request {
+					req := signedRequest(t, `{"amount":1}`, time.Now().Unix())
+					req.Body = io.NopCloser(strings.NewReader(`{"amount":100}`))
+					return req
+				},
+				status: http.StatusUnauthorized,
+			},
+			{
+				name: "wrong secret",
+				req: func() *http.Request {
+					req := signedRequest(t, `{"event":"x"}`, time.Now().Unix())
+					headers, err := webhook.SignPayload("other_secret", []byte(`{"event":"x"}`))
+					require.NoError(t, err)
+					req.Header.Set("X-Webhook-Signature", headers.Signature)
+					return req
+				},
+				status: http.StatusUnauthorized,
+			},
+			{
+				name: "replay outside tolerance",
+				req: func() *http.Request {
+					return signedRequest(t, `{"event":"x"}`, time.Now().Add(-2*time.Minute).Unix())
+				},
+				status: http.StatusUnauthorized,
+			},
+			{
+				name: "missing headers",
+				req: func() *http.Request {
+					return httptest.NewRequest(http.MethodPost, "/webhooks", strings.NewReader(`{"event":"x"}`))
+				},
+				status: http.StatusUnauthorized,
+			},
+			{
+				name: "invalid timestamp",
+				req: func() *http.Request {
+					req := signedRequest(t, `{"event":"x"}`, time.Now().Unix())
+					req.Header.Set("X-Webhook-Timestamp", "yesterday")
+					return req
+				},
+				status: http.StatusUnauthorized,
+			},
+		}
+
+		for _, tt := range tests {
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, tt.req())
+			assert.Equal(t, tt.status, rec.Code, tt.name)
+		}
+		assert.False(t, called)
+	})
+
+	t.Run("body too large", func(t *testing.T) {
+		t.Parallel()
+
+		mw := webhook.ReceiverMiddleware(receiverSecret, time.Minute, webhook.WithReceiverMaxBodySize(8))
+		rec := httptest.NewRecorder()
+		mw(next).ServeHTTP(rec, signedRequest(t, `{"event":"too large"}`, time.Now().Unix()))
+
+		assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+	})
+
+	t.Run("custom error handler", func(t *testing.T) {
+		t.Parallel()
+
+		var gotErr error
+		mw := webhook.ReceiverMiddleware(receiverSecret, time.Minute,
+			webhook.WithReceiverErrorHandler(func(w http.ResponseWriter, r *http.Request, status int, err error) {
+				gotErr = err
+				w.WriteHeader(status)
+			}),
+		)
+
+		rec := httptest.NewRecorder()
+		mw(next).ServeHTTP(rec, signedRequest(t, `{"event":"x"}`, time.Now().Add(-time.Hour).Unix()))
+
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		assert.ErrorIs(t, gotErr, webhook.ErrInvalidConfiguration)
+	})
+
+	t.Run("panics on empty secret", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Panics(t, func() { webhook.ReceiverMiddleware("", time.Minute) })
+	})
+}
+
+func TestVerifyMiddleware(t *testing.T) {
+	t.Parallel()
+
+	var gotBody []byte
+	var gotID string
+	handler := webhook.VerifyMiddleware(receiverSecret, time.Minute)(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			gotID, _ = webhook.IDFromContext(r.Context())
+			gotBody, _ = io.ReadAll(r.Body)
+			w.WriteHeader(http.StatusNoContent)
+		}),
+	)
+
+	t.Run("valid signature reaches handler", func(t *testing.T) {
+		body := `{"event":"invoice.paid"}`
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, signedRequest(t, body, time.Now().Unix()))
+
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+		assert.Equal(t, body, string(gotBody))
+		assert.Equal(t, "evt_123", gotID)
+	})
+
+	tests := []struct {
+		name   string
+		req    func() *http.Request
+		status int
+	}{
+		{
+			name: "missing headers",
+			req: func() *http.Request {
+				return httptest.NewRequest(http.MethodPost, "/webhooks", strings.NewReader(`{"event":"x"}`))
+			},
+			status: http.StatusBadRequest,
+		},
+		{
+			name: "invalid timestamp",
+			req: func() *http.Request {
+				req := signedRequest(t, `{"event":"x"}`, time.Now().Unix())
+				req.Header.Set("X-Webhook-Timestamp", "yesterday")
+				return req
+			},
+			status: http.StatusBadRequest,
+		},
+		{
+			name: "signature mismatch",
+			req: func() *http.Request {
+				req := signedRequest(t, `{"amount":1}`, time.Now().Unix())
+				req.Body = io.NopCloser(strings.NewReader(`{"amount":100}`))
+				return req
+			},
+			status: http.StatusUnauthorized,
+		},
+		{
+			name: "replay outside tolerance",
+			req: func() *http.Request {
+				return signedRequest(t, `{"event":"x"}`, time.Now().Add(-2*time.Minute).Unix())
+			},
+			status: http.StatusUnauthorized,
+		},
+		{
+			name: "timestamp in the future",
+			req: func() *http.Request {
+				return signedRequest(t, `{"event":"x"}`, time.Now().Add(10*time.Minute).Unix())
+			},
+			status: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			h := webhook.VerifyMiddleware(receiverSecret, time.Minute)(
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { called = true }),
+			)
+
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, tt.req())
+
+			assert.Equal(t, tt.status, rec.Code)
+			assert.False(t, called)
+		})
+	}
+}
+
+func TestRawBodyFromContext_Missing(t *testing.T) {
+	t.Parallel()
+
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	_, ok := webhook.RawBodyFromContext(req.Context())
+	assert.False(t, ok)
+}
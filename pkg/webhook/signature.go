@@ -1,11 +1,12 @@
 package webhook
 
 import (
+	"crypto/ed25519"
 	"crypto/hmac"
-	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -17,24 +18,40 @@ type SignatureHeaders struct {
 	Signature string
 	Timestamp int64
 	ID        string
+	// Algorithm is empty for senders that predate the header, meaning HMAC-SHA256
+	Algorithm string
 }
 
 // Headers returns the signature headers as a map for easy HTTP header setting.
 // Uses standard header names that are widely recognized.
 func (s SignatureHeaders) Headers() map[string]string {
-	return map[string]string{
+	headers := map[string]string{
 		"X-Webhook-Signature": s.Signature,
 		"X-Webhook-Timestamp": strconv.FormatInt(s.Timestamp, 10),
 		"X-Webhook-ID":        s.ID,
 	}
+	if s.Algorithm != "" {
+		headers["X-Webhook-Signature-Algorithm"] = s.Algorithm
+	}
+	return headers
 }
 
 // SignPayload creates an HMAC-SHA256 signature for webhook authentication.
 // Timestamp binding prevents replay attacks, following industry best practices.
 // Signature format: HMAC-SHA256(secret, timestamp + "." + payload)
 func SignPayload(secret string, payload []byte) (SignatureHeaders, error) {
-	if secret == "" {
-		return SignatureHeaders{}, fmt.Errorf("%w: secret is required", ErrInvalidConfiguration)
+	signer, err := NewHMACSHA256Signer(secret)
+	if err != nil {
+		return SignatureHeaders{}, err
+	}
+	return SignPayloadWith(signer, payload)
+}
+
+// SignPayloadWith signs timestamp + "." + payload with the given Signer and
+// reports its algorithm in the returned headers.
+func SignPayloadWith(signer Signer, payload []byte) (SignatureHeaders, error) {
+	if signer == nil {
+		return SignatureHeaders{}, fmt.Errorf("%w: signer is required", ErrInvalidConfiguration)
 	}
 	if len(payload) == 0 {
 		return SignatureHeaders{}, fmt.Errorf("%w: payload cannot be empty", ErrInvalidPayload)
@@ -45,26 +62,77 @@ func SignPayload(secret string, payload []byte) (SignatureHeaders, error) {
 
 	// Bind signature to timestamp to prevent replay attacks
 	// Format matches Stripe's webhook signature scheme for compatibility
-	signaturePayload := fmt.Sprintf("%d.%s", timestamp, payload)
-
-	// Use HMAC-SHA256 for cryptographic security without excessive overhead
-	h := hmac.New(sha256.New, []byte(secret))
-	h.Write([]byte(signaturePayload))
-	signature := hex.EncodeToString(h.Sum(nil))
+	signature, err := signer.Sign(signedContent(timestamp, payload))
+	if err != nil {
+		return SignatureHeaders{}, err
+	}
 
 	return SignatureHeaders{
 		Signature: signature,
 		Timestamp: timestamp,
 		ID:        id,
+		Algorithm: signer.Algorithm(),
 	}, nil
 }
 
+// signedContent builds the bytes covered by the signature
+func signedContent(timestamp int64, payload []byte) []byte {
+	return fmt.Appendf(nil, "%d.%s", timestamp, payload)
+}
+
 // VerifySignature validates webhook authenticity and prevents replay attacks.
 // Uses constant-time comparison and timestamp validation for security.
+// The HMAC hash is chosen by headers.Algorithm (hmac-sha256 or hmac-sha512);
+// an empty algorithm is treated as HMAC-SHA256 for senders without the header.
+// Use VerifyEd25519Signature for ed25519 signatures.
 func VerifySignature(secret string, payload []byte, headers SignatureHeaders, maxAge time.Duration) error {
 	if secret == "" {
 		return fmt.Errorf("%w: secret is required", ErrInvalidConfiguration)
 	}
+	h, ok := hmacHash(headers.Algorithm)
+	if !ok {
+		return fmt.Errorf("%w: unsupported signature algorithm %q", ErrInvalidConfiguration, headers.Algorithm)
+	}
+	if err := checkSignatureHeaders(payload, headers, maxAge); err != nil {
+		return err
+	}
+
+	// Recreate signature using the same algorithm as the sender
+	mac := hmac.New(h, []byte(secret))
+	mac.Write(signedContent(headers.Timestamp, payload))
+	expectedSignature := hex.EncodeToString(mac.Sum(nil))
+
+	// Use constant-time comparison to prevent timing-based attacks
+	if !hmac.Equal([]byte(expectedSignature), []byte(headers.Signature)) {
+		return fmt.Errorf("%w: signature mismatch", ErrInvalidConfiguration)
+	}
+
+	return nil
+}
+
+// VerifyEd25519Signature validates a signature created by an ed25519 Signer.
+// headers.Algorithm must be ed25519 so an HMAC signature can't be passed off as one.
+func VerifyEd25519Signature(publicKey ed25519.PublicKey, payload []byte, headers SignatureHeaders, maxAge time.Duration) error {
+	if len(publicKey) != ed25519.PublicKeySize {
+		return fmt.Errorf("%w: invalid ed25519 public key size", ErrInvalidConfiguration)
+	}
+	if headers.Algorithm != AlgorithmEd25519 {
+		return fmt.Errorf("%w: unsupported signature algorithm %q", ErrInvalidConfiguration, headers.Algorithm)
+	}
+	if err := checkSignatureHeaders(payload, headers, maxAge); err != nil {
+		return err
+	}
+
+	signature, err := hex.DecodeString(headers.Signature)
+	if err != nil || !ed25519.Verify(publicKey, signedContent(headers.Timestamp, payload), signature) {
+		return fmt.Errorf("%w: signature mismatch", ErrInvalidConfiguration)
+	}
+
+	return nil
+}
+
+// checkSignatureHeaders validates the inputs shared by all algorithms and the timestamp window
+func checkSignatureHeaders(payload []byte, headers SignatureHeaders, maxAge time.Duration) error {
 	if len(payload) == 0 {
 		return fmt.Errorf("%w: payload cannot be empty", ErrInvalidPayload)
 	}
@@ -84,17 +152,6 @@ func VerifySignature(secret string, payload []byte, headers SignatureHeaders, ma
 		}
 	}
 
-	// Recreate signature using the same algorithm as SignPayload
-	signaturePayload := fmt.Sprintf("%d.%s", headers.Timestamp, payload)
-	h := hmac.New(sha256.New, []byte(secret))
-	h.Write([]byte(signaturePayload))
-	expectedSignature := hex.EncodeToString(h.Sum(nil))
-
-	// Use constant-time comparison to prevent timing-based attacks
-	if !hmac.Equal([]byte(expectedSignature), []byte(headers.Signature)) {
-		return fmt.Errorf("%w: signature mismatch", ErrInvalidConfiguration)
-	}
-
 	return nil
 }
 
@@ -108,6 +165,7 @@ func ExtractSignatureHeaders(headers map[string]string) (SignatureHeaders, error
 	signatureKeys := []string{"X-Webhook-Signature", "x-webhook-signature", "X-WEBHOOK-SIGNATURE"}
 	timestampKeys := []string{"X-Webhook-Timestamp", "x-webhook-timestamp", "X-WEBHOOK-TIMESTAMP"}
 	idKeys := []string{"X-Webhook-ID", "x-webhook-id", "X-WEBHOOK-ID", "X-Webhook-Id"}
+	algorithmKeys := []string{"X-Webhook-Signature-Algorithm", "x-webhook-signature-algorithm", "X-WEBHOOK-SIGNATURE-ALGORITHM"}
 
 	// Find signature header using case-insensitive search
 	for _, key := range signatureKeys {
@@ -136,6 +194,14 @@ func ExtractSignatureHeaders(headers map[string]string) (SignatureHeaders, error
 		}
 	}
 
+	// Extract algorithm (absent for HMAC-SHA256 senders that predate the header)
+	for _, key := range algorithmKeys {
+		if val, ok := headers[key]; ok {
+			sig.Algorithm = strings.ToLower(val)
+			break
+		}
+	}
+
 	if sig.Signature == "" || sig.Timestamp == 0 {
 		return SignatureHeaders{}, fmt.Errorf("%w: missing required signature headers", ErrInvalidConfiguration)
 	}
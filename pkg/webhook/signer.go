@@ -0,0 +1,91 @@
+package webhook
+
+import (
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"hash"
+)
+
+// Signature algorithms reported in the X-Webhook-Signature-Algorithm header
+const (
+	AlgorithmHMACSHA256 = "hmac-sha256"
+	AlgorithmHMACSHA512 = "hmac-sha512"
+	AlgorithmEd25519    = "ed25519"
+)
+
+// Signer produces webhook signatures.
+// Sign receives the signed content (timestamp + "." + payload) and returns
+// the hex-encoded signature; Algorithm names the scheme for receivers.
+type Signer interface {
+	Algorithm() string
+	Sign(payload []byte) (string, error)
+}
+
+// hmacSigner signs with a shared secret
+type hmacSigner struct {
+	algorithm string
+	hash      func() hash.Hash
+	secret    []byte
+}
+
+// NewHMACSHA256Signer returns the default signer used by WithSignature.
+func NewHMACSHA256Signer(secret string) (Signer, error) {
+	return newHMACSigner(AlgorithmHMACSHA256, sha256.New, secret)
+}
+
+// NewHMACSHA512Signer returns a signer for receivers that require HMAC-SHA512.
+func NewHMACSHA512Signer(secret string) (Signer, error) {
+	return newHMACSigner(AlgorithmHMACSHA512, sha512.New, secret)
+}
+
+func newHMACSigner(algorithm string, h func() hash.Hash, secret string) (Signer, error) {
+	if secret == "" {
+		return nil, fmt.Errorf("%w: secret is required", ErrInvalidConfiguration)
+	}
+	return hmacSigner{algorithm: algorithm, hash: h, secret: []byte(secret)}, nil
+}
+
+func (s hmacSigner) Algorithm() string { return s.algorithm }
+
+func (s hmacSigner) Sign(payload []byte) (string, error) {
+	h := hmac.New(s.hash, s.secret)
+	h.Write(payload)
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// ed25519Signer signs with a private key; receivers only need the public key
+type ed25519Signer struct {
+	key ed25519.PrivateKey
+}
+
+// NewEd25519Signer returns a signer whose signatures are verified with
+// VerifyEd25519Signature and the matching public key.
+func NewEd25519Signer(key ed25519.PrivateKey) (Signer, error) {
+	if len(key) != ed25519.PrivateKeySize {
+		return nil, fmt.Errorf("%w: invalid ed25519 private key size", ErrInvalidConfiguration)
+	}
+	return ed25519Signer{key: key}, nil
+}
+
+func (s ed25519Signer) Algorithm() string { return AlgorithmEd25519 }
+
+func (s ed25519Signer) Sign(payload []byte) (string, error) {
+	return hex.EncodeToString(ed25519.Sign(s.key, payload)), nil
+}
+
+// hmacHash returns the hash for an HMAC algorithm name.
+// An empty name means HMAC-SHA256 for senders that predate the algorithm header.
+func hmacHash(algorithm string) (func() hash.Hash, bool) {
+	switch algorithm {
+	case "", AlgorithmHMACSHA256:
+		return sha256.New, true
+	case AlgorithmHMACSHA512:
+		return sha512.New, true
+	default:
+		return nil, false
+	}
+}
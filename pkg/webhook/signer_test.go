@@ -0,0 +1,169 @@
+package webhook_test
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dmitrymomot/saaskit/pkg/webhook"
+)
+
+func TestSignPayloadWith(t *testing.T) {
+	t.Parallel()
+
+	secret := "test_webhook_secret"
+	payload := []byte(`{"event":"user.created"}`)
+
+	t.Run("hmac-sha256 matches SignPayload", func(t *testing.T) {
+		t.Parallel()
+
+		signer, err := webhook.NewHMACSHA256Signer(secret)
+		require.NoError(t, err)
+
+		headers, err := webhook.SignPayloadWith(signer, payload)
+		require.NoError(t, err)
+		assert.Equal(t, webhook.AlgorithmHMACSHA256, headers.Algorithm)
+		assert.Equal(t, webhook.AlgorithmHMACSHA256, headers.Headers()["X-Webhook-Signature-Algorithm"])
+		assert.NoError(t, webhook.VerifySignature(secret, payload, headers, 5*time.Minute))
+	})
+
+	t.Run("hmac-sha512", func(t *testing.T) {
+		t.Parallel()
+
+		signer, err := webhook.NewHMACSHA512Signer(secret)
+		require.NoError(t, err)
+
+		headers, err := webhook.SignPayloadWith(signer, payload)
+		require.NoError(t, err)
+		assert.Equal(t, webhook.AlgorithmHMACSHA512, headers.Algorithm)
+
+		h := hmac.New(sha512.New, []byte(secret))
+		h.Write(fmt.Appendf(nil, "%d.%s", headers.Timestamp, payload))
+		assert.Equal(t, hex.EncodeToString(h.Sum(nil)), headers.Signature)
+
+		assert.NoError(t, webhook.VerifySignature(secret, payload, headers, 5*time.Minute))
+
+		// The algorithm header selects the hash, so a downgraded header must not verify
+		headers.Algorithm = webhook.AlgorithmHMACSHA256
+		assert.ErrorIs(t, webhook.VerifySignature(secret, payload, headers, 5*time.Minute), webhook.ErrInvalidConfiguration)
+	})
+
+	t.Run("ed25519", func(t *testing.T) {
+		t.Parallel()
+
+		pub, priv, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+
+		signer, err := webhook.NewEd25519Signer(priv)
+		require.NoError(t, err)
+
+		headers, err := webhook.SignPayloadWith(signer, payload)
+		require.NoError(t, err)
+		assert.Equal(t, webhook.AlgorithmEd25519, headers.Algorithm)
+		assert.NoError(t, webhook.VerifyEd25519Signature(pub, payload, headers, 5*time.Minute))
+
+		otherPub, _, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+		assert.Error(t, webhook.VerifyEd25519Signature(otherPub, payload, headers, 5*time.Minute))
+		assert.Error(t, webhook.VerifyEd25519Signature(pub, []byte(`{"event":"tampered"}`), headers, 5*time.Minute))
+
+		// HMAC verification can't be used for ed25519 signatures
+		assert.Error(t, webhook.VerifySignature(secret, payload, headers, 5*time.Minute))
+	})
+
+	t.Run("nil signer", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := webhook.SignPayloadWith(nil, payload)
+		assert.ErrorIs(t, err, webhook.ErrInvalidConfiguration)
+	})
+}
+
+func TestNewSigners_InvalidKeys(t *testing.T) {
+	t.Parallel()
+
+	_, err := webhook.NewHMACSHA256Signer("")
+	assert.ErrorIs(t, err, webhook.ErrInvalidConfiguration)
+
+	_, err = webhook.NewHMACSHA512Signer("")
+	assert.ErrorIs(t, err, webhook.ErrInvalidConfiguration)
+
+	_, err = webhook.NewEd25519Signer(ed25519.PrivateKey("short"))
+	assert.ErrorIs(t, err, webhook.ErrInvalidConfiguration)
+}
+
+func TestVerifySignature_Algorithms(t *testing.T) {
+	t.Parallel()
+
+	secret := "secret"
+	payload := []byte(`{"id":"1"}`)
+
+	headers, err := webhook.SignPayload(secret, payload)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		algorithm string
+		wantErr   bool
+	}{
+		{name: "explicit hmac-sha256", algorithm: webhook.AlgorithmHMACSHA256},
+		{name: "missing header is hmac-sha256", algorithm: ""},
+		{name: "unsupported algorithm", algorithm: "md5", wantErr: true},
+		{name: "wrong hash", algorithm: webhook.AlgorithmHMACSHA512, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			h := headers
+			h.Algorithm = tt.algorithm
+			err := webhook.VerifySignature(secret, payload, h, 5*time.Minute)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, webhook.ErrInvalidConfiguration)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestSender_Send_WithSigner(t *testing.T) {
+	t.Parallel()
+
+	secret := "sender_secret"
+	var received http.Header
+	var body []byte
+
+	server := httptest.NewServer(webhook.ReceiverMiddleware(secret, time.Minute)(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			received = r.Header.Clone()
+			body, _ = webhook.RawBodyFromContext(r.Context())
+			w.WriteHeader(http.StatusOK)
+		}),
+	))
+	defer server.Close()
+
+	signer, err := webhook.NewHMACSHA512Signer(secret)
+	require.NoError(t, err)
+
+	err = webhook.NewSender().Send(context.Background(), server.URL, map[string]string{"event": "test"},
+		webhook.WithSigner(signer),
+		webhook.WithNoRetry(),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, webhook.AlgorithmHMACSHA512, received.Get("X-Webhook-Signature-Algorithm"))
+	assert.Len(t, received.Get("X-Webhook-Signature"), sha512.Size*2)
+	assert.JSONEq(t, `{"event":"test"}`, string(body))
+}
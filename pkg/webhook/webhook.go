@@ -12,7 +12,6 @@ import (
 	"time"
 )
 
-// TODO: Add metrics/telemetry integration hooks for monitoring webhook performance and reliability
 // TODO: Add structured logging support for better observability and debugging
 // TODO: Add request ID propagation for distributed tracing across webhook calls
 
@@ -84,10 +83,18 @@ func (s *Sender) Send(ctx context.Context, webhookURL string, data any, opts ...
 		opt(options)
 	}
 
+	// Offload oversized payloads once, before any delivery attempt
+	if options.largePayloadUploader != nil && int64(len(payload)) > options.largePayloadThreshold {
+		payload, err = offloadPayload(ctx, options.largePayloadUploader, payload)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Check payload size limit
 	if options.maxPayloadSize > 0 && int64(len(payload)) > options.maxPayloadSize {
 		return fmt.Errorf("%w: payload size %d bytes exceeds maximum allowed size of %d bytes",
-			ErrInvalidPayload, len(payload), options.maxPayloadSize)
+			ErrPayloadTooLarge, len(payload), options.maxPayloadSize)
 	}
 
 	// Allow per-request client override for testing or custom transports
@@ -96,9 +103,23 @@ func (s *Sender) Send(ctx context.Context, webhookURL string, data any, opts ...
 		client = options.httpClient
 	}
 
+	instr := options.instrumentation
+	host := webhookHost(webhookURL)
+
+	if options.circuitBreaker == nil && options.breakerRegistry != nil {
+		options.circuitBreaker = options.breakerRegistry.Get(EndpointKey(webhookURL))
+	}
+
 	// Fail fast if circuit breaker is protecting the endpoint
-	if options.circuitBreaker != nil && !options.circuitBreaker.Allow() {
-		return ErrCircuitOpen
+	if cb := options.circuitBreaker; cb != nil {
+		allowed, from, to := cb.allow()
+		if from != to {
+			instr.RecordCircuitTransition(ctx, host, from, to)
+		}
+		if !allowed {
+			instr.RecordResult(ctx, host, DeliveryResult{Error: ErrCircuitOpen})
+			return ErrCircuitOpen
+		}
 	}
 
 	// Retry loop with exponential backoff
@@ -114,20 +135,27 @@ func (s *Sender) Send(ctx context.Context, webhookURL string, data any, opts ...
 			}
 		}
 
+		instr.RecordAttempt(ctx, host, attempt+1)
+
 		result, err := s.attemptDelivery(ctx, client, webhookURL, payload, options)
+		result.Attempt = attempt + 1
 
 		// Notify observers of delivery attempt for metrics/logging
+		instr.RecordResult(ctx, host, result)
 		if options.onDelivery != nil {
-			result.Attempt = attempt + 1
 			options.onDelivery(result)
 		}
 
 		// Update circuit breaker state based on result
-		if options.circuitBreaker != nil {
+		if cb := options.circuitBreaker; cb != nil {
+			var from, to CircuitState
 			if err == nil {
-				options.circuitBreaker.RecordSuccess()
+				from, to = cb.recordSuccess()
 			} else {
-				options.circuitBreaker.RecordFailure()
+				from, to = cb.recordFailure()
+			}
+			if from != to {
+				instr.RecordCircuitTransition(ctx, host, from, to)
 			}
 		}
 
@@ -175,6 +203,15 @@ func (s *Sender) validateInputs(webhookURL string, payload []byte) error {
 	return nil
 }
 
+// webhookHost returns the host[:port] of an already validated webhook URL.
+func webhookHost(webhookURL string) string {
+	u, err := url.Parse(webhookURL)
+	if err != nil {
+		return ""
+	}
+	return u.Host
+}
+
 // attemptDelivery makes a single HTTP request attempt with timing and error capture
 func (s *Sender) attemptDelivery(ctx context.Context, client *http.Client, webhookURL string, payload []byte, options *sendOptions) (DeliveryResult, error) {
 	start := time.Now()
@@ -200,9 +237,9 @@ func (s *Sender) attemptDelivery(ctx context.Context, client *http.Client, webho
 		req.Header.Set(k, v)
 	}
 
-	// Add signature if secret is provided
-	if options.signatureSecret != "" {
-		sigHeaders, err := SignPayload(options.signatureSecret, payload)
+	// Add signature if a signer is configured
+	if options.signer != nil {
+		sigHeaders, err := SignPayloadWith(options.signer, payload)
 		if err != nil {
 			result.Duration = time.Since(start)
 			result.Error = err
@@ -230,8 +267,11 @@ func (s *Sender) attemptDelivery(ctx context.Context, client *http.Client, webho
 	result.StatusCode = resp.StatusCode
 	result.Success = resp.StatusCode >= 200 && resp.StatusCode < 300
 
-	// Read response body for error context (use configurable limit to prevent memory exhaustion)
+	// Capture the response body for hooks and error context; the limit prevents memory exhaustion
 	body, _ := io.ReadAll(io.LimitReader(resp.Body, options.maxResponseSize))
+	if len(body) > 0 {
+		result.ResponseBody = body
+	}
 
 	// Check status code
 	if !result.Success {
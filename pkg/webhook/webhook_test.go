@@ -943,6 +943,125 @@ func TestSender_Send_ResponseSizeLimit(t *testing.T) {
 	}
 }
 
+func TestSender_Send_MaxPayloadBytes(t *testing.T) {
+	t.Parallel()
+
+	t.Run("rejects oversized payload before sending", func(t *testing.T) {
+		t.Parallel()
+
+		var requests int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&requests, 1)
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		err := webhook.NewSender().Send(context.Background(), server.URL,
+			map[string]string{"data": strings.Repeat("x", 2048)},
+			webhook.WithMaxPayloadBytes(1024),
+		)
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, webhook.ErrPayloadTooLarge)
+		assert.ErrorIs(t, err, webhook.ErrInvalidPayload)
+		assert.Equal(t, int32(0), atomic.LoadInt32(&requests))
+	})
+
+	t.Run("zero disables the limit", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		err := webhook.NewSender().Send(context.Background(), server.URL,
+			map[string]string{"data": strings.Repeat("x", 11*1024*1024)},
+			webhook.WithMaxPayloadBytes(0),
+		)
+		assert.NoError(t, err)
+	})
+}
+
+func TestSender_Send_MaxResponseBytes(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name             string
+		responseSize     int
+		maxResponseBytes int64
+		statusCode       int
+	}{
+		{
+			name:             "body within limit",
+			responseSize:     1024,
+			maxResponseBytes: 64 * 1024,
+			statusCode:       http.StatusOK,
+		},
+		{
+			name:             "body truncated on success",
+			responseSize:     5 * 1024,
+			maxResponseBytes: 2 * 1024,
+			statusCode:       http.StatusOK,
+		},
+		{
+			name:             "body truncated on failure",
+			responseSize:     100 * 1024,
+			maxResponseBytes: 10 * 1024,
+			statusCode:       http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			responseBody := []byte(strings.Repeat("A", tt.responseSize))
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.statusCode)
+				w.Write(responseBody)
+			}))
+			defer server.Close()
+
+			var result webhook.DeliveryResult
+			err := webhook.NewSender().Send(context.Background(), server.URL,
+				map[string]string{"test": "data"},
+				webhook.WithMaxResponseBytes(tt.maxResponseBytes),
+				webhook.WithNoRetry(),
+				webhook.WithOnDelivery(func(r webhook.DeliveryResult) { result = r }),
+			)
+
+			if tt.statusCode >= 300 {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			wantLen := min(tt.responseSize, int(tt.maxResponseBytes))
+			assert.Equal(t, responseBody[:wantLen], result.ResponseBody)
+		})
+	}
+}
+
+func TestSender_Send_CapturesResponseBody(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+		w.Write([]byte(`{"queued":true}`))
+	}))
+	defer server.Close()
+
+	var result webhook.DeliveryResult
+	err := webhook.NewSender().Send(context.Background(), server.URL, map[string]string{"test": "data"},
+		webhook.WithOnDelivery(func(r webhook.DeliveryResult) { result = r }),
+	)
+
+	require.NoError(t, err)
+	assert.True(t, result.Success)
+	assert.Equal(t, `{"queued":true}`, string(result.ResponseBody))
+}
+
 // Helper function for min since Go doesn't have built-in generic min
 func min(a, b int) int {
 	if a < b {